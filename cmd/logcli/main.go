@@ -16,9 +16,12 @@ import (
 	"gopkg.in/alecthomas/kingpin.v2"
 
 	"github.com/grafana/loki/v3/pkg/logcli/client"
+	"github.com/grafana/loki/v3/pkg/logcli/deletequery"
+	"github.com/grafana/loki/v3/pkg/logcli/explore"
 	"github.com/grafana/loki/v3/pkg/logcli/index"
 	"github.com/grafana/loki/v3/pkg/logcli/labelquery"
 	"github.com/grafana/loki/v3/pkg/logcli/output"
+	"github.com/grafana/loki/v3/pkg/logcli/push"
 	"github.com/grafana/loki/v3/pkg/logcli/query"
 	"github.com/grafana/loki/v3/pkg/logcli/seriesquery"
 	"github.com/grafana/loki/v3/pkg/logcli/volume"
@@ -30,7 +33,7 @@ var (
 	app        = kingpin.New("logcli", "A command-line for loki.").Version(version.Print("logcli"))
 	quiet      = app.Flag("quiet", "Suppress query metadata").Default("false").Short('q').Bool()
 	statistics = app.Flag("stats", "Show query statistics").Default("false").Bool()
-	outputMode = app.Flag("output", "Specify output mode [default, raw, jsonl]. raw suppresses log labels and timestamp.").Default("default").Short('o').Enum("default", "raw", "jsonl")
+	outputMode = app.Flag("output", "Specify output mode [default, raw, jsonl, csv, parquet, openmetrics]. raw suppresses log labels and timestamp. csv and parquet flatten labels and structured metadata into columns. openmetrics only supports metric queries.").Default("default").Short('o').Enum("default", "raw", "jsonl", "csv", "parquet", "openmetrics")
 	timezone   = app.Flag("timezone", "Specify the timezone to use when formatting output timestamps [Local, UTC]").Default("Local").Short('z').Enum("Local", "UTC")
 	cpuProfile = app.Flag("cpuprofile", "Specify the location for writing a CPU profile.").Default("").String()
 	memProfile = app.Flag("memprofile", "Specify the location for writing a memory profile.").Default("").String()
@@ -46,6 +49,13 @@ returned in a few output modes:
 	raw: log line
 	default: log timestamp + log labels + log line
 	jsonl: JSON response from Loki API of log line
+	csv: CSV with a column per label and structured metadata key
+	parquet: Parquet file with a column per label and structured metadata key
+	openmetrics: OpenMetrics text format, for metric queries only
+
+The csv and parquet columns are found in the first 1000 results. The labels
+of later results which are not in those columns are written in the
+other_labels column.
 
 The output of the log can be specified with the "-o" flag, for
 example, "-o raw" for the raw output format.
@@ -253,6 +263,80 @@ Example:
 	   'my-query'
   `)
 	volumeRangeQuery = newVolumeQuery(true, volumeRangeCmd)
+
+	deleteCmd = app.Command("delete", `Manage log deletion requests.
+
+The "delete" command creates, lists and cancels log deletion requests
+using the compactor's /loki/api/v1/delete endpoint. Log deletion
+must be enabled for the tenant.
+
+Example:
+
+	logcli delete create
+	   --from="2021-01-19T10:00:00Z"
+	   --to="2021-01-19T20:00:00Z"
+	   '{app="foo"} |= "secret"'
+
+	logcli delete list
+
+	logcli delete cancel 'request-id'
+`)
+	deleteCreateCmd   = deleteCmd.Command("create", "Create a log deletion request. The query is validated locally before it is sent.")
+	deleteCreateQuery = newDeleteCreateQuery(deleteCreateCmd)
+	deleteListCmd     = deleteCmd.Command("list", "List the log deletion requests of the tenant along with their status and progress.")
+	deleteListQuery   = newDeleteListQuery(deleteListCmd)
+	deleteCancelCmd   = deleteCmd.Command("cancel", "Cancel a log deletion request which has not been processed yet.")
+	deleteCancelQuery = newDeleteCancelQuery(deleteCancelCmd)
+
+	pushCmd = app.Command("push", `Push log lines from files or stdin to Loki.
+
+The "push" command reads the given files, or stdin when no file is given,
+and sends every line as a log entry of a single stream identified by the
+--labels selector. Lines are batched into snappy compressed protobuf push
+requests. Rate limited (429) and failed requests are retried according to
+the --retries, --min-backoff and --max-backoff flags.
+
+By default every line is timestamped when it is read. Use --timestamp-format
+to parse the timestamp from the line instead. It accepts a Go time layout or
+one of rfc3339, rfc3339nano, unix, unix_ms, unix_us and unix_ns. The timestamp
+is read from the beginning of the line, or from the first capture group of
+--timestamp-regex (or the group named "ts") when set. Lines without a
+parseable timestamp get the timestamp of the previous line.
+
+Example:
+
+	logcli push
+	   --labels='{job="incident-123", env="sandbox"}'
+	   --structured-metadata=trace_id=abc
+	   --timestamp-format=rfc3339nano
+	   app.log
+`)
+	pushQuery = newPush(pushCmd)
+
+	exploreCmd = app.Command("explore", `Interactively explore streams and tail their logs.
+
+The "explore" command opens a terminal interface listing the label names of
+the streams received during the --since period. Select a label to list its
+values, and a value to add it to the stream selector; the label names are
+then narrowed down to the streams matching the selector. Once the selector is
+specific enough, press "t" to tail it live.
+
+While tailing, scroll back through the last --buffer entries and press "f" to
+pick the detected fields to show as columns next to the log lines.
+
+Keys:
+
+	up/down, j/k       move the cursor or scroll the tail
+	pgup/pgdown        move by ten lines
+	enter              select a label or value
+	backspace          remove the last matcher
+	t                  tail the current selector
+	f                  pick the field columns while tailing
+	G                  follow the tail again after scrolling back
+	esc                go back
+	q, ctrl+c          quit
+`)
+	explorer = newExplorer(exploreCmd)
 )
 
 func main() {
@@ -330,6 +414,9 @@ func main() {
 		}
 
 		if *tail || *follow {
+			if _, ok := out.(output.Flusher); ok {
+				log.Fatalf("Output mode '%s' is not supported when tailing", *outputMode)
+			}
 			rangeQuery.TailQuery(time.Duration(*delayFor)*time.Second, queryClient, out)
 		} else if rangeQuery.ParallelMaxWorkers == 1 {
 			rangeQuery.DoQuery(queryClient, out, *statistics)
@@ -388,6 +475,16 @@ func main() {
 		} else {
 			index.GetVolume(volumeQuery, queryClient, out, *statistics)
 		}
+	case deleteCreateCmd.FullCommand():
+		deleteCreateQuery.DoCreate(queryClient)
+	case deleteListCmd.FullCommand():
+		deleteListQuery.DoList(queryClient)
+	case deleteCancelCmd.FullCommand():
+		deleteCancelQuery.DoCancel(queryClient)
+	case pushCmd.FullCommand():
+		pushQuery.DoPush(queryClient, os.Stdin)
+	case exploreCmd.FullCommand():
+		explorer.DoExplore(queryClient)
 	}
 }
 
@@ -652,3 +749,82 @@ func newVolumeQuery(rangeQuery bool, cmd *kingpin.CmdClause) *volume.Query {
 
 	return q
 }
+
+func newDeleteCreateQuery(cmd *kingpin.CmdClause) *deletequery.DeleteQuery {
+	var from, to string
+
+	q := &deletequery.DeleteQuery{}
+
+	// executed after all command flags are parsed
+	cmd.Action(func(_ *kingpin.ParseContext) error {
+		q.Start = mustParse(from, time.Time{})
+		q.End = mustParse(to, time.Now())
+		q.Quiet = *quiet
+		return nil
+	})
+
+	cmd.Arg("query", "eg '{foo=\"bar\"} |= \"secret\"'").Required().StringVar(&q.QueryString)
+	cmd.Flag("from", "Delete logs starting at this absolute time (inclusive)").Required().StringVar(&from)
+	cmd.Flag("to", "Delete logs up to this absolute time (inclusive). Defaults to now.").StringVar(&to)
+	cmd.Flag("max-interval", "Split the deletion into requests spanning at most this interval. Only used for queries with line filters.").DurationVar(&q.MaxInterval)
+
+	return q
+}
+
+func newDeleteListQuery(cmd *kingpin.CmdClause) *deletequery.DeleteQuery {
+	q := &deletequery.DeleteQuery{}
+
+	// executed after all command flags are parsed
+	cmd.Action(func(_ *kingpin.ParseContext) error {
+		q.Quiet = *quiet
+		return nil
+	})
+
+	return q
+}
+
+func newDeleteCancelQuery(cmd *kingpin.CmdClause) *deletequery.DeleteQuery {
+	q := &deletequery.DeleteQuery{}
+
+	// executed after all command flags are parsed
+	cmd.Action(func(_ *kingpin.ParseContext) error {
+		q.Quiet = *quiet
+		return nil
+	})
+
+	cmd.Arg("request-id", "ID of the delete request to cancel.").Required().StringVar(&q.RequestID)
+	cmd.Flag("force", "Cancel a partially processed delete request.").Default("false").BoolVar(&q.Force)
+
+	return q
+}
+
+func newPush(cmd *kingpin.CmdClause) *push.Push {
+	p := &push.Push{}
+
+	// executed after all command flags are parsed
+	cmd.Action(func(_ *kingpin.ParseContext) error {
+		p.Quiet = *quiet
+		return nil
+	})
+
+	cmd.Arg("files", "Files to push. Reads from stdin if none is given.").ExistingFilesVar(&p.Files)
+	cmd.Flag("labels", "Labels of the stream to push to, eg '{job=\"foo\"}'").Required().StringVar(&p.Labels)
+	cmd.Flag("structured-metadata", "Structured metadata to attach to every entry, in the form key=value. Can be repeated.").StringMapVar(&p.StructuredMetadata)
+	cmd.Flag("timestamp-format", "Format of the timestamp to parse from each line: a Go time layout or one of rfc3339, rfc3339nano, unix, unix_ms, unix_us, unix_ns. Lines are timestamped when read if not set.").StringVar(&p.TimestampFormat)
+	cmd.Flag("timestamp-regex", "Regex extracting the timestamp from each line with its first capture group or the group named 'ts'. Requires --timestamp-format.").StringVar(&p.TimestampRegex)
+	cmd.Flag("batch-size", "Maximum size in bytes of the log lines sent in a single push request.").Default("1048576").IntVar(&p.BatchSize)
+
+	return p
+}
+
+func newExplorer(cmd *kingpin.CmdClause) *explore.Explorer {
+	e := &explore.Explorer{}
+
+	cmd.Flag("since", "Lookback window used to list labels and values, and to detect fields.").Default("1h").DurationVar(&e.Since)
+	cmd.Flag("delay-for", "Delay in tailing to accumulate logs for re-ordering, eg 5s.").Default("0s").DurationVar(&e.DelayFor)
+	cmd.Flag("limit", "Limit on number of entries to print when starting to tail.").Default("100").IntVar(&e.Limit)
+	cmd.Flag("buffer", "Number of tailed entries kept in memory to scroll back through.").Default("1000").IntVar(&e.MaxEntries)
+	cmd.Flag("field-limit", "Maximum number of detected fields offered as columns.").Default("100").IntVar(&e.FieldLimit)
+
+	return e
+}
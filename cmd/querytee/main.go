@@ -62,6 +62,12 @@ func lokiReadRoutes(cfg Config) []querytee.Route {
 		Tolerance:         cfg.ProxyConfig.ValueComparisonTolerance,
 		UseRelativeError:  cfg.ProxyConfig.UseRelativeError,
 		SkipRecentSamples: cfg.ProxyConfig.SkipRecentSamples,
+
+		OrderInsensitiveStreams: cfg.ProxyConfig.OrderInsensitiveStreams,
+		SkipRecentLogEntries:    cfg.ProxyConfig.SkipRecentLogEntries,
+		IncludeStreamLabels:     cfg.ProxyConfig.IncludeStreamLabels,
+		ExcludeStreamLabels:     cfg.ProxyConfig.ExcludeStreamLabels,
+		MaxStreamDiffSamples:    cfg.ProxyConfig.MaxStreamDiffSamples,
 	})
 
 	return []querytee.Route{
@@ -145,7 +145,8 @@ func newBatchedChunkLoader(
 				Through:  c.Through,
 				Checksum: c.Checksum,
 			},
-			Itr: itr,
+			StreamLabels: c.Metric,
+			Itr:          itr,
 		}, nil
 	}
 	return newBatchedLoader(ctx, fetchers, inputs, mapper, batchSize)
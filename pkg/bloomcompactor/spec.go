@@ -88,8 +88,8 @@ func NewSimpleBloomGenerator(
 	}
 }
 
-func (s *SimpleBloomGenerator) populator(ctx context.Context) func(series *v1.Series, bloom *v1.Bloom) (int, error) {
-	return func(series *v1.Series, bloom *v1.Bloom) (int, error) {
+func (s *SimpleBloomGenerator) populator(ctx context.Context) func(series *v1.Series, bloom *v1.Bloom, summary *v1.SummaryBloom) (int, error) {
+	return func(series *v1.Series, bloom *v1.Bloom, summary *v1.SummaryBloom) (int, error) {
 		chunkItersWithFP, err := s.chunkLoader.Load(ctx, s.userID, series)
 		if err != nil {
 			return 0, errors.Wrapf(err, "failed to load chunks for series: %+v", series)
@@ -97,8 +97,9 @@ func (s *SimpleBloomGenerator) populator(ctx context.Context) func(series *v1.Se
 
 		bytesAdded, err := s.tokenizer.Populate(
 			&v1.SeriesWithBloom{
-				Series: series,
-				Bloom:  bloom,
+				Series:  series,
+				Bloom:   bloom,
+				Summary: summary,
 			},
 			chunkItersWithFP.itr,
 		)
@@ -152,7 +153,7 @@ type LazyBlockBuilderIterator struct {
 	ctx          context.Context
 	opts         v1.BlockOptions
 	metrics      *Metrics
-	populate     func(*v1.Series, *v1.Bloom) (int, error)
+	populate     func(*v1.Series, *v1.Bloom, *v1.SummaryBloom) (int, error)
 	readWriterFn func() (v1.BlockWriter, v1.BlockReader)
 	series       v1.PeekingIterator[*v1.Series]
 	blocks       v1.ResettableIterator[*v1.SeriesWithBloom]
@@ -166,7 +167,7 @@ func NewLazyBlockBuilderIterator(
 	ctx context.Context,
 	opts v1.BlockOptions,
 	metrics *Metrics,
-	populate func(*v1.Series, *v1.Bloom) (int, error),
+	populate func(*v1.Series, *v1.Bloom, *v1.SummaryBloom) (int, error),
 	readWriterFn func() (v1.BlockWriter, v1.BlockReader),
 	series v1.PeekingIterator[*v1.Series],
 	blocks v1.ResettableIterator[*v1.SeriesWithBloom],
@@ -268,11 +269,14 @@ func (s *StoreChunkLoader) Load(ctx context.Context, userID string, series *v1.S
 		fetcher := s.fetcherProvider.GetChunkFetcher(chk.From)
 		chksByFetcher[fetcher] = append(chksByFetcher[fetcher], chunk.Chunk{
 			ChunkRef: logproto.ChunkRef{
-				Fingerprint: uint64(series.Fingerprint),
-				UserID:      userID,
-				From:        chk.From,
-				Through:     chk.Through,
-				Checksum:    chk.Checksum,
+				Fingerprint:   uint64(series.Fingerprint),
+				UserID:        userID,
+				From:          chk.From,
+				Through:       chk.Through,
+				Checksum:      chk.Checksum,
+				SegmentID:     chk.SegmentID,
+				SegmentOffset: chk.SegmentOffset,
+				SegmentLength: chk.SegmentLength,
 			},
 		})
 	}
@@ -121,13 +121,18 @@ func TestSimpleBloomGenerator(t *testing.T) {
 		}{
 			{
 				desc:       "SkipsIncompatibleSchemas",
-				fromSchema: v1.NewBlockOptions(enc, 3, 0, maxBlockSize),
-				toSchema:   v1.NewBlockOptions(enc, 4, 0, maxBlockSize),
+				fromSchema: v1.NewBlockOptions(enc, 3, 0, maxBlockSize, v1.DefaultFalsePositiveRate),
+				toSchema:   v1.NewBlockOptions(enc, 4, 0, maxBlockSize, v1.DefaultFalsePositiveRate),
+			},
+			{
+				desc:       "SkipsIncompatibleFilterParams",
+				fromSchema: v1.NewBlockOptions(enc, 4, 0, maxBlockSize, 0.05),
+				toSchema:   v1.NewBlockOptions(enc, 4, 0, maxBlockSize, v1.DefaultFalsePositiveRate),
 			},
 			{
 				desc:       "CombinesBlocks",
-				fromSchema: v1.NewBlockOptions(enc, 4, 0, maxBlockSize),
-				toSchema:   v1.NewBlockOptions(enc, 4, 0, maxBlockSize),
+				fromSchema: v1.NewBlockOptions(enc, 4, 0, maxBlockSize, v1.DefaultFalsePositiveRate),
+				toSchema:   v1.NewBlockOptions(enc, 4, 0, maxBlockSize, v1.DefaultFalsePositiveRate),
 			},
 		} {
 			t.Run(fmt.Sprintf("%s/%s", tc.desc, enc), func(t *testing.T) {
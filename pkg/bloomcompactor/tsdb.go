@@ -146,9 +146,12 @@ func NewTSDBSeriesIter(ctx context.Context, user string, f sharding.ForSeries, b
 				}
 				for _, chk := range chks {
 					res.Chunks = append(res.Chunks, v1.ChunkRef{
-						From:     model.Time(chk.MinTime),
-						Through:  model.Time(chk.MaxTime),
-						Checksum: chk.Checksum,
+						From:          model.Time(chk.MinTime),
+						Through:       model.Time(chk.MaxTime),
+						Checksum:      chk.Checksum,
+						SegmentID:     chk.SegmentID,
+						SegmentOffset: chk.SegmentOffset,
+						SegmentLength: chk.SegmentLength,
 					})
 				}
 
@@ -28,9 +28,12 @@ func (f forSeriesTestImpl) ForSeries(
 		unmapped := make([]index.ChunkMeta, 0, len(f[i].Chunks))
 		for _, c := range f[i].Chunks {
 			unmapped = append(unmapped, index.ChunkMeta{
-				MinTime:  int64(c.From),
-				MaxTime:  int64(c.Through),
-				Checksum: c.Checksum,
+				MinTime:       int64(c.From),
+				MaxTime:       int64(c.Through),
+				Checksum:      c.Checksum,
+				SegmentID:     c.SegmentID,
+				SegmentOffset: c.SegmentOffset,
+				SegmentLength: c.SegmentLength,
 			})
 		}
 
@@ -54,9 +57,12 @@ func TestTSDBSeriesIter(t *testing.T) {
 					Checksum: 2,
 				},
 				{
-					From:     3,
-					Through:  4,
-					Checksum: 5,
+					From:          3,
+					Through:       4,
+					Checksum:      5,
+					SegmentID:     6,
+					SegmentOffset: 7,
+					SegmentLength: 8,
 				},
 			},
 		},
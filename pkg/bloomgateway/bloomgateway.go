@@ -87,6 +87,7 @@ type Gateway struct {
 	queue       *queue.RequestQueue
 	activeUsers *util.ActiveUsersCleanupService
 	bloomStore  bloomshipper.Store
+	limits      Limits
 
 	pendingTasks *atomic.Int64
 
@@ -107,8 +108,16 @@ func (l *fixedQueueLimits) MaxConsumers(_ string, _ int) int {
 	return l.maxConsumers
 }
 
+func (l *fixedQueueLimits) TenantWeight(_ string) float64 {
+	return 1
+}
+
+func (l *fixedQueueLimits) MaxLowPriorityCapacity(_ string) float64 {
+	return 0
+}
+
 // New returns a new instance of the Bloom Gateway.
-func New(cfg Config, store bloomshipper.Store, logger log.Logger, reg prometheus.Registerer) (*Gateway, error) {
+func New(cfg Config, store bloomshipper.Store, limits Limits, logger log.Logger, reg prometheus.Registerer) (*Gateway, error) {
 	utillog.WarnExperimentalUse("Bloom Gateway", logger)
 	g := &Gateway{
 		cfg:     cfg,
@@ -121,6 +130,7 @@ func New(cfg Config, store bloomshipper.Store, logger log.Logger, reg prometheus
 		pendingTasks: &atomic.Int64{},
 
 		bloomStore: store,
+		limits:     limits,
 	}
 
 	queueMetrics := queue.NewMetrics(reg, constants.Loki, metricsSubsystem)
@@ -227,11 +237,12 @@ func (g *Gateway) FilterChunkRefs(ctx context.Context, req *logproto.FilterChunk
 	}
 
 	filters := v1.ExtractTestableLineFilters(req.Plan.AST)
-	stats.NumFilters = len(filters)
-	g.metrics.receivedFilters.Observe(float64(len(filters)))
+	labelFilters := v1.ExtractTestableLabelFilters(req.Plan.AST)
+	stats.NumFilters = len(filters) + len(labelFilters)
+	g.metrics.receivedFilters.Observe(float64(stats.NumFilters))
 
 	// Shortcut if request does not contain filters
-	if len(filters) == 0 {
+	if len(filters) == 0 && len(labelFilters) == 0 {
 		stats.Status = labelSuccess
 		return &logproto.FilterChunkRefResponse{
 			ChunkRefs: req.Refs,
@@ -258,7 +269,7 @@ func (g *Gateway) FilterChunkRefs(ctx context.Context, req *logproto.FilterChunk
 	tasks := make([]Task, 0, len(seriesByDay))
 	responses := make([][]v1.Output, 0, len(seriesByDay))
 	for _, seriesForDay := range seriesByDay {
-		task, err := NewTask(ctx, tenantID, seriesForDay, filters)
+		task, err := NewTask(ctx, tenantID, seriesForDay, filters, labelFilters)
 		if err != nil {
 			return nil, err
 		}
@@ -500,7 +511,7 @@ func filterChunkRefsForSeries(cur *logproto.GroupedChunkRefs, removals v1.ChunkR
 	var i, j int
 	for i < len(cur.Refs) && j < len(removals) {
 
-		if (*v1.ChunkRef)(cur.Refs[i]).Less(removals[j]) {
+		if ref := (v1.ChunkRef{From: cur.Refs[i].From, Through: cur.Refs[i].Through, Checksum: cur.Refs[i].Checksum}); ref.Less(removals[j]) {
 			// chunk was not removed
 			res = append(res, cur.Refs[i])
 			i++
@@ -103,7 +103,7 @@ func TestBloomGateway_StartStopService(t *testing.T) {
 		}
 
 		store := setupBloomStore(t)
-		gw, err := New(cfg, store, logger, reg)
+		gw, err := New(cfg, store, newLimits(), logger, reg)
 		require.NoError(t, err)
 
 		err = services.StartAndAwaitRunning(context.Background(), gw)
@@ -137,7 +137,7 @@ func TestBloomGateway_FilterChunkRefs(t *testing.T) {
 		mockStore.err = errors.New("request failed")
 
 		reg := prometheus.NewRegistry()
-		gw, err := New(cfg, mockStore, logger, reg)
+		gw, err := New(cfg, mockStore, newLimits(), logger, reg)
 		require.NoError(t, err)
 
 		err = services.StartAndAwaitRunning(context.Background(), gw)
@@ -180,7 +180,7 @@ func TestBloomGateway_FilterChunkRefs(t *testing.T) {
 		mockStore.delay = 2000 * time.Millisecond
 
 		reg := prometheus.NewRegistry()
-		gw, err := New(cfg, mockStore, logger, reg)
+		gw, err := New(cfg, mockStore, newLimits(), logger, reg)
 		require.NoError(t, err)
 
 		err = services.StartAndAwaitRunning(context.Background(), gw)
@@ -218,7 +218,7 @@ func TestBloomGateway_FilterChunkRefs(t *testing.T) {
 		now := mktime("2023-10-03 10:00")
 
 		reg := prometheus.NewRegistry()
-		gw, err := New(cfg, newMockBloomStore(nil, nil), logger, reg)
+		gw, err := New(cfg, newMockBloomStore(nil, nil), newLimits(), logger, reg)
 		require.NoError(t, err)
 
 		err = services.StartAndAwaitRunning(context.Background(), gw)
@@ -263,7 +263,7 @@ func TestBloomGateway_FilterChunkRefs(t *testing.T) {
 		now := mktime("2023-10-03 10:00")
 
 		reg := prometheus.NewRegistry()
-		gw, err := New(cfg, newMockBloomStore(nil, nil), logger, reg)
+		gw, err := New(cfg, newMockBloomStore(nil, nil), newLimits(), logger, reg)
 		require.NoError(t, err)
 
 		err = services.StartAndAwaitRunning(context.Background(), gw)
@@ -308,7 +308,7 @@ func TestBloomGateway_FilterChunkRefs(t *testing.T) {
 		reg := prometheus.NewRegistry()
 		store := newMockBloomStore(queriers, metas)
 
-		gw, err := New(cfg, store, logger, reg)
+		gw, err := New(cfg, store, newLimits(), logger, reg)
 		require.NoError(t, err)
 
 		err = services.StartAndAwaitRunning(context.Background(), gw)
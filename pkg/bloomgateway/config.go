@@ -1,7 +1,9 @@
 package bloomgateway
 
 import (
+	"context"
 	"flag"
+	"time"
 )
 
 // Config configures the Bloom Gateway component.
@@ -48,4 +50,8 @@ type Limits interface {
 	CacheLimits
 	BloomGatewayShardSize(tenantID string) int
 	BloomGatewayEnabled(tenantID string) bool
+	BloomNGramLength(tenantID string) int
+	BloomNGramSkip(tenantID string) int
+	MaxQueryLength(ctx context.Context, userID string) time.Duration
+	MaxQueryLookback(ctx context.Context, userID string) time.Duration
 }
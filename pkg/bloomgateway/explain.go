@@ -0,0 +1,142 @@
+package bloomgateway
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/tenant"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	v1 "github.com/grafana/loki/v3/pkg/storage/bloom/v1"
+	"github.com/grafana/loki/v3/pkg/storage/config"
+	"github.com/grafana/loki/v3/pkg/storage/stores/shipper/bloomshipper"
+	"github.com/grafana/loki/v3/pkg/util"
+	serverutil "github.com/grafana/loki/v3/pkg/util/server"
+	util_validation "github.com/grafana/loki/v3/pkg/util/validation"
+)
+
+const defaultExplainRange = time.Hour
+
+// DayBlocks describes the bloom blocks available for a single day.
+type DayBlocks struct {
+	Day    string `json:"day"`
+	Metas  int    `json:"metas"`
+	Blocks int    `json:"blocks"`
+	// Coverage is the fraction of the fingerprint keyspace covered by the blocks of the day.
+	Coverage float64 `json:"coverage"`
+}
+
+// ExplainResponse describes how blooms can accelerate a query.
+type ExplainResponse struct {
+	Filters []v1.FilterEligibility `json:"filters"`
+	Days    []DayBlocks            `json:"days"`
+}
+
+// Explain returns the bloom eligibility of the filters of the query and the bloom blocks available for each
+// day of the given time range. Filters are explained using the n-gram settings of the tenant.
+// The time range is limited by the max query lookback and length of the tenant.
+func (g *Gateway) Explain(ctx context.Context, tenantID string, from, through model.Time, expr syntax.Expr) (*ExplainResponse, error) {
+	if lookback := g.limits.MaxQueryLookback(ctx, tenantID); lookback > 0 && from.Before(model.Now().Add(-lookback)) {
+		from = model.Now().Add(-lookback)
+		if through.Before(from) {
+			return nil, httpgrpc.Errorf(http.StatusBadRequest, util_validation.ErrQueryTooOld, model.Duration(lookback))
+		}
+	}
+	if length := g.limits.MaxQueryLength(ctx, tenantID); length > 0 && through.Sub(from) > length {
+		return nil, httpgrpc.Errorf(http.StatusBadRequest, util_validation.ErrQueryTooLong, through.Sub(from), model.Duration(length))
+	}
+
+	tokenizer := v1.NewNGramTokenizer(g.limits.BloomNGramLength(tenantID), g.limits.BloomNGramSkip(tenantID))
+	resp := &ExplainResponse{
+		Filters: v1.ExplainFilters(tokenizer, expr),
+		Days:    []DayBlocks{},
+	}
+
+	keyspace := v1.NewBounds(0, math.MaxUint64)
+	for day := truncateDay(from); !day.After(through); day = day.Add(Day) {
+		dayTime := config.NewDayTime(day)
+		interval := bloomshipper.NewInterval(dayTime.Bounds())
+		metas, err := g.bloomStore.FetchMetas(ctx, bloomshipper.MetaSearchParams{
+			TenantID: tenantID,
+			Interval: interval,
+			Keyspace: keyspace,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching metas for day %s", dayTime)
+		}
+
+		blocks := bloomshipper.BlocksForMetas(metas, interval, []v1.FingerprintBounds{keyspace})
+		var covered v1.MultiFingerprintBounds
+		for _, block := range blocks {
+			covered = covered.Union(block.Bounds)
+		}
+		var fingerprints float64
+		for _, bounds := range covered {
+			fingerprints += float64(bounds.Range()) + 1
+		}
+
+		resp.Days = append(resp.Days, DayBlocks{
+			Day:      dayTime.String(),
+			Metas:    len(metas),
+			Blocks:   len(blocks),
+			Coverage: fingerprints / (float64(math.MaxUint64) + 1),
+		})
+	}
+	return resp, nil
+}
+
+// ExplainHandler returns the bloom explanation of the query of the request.
+// The time range defaults to the last hour.
+func (g *Gateway) ExplainHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	expr, err := syntax.ParseExpr(r.FormValue("query"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	through := model.Now()
+	if v := r.FormValue("end"); v != "" {
+		ts, err := util.ParseTime(v)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		through = model.Time(ts)
+	}
+	from := through.Add(-defaultExplainRange)
+	if v := r.FormValue("start"); v != "" {
+		ts, err := util.ParseTime(v)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		from = model.Time(ts)
+	}
+	if through.Before(from) {
+		http.Error(w, "end timestamp must not be before start time", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := g.Explain(r.Context(), tenantID, from, through, expr)
+	if err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,122 @@
+package bloomgateway
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/grafana/loki/v3/pkg/storage/bloom/v1"
+	"github.com/grafana/loki/v3/pkg/storage/config"
+	"github.com/grafana/loki/v3/pkg/storage/stores/shipper/bloomshipper"
+)
+
+func TestGateway_Explain(t *testing.T) {
+	tenantID := "fake"
+	now := mktime("2023-10-03 10:00")
+
+	// blocks covering half of the keyspace
+	var metas []bloomshipper.Meta
+	for _, bounds := range []v1.FingerprintBounds{
+		v1.NewBounds(0, math.MaxUint64/4),
+		v1.NewBounds(math.MaxUint64/4+1, math.MaxUint64/2),
+	} {
+		ref := bloomshipper.Ref{
+			TenantID:       tenantID,
+			TableName:      config.NewDayTable(config.NewDayTime(truncateDay(now)), "").Addr(),
+			Bounds:         bounds,
+			StartTimestamp: now.Add(-2 * time.Hour),
+			EndTimestamp:   now,
+		}
+		metas = append(metas, bloomshipper.Meta{
+			MetaRef: bloomshipper.MetaRef{Ref: ref},
+			Blocks:  []bloomshipper.BlockRef{{Ref: ref}},
+		})
+	}
+	store := newMockBloomStore(nil, metas)
+
+	gw, err := New(Config{Enabled: true}, store, newLimits(), log.NewNopLogger(), prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	params := url.Values{
+		"query": []string{`{app="fake"} |= "foobar" != "bazqux"`},
+		"start": []string{now.Add(-2 * time.Hour).String()},
+		"end":   []string{now.String()},
+	}
+	req := httptest.NewRequest(http.MethodGet, "/bloomgateway/explain?"+params.Encode(), nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), tenantID))
+	w := httptest.NewRecorder()
+	gw.ExplainHandler(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp ExplainResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, []v1.FilterEligibility{
+		{Filter: `|= "foobar"`, Testable: true},
+		{Filter: `!= "bazqux"`, Reason: "negated filters can't be tested"},
+	}, resp.Filters)
+	require.Len(t, resp.Days, 1)
+	require.Equal(t, "2023-10-03", resp.Days[0].Day)
+	require.Equal(t, 2, resp.Days[0].Metas)
+	require.Equal(t, 2, resp.Days[0].Blocks)
+	require.InDelta(t, 0.5, resp.Days[0].Coverage, 0.001)
+
+	t.Run("invalid query", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/bloomgateway/explain?query=foo", nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), tenantID))
+		w := httptest.NewRecorder()
+		gw.ExplainHandler(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("end before start", func(t *testing.T) {
+		params := url.Values{
+			"query": []string{`{app="fake"}`},
+			"start": []string{now.String()},
+			"end":   []string{now.Add(-time.Hour).String()},
+		}
+		req := httptest.NewRequest(http.MethodGet, "/bloomgateway/explain?"+params.Encode(), nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), tenantID))
+		w := httptest.NewRecorder()
+		gw.ExplainHandler(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("range exceeds max query length", func(t *testing.T) {
+		params := url.Values{
+			"query": []string{`{app="fake"}`},
+			"start": []string{now.Add(-800 * time.Hour).String()},
+			"end":   []string{now.String()},
+		}
+		req := httptest.NewRequest(http.MethodGet, "/bloomgateway/explain?"+params.Encode(), nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), tenantID))
+		w := httptest.NewRecorder()
+		gw.ExplainHandler(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Contains(t, w.Body.String(), "the query time range exceeds the limit")
+	})
+}
+
+func TestGateway_ExplainDays(t *testing.T) {
+	now := mktime("2023-10-03 10:00")
+	gw, err := New(Config{Enabled: true}, newMockBloomStore(nil, nil), newLimits(), log.NewNopLogger(), prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	resp, err := gw.Explain(context.Background(), "fake", now.Add(-49*time.Hour), now, nil)
+	require.NoError(t, err)
+	require.Empty(t, resp.Filters)
+	require.Equal(t, []DayBlocks{
+		{Day: "2023-10-01"},
+		{Day: "2023-10-02"},
+		{Day: "2023-10-03"},
+	}, resp.Days)
+}
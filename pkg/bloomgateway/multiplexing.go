@@ -65,6 +65,8 @@ type Task struct {
 	series []*logproto.GroupedChunkRefs
 	// filters of the original request
 	filters []syntax.LineFilterExpr
+	// label filters of the original request, tested against structured metadata and stream labels
+	labelFilters []syntax.LabelFilterExpr
 	// from..through date of the task's chunks
 	interval bloomshipper.Interval
 	// the context from the request
@@ -80,23 +82,24 @@ type Task struct {
 // NewTask returns a new Task that can be enqueued to the task queue.
 // In addition, it returns a result and an error channel, as well
 // as an error if the instantiation fails.
-func NewTask(ctx context.Context, tenantID string, refs seriesWithInterval, filters []syntax.LineFilterExpr) (Task, error) {
+func NewTask(ctx context.Context, tenantID string, refs seriesWithInterval, filters []syntax.LineFilterExpr, labelFilters []syntax.LabelFilterExpr) (Task, error) {
 	key, err := ulid.New(ulid.Now(), entropy)
 	if err != nil {
 		return Task{}, err
 	}
 
 	task := Task{
-		ID:       key,
-		Tenant:   tenantID,
-		err:      new(wrappedError),
-		resCh:    make(chan v1.Output),
-		filters:  filters,
-		series:   refs.series,
-		interval: refs.interval,
-		table:    refs.day,
-		ctx:      ctx,
-		done:     make(chan struct{}),
+		ID:           key,
+		Tenant:       tenantID,
+		err:          new(wrappedError),
+		resCh:        make(chan v1.Output),
+		filters:      filters,
+		labelFilters: labelFilters,
+		series:       refs.series,
+		interval:     refs.interval,
+		table:        refs.day,
+		ctx:          ctx,
+		done:         make(chan struct{}),
 	}
 	return task, nil
 }
@@ -129,22 +132,30 @@ func (t Task) CloseWithError(err error) {
 func (t Task) Copy(series []*logproto.GroupedChunkRefs) Task {
 	// do not copy ID to distinguish it as copied task
 	return Task{
-		Tenant:   t.Tenant,
-		err:      t.err,
-		resCh:    t.resCh,
-		filters:  t.filters,
-		series:   series,
-		interval: t.interval,
-		table:    t.table,
-		ctx:      t.ctx,
-		done:     make(chan struct{}),
+		Tenant:       t.Tenant,
+		err:          t.err,
+		resCh:        t.resCh,
+		filters:      t.filters,
+		labelFilters: t.labelFilters,
+		series:       series,
+		interval:     t.interval,
+		table:        t.table,
+		ctx:          t.ctx,
+		done:         make(chan struct{}),
 	}
 }
 
-func (t Task) RequestIter(tokenizer *v1.NGramTokenizer) v1.Iterator[v1.Request] {
+// RequestIter returns the requests of the task for a block of the given schema.
+// Label filters are only tested against blocks indexing structured metadata and stream labels.
+func (t Task) RequestIter(schema v1.Schema, tokenizer *v1.NGramTokenizer) v1.Iterator[v1.Request] {
+	search := v1.FiltersToBloomTest(tokenizer, t.filters...)
+	if len(t.labelFilters) > 0 && schema.IndexesLabels() {
+		search = v1.BloomTests{search, v1.LabelFiltersToBloomTest(t.labelFilters...)}
+	}
+
 	return &requestIterator{
 		series:  v1.NewSliceIter(t.series),
-		search:  v1.FiltersToBloomTest(tokenizer, t.filters...),
+		search:  search,
 		channel: t.resCh,
 		curr:    v1.Request{},
 	}
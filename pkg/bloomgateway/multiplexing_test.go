@@ -9,6 +9,7 @@ import (
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/loki/v3/pkg/chunkenc"
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logql/syntax"
 	v1 "github.com/grafana/loki/v3/pkg/storage/bloom/v1"
@@ -31,7 +32,7 @@ func TestTask(t *testing.T) {
 			},
 		}
 		swb := partitionRequest(req)[0]
-		task, err := NewTask(context.Background(), "tenant", swb, nil)
+		task, err := NewTask(context.Background(), "tenant", swb, nil, nil)
 		require.NoError(t, err)
 		from, through := task.Bounds()
 		require.Equal(t, ts.Add(-1*time.Hour), from)
@@ -45,7 +46,7 @@ func createTasksForRequests(t *testing.T, tenant string, requests ...*logproto.F
 	tasks := make([]Task, 0, len(requests))
 	for _, r := range requests {
 		for _, swb := range partitionRequest(r) {
-			task, err := NewTask(context.Background(), tenant, swb, nil)
+			task, err := NewTask(context.Background(), tenant, swb, nil, nil)
 			require.NoError(t, err)
 			tasks = append(tasks, task)
 		}
@@ -56,6 +57,7 @@ func createTasksForRequests(t *testing.T, tenant string, requests ...*logproto.F
 func TestTask_RequestIterator(t *testing.T) {
 	ts := mktime("2024-01-24 12:00")
 	tenant := "fake"
+	schema := v1.NewBlockOptions(chunkenc.EncNone, 4, 0, 0, v1.DefaultFalsePositiveRate).Schema
 	tokenizer := v1.NewNGramTokenizer(4, 0)
 
 	t.Run("empty request yields empty iterator", func(t *testing.T) {
@@ -63,8 +65,8 @@ func TestTask_RequestIterator(t *testing.T) {
 			interval: bloomshipper.Interval{Start: 0, End: math.MaxInt64},
 			series:   []*logproto.GroupedChunkRefs{},
 		}
-		task, _ := NewTask(context.Background(), tenant, swb, []syntax.LineFilterExpr{})
-		it := task.RequestIter(tokenizer)
+		task, _ := NewTask(context.Background(), tenant, swb, []syntax.LineFilterExpr{}, nil)
+		it := task.RequestIter(schema, tokenizer)
 		// nothing to iterate over
 		require.False(t, it.Next())
 	})
@@ -107,7 +109,7 @@ func TestTask_RequestIterator(t *testing.T) {
 
 		iters := make([]v1.PeekingIterator[v1.Request], 0, len(tasks))
 		for _, task := range tasks {
-			iters = append(iters, v1.NewPeekingIter(task.RequestIter(tokenizer)))
+			iters = append(iters, v1.NewPeekingIter(task.RequestIter(schema, tokenizer)))
 		}
 
 		// merge the request iterators using the heap sort iterator
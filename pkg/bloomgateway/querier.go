@@ -10,6 +10,7 @@ import (
 	"github.com/prometheus/common/model"
 
 	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
 	"github.com/grafana/loki/v3/pkg/querier/plan"
 	v1 "github.com/grafana/loki/v3/pkg/storage/bloom/v1"
 	"github.com/grafana/loki/v3/pkg/util/constants"
@@ -55,25 +56,44 @@ func newQuerierMetrics(registerer prometheus.Registerer, namespace, subsystem st
 // It is used by the index gateway to filter ChunkRefs based on given line fiter expression.
 type BloomQuerier struct {
 	c       Client
+	limits  Limits
 	logger  log.Logger
 	metrics *querierMetrics
 }
 
-func NewQuerier(c Client, r prometheus.Registerer, logger log.Logger) *BloomQuerier {
+func NewQuerier(c Client, limits Limits, r prometheus.Registerer, logger log.Logger) *BloomQuerier {
 	return &BloomQuerier{
 		c:       c,
+		limits:  limits,
 		metrics: newQuerierMetrics(r, constants.Loki, querierMetricsSubsystem),
 		logger:  logger,
 	}
 }
 
+type chunkRefKey struct {
+	fingerprint uint64
+	ref         logproto.ShortRef
+}
+
 func convertToShortRef(ref *logproto.ChunkRef) *logproto.ShortRef {
 	return &logproto.ShortRef{From: ref.From, Through: ref.Through, Checksum: ref.Checksum}
 }
 
+// hasTestableFilters returns whether the query contains line or label filters which can be tested against blooms.
+func hasTestableFilters(expr syntax.Expr) bool {
+	return len(v1.ExtractTestableLineFilters(expr)) > 0 || len(v1.ExtractTestableLabelFilters(expr)) > 0
+}
+
+// ExplainFilters returns the bloom eligibility of the line and label filters of the query,
+// using the n-gram settings of the tenant.
+func (bq *BloomQuerier) ExplainFilters(tenant string, expr syntax.Expr) []v1.FilterEligibility {
+	tokenizer := v1.NewNGramTokenizer(bq.limits.BloomNGramLength(tenant), bq.limits.BloomNGramSkip(tenant))
+	return v1.ExplainFilters(tokenizer, expr)
+}
+
 func (bq *BloomQuerier) FilterChunkRefs(ctx context.Context, tenant string, from, through model.Time, chunkRefs []*logproto.ChunkRef, queryPlan plan.QueryPlan) ([]*logproto.ChunkRef, error) {
 	// Shortcut that does not require any filtering
-	if len(chunkRefs) == 0 || len(v1.ExtractTestableLineFilters(queryPlan.AST)) == 0 {
+	if len(chunkRefs) == 0 || !hasTestableFilters(queryPlan.AST) {
 		return chunkRefs, nil
 	}
 
@@ -90,10 +110,19 @@ func (bq *BloomQuerier) FilterChunkRefs(ctx context.Context, tenant string, from
 		return nil, err
 	}
 
-	// Flatten response from client and return
+	// Flatten response from client and return the original refs of the remaining chunks,
+	// since short refs don't hold the segment the chunks are stored in.
+	byKey := make(map[chunkRefKey]*logproto.ChunkRef, len(chunkRefs))
+	for _, ref := range chunkRefs {
+		byKey[chunkRefKey{ref.Fingerprint, *convertToShortRef(ref)}] = ref
+	}
 	result := make([]*logproto.ChunkRef, 0, len(chunkRefs))
 	for i := range refs {
 		for _, ref := range refs[i].Refs {
+			if chunkRef, ok := byKey[chunkRefKey{refs[i].Fingerprint, *ref}]; ok {
+				result = append(result, chunkRef)
+				continue
+			}
 			result = append(result, &logproto.ChunkRef{
 				Fingerprint: refs[i].Fingerprint,
 				UserID:      tenant,
@@ -32,7 +32,7 @@ func TestBloomQuerier(t *testing.T) {
 
 	t.Run("client not called when filters are empty", func(t *testing.T) {
 		c := &noopClient{}
-		bq := NewQuerier(c, nil, logger)
+		bq := NewQuerier(c, newLimits(), nil, logger)
 
 		ctx := context.Background()
 		through := model.Now()
@@ -52,7 +52,7 @@ func TestBloomQuerier(t *testing.T) {
 
 	t.Run("client not called when chunkRefs are empty", func(t *testing.T) {
 		c := &noopClient{}
-		bq := NewQuerier(c, nil, logger)
+		bq := NewQuerier(c, newLimits(), nil, logger)
 
 		ctx := context.Background()
 		through := model.Now()
@@ -68,7 +68,7 @@ func TestBloomQuerier(t *testing.T) {
 
 	t.Run("querier propagates error from client", func(t *testing.T) {
 		c := &noopClient{err: errors.New("something went wrong")}
-		bq := NewQuerier(c, nil, logger)
+		bq := NewQuerier(c, newLimits(), nil, logger)
 
 		ctx := context.Background()
 		through := model.Now()
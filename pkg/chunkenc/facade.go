@@ -1,11 +1,14 @@
 package chunkenc
 
 import (
+	"context"
 	"io"
+	"sort"
 	"time"
 
 	"github.com/prometheus/common/model"
 
+	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/storage/chunk"
 	"github.com/grafana/loki/v3/pkg/util/filter"
 )
@@ -128,3 +131,38 @@ func UncompressedSize(c chunk.Data) (int, bool) {
 
 	return f.c.UncompressedSize(), true
 }
+
+// StructuredMetadataKeys is a helper function returning the sorted names of the structured metadata keys
+// of the entries of the Cortex interface encoding.Chunk. Keys collected while appending are used when available,
+// otherwise the whole chunk is decoded.
+func StructuredMetadataKeys(c chunk.Data) ([]string, error) {
+	f, ok := c.(*Facade)
+	if !ok || f.c == nil {
+		return nil, nil
+	}
+	if mc, ok := f.c.(*MemChunk); ok {
+		if keys, ok := mc.StructuredMetadataKeys(); ok {
+			return keys, nil
+		}
+	}
+
+	from, through := f.c.Bounds()
+	it, err := f.c.Iterator(context.Background(), from, through.Add(time.Nanosecond), logproto.FORWARD, noopStreamPipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	seen := map[string]struct{}{}
+	var keys []string
+	for it.Next() {
+		for _, l := range it.Entry().StructuredMetadata {
+			if _, ok := seen[l.Name]; !ok {
+				seen[l.Name] = struct{}{}
+				keys = append(keys, l.Name)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys, it.Error()
+}
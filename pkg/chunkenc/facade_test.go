@@ -0,0 +1,44 @@
+package chunkenc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/push"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+func TestStructuredMetadataKeys(t *testing.T) {
+	c := NewMemChunk(ChunkFormatV4, EncSnappy, UnorderedWithStructuredMetadataHeadBlockFmt, testBlockSize, testTargetSize)
+	for i, sm := range []push.LabelsAdapter{
+		{{Name: "trace_id", Value: "1"}},
+		nil,
+		{{Name: "pod", Value: "a"}, {Name: "trace_id", Value: "2"}},
+	} {
+		require.NoError(t, c.Append(&logproto.Entry{
+			Timestamp:          time.Unix(0, int64(i)),
+			Line:               "line",
+			StructuredMetadata: sm,
+		}))
+	}
+	require.NoError(t, c.cut())
+
+	keys, err := StructuredMetadataKeys(NewFacade(c, testBlockSize, testTargetSize))
+	require.NoError(t, err)
+	require.Equal(t, []string{"pod", "trace_id"}, keys)
+
+	// keys of chunks decoded from storage aren't tracked and are read from the entries.
+	b, err := c.Bytes()
+	require.NoError(t, err)
+	decoded, err := NewByteChunk(b, testBlockSize, testTargetSize)
+	require.NoError(t, err)
+	_, ok := decoded.StructuredMetadataKeys()
+	require.False(t, ok)
+
+	keys, err = StructuredMetadataKeys(NewFacade(decoded, testBlockSize, testTargetSize))
+	require.NoError(t, err)
+	require.Equal(t, []string{"pod", "trace_id"}, keys)
+}
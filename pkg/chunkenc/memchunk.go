@@ -9,6 +9,8 @@ import (
 	"hash/crc32"
 	"io"
 	"reflect"
+	"sort"
+	"strings"
 	"time"
 	"unsafe"
 
@@ -137,6 +139,10 @@ type MemChunk struct {
 
 	// compressed size of chunk. Set when chunk is cut or while decoding chunk from storage.
 	compressedSize int
+
+	// names of the structured metadata keys of the appended entries.
+	// nil when the chunk wasn't built by appending, e.g. when decoded from storage.
+	structuredMetadataKeys map[string]struct{}
 }
 
 type block struct {
@@ -384,6 +390,8 @@ func newMemChunkWithFormat(format byte, enc Encoding, head HeadBlockFmt, blockSi
 		encoding:   enc,
 		headFmt:    head,
 		symbolizer: symbolizer,
+
+		structuredMetadataKeys: map[string]struct{}{},
 	}
 }
 
@@ -849,6 +857,14 @@ func (c *MemChunk) Append(entry *logproto.Entry) error {
 	if err := c.head.Append(entryTimestamp, entry.Line, logproto.FromLabelAdaptersToLabels(entry.StructuredMetadata)); err != nil {
 		return err
 	}
+	if c.structuredMetadataKeys != nil {
+		for _, l := range entry.StructuredMetadata {
+			if _, ok := c.structuredMetadataKeys[l.Name]; !ok {
+				// the name may reference the push request buffer.
+				c.structuredMetadataKeys[strings.Clone(l.Name)] = struct{}{}
+			}
+		}
+	}
 
 	if c.head.UncompressedSize() >= c.blockSize {
 		return c.cut()
@@ -857,6 +873,20 @@ func (c *MemChunk) Append(entry *logproto.Entry) error {
 	return nil
 }
 
+// StructuredMetadataKeys returns the sorted names of the structured metadata keys of the appended entries.
+// It returns false when they are unknown, i.e. for chunks decoded from storage or checkpoints.
+func (c *MemChunk) StructuredMetadataKeys() ([]string, bool) {
+	if c.structuredMetadataKeys == nil {
+		return nil, false
+	}
+	keys := make([]string, 0, len(c.structuredMetadataKeys))
+	for k := range c.structuredMetadataKeys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, true
+}
+
 // Close implements Chunk.
 // TODO: Fix this to check edge cases.
 func (c *MemChunk) Close() error {
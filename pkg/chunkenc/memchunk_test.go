@@ -1158,6 +1158,11 @@ func TestCheckpointEncoding(t *testing.T) {
 				}
 			}
 
+			// structured metadata keys are only tracked while appending.
+			_, ok := cpy.StructuredMetadataKeys()
+			require.False(t, ok)
+			cpy.structuredMetadataKeys = c.structuredMetadataKeys
+
 			require.Equal(t, c, cpy)
 
 			// add a few more to head
@@ -1187,6 +1192,11 @@ func TestCheckpointEncoding(t *testing.T) {
 				}
 			}
 
+			// structured metadata keys are only tracked while appending.
+			_, ok = cpy.StructuredMetadataKeys()
+			require.False(t, ok)
+			cpy.structuredMetadataKeys = c.structuredMetadataKeys
+
 			require.Equal(t, c, cpy)
 		})
 	}
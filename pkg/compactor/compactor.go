@@ -23,6 +23,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/compactor/deletion"
 	"github.com/grafana/loki/v3/pkg/compactor/retention"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/client"
+	"github.com/grafana/loki/v3/pkg/storage/chunk/client/encryption"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/client/local"
 	chunk_util "github.com/grafana/loki/v3/pkg/storage/chunk/client/util"
 	"github.com/grafana/loki/v3/pkg/storage/config"
@@ -88,6 +89,7 @@ type Config struct {
 	RunOnce                     bool                `yaml:"_" doc:"hidden"`
 	TablesToCompact             int                 `yaml:"tables_to_compact"`
 	SkipLatestNTables           int                 `yaml:"skip_latest_n_tables"`
+	KeyRotationEnabled          bool                `yaml:"encryption_key_rotation_enabled" category:"experimental"`
 }
 
 // RegisterFlags registers flags.
@@ -106,6 +108,7 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.DurationVar(&cfg.RetentionTableTimeout, "compactor.retention-table-timeout", 0, "The maximum amount of time to spend running retention and deletion on any given table in the index.")
 	f.IntVar(&cfg.MaxCompactionParallelism, "compactor.max-compaction-parallelism", 1, "Maximum number of tables to compact in parallel. While increasing this value, please make sure compactor has enough disk space allocated to be able to store and compact as many tables.")
 	f.IntVar(&cfg.UploadParallelism, "compactor.upload-parallelism", 10, "Number of upload/remove operations to execute in parallel when finalizing a compaction. NOTE: This setting is per compaction operation, which can be executed in parallel. The upper bound on the number of concurrent uploads is upload_parallelism * max_compaction_parallelism.")
+	f.BoolVar(&cfg.KeyRotationEnabled, "compactor.encryption-key-rotation-enabled", false, "Experimental: Re-encrypt the chunks and index files of tenants with their current key during compaction, so that their previous keys can be destroyed. Requires encryption at rest to be enabled for the store.")
 	f.BoolVar(&cfg.RunOnce, "compactor.run-once", false, "Run the compactor one time to cleanup and compact index files only (no retention applied)")
 	f.IntVar(&cfg.TablesToCompact, "compactor.tables-to-compact", 0, "Number of tables that compactor will try to compact. Newer tables are chosen when this is less than the number of tables available.")
 	f.IntVar(&cfg.SkipLatestNTables, "compactor.skip-latest-n-tables", 0, "Do not compact N latest tables. Together with -compactor.run-once and -compactor.tables-to-compact, this is useful when clearing compactor backlogs.")
@@ -192,6 +195,7 @@ type storeContainer struct {
 	tableMarker        retention.TableMarker
 	sweeper            *retention.Sweeper
 	indexStorageClient storage.Client
+	keyRotator         *keyRotator
 }
 
 type Limits interface {
@@ -291,10 +295,30 @@ func (c *Compactor) init(objectStoreClients map[config.DayTime]client.ObjectClie
 		var sc storeContainer
 		sc.indexStorageClient = storage.NewIndexStorageClient(objectClient, period.IndexTables.PathPrefix)
 
+		var (
+			raw     = objectClient
+			encoder client.KeyEncoder
+		)
+		encrypted, isEncrypted := objectClient.(*encryption.ObjectClient)
+		if isEncrypted {
+			raw = encrypted.GetDownstream()
+		}
+		if casted, ok := raw.(client.PrefixedObjectClient); ok {
+			raw = casted.GetDownstream()
+		}
+		if _, ok := raw.(*local.FSObjectClient); ok {
+			encoder = client.FSEncoder
+		}
+
+		if c.cfg.KeyRotationEnabled {
+			if !isEncrypted {
+				return fmt.Errorf("encryption key rotation requires encryption at rest to be enabled for the store of the period starting at %s", period.From.String())
+			}
+			sc.keyRotator = newKeyRotator(encrypted, encoder, schemaConfig)
+		}
+
 		if c.cfg.RetentionEnabled {
 			var (
-				raw              client.ObjectClient
-				encoder          client.KeyEncoder
 				name             = fmt.Sprintf("%s_%s", period.ObjectType, period.From.String())
 				retentionWorkDir = filepath.Join(c.cfg.WorkingDirectory, "retention", name)
 				r                = prometheus.WrapRegistererWith(prometheus.Labels{"from": name}, r)
@@ -313,14 +337,6 @@ func (c *Compactor) init(objectStoreClients map[config.DayTime]client.ObjectClie
 			// remove markers from the store dir after copying them to period specific dirs.
 			legacyMarkerDirs[period.ObjectType] = struct{}{}
 
-			if casted, ok := objectClient.(client.PrefixedObjectClient); ok {
-				raw = casted.GetDownstream()
-			} else {
-				raw = objectClient
-			}
-			if _, ok := raw.(*local.FSObjectClient); ok {
-				encoder = client.FSEncoder
-			}
 			chunkClient := client.NewClient(objectClient, encoder, schemaConfig)
 
 			sc.sweeper, err = retention.NewSweeper(retentionWorkDir, chunkClient, c.cfg.RetentionDeleteWorkCount, c.cfg.RetentionDeleteDelay, r)
@@ -646,6 +662,7 @@ func (c *Compactor) CompactTable(ctx context.Context, tableName string, applyRet
 		level.Error(util_log.Logger).Log("msg", "failed to initialize table for compaction", "table", tableName, "err", err)
 		return err
 	}
+	table.keyRotator = sc.keyRotator
 
 	interval := retention.ExtractIntervalFromTableName(tableName)
 	intervalMayHaveExpiredChunks := false
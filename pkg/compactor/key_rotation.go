@@ -0,0 +1,152 @@
+package compactor
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/go-kit/log/level"
+
+	"github.com/grafana/loki/v3/pkg/compactor/retention"
+	"github.com/grafana/loki/v3/pkg/storage/chunk"
+	"github.com/grafana/loki/v3/pkg/storage/chunk/client"
+	"github.com/grafana/loki/v3/pkg/storage/chunk/client/encryption"
+	"github.com/grafana/loki/v3/pkg/storage/config"
+)
+
+// keyRotator re-encrypts the chunks and index files of tenants with their current encryption key during compaction.
+// It remembers the tables it already rotated to the current key of each tenant to avoid scanning them on every run.
+type keyRotator struct {
+	objectClient *encryption.ObjectClient
+	encoder      client.KeyEncoder
+	schemaCfg    config.SchemaConfig
+
+	mtx     sync.Mutex
+	rotated map[string]string
+}
+
+func newKeyRotator(objectClient *encryption.ObjectClient, encoder client.KeyEncoder, schemaCfg config.SchemaConfig) *keyRotator {
+	return &keyRotator{
+		objectClient: objectClient,
+		encoder:      encoder,
+		schemaCfg:    schemaCfg,
+		rotated:      map[string]string{},
+	}
+}
+
+func rotationKey(tableName, userID string) string {
+	return tableName + "/" + userID
+}
+
+func (r *keyRotator) isRotated(tableName, userID, keyID string) bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return r.rotated[rotationKey(tableName, userID)] == keyID
+}
+
+// markRotated records the keys the index sets of the table were rotated to, once they have been uploaded.
+func (r *keyRotator) markRotated(tableName string, rotatedKeys map[string]string) {
+	if r == nil {
+		return
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	for userID, keyID := range rotatedKeys {
+		r.rotated[rotationKey(tableName, userID)] = keyID
+	}
+}
+
+func (r *keyRotator) chunkKey(userID, chunkID string) (string, error) {
+	if r.encoder == nil {
+		return chunkID, nil
+	}
+
+	c, err := chunk.ParseExternalKey(userID, chunkID)
+	if err != nil {
+		return "", err
+	}
+	return r.encoder(r.schemaCfg, c), nil
+}
+
+// rotateKeys re-encrypts the chunks of the user index sets of the table which weren't rotated to the current key of
+// their tenant yet, and makes the index sets upload their index again so that it is encrypted with the current key.
+// Common index sets hold the index of several tenants and are encrypted with the default key whenever they are compacted.
+// It returns the keys the index sets were rotated to.
+func (t *table) rotateKeys() (map[string]string, error) {
+	rotatedKeys := map[string]string{}
+	for userID, is := range t.indexSets {
+		if userID == "" {
+			continue
+		}
+
+		keyID, err := t.keyRotator.objectClient.CurrentKeyID(t.ctx, userID)
+		if errors.Is(err, encryption.ErrKeyRevoked) {
+			// the data of the tenant can't be read anymore.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if t.keyRotator.isRotated(t.name, userID, keyID) {
+			continue
+		}
+
+		if is.compactedIndex == nil && len(is.ListSourceFiles()) == 1 {
+			if err := t.openCompactedIndexForRetention(is); err != nil {
+				return nil, err
+			}
+		}
+		if is.compactedIndex == nil {
+			continue
+		}
+
+		rewrapped := 0
+		segments := map[uint64]struct{}{}
+		err = is.compactedIndex.ForEachChunk(t.ctx, func(ce retention.ChunkEntry) (bool, error) {
+			var (
+				key string
+				err error
+			)
+			if ce.SegmentLength > 0 {
+				// segments hold many chunks, so they only need to be rewrapped once.
+				if _, ok := segments[ce.SegmentID]; ok {
+					return false, nil
+				}
+				segments[ce.SegmentID] = struct{}{}
+				key = client.SegmentKey(userID, ce.SegmentID)
+			} else {
+				key, err = t.keyRotator.chunkKey(userID, string(ce.ChunkID))
+				if err != nil {
+					return false, err
+				}
+			}
+
+			ok, err := t.keyRotator.objectClient.Rewrap(t.ctx, userID, key)
+			if err != nil {
+				if t.keyRotator.objectClient.IsObjectNotFoundErr(err) {
+					return false, nil
+				}
+				return false, fmt.Errorf("failed to rotate encryption key of chunk %s: %w", key, err)
+			}
+			if ok {
+				rewrapped++
+			}
+			return false, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// upload the index again to encrypt it with the current key and remove the source files encrypted with previous keys.
+		is.uploadCompactedDB = true
+		is.removeSourceObjects = true
+		rotatedKeys[userID] = keyID
+
+		level.Info(is.logger).Log("msg", "rotated encryption key", "key_id", keyID, "rewrapped_chunks", rewrapped)
+	}
+
+	return rotatedKeys, nil
+}
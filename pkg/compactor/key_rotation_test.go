@@ -0,0 +1,109 @@
+package compactor
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/storage/chunk/client/encryption"
+	"github.com/grafana/loki/v3/pkg/storage/chunk/client/local"
+	"github.com/grafana/loki/v3/pkg/storage/config"
+	"github.com/grafana/loki/v3/pkg/storage/stores/shipper/indexshipper/storage"
+)
+
+func TestTable_CompactionKeyRotation(t *testing.T) {
+	tempDir := t.TempDir()
+	objectStoragePath := filepath.Join(tempDir, objectsStorageDirName)
+	tablePathInStorage := filepath.Join(objectStoragePath, tableName)
+	tableWorkingDirectory := filepath.Join(tempDir, workingDirName, tableName)
+
+	SetupTable(t, tablePathInStorage, IndexesConfig{}, PerUserIndexesConfig{
+		IndexesConfig: IndexesConfig{NumCompactedFiles: 1},
+		NumUsers:      2,
+	})
+
+	newKey := func() string {
+		key := make([]byte, 32)
+		_, err := rand.Read(key)
+		require.NoError(t, err)
+		return base64.StdEncoding.EncodeToString(key)
+	}
+	keys := map[string]string{"d1": newKey(), "k1": newKey(), "k2": newKey()}
+
+	keyringPath := filepath.Join(tempDir, "keyring.yaml")
+	writeKeyring := func(user0Keys []string, modTime time.Time) {
+		content := fmt.Sprintf("default:\n  - id: d1\n    key: %s\ntenants:\n  user-0:\n", keys["d1"])
+		for _, id := range user0Keys {
+			content += fmt.Sprintf("    - id: %s\n      key: %s\n", id, keys[id])
+		}
+		require.NoError(t, os.WriteFile(keyringPath, []byte(content), 0o600))
+		require.NoError(t, os.Chtimes(keyringPath, modTime, modTime))
+	}
+	writeKeyring([]string{"k1"}, time.Now().Add(-time.Hour))
+
+	keyring, err := encryption.NewKeyring(encryption.KeyringConfig{Path: keyringPath})
+	require.NoError(t, err)
+
+	fsObjectClient, err := local.NewFSObjectClient(local.FSConfig{Directory: objectStoragePath})
+	require.NoError(t, err)
+	objectClient := encryption.NewObjectClient(fsObjectClient, keyring)
+	rotator := newKeyRotator(objectClient, nil, config.SchemaConfig{})
+
+	compact := func() {
+		table, err := newTable(context.Background(), tableWorkingDirectory, storage.NewIndexStorageClient(objectClient, ""),
+			newTestIndexCompactor(), config.PeriodConfig{}, nil, nil, 10)
+		require.NoError(t, err)
+		table.keyRotator = rotator
+
+		require.NoError(t, table.compact(false))
+	}
+
+	// indexFile returns the name of the only index file of the user and its content in the storage.
+	indexFile := func(userID string) (string, []byte) {
+		files, _ := listDir(t, filepath.Join(tablePathInStorage, userID))
+		require.Len(t, files, 1)
+
+		content, err := os.ReadFile(filepath.Join(tablePathInStorage, userID, files[0]))
+		require.NoError(t, err)
+		return files[0], content
+	}
+
+	// the plaintext index files get encrypted with the keys of their tenants.
+	compact()
+	user0File, content := indexFile("user-0")
+	require.Contains(t, string(content), "tenant/user-0/k1")
+	user1File, content := indexFile("user-1")
+	require.Contains(t, string(content), "default/d1")
+
+	// index sets already rotated to the current keys are left untouched.
+	compact()
+	name, _ := indexFile("user-0")
+	require.Equal(t, user0File, name)
+	name, _ = indexFile("user-1")
+	require.Equal(t, user1File, name)
+
+	// the test compacted index names files after the current second, rename the file to make sure the new one doesn't overwrite it.
+	require.NoError(t, os.Rename(filepath.Join(tablePathInStorage, "user-0", user0File), filepath.Join(tablePathInStorage, "user-0", "compactor-0.gz")))
+
+	// rotate the key of user-0.
+	writeKeyring([]string{"k1", "k2"}, time.Now())
+	compact()
+	_, content = indexFile("user-0")
+	require.Contains(t, string(content), "tenant/user-0/k2")
+	name, _ = indexFile("user-1")
+	require.Equal(t, user1File, name)
+
+	// the previous key can now be destroyed.
+	writeKeyring([]string{"k2"}, time.Now().Add(time.Hour))
+	name, _ = indexFile("user-0")
+	rc, _, err := objectClient.GetObject(context.Background(), filepath.Join(tableName, "user-0", name))
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+}
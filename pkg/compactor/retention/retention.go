@@ -36,6 +36,11 @@ type ChunkRef struct {
 	ChunkID  []byte
 	From     model.Time
 	Through  model.Time
+
+	// The segment the chunk is stored in, if any. See logproto.ChunkRef.
+	SegmentID     uint64
+	SegmentOffset uint64
+	SegmentLength uint32
 }
 
 func (c ChunkRef) String() string {
@@ -356,6 +361,9 @@ func (c *chunkRewriter) rewriteChunk(ctx context.Context, ce ChunkEntry, tableIn
 	if err != nil {
 		return false, false, err
 	}
+	chk.SegmentID = ce.SegmentID
+	chk.SegmentOffset = ce.SegmentOffset
+	chk.SegmentLength = ce.SegmentLength
 
 	chks, err := c.chunkClient.GetChunks(ctx, []chunk.Chunk{chk})
 	if err != nil {
@@ -83,6 +83,9 @@ type table struct {
 	usersWithPerUserIndex []string
 	logger                log.Logger
 
+	// keyRotator is set when encryption keys of tenants have to be rotated during compaction.
+	keyRotator *keyRotator
+
 	ctx context.Context
 }
 
@@ -181,7 +184,20 @@ func (t *table) compact(applyRetention bool) error {
 		}
 	}
 
-	return t.done()
+	var rotatedKeys map[string]string
+	if t.keyRotator != nil {
+		rotatedKeys, err = t.rotateKeys()
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := t.done(); err != nil {
+		return err
+	}
+
+	t.keyRotator.markRotated(t.name, rotatedKeys)
+	return nil
 }
 
 func (t *table) done() error {
@@ -10,13 +10,11 @@ import (
 	"strconv"
 	"strings"
 	"time"
-	"unicode"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/gogo/status"
 	"github.com/prometheus/prometheus/model/labels"
-	"go.opentelemetry.io/collector/pdata/plog"
 	"google.golang.org/grpc/codes"
 
 	"github.com/grafana/dskit/httpgrpc"
@@ -37,6 +35,8 @@ import (
 	"github.com/grafana/loki/v3/pkg/analytics"
 	"github.com/grafana/loki/v3/pkg/compactor/retention"
 	"github.com/grafana/loki/v3/pkg/distributor/clientpool"
+	"github.com/grafana/loki/v3/pkg/distributor/labelpolicy"
+	"github.com/grafana/loki/v3/pkg/distributor/severity"
 	"github.com/grafana/loki/v3/pkg/distributor/shardstreams"
 	"github.com/grafana/loki/v3/pkg/distributor/writefailures"
 	"github.com/grafana/loki/v3/pkg/ingester"
@@ -87,6 +87,9 @@ type Config struct {
 	// WriteFailuresLoggingCfg customizes write failures logging behavior.
 	WriteFailuresLogging writefailures.Cfg `yaml:"write_failures_logging" doc:"description=Customize the logging of write failures."`
 
+	// RejectionsStream customizes the stream the write failures of the tenants are written to.
+	RejectionsStream writefailures.StreamCfg `yaml:"rejections_stream" doc:"description=Customize the stream the write failures of the tenants with validation.rejections-stream-enabled are written to."`
+
 	OTLPConfig push.GlobalOTLPConfig `yaml:"otlp_config"`
 }
 
@@ -96,11 +99,13 @@ func (cfg *Config) RegisterFlags(fs *flag.FlagSet) {
 	cfg.DistributorRing.RegisterFlags(fs)
 	cfg.RateStore.RegisterFlagsWithPrefix("distributor.rate-store", fs)
 	cfg.WriteFailuresLogging.RegisterFlagsWithPrefix("distributor.write-failures-logging", fs)
+	cfg.RejectionsStream.RegisterFlagsWithPrefix("distributor.rejections-stream", fs)
 }
 
 // RateStore manages the ingestion rate of streams, populated by data fetched from ingesters.
 type RateStore interface {
 	RateFor(tenantID string, streamHash uint64) (int64, float64)
+	AdaptiveShardsFor(tenantID string, streamHash uint64) int
 }
 
 // Distributor coordinates replicates and distribution of log streams.
@@ -136,6 +141,8 @@ type Distributor struct {
 
 	// Push failures rate limiter.
 	writeFailuresManager *writefailures.Manager
+	// Writes a sample of the push failures to the rejections stream of the tenants.
+	rejectionsWriter *writefailures.StreamWriter
 
 	RequestParserWrapper push.RequestParserWrapper
 
@@ -144,6 +151,7 @@ type Distributor struct {
 	ingesterAppendTimeouts *prometheus.CounterVec
 	replicationFactor      prometheus.Gauge
 	streamShardCount       prometheus.Counter
+	labelPolicyRulesFired  *prometheus.CounterVec
 
 	usageTracker push.UsageTracker
 }
@@ -227,6 +235,11 @@ func New(
 			Name:      "stream_sharding_count",
 			Help:      "Total number of times the distributor has sharded streams",
 		}),
+		labelPolicyRulesFired: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Name:      "distributor_label_policy_rules_fired_total",
+			Help:      "The total number of streams whose labels were changed or rejected by a rule of the label policy of the tenant.",
+		}, []string{"tenant", "rule"}),
 		writeFailuresManager: writefailures.NewManager(logger, registerer, cfg.WriteFailuresLogging, configs, "distributor"),
 	}
 
@@ -268,7 +281,12 @@ func New(
 	)
 	d.rateStore = rs
 
-	servs = append(servs, d.pool, rs)
+	d.rejectionsWriter, err = writefailures.NewStreamWriter(cfg.RejectionsStream, overrides, d, logger, registerer)
+	if err != nil {
+		return nil, err
+	}
+
+	servs = append(servs, d.pool, rs, d.rejectionsWriter)
 	d.subservices, err = services.NewManager(servs...)
 	if err != nil {
 		return nil, errors.Wrap(err, "services manager")
@@ -341,6 +359,14 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 
 	var validationErrors util.GroupedErrors
 	validationContext := d.validator.getValidationContextForTime(time.Now(), tenantID)
+	// the rejections stream keeps its labels, it must not be dropped or relabeled by the label policy of the tenant.
+	if writefailures.IsRejectionsPush(ctx) {
+		validationContext.labelPolicy = nil
+	}
+
+	// The labels of the validated streams are kept for the usage tracker, so that a rate limited request doesn't
+	// parse them and apply the label policy twice.
+	var trackedStreams []trackedStream
 
 	func() {
 		sp := opentracing.SpanFromContext(ctx)
@@ -359,10 +385,16 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 			// Truncate first so subsequent steps have consistent line lengths
 			d.truncateLines(validationContext, &stream)
 
-			var lbs labels.Labels
-			lbs, stream.Labels, stream.Hash, err = d.parseStreamLabels(validationContext, stream.Labels, stream)
+			var lbs, policyMetadata labels.Labels
+			lbs, stream.Labels, stream.Hash, policyMetadata, err = d.parseStreamLabels(validationContext, stream.Labels, stream)
 			if err != nil {
 				d.writeFailuresManager.Log(tenantID, err)
+				d.rejectionsWriter.Write(ctx, tenantID, writefailures.Failure{
+					Reason: rejectionReason(err),
+					Err:    err,
+					Stream: stream.Labels,
+					Entry:  &stream.Entries[0],
+				})
 				validationErrors.Add(err)
 				validation.DiscardedSamples.WithLabelValues(validation.InvalidLabels, tenantID).Add(float64(len(stream.Entries)))
 				bytes := 0
@@ -376,16 +408,38 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 			n := 0
 			pushSize := 0
 			prevTs := stream.Entries[0].Timestamp
-			addLogLevel := validationContext.allowStructuredMetadata && validationContext.discoverLogLevels && !lbs.Has(labelLevel)
+			severityCfg := validationContext.severityNormalization
+			normalizeLogLevel := severityCfg != nil && severityCfg.Enabled && !lbs.Has(labelLevel)
+			indexLogLevel := normalizeLogLevel && severityCfg.IndexLabel
+			addLogLevel := !normalizeLogLevel && validationContext.allowStructuredMetadata && validationContext.discoverLogLevels && !lbs.Has(labelLevel)
+			var logLevels []string
 			for _, entry := range stream.Entries {
+				if len(policyMetadata) > 0 {
+					entry.StructuredMetadata = append(entry.StructuredMetadata, logproto.FromLabelsToLabelAdapters(policyMetadata)...)
+				}
+
 				if err := d.validator.ValidateEntry(ctx, validationContext, lbs, entry); err != nil {
 					d.writeFailuresManager.Log(tenantID, err)
+					d.rejectionsWriter.Write(ctx, tenantID, writefailures.Failure{
+						Reason: rejectionReason(err),
+						Err:    err,
+						Stream: stream.Labels,
+						Entry:  &entry,
+					})
 					validationErrors.Add(err)
 					continue
 				}
 
 				structuredMetadata := logproto.FromLabelAdaptersToLabels(entry.StructuredMetadata)
-				if addLogLevel && !structuredMetadata.Has(labelLevel) {
+				if normalizeLogLevel {
+					logLevel := severityCfg.Detect(entry.Line, structuredMetadata)
+					if validationContext.allowStructuredMetadata {
+						entry.StructuredMetadata = setLogLevel(entry.StructuredMetadata, logLevel)
+					}
+					if indexLogLevel {
+						logLevels = append(logLevels, logLevel)
+					}
+				} else if addLogLevel && !structuredMetadata.Has(labelLevel) {
 					logLevel := detectLogLevelFromLogEntry(entry, structuredMetadata)
 					entry.StructuredMetadata = append(entry.StructuredMetadata, logproto.LabelAdapter{
 						Name:  labelLevel,
@@ -415,15 +469,31 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 				pushSize += len(entry.Line)
 			}
 			stream.Entries = stream.Entries[:n]
+			if d.usageTracker != nil {
+				trackedStreams = append(trackedStreams, trackedStream{lbs: lbs, size: pushSize})
+			}
+
+			levelStreams := []logproto.Stream{stream}
+			if indexLogLevel {
+				levelStreams = splitStreamByLogLevel(lbs, stream, logLevels)
+			}
 
 			shardStreamsCfg := d.validator.Limits.ShardStreams(tenantID)
-			if shardStreamsCfg.Enabled {
-				streams = append(streams, d.shardStream(stream, pushSize, tenantID)...)
-			} else {
-				streams = append(streams, KeyedStream{
-					HashKey: lokiring.TokenFor(tenantID, stream.Labels),
-					Stream:  stream,
-				})
+			for _, levelStream := range levelStreams {
+				if shardStreamsCfg.Enabled {
+					if indexLogLevel {
+						pushSize = 0
+						for _, e := range levelStream.Entries {
+							pushSize += len(e.Line)
+						}
+					}
+					streams = append(streams, d.shardStream(levelStream, pushSize, tenantID)...)
+				} else {
+					streams = append(streams, KeyedStream{
+						HashKey: lokiring.TokenFor(tenantID, levelStream.Labels),
+						Stream:  levelStream,
+					})
+				}
 			}
 		}
 	}()
@@ -439,31 +509,31 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 	}
 
 	now := time.Now()
-	if !d.ingestionRateLimiter.AllowN(now, tenantID, validatedLineSize) {
+	// the rejections stream is limited by its own rate, so that it's still written when the tenant is rate limited.
+	if !writefailures.IsRejectionsPush(ctx) && !d.ingestionRateLimiter.AllowN(now, tenantID, validatedLineSize) {
 		// Return a 429 to indicate to the client they are being rate limited
 		validation.DiscardedSamples.WithLabelValues(validation.RateLimited, tenantID).Add(float64(validatedLineCount))
 		validation.DiscardedBytes.WithLabelValues(validation.RateLimited, tenantID).Add(float64(validatedLineSize))
 
 		if d.usageTracker != nil {
-			for _, stream := range req.Streams {
-				lbs, _, _, err := d.parseStreamLabels(validationContext, stream.Labels, stream)
-				if err != nil {
-					continue
-				}
-
-				discardedStreamBytes := 0
-				for _, e := range stream.Entries {
-					discardedStreamBytes += len(e.Line)
-				}
-
-				if d.usageTracker != nil {
-					d.usageTracker.DiscardedBytesAdd(ctx, tenantID, validation.RateLimited, lbs, float64(discardedStreamBytes))
-				}
+			for _, stream := range trackedStreams {
+				d.usageTracker.DiscardedBytesAdd(ctx, tenantID, validation.RateLimited, stream.lbs, float64(stream.size))
 			}
 		}
 
 		err = fmt.Errorf(validation.RateLimitedErrorMsg, tenantID, int(d.ingestionRateLimiter.Limit(now, tenantID)), validatedLineCount, validatedLineSize)
 		d.writeFailuresManager.Log(tenantID, err)
+		for _, stream := range streams {
+			if len(stream.Stream.Entries) == 0 {
+				continue
+			}
+			d.rejectionsWriter.Write(ctx, tenantID, writefailures.Failure{
+				Reason: validation.RateLimited,
+				Err:    err,
+				Stream: stream.Stream.Labels,
+				Entry:  &stream.Stream.Entries[0],
+			})
+		}
 		return nil, httpgrpc.Errorf(http.StatusTooManyRequests, err.Error())
 	}
 
@@ -524,11 +594,21 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 			if sp := opentracing.SpanFromContext(ctx); sp != nil {
 				localCtx = opentracing.ContextWithSpan(localCtx, sp)
 			}
+			if writefailures.IsRejectionsPush(ctx) {
+				localCtx = writefailures.InjectRejectionsPushIntoGRPCRequest(localCtx)
+			}
 			d.sendStreams(localCtx, ingester, samples, &tracker)
 		}(ingesterDescs[ingester], streams)
 	}
 	select {
 	case err := <-tracker.err:
+		if resp, ok := httpgrpc.HTTPResponseFromError(err); ok && resp.Code/100 == 4 {
+			// The ingesters rejected the push, e.g. because of the stream limit of the tenant.
+			d.rejectionsWriter.Write(ctx, tenantID, writefailures.Failure{
+				Reason: writefailures.IngesterRejected,
+				Err:    errors.New(string(resp.Body)),
+			})
+		}
 		return nil, err
 	case <-tracker.done:
 		return &logproto.PushResponse{}, validationErr
@@ -733,24 +813,64 @@ func (d *Distributor) sendStreamsErr(ctx context.Context, ingester ring.Instance
 	return err
 }
 
+// trackedStream holds the labels and the size of the validated entries of a stream for the usage tracker.
+type trackedStream struct {
+	lbs  labels.Labels
+	size int
+}
+
 type labelData struct {
-	ls   labels.Labels
-	hash uint64
+	ls                 labels.Labels
+	hash               uint64
+	structuredMetadata labels.Labels
+	fired              []string
 }
 
-func (d *Distributor) parseStreamLabels(vContext validationContext, key string, stream logproto.Stream) (labels.Labels, string, uint64, error) {
-	if val, ok := d.labelCache.Get(key); ok {
+// labelCacheKey keys the label cache by the label policy of the tenant and the labels of the stream. The policy
+// is identified by its first rule: the overrides of a tenant build a new policy when the runtime config changes,
+// and the rule can't be reused by another policy while the cache references it.
+type labelCacheKey struct {
+	policy *labelpolicy.Rule
+	labels string
+}
+
+// parseStreamLabels parses, applies the label policy to and validates the labels of a stream. It returns the labels
+// the label policy moved to the structured metadata of the entries along with the labels of the stream.
+func (d *Distributor) parseStreamLabels(vContext validationContext, key string, stream logproto.Stream) (labels.Labels, string, uint64, labels.Labels, error) {
+	cacheKey := labelCacheKey{labels: key}
+	if len(vContext.labelPolicy) > 0 {
+		cacheKey.policy = vContext.labelPolicy[0]
+	}
+	if val, ok := d.labelCache.Get(cacheKey); ok {
 		labelVal := val.(labelData)
-		return labelVal.ls, labelVal.ls.String(), labelVal.hash, nil
+		for _, rule := range labelVal.fired {
+			d.labelPolicyRulesFired.WithLabelValues(vContext.userID, rule).Inc()
+		}
+		return labelVal.ls, labelVal.ls.String(), labelVal.hash, labelVal.structuredMetadata, nil
 	}
 
 	ls, err := syntax.ParseLabels(key)
 	if err != nil {
-		return nil, "", 0, fmt.Errorf(validation.InvalidLabelsErrorMsg, key, err)
+		return nil, "", 0, nil, newValidationError(validation.InvalidLabels, fmt.Errorf(validation.InvalidLabelsErrorMsg, key, err))
+	}
+
+	var (
+		structuredMetadata labels.Labels
+		fired              []string
+	)
+	if len(vContext.labelPolicy) > 0 {
+		res, err := d.validator.ApplyLabelPolicy(vContext, ls, stream)
+		for _, rule := range res.Fired {
+			d.labelPolicyRulesFired.WithLabelValues(vContext.userID, rule).Inc()
+		}
+		if err != nil {
+			return nil, "", 0, nil, err
+		}
+		ls, structuredMetadata, fired = res.Labels, res.StructuredMetadata, res.Fired
 	}
 
 	if err := d.validator.ValidateLabels(vContext, ls, stream); err != nil {
-		return nil, "", 0, err
+		return nil, "", 0, nil, err
 	}
 
 	// We do not want to count service_name added by us in the stream limit so adding it after validating original labels.
@@ -769,8 +889,8 @@ func (d *Distributor) parseStreamLabels(vContext validationContext, key string,
 
 	lsHash := ls.Hash()
 
-	d.labelCache.Add(key, labelData{ls, lsHash})
-	return ls, ls.String(), lsHash, nil
+	d.labelCache.Add(cacheKey, labelData{ls, lsHash, structuredMetadata, fired})
+	return ls, ls.String(), lsHash, structuredMetadata, nil
 }
 
 // shardCountFor returns the right number of shards to be used by the given stream.
@@ -779,6 +899,9 @@ func (d *Distributor) parseStreamLabels(vContext validationContext, key string,
 // based on the rate stored in the rate store and will store the new evaluated number of shards.
 //
 // desiredRate is expected to be given in bytes.
+//
+// With adaptive sharding enabled, the stream gets at least as many shards as the
+// rate store asks for because ingesters rate limited the stream.
 func (d *Distributor) shardCountFor(logger log.Logger, stream *logproto.Stream, pushSize int, tenantID string, streamShardcfg *shardstreams.Config) int {
 	if streamShardcfg.DesiredRate.Val() <= 0 {
 		if streamShardcfg.LoggingEnabled {
@@ -801,6 +924,16 @@ func (d *Distributor) shardCountFor(logger log.Logger, stream *logproto.Stream,
 	}
 
 	shards := calculateShards(rate, int(float64(pushSize)*pushRate), streamShardcfg.DesiredRate.Val())
+	if streamShardcfg.AdaptiveEnabled {
+		adaptive := d.rateStore.AdaptiveShardsFor(tenantID, stream.Hash)
+		if adaptive > shards {
+			if streamShardcfg.LoggingEnabled {
+				level.Info(logger).Log("msg", "sharding rate limited stream", "shard_count", adaptive, "desired_rate_shard_count", shards)
+			}
+			shards = adaptive
+		}
+	}
+
 	if shards == 0 {
 		// 1 shard is enough for the given stream.
 		return 1
@@ -865,16 +998,8 @@ func detectLogLevelFromLogEntry(entry logproto.Entry, structuredMetadata labels.
 		if err != nil {
 			return logLevelInfo
 		}
-		if otlpSeverityNumber <= int(plog.SeverityNumberDebug4) {
-			return logLevelDebug
-		} else if otlpSeverityNumber <= int(plog.SeverityNumberInfo4) {
-			return logLevelInfo
-		} else if otlpSeverityNumber <= int(plog.SeverityNumberWarn4) {
-			return logLevelWarn
-		} else if otlpSeverityNumber <= int(plog.SeverityNumberError4) {
-			return logLevelError
-		} else if otlpSeverityNumber <= int(plog.SeverityNumberFatal4) {
-			return logLevelFatal
+		if logLevel, ok := severity.FromOTLPSeverityNumber(otlpSeverityNumber); ok {
+			return logLevel
 		}
 		return logLevelInfo
 	}
@@ -882,81 +1007,39 @@ func detectLogLevelFromLogEntry(entry logproto.Entry, structuredMetadata labels.
 	return extractLogLevelFromLogLine(entry.Line)
 }
 
-func extractLogLevelFromLogLine(log string) string {
-	// check for log levels in known log formats to avoid any false detection
-
-	// json logs:
-	var firstNonSpaceChar rune
-	for _, char := range log {
-		if !unicode.IsSpace(char) {
-			firstNonSpaceChar = char
-			break
-		}
-	}
-
-	var lastNonSpaceChar rune
-	for i := len(log) - 1; i >= 0; i-- {
-		char := rune(log[i])
-		if !unicode.IsSpace(char) {
-			lastNonSpaceChar = char
-			break
-		}
-	}
-
-	if firstNonSpaceChar == '{' && lastNonSpaceChar == '}' {
-		if strings.Contains(log, `:"err"`) || strings.Contains(log, `:"ERR"`) ||
-			strings.Contains(log, `:"error"`) || strings.Contains(log, `:"ERROR"`) {
-			return logLevelError
-		}
-		if strings.Contains(log, `:"warn"`) || strings.Contains(log, `:"WARN"`) ||
-			strings.Contains(log, `:"warning"`) || strings.Contains(log, `:"WARNING"`) {
-			return logLevelWarn
-		}
-		if strings.Contains(log, `:"critical"`) || strings.Contains(log, `:"CRITICAL"`) {
-			return logLevelCritical
-		}
-		if strings.Contains(log, `:"debug"`) || strings.Contains(log, `:"DEBUG"`) {
-			return logLevelDebug
-		}
-		if strings.Contains(log, `:"info"`) || strings.Contains(log, `:"INFO"`) {
-			return logLevelDebug
+// setLogLevel sets the level in the structured metadata of an entry, replacing the existing one.
+func setLogLevel(structuredMetadata []logproto.LabelAdapter, logLevel string) []logproto.LabelAdapter {
+	for i := range structuredMetadata {
+		if structuredMetadata[i].Name == labelLevel {
+			structuredMetadata[i].Value = logLevel
+			return structuredMetadata
 		}
 	}
+	return append(structuredMetadata, logproto.LabelAdapter{Name: labelLevel, Value: logLevel})
+}
 
-	// logfmt logs:
-	if strings.Contains(log, "=") {
-		if strings.Contains(log, "=err") || strings.Contains(log, "=ERR") ||
-			strings.Contains(log, "=error") || strings.Contains(log, "=ERROR") {
-			return logLevelError
-		}
-		if strings.Contains(log, "=warn") || strings.Contains(log, "=WARN") ||
-			strings.Contains(log, "=warning") || strings.Contains(log, "=WARNING") {
-			return logLevelWarn
-		}
-		if strings.Contains(log, "=critical") || strings.Contains(log, "=CRITICAL") {
-			return logLevelCritical
-		}
-		if strings.Contains(log, "=debug") || strings.Contains(log, "=DEBUG") {
-			return logLevelDebug
-		}
-		if strings.Contains(log, "=info") || strings.Contains(log, "=INFO") {
-			return logLevelDebug
+// splitStreamByLogLevel splits the entries of a stream into a stream per level, with the level as an indexed label.
+func splitStreamByLogLevel(lbs labels.Labels, stream logproto.Stream, logLevels []string) []logproto.Stream {
+	var (
+		streams = make([]logproto.Stream, 0, 1)
+		byLevel = make(map[string]int, 1)
+	)
+	for i, entry := range stream.Entries {
+		idx, ok := byLevel[logLevels[i]]
+		if !ok {
+			ls := labels.NewBuilder(lbs).Set(labelLevel, logLevels[i]).Labels()
+			idx = len(streams)
+			byLevel[logLevels[i]] = idx
+			streams = append(streams, logproto.Stream{Labels: ls.String(), Hash: ls.Hash()})
 		}
+		streams[idx].Entries = append(streams[idx].Entries, entry)
 	}
+	return streams
+}
 
-	if strings.Contains(log, "err:") || strings.Contains(log, "ERR:") ||
-		strings.Contains(log, "error") || strings.Contains(log, "ERROR") {
-		return logLevelError
-	}
-	if strings.Contains(log, "warn:") || strings.Contains(log, "WARN:") ||
-		strings.Contains(log, "warning") || strings.Contains(log, "WARNING") {
-		return logLevelWarn
-	}
-	if strings.Contains(log, "CRITICAL:") || strings.Contains(log, "critical:") {
-		return logLevelCritical
-	}
-	if strings.Contains(log, "debug:") || strings.Contains(log, "DEBUG:") {
-		return logLevelDebug
+func extractLogLevelFromLogLine(log string) string {
+	if logLevel, ok := severity.FromLine(log); ok {
+		return logLevel
 	}
 
 	// Default to info if no specific level is found
@@ -22,6 +22,7 @@ import (
 	"github.com/grafana/dskit/services"
 	"github.com/grafana/dskit/user"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/assert"
@@ -29,9 +30,13 @@ import (
 	"go.opentelemetry.io/collector/pdata/plog"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"gopkg.in/yaml.v2"
 
 	"github.com/grafana/loki/pkg/push"
 
+	"github.com/grafana/loki/v3/pkg/distributor/labelpolicy"
+	"github.com/grafana/loki/v3/pkg/distributor/writefailures"
 	"github.com/grafana/loki/v3/pkg/ingester"
 	"github.com/grafana/loki/v3/pkg/ingester/client"
 	loghttp_push "github.com/grafana/loki/v3/pkg/loghttp/push"
@@ -589,6 +594,214 @@ func Test_TruncateLogLines(t *testing.T) {
 	})
 }
 
+func Test_RejectionsStream(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.RejectionsStreamEnabled = true
+
+	ingester := &mockIngester{}
+	distributors, _ := prepare(t, 1, 5, limits, func(addr string) (ring_client.PoolClient, error) { return ingester, nil })
+
+	request := makeWriteRequest(1, 10)
+	request.Streams[0].Entries[0].Timestamp = time.Now().Add(-30 * 24 * time.Hour)
+	_, err := distributors[0].Push(ctx, request)
+	require.Error(t, err)
+
+	// the rejected entry is pushed to the rejections stream of the tenant.
+	var rejections *logproto.Stream
+	require.Eventually(t, func() bool {
+		ingester.mu.Lock()
+		defer ingester.mu.Unlock()
+		for _, req := range ingester.pushed {
+			for _, stream := range req.Streams {
+				if stream.Labels == `{__loki_rejections__="true", reason="greater_than_max_sample_age", service_name="unknown_service"}` {
+					rejections = &stream
+					return true
+				}
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Len(t, rejections.Entries, 1)
+	require.Contains(t, rejections.Entries[0].Line, `line=0000000000`)
+}
+
+func Test_RejectionsStreamIsNotRateLimited(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.RejectionsStreamEnabled = true
+	limits.IngestionRateMB = 1e-6
+	limits.IngestionBurstSizeMB = 1e-6
+
+	ingester := &mockIngester{}
+	distributors, _ := prepare(t, 1, 5, limits, func(addr string) (ring_client.PoolClient, error) { return ingester, nil })
+
+	_, err := distributors[0].Push(ctx, makeWriteRequest(1, 10))
+	require.Error(t, err)
+
+	// the rate limited entry is pushed to the rejections stream, even though the tenant is still rate limited.
+	require.Eventually(t, func() bool {
+		ingester.mu.Lock()
+		defer ingester.mu.Unlock()
+		for _, req := range ingester.pushed {
+			for _, stream := range req.Streams {
+				if stream.Labels == `{__loki_rejections__="true", reason="rate_limited", service_name="unknown_service"}` {
+					return true
+				}
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_RejectionsStreamIgnoresLabelPolicy(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.RejectionsStreamEnabled = true
+	require.NoError(t, yaml.Unmarshal([]byte(`
+- name: no-bar
+  action: drop
+  source_labels: [foo]
+  regex: bar
+- name: no-rejections
+  action: drop
+  source_labels: [__loki_rejections__]
+  regex: "true"
+`), &limits.LabelPolicy))
+
+	ingester := &mockIngester{}
+	distributors, _ := prepare(t, 1, 5, limits, func(addr string) (ring_client.PoolClient, error) { return ingester, nil })
+
+	_, err := distributors[0].Push(ctx, makeWriteRequestWithLabels(1, 10, []string{`{foo="bar"}`}))
+	require.Error(t, err)
+
+	// the stream rejected by the label policy is written to the rejections stream, which the policy doesn't apply to,
+	// and the ingesters are told it's a rejections push.
+	require.Eventually(t, func() bool {
+		ingester.mu.Lock()
+		defer ingester.mu.Unlock()
+		for _, req := range ingester.pushed {
+			for _, stream := range req.Streams {
+				if strings.Contains(stream.Labels, `__loki_rejections__="true"`) {
+					return ingester.rejectionsPushes > 0
+				}
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_LabelPolicy(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.DiscoverLogLevels = false
+	require.NoError(t, yaml.Unmarshal([]byte(`
+- name: request-id-to-metadata
+  action: structured_metadata
+  regex: request_id
+- name: no-debug-streams
+  action: drop
+  source_labels: [level]
+  regex: debug
+`), &limits.LabelPolicy))
+
+	ingester := &mockIngester{}
+	distributors, _ := prepare(t, 1, 5, limits, func(addr string) (ring_client.PoolClient, error) { return ingester, nil })
+
+	request := makeWriteRequestWithLabels(1, 10, []string{`{foo="bar", request_id="1"}`, `{foo="bar", level="debug"}`})
+	_, err := distributors[0].Push(ctx, request)
+	require.ErrorContains(t, err, `stream '{foo="bar", level="debug"}' was rejected by the label policy rule 'no-debug-streams'`)
+
+	ingester.mu.Lock()
+	defer ingester.mu.Unlock()
+	require.NotEmpty(t, ingester.pushed)
+	require.Len(t, ingester.pushed[0].Streams, 1)
+	stream := ingester.pushed[0].Streams[0]
+	require.Equal(t, `{foo="bar", service_name="unknown_service"}`, stream.Labels)
+	require.Equal(t, push.LabelsAdapter{{Name: "request_id", Value: "1"}}, stream.Entries[0].StructuredMetadata)
+
+	require.Equal(t, 1.0, testutil.ToFloat64(distributors[0].labelPolicyRulesFired.WithLabelValues("test", "request-id-to-metadata")))
+	require.Equal(t, 1.0, testutil.ToFloat64(distributors[0].labelPolicyRulesFired.WithLabelValues("test", "no-debug-streams")))
+
+	// the labels are cached along with the policy, the rules still fire for every push.
+	ingester.pushed = nil
+	ingester.mu.Unlock()
+	_, err = distributors[0].Push(ctx, makeWriteRequestWithLabels(1, 10, []string{`{foo="bar", request_id="1"}`}))
+	ingester.mu.Lock()
+	require.NoError(t, err)
+	require.NotEmpty(t, ingester.pushed)
+	require.Equal(t, push.LabelsAdapter{{Name: "request_id", Value: "1"}}, ingester.pushed[0].Streams[0].Entries[0].StructuredMetadata)
+	require.Equal(t, 2.0, testutil.ToFloat64(distributors[0].labelPolicyRulesFired.WithLabelValues("test", "request-id-to-metadata")))
+}
+
+func Test_LabelPolicyCacheKeyedByPolicy(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.DiscoverLogLevels = false
+
+	ingester := &mockIngester{}
+	distributors, _ := prepare(t, 1, 5, limits, func(addr string) (ring_client.PoolClient, error) { return ingester, nil })
+
+	_, err := distributors[0].Push(ctx, makeWriteRequestWithLabels(1, 10, []string{`{foo="bar", request_id="1"}`}))
+	require.NoError(t, err)
+
+	// the policy of the tenant changes at runtime.
+	policy := []*labelpolicy.Rule{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+- name: request-id-to-metadata
+  action: structured_metadata
+  regex: request_id
+`), &policy))
+	vContext := distributors[0].validator.getValidationContextForTime(time.Now(), "test")
+	vContext.labelPolicy = policy
+
+	ls, _, _, metadata, err := distributors[0].parseStreamLabels(vContext, `{foo="bar", request_id="1"}`, logproto.Stream{})
+	require.NoError(t, err)
+	require.Equal(t, `{foo="bar", service_name="unknown_service"}`, ls.String())
+	require.Equal(t, `{request_id="1"}`, metadata.String())
+}
+
+type mockUsageTracker struct {
+	discarded map[string]float64
+}
+
+func (m *mockUsageTracker) ReceivedBytesAdd(_ context.Context, _ string, _ time.Duration, _ labels.Labels, _ float64) {
+}
+
+func (m *mockUsageTracker) DiscardedBytesAdd(_ context.Context, _, reason string, lbs labels.Labels, value float64) {
+	m.discarded[reason+lbs.String()] += value
+}
+
+func Test_LabelPolicyRateLimitedWithUsageTracker(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.DiscoverLogLevels = false
+	limits.IngestionRateMB = 1e-6
+	limits.IngestionBurstSizeMB = 1e-6
+	require.NoError(t, yaml.Unmarshal([]byte(`
+- name: request-id-to-metadata
+  action: structured_metadata
+  regex: request_id
+- name: no-debug-streams
+  action: drop
+  source_labels: [level]
+  regex: debug
+`), &limits.LabelPolicy))
+
+	distributors, _ := prepare(t, 1, 5, limits, nil)
+	tracker := &mockUsageTracker{discarded: map[string]float64{}}
+	distributors[0].usageTracker = tracker
+
+	rejectedBefore := testutil.ToFloat64(validation.DiscardedSamples.WithLabelValues(validation.LabelPolicyRejected, "test"))
+	_, err := distributors[0].Push(ctx, makeWriteRequestWithLabels(1, 10, []string{`{foo="bar", request_id="1"}`, `{foo="bar", level="debug"}`}))
+	require.ErrorContains(t, err, "Ingestion rate limit exceeded")
+
+	require.Equal(t, 1.0, testutil.ToFloat64(distributors[0].labelPolicyRulesFired.WithLabelValues("test", "request-id-to-metadata")))
+	require.Equal(t, 1.0, testutil.ToFloat64(distributors[0].labelPolicyRulesFired.WithLabelValues("test", "no-debug-streams")))
+	require.Equal(t, rejectedBefore+1, testutil.ToFloat64(validation.DiscardedSamples.WithLabelValues(validation.LabelPolicyRejected, "test")))
+	require.Equal(t, map[string]float64{validation.RateLimited + `{foo="bar", service_name="unknown_service"}`: 10}, tracker.discarded)
+}
+
 func TestStreamShard(t *testing.T) {
 	// setup base stream.
 	baseStream := logproto.Stream{}
@@ -847,7 +1060,7 @@ func Benchmark_SortLabelsOnPush(b *testing.B) {
 	for n := 0; n < b.N; n++ {
 		stream := request.Streams[0]
 		stream.Labels = `{buzz="f", a="b"}`
-		_, _, _, err := d.parseStreamLabels(vCtx, stream.Labels, stream)
+		_, _, _, _, err := d.parseStreamLabels(vCtx, stream.Labels, stream)
 		if err != nil {
 			panic("parseStreamLabels fail,err:" + err.Error())
 		}
@@ -890,7 +1103,7 @@ func TestParseStreamLabels(t *testing.T) {
 				return limits
 			},
 			origLabels:  `{}`,
-			expectedErr: fmt.Errorf(validation.MissingLabelsErrorMsg),
+			expectedErr: newValidationError(validation.MissingLabels, fmt.Errorf(validation.MissingLabelsErrorMsg)),
 		},
 		{
 			name:       "service name label enabled",
@@ -962,7 +1175,7 @@ func TestParseStreamLabels(t *testing.T) {
 		vCtx := d.validator.getValidationContextForTime(testTime, "123")
 
 		t.Run(tc.name, func(t *testing.T) {
-			lbs, lbsString, hash, err := d.parseStreamLabels(vCtx, tc.origLabels, logproto.Stream{
+			lbs, lbsString, hash, _, err := d.parseStreamLabels(vCtx, tc.origLabels, logproto.Stream{
 				Labels: tc.origLabels,
 			})
 			if tc.expectedErr != nil {
@@ -1052,6 +1265,8 @@ func TestShardCountFor(t *testing.T) {
 		pushRate    float64
 		desiredRate loki_flagext.ByteSize
 
+		adaptiveShards int
+
 		pushSize   int // used for sanity check.
 		wantShards int
 		wantErr    bool
@@ -1151,14 +1366,37 @@ func TestShardCountFor(t *testing.T) {
 			wantShards:  6,
 			wantErr:     false,
 		},
+		{
+			name:           "a rate limited stream gets the shards the rate store asks for",
+			stream:         &logproto.Stream{Entries: []logproto.Entry{{Line: "a"}, {Line: "b"}}},
+			rate:           24, // in bytes
+			pushRate:       1,
+			desiredRate:    40, // in bytes
+			pushSize:       10, // in bytes
+			adaptiveShards: 4,
+			wantShards:     4,
+			wantErr:        false,
+		},
+		{
+			name:           "the desired rate wins if it needs more shards than the rate store asks for",
+			stream:         &logproto.Stream{Entries: []logproto.Entry{{Line: "a"}, {Line: "b"}}},
+			rate:           24, // in bytes
+			pushRate:       3,
+			desiredRate:    40,  // in bytes
+			pushSize:       200, // in bytes
+			adaptiveShards: 2,
+			wantShards:     6,
+			wantErr:        false,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			limits := &validation.Limits{}
 			flagext.DefaultValues(limits)
 			limits.ShardStreams.DesiredRate = tc.desiredRate
+			limits.ShardStreams.AdaptiveEnabled = true
 
 			d := &Distributor{
-				rateStore: &fakeRateStore{tc.rate, tc.pushRate},
+				rateStore: &fakeRateStore{rate: tc.rate, pushRate: tc.pushRate, adaptiveShards: tc.adaptiveShards},
 			}
 			got := d.shardCountFor(util_log.Logger, tc.stream, tc.pushSize, "fake", limits.ShardStreams)
 			require.Equal(t, tc.wantShards, got)
@@ -1397,13 +1635,14 @@ type mockIngester struct {
 	logproto.PusherClient
 	logproto.StreamDataClient
 
-	failAfter    time.Duration
-	succeedAfter time.Duration
-	mu           sync.Mutex
-	pushed       []*logproto.PushRequest
+	failAfter        time.Duration
+	succeedAfter     time.Duration
+	mu               sync.Mutex
+	pushed           []*logproto.PushRequest
+	rejectionsPushes int
 }
 
-func (i *mockIngester) Push(_ context.Context, in *logproto.PushRequest, _ ...grpc.CallOption) (*logproto.PushResponse, error) {
+func (i *mockIngester) Push(ctx context.Context, in *logproto.PushRequest, _ ...grpc.CallOption) (*logproto.PushResponse, error) {
 	if i.failAfter > 0 {
 		time.Sleep(i.failAfter)
 		return nil, fmt.Errorf("push request failed")
@@ -1416,6 +1655,9 @@ func (i *mockIngester) Push(_ context.Context, in *logproto.PushRequest, _ ...gr
 	defer i.mu.Unlock()
 
 	i.pushed = append(i.pushed, in)
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && writefailures.IsRejectionsPush(writefailures.ExtractRejectionsPushFromGRPCRequest(metadata.NewIncomingContext(ctx, md))) {
+		i.rejectionsPushes++
+	}
 	return nil, nil
 }
 
@@ -1439,14 +1681,19 @@ func (i *mockIngester) Close() error {
 }
 
 type fakeRateStore struct {
-	rate     int64
-	pushRate float64
+	rate           int64
+	pushRate       float64
+	adaptiveShards int
 }
 
 func (s *fakeRateStore) RateFor(_ string, _ uint64) (int64, float64) {
 	return s.rate, s.pushRate
 }
 
+func (s *fakeRateStore) AdaptiveShardsFor(_ string, _ uint64) int {
+	return s.adaptiveShards
+}
+
 type mockTee struct {
 	mu         sync.Mutex
 	duplicated [][]KeyedStream
@@ -1589,6 +1836,85 @@ func Test_DetectLogLevels(t *testing.T) {
 	})
 }
 
+func Test_SeverityNormalization(t *testing.T) {
+	setup := func(indexLabel bool) (*validation.Limits, *mockIngester) {
+		limits := &validation.Limits{}
+		flagext.DefaultValues(limits)
+
+		limits.DiscoverServiceName = nil
+		limits.AllowStructuredMetadata = true
+		limits.SeverityNormalization.Enabled = true
+		limits.SeverityNormalization.IndexLabel = indexLabel
+		return limits, &mockIngester{}
+	}
+
+	makeWriteReq := func(lines ...string) *logproto.PushRequest {
+		stream := logproto.Stream{Labels: `{foo="bar"}`}
+		for i, line := range lines {
+			stream.Entries = append(stream.Entries, logproto.Entry{
+				Timestamp: time.Now().Add(time.Duration(i) * time.Millisecond),
+				Line:      line,
+			})
+		}
+		return &logproto.PushRequest{Streams: []logproto.Stream{stream}}
+	}
+
+	t.Run("levels are normalized in structured metadata", func(t *testing.T) {
+		limits, ingester := setup(false)
+		distributors, _ := prepare(t, 1, 5, limits, func(addr string) (ring_client.PoolClient, error) { return ingester, nil })
+
+		writeReq := makeWriteReq(`{"severity":"WARNING","msg":"foo"}`, `ts=1 lvl=E msg=foo`, `<11>foo`, `foo`)
+		writeReq.Streams[0].Entries[3].StructuredMetadata = push.LabelsAdapter{{Name: labelLevel, Value: "Informational"}}
+		_, err := distributors[0].Push(ctx, writeReq)
+		require.NoError(t, err)
+
+		topVal := ingester.Peek()
+		require.Equal(t, `{foo="bar"}`, topVal.Streams[0].Labels)
+		var levels []string
+		for _, e := range topVal.Streams[0].Entries {
+			require.Len(t, e.StructuredMetadata, 1)
+			require.Equal(t, labelLevel, e.StructuredMetadata[0].Name)
+			levels = append(levels, e.StructuredMetadata[0].Value)
+		}
+		require.Equal(t, []string{"warn", "error", "error", "info"}, levels)
+	})
+
+	t.Run("levels are added as an indexed label", func(t *testing.T) {
+		limits, ingester := setup(true)
+		distributors, _ := prepare(t, 1, 5, limits, func(addr string) (ring_client.PoolClient, error) { return ingester, nil })
+
+		_, err := distributors[0].Push(ctx, makeWriteReq(`level=warn msg=foo`, `level=info msg=foo`, `level=W msg=bar`, `foo`))
+		require.NoError(t, err)
+
+		ingester.mu.Lock()
+		defer ingester.mu.Unlock()
+		entries := map[string]int{}
+		for _, req := range ingester.pushed {
+			for _, stream := range req.Streams {
+				entries[stream.Labels] = len(stream.Entries)
+			}
+		}
+		require.Equal(t, map[string]int{
+			`{foo="bar", level="info"}`:    1,
+			`{foo="bar", level="unknown"}`: 1,
+			`{foo="bar", level="warn"}`:    2,
+		}, entries)
+	})
+
+	t.Run("streams with a level label are left as is", func(t *testing.T) {
+		limits, ingester := setup(true)
+		distributors, _ := prepare(t, 1, 5, limits, func(addr string) (ring_client.PoolClient, error) { return ingester, nil })
+
+		writeReq := makeWriteReq(`level=warn msg=foo`)
+		writeReq.Streams[0].Labels = `{foo="bar", level="WARN"}`
+		_, err := distributors[0].Push(ctx, writeReq)
+		require.NoError(t, err)
+		topVal := ingester.Peek()
+		require.Equal(t, `{foo="bar", level="WARN"}`, topVal.Streams[0].Labels)
+		require.Len(t, topVal.Streams[0].Entries[0].StructuredMetadata, 0)
+	})
+}
+
 func Test_detectLogLevelFromLogEntry(t *testing.T) {
 	for _, tc := range []struct {
 		name             string
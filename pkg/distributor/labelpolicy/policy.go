@@ -0,0 +1,93 @@
+package labelpolicy
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+)
+
+// Result is the result of applying the rules of a label policy to the labels
+// of a stream.
+type Result struct {
+	Labels labels.Labels
+	// StructuredMetadata are the labels moved to the structured metadata of the
+	// entries of the stream.
+	StructuredMetadata labels.Labels
+	// Fired are the names of the rules which changed the labels or rejected
+	// the stream.
+	Fired []string
+	// RejectedBy is the name of the rule which rejected the stream, or empty if
+	// the stream isn't rejected.
+	RejectedBy string
+}
+
+// Apply applies the rules to the labels of a stream in order. It stops at the
+// first rule rejecting the stream.
+func Apply(rules []*Rule, ls labels.Labels) Result {
+	res := Result{Labels: ls}
+	if len(rules) == 0 {
+		return res
+	}
+
+	lb := labels.NewBuilder(ls)
+	for _, r := range rules {
+		changed := false
+		switch r.Action {
+		case StructuredMetadata:
+			var moved []string
+			lb.Range(func(l labels.Label) {
+				if r.regex.MatchString(l.Name) {
+					res.StructuredMetadata = append(res.StructuredMetadata, l)
+					moved = append(moved, l.Name)
+				}
+			})
+			lb.Del(moved...)
+			changed = len(moved) > 0
+		case Hash:
+			if v, ok := sourceValue(lb, r); ok {
+				hashed := strconv.FormatUint(xxhash.Sum64String(v), 16)
+				changed = lb.Get(r.TargetLabel) != hashed
+				lb.Set(r.TargetLabel, hashed)
+			}
+		default:
+			if r.relabel == nil {
+				continue
+			}
+			before := lb.Labels()
+			if !relabel.ProcessBuilder(lb, r.relabel) {
+				res.Fired = append(res.Fired, r.Name)
+				res.RejectedBy = r.Name
+				return res
+			}
+			changed = !labels.Equal(before, lb.Labels())
+		}
+
+		if changed {
+			res.Fired = append(res.Fired, r.Name)
+		}
+	}
+
+	res.Labels = lb.Labels()
+	return res
+}
+
+// sourceValue returns the concatenated values of the source labels of the
+// rule, and false if none of them is set.
+func sourceValue(lb *labels.Builder, r *Rule) (string, bool) {
+	separator := r.Separator
+	if separator == "" {
+		separator = defaultSeparator
+	}
+
+	values := make([]string, 0, len(r.SourceLabels))
+	set := false
+	for _, name := range r.SourceLabels {
+		v := lb.Get(name)
+		set = set || v != ""
+		values = append(values, v)
+	}
+	return strings.Join(values, separator), set
+}
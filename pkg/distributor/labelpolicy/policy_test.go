@@ -0,0 +1,162 @@
+package labelpolicy
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestApply(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		rules  string
+		labels labels.Labels
+		want   Result
+	}{
+		{
+			name:   "no rules",
+			labels: labels.FromStrings("app", "foo"),
+			want:   Result{Labels: labels.FromStrings("app", "foo")},
+		},
+		{
+			name: "drop a label",
+			rules: `
+- name: drop-pod
+  action: labeldrop
+  regex: pod`,
+			labels: labels.FromStrings("app", "foo", "pod", "foo-1"),
+			want:   Result{Labels: labels.FromStrings("app", "foo"), Fired: []string{"drop-pod"}},
+		},
+		{
+			name: "move labels to structured metadata",
+			rules: `
+- name: ids-to-metadata
+  action: structured_metadata
+  regex: (request|trace)_id`,
+			labels: labels.FromStrings("app", "foo", "request_id", "1", "trace_id", "2"),
+			want: Result{
+				Labels:             labels.FromStrings("app", "foo"),
+				StructuredMetadata: labels.FromStrings("request_id", "1", "trace_id", "2"),
+				Fired:              []string{"ids-to-metadata"},
+			},
+		},
+		{
+			name: "hash a label",
+			rules: `
+- name: hash-user
+  action: hash
+  source_labels: [user]
+  target_label: user`,
+			labels: labels.FromStrings("app", "foo", "user", "bob"),
+			want: Result{
+				Labels: labels.FromStrings("app", "foo", "user", strconv.FormatUint(xxhash.Sum64String("bob"), 16)),
+				Fired:  []string{"hash-user"},
+			},
+		},
+		{
+			name: "hash a missing label",
+			rules: `
+- name: hash-user
+  action: hash
+  source_labels: [user]
+  target_label: user`,
+			labels: labels.FromStrings("app", "foo"),
+			want:   Result{Labels: labels.FromStrings("app", "foo")},
+		},
+		{
+			name: "bucket a label",
+			rules: `
+- name: bucket-user
+  action: hashmod
+  source_labels: [user]
+  modulus: 1
+  target_label: user`,
+			labels: labels.FromStrings("app", "foo", "user", "bob"),
+			want:   Result{Labels: labels.FromStrings("app", "foo", "user", "0"), Fired: []string{"bucket-user"}},
+		},
+		{
+			name: "reject a stream",
+			rules: `
+- name: drop-pod
+  action: labeldrop
+  regex: pod
+- name: no-debug
+  action: drop
+  source_labels: [level]
+  regex: debug
+- name: never-applied
+  action: labeldrop
+  regex: app`,
+			labels: labels.FromStrings("app", "foo", "level", "debug", "pod", "foo-1"),
+			want:   Result{Labels: labels.FromStrings("app", "foo", "level", "debug", "pod", "foo-1"), Fired: []string{"drop-pod", "no-debug"}, RejectedBy: "no-debug"},
+		},
+		{
+			name: "rules which don't change the labels don't fire",
+			rules: `
+- name: no-debug
+  action: drop
+  source_labels: [level]
+  regex: debug
+- name: rename-service
+  source_labels: [service]
+  target_label: service_name`,
+			labels: labels.FromStrings("app", "foo", "level", "info"),
+			want:   Result{Labels: labels.FromStrings("app", "foo", "level", "info")},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var rules []*Rule
+			require.NoError(t, yaml.Unmarshal([]byte(tc.rules), &rules))
+			require.Equal(t, tc.want, Apply(rules, tc.labels))
+		})
+	}
+}
+
+func TestRuleValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		rule    string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			rule: `{name: drop-pod, action: labeldrop, regex: pod}`,
+		},
+		{
+			name:    "missing name",
+			rule:    `{action: labeldrop, regex: pod}`,
+			wantErr: errMissingName.Error(),
+		},
+		{
+			name:    "unknown action",
+			rule:    `{name: foo, action: bar}`,
+			wantErr: `rule foo: unknown relabel action "bar"`,
+		},
+		{
+			name:    "structured metadata without regex",
+			rule:    `{name: foo, action: structured_metadata}`,
+			wantErr: "rule foo: the regex must be set for the structured_metadata action",
+		},
+		{
+			name:    "hash without target label",
+			rule:    `{name: foo, action: hash, source_labels: [user]}`,
+			wantErr: "rule foo: the source labels and the target label must be set for the hash action",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var rule Rule
+			err := yaml.Unmarshal([]byte(tc.rule), &rule)
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package labelpolicy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/prometheus/model/relabel"
+	"gopkg.in/yaml.v2"
+)
+
+// Action is the action a rule performs on the labels of a stream. Besides the
+// Loki specific actions below, a rule can perform any of the Prometheus
+// relabeling actions.
+type Action string
+
+const (
+	// StructuredMetadata moves the labels whose name matches the regex to the
+	// structured metadata of the entries of the stream.
+	StructuredMetadata Action = "structured_metadata"
+	// Hash replaces the value of the target label with a hash of the
+	// concatenated values of the source labels.
+	Hash Action = "hash"
+
+	defaultSeparator = ";"
+)
+
+var errMissingName = errors.New("the name of the rule must be set")
+
+// Rule is a rule of the label policy of a tenant. Rules use the fields of the
+// Prometheus relabeling configs.
+type Rule struct {
+	Name         string   `yaml:"name" json:"name" doc:"description=Name of the rule. It is used in metrics and in the error returned for the streams rejected by the rule."`
+	Action       Action   `yaml:"action,omitempty" json:"action,omitempty" doc:"description=Action to perform. It allows the Prometheus relabeling actions, structured_metadata to move the labels whose name matches the regex to the structured metadata of the entries, and hash to replace the target label with a hash of the source labels. Streams dropped by the drop, keep, dropequal and keepequal actions are rejected."`
+	SourceLabels []string `yaml:"source_labels,flow,omitempty" json:"source_labels,omitempty" doc:"description=Labels whose values are concatenated with the separator and matched against the regex."`
+	Separator    string   `yaml:"separator,omitempty" json:"separator,omitempty" doc:"description=Separator placed between the concatenated values of the source labels. Defaults to ;."`
+	Regex        string   `yaml:"regex,omitempty" json:"regex,omitempty" doc:"description=Regex matched against the concatenated values of the source labels, or against the label names for the labeldrop, labelkeep, labelmap and structured_metadata actions."`
+	Modulus      uint64   `yaml:"modulus,omitempty" json:"modulus,omitempty" doc:"description=Modulus of the hash of the source labels for the hashmod action."`
+	TargetLabel  string   `yaml:"target_label,omitempty" json:"target_label,omitempty" doc:"description=Label the result of the replace, hashmod, lowercase, uppercase and hash actions is written to."`
+	Replacement  string   `yaml:"replacement,omitempty" json:"replacement,omitempty" doc:"description=Replacement written to the target label by the replace action. Regex capture groups are available."`
+
+	relabel *relabel.Config
+	regex   relabel.Regexp
+}
+
+func (r *Rule) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain Rule
+	if err := unmarshal((*plain)(r)); err != nil {
+		return err
+	}
+	return r.Validate()
+}
+
+func (r *Rule) UnmarshalJSON(b []byte) error {
+	type plain Rule
+	if err := json.Unmarshal(b, (*plain)(r)); err != nil {
+		return err
+	}
+	return r.Validate()
+}
+
+// Validate validates the rule and prepares it to be applied.
+func (r *Rule) Validate() error {
+	if r.Name == "" {
+		return errMissingName
+	}
+
+	switch r.Action {
+	case StructuredMetadata:
+		if r.Regex == "" {
+			return fmt.Errorf("rule %s: the regex must be set for the %s action", r.Name, r.Action)
+		}
+		re, err := relabel.NewRegexp(r.Regex)
+		if err != nil {
+			return fmt.Errorf("rule %s: invalid regex: %w", r.Name, err)
+		}
+		r.regex = re
+	case Hash:
+		if len(r.SourceLabels) == 0 || r.TargetLabel == "" {
+			return fmt.Errorf("rule %s: the source labels and the target label must be set for the %s action", r.Name, r.Action)
+		}
+	default:
+		// Let Prometheus validate the rule and set the defaults of its action.
+		out, err := yaml.Marshal(r)
+		if err != nil {
+			return err
+		}
+		var cfg relabel.Config
+		if err := yaml.Unmarshal(out, &cfg); err != nil {
+			return fmt.Errorf("rule %s: %w", r.Name, err)
+		}
+		r.relabel = &cfg
+	}
+
+	return nil
+}
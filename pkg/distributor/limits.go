@@ -4,7 +4,10 @@ import (
 	"time"
 
 	"github.com/grafana/loki/v3/pkg/compactor/retention"
+	"github.com/grafana/loki/v3/pkg/distributor/labelpolicy"
+	"github.com/grafana/loki/v3/pkg/distributor/severity"
 	"github.com/grafana/loki/v3/pkg/distributor/shardstreams"
+	"github.com/grafana/loki/v3/pkg/distributor/writefailures"
 	"github.com/grafana/loki/v3/pkg/loghttp/push"
 )
 
@@ -24,6 +27,8 @@ type Limits interface {
 	IncrementDuplicateTimestamps(userID string) bool
 	DiscoverServiceName(userID string) []string
 	DiscoverLogLevels(userID string) bool
+	SeverityNormalization(userID string) *severity.Config
+	LabelPolicy(userID string) []*labelpolicy.Rule
 
 	ShardStreams(userID string) *shardstreams.Config
 	IngestionRateStrategy() string
@@ -33,4 +38,6 @@ type Limits interface {
 	MaxStructuredMetadataSize(userID string) int
 	MaxStructuredMetadataCount(userID string) int
 	OTLPConfig(userID string) push.OTLPConfig
+
+	writefailures.StreamLimits
 }
@@ -29,6 +29,11 @@ const (
 	// A larger factor weights recent samples more heavily while a smaller
 	// factor weights historic samples more heavily.
 	smoothingFactor = .4
+
+	// A shard added to a stream because ingesters rate limited it is merged back
+	// only once the rate of the stream fits in this fraction of the capacity of
+	// the remaining shards.
+	adaptiveMergeThreshold = .8
 )
 
 type RateStoreConfig struct {
@@ -51,10 +56,74 @@ type ingesterClient struct {
 }
 
 type expiringRate struct {
-	createdAt time.Time
-	rate      int64
-	shards    int64
-	pushes    float64
+	createdAt   time.Time
+	rate        int64
+	shards      int64
+	pushes      float64
+	rateLimited int64
+	adaptive    adaptiveShards
+}
+
+// adaptiveShards tracks the number of shards of a stream raised because
+// ingesters rate limited the stream.
+type adaptiveShards struct {
+	// shards is the minimum number of shards of the stream, 0 if the stream
+	// isn't adaptively sharded.
+	shards int64
+	// capacity is the rate a single shard accepted the last time ingesters rate
+	// limited the stream.
+	capacity   int64
+	lastChange time.Time
+}
+
+// grow raises the number of shards of a stream after ingesters rate limited
+// rateLimited of the rate bytes pushed to its shards since the last update.
+func (a *adaptiveShards) grow(rate, rateLimited, shards int64, now time.Time) {
+	if rateLimited <= 0 {
+		return
+	}
+
+	shards = max(shards, 1)
+	target := 2 * shards
+	if accepted := rate - rateLimited; accepted > 0 {
+		// Spread the rate over as many shards as needed for each of them to
+		// stay under the rate the current shards accepted.
+		target = max(int64(math.Ceil(float64(shards*rate)/float64(accepted))), shards+1)
+		a.capacity = accepted / shards
+	}
+
+	a.shards = max(a.shards, target)
+	a.lastChange = now
+}
+
+// merge removes one of the shards of an adaptively sharded stream once it
+// wasn't rate limited for cooldown and the rate fits in the remaining shards.
+func (a *adaptiveShards) merge(rate int64, cooldown time.Duration, now time.Time) {
+	if a.shards == 0 || now.Sub(a.lastChange) < cooldown {
+		return
+	}
+
+	if a.capacity > 0 && float64(rate) > adaptiveMergeThreshold*float64(a.capacity*(a.shards-1)) {
+		return
+	}
+
+	a.shards--
+	a.lastChange = now
+	if a.shards <= 1 {
+		*a = adaptiveShards{}
+	}
+}
+
+// StreamRateStats is the rate store's view of a stream, with its shards
+// combined.
+type StreamRateStats struct {
+	StreamHashNoShard uint64
+	Rate              int64
+	Pushes            float64
+	Shards            int64
+	// AdaptiveShards is the number of shards the stream needs because ingesters
+	// rate limited it, 0 if they didn't.
+	AdaptiveShards int64
 }
 
 type rateStore struct {
@@ -118,16 +187,18 @@ func (s *rateStore) updateAllRates(ctx context.Context) error {
 	s.metrics.maxStreamRate.Set(float64(updateStats.maxRate))
 	s.metrics.maxStreamShardCount.Set(float64(updateStats.maxShards))
 	s.metrics.streamCount.Set(float64(updateStats.totalStreams))
+	s.metrics.adaptiveShardedStreams.Set(float64(updateStats.adaptiveStreams))
 	s.metrics.expiredCount.Add(float64(updateStats.expiredCount))
 
 	return nil
 }
 
 type rateStats struct {
-	maxShards    int64
-	maxRate      int64
-	totalStreams int64
-	expiredCount int64
+	maxShards       int64
+	maxRate         int64
+	totalStreams    int64
+	expiredCount    int64
+	adaptiveStreams int64
 }
 
 func (s *rateStore) updateRates(ctx context.Context, updated map[string]map[uint64]expiringRate) rateStats {
@@ -146,11 +217,25 @@ func (s *rateStore) updateRates(ctx context.Context, updated map[string]map[uint
 			s.rates[tenantID] = map[uint64]expiringRate{}
 		}
 
+		cooldown := time.Duration(s.limits.ShardStreams(tenantID).AdaptiveMergeCooldown)
 		for stream, rate := range tenant {
-			if oldRate, ok := s.rates[tenantID][stream]; ok {
+			oldRate, ok := s.rates[tenantID][stream]
+			if ok {
+				rate.adaptive = oldRate.adaptive
+			}
+
+			// Shards are added based on the rates of the last update rather than the
+			// smoothed ones, so that rate limited streams are sharded right away.
+			rate.adaptive.grow(rate.rate, rate.rateLimited, rate.shards, rate.createdAt)
+
+			if ok {
 				rate.rate = weightedMovingAverage(rate.rate, oldRate.rate)
 				rate.pushes = weightedMovingAverageF(rate.pushes, oldRate.pushes)
 			}
+
+			if rate.rateLimited == 0 {
+				rate.adaptive.merge(rate.rate, cooldown, rate.createdAt)
+			}
 			s.rates[tenantID][stream] = rate
 			streamCnt++
 		}
@@ -170,9 +255,11 @@ func weightedMovingAverageF(next, last float64) float64 {
 
 func (s *rateStore) cleanupExpired(updated map[string]map[uint64]expiringRate) rateStats {
 	var rs rateStats
+	now := time.Now()
 
 	for tID, tenant := range s.rates {
 		rs.totalStreams += int64(len(tenant))
+		cooldown := time.Duration(s.limits.ShardStreams(tID).AdaptiveMergeCooldown)
 		for stream, rate := range tenant {
 			if time.Since(rate.createdAt) > s.rateKeepAlive {
 				rs.expiredCount++
@@ -186,9 +273,15 @@ func (s *rateStore) cleanupExpired(updated map[string]map[uint64]expiringRate) r
 			if !s.wasUpdated(tID, stream, updated) {
 				rate.rate = weightedMovingAverage(0, rate.rate)
 				rate.pushes = weightedMovingAverageF(0, rate.pushes)
+				rate.adaptive.merge(rate.rate, cooldown, now)
 				s.rates[tID][stream] = rate
 			}
 
+			if rate.adaptive.shards > 0 {
+				rs.adaptiveStreams++
+				s.metrics.adaptiveStreamShardCount.Observe(float64(rate.adaptive.shards))
+			}
+
 			rs.maxRate = max(rs.maxRate, rate.rate)
 			rs.maxShards = max(rs.maxShards, rate.shards)
 
@@ -247,6 +340,7 @@ func (s *rateStore) aggregateByShard(ctx context.Context, streamRates map[string
 			rate := rates[tID][streamRate.StreamHashNoShard]
 			rate.rate += streamRate.Rate
 			rate.pushes = math.Max(float64(streamRate.Pushes), rate.pushes)
+			rate.rateLimited += streamRate.RateLimited
 			rate.shards++
 			rate.createdAt = now
 
@@ -378,3 +472,35 @@ func (s *rateStore) RateFor(tenant string, streamHash uint64) (int64, float64) {
 
 	return 0, 0
 }
+
+// AdaptiveShardsFor returns the number of shards the stream needs because
+// ingesters rate limited it, or 0 if they didn't.
+func (s *rateStore) AdaptiveShardsFor(tenant string, streamHash uint64) int {
+	s.rateLock.RLock()
+	defer s.rateLock.RUnlock()
+
+	if t, ok := s.rates[tenant]; ok {
+		return int(t[streamHash].adaptive.shards)
+	}
+
+	return 0
+}
+
+// StreamRatesFor returns the current stats of every stream of the tenant.
+func (s *rateStore) StreamRatesFor(tenant string) []StreamRateStats {
+	s.rateLock.RLock()
+	defer s.rateLock.RUnlock()
+
+	stats := make([]StreamRateStats, 0, len(s.rates[tenant]))
+	for stream, rate := range s.rates[tenant] {
+		stats = append(stats, StreamRateStats{
+			StreamHashNoShard: stream,
+			Rate:              rate.rate,
+			Pushes:            rate.pushes,
+			Shards:            rate.shards,
+			AdaptiveShards:    rate.adaptive.shards,
+		})
+	}
+
+	return stats
+}
@@ -9,15 +9,17 @@ import (
 )
 
 type ratestoreMetrics struct {
-	rateRefreshFailures *prometheus.CounterVec
-	streamCount         prometheus.Gauge
-	expiredCount        prometheus.Counter
-	maxStreamShardCount prometheus.Gauge
-	streamShardCount    prometheus.Histogram
-	maxStreamRate       prometheus.Gauge
-	streamRate          prometheus.Histogram
-	maxUniqueStreamRate prometheus.Gauge
-	refreshDuration     *instrument.HistogramCollector
+	rateRefreshFailures      *prometheus.CounterVec
+	streamCount              prometheus.Gauge
+	expiredCount             prometheus.Counter
+	maxStreamShardCount      prometheus.Gauge
+	streamShardCount         prometheus.Histogram
+	adaptiveShardedStreams   prometheus.Gauge
+	adaptiveStreamShardCount prometheus.Histogram
+	maxStreamRate            prometheus.Gauge
+	streamRate               prometheus.Histogram
+	maxUniqueStreamRate      prometheus.Gauge
+	refreshDuration          *instrument.HistogramCollector
 }
 
 func newRateStoreMetrics(reg prometheus.Registerer) *ratestoreMetrics {
@@ -48,6 +50,17 @@ func newRateStoreMetrics(reg prometheus.Registerer) *ratestoreMetrics {
 			Help:      "The distribution of number of shards for a single stream reported by ingesters during a sync operation.",
 			Buckets:   []float64{0, 1, 2, 4, 8, 16, 32, 64, 128},
 		}),
+		adaptiveShardedStreams: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace: constants.Loki,
+			Name:      "rate_store_adaptive_sharded_streams",
+			Help:      "The number of streams sharded further because ingesters rate limited them.",
+		}),
+		adaptiveStreamShardCount: promauto.With(reg).NewHistogram(prometheus.HistogramOpts{
+			Namespace: constants.Loki,
+			Name:      "rate_store_adaptive_stream_shards",
+			Help:      "The distribution of the number of shards of streams sharded further because ingesters rate limited them.",
+			Buckets:   []float64{2, 4, 8, 16, 32, 64, 128},
+		}),
 		maxStreamRate: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
 			Namespace: constants.Loki,
 			Name:      "rate_store_max_stream_rate_bytes",
@@ -230,6 +230,55 @@ func TestRateStore(t *testing.T) {
 		_, afterNewPushRate := tc.rateStore.RateFor("tenant 1", 0)
 		require.EqualValues(t, weightedMovingAverageF(0, 1), afterNewPushRate)
 	})
+
+	t.Run("it shards rate limited streams and merges the shards back", func(t *testing.T) {
+		tc := setup(true)
+		tc.ring.replicationSet = ring.ReplicationSet{
+			Instances: []ring.InstanceDesc{
+				{Addr: "ingester0"},
+			},
+		}
+
+		// Half of the 200 bytes pushed to the two shards were rate limited, so
+		// a shard accepts 50 bytes and the stream needs 4 shards.
+		tc.clientPool.clients = map[string]client.PoolClient{
+			"ingester0": newRateClient([]*logproto.StreamRate{
+				{Tenant: "tenant 1", StreamHash: 1, StreamHashNoShard: 0, Rate: 100, Pushes: 1, RateLimited: 50},
+				{Tenant: "tenant 1", StreamHash: 2, StreamHashNoShard: 0, Rate: 100, Pushes: 1, RateLimited: 50},
+			}, 1),
+		}
+
+		require.NoError(t, tc.rateStore.instrumentedUpdateAllRates(context.Background()))
+		require.Equal(t, 4, tc.rateStore.AdaptiveShardsFor("tenant 1", 0))
+		require.Equal(t, []StreamRateStats{
+			{StreamHashNoShard: 0, Rate: 200, Pushes: 1, Shards: 2, AdaptiveShards: 4},
+		}, tc.rateStore.StreamRatesFor("tenant 1"))
+
+		// The stream isn't rate limited anymore, but still doesn't fit in 3 shards.
+		tc.clientPool.clients = map[string]client.PoolClient{
+			"ingester0": newRateClient([]*logproto.StreamRate{
+				{Tenant: "tenant 1", StreamHash: 1, StreamHashNoShard: 0, Rate: 50, Pushes: 1},
+				{Tenant: "tenant 1", StreamHash: 2, StreamHashNoShard: 0, Rate: 50, Pushes: 1},
+				{Tenant: "tenant 1", StreamHash: 3, StreamHashNoShard: 0, Rate: 50, Pushes: 1},
+				{Tenant: "tenant 1", StreamHash: 4, StreamHashNoShard: 0, Rate: 50, Pushes: 1},
+			}, 1),
+		}
+
+		require.NoError(t, tc.rateStore.instrumentedUpdateAllRates(context.Background()))
+		require.Equal(t, 4, tc.rateStore.AdaptiveShardsFor("tenant 1", 0))
+
+		// The shards are merged back one by one as the rate goes down.
+		tc.ring.replicationSet = ring.ReplicationSet{}
+
+		shards := 4
+		for i := 0; i < 10 && shards > 0; i++ {
+			require.NoError(t, tc.rateStore.instrumentedUpdateAllRates(context.Background()))
+			next := tc.rateStore.AdaptiveShardsFor("tenant 1", 0)
+			require.LessOrEqual(t, next, shards)
+			shards = next
+		}
+		require.Equal(t, 0, shards)
+	})
 }
 
 var benchErr error
@@ -0,0 +1,47 @@
+package severity
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/dskit/flagext"
+)
+
+type Config struct {
+	Enabled bool `yaml:"enabled" json:"enabled" category:"experimental"`
+
+	// Fields are the JSON and logfmt fields the level is parsed from, in order of precedence.
+	Fields flagext.StringSliceCSV `yaml:"fields" json:"fields" category:"experimental"`
+
+	// Mapping maps additional spellings of the levels to the canonical levels. It takes precedence over the
+	// built-in spellings.
+	Mapping map[string]string `yaml:"mapping" json:"mapping" category:"experimental" doc:"description=Experimental: Additional spellings of the levels, mapped to one of the canonical levels debug, info, warn, error, critical and fatal. The spellings are case insensitive and take precedence over the built-in ones. For example, map 30 to info for pino logs, which are otherwise read as Python logging levels.\nExample:\n mapping:\n '30': info\n notice: warn"`
+
+	// IndexLabel also adds the level as an indexed label, which splits the streams by level.
+	IndexLabel bool `yaml:"index_label" json:"index_label" category:"experimental"`
+}
+
+func (cfg *Config) RegisterFlagsWithPrefix(prefix string, fs *flag.FlagSet) {
+	fs.BoolVar(&cfg.Enabled, prefix+".enabled", false, "Experimental: Normalize the level of the log lines during ingestion and add it to their structured metadata with name 'level'. The level is read from an existing 'level' structured metadata, the OTLP severity, the syslog priority of the line or the JSON and logfmt fields of the line, and mapped to one of 'debug', 'info', 'warn', 'error', 'critical', 'fatal' or 'unknown' if no level is found. It replaces the detection enabled by -validation.discover-log-levels.")
+	cfg.Fields = []string{"level", "lvl", "severity", "loglevel", "log_level"}
+	fs.Var(&cfg.Fields, prefix+".fields", "Experimental: Comma separated list of the JSON and logfmt fields the level is parsed from, in order of precedence.")
+	fs.BoolVar(&cfg.IndexLabel, prefix+".index-label", false, "Experimental: Also add the normalized level as an indexed 'level' label to the streams without one. The entries of a stream are split into a stream per level. The level label counts towards max_label_names_per_series.")
+}
+
+// Validate validates the config and normalizes the spellings of the mapping.
+func (cfg *Config) Validate() error {
+	if len(cfg.Mapping) == 0 {
+		return nil
+	}
+
+	mapping := make(map[string]string, len(cfg.Mapping))
+	for spelling, level := range cfg.Mapping {
+		if !isCanonical(level) {
+			return fmt.Errorf("severity normalization: invalid level %q for %q, must be one of %s", level, spelling, strings.Join(canonicalLevels, ", "))
+		}
+		mapping[strings.ToLower(spelling)] = level
+	}
+	cfg.Mapping = mapping
+	return nil
+}
@@ -0,0 +1,260 @@
+package severity
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unsafe"
+
+	"github.com/grafana/jsonparser"
+	"github.com/prometheus/prometheus/model/labels"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/grafana/loki/v3/pkg/loghttp/push"
+	"github.com/grafana/loki/v3/pkg/logql/log/logfmt"
+)
+
+// The canonical levels.
+const (
+	Debug    = "debug"
+	Info     = "info"
+	Warn     = "warn"
+	Error    = "error"
+	Critical = "critical"
+	Fatal    = "fatal"
+	// Unknown is the level of the lines without a level.
+	Unknown = "unknown"
+
+	// Label is the name of the structured metadata and of the indexed label the level is stored in.
+	Label = "level"
+)
+
+var canonicalLevels = []string{Debug, Info, Warn, Error, Critical, Fatal, Unknown}
+
+// spellings maps the lowercased vendor spellings of the levels to the canonical levels. The numeric levels are the
+// ones of the Python logging package.
+var spellings = map[string]string{
+	"trace": Debug, "trc": Debug, "debug": Debug, "dbg": Debug, "d": Debug, "10": Debug,
+	"info": Info, "inf": Info, "i": Info, "information": Info, "informational": Info, "notice": Info, "20": Info,
+	"warn": Warn, "wrn": Warn, "w": Warn, "warning": Warn, "30": Warn,
+	"error": Error, "err": Error, "eror": Error, "e": Error, "40": Error,
+	"critical": Critical, "crit": Critical, "crt": Critical, "c": Critical, "alert": Critical, "50": Critical,
+	"fatal": Fatal, "ftl": Fatal, "f": Fatal, "panic": Fatal, "emerg": Fatal, "emergency": Fatal,
+}
+
+// syslogSeverities maps the severities of the syslog priorities to the canonical levels.
+var syslogSeverities = [8]string{Fatal, Critical, Critical, Error, Warn, Info, Info, Debug}
+
+func isCanonical(level string) bool {
+	for _, l := range canonicalLevels {
+		if level == l {
+			return true
+		}
+	}
+	return false
+}
+
+// Detect returns the canonical level of a log line. The level is read, in order, from the level in the structured
+// metadata, the OTLP severity number and text, the syslog priority of the line and the configured JSON or logfmt
+// fields of the line, and last guessed from the words of the line. It returns Unknown if no level is found.
+func (cfg *Config) Detect(line string, structuredMetadata labels.Labels) string {
+	if level, ok := cfg.Normalize(structuredMetadata.Get(Label)); ok {
+		return level
+	}
+	if n, err := strconv.Atoi(structuredMetadata.Get(push.OTLPSeverityNumber)); err == nil {
+		if level, ok := FromOTLPSeverityNumber(n); ok {
+			return level
+		}
+	}
+	if level, ok := cfg.Normalize(structuredMetadata.Get(push.OTLPSeverityText)); ok {
+		return level
+	}
+	if level, ok := fromSyslogPriority(line); ok {
+		return level
+	}
+	if level, ok := cfg.fromFields(line); ok {
+		return level
+	}
+	if level, ok := FromLine(line); ok {
+		return level
+	}
+	return Unknown
+}
+
+// Normalize maps a spelling of a level to the canonical level, using the mapping of the config before the built-in
+// spellings.
+func (cfg *Config) Normalize(spelling string) (string, bool) {
+	if spelling == "" {
+		return "", false
+	}
+	spelling = strings.ToLower(strings.TrimSpace(spelling))
+	if level, ok := cfg.Mapping[spelling]; ok {
+		return level, true
+	}
+	level, ok := spellings[spelling]
+	return level, ok
+}
+
+// FromOTLPSeverityNumber maps an OTLP severity number to the canonical level.
+// See https://opentelemetry.io/docs/specs/otel/logs/data-model/#field-severitynumber
+func FromOTLPSeverityNumber(n int) (string, bool) {
+	switch {
+	case n <= int(plog.SeverityNumberUnspecified):
+		return "", false
+	case n <= int(plog.SeverityNumberDebug4):
+		return Debug, true
+	case n <= int(plog.SeverityNumberInfo4):
+		return Info, true
+	case n <= int(plog.SeverityNumberWarn4):
+		return Warn, true
+	case n <= int(plog.SeverityNumberError4):
+		return Error, true
+	case n <= int(plog.SeverityNumberFatal4):
+		return Fatal, true
+	}
+	return "", false
+}
+
+// fromSyslogPriority returns the level of the syslog priority the line starts with, like <34>.
+func fromSyslogPriority(line string) (string, bool) {
+	if len(line) < 3 || line[0] != '<' {
+		return "", false
+	}
+	end := strings.IndexByte(line[:min(len(line), 5)], '>')
+	if end < 2 {
+		return "", false
+	}
+	priority, err := strconv.Atoi(line[1:end])
+	if err != nil || priority < 0 || priority > 191 {
+		return "", false
+	}
+	return syslogSeverities[priority%8], true
+}
+
+// fromFields returns the level of the first configured field of a JSON or logfmt line.
+func (cfg *Config) fromFields(line string) (string, bool) {
+	if len(cfg.Fields) == 0 {
+		return "", false
+	}
+
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" {
+		return "", false
+	}
+	b := unsafe.Slice(unsafe.StringData(trimmed), len(trimmed))
+
+	if trimmed[0] == '{' {
+		for _, field := range cfg.Fields {
+			value, dataType, _, err := jsonparser.Get(b, field)
+			if err != nil || (dataType != jsonparser.String && dataType != jsonparser.Number) {
+				continue
+			}
+			if level, ok := cfg.Normalize(string(value)); ok {
+				return level, true
+			}
+		}
+		return "", false
+	}
+
+	if !strings.ContainsRune(trimmed, '=') {
+		return "", false
+	}
+
+	// Keep the level of the field with the highest precedence.
+	level, precedence := "", len(cfg.Fields)
+	dec := logfmt.NewDecoder(b)
+	for precedence > 0 && dec.ScanKeyval() {
+		for i, field := range cfg.Fields[:precedence] {
+			if string(dec.Key()) != field {
+				continue
+			}
+			if l, ok := cfg.Normalize(string(dec.Value())); ok {
+				level, precedence = l, i
+			}
+			break
+		}
+	}
+	return level, level != ""
+}
+
+// FromLine guesses the level of a line from the level-like words it contains, looking first for the levels of JSON and
+// logfmt lines to avoid false detections.
+func FromLine(log string) (string, bool) {
+	// check for log levels in known log formats to avoid any false detection
+
+	// json logs:
+	var firstNonSpaceChar rune
+	for _, char := range log {
+		if !unicode.IsSpace(char) {
+			firstNonSpaceChar = char
+			break
+		}
+	}
+
+	var lastNonSpaceChar rune
+	for i := len(log) - 1; i >= 0; i-- {
+		char := rune(log[i])
+		if !unicode.IsSpace(char) {
+			lastNonSpaceChar = char
+			break
+		}
+	}
+
+	if firstNonSpaceChar == '{' && lastNonSpaceChar == '}' {
+		if strings.Contains(log, `:"err"`) || strings.Contains(log, `:"ERR"`) ||
+			strings.Contains(log, `:"error"`) || strings.Contains(log, `:"ERROR"`) {
+			return Error, true
+		}
+		if strings.Contains(log, `:"warn"`) || strings.Contains(log, `:"WARN"`) ||
+			strings.Contains(log, `:"warning"`) || strings.Contains(log, `:"WARNING"`) {
+			return Warn, true
+		}
+		if strings.Contains(log, `:"critical"`) || strings.Contains(log, `:"CRITICAL"`) {
+			return Critical, true
+		}
+		if strings.Contains(log, `:"debug"`) || strings.Contains(log, `:"DEBUG"`) {
+			return Debug, true
+		}
+		if strings.Contains(log, `:"info"`) || strings.Contains(log, `:"INFO"`) {
+			return Debug, true
+		}
+	}
+
+	// logfmt logs:
+	if strings.Contains(log, "=") {
+		if strings.Contains(log, "=err") || strings.Contains(log, "=ERR") ||
+			strings.Contains(log, "=error") || strings.Contains(log, "=ERROR") {
+			return Error, true
+		}
+		if strings.Contains(log, "=warn") || strings.Contains(log, "=WARN") ||
+			strings.Contains(log, "=warning") || strings.Contains(log, "=WARNING") {
+			return Warn, true
+		}
+		if strings.Contains(log, "=critical") || strings.Contains(log, "=CRITICAL") {
+			return Critical, true
+		}
+		if strings.Contains(log, "=debug") || strings.Contains(log, "=DEBUG") {
+			return Debug, true
+		}
+		if strings.Contains(log, "=info") || strings.Contains(log, "=INFO") {
+			return Debug, true
+		}
+	}
+
+	if strings.Contains(log, "err:") || strings.Contains(log, "ERR:") ||
+		strings.Contains(log, "error") || strings.Contains(log, "ERROR") {
+		return Error, true
+	}
+	if strings.Contains(log, "warn:") || strings.Contains(log, "WARN:") ||
+		strings.Contains(log, "warning") || strings.Contains(log, "WARNING") {
+		return Warn, true
+	}
+	if strings.Contains(log, "CRITICAL:") || strings.Contains(log, "critical:") {
+		return Critical, true
+	}
+	if strings.Contains(log, "debug:") || strings.Contains(log, "DEBUG:") {
+		return Debug, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,124 @@
+package severity
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+)
+
+func defaultConfig() *Config {
+	cfg := &Config{}
+	cfg.RegisterFlagsWithPrefix("", flag.NewFlagSet("", flag.PanicOnError))
+	return cfg
+}
+
+func TestDetect(t *testing.T) {
+	for _, tc := range []struct {
+		name               string
+		line               string
+		structuredMetadata labels.Labels
+		mapping            map[string]string
+		want               string
+	}{
+		{
+			name: "no level",
+			line: "foo bar",
+			want: Unknown,
+		},
+		{
+			name:               "structured metadata",
+			line:               `level=debug`,
+			structuredMetadata: labels.FromStrings("level", "WARNING"),
+			want:               Warn,
+		},
+		{
+			name:               "unknown structured metadata",
+			line:               `level=debug`,
+			structuredMetadata: labels.FromStrings("level", "foo"),
+			want:               Debug,
+		},
+		{
+			name:               "otlp severity number",
+			structuredMetadata: labels.FromStrings("severity_number", "13", "severity_text", "INFO"),
+			want:               Warn,
+		},
+		{
+			name:               "otlp severity text",
+			structuredMetadata: labels.FromStrings("severity_text", "Fatal"),
+			want:               Fatal,
+		},
+		{
+			name: "syslog priority",
+			line: "<34>Oct 11 22:14:15 mymachine su: 'su root' failed",
+			want: Critical,
+		},
+		{
+			name: "invalid syslog priority",
+			line: "<999> level=info",
+			want: Info,
+		},
+		{
+			name: "json",
+			line: ` {"msg":"foo","lvl":"E"}`,
+			want: Error,
+		},
+		{
+			name: "json numeric level",
+			line: `{"msg":"foo","level":30}`,
+			want: Warn,
+		},
+		{
+			name:    "json numeric level with mapping",
+			line:    `{"msg":"foo","level":30}`,
+			mapping: map[string]string{"30": Info},
+			want:    Info,
+		},
+		{
+			name: "json nested level",
+			line: `{"msg":"foo","data":{"level":"error"}}`,
+			want: Error,
+		},
+		{
+			name: "json without level",
+			line: `{"msg":"foo"}`,
+			want: Unknown,
+		},
+		{
+			name: "logfmt",
+			line: `ts=2024-01-01T00:00:00Z msg="level=error" lvl=W`,
+			want: Warn,
+		},
+		{
+			name: "logfmt fields precedence",
+			line: `severity=error msg=foo level=info`,
+			want: Info,
+		},
+		{
+			name: "plain text",
+			line: "2024-01-01 ERROR failed",
+			want: Error,
+		},
+		{
+			name:    "logfmt with mapping",
+			line:    `level=NOTICE msg=foo`,
+			mapping: map[string]string{"Notice": Warn},
+			want:    Warn,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.Mapping = tc.mapping
+			require.NoError(t, cfg.Validate())
+			require.Equal(t, tc.want, cfg.Detect(tc.line, tc.structuredMetadata))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Mapping = map[string]string{"30": "information"}
+	require.EqualError(t, cfg.Validate(), `severity normalization: invalid level "information" for "30", must be one of debug, info, warn, error, critical, fatal, unknown`)
+}
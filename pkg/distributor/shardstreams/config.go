@@ -2,6 +2,9 @@ package shardstreams
 
 import (
 	"flag"
+	"time"
+
+	"github.com/prometheus/common/model"
 
 	"github.com/grafana/loki/v3/pkg/util/flagext"
 )
@@ -13,6 +16,10 @@ type Config struct {
 	// DesiredRate is the threshold used to shard the stream into smaller pieces.
 	// Expected to be in bytes.
 	DesiredRate flagext.ByteSize `yaml:"desired_rate" json:"desired_rate"`
+
+	// AdaptiveEnabled shards streams further when ingesters rate limit them.
+	AdaptiveEnabled       bool           `yaml:"adaptive_enabled" json:"adaptive_enabled" category:"experimental"`
+	AdaptiveMergeCooldown model.Duration `yaml:"adaptive_merge_cooldown" json:"adaptive_merge_cooldown" category:"experimental"`
 }
 
 func (cfg *Config) RegisterFlagsWithPrefix(prefix string, fs *flag.FlagSet) {
@@ -20,4 +27,7 @@ func (cfg *Config) RegisterFlagsWithPrefix(prefix string, fs *flag.FlagSet) {
 	fs.BoolVar(&cfg.LoggingEnabled, prefix+".logging-enabled", false, "Enable logging when sharding streams")
 	cfg.DesiredRate.Set("1536KB") //nolint:errcheck
 	fs.Var(&cfg.DesiredRate, prefix+".desired-rate", "threshold used to cut a new shard. Default (1536KB) means if a rate is above 1536KB/s, it will be sharded.")
+	fs.BoolVar(&cfg.AdaptiveEnabled, prefix+".adaptive-enabled", false, "Experimental: Also shard streams based on the bytes ingesters reject because of the per-stream rate limit, in addition to the desired rate. The shards are merged back once the stream isn't rate limited anymore.")
+	cfg.AdaptiveMergeCooldown = model.Duration(time.Minute)
+	fs.Var(&cfg.AdaptiveMergeCooldown, prefix+".adaptive-merge-cooldown", "Experimental: How long a stream sharded because of the per-stream rate limit must not be rate limited before one of its shards is merged back.")
 }
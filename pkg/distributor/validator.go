@@ -9,6 +9,8 @@ import (
 
 	"github.com/prometheus/prometheus/model/labels"
 
+	"github.com/grafana/loki/v3/pkg/distributor/labelpolicy"
+	"github.com/grafana/loki/v3/pkg/distributor/severity"
 	"github.com/grafana/loki/v3/pkg/loghttp/push"
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/validation"
@@ -45,11 +47,14 @@ type validationContext struct {
 	incrementDuplicateTimestamps bool
 	discoverServiceName          []string
 	discoverLogLevels            bool
+	severityNormalization        *severity.Config
 
 	allowStructuredMetadata    bool
 	maxStructuredMetadataSize  int
 	maxStructuredMetadataCount int
 
+	labelPolicy []*labelpolicy.Rule
+
 	userID string
 }
 
@@ -67,9 +72,11 @@ func (v Validator) getValidationContextForTime(now time.Time, userID string) val
 		incrementDuplicateTimestamps: v.IncrementDuplicateTimestamps(userID),
 		discoverServiceName:          v.DiscoverServiceName(userID),
 		discoverLogLevels:            v.DiscoverLogLevels(userID),
+		severityNormalization:        v.SeverityNormalization(userID),
 		allowStructuredMetadata:      v.AllowStructuredMetadata(userID),
 		maxStructuredMetadataSize:    v.MaxStructuredMetadataSize(userID),
 		maxStructuredMetadataCount:   v.MaxStructuredMetadataCount(userID),
+		labelPolicy:                  v.LabelPolicy(userID),
 	}
 }
 
@@ -87,7 +94,7 @@ func (v Validator) ValidateEntry(ctx context.Context, vCtx validationContext, la
 		if v.usageTracker != nil {
 			v.usageTracker.DiscardedBytesAdd(ctx, vCtx.userID, validation.GreaterThanMaxSampleAge, labels, float64(len(entry.Line)))
 		}
-		return fmt.Errorf(validation.GreaterThanMaxSampleAgeErrorMsg, labels, formatedEntryTime, formatedRejectMaxAgeTime)
+		return newValidationError(validation.GreaterThanMaxSampleAge, fmt.Errorf(validation.GreaterThanMaxSampleAgeErrorMsg, labels, formatedEntryTime, formatedRejectMaxAgeTime))
 	}
 
 	if ts > vCtx.creationGracePeriod {
@@ -97,7 +104,7 @@ func (v Validator) ValidateEntry(ctx context.Context, vCtx validationContext, la
 		if v.usageTracker != nil {
 			v.usageTracker.DiscardedBytesAdd(ctx, vCtx.userID, validation.TooFarInFuture, labels, float64(len(entry.Line)))
 		}
-		return fmt.Errorf(validation.TooFarInFutureErrorMsg, labels, formatedEntryTime)
+		return newValidationError(validation.TooFarInFuture, fmt.Errorf(validation.TooFarInFutureErrorMsg, labels, formatedEntryTime))
 	}
 
 	if maxSize := vCtx.maxLineSize; maxSize != 0 && len(entry.Line) > maxSize {
@@ -110,7 +117,7 @@ func (v Validator) ValidateEntry(ctx context.Context, vCtx validationContext, la
 		if v.usageTracker != nil {
 			v.usageTracker.DiscardedBytesAdd(ctx, vCtx.userID, validation.LineTooLong, labels, float64(len(entry.Line)))
 		}
-		return fmt.Errorf(validation.LineTooLongErrorMsg, maxSize, labels, len(entry.Line))
+		return newValidationError(validation.LineTooLong, fmt.Errorf(validation.LineTooLongErrorMsg, maxSize, labels, len(entry.Line)))
 	}
 
 	if len(entry.StructuredMetadata) > 0 {
@@ -120,7 +127,7 @@ func (v Validator) ValidateEntry(ctx context.Context, vCtx validationContext, la
 			if v.usageTracker != nil {
 				v.usageTracker.DiscardedBytesAdd(ctx, vCtx.userID, validation.DisallowedStructuredMetadata, labels, float64(len(entry.Line)))
 			}
-			return fmt.Errorf(validation.DisallowedStructuredMetadataErrorMsg, labels)
+			return newValidationError(validation.DisallowedStructuredMetadata, fmt.Errorf(validation.DisallowedStructuredMetadataErrorMsg, labels))
 		}
 
 		var structuredMetadataSizeBytes, structuredMetadataCount int
@@ -135,7 +142,7 @@ func (v Validator) ValidateEntry(ctx context.Context, vCtx validationContext, la
 			if v.usageTracker != nil {
 				v.usageTracker.DiscardedBytesAdd(ctx, vCtx.userID, validation.StructuredMetadataTooLarge, labels, float64(len(entry.Line)))
 			}
-			return fmt.Errorf(validation.StructuredMetadataTooLargeErrorMsg, labels, structuredMetadataSizeBytes, vCtx.maxStructuredMetadataSize)
+			return newValidationError(validation.StructuredMetadataTooLarge, fmt.Errorf(validation.StructuredMetadataTooLargeErrorMsg, labels, structuredMetadataSizeBytes, vCtx.maxStructuredMetadataSize))
 		}
 
 		if maxCount := vCtx.maxStructuredMetadataCount; maxCount != 0 && structuredMetadataCount > maxCount {
@@ -144,7 +151,7 @@ func (v Validator) ValidateEntry(ctx context.Context, vCtx validationContext, la
 			if v.usageTracker != nil {
 				v.usageTracker.DiscardedBytesAdd(ctx, vCtx.userID, validation.StructuredMetadataTooMany, labels, float64(len(entry.Line)))
 			}
-			return fmt.Errorf(validation.StructuredMetadataTooManyErrorMsg, labels, structuredMetadataCount, vCtx.maxStructuredMetadataCount)
+			return newValidationError(validation.StructuredMetadataTooMany, fmt.Errorf(validation.StructuredMetadataTooManyErrorMsg, labels, structuredMetadataCount, vCtx.maxStructuredMetadataCount))
 		}
 	}
 
@@ -155,31 +162,69 @@ func (v Validator) ValidateEntry(ctx context.Context, vCtx validationContext, la
 func (v Validator) ValidateLabels(ctx validationContext, ls labels.Labels, stream logproto.Stream) error {
 	if len(ls) == 0 {
 		validation.DiscardedSamples.WithLabelValues(validation.MissingLabels, ctx.userID).Inc()
-		return fmt.Errorf(validation.MissingLabelsErrorMsg)
+		return newValidationError(validation.MissingLabels, fmt.Errorf(validation.MissingLabelsErrorMsg))
 	}
 	numLabelNames := len(ls)
+	// The level label indexed by the severity normalization is added to the stream after its labels are validated.
+	if cfg := ctx.severityNormalization; cfg != nil && cfg.Enabled && cfg.IndexLabel && !ls.Has(labelLevel) {
+		numLabelNames++
+	}
 	if numLabelNames > ctx.maxLabelNamesPerSeries {
 		updateMetrics(validation.MaxLabelNamesPerSeries, ctx.userID, stream)
-		return fmt.Errorf(validation.MaxLabelNamesPerSeriesErrorMsg, stream.Labels, numLabelNames, ctx.maxLabelNamesPerSeries)
+		return newValidationError(validation.MaxLabelNamesPerSeries, fmt.Errorf(validation.MaxLabelNamesPerSeriesErrorMsg, stream.Labels, numLabelNames, ctx.maxLabelNamesPerSeries))
 	}
 
 	lastLabelName := ""
 	for _, l := range ls {
 		if len(l.Name) > ctx.maxLabelNameLength {
 			updateMetrics(validation.LabelNameTooLong, ctx.userID, stream)
-			return fmt.Errorf(validation.LabelNameTooLongErrorMsg, stream.Labels, l.Name)
+			return newValidationError(validation.LabelNameTooLong, fmt.Errorf(validation.LabelNameTooLongErrorMsg, stream.Labels, l.Name))
 		} else if len(l.Value) > ctx.maxLabelValueLength {
 			updateMetrics(validation.LabelValueTooLong, ctx.userID, stream)
-			return fmt.Errorf(validation.LabelValueTooLongErrorMsg, stream.Labels, l.Value)
+			return newValidationError(validation.LabelValueTooLong, fmt.Errorf(validation.LabelValueTooLongErrorMsg, stream.Labels, l.Value))
 		} else if cmp := strings.Compare(lastLabelName, l.Name); cmp == 0 {
 			updateMetrics(validation.DuplicateLabelNames, ctx.userID, stream)
-			return fmt.Errorf(validation.DuplicateLabelNamesErrorMsg, stream.Labels, l.Name)
+			return newValidationError(validation.DuplicateLabelNames, fmt.Errorf(validation.DuplicateLabelNamesErrorMsg, stream.Labels, l.Name))
 		}
 		lastLabelName = l.Name
 	}
 	return nil
 }
 
+// ApplyLabelPolicy applies the label policy of the tenant to the labels of the stream, and returns an error if the
+// policy rejects the stream.
+func (v Validator) ApplyLabelPolicy(ctx validationContext, ls labels.Labels, stream logproto.Stream) (labelpolicy.Result, error) {
+	res := labelpolicy.Apply(ctx.labelPolicy, ls)
+	if res.RejectedBy != "" {
+		updateMetrics(validation.LabelPolicyRejected, ctx.userID, stream)
+		return res, newValidationError(validation.LabelPolicyRejected, fmt.Errorf(validation.LabelPolicyRejectedErrorMsg, stream.Labels, res.RejectedBy))
+	}
+	return res, nil
+}
+
+// validationError is returned for invalid streams and entries, and holds the reason they were rejected for.
+type validationError struct {
+	reason string
+	err    error
+}
+
+func newValidationError(reason string, err error) error {
+	return &validationError{reason: reason, err: err}
+}
+
+func (e *validationError) Error() string {
+	return e.err.Error()
+}
+
+// rejectionReason returns the reason a stream or entry was rejected for with the validation error.
+func rejectionReason(err error) string {
+	var vErr *validationError
+	if errors.As(err, &vErr) {
+		return vErr.reason
+	}
+	return "unknown"
+}
+
 func updateMetrics(reason, userID string, stream logproto.Stream) {
 	validation.DiscardedSamples.WithLabelValues(reason, userID).Inc()
 	bytes := 0
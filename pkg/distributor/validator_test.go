@@ -13,6 +13,7 @@ import (
 
 	"github.com/grafana/loki/pkg/push"
 
+	"github.com/grafana/loki/v3/pkg/distributor/severity"
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logql/syntax"
 	"github.com/grafana/loki/v3/pkg/validation"
@@ -62,18 +63,18 @@ func TestValidator_ValidateEntry(t *testing.T) {
 				},
 			},
 			logproto.Entry{Timestamp: testTime.Add(-time.Hour * 5), Line: "test"},
-			fmt.Errorf(validation.GreaterThanMaxSampleAgeErrorMsg,
+			newValidationError(validation.GreaterThanMaxSampleAge, fmt.Errorf(validation.GreaterThanMaxSampleAgeErrorMsg,
 				testStreamLabelsString,
 				testTime.Add(-time.Hour*5).Format(timeFormat),
 				testTime.Add(-1*time.Hour).Format(timeFormat), // same as RejectOldSamplesMaxAge
-			),
+			)),
 		},
 		{
 			"test too new",
 			"test",
 			nil,
 			logproto.Entry{Timestamp: testTime.Add(time.Hour * 5), Line: "test"},
-			fmt.Errorf(validation.TooFarInFutureErrorMsg, testStreamLabelsString, testTime.Add(time.Hour*5).Format(timeFormat)),
+			newValidationError(validation.TooFarInFuture, fmt.Errorf(validation.TooFarInFutureErrorMsg, testStreamLabelsString, testTime.Add(time.Hour*5).Format(timeFormat))),
 		},
 		{
 			"line too long",
@@ -84,7 +85,7 @@ func TestValidator_ValidateEntry(t *testing.T) {
 				},
 			},
 			logproto.Entry{Timestamp: testTime, Line: "12345678901"},
-			fmt.Errorf(validation.LineTooLongErrorMsg, 10, testStreamLabelsString, 11),
+			newValidationError(validation.LineTooLong, fmt.Errorf(validation.LineTooLongErrorMsg, 10, testStreamLabelsString, 11)),
 		},
 		{
 			"disallowed structured metadata",
@@ -95,7 +96,7 @@ func TestValidator_ValidateEntry(t *testing.T) {
 				},
 			},
 			logproto.Entry{Timestamp: testTime, Line: "12345678901", StructuredMetadata: push.LabelsAdapter{{Name: "foo", Value: "bar"}}},
-			fmt.Errorf(validation.DisallowedStructuredMetadataErrorMsg, testStreamLabelsString),
+			newValidationError(validation.DisallowedStructuredMetadata, fmt.Errorf(validation.DisallowedStructuredMetadataErrorMsg, testStreamLabelsString)),
 		},
 		{
 			"structured metadata too big",
@@ -107,7 +108,7 @@ func TestValidator_ValidateEntry(t *testing.T) {
 				},
 			},
 			logproto.Entry{Timestamp: testTime, Line: "12345678901", StructuredMetadata: push.LabelsAdapter{{Name: "foo", Value: "bar"}}},
-			fmt.Errorf(validation.StructuredMetadataTooLargeErrorMsg, testStreamLabelsString, 6, 4),
+			newValidationError(validation.StructuredMetadataTooLarge, fmt.Errorf(validation.StructuredMetadataTooLargeErrorMsg, testStreamLabelsString, 6, 4)),
 		},
 		{
 			"structured metadata too many",
@@ -119,7 +120,7 @@ func TestValidator_ValidateEntry(t *testing.T) {
 				},
 			},
 			logproto.Entry{Timestamp: testTime, Line: "12345678901", StructuredMetadata: push.LabelsAdapter{{Name: "foo", Value: "bar"}, {Name: "too", Value: "many"}}},
-			fmt.Errorf(validation.StructuredMetadataTooManyErrorMsg, testStreamLabelsString, 2, 1),
+			newValidationError(validation.StructuredMetadataTooMany, fmt.Errorf(validation.StructuredMetadataTooManyErrorMsg, testStreamLabelsString, 2, 1)),
 		},
 	}
 	for _, tt := range tests {
@@ -157,7 +158,7 @@ func TestValidator_ValidateLabels(t *testing.T) {
 			"test",
 			nil,
 			"{}",
-			fmt.Errorf(validation.MissingLabelsErrorMsg),
+			newValidationError(validation.MissingLabels, fmt.Errorf(validation.MissingLabelsErrorMsg)),
 		},
 		{
 			"test too many labels",
@@ -166,7 +167,33 @@ func TestValidator_ValidateLabels(t *testing.T) {
 				&validation.Limits{MaxLabelNamesPerSeries: 2},
 			},
 			"{foo=\"bar\",food=\"bars\",fed=\"bears\"}",
-			fmt.Errorf(validation.MaxLabelNamesPerSeriesErrorMsg, "{foo=\"bar\",food=\"bars\",fed=\"bears\"}", 3, 2),
+			newValidationError(validation.MaxLabelNamesPerSeries, fmt.Errorf(validation.MaxLabelNamesPerSeriesErrorMsg, "{foo=\"bar\",food=\"bars\",fed=\"bears\"}", 3, 2)),
+		},
+		{
+			"test too many labels with the indexed level label",
+			"test",
+			fakeLimits{
+				&validation.Limits{
+					MaxLabelNamesPerSeries: 2,
+					SeverityNormalization:  &severity.Config{Enabled: true, IndexLabel: true},
+				},
+			},
+			"{foo=\"bar\",food=\"bars\"}",
+			newValidationError(validation.MaxLabelNamesPerSeries, fmt.Errorf(validation.MaxLabelNamesPerSeriesErrorMsg, "{foo=\"bar\",food=\"bars\"}", 3, 2)),
+		},
+		{
+			"test existing level label with the indexed level label",
+			"test",
+			fakeLimits{
+				&validation.Limits{
+					MaxLabelNamesPerSeries: 2,
+					MaxLabelNameLength:     5,
+					MaxLabelValueLength:    5,
+					SeverityNormalization:  &severity.Config{Enabled: true, IndexLabel: true},
+				},
+			},
+			"{foo=\"bar\",level=\"info\"}",
+			nil,
 		},
 		{
 			"label name too long",
@@ -178,7 +205,7 @@ func TestValidator_ValidateLabels(t *testing.T) {
 				},
 			},
 			"{fooooo=\"bar\"}",
-			fmt.Errorf(validation.LabelNameTooLongErrorMsg, "{fooooo=\"bar\"}", "fooooo"),
+			newValidationError(validation.LabelNameTooLong, fmt.Errorf(validation.LabelNameTooLongErrorMsg, "{fooooo=\"bar\"}", "fooooo")),
 		},
 		{
 			"label value too long",
@@ -191,7 +218,7 @@ func TestValidator_ValidateLabels(t *testing.T) {
 				},
 			},
 			"{foo=\"barrrrrr\"}",
-			fmt.Errorf(validation.LabelValueTooLongErrorMsg, "{foo=\"barrrrrr\"}", "barrrrrr"),
+			newValidationError(validation.LabelValueTooLong, fmt.Errorf(validation.LabelValueTooLongErrorMsg, "{foo=\"barrrrrr\"}", "barrrrrr")),
 		},
 		{
 			"duplicate label",
@@ -204,7 +231,7 @@ func TestValidator_ValidateLabels(t *testing.T) {
 				},
 			},
 			"{foo=\"bar\", foo=\"barf\"}",
-			fmt.Errorf(validation.DuplicateLabelNamesErrorMsg, "{foo=\"bar\", foo=\"barf\"}", "foo"),
+			newValidationError(validation.DuplicateLabelNames, fmt.Errorf(validation.DuplicateLabelNamesErrorMsg, "{foo=\"bar\", foo=\"barf\"}", "foo")),
 		},
 		{
 			"label value contains %",
@@ -217,7 +244,7 @@ func TestValidator_ValidateLabels(t *testing.T) {
 				},
 			},
 			"{foo=\"bar\", foo=\"barf%s\"}",
-			errors.New("stream '{foo=\"bar\", foo=\"barf%s\"}' has label value too long: 'barf%s'"), // Intentionally construct the string to make sure %s isn't substituted as (MISSING)
+			newValidationError(validation.LabelValueTooLong, errors.New("stream '{foo=\"bar\", foo=\"barf%s\"}' has label value too long: 'barf%s'")), // Intentionally construct the string to make sure %s isn't substituted as (MISSING)
 		},
 	}
 	for _, tt := range tests {
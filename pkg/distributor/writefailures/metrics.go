@@ -28,3 +28,29 @@ func newMetrics(reg prometheus.Registerer, subsystem string) *metrics {
 		}, []string{"org_id"}),
 	}
 }
+
+type streamMetrics struct {
+	writtenCount   *prometheus.CounterVec
+	discardedCount *prometheus.CounterVec
+	pushFailures   *prometheus.CounterVec
+}
+
+func newStreamMetrics(reg prometheus.Registerer) *streamMetrics {
+	return &streamMetrics{
+		writtenCount: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Name:      "write_failures_stream_entries_total",
+			Help:      "The total number of write failures written to the rejections stream of a tenant.",
+		}, []string{"org_id"}),
+		discardedCount: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Name:      "write_failures_stream_discarded_total",
+			Help:      "The total number of write failures of a tenant not written to its rejections stream because of its rate.",
+		}, []string{"org_id"}),
+		pushFailures: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Name:      "write_failures_stream_push_failures_total",
+			Help:      "The total number of failed pushes of the rejections stream to a tenant.",
+		}, []string{"org_id"}),
+	}
+}
@@ -0,0 +1,220 @@
+package writefailures
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/go-logfmt/logfmt"
+	"github.com/grafana/dskit/limiter"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/labels"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/util/flagext"
+)
+
+const (
+	// RejectionsLabel is the label of the rejections stream.
+	RejectionsLabel = "__loki_rejections__"
+	// IngesterRejected is the reason of the write failures returned by the ingesters.
+	IngesterRejected = "ingester_rejected"
+
+	reasonLabel = "reason"
+	tenantLabel = "tenant"
+)
+
+// StreamCfg configures writing a sample of the write failures to a stream the tenants can query.
+type StreamCfg struct {
+	Rate        flagext.ByteSize `yaml:"rate"`
+	MaxLineSize flagext.ByteSize `yaml:"max_line_size"`
+	FlushPeriod time.Duration    `yaml:"flush_period"`
+}
+
+// RegisterFlagsWithPrefix registers the rejections stream flags.
+func (cfg *StreamCfg) RegisterFlagsWithPrefix(prefix string, fs *flag.FlagSet) {
+	_ = cfg.Rate.Set("10KB")
+	fs.Var(&cfg.Rate, prefix+".rate", "Volume of write failures written to the rejections stream of a tenant (per second). Write failures beyond this rate are dropped. The rejections stream doesn't count towards the ingestion rate limit, and isn't subject to the label policy, stream limits and stream budgets, of the tenant it's pushed to.")
+	_ = cfg.MaxLineSize.Set("1KB")
+	fs.Var(&cfg.MaxLineSize, prefix+".max-line-size", "Maximum size of the rejected lines written to the rejections stream. Longer lines are truncated.")
+	fs.DurationVar(&cfg.FlushPeriod, prefix+".flush-period", time.Second, "Period at which the write failures are pushed to the rejections stream.")
+}
+
+// StreamLimits are the per-tenant limits of the rejections stream.
+type StreamLimits interface {
+	RejectionsStreamEnabled(userID string) bool
+	RejectionsStreamTenant(userID string) string
+}
+
+// Pusher pushes the rejections stream.
+type Pusher interface {
+	Push(ctx context.Context, req *logproto.PushRequest) (*logproto.PushResponse, error)
+}
+
+// Failure is a write failure written to the rejections stream.
+type Failure struct {
+	// Reason of the failure, e.g. line_too_long.
+	Reason string
+	Err    error
+	// Stream holds the labels of the rejected stream, if any.
+	Stream string
+	// Entry is the rejected entry, if any.
+	Entry *logproto.Entry
+}
+
+type rejectionsPushContextKey struct{}
+
+// rejectionsPushMetadataKey is the gRPC metadata key marking the pushes of the distributors to the ingesters which
+// write a rejections stream.
+const rejectionsPushMetadataKey = "x-loki-rejections-push"
+
+// IsRejectionsPush returns whether the push of the context writes a rejections stream.
+// These pushes are limited by the rate of the rejections stream instead of the ingestion rate of the tenant, and
+// aren't subject to the label policy, stream limits and stream budgets of the tenant.
+func IsRejectionsPush(ctx context.Context) bool {
+	return ctx.Value(rejectionsPushContextKey{}) != nil
+}
+
+// InjectRejectionsPushIntoGRPCRequest marks the outgoing gRPC push of the context as writing a rejections stream.
+func InjectRejectionsPushIntoGRPCRequest(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, rejectionsPushMetadataKey, "true")
+}
+
+// ExtractRejectionsPushFromGRPCRequest returns the context of an incoming gRPC push marked as writing a rejections
+// stream, so that IsRejectionsPush holds for it. It must only be used by the ingesters, which are only pushed to by
+// the distributors.
+func ExtractRejectionsPushFromGRPCRequest(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md.Get(rejectionsPushMetadataKey)) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, rejectionsPushContextKey{}, struct{}{})
+}
+
+// StreamWriter writes a sample of the write failures of a tenant to a stream with the RejectionsLabel, pushed to
+// the tenant itself or to the tenant configured in its limits.
+type StreamWriter struct {
+	services.Service
+
+	cfg     StreamCfg
+	limits  StreamLimits
+	pusher  Pusher
+	limiter *limiter.RateLimiter
+	logger  log.Logger
+	metrics *streamMetrics
+
+	mtx sync.Mutex
+	// pending holds the streams to push by tenant and labels.
+	pending map[string]map[string]*logproto.Stream
+}
+
+func NewStreamWriter(cfg StreamCfg, limits StreamLimits, pusher Pusher, logger log.Logger, reg prometheus.Registerer) (*StreamWriter, error) {
+	if cfg.FlushPeriod <= 0 {
+		return nil, fmt.Errorf("invalid rejections stream flush period: %v", cfg.FlushPeriod)
+	}
+
+	strategy := newStrategy(cfg.Rate.Val(), float64(cfg.Rate.Val()))
+	w := &StreamWriter{
+		cfg:     cfg,
+		limits:  limits,
+		pusher:  pusher,
+		limiter: limiter.NewRateLimiter(strategy, time.Minute),
+		logger:  log.With(logger, "component", "rejections-stream"),
+		metrics: newStreamMetrics(reg),
+		pending: map[string]map[string]*logproto.Stream{},
+	}
+	w.Service = services.NewTimerService(cfg.FlushPeriod, nil, w.flush, w.stopping)
+	return w, nil
+}
+
+// Write adds the failure to the rejections stream, if it's enabled for the tenant and the failure is within the
+// rate of the tenant. The failures of pushes of rejections streams are ignored.
+func (w *StreamWriter) Write(ctx context.Context, tenantID string, f Failure) {
+	if w == nil || IsRejectionsPush(ctx) || !w.limits.RejectionsStreamEnabled(tenantID) {
+		return
+	}
+
+	line := w.formatLine(f)
+	if !w.limiter.AllowN(time.Now(), tenantID, len(line)) {
+		w.metrics.discardedCount.WithLabelValues(tenantID).Inc()
+		return
+	}
+
+	lbs := labels.NewBuilder(labels.FromStrings(RejectionsLabel, "true", reasonLabel, f.Reason))
+	target := w.limits.RejectionsStreamTenant(tenantID)
+	if target == "" {
+		target = tenantID
+	} else if target != tenantID {
+		lbs.Set(tenantLabel, tenantID)
+	}
+	key := lbs.Labels().String()
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	streams, ok := w.pending[target]
+	if !ok {
+		streams = map[string]*logproto.Stream{}
+		w.pending[target] = streams
+	}
+	stream, ok := streams[key]
+	if !ok {
+		stream = &logproto.Stream{Labels: key}
+		streams[key] = stream
+	}
+	// The timestamp is taken under the lock so that the entries of a stream are in order.
+	stream.Entries = append(stream.Entries, logproto.Entry{Timestamp: time.Now(), Line: line})
+	w.metrics.writtenCount.WithLabelValues(tenantID).Inc()
+}
+
+func (w *StreamWriter) formatLine(f Failure) string {
+	var buf bytes.Buffer
+	enc := logfmt.NewEncoder(&buf)
+	_ = enc.EncodeKeyval("msg", f.Err.Error())
+	if f.Stream != "" {
+		_ = enc.EncodeKeyval("stream", f.Stream)
+	}
+	if f.Entry != nil {
+		_ = enc.EncodeKeyval("ts", f.Entry.Timestamp.Format(time.RFC3339Nano))
+		line := f.Entry.Line
+		if maxSize := w.cfg.MaxLineSize.Val(); maxSize > 0 && len(line) > maxSize {
+			line = line[:maxSize]
+			_ = enc.EncodeKeyval("truncated", true)
+		}
+		_ = enc.EncodeKeyval("line", line)
+	}
+	return buf.String()
+}
+
+func (w *StreamWriter) flush(ctx context.Context) error {
+	w.mtx.Lock()
+	pending := w.pending
+	w.pending = map[string]map[string]*logproto.Stream{}
+	w.mtx.Unlock()
+
+	for tenantID, streams := range pending {
+		req := &logproto.PushRequest{Streams: make([]logproto.Stream, 0, len(streams))}
+		for _, stream := range streams {
+			req.Streams = append(req.Streams, *stream)
+		}
+
+		pushCtx := context.WithValue(user.InjectOrgID(ctx, tenantID), rejectionsPushContextKey{}, struct{}{})
+		if _, err := w.pusher.Push(pushCtx, req); err != nil {
+			level.Warn(w.logger).Log("msg", "failed to push rejections stream", "org_id", tenantID, "err", err)
+			w.metrics.pushFailures.WithLabelValues(tenantID).Inc()
+		}
+	}
+	return nil
+}
+
+func (w *StreamWriter) stopping(_ error) error {
+	return w.flush(context.Background())
+}
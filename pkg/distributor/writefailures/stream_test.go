@@ -0,0 +1,99 @@
+package writefailures
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/util/flagext"
+)
+
+type streamLimitsMock struct {
+	enabled map[string]bool
+	tenant  map[string]string
+}
+
+func (m *streamLimitsMock) RejectionsStreamEnabled(userID string) bool {
+	return m.enabled[userID]
+}
+
+func (m *streamLimitsMock) RejectionsStreamTenant(userID string) string {
+	return m.tenant[userID]
+}
+
+type pusherMock struct {
+	mtx    sync.Mutex
+	ctxs   []context.Context
+	pushed map[string][]logproto.Stream
+}
+
+func (m *pusherMock) Push(ctx context.Context, req *logproto.PushRequest) (*logproto.PushResponse, error) {
+	tenantID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.ctxs = append(m.ctxs, ctx)
+	m.pushed[tenantID] = append(m.pushed[tenantID], req.Streams...)
+	return &logproto.PushResponse{}, nil
+}
+
+func TestStreamWriter(t *testing.T) {
+	limits := &streamLimitsMock{
+		enabled: map[string]bool{"tenant-a": true, "tenant-b": true},
+		tenant:  map[string]string{"tenant-b": "ops"},
+	}
+	pusher := &pusherMock{pushed: map[string][]logproto.Stream{}}
+
+	w, err := NewStreamWriter(StreamCfg{Rate: flagext.ByteSize(1000), MaxLineSize: flagext.ByteSize(5), FlushPeriod: time.Second}, limits, pusher, log.NewNopLogger(), prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	entry := logproto.Entry{Timestamp: time.Unix(0, 0).UTC(), Line: "a long line"}
+	w.Write(ctx, "tenant-a", Failure{Reason: "line_too_long", Err: errors.New("line too long"), Stream: `{app="foo"}`, Entry: &entry})
+	w.Write(ctx, "tenant-a", Failure{Reason: IngesterRejected, Err: errors.New("stream limit")})
+	w.Write(ctx, "tenant-b", Failure{Reason: "line_too_long", Err: errors.New("line too long"), Stream: `{app="bar"}`, Entry: &entry})
+	w.Write(ctx, "tenant-c", Failure{Reason: "line_too_long", Err: errors.New("line too long"), Stream: `{app="baz"}`, Entry: &entry})
+
+	// the failures of tenant-a beyond its rate are dropped.
+	w.Write(ctx, "tenant-a", Failure{Reason: "line_too_long", Err: errors.New(strings.Repeat("z", 1000))})
+
+	require.NoError(t, w.flush(ctx))
+
+	require.Len(t, pusher.pushed, 2)
+	require.ElementsMatch(t, []string{`{__loki_rejections__="true", reason="line_too_long"}`, `{__loki_rejections__="true", reason="ingester_rejected"}`}, streamLabels(pusher.pushed["tenant-a"]))
+	for _, stream := range pusher.pushed["tenant-a"] {
+		require.Len(t, stream.Entries, 1)
+		if stream.Labels == `{__loki_rejections__="true", reason="line_too_long"}` {
+			require.Equal(t, `msg="line too long" stream="{app=\"foo\"}" ts=1970-01-01T00:00:00Z truncated=true line="a lon"`, stream.Entries[0].Line)
+		} else {
+			require.Equal(t, `msg="stream limit"`, stream.Entries[0].Line)
+		}
+	}
+	require.Equal(t, []string{`{__loki_rejections__="true", reason="line_too_long", tenant="tenant-b"}`}, streamLabels(pusher.pushed["ops"]))
+
+	// the failures of the pushes of rejections streams aren't written.
+	for _, pushCtx := range pusher.ctxs {
+		w.Write(pushCtx, "tenant-b", Failure{Reason: "line_too_long", Err: errors.New("line too long")})
+	}
+	require.Empty(t, w.pending)
+}
+
+func streamLabels(streams []logproto.Stream) []string {
+	lbs := make([]string, 0, len(streams))
+	for _, stream := range streams {
+		lbs = append(lbs, stream.Labels)
+	}
+	return lbs
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	gokit_log "github.com/go-kit/log"
+	"github.com/grafana/dskit/ring"
 	"github.com/grafana/dskit/services"
 	"github.com/grafana/dskit/user"
 	"github.com/prometheus/prometheus/model/labels"
@@ -136,6 +137,106 @@ func TestIngesterWAL(t *testing.T) {
 	ensureIngesterData(ctx, t, start, end, i)
 }
 
+func TestIngesterWALJoinRingBeforeReplay(t *testing.T) {
+	walDir := t.TempDir()
+
+	ingesterConfig := defaultIngesterTestConfigWithWAL(t, walDir)
+	ingesterConfig.WAL.ReplayConcurrency = 2
+
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+
+	newStore := func() *mockStore {
+		return &mockStore{
+			chunks: map[string][]chunk.Chunk{},
+		}
+	}
+
+	i, err := New(ingesterConfig, client.Config{}, newStore(), limits, runtime.DefaultTenantConfigs(), nil, writefailures.Cfg{}, constants.Loki, gokit_log.NewNopLogger())
+	require.NoError(t, err)
+	require.Nil(t, services.StartAndAwaitRunning(context.Background(), i))
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+
+	req := logproto.PushRequest{
+		Streams: []logproto.Stream{
+			{
+				Labels: `{foo="bar",bar="baz1"}`,
+			},
+			{
+				Labels: `{foo="bar",bar="baz2"}`,
+			},
+		},
+	}
+
+	start := time.Now()
+	steps := 10
+	end := start.Add(time.Second * time.Duration(steps))
+
+	for i := 0; i < steps; i++ {
+		req.Streams[0].Entries = append(req.Streams[0].Entries, logproto.Entry{
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+			Line:      fmt.Sprintf("line %d", i),
+		})
+		req.Streams[1].Entries = append(req.Streams[1].Entries, logproto.Entry{
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+			Line:      fmt.Sprintf("line %d", i),
+		})
+	}
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	_, err = i.Push(ctx, &req)
+	require.NoError(t, err)
+
+	require.Nil(t, services.StopAndAwaitTerminated(context.Background(), i))
+
+	// restart the ingester, joining the ring before replaying the WAL
+	ingesterConfig.WAL.JoinRingBeforeReplay = true
+	i, err = New(ingesterConfig, client.Config{}, newStore(), limits, runtime.DefaultTenantConfigs(), nil, writefailures.Cfg{}, constants.Loki, gokit_log.NewNopLogger())
+	require.NoError(t, err)
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+	require.Nil(t, services.StartAndAwaitRunning(context.Background(), i))
+
+	// the ingester goes ACTIVE once the replay is finished
+	require.Eventually(t, func() bool {
+		return i.lifecycler.GetState() == ring.ACTIVE
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, i.CheckReady(ctx))
+
+	// ensure we've recovered data from wal segments
+	ensureIngesterData(ctx, t, start, end, i)
+
+	_, err = i.Push(ctx, &logproto.PushRequest{
+		Streams: []logproto.Stream{
+			{
+				Labels:  `{foo="bar",bar="baz3"}`,
+				Entries: []logproto.Entry{{Timestamp: end, Line: "line"}},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	// pushes and queries are rejected while the WAL is replaying, and an ingester restored as ACTIVE in the ring
+	// isn't ready until the replay is finished.
+	i.replaying.Store(true)
+	_, err = i.Push(ctx, &req)
+	require.Equal(t, ErrReplaying, err)
+	_, err = i.Label(ctx, &logproto.LabelRequest{})
+	require.Equal(t, ErrReplaying, err)
+	require.Error(t, i.CheckReady(ctx))
+
+	i.replaying.Store(false)
+	require.NoError(t, i.CheckReady(ctx))
+}
+
+func TestJoinRingBeforeReplayRequiresImmediateJoin(t *testing.T) {
+	cfg := defaultIngesterTestConfigWithWAL(t, t.TempDir())
+	cfg.WAL.JoinRingBeforeReplay = true
+	require.NoError(t, cfg.Validate())
+
+	cfg.LifecyclerConfig.ObservePeriod = time.Second
+	require.Error(t, cfg.Validate())
+}
+
 func TestIngesterWALIgnoresStreamLimits(t *testing.T) {
 	walDir := t.TempDir()
 
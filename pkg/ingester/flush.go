@@ -295,21 +295,13 @@ func (i *Ingester) flushChunks(ctx context.Context, fp model.Fingerprint, labelP
 	sizePerTenant := i.metrics.chunkSizePerTenant.WithLabelValues(userID)
 	countPerTenant := i.metrics.chunksPerTenant.WithLabelValues(userID)
 
-	for j, c := range cs {
-		if err := i.closeChunk(c, chunkMtx); err != nil {
-			return fmt.Errorf("chunk close for flushing: %w", err)
-		}
-
-		firstTime, lastTime := util.RoundToMilliseconds(c.chunk.Bounds())
-		ch := chunk.NewChunk(
-			userID, fp, metric,
-			chunkenc.NewFacade(c.chunk, i.cfg.BlockSize, i.cfg.TargetChunkSize),
-			firstTime,
-			lastTime,
-		)
+	if i.segmentFlusher != nil {
+		return i.flushChunksInSegment(ctx, userID, fp, metric, cs, chunkMtx, sizePerTenant, countPerTenant)
+	}
 
-		// encodeChunk mutates the chunk so we must pass by reference
-		if err := i.encodeChunk(ctx, &ch, c); err != nil {
+	for j, c := range cs {
+		ch, err := i.closeAndEncodeChunk(ctx, userID, fp, metric, c, chunkMtx)
+		if err != nil {
 			return err
 		}
 
@@ -317,20 +309,65 @@ func (i *Ingester) flushChunks(ctx context.Context, fp model.Fingerprint, labelP
 			return err
 		}
 
-		reason := func() string {
-			chunkMtx.Lock()
-			defer chunkMtx.Unlock()
+		i.reportFlushedChunkStatistics(&ch, c, sizePerTenant, countPerTenant, flushReason(c, chunkMtx))
+		i.markChunkAsFlushed(cs[j], chunkMtx)
+	}
 
-			return c.reason
-		}()
+	return nil
+}
 
-		i.reportFlushedChunkStatistics(&ch, c, sizePerTenant, countPerTenant, reason)
-		i.markChunkAsFlushed(cs[j], chunkMtx)
+// flushChunksInSegment flushes the chunks together with the chunks flushed concurrently for the tenant in a segment
+// object.
+func (i *Ingester) flushChunksInSegment(ctx context.Context, userID string, fp model.Fingerprint, metric labels.Labels, cs []*chunkDesc, chunkMtx sync.Locker, sizePerTenant, countPerTenant prometheus.Counter) error {
+	chunks := make([]chunk.Chunk, 0, len(cs))
+	for _, c := range cs {
+		ch, err := i.closeAndEncodeChunk(ctx, userID, fp, metric, c, chunkMtx)
+		if err != nil {
+			return err
+		}
+		chunks = append(chunks, ch)
 	}
 
+	if err := i.segmentFlusher.Flush(ctx, userID, chunks); err != nil {
+		return err
+	}
+	i.metrics.flushedChunksStats.Inc(int64(len(chunks)))
+
+	for j, c := range cs {
+		i.reportFlushedChunkStatistics(&chunks[j], c, sizePerTenant, countPerTenant, flushReason(c, chunkMtx))
+		i.markChunkAsFlushed(c, chunkMtx)
+	}
 	return nil
 }
 
+// closeAndEncodeChunk closes the chunk of the given chunkDesc and encodes it in a chunk.Chunk ready to be flushed.
+func (i *Ingester) closeAndEncodeChunk(ctx context.Context, userID string, fp model.Fingerprint, metric labels.Labels, c *chunkDesc, chunkMtx sync.Locker) (chunk.Chunk, error) {
+	if err := i.closeChunk(c, chunkMtx); err != nil {
+		return chunk.Chunk{}, fmt.Errorf("chunk close for flushing: %w", err)
+	}
+
+	firstTime, lastTime := util.RoundToMilliseconds(c.chunk.Bounds())
+	ch := chunk.NewChunk(
+		userID, fp, metric,
+		chunkenc.NewFacade(c.chunk, i.cfg.BlockSize, i.cfg.TargetChunkSize),
+		firstTime,
+		lastTime,
+	)
+
+	// encodeChunk mutates the chunk so we must pass by reference
+	if err := i.encodeChunk(ctx, &ch, c); err != nil {
+		return chunk.Chunk{}, err
+	}
+	return ch, nil
+}
+
+func flushReason(c *chunkDesc, chunkMtx sync.Locker) string {
+	chunkMtx.Lock()
+	defer chunkMtx.Unlock()
+
+	return c.reason
+}
+
 // markChunkAsFlushed mark a chunk to make sure it won't be flushed if this operation fails.
 func (i *Ingester) markChunkAsFlushed(desc *chunkDesc, chunkMtx sync.Locker) {
 	chunkMtx.Lock()
@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"math"
 	"math/rand"
 	"net/http"
 	"os"
@@ -30,6 +31,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
+	"go.uber.org/atomic"
 	"google.golang.org/grpc/health/grpc_health_v1"
 
 	"github.com/grafana/loki/v3/pkg/analytics"
@@ -63,10 +65,13 @@ const (
 	shutdownMarkerFilename = "shutdown-requested.txt"
 )
 
-// ErrReadOnly is returned when the ingester is shutting down and a push was
-// attempted.
 var (
+	// ErrReadOnly is returned when the ingester is shutting down and a push was
+	// attempted.
 	ErrReadOnly = errors.New("Ingester is shutting down")
+	// ErrReplaying is returned when the ingester joined the ring before replaying
+	// its WAL and a push or a query was attempted before the replay finished.
+	ErrReplaying = errors.New("Ingester is replaying its WAL")
 
 	compressionStats   = analytics.NewString("ingester_compression")
 	targetSizeStats    = analytics.NewInt("ingester_target_size_bytes")
@@ -104,6 +109,8 @@ type Config struct {
 
 	WAL WALConfig `yaml:"wal,omitempty" doc:"description=The ingester WAL (Write Ahead Log) records incoming logs and stores them on the local file systems in order to guarantee persistence of acknowledged data in the event of a process crash."`
 
+	SegmentFlush SegmentFlushConfig `yaml:"segment_flush" category:"experimental" doc:"description=Configures flushing the chunks of a tenant together in segment objects, to reduce the number of objects written to the object store."`
+
 	ChunkFilterer          chunk.RequestChunkFilterer     `yaml:"-"`
 	PipelineWrapper        lokilog.PipelineWrapper        `yaml:"-"`
 	SampleExtractorWrapper lokilog.SampleExtractorWrapper `yaml:"-"`
@@ -122,6 +129,7 @@ type Config struct {
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	cfg.LifecyclerConfig.RegisterFlags(f, util_log.Logger)
 	cfg.WAL.RegisterFlags(f)
+	cfg.SegmentFlush.RegisterFlags(f)
 
 	f.IntVar(&cfg.ConcurrentFlushes, "ingester.concurrent-flushes", 32, "How many flushes can happen concurrently from each stream.")
 	f.DurationVar(&cfg.FlushCheckPeriod, "ingester.flush-check-period", 30*time.Second, "How often should the ingester see if there are any blocks to flush. The first flush check is delayed by a random time up to 0.8x the flush check period. Additionally, there is +/- 1% jitter added to the interval.")
@@ -153,10 +161,18 @@ func (cfg *Config) Validate() error {
 		return err
 	}
 
+	if err = cfg.SegmentFlush.Validate(); err != nil {
+		return err
+	}
+
 	if cfg.IndexShards <= 0 {
 		return fmt.Errorf("invalid ingester index shard factor: %d", cfg.IndexShards)
 	}
 
+	if cfg.WAL.Enabled && cfg.WAL.JoinRingBeforeReplay && (cfg.LifecyclerConfig.JoinAfter > 0 || cfg.LifecyclerConfig.ObservePeriod > 0) {
+		return errors.New("joining the ring before replaying the WAL requires the ingester join after and observe period to be 0")
+	}
+
 	return nil
 }
 
@@ -231,10 +247,18 @@ type Ingester struct {
 	// Only used by WAL & flusher to coordinate backpressure during replay.
 	replayController *replayController
 
+	// Only used when the ingester joins the ring before replaying the WAL.
+	// replaying is set until the replay is finished, and replayErr receives the error failing the replay.
+	replaying atomic.Bool
+	replayErr chan error
+
 	metrics *ingesterMetrics
 
 	wal WAL
 
+	// Only set when chunks are flushed in segment objects.
+	segmentFlusher *segmentFlusher
+
 	chunkFilter      chunk.RequestChunkFilterer
 	extractorWrapper lokilog.SampleExtractorWrapper
 	pipelineWrapper  lokilog.PipelineWrapper
@@ -276,6 +300,14 @@ func New(cfg Config, clientConfig client.Config, store Store, limits Limits, con
 	}
 	i.replayController = newReplayController(metrics, cfg.WAL, &replayFlusher{i})
 
+	if cfg.SegmentFlush.Enabled {
+		writer, ok := store.(stores.SegmentWriter)
+		if !ok {
+			return nil, fmt.Errorf("segment flush is enabled but the store doesn't support writing segments")
+		}
+		i.segmentFlusher = newSegmentFlusher(cfg.SegmentFlush, writer, cfg.FlushOpTimeout, metrics)
+	}
+
 	if cfg.WAL.Enabled {
 		if err := os.MkdirAll(cfg.WAL.Dir, os.ModePerm); err != nil {
 			// Best effort try to make path absolute for easier debugging.
@@ -294,7 +326,15 @@ func New(cfg Config, clientConfig client.Config, store Store, limits Limits, con
 	}
 	i.wal = wal
 
-	i.lifecycler, err = ring.NewLifecycler(cfg.LifecyclerConfig, i, "ingester", RingKey, !cfg.WAL.Enabled || cfg.WAL.FlushOnShutdown, logger, prometheus.WrapRegistererWithPrefix(metricsNamespace+"_", registerer))
+	lifecyclerCfg := cfg.LifecyclerConfig
+	if cfg.WAL.Enabled && cfg.WAL.JoinRingBeforeReplay {
+		// The lifecycler joins the ring in the JOINING state and observes its tokens before switching to ACTIVE.
+		// Observing them for longer than any replay leaves switching to ACTIVE to the ingester once the replay is finished.
+		lifecyclerCfg.ObservePeriod = math.MaxInt64
+		i.replayErr = make(chan error, 1)
+	}
+
+	i.lifecycler, err = ring.NewLifecycler(lifecyclerCfg, i, "ingester", RingKey, !cfg.WAL.Enabled || cfg.WAL.FlushOnShutdown, logger, prometheus.WrapRegistererWithPrefix(metricsNamespace+"_", registerer))
 	if err != nil {
 		return nil, err
 	}
@@ -409,90 +449,167 @@ func (i *Ingester) setupAutoForget() {
 }
 
 func (i *Ingester) starting(ctx context.Context) error {
-	if i.cfg.WAL.Enabled {
-		start := time.Now()
-
-		// Ignore retain period during wal replay.
-		oldRetain := i.cfg.RetainPeriod
-		i.cfg.RetainPeriod = 0
+	if i.cfg.WAL.Enabled && i.cfg.WAL.JoinRingBeforeReplay {
+		return i.startingBeforeReplay(ctx)
+	}
 
-		// Disable the in process stream limit checks while replaying the WAL.
-		// It is re-enabled in the recover's Close() method.
-		i.limiter.DisableForWALReplay()
+	if i.cfg.WAL.Enabled {
+		if err := i.replayWAL(ctx); err != nil {
+			return err
+		}
+	}
 
-		recoverer := newIngesterRecoverer(i)
+	i.InitFlushQueues()
 
-		i.metrics.walReplayActive.Set(1)
+	if err := i.startLifecycler(ctx); err != nil {
+		return err
+	}
 
-		endReplay := func() func() {
-			var once sync.Once
-			return func() {
-				once.Do(func() {
-					level.Info(i.logger).Log("msg", "closing recoverer")
-					recoverer.Close()
+	// start our loop
+	i.loopDone.Add(1)
+	go i.loop()
+	return nil
+}
 
-					elapsed := time.Since(start)
+// startingBeforeReplay joins the ring before replaying the WAL, so that the ingester is ready while the replay is in
+// progress. The ingester stays JOINING and rejects pushes and queries until the replay is finished, so distributors
+// write to the other replicas of its streams meanwhile.
+func (i *Ingester) startingBeforeReplay(ctx context.Context) error {
+	i.replaying.Store(true)
 
-					i.metrics.walReplayActive.Set(0)
-					i.metrics.walReplayDuration.Set(elapsed.Seconds())
-					i.cfg.RetainPeriod = oldRetain
-					level.Info(i.logger).Log("msg", "WAL recovery finished", "time", elapsed.String())
-				})
-			}
-		}()
-		defer endReplay()
+	if err := i.startLifecycler(ctx); err != nil {
+		return err
+	}
 
-		level.Info(i.logger).Log("msg", "recovering from checkpoint")
-		checkpointReader, checkpointCloser, err := newCheckpointReader(i.cfg.WAL.Dir, i.logger)
-		if err != nil {
-			return err
+	// The loop starts once the replay is finished, and stopping the ingester waits for the replay to finish.
+	i.loopDone.Add(1)
+	go func() {
+		err := i.replayWAL(ctx)
+		// The flush queues are initialised after the replay, which flushes them itself when it reaches the memory ceiling.
+		i.InitFlushQueues()
+		if err == nil {
+			err = i.activateAfterReplay(ctx)
 		}
-		defer checkpointCloser.Close()
-
-		checkpointRecoveryErr := RecoverCheckpoint(checkpointReader, recoverer)
-		if checkpointRecoveryErr != nil {
-			i.metrics.walCorruptionsTotal.WithLabelValues(walTypeCheckpoint).Inc()
-			level.Error(i.logger).Log(
-				"msg",
-				`Recovered from checkpoint with errors. Some streams were likely not recovered due to WAL checkpoint file corruptions (or WAL file deletions while Loki is running). No administrator action is needed and data loss is only a possibility if more than (replication factor / 2 + 1) ingesters suffer from this.`,
-				"elapsed", time.Since(start).String(),
-			)
+		if err != nil {
+			i.loopDone.Done()
+			i.replayErr <- err
+			return
 		}
-		level.Info(i.logger).Log(
-			"msg", "recovered WAL checkpoint recovery finished",
-			"elapsed", time.Since(start).String(),
-			"errors", checkpointRecoveryErr != nil,
-		)
 
-		level.Info(i.logger).Log("msg", "recovering from WAL")
-		segmentReader, segmentCloser, err := wal.NewWalReader(i.cfg.WAL.Dir, -1)
-		if err != nil {
-			return err
+		i.replaying.Store(false)
+		i.loop()
+	}()
+	return nil
+}
+
+// activateAfterReplay switches the ingester from JOINING to ACTIVE in the ring once its WAL is replayed.
+func (i *Ingester) activateAfterReplay(ctx context.Context) error {
+	// The lifecycler joins the ring right after it starts, which may not have happened yet for short replays.
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for i.lifecycler.GetState() == ring.PENDING {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
 		}
-		defer segmentCloser.Close()
-
-		segmentRecoveryErr := RecoverWAL(ctx, segmentReader, recoverer)
-		if segmentRecoveryErr != nil {
-			i.metrics.walCorruptionsTotal.WithLabelValues(walTypeSegment).Inc()
-			level.Error(i.logger).Log(
-				"msg",
-				"Recovered from WAL segments with errors. Some streams and/or entries were likely not recovered due to WAL segment file corruptions (or WAL file deletions while Loki is running). No administrator action is needed and data loss is only a possibility if more than (replication factor / 2 + 1) ingesters suffer from this.",
-				"elapsed", time.Since(start).String(),
-			)
+	}
+
+	// The ingester may already be ACTIVE if it was found in the ring with its tokens.
+	if i.lifecycler.GetState() != ring.JOINING {
+		return nil
+	}
+	level.Info(i.logger).Log("msg", "WAL replay finished, switching to ACTIVE in the ring")
+	return i.lifecycler.ChangeState(ctx, ring.ACTIVE)
+}
+
+// replayWAL recovers the in-memory streams from the last checkpoint and the WAL segments written after it,
+// then starts the WAL.
+func (i *Ingester) replayWAL(ctx context.Context) error {
+	start := time.Now()
+
+	// Ignore retain period during wal replay.
+	oldRetain := i.cfg.RetainPeriod
+	i.cfg.RetainPeriod = 0
+
+	// Disable the in process stream limit checks while replaying the WAL.
+	// It is re-enabled in the recover's Close() method.
+	i.limiter.DisableForWALReplay()
+
+	recoverer := newIngesterRecoverer(i)
+
+	i.metrics.walReplayActive.Set(1)
+
+	endReplay := func() func() {
+		var once sync.Once
+		return func() {
+			once.Do(func() {
+				level.Info(i.logger).Log("msg", "closing recoverer")
+				recoverer.Close()
+
+				elapsed := time.Since(start)
+
+				i.metrics.walReplayActive.Set(0)
+				i.metrics.walReplayDuration.Set(elapsed.Seconds())
+				i.cfg.RetainPeriod = oldRetain
+				level.Info(i.logger).Log("msg", "WAL recovery finished", "time", elapsed.String())
+			})
 		}
-		level.Info(i.logger).Log(
-			"msg", "WAL segment recovery finished",
+	}()
+	defer endReplay()
+
+	level.Info(i.logger).Log("msg", "recovering from checkpoint")
+	checkpointReader, checkpointCloser, err := newCheckpointReader(i.cfg.WAL.Dir, i.logger)
+	if err != nil {
+		return err
+	}
+	defer checkpointCloser.Close()
+
+	checkpointRecoveryErr := RecoverCheckpoint(checkpointReader, recoverer)
+	if checkpointRecoveryErr != nil {
+		i.metrics.walCorruptionsTotal.WithLabelValues(walTypeCheckpoint).Inc()
+		level.Error(i.logger).Log(
+			"msg",
+			`Recovered from checkpoint with errors. Some streams were likely not recovered due to WAL checkpoint file corruptions (or WAL file deletions while Loki is running). No administrator action is needed and data loss is only a possibility if more than (replication factor / 2 + 1) ingesters suffer from this.`,
 			"elapsed", time.Since(start).String(),
-			"errors", segmentRecoveryErr != nil,
 		)
+	}
+	level.Info(i.logger).Log(
+		"msg", "recovered WAL checkpoint recovery finished",
+		"elapsed", time.Since(start).String(),
+		"errors", checkpointRecoveryErr != nil,
+	)
 
-		endReplay()
+	level.Info(i.logger).Log("msg", "recovering from WAL")
+	segmentReader, segmentCloser, err := wal.NewWalReader(i.cfg.WAL.Dir, -1)
+	if err != nil {
+		return err
+	}
+	defer segmentCloser.Close()
 
-		i.wal.Start()
+	segmentRecoveryErr := RecoverWAL(ctx, segmentReader, recoverer)
+	if segmentRecoveryErr != nil {
+		i.metrics.walCorruptionsTotal.WithLabelValues(walTypeSegment).Inc()
+		level.Error(i.logger).Log(
+			"msg",
+			"Recovered from WAL segments with errors. Some streams and/or entries were likely not recovered due to WAL segment file corruptions (or WAL file deletions while Loki is running). No administrator action is needed and data loss is only a possibility if more than (replication factor / 2 + 1) ingesters suffer from this.",
+			"elapsed", time.Since(start).String(),
+		)
 	}
+	level.Info(i.logger).Log(
+		"msg", "WAL segment recovery finished",
+		"elapsed", time.Since(start).String(),
+		"errors", segmentRecoveryErr != nil,
+	)
 
-	i.InitFlushQueues()
+	endReplay()
+
+	i.wal.Start()
+	return nil
+}
 
+// startLifecycler starts the lifecycler, which joins the ring.
+func (i *Ingester) startLifecycler(ctx context.Context) error {
 	// pass new context to lifecycler, so that it doesn't stop automatically when Ingester's service context is done
 	err := i.lifecycler.StartAsync(context.Background())
 	if err != nil {
@@ -514,10 +631,6 @@ func (i *Ingester) starting(ctx context.Context) error {
 		level.Info(i.logger).Log("msg", "detected existing shutdown marker, setting unregister and flush on shutdown", "path", shutdownMarkerPath)
 		i.setPrepareShutdown()
 	}
-
-	// start our loop
-	i.loopDone.Add(1)
-	go i.loop()
 	return nil
 }
 
@@ -529,6 +642,8 @@ func (i *Ingester) running(ctx context.Context) error {
 	// stop
 	case err := <-i.lifecyclerWatcher.Chan():
 		serviceError = fmt.Errorf("lifecycler failed: %w", err)
+	case err := <-i.replayErr:
+		serviceError = fmt.Errorf("WAL replay failed: %w", err)
 	}
 
 	// close tailers before stopping our loop
@@ -827,12 +942,15 @@ func (i *Ingester) Push(ctx context.Context, req *logproto.PushRequest) (*logpro
 		return nil, err
 	} else if i.readonly {
 		return nil, ErrReadOnly
+	} else if i.replaying.Load() {
+		return nil, ErrReplaying
 	}
 
 	instance, err := i.GetOrCreateInstance(instanceID)
 	if err != nil {
 		return &logproto.PushResponse{}, err
 	}
+	ctx = writefailures.ExtractRejectionsPushFromGRPCRequest(ctx)
 	return &logproto.PushResponse{}, instance.Push(ctx, req)
 }
 
@@ -875,6 +993,10 @@ func (i *Ingester) GetOrCreateInstance(instanceID string) (*instance, error) { /
 
 // Query the ingests for log streams matching a set of matchers.
 func (i *Ingester) Query(req *logproto.QueryRequest, queryServer logproto.Querier_QueryServer) error {
+	if i.replaying.Load() {
+		return ErrReplaying
+	}
+
 	// initialize stats collection for ingester queries.
 	_, ctx := stats.NewContext(queryServer.Context())
 	_, ctx = metadata.NewContext(ctx)
@@ -935,6 +1057,10 @@ func (i *Ingester) Query(req *logproto.QueryRequest, queryServer logproto.Querie
 
 // QuerySample the ingesters for series from logs matching a set of matchers.
 func (i *Ingester) QuerySample(req *logproto.SampleQueryRequest, queryServer logproto.Querier_QuerySampleServer) error {
+	if i.replaying.Load() {
+		return ErrReplaying
+	}
+
 	// initialize stats collection for ingester queries.
 	_, ctx := stats.NewContext(queryServer.Context())
 	_, ctx = metadata.NewContext(ctx)
@@ -1015,6 +1141,10 @@ func (i *Ingester) asyncStoreMaxLookBack() time.Duration {
 
 // GetChunkIDs is meant to be used only when using an async store like boltdb-shipper or tsdb.
 func (i *Ingester) GetChunkIDs(ctx context.Context, req *logproto.GetChunkIDsRequest) (*logproto.GetChunkIDsResponse, error) {
+	if i.replaying.Load() {
+		return nil, ErrReplaying
+	}
+
 	orgID, err := tenant.TenantID(ctx)
 	if err != nil {
 		return nil, err
@@ -1051,6 +1181,10 @@ func (i *Ingester) GetChunkIDs(ctx context.Context, req *logproto.GetChunkIDsReq
 	for _, chunks := range chunksGroups {
 		for _, chk := range chunks {
 			resp.ChunkIDs = append(resp.ChunkIDs, s.ExternalKey(chk.ChunkRef))
+			if chk.SegmentLength > 0 {
+				ref := chk.ChunkRef
+				resp.SegmentRefs = append(resp.SegmentRefs, &ref)
+			}
 		}
 	}
 
@@ -1059,6 +1193,10 @@ func (i *Ingester) GetChunkIDs(ctx context.Context, req *logproto.GetChunkIDsReq
 
 // Label returns the set of labels for the stream this ingester knows about.
 func (i *Ingester) Label(ctx context.Context, req *logproto.LabelRequest) (*logproto.LabelResponse, error) {
+	if i.replaying.Load() {
+		return nil, ErrReplaying
+	}
+
 	userID, err := tenant.TenantID(ctx)
 	if err != nil {
 		return nil, err
@@ -1129,6 +1267,10 @@ func (i *Ingester) Label(ctx context.Context, req *logproto.LabelRequest) (*logp
 
 // Series queries the ingester for log stream identifiers (label sets) matching a set of matchers
 func (i *Ingester) Series(ctx context.Context, req *logproto.SeriesRequest) (*logproto.SeriesResponse, error) {
+	if i.replaying.Load() {
+		return nil, ErrReplaying
+	}
+
 	instanceID, err := tenant.TenantID(ctx)
 	if err != nil {
 		return nil, err
@@ -1142,6 +1284,10 @@ func (i *Ingester) Series(ctx context.Context, req *logproto.SeriesRequest) (*lo
 }
 
 func (i *Ingester) GetStats(ctx context.Context, req *logproto.IndexStatsRequest) (*logproto.IndexStatsResponse, error) {
+	if i.replaying.Load() {
+		return nil, ErrReplaying
+	}
+
 	sp := opentracing.SpanFromContext(ctx)
 
 	user, err := tenant.TenantID(ctx)
@@ -1201,6 +1347,10 @@ func (i *Ingester) GetStats(ctx context.Context, req *logproto.IndexStatsRequest
 }
 
 func (i *Ingester) GetVolume(ctx context.Context, req *logproto.VolumeRequest) (*logproto.VolumeResponse, error) {
+	if i.replaying.Load() {
+		return nil, ErrReplaying
+	}
+
 	user, err := tenant.TenantID(ctx)
 	if err != nil {
 		return nil, err
@@ -1256,6 +1406,15 @@ func (i *Ingester) CheckReady(ctx context.Context) error {
 	if s := i.State(); s != services.Running && s != services.Stopping {
 		return fmt.Errorf("ingester not ready: %v", s)
 	}
+	if i.replaying.Load() {
+		// The ingester joined the ring before replaying the WAL, and is ready as soon as it is JOINING in the ring.
+		// The lifecycler restores the state of instances found in the ring, so the ingester may be ACTIVE and fail the
+		// pushes and queries it receives until the replay is finished: it isn't ready in that case.
+		if s := i.lifecycler.GetState(); s != ring.JOINING {
+			return fmt.Errorf("ingester not ready: replaying WAL in ring state %v", s)
+		}
+		return nil
+	}
 	return i.lifecycler.CheckReady(ctx)
 }
 
@@ -1280,6 +1439,10 @@ func (i *Ingester) getInstances() []*instance {
 
 // Tail logs matching given query
 func (i *Ingester) Tail(req *logproto.TailRequest, queryServer logproto.Querier_TailServer) error {
+	if i.replaying.Load() {
+		return ErrReplaying
+	}
+
 	select {
 	case <-i.tailersQuit:
 		return errors.New("Ingester is stopping")
@@ -1379,6 +1542,10 @@ func (i *Ingester) GetDetectedFields(_ context.Context, _ *logproto.DetectedFiel
 
 // GetDetectedLabels returns map of detected labels and unique values from this ingester
 func (i *Ingester) GetDetectedLabels(ctx context.Context, req *logproto.DetectedLabelsRequest) (*logproto.LabelToValuesResponse, error) {
+	if i.replaying.Load() {
+		return nil, ErrReplaying
+	}
+
 	userID, err := tenant.TenantID(ctx)
 	if err != nil {
 		return nil, err
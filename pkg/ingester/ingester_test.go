@@ -150,7 +150,7 @@ func TestIngester_GetStreamRates_Correctness(t *testing.T) {
 
 	for idx := 0; idx < 100; idx++ {
 		// push 100 different streams.
-		i.streamRateCalculator.Record("fake", uint64(idx), uint64(idx), 10)
+		i.streamRateCalculator.Record("fake", uint64(idx), uint64(idx), 10, 0)
 	}
 
 	i.streamRateCalculator.updateRates()
@@ -178,7 +178,7 @@ func BenchmarkGetStreamRatesAllocs(b *testing.B) {
 	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
 
 	for idx := 0; idx < 1000; idx++ {
-		i.streamRateCalculator.Record("fake", uint64(idx), uint64(idx), 10)
+		i.streamRateCalculator.Record("fake", uint64(idx), uint64(idx), 10, 0)
 	}
 	i.streamRateCalculator.updateRates()
 
@@ -415,6 +415,49 @@ func TestIngesterStreamLimitExceeded(t *testing.T) {
 	require.Contains(t, err.Error(), expectedLabels.String())
 }
 
+func TestIngesterRejectionsStreamIgnoresStreamLimits(t *testing.T) {
+	ingesterConfig := defaultIngesterTestConfig(t)
+	defaultLimits := defaultLimitsTestConfig()
+	defaultLimits.MaxLocalStreamsPerUser = 1
+	defaultLimits.StreamBudgets = []validation.StreamBudget{
+		{Label: "reason", MaxValuesPerIngester: 1},
+	}
+	require.NoError(t, defaultLimits.Validate())
+	overrides, err := validation.NewOverrides(defaultLimits, nil)
+	require.NoError(t, err)
+
+	store := &mockStore{
+		chunks: map[string][]chunk.Chunk{},
+	}
+
+	i, err := New(ingesterConfig, client.Config{}, store, overrides, runtime.DefaultTenantConfigs(), nil, writefailures.Cfg{}, constants.Loki, log.NewNopLogger())
+	require.NoError(t, err)
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+
+	push := func(ctx context.Context, ls string) error {
+		_, err := i.Push(ctx, &logproto.PushRequest{
+			Streams: []logproto.Stream{
+				{
+					Labels:  ls,
+					Entries: []logproto.Entry{{Timestamp: time.Unix(0, 0), Line: "line"}},
+				},
+			},
+		})
+		return err
+	}
+
+	// the tenant is at its stream limit
+	ctx := user.InjectOrgID(context.Background(), "test")
+	require.NoError(t, push(ctx, `{foo="bar"}`))
+	require.Error(t, push(ctx, `{__loki_rejections__="true", reason="rate_limited"}`))
+
+	// the distributors mark the pushes of the rejections stream in the gRPC metadata
+	md, _ := metadata.FromOutgoingContext(writefailures.InjectRejectionsPushIntoGRPCRequest(ctx))
+	rejectionsCtx := metadata.NewIncomingContext(ctx, md)
+	require.NoError(t, push(rejectionsCtx, `{__loki_rejections__="true", reason="rate_limited"}`))
+	require.NoError(t, push(rejectionsCtx, `{__loki_rejections__="true", reason="stream_limit"}`))
+}
+
 type mockStore struct {
 	mtx    sync.Mutex
 	chunks map[string][]chunk.Chunk
@@ -469,6 +512,10 @@ func (s *mockStore) LabelNamesForMetricName(_ context.Context, _ string, _, _ mo
 	return nil, nil
 }
 
+func (s *mockStore) StructuredMetadataKeys(_ context.Context, _ string, _, _ model.Time, _ ...*labels.Matcher) ([]string, error) {
+	return nil, nil
+}
+
 func (s *mockStore) GetChunkFetcher(_ model.Time) *fetcher.Fetcher {
 	return nil
 }
@@ -96,6 +96,9 @@ type instance struct {
 	index  *index.Multi
 	mapper *FpMapper // using of mapper no longer needs mutex because reading from streams is lock-free
 
+	// streamBudgets counts the streams for the stream budgets, and adds them to and removes them from the index.
+	streamBudgets *streamBudgetCounts
+
 	instanceID string
 
 	streamsCreatedTotal prometheus.Counter
@@ -155,6 +158,8 @@ func newInstance(
 		index:      invertedIndex,
 		instanceID: instanceID,
 
+		streamBudgets: newStreamBudgetCounts(invertedIndex),
+
 		streamsCreatedTotal: streamsCreatedTotal.WithLabelValues(instanceID),
 		streamsRemovedTotal: streamsRemovedTotal.WithLabelValues(instanceID),
 
@@ -282,7 +287,10 @@ func (i *instance) createStream(ctx context.Context, pushReqStream logproto.Stre
 		return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
 	}
 
-	if record != nil {
+	// the rejections stream is written even when the tenant is at its stream limits, to report the rejected streams.
+	rejectionsPush := writefailures.IsRejectionsPush(ctx)
+
+	if record != nil && !rejectionsPush {
 		err = i.limiter.AssertMaxStreamsPerUser(i.instanceID, i.streams.Len())
 	}
 
@@ -308,9 +316,40 @@ func (i *instance) createStream(ctx context.Context, pushReqStream logproto.Stre
 		return nil, httpgrpc.Errorf(http.StatusTooManyRequests, validation.StreamLimitErrorMsg, labels, i.instanceID)
 	}
 
+	if record != nil && !rejectionsPush {
+		if err := i.limiter.AssertStreamBudgets(i.instanceID, labels, i.streamBudgets); err != nil {
+			var budgetErr *streamBudgetError
+			if !errors.As(err, &budgetErr) {
+				return nil, fmt.Errorf("failed to check stream budgets: %w", err)
+			}
+
+			if i.configs.LogStreamCreation(i.instanceID) {
+				level.Debug(util_log.Logger).Log(
+					"msg", "failed to create stream, exceeded stream budget",
+					"org_id", i.instanceID,
+					"err", err,
+					"stream", pushReqStream.Labels,
+				)
+			}
+
+			bytes := 0
+			for _, e := range pushReqStream.Entries {
+				bytes += len(e.Line)
+			}
+			validation.DiscardedSamples.WithLabelValues(validation.StreamBudgetLimit, i.instanceID).Add(float64(len(pushReqStream.Entries)))
+			validation.DiscardedBytes.WithLabelValues(validation.StreamBudgetLimit, i.instanceID).Add(float64(bytes))
+			validation.DiscardedStreamBudgetSamples.WithLabelValues(budgetErr.budget, i.instanceID).Add(float64(len(pushReqStream.Entries)))
+			validation.DiscardedStreamBudgetBytes.WithLabelValues(budgetErr.budget, i.instanceID).Add(float64(bytes))
+			if i.customStreamsTracker != nil {
+				i.customStreamsTracker.DiscardedBytesAdd(ctx, i.instanceID, validation.StreamBudgetLimit, labels, float64(bytes))
+			}
+			return nil, httpgrpc.Errorf(http.StatusTooManyRequests, validation.StreamBudgetLimitErrorMsg, budgetErr.budget, labels, budgetErr.limit, i.instanceID)
+		}
+	}
+
 	fp := i.getHashForLabels(labels)
 
-	sortedLabels := i.index.Add(logproto.FromLabelsToLabelAdapters(labels), fp)
+	sortedLabels := i.streamBudgets.add(labels, fp)
 
 	chunkfmt, headfmt, err := i.chunkFormatAt(minTs(&pushReqStream))
 	if err != nil {
@@ -348,7 +387,7 @@ func (i *instance) createStream(ctx context.Context, pushReqStream logproto.Stre
 }
 
 func (i *instance) createStreamByFP(ls labels.Labels, fp model.Fingerprint) (*stream, error) {
-	sortedLabels := i.index.Add(logproto.FromLabelsToLabelAdapters(ls), fp)
+	sortedLabels := i.streamBudgets.add(ls, fp)
 
 	chunkfmt, headfmt, err := i.chunkFormatAt(model.Now())
 	if err != nil {
@@ -399,7 +438,7 @@ func (i *instance) getOrCreateStream(ctx context.Context, pushReqStream logproto
 // removeStream removes a stream from the instance.
 func (i *instance) removeStream(s *stream) {
 	if i.streams.Delete(s) {
-		i.index.Delete(s.labels, s.fp)
+		i.streamBudgets.remove(s.labels, s.fp)
 		i.streamsRemovedTotal.Inc()
 		memoryStreams.WithLabelValues(i.instanceID).Dec()
 		memoryStreamsLabelsBytes.Sub(float64(len(s.labels.String())))
@@ -20,6 +20,7 @@ import (
 
 	"github.com/grafana/dskit/flagext"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/require"
@@ -96,6 +97,52 @@ func TestLabelsCollisions(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestStreamBudgets(t *testing.T) {
+	limitsCfg := defaultLimitsTestConfig()
+	limitsCfg.StreamBudgets = []validation.StreamBudget{
+		{Selector: `{namespace="foo"}`, MaxStreams: 2},
+		{Label: "pod_ip", MaxValuesPerIngester: 2},
+	}
+	require.NoError(t, limitsCfg.Validate())
+	limits, err := validation.NewOverrides(limitsCfg, nil)
+	require.NoError(t, err)
+	limiter := NewLimiter(limits, NilMetrics, &ringCountMock{count: 1}, 1)
+
+	i, err := newInstance(defaultConfig(), defaultPeriodConfigs, "test", limiter, loki_runtime.DefaultTenantConfigs(), noopWAL{}, NilMetrics, &OnceSwitch{}, nil, nil, nil, NewStreamRateCalculator(), nil)
+	require.Nil(t, err)
+
+	push := func(ls string) error {
+		return i.Push(context.Background(), &logproto.PushRequest{Streams: []logproto.Stream{
+			{Labels: ls, Entries: entries(1, time.Now().Add(-time.Minute))},
+		}})
+	}
+
+	require.NoError(t, push(`{namespace="foo", app="a"}`))
+	require.NoError(t, push(`{namespace="foo", app="b"}`))
+	err = push(`{namespace="foo", app="c"}`)
+	require.ErrorContains(t, err, `Stream budget {namespace="foo"} exceeded when trying to create stream {app="c", namespace="foo"} (limit: 2)`)
+	require.NoError(t, push(`{namespace="bar", app="c"}`))
+	// existing streams can still be pushed to.
+	require.NoError(t, push(`{namespace="foo", app="a"}`))
+
+	require.NoError(t, push(`{app="a", namespace="bar", pod_ip="10.0.0.1"}`))
+	require.NoError(t, push(`{namespace="bar", pod_ip="10.0.0.2", app="a"}`))
+	// a new stream with an existing value doesn't add a value.
+	require.NoError(t, push(`{namespace="bar", pod_ip="10.0.0.2", app="b"}`))
+	err = push(`{namespace="bar", pod_ip="10.0.0.3", app="a"}`)
+	require.ErrorContains(t, err, `Stream budget pod_ip exceeded when trying to create stream {app="a", namespace="bar", pod_ip="10.0.0.3"} (limit: 2)`)
+
+	require.Equal(t, 1.0, testutil.ToFloat64(validation.DiscardedStreamBudgetSamples.WithLabelValues("pod_ip", "test")))
+
+	// removing the only stream with a value frees it up for another value.
+	s, ok := i.streams.Load(`{app="a", namespace="bar", pod_ip="10.0.0.1"}`)
+	require.True(t, ok)
+	i.streams.WithLock(func() {
+		i.removeStream(s)
+	})
+	require.NoError(t, push(`{namespace="bar", pod_ip="10.0.0.3", app="a"}`))
+}
+
 func TestConcurrentPushes(t *testing.T) {
 	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
 	require.NoError(t, err)
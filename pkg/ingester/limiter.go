@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/prometheus/prometheus/model/labels"
 	"golang.org/x/time/rate"
 
 	"github.com/grafana/loki/v3/pkg/distributor/shardstreams"
@@ -26,6 +27,7 @@ type Limits interface {
 	UnorderedWrites(userID string) bool
 	MaxLocalStreamsPerUser(userID string) int
 	MaxGlobalStreamsPerUser(userID string) int
+	StreamBudgets(userID string) []validation.StreamBudget
 	PerStreamRateLimit(userID string) validation.RateLimit
 	ShardStreams(userID string) *shardstreams.Config
 }
@@ -111,6 +113,84 @@ func (l *Limiter) AssertMaxStreamsPerUser(userID string, streams int) error {
 	return fmt.Errorf(errMaxStreamsPerUserLimitExceeded, userID, streams, calculatedLimit, localLimit, globalLimit, adjustedGlobalLimit)
 }
 
+// streamBudgetError is returned when creating a stream would exceed a stream budget.
+type streamBudgetError struct {
+	budget string
+	limit  int
+}
+
+func (e *streamBudgetError) Error() string {
+	return fmt.Sprintf("stream budget %s exceeded, limit: %d", e.budget, e.limit)
+}
+
+// AssertStreamBudgets ensures that creating a stream with the given labels doesn't exceed the stream budgets of the
+// tenant, given the counts of its active streams, and returns a *streamBudgetError if it does.
+//
+// The max_streams limits of the selectors are converted to local limits. The max_values_per_ingester limits of the
+// labels already are local limits, because the streams with a value can be spread across all the ingesters.
+func (l *Limiter) AssertStreamBudgets(userID string, ls labels.Labels, counts *streamBudgetCounts) error {
+	l.mtx.RLock()
+	defer l.mtx.RUnlock()
+	if l.disabled {
+		return nil
+	}
+
+	for _, budget := range l.limits.StreamBudgets(userID) {
+		if budget.Label != "" {
+			value := ls.Get(budget.Label)
+			if value == "" || budget.MaxValuesPerIngester <= 0 {
+				continue
+			}
+
+			values, exists, err := counts.labelValues(budget.Label, value)
+			if err != nil {
+				return err
+			}
+			if values < budget.MaxValuesPerIngester || exists {
+				continue
+			}
+			return &streamBudgetError{budget: budget.Name(), limit: budget.MaxValuesPerIngester}
+		}
+
+		if len(budget.Matchers) == 0 || !matchesAll(budget.Matchers, ls) {
+			continue
+		}
+
+		limit := l.convertGlobalToLocalBudget(budget.MaxStreams)
+		if limit == 0 {
+			continue
+		}
+
+		streams, err := counts.streams(budget)
+		if err != nil {
+			return err
+		}
+		if streams >= limit {
+			return &streamBudgetError{budget: budget.Name(), limit: limit}
+		}
+	}
+
+	return nil
+}
+
+// convertGlobalToLocalBudget converts the global limit of a stream budget to a local limit, which is at least 1 so
+// that small budgets aren't ignored.
+func (l *Limiter) convertGlobalToLocalBudget(globalLimit int) int {
+	if limit := l.convertGlobalToLocalLimit(globalLimit); limit > 0 || l.ring.HealthyInstancesCount() == 0 {
+		return limit
+	}
+	return 1
+}
+
+func matchesAll(matchers []*labels.Matcher, ls labels.Labels) bool {
+	for _, m := range matchers {
+		if !m.Matches(ls.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
 func (l *Limiter) convertGlobalToLocalLimit(globalLimit int) int {
 	if globalLimit == 0 {
 		return 0
@@ -6,10 +6,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/time/rate"
 
+	"github.com/grafana/loki/v3/pkg/ingester/index"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
 	"github.com/grafana/loki/v3/pkg/validation"
 )
 
@@ -118,6 +122,133 @@ func TestLimiter_AssertMaxStreamsPerUser(t *testing.T) {
 	}
 }
 
+func TestLimiter_AssertStreamBudgets(t *testing.T) {
+	budgets := []validation.StreamBudget{
+		{
+			Selector:   `{namespace="foo"}`,
+			MaxStreams: 6,
+			Matchers:   []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "namespace", "foo")},
+		},
+		{Label: "pod_ip", MaxValuesPerIngester: 3},
+	}
+
+	tests := map[string]struct {
+		labels            labels.Labels
+		streams           []string
+		ringIngesterCount int
+		disabled          bool
+		expected          error
+	}{
+		"selector under the limit": {
+			labels:            labels.FromStrings("namespace", "foo"),
+			streams:           []string{`{namespace="foo", app="a"}`, `{namespace="foo", app="b"}`},
+			ringIngesterCount: 2,
+		},
+		"selector at the limit": {
+			labels:            labels.FromStrings("namespace", "foo"),
+			streams:           []string{`{namespace="foo", app="a"}`, `{namespace="foo", app="b"}`, `{namespace="foo", app="c"}`},
+			ringIngesterCount: 2,
+			expected:          &streamBudgetError{budget: `{namespace="foo"}`, limit: 3},
+		},
+		"selector not matching": {
+			labels:            labels.FromStrings("namespace", "bar"),
+			streams:           []string{`{namespace="foo", app="a"}`, `{namespace="foo", app="b"}`, `{namespace="foo", app="c"}`},
+			ringIngesterCount: 2,
+		},
+		"local selector limit is at least one": {
+			labels:            labels.FromStrings("namespace", "foo"),
+			streams:           []string{`{namespace="foo", app="a"}`},
+			ringIngesterCount: 10,
+			expected:          &streamBudgetError{budget: `{namespace="foo"}`, limit: 1},
+		},
+		"label with a new value under the limit": {
+			labels:            labels.FromStrings("namespace", "bar", "pod_ip", "10.0.0.2"),
+			streams:           []string{`{namespace="bar", pod_ip="10.0.0.1"}`},
+			ringIngesterCount: 1,
+		},
+		"label with a new value at the limit": {
+			labels:            labels.FromStrings("namespace", "bar", "pod_ip", "10.0.0.4"),
+			streams:           []string{`{namespace="bar", pod_ip="10.0.0.1"}`, `{namespace="bar", pod_ip="10.0.0.2"}`, `{namespace="bar", pod_ip="10.0.0.3"}`},
+			ringIngesterCount: 1,
+			expected:          &streamBudgetError{budget: "pod_ip", limit: 3},
+		},
+		"label with an existing value at the limit": {
+			labels:            labels.FromStrings("namespace", "bar", "pod_ip", "10.0.0.3"),
+			streams:           []string{`{namespace="bar", pod_ip="10.0.0.1"}`, `{namespace="bar", pod_ip="10.0.0.2"}`, `{namespace="bar", pod_ip="10.0.0.3"}`},
+			ringIngesterCount: 1,
+		},
+		"label limit isn't divided by the ingesters": {
+			labels:            labels.FromStrings("namespace", "bar", "pod_ip", "10.0.0.3"),
+			streams:           []string{`{namespace="bar", pod_ip="10.0.0.1"}`, `{namespace="bar", pod_ip="10.0.0.2"}`},
+			ringIngesterCount: 10,
+		},
+		"limiter disabled": {
+			labels:            labels.FromStrings("namespace", "foo"),
+			streams:           []string{`{namespace="foo", app="a"}`, `{namespace="foo", app="b"}`, `{namespace="foo", app="c"}`},
+			ringIngesterCount: 2,
+			disabled:          true,
+		},
+	}
+
+	for testName, testData := range tests {
+		testData := testData
+
+		t.Run(testName, func(t *testing.T) {
+			limits, err := validation.NewOverrides(validation.Limits{StreamBudgets: budgets}, nil)
+			require.NoError(t, err)
+
+			limiter := NewLimiter(limits, NilMetrics, &ringCountMock{count: testData.ringIngesterCount}, 1)
+			if testData.disabled {
+				limiter.DisableForWALReplay()
+			}
+
+			idx, err := index.NewMultiInvertedIndex(defaultPeriodConfigs, 1)
+			require.NoError(t, err)
+			counts := newStreamBudgetCounts(idx)
+			for i, s := range testData.streams {
+				ls, err := syntax.ParseLabels(s)
+				require.NoError(t, err)
+				counts.add(ls, model.Fingerprint(i))
+			}
+
+			assert.Equal(t, testData.expected, limiter.AssertStreamBudgets("test", testData.labels, counts))
+		})
+	}
+}
+
+func TestStreamBudgetCounts(t *testing.T) {
+	idx, err := index.NewMultiInvertedIndex(defaultPeriodConfigs, 1)
+	require.NoError(t, err)
+	counts := newStreamBudgetCounts(idx)
+
+	a, b := labels.FromStrings("namespace", "foo", "pod_ip", "10.0.0.1"), labels.FromStrings("namespace", "foo", "pod_ip", "10.0.0.2")
+	// the stream added before the budgets are checked is counted from the index.
+	counts.add(a, 1)
+
+	budget := validation.StreamBudget{Selector: `{namespace="foo"}`, Matchers: []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "namespace", "foo")}}
+	streams, err := counts.streams(budget)
+	require.NoError(t, err)
+	require.Equal(t, 1, streams)
+	values, exists, err := counts.labelValues("pod_ip", "10.0.0.2")
+	require.NoError(t, err)
+	require.Equal(t, 1, values)
+	require.False(t, exists)
+
+	counts.add(b, 2)
+	streams, _ = counts.streams(budget)
+	require.Equal(t, 2, streams)
+	values, exists, _ = counts.labelValues("pod_ip", "10.0.0.2")
+	require.Equal(t, 2, values)
+	require.True(t, exists)
+
+	counts.remove(a, 1)
+	streams, _ = counts.streams(budget)
+	require.Equal(t, 1, streams)
+	values, exists, _ = counts.labelValues("pod_ip", "10.0.0.1")
+	require.Equal(t, 1, values)
+	require.False(t, exists)
+}
+
 func TestLimiter_minNonZero(t *testing.T) {
 	t.Parallel()
 
@@ -49,6 +49,8 @@ type ingesterMetrics struct {
 	chunkEncodeTime               prometheus.Histogram
 	chunksFlushedPerReason        *prometheus.CounterVec
 	chunkLifespan                 prometheus.Histogram
+	segmentChunks                 prometheus.Histogram
+	segmentSize                   prometheus.Histogram
 	flushedChunksStats            *analytics.Counter
 	flushedChunksBytesStats       *analytics.Statistics
 	flushedChunksLinesStats       *analytics.Statistics
@@ -244,6 +246,20 @@ func newIngesterMetrics(r prometheus.Registerer, metricsNamespace string) *inges
 			// 1h -> 8hr
 			Buckets: prometheus.LinearBuckets(1, 1, 8),
 		}),
+		segmentChunks: promauto.With(r).NewHistogram(prometheus.HistogramOpts{
+			Namespace: constants.Loki,
+			Name:      "ingester_segment_chunks",
+			Help:      "Distribution of the number of chunks in flushed segments.",
+			// 1 -> 4096
+			Buckets: prometheus.ExponentialBuckets(1, 4, 7),
+		}),
+		segmentSize: promauto.With(r).NewHistogram(prometheus.HistogramOpts{
+			Namespace: constants.Loki,
+			Name:      "ingester_segment_size_bytes",
+			Help:      "Distribution of flushed segment sizes.",
+			// 64KB -> 64MB
+			Buckets: prometheus.ExponentialBuckets(64*1024, 4, 6),
+		}),
 		flushedChunksStats:            analytics.NewCounter("ingester_flushed_chunks"),
 		flushedChunksBytesStats:       analytics.NewStatistics("ingester_flushed_chunks_bytes"),
 		flushedChunksLinesStats:       analytics.NewStatistics("ingester_flushed_chunks_lines"),
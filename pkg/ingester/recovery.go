@@ -74,8 +74,13 @@ func newIngesterRecoverer(i *Ingester) *ingesterRecoverer {
 	}
 }
 
-// Use all available cores
-func (r *ingesterRecoverer) NumWorkers() int { return runtime.GOMAXPROCS(0) }
+// NumWorkers returns the configured replay concurrency, using all available cores by default.
+func (r *ingesterRecoverer) NumWorkers() int {
+	if n := r.ing.cfg.WAL.ReplayConcurrency; n > 0 {
+		return n
+	}
+	return runtime.GOMAXPROCS(0)
+}
 
 func (r *ingesterRecoverer) Series(series *Series) error {
 	return r.ing.replayController.WithBackPressure(func() error {
@@ -233,11 +238,16 @@ func (r *ingesterRecoverer) Done() <-chan struct{} {
 }
 
 func RecoverWAL(ctx context.Context, reader WALReader, recoverer Recoverer) error {
-	dispatch := func(recoverer Recoverer, b []byte, inputs []chan recoveryInput) error {
+	decode := func(b []byte) (interface{}, error) {
 		rec := recordPool.GetRecord()
 		if err := wal.DecodeRecord(b, rec); err != nil {
-			return err
+			return nil, err
 		}
+		return rec, nil
+	}
+
+	dispatch := func(recoverer Recoverer, data interface{}, inputs []chan recoveryInput) error {
+		rec := data.(*wal.Record)
 
 		// First process all series to ensure we don't write entries to nonexistant series.
 		var firstErr error
@@ -290,6 +300,7 @@ func RecoverWAL(ctx context.Context, reader WALReader, recoverer Recoverer) erro
 	return recoverGeneric(
 		reader,
 		recoverer,
+		decode,
 		dispatch,
 		process,
 	)
@@ -297,11 +308,16 @@ func RecoverWAL(ctx context.Context, reader WALReader, recoverer Recoverer) erro
 }
 
 func RecoverCheckpoint(reader WALReader, recoverer Recoverer) error {
-	dispatch := func(_ Recoverer, b []byte, inputs []chan recoveryInput) error {
+	decode := func(b []byte) (interface{}, error) {
 		s := &Series{}
 		if err := decodeCheckpointRecord(b, s); err != nil {
-			return err
+			return nil, err
 		}
+		return s, nil
+	}
+
+	dispatch := func(_ Recoverer, data interface{}, inputs []chan recoveryInput) error {
+		s := data.(*Series)
 
 		worker := int(s.Fingerprint % uint64(len(inputs)))
 		inputs[worker] <- recoveryInput{
@@ -339,6 +355,7 @@ func RecoverCheckpoint(reader WALReader, recoverer Recoverer) error {
 	return recoverGeneric(
 		reader,
 		recoverer,
+		decode,
 		dispatch,
 		process,
 	)
@@ -349,15 +366,27 @@ type recoveryInput struct {
 	data   interface{}
 }
 
+// decodedRecord is a record read from the WAL, which is decoded concurrently with the records around it.
+type decodedRecord struct {
+	b    []byte
+	data interface{}
+	err  error
+	// done is closed once the record is decoded.
+	done chan struct{}
+}
+
 // recoverGeneric enables reusing the ability to recover from WALs of different types
-// by exposing the dispatch and process functions.
+// by exposing the decode, dispatch and process functions.
+// Records are decoded concurrently by the workers, but dispatched in the order they were read in, so that
+// dispatch can rely on the records preceding a record, e.g. the series of its entries, to be dispatched first.
 // Note: it explicitly does not call the Recoverer.Close function as it's possible to layer
 // multiple recoveries on top of each other, as in the case of recovering from Checkpoints
 // then the WAL.
 func recoverGeneric(
 	reader WALReader,
 	recoverer Recoverer,
-	dispatch func(Recoverer, []byte, []chan recoveryInput) error,
+	decode func([]byte) (interface{}, error),
+	dispatch func(Recoverer, interface{}, []chan recoveryInput) error,
 	process func(Recoverer, <-chan recoveryInput, chan<- error),
 ) error {
 	var wg sync.WaitGroup
@@ -381,7 +410,23 @@ func recoverGeneric(
 
 	}
 
+	// records holds the records in the order they were read in, while toDecode distributes them to the decoders.
+	records := make(chan *decodedRecord, nWorkers)
+	toDecode := make(chan *decodedRecord, nWorkers)
+	for i := 0; i < nWorkers; i++ {
+		go func() {
+			for rec := range toDecode {
+				rec.data, rec.err = decode(rec.b)
+				rec.b = nil
+				close(rec.done)
+			}
+		}()
+	}
+
 	go func() {
+		defer close(records)
+		defer close(toDecode)
+
 		for reader.Next() {
 			b := reader.Record()
 			if err := reader.Err(); err != nil {
@@ -389,7 +434,25 @@ func recoverGeneric(
 				continue
 			}
 
-			if err := dispatch(recoverer, b, inputs); err != nil {
+			// The record is only valid until the next call to Next, so it's copied before being decoded concurrently.
+			rec := &decodedRecord{
+				b:    append([]byte(nil), b...),
+				done: make(chan struct{}),
+			}
+			toDecode <- rec
+			records <- rec
+		}
+	}()
+
+	go func() {
+		for rec := range records {
+			<-rec.done
+			if rec.err != nil {
+				errCh <- rec.err
+				continue
+			}
+
+			if err := dispatch(recoverer, rec.data, inputs); err != nil {
 				errCh <- err
 				continue
 			}
@@ -0,0 +1,184 @@
+package ingester
+
+import (
+	"context"
+	"flag"
+	"sync"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/pkg/errors"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/storage/chunk"
+	"github.com/grafana/loki/v3/pkg/storage/stores"
+	"github.com/grafana/loki/v3/pkg/util/flagext"
+)
+
+// SegmentFlushConfig configures flushing the chunks of a tenant together in segment objects.
+type SegmentFlushConfig struct {
+	Enabled    bool             `yaml:"enabled"`
+	TargetSize flagext.ByteSize `yaml:"target_size"`
+	MaxWait    time.Duration    `yaml:"max_wait"`
+}
+
+// RegisterFlags adds the flags required to config this to the given FlagSet
+func (cfg *SegmentFlushConfig) RegisterFlags(f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, "ingester.segment-flush.enabled", false, "Experimental: Flush the chunks of a tenant together in segment objects holding many chunks, instead of one object per chunk. Requires a TSDB index with schema v15 or later for the periods the chunks are written to; chunks of other periods are flushed one object per chunk. Segments are never deleted by the compactor, so it can't be enabled along with compactor retention.")
+
+	cfg.TargetSize = 8 << 20
+	f.Var(&cfg.TargetSize, "ingester.segment-flush.target-size", "Experimental: Size of the chunks after which a segment is written. A unit suffix (KB, MB, GB) may be applied.")
+	f.DurationVar(&cfg.MaxWait, "ingester.segment-flush.max-wait", time.Second, "Experimental: Maximum time chunks wait for other chunks of the tenant before their segment is written.")
+}
+
+func (cfg *SegmentFlushConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.TargetSize <= 0 {
+		return errors.Errorf("invalid segment flush target size: %d", cfg.TargetSize)
+	}
+	if cfg.MaxWait <= 0 {
+		return errors.Errorf("invalid segment flush max wait: %v", cfg.MaxWait)
+	}
+	return nil
+}
+
+// segmentFlusher collects the chunks flushed concurrently for a tenant, and writes them together in a segment object
+// once the segment reaches its target size or its oldest chunk waited for the max wait.
+type segmentFlusher struct {
+	cfg     SegmentFlushConfig
+	writer  stores.SegmentWriter
+	timeout time.Duration
+	metrics *ingesterMetrics
+
+	mtx     sync.Mutex
+	pending map[string]*pendingSegment
+}
+
+type pendingSegment struct {
+	userID string
+	chunks []chunk.Chunk
+	size   int
+	timer  *time.Timer
+
+	// taken is set once the segment is being written, so that it isn't written twice.
+	taken bool
+	done  chan struct{}
+	err   error
+}
+
+func newSegmentFlusher(cfg SegmentFlushConfig, writer stores.SegmentWriter, timeout time.Duration, metrics *ingesterMetrics) *segmentFlusher {
+	return &segmentFlusher{
+		cfg:     cfg,
+		writer:  writer,
+		timeout: timeout,
+		metrics: metrics,
+		pending: map[string]*pendingSegment{},
+	}
+}
+
+// Flush adds the encoded chunks to the pending segment of the tenant and waits for the segment to be written.
+func (f *segmentFlusher) Flush(ctx context.Context, userID string, chunks []chunk.Chunk) error {
+	size := 0
+	for _, c := range chunks {
+		encoded, err := c.Encoded()
+		if err != nil {
+			return err
+		}
+		size += len(encoded)
+	}
+
+	f.mtx.Lock()
+	segment, ok := f.pending[userID]
+	if !ok {
+		segment = &pendingSegment{
+			userID: userID,
+			done:   make(chan struct{}),
+		}
+		segment.timer = time.AfterFunc(f.cfg.MaxWait, func() { f.write(segment) })
+		f.pending[userID] = segment
+	}
+	segment.chunks = append(segment.chunks, chunks...)
+	segment.size += size
+	full := segment.size >= int(f.cfg.TargetSize)
+	f.mtx.Unlock()
+
+	if full {
+		f.write(segment)
+	}
+
+	select {
+	case <-segment.done:
+		return segment.err
+	case <-ctx.Done():
+		if f.withdraw(segment, chunks, size) {
+			return ctx.Err()
+		}
+		// the segment is already being written, wait for it so that the chunks aren't flushed again.
+		<-segment.done
+		return segment.err
+	}
+}
+
+// withdraw takes the chunks back out of the segment if it isn't being written yet, so that they aren't written
+// along with the segment after the flush gave up on them. It returns false if the segment is already being written.
+func (f *segmentFlusher) withdraw(segment *pendingSegment, chunks []chunk.Chunk, size int) bool {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if segment.taken {
+		return false
+	}
+
+	withdrawn := make(map[logproto.ChunkRef]struct{}, len(chunks))
+	for _, c := range chunks {
+		withdrawn[c.ChunkRef] = struct{}{}
+	}
+	remaining := segment.chunks[:0]
+	for _, c := range segment.chunks {
+		if _, ok := withdrawn[c.ChunkRef]; !ok {
+			remaining = append(remaining, c)
+		}
+	}
+	segment.chunks = remaining
+	segment.size -= size
+
+	// an empty segment is dropped without being written
+	if len(segment.chunks) == 0 {
+		segment.taken = true
+		if f.pending[segment.userID] == segment {
+			delete(f.pending, segment.userID)
+		}
+		segment.timer.Stop()
+		close(segment.done)
+	}
+	return true
+}
+
+func (f *segmentFlusher) write(segment *pendingSegment) {
+	f.mtx.Lock()
+	if segment.taken {
+		f.mtx.Unlock()
+		return
+	}
+	segment.taken = true
+	if f.pending[segment.userID] == segment {
+		delete(f.pending, segment.userID)
+	}
+	f.mtx.Unlock()
+
+	segment.timer.Stop()
+	defer close(segment.done)
+
+	// the segment holds chunks of many flushes, so it isn't bound to the context of any of them.
+	ctx, cancel := context.WithTimeout(user.InjectOrgID(context.Background(), segment.userID), f.timeout)
+	defer cancel()
+
+	if err := f.writer.PutSegment(ctx, segment.userID, segment.chunks); err != nil {
+		segment.err = errors.Wrap(err, "store put segment")
+		return
+	}
+	f.metrics.segmentChunks.Observe(float64(len(segment.chunks)))
+	f.metrics.segmentSize.Observe(float64(segment.size))
+}
@@ -0,0 +1,156 @@
+package ingester
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	gokitlog "github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/distributor/writefailures"
+	"github.com/grafana/loki/v3/pkg/ingester/client"
+	"github.com/grafana/loki/v3/pkg/runtime"
+	"github.com/grafana/loki/v3/pkg/storage/chunk"
+	"github.com/grafana/loki/v3/pkg/util/constants"
+	"github.com/grafana/loki/v3/pkg/util/flagext"
+	"github.com/grafana/loki/v3/pkg/validation"
+)
+
+type segmentTestStore struct {
+	*testStore
+
+	segmentsMtx sync.Mutex
+	segments    [][]chunk.Chunk
+}
+
+func (s *segmentTestStore) PutSegment(ctx context.Context, userID string, chunks []chunk.Chunk) error {
+	orgID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return err
+	}
+	if orgID != userID {
+		return errors.New("unexpected tenant")
+	}
+
+	s.segmentsMtx.Lock()
+	defer s.segmentsMtx.Unlock()
+	s.segments = append(s.segments, chunks)
+	return nil
+}
+
+func newSegmentTestStore(t *testing.T, cfg Config) (*segmentTestStore, *Ingester) {
+	store := &segmentTestStore{
+		testStore: &testStore{
+			chunks: map[string][]chunk.Chunk{},
+		},
+	}
+
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+
+	ing, err := New(cfg, client.Config{}, store, limits, runtime.DefaultTenantConfigs(), nil, writefailures.Cfg{}, constants.Loki, gokitlog.NewNopLogger())
+	require.NoError(t, err)
+	return store, ing
+}
+
+func TestSegmentFlush(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		targetSize       flagext.ByteSize
+		maxWait          time.Duration
+		expectedSegments int
+	}{
+		{
+			name:             "written after max wait",
+			targetSize:       1 << 30,
+			maxWait:          100 * time.Millisecond,
+			expectedSegments: 1,
+		},
+		{
+			name:             "written once target size is reached",
+			targetSize:       1,
+			maxWait:          time.Hour,
+			expectedSegments: 2,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := defaultIngesterTestConfig(t)
+			cfg.SegmentFlush.Enabled = true
+			cfg.SegmentFlush.TargetSize = tc.targetSize
+			cfg.SegmentFlush.MaxWait = tc.maxWait
+			store, ing := newSegmentTestStore(t, cfg)
+
+			ctx := user.InjectOrgID(context.Background(), "foo")
+			descs := [][]*chunkDesc{buildChunkDecs(t), buildChunkDecs(t)}
+			errs := make(chan error, len(descs))
+			for _, cs := range descs {
+				go func(cs []*chunkDesc) {
+					errs <- ing.flushChunks(ctx, 0, makeRandomLabels(), cs, &sync.RWMutex{})
+				}(cs)
+			}
+			for range descs {
+				require.NoError(t, <-errs)
+			}
+
+			require.Len(t, store.segments, tc.expectedSegments)
+			total := 0
+			for _, segment := range store.segments {
+				total += len(segment)
+			}
+			require.Equal(t, 20, total)
+			require.Empty(t, store.chunks)
+		})
+	}
+}
+
+func TestSegmentFlushCanceled(t *testing.T) {
+	cfg := defaultIngesterTestConfig(t)
+	cfg.SegmentFlush.Enabled = true
+	cfg.SegmentFlush.TargetSize = 1 << 30
+	cfg.SegmentFlush.MaxWait = time.Hour
+	store, ing := newSegmentTestStore(t, cfg)
+
+	pending := func() bool {
+		ing.segmentFlusher.mtx.Lock()
+		defer ing.segmentFlusher.mtx.Unlock()
+		return len(ing.segmentFlusher.pending) > 0
+	}
+
+	// the canceled flush gives up on its chunks before the segment is written
+	ctx, cancel := context.WithCancel(user.InjectOrgID(context.Background(), "foo"))
+	canceled := buildChunkDecs(t)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- ing.flushChunks(ctx, 0, makeRandomLabels(), canceled, &sync.RWMutex{})
+	}()
+	require.Eventually(t, pending, time.Second, time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-errs, context.Canceled)
+	require.False(t, pending())
+
+	ing.segmentFlusher.cfg.MaxWait = 10 * time.Millisecond
+	flushed := buildChunkDecs(t)
+	require.NoError(t, ing.flushChunks(user.InjectOrgID(context.Background(), "foo"), 0, makeRandomLabels(), flushed, &sync.RWMutex{}))
+
+	require.Len(t, store.segments, 1)
+	require.Len(t, store.segments[0], len(flushed))
+	for _, c := range canceled {
+		require.True(t, c.flushed.IsZero())
+	}
+}
+
+func TestSegmentFlushRequiresSegmentWriter(t *testing.T) {
+	cfg := defaultIngesterTestConfig(t)
+	cfg.SegmentFlush.Enabled = true
+
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+
+	_, err = New(cfg, client.Config{}, &testStore{}, limits, runtime.DefaultTenantConfigs(), nil, writefailures.Cfg{}, constants.Loki, gokitlog.NewNopLogger())
+	require.Error(t, err)
+}
@@ -0,0 +1,133 @@
+package ingester
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql"
+	"github.com/grafana/loki/v3/pkg/validation"
+)
+
+// streamBudgetIndex is the index of the active streams of a tenant the stream budgets are counted with.
+type streamBudgetIndex interface {
+	Add(labels []logproto.LabelAdapter, fp model.Fingerprint) labels.Labels
+	Delete(labels labels.Labels, fp model.Fingerprint)
+	Lookup(t time.Time, matchers []*labels.Matcher, shard *logql.Shard) ([]model.Fingerprint, error)
+	LabelValues(t time.Time, name string, shard *logql.Shard) ([]string, error)
+}
+
+// streamBudgetCounts counts the active streams of a tenant for its stream budgets, so that creating a stream doesn't
+// query the index. A budget is counted from the index the first time it's checked, which also covers the budgets
+// added at runtime, and its counts are then updated as streams are added to and removed from the index.
+type streamBudgetCounts struct {
+	mtx sync.Mutex
+	idx streamBudgetIndex
+
+	// selectors holds the number of streams matching each selector.
+	selectors map[string]*selectorCount
+	// values holds the number of streams with each value, by label name.
+	values map[string]map[string]int
+}
+
+type selectorCount struct {
+	matchers []*labels.Matcher
+	streams  int
+}
+
+func newStreamBudgetCounts(idx streamBudgetIndex) *streamBudgetCounts {
+	return &streamBudgetCounts{
+		idx:       idx,
+		selectors: map[string]*selectorCount{},
+		values:    map[string]map[string]int{},
+	}
+}
+
+// add adds a stream to the index and to the counts of the budgets, and returns the sorted labels of the stream.
+// The index is updated along with the counts so that a budget counted from the index isn't updated twice.
+func (c *streamBudgetCounts) add(ls labels.Labels, fp model.Fingerprint) labels.Labels {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	sortedLabels := c.idx.Add(logproto.FromLabelsToLabelAdapters(ls), fp)
+	c.update(ls, 1)
+	return sortedLabels
+}
+
+// remove removes a stream from the index and from the counts of the budgets.
+func (c *streamBudgetCounts) remove(ls labels.Labels, fp model.Fingerprint) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.idx.Delete(ls, fp)
+	c.update(ls, -1)
+}
+
+func (c *streamBudgetCounts) update(ls labels.Labels, delta int) {
+	for _, sc := range c.selectors {
+		if matchesAll(sc.matchers, ls) {
+			sc.streams += delta
+		}
+	}
+
+	for name, values := range c.values {
+		value := ls.Get(name)
+		if value == "" {
+			continue
+		}
+		if values[value] += delta; values[value] <= 0 {
+			delete(values, value)
+		}
+	}
+}
+
+// streams returns the number of active streams matching the selector of the budget.
+func (c *streamBudgetCounts) streams(budget validation.StreamBudget) (int, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	sc, ok := c.selectors[budget.Selector]
+	if !ok {
+		fps, err := c.idx.Lookup(time.Now(), budget.Matchers, nil)
+		if err != nil {
+			return 0, err
+		}
+		sc = &selectorCount{matchers: budget.Matchers, streams: len(fps)}
+		c.selectors[budget.Selector] = sc
+	}
+	return sc.streams, nil
+}
+
+// labelValues returns the number of values of the label across the active streams, and whether one of them has
+// the given value.
+func (c *streamBudgetCounts) labelValues(name, value string) (int, bool, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	values, ok := c.values[name]
+	if !ok {
+		now := time.Now()
+		existing, err := c.idx.LabelValues(now, name, nil)
+		if err != nil {
+			return 0, false, err
+		}
+
+		values = make(map[string]int, len(existing))
+		for _, v := range existing {
+			fps, err := c.idx.Lookup(now, []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, name, v)}, nil)
+			if err != nil {
+				return 0, false, err
+			}
+			if len(fps) > 0 {
+				values[v] = len(fps)
+			}
+		}
+		c.values[name] = values
+	}
+
+	_, exists := values[value]
+	return len(values), exists, nil
+}
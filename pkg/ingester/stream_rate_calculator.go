@@ -80,6 +80,7 @@ func (c *StreamRateCalculator) updateRates() {
 					StreamHashNoShard: streamRate.StreamHashNoShard,
 					Rate:              streamRate.Rate,
 					Pushes:            streamRate.Pushes,
+					RateLimited:       streamRate.RateLimited,
 				})
 			}
 		}
@@ -101,7 +102,10 @@ func (c *StreamRateCalculator) Rates() []logproto.StreamRate {
 	return c.allRates
 }
 
-func (c *StreamRateCalculator) Record(tenant string, streamHash, streamHashNoShard uint64, bytes int) {
+// Record records a push of the given bytes to a stream. rateLimitedBytes are
+// the bytes of the push rejected by the per-stream rate limit, which are
+// included in bytes.
+func (c *StreamRateCalculator) Record(tenant string, streamHash, streamHashNoShard uint64, bytes, rateLimitedBytes int) {
 	i := streamHash & uint64(c.size-1)
 
 	c.locks[i].Lock()
@@ -113,6 +117,7 @@ func (c *StreamRateCalculator) Record(tenant string, streamHash, streamHashNoSha
 	streamRate.StreamHashNoShard = streamHashNoShard
 	streamRate.Tenant = tenant
 	streamRate.Rate += int64(bytes)
+	streamRate.RateLimited += int64(rateLimitedBytes)
 	streamRate.Pushes++
 	tenantMap[streamHash] = streamRate
 
@@ -13,11 +13,11 @@ func TestStreamRateCalculator(t *testing.T) {
 	defer calc.Stop()
 
 	for i := 0; i < 100; i++ {
-		calc.Record("tenant 1", 1, 1, 100)
+		calc.Record("tenant 1", 1, 1, 100, 0)
 	}
 
 	for i := 0; i < 100; i++ {
-		calc.Record("tenant 2", 1, 1, 100)
+		calc.Record("tenant 2", 1, 1, 100, 10)
 	}
 
 	require.Eventually(t, func() bool {
@@ -27,8 +27,8 @@ func TestStreamRateCalculator(t *testing.T) {
 		})
 
 		if len(rates) > 1 {
-			return rates[0].Tenant == "tenant 1" && rates[0].Rate == 10000 && rates[0].Pushes == 100 &&
-				rates[1].Tenant == "tenant 2" && rates[1].Rate == 10000 && rates[1].Pushes == 100
+			return rates[0].Tenant == "tenant 1" && rates[0].Rate == 10000 && rates[0].Pushes == 100 && rates[0].RateLimited == 0 &&
+				rates[1].Tenant == "tenant 2" && rates[1].Rate == 10000 && rates[1].Pushes == 100 && rates[1].RateLimited == 1000
 		}
 
 		return false
@@ -29,12 +29,18 @@ type WALConfig struct {
 	CheckpointDuration  time.Duration    `yaml:"checkpoint_duration"`
 	FlushOnShutdown     bool             `yaml:"flush_on_shutdown"`
 	ReplayMemoryCeiling flagext.ByteSize `yaml:"replay_memory_ceiling"`
+	ReplayConcurrency   int              `yaml:"replay_concurrency"`
+
+	JoinRingBeforeReplay bool `yaml:"join_ring_before_replay" category:"experimental"`
 }
 
 func (cfg *WALConfig) Validate() error {
 	if cfg.Enabled && cfg.CheckpointDuration < 1 {
 		return errors.Errorf("invalid checkpoint duration: %v", cfg.CheckpointDuration)
 	}
+	if cfg.ReplayConcurrency < 0 {
+		return errors.Errorf("invalid WAL replay concurrency: %d", cfg.ReplayConcurrency)
+	}
 	return nil
 }
 
@@ -48,6 +54,8 @@ func (cfg *WALConfig) RegisterFlags(f *flag.FlagSet) {
 	// Need to set default here
 	cfg.ReplayMemoryCeiling = flagext.ByteSize(defaultCeiling)
 	f.Var(&cfg.ReplayMemoryCeiling, "ingester.wal-replay-memory-ceiling", "Maximum memory size the WAL may use during replay. After hitting this, it will flush data to storage before continuing. A unit suffix (KB, MB, GB) may be applied.")
+	f.IntVar(&cfg.ReplayConcurrency, "ingester.wal-replay-concurrency", 0, "Number of workers decoding WAL records and replaying streams during replay. Streams are assigned to workers by fingerprint. 0 to use one worker per available CPU.")
+	f.BoolVar(&cfg.JoinRingBeforeReplay, "ingester.wal-join-ring-before-replay", false, "Experimental: Join the ring before replaying the WAL, and report the ingester ready while the replay is in progress. The ingester stays in the JOINING state and rejects pushes and queries until the replay is finished, so that distributors write to the other replicas of the streams meanwhile. An ingester restored as ACTIVE from the ring isn't ready until the replay is finished. Requires the ingester join after and observe period to be 0.")
 }
 
 // WAL interface allows us to have a no-op WAL when the WAL is disabled.
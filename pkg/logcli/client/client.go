@@ -1,6 +1,7 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"fmt"
@@ -13,9 +14,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
 	"github.com/gorilla/websocket"
 	json "github.com/json-iterator/go"
 	"github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
 
 	"github.com/grafana/dskit/backoff"
 
@@ -28,16 +32,19 @@ import (
 )
 
 const (
-	queryPath         = "/loki/api/v1/query"
-	queryRangePath    = "/loki/api/v1/query_range"
-	labelsPath        = "/loki/api/v1/labels"
-	labelValuesPath   = "/loki/api/v1/label/%s/values"
-	seriesPath        = "/loki/api/v1/series"
-	tailPath          = "/loki/api/v1/tail"
-	statsPath         = "/loki/api/v1/index/stats"
-	volumePath        = "/loki/api/v1/index/volume"
-	volumeRangePath   = "/loki/api/v1/index/volume_range"
-	defaultAuthHeader = "Authorization"
+	queryPath          = "/loki/api/v1/query"
+	queryRangePath     = "/loki/api/v1/query_range"
+	labelsPath         = "/loki/api/v1/labels"
+	labelValuesPath    = "/loki/api/v1/label/%s/values"
+	seriesPath         = "/loki/api/v1/series"
+	tailPath           = "/loki/api/v1/tail"
+	statsPath          = "/loki/api/v1/index/stats"
+	volumePath         = "/loki/api/v1/index/volume"
+	volumeRangePath    = "/loki/api/v1/index/volume_range"
+	deletePath         = "/loki/api/v1/delete"
+	pushPath           = "/loki/api/v1/push"
+	detectedFieldsPath = "/loki/api/v1/detected_fields"
+	defaultAuthHeader  = "Authorization"
 )
 
 var userAgent = fmt.Sprintf("loki-logcli/%s", build.Version)
@@ -54,6 +61,29 @@ type Client interface {
 	GetStats(queryStr string, start, end time.Time, quiet bool) (*logproto.IndexStatsResponse, error)
 	GetVolume(query *volume.Query) (*loghttp.QueryResponse, error)
 	GetVolumeRange(query *volume.Query) (*loghttp.QueryResponse, error)
+	CreateDeleteRequest(params DeleteRequestParams, quiet bool) error
+	ListDeleteRequests(quiet bool) ([]DeleteRequest, error)
+	CancelDeleteRequest(requestID string, force bool, quiet bool) error
+	Push(req *logproto.PushRequest, quiet bool) error
+	GetDetectedFields(queryStr string, fieldLimit, lineLimit int, start, end time.Time, quiet bool) (*logproto.DetectedFieldsResponse, error)
+}
+
+// DeleteRequestParams contains the parameters used to create a log deletion request.
+type DeleteRequestParams struct {
+	Query       string
+	Start       time.Time
+	End         time.Time
+	MaxInterval time.Duration
+}
+
+// DeleteRequest is a log deletion request as returned by the compactor.
+type DeleteRequest struct {
+	RequestID string     `json:"request_id"`
+	StartTime model.Time `json:"start_time"`
+	EndTime   model.Time `json:"end_time"`
+	Query     string     `json:"query"`
+	Status    string     `json:"status"`
+	CreatedAt model.Time `json:"created_at"`
 }
 
 // Tripperware can wrap a roundtripper.
@@ -194,6 +224,72 @@ func (c *DefaultClient) GetVolumeRange(query *volume.Query) (*loghttp.QueryRespo
 	return c.getVolume(volumeRangePath, query)
 }
 
+// GetDetectedFields uses the /loki/api/v1/detected_fields endpoint to list the fields detected in the logs matching the query
+func (c *DefaultClient) GetDetectedFields(queryStr string, fieldLimit, lineLimit int, start, end time.Time, quiet bool) (*logproto.DetectedFieldsResponse, error) {
+	params := util.NewQueryStringBuilder()
+	params.SetString("query", queryStr)
+	params.SetInt("start", start.UnixNano())
+	params.SetInt("end", end.UnixNano())
+	if fieldLimit > 0 {
+		params.SetInt32("field_limit", fieldLimit)
+	}
+	if lineLimit > 0 {
+		params.SetInt32("line_limit", lineLimit)
+	}
+
+	var resp logproto.DetectedFieldsResponse
+	if err := c.doRequest(detectedFieldsPath, params.Encode(), quiet, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateDeleteRequest uses the /loki/api/v1/delete endpoint to create a log deletion request
+func (c *DefaultClient) CreateDeleteRequest(p DeleteRequestParams, quiet bool) error {
+	params := util.NewQueryStringBuilder()
+	params.SetString("query", p.Query)
+	params.SetString("start", p.Start.UTC().Format(time.RFC3339))
+	if !p.End.IsZero() {
+		params.SetString("end", p.End.UTC().Format(time.RFC3339))
+	}
+	if p.MaxInterval != 0 {
+		params.SetString("max_interval", p.MaxInterval.String())
+	}
+
+	return c.doHTTPRequest(http.MethodPost, deletePath, params.Encode(), quiet, nil)
+}
+
+// ListDeleteRequests uses the /loki/api/v1/delete endpoint to list all log deletion requests of the tenant
+func (c *DefaultClient) ListDeleteRequests(quiet bool) ([]DeleteRequest, error) {
+	var deleteRequests []DeleteRequest
+	if err := c.doRequest(deletePath, "", quiet, &deleteRequests); err != nil {
+		return nil, err
+	}
+	return deleteRequests, nil
+}
+
+// CancelDeleteRequest uses the /loki/api/v1/delete endpoint to cancel a log deletion request
+func (c *DefaultClient) CancelDeleteRequest(requestID string, force bool, quiet bool) error {
+	params := util.NewQueryStringBuilder()
+	params.SetString("request_id", requestID)
+	if force {
+		params.SetString("force", "true")
+	}
+
+	return c.doHTTPRequest(http.MethodDelete, deletePath, params.Encode(), quiet, nil)
+}
+
+// Push uses the /loki/api/v1/push endpoint to send a snappy compressed protobuf push request
+func (c *DefaultClient) Push(req *logproto.PushRequest, quiet bool) error {
+	buf, err := proto.Marshal(req)
+	if err != nil {
+		return err
+	}
+	buf = snappy.Encode(nil, buf)
+
+	return c.doHTTPRequestWithBody(http.MethodPost, pushPath, "", buf, "application/x-protobuf", quiet, nil)
+}
+
 func (c *DefaultClient) getVolume(path string, query *volume.Query) (*loghttp.QueryResponse, error) {
 	queryStr, start, end, limit, step, targetLabels, aggregateByLabels, quiet :=
 		query.QueryString, query.Start, query.End, query.Limit, query.Step,
@@ -236,6 +332,18 @@ func (c *DefaultClient) doQuery(path string, query string, quiet bool) (*loghttp
 }
 
 func (c *DefaultClient) doRequest(path, query string, quiet bool, out interface{}) error {
+	return c.doHTTPRequest(http.MethodGet, path, query, quiet, out)
+}
+
+// doHTTPRequest sends a request with the given method and decodes the JSON response into out.
+// The response body is discarded if out is nil.
+func (c *DefaultClient) doHTTPRequest(method, path, query string, quiet bool, out interface{}) error {
+	return c.doHTTPRequestWithBody(method, path, query, nil, "", quiet, out)
+}
+
+// doHTTPRequestWithBody is like doHTTPRequest but also sends the given body, which is
+// re-sent on every retry. Client errors other than 429 are not retried for non-GET requests.
+func (c *DefaultClient) doHTTPRequestWithBody(method, path, query string, body []byte, contentType string, quiet bool, out interface{}) error {
 	us, err := buildURL(c.Address, path, query)
 	if err != nil {
 		return err
@@ -244,7 +352,7 @@ func (c *DefaultClient) doRequest(path, query string, quiet bool, out interface{
 		log.Print(us)
 	}
 
-	req, err := http.NewRequest("GET", us, nil)
+	req, err := http.NewRequest(method, us, nil)
 	if err != nil {
 		return err
 	}
@@ -254,6 +362,9 @@ func (c *DefaultClient) doRequest(path, query string, quiet bool, out interface{
 		return err
 	}
 	req.Header = h
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
 
 	// Parse the URL to extract the host
 	clientConfig := config.HTTPClientConfig{
@@ -292,6 +403,10 @@ func (c *DefaultClient) doRequest(path, query string, quiet bool, out interface{
 		if !backoff.Ongoing() {
 			break
 		}
+		if body != nil {
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			req.ContentLength = int64(len(body))
+		}
 		resp, err = client.Do(req)
 		if err != nil {
 			log.Println("error sending request", err)
@@ -304,6 +419,9 @@ func (c *DefaultClient) doRequest(path, query string, quiet bool, out interface{
 			if err := resp.Body.Close(); err != nil {
 				log.Println("error closing body", err)
 			}
+			if method != http.MethodGet && resp.StatusCode/100 == 4 && resp.StatusCode != http.StatusTooManyRequests {
+				return fmt.Errorf("error response from server: %s (%d)", strings.TrimSpace(string(buf)), resp.StatusCode)
+			}
 			backoff.Wait()
 			continue
 		}
@@ -321,6 +439,9 @@ func (c *DefaultClient) doRequest(path, query string, quiet bool, out interface{
 			log.Println("error closing body", err)
 		}
 	}()
+	if out == nil {
+		return nil
+	}
 	return json.NewDecoder(resp.Body).Decode(out)
 }
 
@@ -204,6 +204,26 @@ func (f *FileClient) GetVolumeRange(_ *volume.Query) (*loghttp.QueryResponse, er
 	return nil, ErrNotSupported
 }
 
+func (f *FileClient) CreateDeleteRequest(_ DeleteRequestParams, _ bool) error {
+	return ErrNotSupported
+}
+
+func (f *FileClient) ListDeleteRequests(_ bool) ([]DeleteRequest, error) {
+	return nil, ErrNotSupported
+}
+
+func (f *FileClient) CancelDeleteRequest(_ string, _ bool, _ bool) error {
+	return ErrNotSupported
+}
+
+func (f *FileClient) Push(_ *logproto.PushRequest, _ bool) error {
+	return ErrNotSupported
+}
+
+func (f *FileClient) GetDetectedFields(_ string, _, _ int, _, _ time.Time, _ bool) (*logproto.DetectedFieldsResponse, error) {
+	return nil, ErrNotSupported
+}
+
 type limiter struct {
 	n int
 }
@@ -0,0 +1,107 @@
+package deletequery
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/grafana/loki/v3/pkg/logcli/client"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+// DeleteQuery contains all necessary fields to create, list and cancel log deletion requests
+type DeleteQuery struct {
+	QueryString string
+	Start       time.Time
+	End         time.Time
+	MaxInterval time.Duration
+	RequestID   string
+	Force       bool
+	Quiet       bool
+}
+
+// Validate checks the deletion query and time range locally before it is sent to Loki,
+// using the same rules as the compactor.
+func (q *DeleteQuery) Validate(now time.Time) error {
+	if q.QueryString == "" {
+		return errors.New("query not set")
+	}
+	if _, err := syntax.ParseLogSelector(q.QueryString, false); err != nil {
+		return fmt.Errorf("invalid deletion query: %w", err)
+	}
+	if q.Start.IsZero() {
+		return errors.New("start time not set")
+	}
+	if q.End.After(now) {
+		return errors.New("deletes in the future are not allowed")
+	}
+	if !q.End.IsZero() && q.Start.After(q.End) {
+		return errors.New("start time can't be greater than end time")
+	}
+	if q.MaxInterval != 0 && q.MaxInterval < time.Second {
+		return errors.New("max interval must be at least one second")
+	}
+	return nil
+}
+
+// DoCreate validates and creates a log deletion request
+func (q *DeleteQuery) DoCreate(c client.Client) {
+	if err := q.Validate(time.Now()); err != nil {
+		log.Fatalf("Invalid delete request: %s", err)
+	}
+
+	err := c.CreateDeleteRequest(client.DeleteRequestParams{
+		Query:       q.QueryString,
+		Start:       q.Start,
+		End:         q.End,
+		MaxInterval: q.MaxInterval,
+	}, q.Quiet)
+	if err != nil {
+		log.Fatalf("Error creating delete request: %+v", err)
+	}
+
+	if !q.Quiet {
+		log.Println("Delete request created, use `logcli delete list` to follow its progress.")
+	}
+}
+
+// DoList prints out all log deletion requests of the tenant along with their status
+func (q *DeleteQuery) DoList(c client.Client) {
+	deleteRequests, err := c.ListDeleteRequests(q.Quiet)
+	if err != nil {
+		log.Fatalf("Error listing delete requests: %+v", err)
+	}
+
+	printDeleteRequests(os.Stdout, deleteRequests)
+}
+
+// DoCancel cancels a log deletion request
+func (q *DeleteQuery) DoCancel(c client.Client) {
+	if err := c.CancelDeleteRequest(q.RequestID, q.Force, q.Quiet); err != nil {
+		log.Fatalf("Error cancelling delete request: %+v", err)
+	}
+
+	if !q.Quiet {
+		log.Printf("Delete request %s cancelled", q.RequestID)
+	}
+}
+
+func printDeleteRequests(out io.Writer, deleteRequests []client.DeleteRequest) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Request ID\tCreated At\tStart\tEnd\tStatus\tQuery\n")
+	for _, r := range deleteRequests {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.RequestID,
+			r.CreatedAt.Time().UTC().Format(time.RFC3339),
+			r.StartTime.Time().UTC().Format(time.RFC3339),
+			r.EndTime.Time().UTC().Format(time.RFC3339),
+			r.Status,
+			r.Query,
+		)
+	}
+	w.Flush()
+}
@@ -0,0 +1,90 @@
+package deletequery
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logcli/client"
+)
+
+func TestDeleteQuery_Validate(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	start := now.Add(-time.Hour)
+
+	for _, tc := range []struct {
+		name    string
+		query   DeleteQuery
+		wantErr string
+	}{
+		{
+			name:  "valid",
+			query: DeleteQuery{QueryString: `{foo="bar"} |= "secret"`, Start: start, End: now},
+		},
+		{
+			name:    "missing query",
+			query:   DeleteQuery{Start: start, End: now},
+			wantErr: "query not set",
+		},
+		{
+			name:    "invalid query",
+			query:   DeleteQuery{QueryString: `{foo="bar"`, Start: start, End: now},
+			wantErr: "invalid deletion query",
+		},
+		{
+			name:    "metric query",
+			query:   DeleteQuery{QueryString: `rate({foo="bar"}[1m])`, Start: start, End: now},
+			wantErr: "invalid deletion query",
+		},
+		{
+			name:    "missing start",
+			query:   DeleteQuery{QueryString: `{foo="bar"}`, End: now},
+			wantErr: "start time not set",
+		},
+		{
+			name:    "end in the future",
+			query:   DeleteQuery{QueryString: `{foo="bar"}`, Start: start, End: now.Add(time.Minute)},
+			wantErr: "deletes in the future are not allowed",
+		},
+		{
+			name:    "start after end",
+			query:   DeleteQuery{QueryString: `{foo="bar"}`, Start: now, End: start},
+			wantErr: "start time can't be greater than end time",
+		},
+		{
+			name:    "max interval too small",
+			query:   DeleteQuery{QueryString: `{foo="bar"}`, Start: start, End: now, MaxInterval: time.Millisecond},
+			wantErr: "max interval must be at least one second",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.query.Validate(now)
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func Test_printDeleteRequests(t *testing.T) {
+	var buf bytes.Buffer
+	printDeleteRequests(&buf, []client.DeleteRequest{
+		{
+			RequestID: "abc",
+			StartTime: model.TimeFromUnix(0),
+			EndTime:   model.TimeFromUnix(3600),
+			Query:     `{foo="bar"}`,
+			Status:    "50% Complete",
+			CreatedAt: model.TimeFromUnix(7200),
+		},
+	})
+
+	require.Equal(t, `Request ID  Created At            Start                 End                   Status        Query
+abc         1970-01-01T02:00:00Z  1970-01-01T00:00:00Z  1970-01-01T01:00:00Z  50% Complete  {foo="bar"}
+`, buf.String())
+}
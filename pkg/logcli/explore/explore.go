@@ -0,0 +1,273 @@
+package explore
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/grafana/loki/v3/pkg/logcli/client"
+	"github.com/grafana/loki/v3/pkg/logcli/query"
+	"github.com/grafana/loki/v3/pkg/loghttp"
+)
+
+const (
+	enterAltScreen = "\x1b[?1049h\x1b[?25l"
+	exitAltScreen  = "\x1b[?25h\x1b[?1049l"
+	cursorHome     = "\x1b[H"
+	clearLine      = "\x1b[K"
+	clearBelow     = "\x1b[J"
+
+	refreshInterval = 50 * time.Millisecond
+)
+
+// Explorer contains all necessary fields to run the interactive terminal explorer
+type Explorer struct {
+	Since      time.Duration
+	DelayFor   time.Duration
+	Limit      int
+	MaxEntries int
+	FieldLimit int
+}
+
+// DoExplore runs the explorer in the terminal until the user quits
+func (e *Explorer) DoExplore(c client.Client) {
+	if err := e.run(c, os.Stdin, os.Stdout); err != nil {
+		log.Fatalf("Explore failed: %+v", err)
+	}
+}
+
+func (e *Explorer) run(c client.Client, in *os.File, out *os.File) error {
+	fd := int(in.Fd())
+	if !term.IsTerminal(fd) {
+		return errors.New("explore requires an interactive terminal")
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = term.Restore(fd, state)
+	}()
+
+	w := bufio.NewWriter(out)
+	fmt.Fprint(w, enterAltScreen)
+	defer func() {
+		fmt.Fprint(w, exitAltScreen)
+		w.Flush()
+	}()
+
+	msgs := make(chan interface{}, 1024)
+
+	// Anything logged by the client would garble the screen, show it in the status line instead.
+	log.SetOutput(statusWriter(msgs))
+	defer log.SetOutput(os.Stderr)
+
+	go readKeys(in, msgs)
+
+	ex := &explorer{
+		Explorer: e,
+		client:   c,
+		msgs:     msgs,
+		model:    newModel(e.MaxEntries),
+	}
+	ex.loadLabels()
+
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	dirty := true
+	for {
+		select {
+		case msg := <-msgs:
+			dirty = true
+			if ex.do(ex.model.update(msg)) {
+				ex.stopTail()
+				return nil
+			}
+		case <-ticker.C:
+			width, height, err := term.GetSize(int(out.Fd()))
+			if err != nil {
+				return err
+			}
+			if !dirty && width == ex.width && height == ex.height {
+				continue
+			}
+			ex.width, ex.height, dirty = width, height, false
+			draw(w, ex.model.render(width, height))
+		}
+	}
+}
+
+// explorer executes the actions requested by the model and sends the results back as messages.
+type explorer struct {
+	*Explorer
+	client client.Client
+	msgs   chan interface{}
+	model  *model
+
+	width, height int
+	cancelTail    context.CancelFunc
+}
+
+// do executes the action and returns true if the explorer should exit.
+func (e *explorer) do(a action) bool {
+	switch a {
+	case actionLoadLabels:
+		e.loadLabels()
+	case actionLoadValues:
+		e.loadValues(e.model.label)
+	case actionStartTail:
+		e.startTail(e.model.selector())
+	case actionStopTail:
+		e.stopTail()
+	case actionQuit:
+		return true
+	}
+	return false
+}
+
+func (e *explorer) loadLabels() {
+	selector := e.model.selector()
+	selected := make(map[string]bool, len(e.model.matchers))
+	for _, lm := range e.model.matchers {
+		selected[lm.name] = true
+	}
+	end := time.Now()
+	start := end.Add(-e.Since)
+
+	go func() {
+		if len(selected) == 0 {
+			resp, err := e.client.ListLabelNames(true, start, end)
+			if err != nil {
+				e.msgs <- statusMsg{status: fmt.Sprintf("error listing labels: %s", err)}
+				return
+			}
+			e.msgs <- labelsMsg{labels: resp.Data}
+			return
+		}
+
+		resp, err := e.client.Series([]string{selector}, start, end, true)
+		if err != nil {
+			e.msgs <- statusMsg{status: fmt.Sprintf("error listing series: %s", err)}
+			return
+		}
+		e.msgs <- labelsMsg{labels: labelsFromSeries(resp.Data, func(name string) bool { return selected[name] })}
+	}()
+}
+
+func (e *explorer) loadValues(label string) {
+	selector := e.model.selector()
+	hasMatchers := len(e.model.matchers) > 0
+	end := time.Now()
+	start := end.Add(-e.Since)
+
+	go func() {
+		if !hasMatchers {
+			resp, err := e.client.ListLabelValues(label, true, start, end)
+			if err != nil {
+				e.msgs <- statusMsg{status: fmt.Sprintf("error listing values of %s: %s", label, err)}
+				return
+			}
+			e.msgs <- valuesMsg{label: label, values: resp.Data}
+			return
+		}
+
+		resp, err := e.client.Series([]string{selector}, start, end, true)
+		if err != nil {
+			e.msgs <- statusMsg{status: fmt.Sprintf("error listing series: %s", err)}
+			return
+		}
+		e.msgs <- valuesMsg{label: label, values: valuesFromSeries(resp.Data, label)}
+	}()
+}
+
+func (e *explorer) startTail(selector string) {
+	e.stopTail()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancelTail = cancel
+
+	end := time.Now()
+	start := end.Add(-e.Since)
+
+	go func() {
+		resp, err := e.client.GetDetectedFields(selector, e.FieldLimit, 0, start, end, true)
+		if err != nil {
+			e.msgs <- statusMsg{status: fmt.Sprintf("detected fields unavailable: %s", err)}
+			return
+		}
+		e.msgs <- fieldsMsg{fields: resp.Fields}
+	}()
+
+	go func() {
+		q := &query.Query{
+			QueryString: selector,
+			Start:       start,
+			Limit:       e.Limit,
+			Quiet:       true,
+		}
+		err := q.Tail(ctx, e.DelayFor, e.client, func(labels loghttp.LabelSet, entry loghttp.Entry) {
+			select {
+			case e.msgs <- entryMsg{labels: labels, entry: entry}:
+			case <-ctx.Done():
+			}
+		})
+		if err != nil {
+			e.msgs <- statusMsg{status: fmt.Sprintf("error tailing %s: %s", selector, err)}
+		}
+	}()
+}
+
+func (e *explorer) stopTail() {
+	if e.cancelTail != nil {
+		e.cancelTail()
+		e.cancelTail = nil
+	}
+}
+
+func readKeys(r io.Reader, msgs chan<- interface{}) {
+	buf := make([]byte, 64)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			msgs <- key{code: keyCtrlC}
+			return
+		}
+		for _, k := range parseKeys(buf[:n]) {
+			msgs <- k
+		}
+	}
+}
+
+func draw(w *bufio.Writer, lines []string) {
+	w.WriteString(cursorHome)
+	for i, line := range lines {
+		if i > 0 {
+			w.WriteString("\r\n")
+		}
+		w.WriteString(strings.ReplaceAll(line, "\t", " "))
+		w.WriteString(clearLine)
+	}
+	w.WriteString(clearBelow)
+	w.Flush()
+}
+
+// statusWriter sends each written line as a status message.
+type statusWriter chan<- interface{}
+
+func (s statusWriter) Write(p []byte) (int, error) {
+	select {
+	case s <- statusMsg{status: strings.TrimSpace(string(p))}:
+	default:
+	}
+	return len(p), nil
+}
@@ -0,0 +1,673 @@
+package explore
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-logfmt/logfmt"
+
+	"github.com/grafana/loki/v3/pkg/loghttp"
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+type view int
+
+const (
+	viewLabels view = iota
+	viewValues
+	viewTail
+	viewFields
+)
+
+// action is a side effect requested by the model, executed by the explorer.
+type action int
+
+const (
+	actionNone action = iota
+	actionLoadLabels
+	actionLoadValues
+	actionStartTail
+	actionStopTail
+	actionQuit
+)
+
+type keyCode int
+
+const (
+	keyRune keyCode = iota
+	keyUp
+	keyDown
+	keyPgUp
+	keyPgDown
+	keyHome
+	keyEnd
+	keyEnter
+	keyBackspace
+	keyEsc
+	keyCtrlC
+)
+
+type key struct {
+	code keyCode
+	r    rune
+}
+
+// Messages sent by the explorer to the model once a request completes.
+type (
+	labelsMsg struct {
+		labels []string
+	}
+	valuesMsg struct {
+		label  string
+		values []string
+	}
+	entryMsg struct {
+		labels loghttp.LabelSet
+		entry  loghttp.Entry
+	}
+	fieldsMsg struct {
+		fields []*logproto.DetectedField
+	}
+	statusMsg struct {
+		status string
+	}
+)
+
+type matcher struct {
+	name, value string
+}
+
+type tailEntry struct {
+	labels loghttp.LabelSet
+	entry  loghttp.Entry
+	// fields are the parsed fields of the line, lazily parsed when field columns are shown.
+	fields map[string]string
+}
+
+// model holds the state of the explorer and renders it, it doesn't do any I/O
+// so that it can be tested without a terminal or a Loki server.
+type model struct {
+	view     view
+	matchers []matcher
+
+	labels []string
+	label  string
+	values []string
+	cursor int
+
+	entries    []*tailEntry
+	maxEntries int
+	// offset is the number of entries scrolled back from the most recent one, 0 follows the tail.
+	offset int
+
+	fields  []*logproto.DetectedField
+	columns []string
+
+	status string
+}
+
+func newModel(maxEntries int) *model {
+	return &model{maxEntries: maxEntries}
+}
+
+// selector returns the stream selector built from the selected label values.
+func (m *model) selector() string {
+	if len(m.matchers) == 0 {
+		return "{}"
+	}
+	parts := make([]string, 0, len(m.matchers))
+	for _, lm := range m.matchers {
+		parts = append(parts, lm.name+"="+strconv.Quote(lm.value))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+func (m *model) selected(name string) bool {
+	for _, lm := range m.matchers {
+		if lm.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *model) update(msg interface{}) action {
+	switch msg := msg.(type) {
+	case key:
+		return m.handleKey(msg)
+	case labelsMsg:
+		m.labels = msg.labels
+		m.cursor = clamp(m.cursor, 0, len(m.labels)-1)
+		m.status = ""
+	case valuesMsg:
+		if m.view == viewValues && msg.label == m.label {
+			m.values = msg.values
+			m.cursor = clamp(m.cursor, 0, len(m.values)-1)
+			m.status = ""
+		}
+	case entryMsg:
+		if m.view != viewTail && m.view != viewFields {
+			return actionNone
+		}
+		m.entries = append(m.entries, &tailEntry{labels: msg.labels, entry: msg.entry})
+		if len(m.entries) > m.maxEntries {
+			m.entries = m.entries[len(m.entries)-m.maxEntries:]
+		}
+		// Keep the scrolled back entries in place while new ones arrive.
+		if m.offset > 0 {
+			m.offset = clamp(m.offset+1, 0, len(m.entries)-1)
+		}
+	case fieldsMsg:
+		m.fields = msg.fields
+	case statusMsg:
+		m.status = msg.status
+	}
+	return actionNone
+}
+
+func (m *model) handleKey(k key) action {
+	if k.code == keyCtrlC || (k.code == keyRune && k.r == 'q') {
+		return actionQuit
+	}
+
+	switch m.view {
+	case viewLabels:
+		switch {
+		case k.code == keyEnter && len(m.labels) > 0:
+			m.label = m.labels[m.cursor]
+			m.values = nil
+			m.view = viewValues
+			m.cursor = 0
+			m.status = "loading values of " + m.label
+			return actionLoadValues
+		case k.code == keyBackspace && len(m.matchers) > 0:
+			m.matchers = m.matchers[:len(m.matchers)-1]
+			m.cursor = 0
+			m.status = "loading labels"
+			return actionLoadLabels
+		case k.code == keyRune && k.r == 't':
+			if len(m.matchers) == 0 {
+				m.status = "select at least one label value to tail"
+				return actionNone
+			}
+			m.view = viewTail
+			m.entries = nil
+			m.offset = 0
+			m.fields = nil
+			m.status = "tailing " + m.selector()
+			return actionStartTail
+		default:
+			m.cursor = moveCursor(m.cursor, len(m.labels), k)
+		}
+	case viewValues:
+		switch {
+		case k.code == keyEnter && len(m.values) > 0:
+			lm := matcher{name: m.label, value: m.values[m.cursor]}
+			replaced := false
+			for i := range m.matchers {
+				if m.matchers[i].name == lm.name {
+					m.matchers[i] = lm
+					replaced = true
+				}
+			}
+			if !replaced {
+				m.matchers = append(m.matchers, lm)
+			}
+			m.view = viewLabels
+			m.cursor = 0
+			m.status = "loading labels"
+			return actionLoadLabels
+		case k.code == keyEsc || k.code == keyBackspace:
+			m.view = viewLabels
+			m.cursor = 0
+		default:
+			m.cursor = moveCursor(m.cursor, len(m.values), k)
+		}
+	case viewTail:
+		switch {
+		case k.code == keyEsc || k.code == keyBackspace:
+			m.view = viewLabels
+			m.entries = nil
+			m.cursor = 0
+			m.status = ""
+			return actionStopTail
+		case k.code == keyRune && k.r == 'f':
+			m.view = viewFields
+			m.cursor = 0
+		case k.code == keyUp:
+			m.offset = clamp(m.offset+1, 0, len(m.entries)-1)
+		case k.code == keyPgUp:
+			m.offset = clamp(m.offset+10, 0, len(m.entries)-1)
+		case k.code == keyDown:
+			m.offset = clamp(m.offset-1, 0, len(m.entries)-1)
+		case k.code == keyPgDown:
+			m.offset = clamp(m.offset-10, 0, len(m.entries)-1)
+		case k.code == keyEnd || (k.code == keyRune && k.r == 'G'):
+			m.offset = 0
+		case k.code == keyHome:
+			m.offset = clamp(len(m.entries)-1, 0, len(m.entries)-1)
+		}
+	case viewFields:
+		switch {
+		case k.code == keyEsc || k.code == keyBackspace || (k.code == keyRune && k.r == 'f'):
+			m.view = viewTail
+		case (k.code == keyEnter || (k.code == keyRune && k.r == ' ')) && len(m.fields) > 0:
+			m.toggleColumn(m.fields[m.cursor].Label)
+		default:
+			m.cursor = moveCursor(m.cursor, len(m.fields), k)
+		}
+	}
+	return actionNone
+}
+
+func (m *model) toggleColumn(name string) {
+	for i, c := range m.columns {
+		if c == name {
+			m.columns = append(m.columns[:i], m.columns[i+1:]...)
+			return
+		}
+	}
+	m.columns = append(m.columns, name)
+}
+
+func (m *model) columnEnabled(name string) bool {
+	for _, c := range m.columns {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// render returns the lines of the screen for the given size.
+func (m *model) render(width, height int) []string {
+	lines := make([]string, 0, height)
+	lines = append(lines, truncate("logcli explore  "+m.selector(), width))
+
+	var help string
+	switch m.view {
+	case viewLabels:
+		help = "enter: values  t: tail  backspace: remove last matcher  q: quit"
+	case viewValues:
+		help = "enter: add matcher  esc: back  q: quit"
+	case viewTail:
+		help = "up/down/pgup/pgdown: scroll  G: follow  f: fields  esc: back  q: quit"
+	case viewFields:
+		help = "space/enter: toggle column  f/esc: back to tail  q: quit"
+	}
+	lines = append(lines, truncate(help, width))
+
+	bodyHeight := height - 3
+	if bodyHeight < 0 {
+		bodyHeight = 0
+	}
+
+	var body []string
+	switch m.view {
+	case viewLabels:
+		body = renderList(m.labels, m.cursor, bodyHeight, width)
+	case viewValues:
+		body = renderList(m.values, m.cursor, bodyHeight, width)
+	case viewTail:
+		body = m.renderTail(bodyHeight, width)
+	case viewFields:
+		items := make([]string, 0, len(m.fields))
+		for _, f := range m.fields {
+			check := "[ ]"
+			if m.columnEnabled(f.Label) {
+				check = "[x]"
+			}
+			items = append(items, fmt.Sprintf("%s %s (%s, cardinality %d)", check, f.Label, f.Type, f.Cardinality))
+		}
+		body = renderList(items, m.cursor, bodyHeight, width)
+	}
+	lines = append(lines, body...)
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+
+	status := m.status
+	if m.view == viewTail && m.offset > 0 {
+		status = fmt.Sprintf("scrolled back %d entries  %s", m.offset, status)
+	}
+	lines = append(lines, truncate(status, width))
+	return lines
+}
+
+func (m *model) renderTail(height, width int) []string {
+	if height == 0 || len(m.entries) == 0 {
+		return nil
+	}
+
+	// Keep a line for the header of the field columns.
+	rows := height
+	if len(m.columns) > 0 {
+		rows--
+	}
+
+	end := len(m.entries) - m.offset
+	start := end - rows
+	if start < 0 {
+		start = 0
+	}
+	visible := m.entries[start:end]
+
+	// Size the field columns after the values which are visible.
+	widths := make([]int, len(m.columns))
+	for i, c := range m.columns {
+		widths[i] = utf8.RuneCountInString(c)
+	}
+	for _, e := range visible {
+		if len(m.columns) > 0 && e.fields == nil {
+			e.fields = parseFields(e.entry)
+		}
+		for i, c := range m.columns {
+			if l := utf8.RuneCountInString(e.fields[c]); l > widths[i] {
+				widths[i] = l
+			}
+		}
+	}
+	for i := range widths {
+		if widths[i] > maxColumnWidth {
+			widths[i] = maxColumnWidth
+		}
+	}
+
+	lines := make([]string, 0, height)
+	if len(m.columns) > 0 {
+		var b strings.Builder
+		b.WriteString(pad("time", len(timeFormat)))
+		for i, c := range m.columns {
+			b.WriteString(" ")
+			b.WriteString(pad(c, widths[i]))
+		}
+		b.WriteString(" line")
+		lines = append(lines, truncate(b.String(), width))
+	}
+
+	for _, e := range visible {
+		var b strings.Builder
+		b.WriteString(e.entry.Timestamp.Format(timeFormat))
+		for i, c := range m.columns {
+			b.WriteString(" ")
+			b.WriteString(pad(e.fields[c], widths[i]))
+		}
+		b.WriteString(" ")
+		if lbls := m.streamLabels(e.labels); lbls != "" {
+			b.WriteString(lbls)
+			b.WriteString(" ")
+		}
+		b.WriteString(e.entry.Line)
+		lines = append(lines, truncate(b.String(), width))
+	}
+	return lines
+}
+
+// streamLabels formats the labels of a stream which are not already part of the selector.
+func (m *model) streamLabels(lbls loghttp.LabelSet) string {
+	names := make([]string, 0, len(lbls))
+	for name := range lbls {
+		if !m.selected(name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+"="+strconv.Quote(lbls[name]))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+const (
+	timeFormat     = "2006-01-02T15:04:05.000"
+	maxColumnWidth = 30
+)
+
+func renderList(items []string, cursor, height, width int) []string {
+	if height == 0 {
+		return nil
+	}
+	start := 0
+	if cursor >= height {
+		start = cursor - height + 1
+	}
+	end := min(start+height, len(items))
+
+	lines := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		prefix := "  "
+		if i == cursor {
+			prefix = "> "
+		}
+		lines = append(lines, truncate(prefix+items[i], width))
+	}
+	return lines
+}
+
+func moveCursor(cursor, n int, k key) int {
+	switch k.code {
+	case keyUp:
+		cursor--
+	case keyDown:
+		cursor++
+	case keyPgUp:
+		cursor -= 10
+	case keyPgDown:
+		cursor += 10
+	case keyHome:
+		cursor = 0
+	case keyEnd:
+		cursor = n - 1
+	case keyRune:
+		switch k.r {
+		case 'k':
+			cursor--
+		case 'j':
+			cursor++
+		}
+	}
+	return clamp(cursor, 0, n-1)
+}
+
+func clamp(v, lo, hi int) int {
+	if v > hi {
+		v = hi
+	}
+	if v < lo {
+		v = lo
+	}
+	return v
+}
+
+// truncate cuts s to the width of the screen, after escaping the characters which aren't printable so that control
+// sequences in the log lines don't reach the terminal.
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	s = escapeNonPrintable(s)
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	return string([]rune(s)[:width])
+}
+
+func escapeNonPrintable(s string) string {
+	printable := true
+	for _, r := range s {
+		if r != '\t' && !unicode.IsPrint(r) {
+			printable = false
+			break
+		}
+	}
+	if printable {
+		return s
+	}
+
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\t' || unicode.IsPrint(r):
+			b.WriteRune(r)
+		default:
+			quoted := strconv.QuoteRuneToASCII(r)
+			b.WriteString(quoted[1 : len(quoted)-1])
+		}
+	}
+	return b.String()
+}
+
+func pad(s string, width int) string {
+	s = truncate(s, width)
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// parseFields extracts the fields of a log line, either JSON or logfmt, along with its structured metadata.
+// Nested JSON objects are flattened with "_" like the json parser of LogQL.
+func parseFields(entry loghttp.Entry) map[string]string {
+	fields := map[string]string{}
+	for _, l := range entry.StructuredMetadata {
+		fields[l.Name] = l.Value
+	}
+	for _, l := range entry.Parsed {
+		fields[l.Name] = l.Value
+	}
+
+	line := strings.TrimSpace(entry.Line)
+	if strings.HasPrefix(line, "{") {
+		var obj map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &obj); err == nil {
+			flattenJSON(fields, "", obj)
+			return fields
+		}
+	}
+
+	dec := logfmt.NewDecoder(strings.NewReader(line))
+	for dec.ScanRecord() {
+		for dec.ScanKeyval() {
+			if len(dec.Value()) > 0 {
+				fields[string(dec.Key())] = string(dec.Value())
+			}
+		}
+	}
+	return fields
+}
+
+func flattenJSON(fields map[string]string, prefix string, obj map[string]interface{}) {
+	for k, v := range obj {
+		name := k
+		if prefix != "" {
+			name = prefix + "_" + k
+		}
+		switch v := v.(type) {
+		case map[string]interface{}:
+			flattenJSON(fields, name, v)
+		case string:
+			fields[name] = v
+		case nil:
+		default:
+			b, err := json.Marshal(v)
+			if err == nil {
+				fields[name] = string(b)
+			}
+		}
+	}
+}
+
+// labelsFromSeries returns the label names of the series which are not part of the selector.
+func labelsFromSeries(series []loghttp.LabelSet, selected func(string) bool) []string {
+	set := map[string]struct{}{}
+	for _, s := range series {
+		for name := range s {
+			if !selected(name) {
+				set[name] = struct{}{}
+			}
+		}
+	}
+	return sortedKeys(set)
+}
+
+// valuesFromSeries returns the values of the label in the series.
+func valuesFromSeries(series []loghttp.LabelSet, name string) []string {
+	set := map[string]struct{}{}
+	for _, s := range series {
+		if v, ok := s[name]; ok {
+			set[v] = struct{}{}
+		}
+	}
+	return sortedKeys(set)
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// parseKeys parses the bytes read from a terminal in raw mode into keys.
+func parseKeys(b []byte) []key {
+	var keys []key
+	for len(b) > 0 {
+		switch {
+		case b[0] == 0x1b && len(b) >= 3 && b[1] == '[':
+			n := 3
+			switch b[2] {
+			case 'A':
+				keys = append(keys, key{code: keyUp})
+			case 'B':
+				keys = append(keys, key{code: keyDown})
+			case 'H':
+				keys = append(keys, key{code: keyHome})
+			case 'F':
+				keys = append(keys, key{code: keyEnd})
+			case '5', '6', '1', '4':
+				if len(b) >= 4 && b[3] == '~' {
+					n = 4
+					switch b[2] {
+					case '5':
+						keys = append(keys, key{code: keyPgUp})
+					case '6':
+						keys = append(keys, key{code: keyPgDown})
+					case '1':
+						keys = append(keys, key{code: keyHome})
+					case '4':
+						keys = append(keys, key{code: keyEnd})
+					}
+				}
+			}
+			b = b[n:]
+			continue
+		case b[0] == 0x1b:
+			keys = append(keys, key{code: keyEsc})
+		case b[0] == '\r' || b[0] == '\n':
+			keys = append(keys, key{code: keyEnter})
+		case b[0] == 0x7f || b[0] == 0x08:
+			keys = append(keys, key{code: keyBackspace})
+		case b[0] == 0x03:
+			keys = append(keys, key{code: keyCtrlC})
+		default:
+			r, size := utf8.DecodeRune(b)
+			keys = append(keys, key{code: keyRune, r: r})
+			b = b[size:]
+			continue
+		}
+		b = b[1:]
+	}
+	return keys
+}
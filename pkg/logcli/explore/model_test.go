@@ -0,0 +1,172 @@
+package explore
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/loghttp"
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+func TestParseKeys(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   string
+		want []key
+	}{
+		{"runes", "tq", []key{{code: keyRune, r: 't'}, {code: keyRune, r: 'q'}}},
+		{"arrows", "\x1b[A\x1b[B", []key{{code: keyUp}, {code: keyDown}}},
+		{"pages", "\x1b[5~\x1b[6~", []key{{code: keyPgUp}, {code: keyPgDown}}},
+		{"home and end", "\x1b[H\x1b[4~", []key{{code: keyHome}, {code: keyEnd}}},
+		{"escape", "\x1b", []key{{code: keyEsc}}},
+		{"control", "\r\x7f\x03", []key{{code: keyEnter}, {code: keyBackspace}, {code: keyCtrlC}}},
+		{"unicode", "é", []key{{code: keyRune, r: 'é'}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, parseKeys([]byte(tc.in)))
+		})
+	}
+}
+
+func TestModel_DrillDown(t *testing.T) {
+	m := newModel(10)
+	require.Equal(t, "{}", m.selector())
+
+	// Tailing requires at least one matcher.
+	require.Equal(t, actionNone, m.update(key{code: keyRune, r: 't'}))
+	require.Equal(t, viewLabels, m.view)
+
+	m.update(labelsMsg{labels: []string{"app", "env"}})
+	m.update(key{code: keyDown})
+	require.Equal(t, actionLoadValues, m.update(key{code: keyEnter}))
+	require.Equal(t, viewValues, m.view)
+	require.Equal(t, "env", m.label)
+
+	// Values of another label are ignored.
+	m.update(valuesMsg{label: "app", values: []string{"api"}})
+	require.Nil(t, m.values)
+
+	m.update(valuesMsg{label: "env", values: []string{"dev", `pro"d`}})
+	m.update(key{code: keyRune, r: 'j'})
+	require.Equal(t, actionLoadLabels, m.update(key{code: keyEnter}))
+	require.Equal(t, viewLabels, m.view)
+	require.Equal(t, `{env="pro\"d"}`, m.selector())
+
+	// Selecting another value of the same label replaces the matcher.
+	m.update(labelsMsg{labels: []string{"env"}})
+	m.update(key{code: keyEnter})
+	m.update(valuesMsg{label: "env", values: []string{"dev"}})
+	m.update(key{code: keyEnter})
+	require.Equal(t, `{env="dev"}`, m.selector())
+
+	require.Equal(t, actionStartTail, m.update(key{code: keyRune, r: 't'}))
+	require.Equal(t, viewTail, m.view)
+	require.Equal(t, actionStopTail, m.update(key{code: keyEsc}))
+	require.Equal(t, viewLabels, m.view)
+
+	require.Equal(t, actionLoadLabels, m.update(key{code: keyBackspace}))
+	require.Equal(t, "{}", m.selector())
+
+	require.Equal(t, actionQuit, m.update(key{code: keyRune, r: 'q'}))
+}
+
+func TestModel_Tail(t *testing.T) {
+	m := newModel(3)
+	m.matchers = []matcher{{name: "app", value: "api"}}
+	m.update(key{code: keyRune, r: 't'})
+
+	now := time.Unix(0, 0).UTC()
+	for i, line := range []string{"a", "b", "c", "d"} {
+		m.update(entryMsg{
+			labels: loghttp.LabelSet{"app": "api", "pod": "p1"},
+			entry:  loghttp.Entry{Timestamp: now.Add(time.Duration(i) * time.Second), Line: line},
+		})
+	}
+	require.Len(t, m.entries, 3)
+	require.Equal(t, "b", m.entries[0].entry.Line)
+
+	lines := m.render(80, 5)
+	require.Len(t, lines, 5)
+	require.Equal(t, `1970-01-01T00:00:02.000 {pod="p1"} c`, lines[2])
+	require.Equal(t, `1970-01-01T00:00:03.000 {pod="p1"} d`, lines[3])
+
+	// Scrolling back stays on the same entries when new ones arrive.
+	m.update(key{code: keyUp})
+	require.Equal(t, 1, m.offset)
+	m.update(entryMsg{entry: loghttp.Entry{Timestamp: now, Line: "e"}})
+	require.Equal(t, 2, m.offset)
+	require.Contains(t, m.render(80, 5)[4], "scrolled back 2 entries")
+
+	m.update(key{code: keyRune, r: 'G'})
+	require.Equal(t, 0, m.offset)
+}
+
+func TestModel_Columns(t *testing.T) {
+	m := newModel(10)
+	m.matchers = []matcher{{name: "app", value: "api"}}
+	m.update(key{code: keyRune, r: 't'})
+	m.update(fieldsMsg{fields: []*logproto.DetectedField{
+		{Label: "level", Type: logproto.DetectedFieldString, Cardinality: 3},
+		{Label: "status", Type: logproto.DetectedFieldInt, Cardinality: 10},
+	}})
+	m.update(entryMsg{entry: loghttp.Entry{Timestamp: time.Unix(0, 0).UTC(), Line: "level=info status=200 msg=done"}})
+
+	m.update(key{code: keyRune, r: 'f'})
+	require.Equal(t, viewFields, m.view)
+	m.update(key{code: keyDown})
+	m.update(key{code: keyRune, r: ' '})
+	require.Equal(t, []string{"status"}, m.columns)
+	require.Contains(t, m.render(80, 6)[3], "> [x] status")
+
+	m.update(key{code: keyEsc})
+	require.Equal(t, viewTail, m.view)
+	lines := m.render(80, 6)
+	require.Equal(t, "time                    status line", lines[2])
+	require.Equal(t, "1970-01-01T00:00:00.000 200    level=info status=200 msg=done", lines[3])
+
+	m.toggleColumn("status")
+	require.Empty(t, m.columns)
+}
+
+func TestParseFields(t *testing.T) {
+	fields := parseFields(loghttp.Entry{
+		Line:               `{"level":"info","req":{"status":200,"path":"/"},"empty":null}`,
+		StructuredMetadata: labels.Labels{{Name: "trace_id", Value: "abc"}},
+	})
+	require.Equal(t, map[string]string{
+		"level":      "info",
+		"req_status": "200",
+		"req_path":   "/",
+		"trace_id":   "abc",
+	}, fields)
+
+	fields = parseFields(loghttp.Entry{Line: `level=warn msg="slow query" empty=`})
+	require.Equal(t, map[string]string{"level": "warn", "msg": "slow query"}, fields)
+}
+
+func TestRenderList(t *testing.T) {
+	items := []string{"a", "b", "c", "d"}
+	require.Equal(t, []string{"  a", "> b"}, renderList(items, 1, 2, 10))
+	require.Equal(t, []string{"  c", "> d"}, renderList(items, 3, 2, 10))
+	require.Equal(t, []string{">"}, renderList(items, 0, 1, 1))
+	require.True(t, strings.HasPrefix(truncate("héllo", 2), "h"))
+}
+
+func TestTruncateEscapesNonPrintable(t *testing.T) {
+	require.Equal(t, `\x1b[2Jcleared\r\n`, truncate("\x1b[2Jcleared\r\n", 80))
+	require.Equal(t, `\x1b[`, truncate("\x1b[2J", 5))
+	require.Equal(t, "héllo\twörld", truncate("héllo\twörld", 80))
+}
+
+func TestFromSeries(t *testing.T) {
+	series := []loghttp.LabelSet{
+		{"app": "api", "env": "prod"},
+		{"app": "api", "env": "dev", "pod": "p1"},
+	}
+	require.Equal(t, []string{"env", "pod"}, labelsFromSeries(series, func(name string) bool { return name == "app" }))
+	require.Equal(t, []string{"dev", "prod"}, valuesFromSeries(series, "env"))
+}
@@ -31,6 +31,9 @@ func do(q *volume.Query, rangeQuery bool, c client.Client, out output.LogOutput,
 	}
 
 	_, _ = resultsPrinter.PrintResult(resp.Data.Result, out, nil)
+	if err := output.Flush(out); err != nil {
+		log.Fatalf("Error writing output: %+v", err)
+	}
 }
 
 // getVolume returns a volume result
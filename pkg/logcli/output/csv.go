@@ -0,0 +1,119 @@
+package output
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/grafana/loki/v3/pkg/loghttp"
+)
+
+// CSVOutput prints logs and metric samples as CSV with one column per label, suitable for spreadsheets.
+// The first rows are buffered to find the label columns, see table.
+type CSVOutput struct {
+	w       io.Writer
+	options *LogOutputOptions
+	table   *table
+}
+
+func newCSVOutput(w io.Writer, options *LogOutputOptions) *CSVOutput {
+	o := &CSVOutput{
+		w:       w,
+		options: options,
+	}
+	o.table = newTable(&csvTableWriter{w: w, options: options})
+	return o
+}
+
+// FormatAndPrintln adds a log entry as a CSV row
+func (o *CSVOutput) FormatAndPrintln(ts time.Time, lbls loghttp.LabelSet, _ int, line string) {
+	o.table.addEntry(ts, lbls, loghttp.Entry{Line: line}, !o.options.NoLabels)
+}
+
+// FormatAndPrintEntry adds a log entry as a CSV row, including its structured metadata and parsed labels
+func (o *CSVOutput) FormatAndPrintEntry(lbls loghttp.LabelSet, entry loghttp.Entry) {
+	o.table.addEntry(entry.Timestamp, lbls, entry, !o.options.NoLabels)
+}
+
+// PrintMatrix adds the samples of a matrix as CSV rows
+func (o *CSVOutput) PrintMatrix(matrix loghttp.Matrix) {
+	o.table.addMatrix(matrix)
+}
+
+// PrintVector adds the samples of a vector as CSV rows
+func (o *CSVOutput) PrintVector(vector loghttp.Vector) {
+	o.table.addVector(vector)
+}
+
+// PrintScalar adds a scalar as a CSV row
+func (o *CSVOutput) PrintScalar(scalar loghttp.Scalar) {
+	o.table.addScalar(scalar)
+}
+
+// Flush writes the remaining rows. Nothing is written if there are no rows.
+func (o *CSVOutput) Flush() error {
+	return o.table.flush()
+}
+
+type csvTableWriter struct {
+	w       io.Writer
+	options *LogOutputOptions
+
+	csv         *csv.Writer
+	columns     []string
+	otherLabels bool
+	metrics     bool
+	record      []string
+}
+
+func (w *csvTableWriter) writeHeader(columns []string, otherLabels, metrics bool) error {
+	w.csv = csv.NewWriter(w.w)
+	w.columns, w.otherLabels, w.metrics = columns, otherLabels, metrics
+
+	w.record = make([]string, 0, len(columns)+3)
+	w.record = append(w.record, timestampColumn)
+	w.record = append(w.record, columns...)
+	if otherLabels {
+		w.record = append(w.record, otherLabelsColumn)
+	}
+	if metrics {
+		w.record = append(w.record, valueColumn)
+	} else {
+		w.record = append(w.record, lineColumn)
+	}
+	return w.csv.Write(w.record)
+}
+
+func (w *csvTableWriter) writeRows(rows []row) error {
+	for _, r := range rows {
+		w.record = w.record[:0]
+		w.record = append(w.record, r.ts.In(w.options.Timezone).Format(time.RFC3339Nano))
+		for _, name := range w.columns {
+			w.record = append(w.record, r.labels[name])
+		}
+		if w.otherLabels {
+			w.record = append(w.record, otherLabels(w.columns, r.labels))
+		}
+		if w.metrics {
+			w.record = append(w.record, strconv.FormatFloat(r.value, 'f', -1, 64))
+		} else {
+			w.record = append(w.record, r.line)
+		}
+		if err := w.csv.Write(w.record); err != nil {
+			return err
+		}
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+func (w *csvTableWriter) close() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+// WithWriter returns a copy of the LogOutput with the writer set to the given writer
+func (o *CSVOutput) WithWriter(w io.Writer) LogOutput {
+	return newCSVOutput(w, o.options)
+}
@@ -0,0 +1,107 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/loghttp"
+)
+
+func TestCSVOutput_Streams(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := map[string]struct {
+		options  *LogOutputOptions
+		expected string
+	}{
+		"with labels": {
+			&LogOutputOptions{Timezone: time.UTC},
+			"timestamp,app,line_extracted,trace_id,line\n" +
+				"2006-01-02T15:04:05Z,foo,,abc,\"hello, world\"\n" +
+				"2006-01-02T15:04:06Z,bar,x,,second\n",
+		},
+		"labels output disabled": {
+			&LogOutputOptions{Timezone: time.UTC, NoLabels: true},
+			"timestamp,line\n" +
+				"2006-01-02T15:04:05Z,\"hello, world\"\n" +
+				"2006-01-02T15:04:06Z,second\n",
+		},
+	}
+
+	for name, data := range tests {
+		data := data
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			out, err := NewLogOutput(buf, "csv", data.options)
+			require.NoError(t, err)
+
+			out.(EntryOutput).FormatAndPrintEntry(loghttp.LabelSet{"app": "foo"}, loghttp.Entry{
+				Timestamp:          ts,
+				Line:               "hello, world",
+				StructuredMetadata: labels.FromStrings("trace_id", "abc"),
+			})
+			out.FormatAndPrintln(ts.Add(time.Second), loghttp.LabelSet{"app": "bar", "line": "x"}, 0, "second")
+			require.Empty(t, buf.String())
+
+			require.NoError(t, Flush(out))
+			require.Equal(t, data.expected, buf.String())
+		})
+	}
+}
+
+func TestCSVOutput_Matrix(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	out, err := NewLogOutput(buf, "csv", &LogOutputOptions{Timezone: time.UTC})
+	require.NoError(t, err)
+
+	out.(MetricOutput).PrintMatrix(loghttp.Matrix{
+		{Metric: model.Metric{"app": "foo"}, Values: []model.SamplePair{{Timestamp: 1000, Value: 1.5}, {Timestamp: 2000, Value: 2}}},
+		{Metric: model.Metric{"level": "error"}, Values: []model.SamplePair{{Timestamp: 1000, Value: 3}}},
+	})
+	require.NoError(t, Flush(out))
+	require.Equal(t, "timestamp,app,level,value\n"+
+		"1970-01-01T00:00:01Z,foo,,1.5\n"+
+		"1970-01-01T00:00:02Z,foo,,2\n"+
+		"1970-01-01T00:00:01Z,,error,3\n", buf.String())
+}
+
+func TestCSVOutput_FlushEmpty(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	out, err := NewLogOutput(buf, "csv", &LogOutputOptions{Timezone: time.UTC})
+	require.NoError(t, err)
+	require.NoError(t, Flush(out))
+	require.Empty(t, buf.String())
+}
+
+func TestCSVOutput_StreamsRows(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	out, err := NewLogOutput(buf, "csv", &LogOutputOptions{Timezone: time.UTC})
+	require.NoError(t, err)
+
+	ts := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	for i := 0; i < maxBufferedRows; i++ {
+		out.FormatAndPrintln(ts, loghttp.LabelSet{"app": "foo"}, 0, "line")
+	}
+	// the rows are written once the columns are known.
+	require.Equal(t, maxBufferedRows+1, strings.Count(buf.String(), "\n"))
+	require.True(t, strings.HasPrefix(buf.String(), "timestamp,app,other_labels,line\n2006-01-02T15:04:05Z,foo,,line\n"))
+
+	out.FormatAndPrintln(ts, loghttp.LabelSet{"app": "bar", "pod": "bar-1"}, 0, "new label")
+	require.NoError(t, Flush(out))
+	require.True(t, strings.HasSuffix(buf.String(), "2006-01-02T15:04:05Z,bar,\"{pod=\"\"bar-1\"\"}\",new label\n"))
+}
@@ -0,0 +1,129 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/v3/pkg/loghttp"
+)
+
+// DefaultMetricName is the name given to the series of metric query results in OpenMetrics output,
+// since LogQL metric queries don't return a metric name.
+const DefaultMetricName = "logql_query_result"
+
+// OpenMetricsOutput prints the results of metric queries in the OpenMetrics text format,
+// suitable for importing them into Prometheus compatible tools.
+type OpenMetricsOutput struct {
+	w       io.Writer
+	options *LogOutputOptions
+
+	headerWritten bool
+}
+
+// FormatAndPrintln fails since log lines can't be printed as OpenMetrics
+func (o *OpenMetricsOutput) FormatAndPrintln(_ time.Time, _ loghttp.LabelSet, _ int, _ string) {
+	log.Fatalf("openmetrics output only supports metric queries")
+}
+
+// PrintMatrix prints every sample of the matrix
+func (o *OpenMetricsOutput) PrintMatrix(matrix loghttp.Matrix) {
+	o.writeHeader()
+	for _, s := range matrix {
+		lbls := formatMetricLabels(s.Metric)
+		for _, v := range s.Values {
+			o.writeSample(lbls, v.Value, v.Timestamp)
+		}
+	}
+}
+
+// PrintVector prints every sample of the vector
+func (o *OpenMetricsOutput) PrintVector(vector loghttp.Vector) {
+	o.writeHeader()
+	for _, s := range vector {
+		o.writeSample(formatMetricLabels(s.Metric), s.Value, s.Timestamp)
+	}
+}
+
+// PrintScalar prints the scalar as a sample without labels
+func (o *OpenMetricsOutput) PrintScalar(scalar loghttp.Scalar) {
+	o.writeHeader()
+	o.writeSample("", scalar.Value, scalar.Timestamp)
+}
+
+// Flush terminates the exposition with the EOF marker
+func (o *OpenMetricsOutput) Flush() error {
+	if !o.headerWritten {
+		return nil
+	}
+	o.headerWritten = false
+	_, err := fmt.Fprintln(o.w, "# EOF")
+	return err
+}
+
+// WithWriter returns a copy of the LogOutput with the writer set to the given writer
+func (o *OpenMetricsOutput) WithWriter(w io.Writer) LogOutput {
+	return &OpenMetricsOutput{
+		w:       w,
+		options: o.options,
+	}
+}
+
+func (o *OpenMetricsOutput) writeHeader() {
+	if o.headerWritten {
+		return
+	}
+	o.headerWritten = true
+	fmt.Fprintf(o.w, "# TYPE %s gauge\n", DefaultMetricName)
+}
+
+func (o *OpenMetricsOutput) writeSample(lbls string, value model.SampleValue, ts model.Time) {
+	fmt.Fprintf(o.w, "%s%s %s %s\n",
+		DefaultMetricName,
+		lbls,
+		strconv.FormatFloat(float64(value), 'g', -1, 64),
+		strconv.FormatFloat(float64(ts)/1e3, 'f', -1, 64),
+	)
+}
+
+// formatMetricLabels formats the labels of a series sorted by name,
+// the metric name label is dropped since all results share the same name.
+func formatMetricLabels(m model.Metric) string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		if name == model.MetricNameLabel {
+			continue
+		}
+		names = append(names, string(name))
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name)
+		b.WriteString(`="`)
+		b.WriteString(escapeLabelValue(string(m[model.LabelName(name)])))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeLabelValue(v string) string {
+	return labelValueReplacer.Replace(v)
+}
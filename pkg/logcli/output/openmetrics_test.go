@@ -0,0 +1,69 @@
+package output
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/loghttp"
+)
+
+func TestOpenMetricsOutput(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		print    func(o MetricOutput)
+		expected string
+	}{
+		"matrix": {
+			func(o MetricOutput) {
+				o.PrintMatrix(loghttp.Matrix{
+					{Metric: model.Metric{"app": "foo", "msg": "a \"quoted\"\nvalue"}, Values: []model.SamplePair{{Timestamp: 1500, Value: 1.5}, {Timestamp: 2000, Value: model.SampleValue(math.Inf(1))}}},
+				})
+			},
+			"# TYPE logql_query_result gauge\n" +
+				"logql_query_result{app=\"foo\",msg=\"a \\\"quoted\\\"\\nvalue\"} 1.5 1.5\n" +
+				"logql_query_result{app=\"foo\",msg=\"a \\\"quoted\\\"\\nvalue\"} +Inf 2\n" +
+				"# EOF\n",
+		},
+		"vector": {
+			func(o MetricOutput) {
+				o.PrintVector(loghttp.Vector{
+					{Metric: model.Metric{"__name__": "ignored", "level": "error"}, Timestamp: 1000, Value: 3},
+					{Metric: model.Metric{}, Timestamp: 1000, Value: 4},
+				})
+			},
+			"# TYPE logql_query_result gauge\n" +
+				"logql_query_result{level=\"error\"} 3 1\n" +
+				"logql_query_result 4 1\n" +
+				"# EOF\n",
+		},
+		"scalar": {
+			func(o MetricOutput) {
+				o.PrintScalar(loghttp.Scalar{Timestamp: 1000, Value: 42})
+			},
+			"# TYPE logql_query_result gauge\n" +
+				"logql_query_result 42 1\n" +
+				"# EOF\n",
+		},
+	}
+
+	for name, data := range tests {
+		data := data
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			out, err := NewLogOutput(buf, "openmetrics", &LogOutputOptions{Timezone: time.UTC})
+			require.NoError(t, err)
+
+			data.print(out.(MetricOutput))
+			require.NoError(t, Flush(out))
+			require.Equal(t, data.expected, buf.String())
+		})
+	}
+}
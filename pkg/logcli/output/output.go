@@ -61,6 +61,15 @@ func NewLogOutput(w io.Writer, mode string, options *LogOutputOptions) (LogOutpu
 			w:       w,
 			options: options,
 		}, nil
+	case "csv":
+		return newCSVOutput(w, options), nil
+	case "parquet":
+		return newParquetOutput(w, options), nil
+	case "openmetrics":
+		return &OpenMetricsOutput{
+			w:       w,
+			options: options,
+		}, nil
 	default:
 		return nil, fmt.Errorf("unknown log output mode '%s'", mode)
 	}
@@ -0,0 +1,127 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/grafana/loki/v3/pkg/loghttp"
+)
+
+// ParquetOutput writes logs and metric samples as a Parquet file with one optional string column per label.
+// The first rows are buffered to find the label columns, see table, and the following rows are written in row groups
+// of up to maxBufferedRows rows.
+type ParquetOutput struct {
+	w       io.Writer
+	options *LogOutputOptions
+	table   *table
+}
+
+func newParquetOutput(w io.Writer, options *LogOutputOptions) *ParquetOutput {
+	return &ParquetOutput{
+		w:       w,
+		options: options,
+		table:   newTable(&parquetTableWriter{w: w}),
+	}
+}
+
+// FormatAndPrintln adds a log entry as a Parquet row
+func (o *ParquetOutput) FormatAndPrintln(ts time.Time, lbls loghttp.LabelSet, _ int, line string) {
+	o.table.addEntry(ts, lbls, loghttp.Entry{Line: line}, !o.options.NoLabels)
+}
+
+// FormatAndPrintEntry adds a log entry as a Parquet row, including its structured metadata and parsed labels
+func (o *ParquetOutput) FormatAndPrintEntry(lbls loghttp.LabelSet, entry loghttp.Entry) {
+	o.table.addEntry(entry.Timestamp, lbls, entry, !o.options.NoLabels)
+}
+
+// PrintMatrix adds the samples of a matrix as Parquet rows
+func (o *ParquetOutput) PrintMatrix(matrix loghttp.Matrix) {
+	o.table.addMatrix(matrix)
+}
+
+// PrintVector adds the samples of a vector as Parquet rows
+func (o *ParquetOutput) PrintVector(vector loghttp.Vector) {
+	o.table.addVector(vector)
+}
+
+// PrintScalar adds a scalar as a Parquet row
+func (o *ParquetOutput) PrintScalar(scalar loghttp.Scalar) {
+	o.table.addScalar(scalar)
+}
+
+// Flush writes the remaining rows and closes the Parquet file. Nothing is written if there are no rows.
+func (o *ParquetOutput) Flush() error {
+	return o.table.flush()
+}
+
+type parquetTableWriter struct {
+	w io.Writer
+
+	parquet     *parquet.Writer
+	columns     []string
+	otherLabels bool
+	metrics     bool
+}
+
+func (w *parquetTableWriter) writeHeader(columns []string, otherLabels, metrics bool) error {
+	if len(columns)+3 > parquet.MaxColumnIndex+1 {
+		return fmt.Errorf("too many label columns for parquet output: %d", len(columns))
+	}
+	w.columns, w.otherLabels, w.metrics = columns, otherLabels, metrics
+
+	group := parquet.Group{
+		timestampColumn: parquet.Timestamp(parquet.Nanosecond),
+	}
+	for _, name := range columns {
+		group[name] = parquet.Optional(parquet.String())
+	}
+	if otherLabels {
+		group[otherLabelsColumn] = parquet.Optional(parquet.String())
+	}
+	if metrics {
+		group[valueColumn] = parquet.Leaf(parquet.DoubleType)
+	} else {
+		group[lineColumn] = parquet.String()
+	}
+	w.parquet = parquet.NewWriter(w.w, parquet.NewSchema("logcli", group))
+	return nil
+}
+
+// writeRows writes the rows as a row group.
+func (w *parquetTableWriter) writeRows(rows []row) error {
+	for _, r := range rows {
+		values := make(map[string]interface{}, len(r.labels)+2)
+		for _, name := range w.columns {
+			if value, ok := r.labels[name]; ok {
+				values[name] = value
+			}
+		}
+		if w.otherLabels {
+			if other := otherLabels(w.columns, r.labels); other != "" {
+				values[otherLabelsColumn] = other
+			}
+		}
+		values[timestampColumn] = r.ts
+		if w.metrics {
+			values[valueColumn] = r.value
+		} else {
+			values[lineColumn] = r.line
+		}
+		if err := w.parquet.Write(values); err != nil {
+			return err
+		}
+	}
+	return w.parquet.Flush()
+}
+
+func (w *parquetTableWriter) close() error {
+	return w.parquet.Close()
+}
+
+// WithWriter returns a copy of the LogOutput with the writer set to the given writer
+func (o *ParquetOutput) WithWriter(w io.Writer) LogOutput {
+	return newParquetOutput(w, o.options)
+}
@@ -0,0 +1,129 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/loghttp"
+)
+
+func readParquet(t *testing.T, b []byte) ([]string, []map[string]string) {
+	t.Helper()
+
+	f, err := parquet.OpenFile(bytes.NewReader(b), int64(len(b)))
+	require.NoError(t, err)
+
+	var columns []string
+	for _, field := range f.Schema().Fields() {
+		columns = append(columns, field.Name())
+	}
+
+	r := parquet.NewReader(f)
+	defer r.Close()
+
+	var rows []map[string]string
+	for i := int64(0); i < r.NumRows(); i++ {
+		row := map[string]interface{}{}
+		require.NoError(t, r.Read(&row))
+
+		values := map[string]string{}
+		for name, v := range row {
+			switch v := v.(type) {
+			case nil:
+			case time.Time:
+				values[name] = v.UTC().Format(time.RFC3339Nano)
+			case []byte:
+				values[name] = string(v)
+			default:
+				values[name] = fmtValue(v)
+			}
+		}
+		rows = append(rows, values)
+	}
+	return columns, rows
+}
+
+func fmtValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return model.SampleValue(v).String()
+	case int64:
+		return time.Unix(0, v).UTC().Format(time.RFC3339Nano)
+	}
+	return ""
+}
+
+func TestParquetOutput_Streams(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	out, err := NewLogOutput(buf, "parquet", &LogOutputOptions{Timezone: time.UTC})
+	require.NoError(t, err)
+
+	ts := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	out.(EntryOutput).FormatAndPrintEntry(loghttp.LabelSet{"app": "foo"}, loghttp.Entry{
+		Timestamp:          ts,
+		Line:               "hello",
+		StructuredMetadata: labels.FromStrings("trace_id", "abc"),
+	})
+	out.FormatAndPrintln(ts.Add(time.Second), loghttp.LabelSet{"app": "bar"}, 0, "world")
+	require.NoError(t, Flush(out))
+
+	columns, rows := readParquet(t, buf.Bytes())
+	require.Equal(t, []string{"app", "line", "timestamp", "trace_id"}, columns)
+	require.Equal(t, []map[string]string{
+		{"timestamp": "2006-01-02T15:04:05Z", "app": "foo", "trace_id": "abc", "line": "hello"},
+		{"timestamp": "2006-01-02T15:04:06Z", "app": "bar", "line": "world"},
+	}, rows)
+}
+
+func TestParquetOutput_Vector(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	out, err := NewLogOutput(buf, "parquet", &LogOutputOptions{Timezone: time.UTC})
+	require.NoError(t, err)
+
+	out.(MetricOutput).PrintVector(loghttp.Vector{
+		{Metric: model.Metric{"level": "error"}, Timestamp: 1000, Value: 3},
+	})
+	require.NoError(t, Flush(out))
+
+	columns, rows := readParquet(t, buf.Bytes())
+	require.Equal(t, []string{"level", "timestamp", "value"}, columns)
+	require.Equal(t, []map[string]string{
+		{"timestamp": "1970-01-01T00:00:01Z", "level": "error", "value": "3"},
+	}, rows)
+}
+
+func TestParquetOutput_RowGroups(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	out, err := NewLogOutput(buf, "parquet", &LogOutputOptions{Timezone: time.UTC})
+	require.NoError(t, err)
+
+	ts := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	for i := 0; i < 2*maxBufferedRows; i++ {
+		out.FormatAndPrintln(ts, loghttp.LabelSet{"app": "foo"}, 0, "line")
+	}
+	out.FormatAndPrintln(ts, loghttp.LabelSet{"app": "bar", "pod": "bar-1"}, 0, "new label")
+	require.NoError(t, Flush(out))
+
+	f, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Len(t, f.RowGroups(), 3)
+
+	columns, rows := readParquet(t, buf.Bytes())
+	require.Equal(t, []string{"app", "line", "other_labels", "timestamp"}, columns)
+	require.Len(t, rows, 2*maxBufferedRows+1)
+	require.Equal(t, map[string]string{"timestamp": "2006-01-02T15:04:05Z", "app": "bar", "other_labels": `{pod="bar-1"}`, "line": "new label"}, rows[2*maxBufferedRows])
+}
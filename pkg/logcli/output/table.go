@@ -0,0 +1,250 @@
+package output
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/loghttp"
+)
+
+// EntryOutput is implemented by log outputs which also print the structured metadata
+// and parsed labels of log entries.
+type EntryOutput interface {
+	FormatAndPrintEntry(lbls loghttp.LabelSet, entry loghttp.Entry)
+}
+
+// MetricOutput is implemented by outputs which print the results of metric queries themselves.
+type MetricOutput interface {
+	PrintMatrix(matrix loghttp.Matrix)
+	PrintVector(vector loghttp.Vector)
+	PrintScalar(scalar loghttp.Scalar)
+}
+
+// Flusher is implemented by outputs which buffer the results and write them once
+// all of them have been printed.
+type Flusher interface {
+	Flush() error
+}
+
+// Flush flushes the output if it buffers results.
+func Flush(out LogOutput) error {
+	if f, ok := out.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Names of the columns which are not labels.
+const (
+	timestampColumn   = "timestamp"
+	lineColumn        = "line"
+	valueColumn       = "value"
+	otherLabelsColumn = "other_labels"
+)
+
+// maxBufferedRows is the number of rows a table buffers to find its label columns, before
+// it starts writing them.
+const maxBufferedRows = 1000
+
+// tableWriter writes the rows of a table.
+type tableWriter interface {
+	// writeHeader is called once with the sorted label columns, before the first rows are written.
+	// otherLabels is true if the rows can have labels which are not in the label columns.
+	writeHeader(columns []string, otherLabels, metrics bool) error
+	writeRows(rows []row) error
+	close() error
+}
+
+// table writes log entries or metric samples as rows with flattened label columns,
+// for outputs which have to know all the columns before writing the first row.
+// The label columns are found in the first rows, which are buffered. The labels of the
+// following rows which are not in those columns are written in the other_labels column.
+type table struct {
+	mtx     sync.Mutex
+	w       tableWriter
+	columns map[string]struct{}
+	rows    []row
+	metrics bool
+	started bool
+	err     error
+}
+
+type row struct {
+	ts     time.Time
+	labels map[string]string
+	line   string
+	value  float64
+}
+
+func newTable(w tableWriter) *table {
+	return &table{w: w, columns: map[string]struct{}{}}
+}
+
+// addEntry adds a log entry, stream labels are overridden by structured metadata
+// and parsed labels of the same name like in LogQL.
+func (t *table) addEntry(ts time.Time, lbls loghttp.LabelSet, entry loghttp.Entry, withLabels bool) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	r := row{ts: ts, line: entry.Line}
+	if withLabels {
+		r.labels = make(map[string]string, len(lbls)+len(entry.StructuredMetadata)+len(entry.Parsed))
+		for name, value := range lbls {
+			r.labels[labelColumn(name)] = value
+		}
+		for _, l := range entry.StructuredMetadata {
+			r.labels[labelColumn(l.Name)] = l.Value
+		}
+		for _, l := range entry.Parsed {
+			r.labels[labelColumn(l.Name)] = l.Value
+		}
+	}
+	t.add(r)
+}
+
+func (t *table) addMatrix(matrix loghttp.Matrix) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.setMetrics()
+	for _, s := range matrix {
+		lbls := metricLabels(s.Metric)
+		for _, v := range s.Values {
+			t.add(row{ts: v.Timestamp.Time(), labels: lbls, value: float64(v.Value)})
+		}
+	}
+}
+
+func (t *table) addVector(vector loghttp.Vector) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.setMetrics()
+	for _, s := range vector {
+		t.add(row{ts: s.Timestamp.Time(), labels: metricLabels(s.Metric), value: float64(s.Value)})
+	}
+}
+
+func (t *table) addScalar(scalar loghttp.Scalar) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.setMetrics()
+	t.add(row{ts: scalar.Timestamp.Time(), value: float64(scalar.Value)})
+}
+
+func (t *table) setMetrics() {
+	if !t.started {
+		t.metrics = true
+	}
+}
+
+// add buffers a row, and writes the buffered rows once there are maxBufferedRows of them.
+func (t *table) add(r row) {
+	if !t.started {
+		for name := range r.labels {
+			t.columns[name] = struct{}{}
+		}
+	}
+	t.rows = append(t.rows, r)
+	if len(t.rows) < maxBufferedRows || t.err != nil {
+		return
+	}
+
+	if !t.started {
+		t.err = t.start(true)
+	}
+	if t.err == nil {
+		t.err = t.w.writeRows(t.rows)
+	}
+	t.rows = t.rows[:0]
+}
+
+func (t *table) start(otherLabels bool) error {
+	t.started = true
+	return t.w.writeHeader(t.labelColumns(), otherLabels, t.metrics)
+}
+
+// flush writes the buffered rows and closes the writer. Nothing is written if there are no rows.
+func (t *table) flush() error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	defer t.reset()
+
+	if t.err != nil {
+		return t.err
+	}
+	if !t.started {
+		if len(t.rows) == 0 {
+			return nil
+		}
+		if err := t.start(false); err != nil {
+			return err
+		}
+	}
+	if len(t.rows) > 0 {
+		if err := t.w.writeRows(t.rows); err != nil {
+			return err
+		}
+	}
+	return t.w.close()
+}
+
+// labelColumns returns the sorted names of all the label columns.
+func (t *table) labelColumns() []string {
+	columns := make([]string, 0, len(t.columns))
+	for name := range t.columns {
+		columns = append(columns, name)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// reset removes all the buffered rows and columns.
+func (t *table) reset() {
+	t.columns = map[string]struct{}{}
+	t.rows = nil
+	t.metrics = false
+	t.started = false
+	t.err = nil
+}
+
+// otherLabels returns the labels of a row which are not in the label columns, formatted like a stream selector.
+func otherLabels(columns []string, lbls map[string]string) string {
+	var other map[string]string
+	for name, value := range lbls {
+		if i := sort.SearchStrings(columns, name); i < len(columns) && columns[i] == name {
+			continue
+		}
+		if other == nil {
+			other = map[string]string{}
+		}
+		other[name] = value
+	}
+	if other == nil {
+		return ""
+	}
+	return labels.FromMap(other).String()
+}
+
+func metricLabels(m model.Metric) map[string]string {
+	lbls := make(map[string]string, len(m))
+	for name, value := range m {
+		lbls[labelColumn(string(name))] = string(value)
+	}
+	return lbls
+}
+
+// labelColumn returns the column name of a label, labels clashing with the
+// timestamp, line, value and other_labels columns get the "_extracted" suffix like in LogQL.
+func labelColumn(name string) string {
+	switch name {
+	case timestampColumn, lineColumn, valueColumn, otherLabelsColumn:
+		return name + "_extracted"
+	}
+	return name
+}
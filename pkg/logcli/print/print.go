@@ -48,10 +48,22 @@ func (r *QueryResultPrinter) PrintResult(value loghttp.ResultValue, out output.L
 	case logqlmodel.ValueTypeStreams:
 		length, entry = r.printStream(value.(loghttp.Streams), out, lastEntry)
 	case loghttp.ResultTypeScalar:
+		if mo, ok := out.(output.MetricOutput); ok {
+			mo.PrintScalar(value.(loghttp.Scalar))
+			break
+		}
 		printScalar(value.(loghttp.Scalar))
 	case loghttp.ResultTypeMatrix:
+		if mo, ok := out.(output.MetricOutput); ok {
+			mo.PrintMatrix(value.(loghttp.Matrix))
+			break
+		}
 		printMatrix(value.(loghttp.Matrix))
 	case loghttp.ResultTypeVector:
+		if mo, ok := out.(output.MetricOutput); ok {
+			mo.PrintVector(value.(loghttp.Vector))
+			break
+		}
 		printVector(value.(loghttp.Vector))
 	default:
 		log.Fatalf("Unable to print unsupported type: %v", value.Type())
@@ -144,7 +156,11 @@ func (r *QueryResultPrinter) printStream(streams loghttp.Streams, out output.Log
 				continue
 			}
 		}
-		out.FormatAndPrintln(e.entry.Timestamp, e.labels, maxLabelsLen, e.entry.Line)
+		if eo, ok := out.(output.EntryOutput); ok {
+			eo.FormatAndPrintEntry(e.labels, e.entry)
+		} else {
+			out.FormatAndPrintln(e.entry.Timestamp, e.labels, maxLabelsLen, e.entry.Line)
+		}
 		printed++
 	}
 
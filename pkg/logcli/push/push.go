@@ -0,0 +1,308 @@
+package push
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/logcli/client"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+// Special values for the timestamp format, in addition to Go time layouts.
+const (
+	FormatRFC3339     = "rfc3339"
+	FormatRFC3339Nano = "rfc3339nano"
+	FormatUnix        = "unix"
+	FormatUnixMs      = "unix_ms"
+	FormatUnixUs      = "unix_us"
+	FormatUnixNs      = "unix_ns"
+
+	defaultBatchSize = 1 << 20
+)
+
+// Push contains all necessary fields to read log lines from files or stdin and push them to Loki
+type Push struct {
+	Files              []string
+	Labels             string
+	StructuredMetadata map[string]string
+	TimestampFormat    string
+	TimestampRegex     string
+	BatchSize          int
+	Quiet              bool
+
+	// now is used to timestamp lines when no timestamp format is set.
+	now func() time.Time
+}
+
+// DoPush reads all the configured files, or stdin when no file is given, and pushes their lines to Loki
+func (p *Push) DoPush(c client.Client, stdin io.Reader) {
+	pusher, err := p.newPusher(c)
+	if err != nil {
+		log.Fatalf("Invalid push configuration: %s", err)
+	}
+
+	if len(p.Files) == 0 {
+		if err := pusher.pushReader(stdin, "stdin"); err != nil {
+			log.Fatalf("Error pushing logs from stdin: %+v", err)
+		}
+	}
+
+	for _, name := range p.Files {
+		f, err := os.Open(name)
+		if err != nil {
+			log.Fatalf("Unable to open file %s: %s", name, err)
+		}
+		err = pusher.pushReader(f, name)
+		f.Close()
+		if err != nil {
+			log.Fatalf("Error pushing logs from %s: %+v", name, err)
+		}
+	}
+
+	if !p.Quiet {
+		log.Printf("Pushed %d entries (%d bytes) in %d requests", pusher.entries, pusher.bytes, pusher.requests)
+		if pusher.unparsed > 0 {
+			log.Printf("%d entries had no parseable timestamp and were assigned the timestamp of the previous entry", pusher.unparsed)
+		}
+	}
+}
+
+type pusher struct {
+	client             client.Client
+	labels             string
+	structuredMetadata []logproto.LabelAdapter
+	parseTimestamp     func(line string) (time.Time, bool)
+	now                func() time.Time
+	maxBatchBytes      int
+	quiet              bool
+
+	batch      []logproto.Entry
+	batchBytes int
+
+	entries, bytes, requests, unparsed int
+}
+
+func (p *Push) newPusher(c client.Client) (*pusher, error) {
+	if p.Labels == "" {
+		return nil, errors.New("labels not set")
+	}
+	lbs, err := syntax.ParseLabels(p.Labels)
+	if err != nil {
+		return nil, fmt.Errorf("invalid labels %s: %w", p.Labels, err)
+	}
+	if lbs.IsEmpty() {
+		return nil, errors.New("at least one label is required")
+	}
+
+	parseTimestamp, err := timestampParser(p.TimestampFormat, p.TimestampRegex)
+	if err != nil {
+		return nil, err
+	}
+
+	structuredMetadata := make([]logproto.LabelAdapter, 0, len(p.StructuredMetadata))
+	for _, l := range labels.FromMap(p.StructuredMetadata) {
+		structuredMetadata = append(structuredMetadata, logproto.LabelAdapter{Name: l.Name, Value: l.Value})
+	}
+
+	batchSize := p.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	now := p.now
+	if now == nil {
+		now = time.Now
+	}
+
+	return &pusher{
+		client:             c,
+		labels:             lbs.String(),
+		structuredMetadata: structuredMetadata,
+		parseTimestamp:     parseTimestamp,
+		now:                now,
+		maxBatchBytes:      batchSize,
+		quiet:              p.Quiet,
+	}, nil
+}
+
+func (p *pusher) pushReader(r io.Reader, name string) error {
+	var (
+		reader = bufio.NewReader(r)
+		last   time.Time
+	)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if line != "" {
+			var ts time.Time
+			if p.parseTimestamp == nil {
+				ts = p.now()
+			} else if parsed, ok := p.parseTimestamp(line); ok {
+				ts = parsed
+			} else {
+				p.unparsed++
+				ts = last
+				if ts.IsZero() {
+					ts = p.now()
+				}
+			}
+			last = ts
+
+			if err := p.add(logproto.Entry{Timestamp: ts, Line: line, StructuredMetadata: p.structuredMetadata}); err != nil {
+				return err
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	return p.flush()
+}
+
+func (p *pusher) add(entry logproto.Entry) error {
+	size := len(entry.Line)
+	if len(p.batch) > 0 && p.batchBytes+size > p.maxBatchBytes {
+		if err := p.flush(); err != nil {
+			return err
+		}
+	}
+
+	p.batch = append(p.batch, entry)
+	p.batchBytes += size
+	return nil
+}
+
+func (p *pusher) flush() error {
+	if len(p.batch) == 0 {
+		return nil
+	}
+
+	req := &logproto.PushRequest{
+		Streams: []logproto.Stream{
+			{Labels: p.labels, Entries: p.batch},
+		},
+	}
+	if err := p.client.Push(req, p.quiet); err != nil {
+		return err
+	}
+
+	p.entries += len(p.batch)
+	p.bytes += p.batchBytes
+	p.requests++
+
+	p.batch = nil
+	p.batchBytes = 0
+	return nil
+}
+
+// timestampParser returns a function extracting the timestamp of a line.
+// The timestamp is taken from the first capture group of the regex (or the group named "ts") when set,
+// otherwise from the leading whitespace separated fields of the line, as many as there are in the format.
+// A nil function is returned when no format is set, in which case lines are timestamped when they are read.
+func timestampParser(format, expr string) (func(line string) (time.Time, bool), error) {
+	if format == "" {
+		if expr != "" {
+			return nil, errors.New("timestamp regex requires a timestamp format")
+		}
+		return nil, nil
+	}
+
+	parse := parseTimeFunc(format)
+
+	if expr == "" {
+		numFields := 1
+		switch format {
+		case FormatRFC3339, FormatRFC3339Nano, FormatUnix, FormatUnixMs, FormatUnixUs, FormatUnixNs:
+		default:
+			numFields = len(strings.Fields(format))
+		}
+
+		return func(line string) (time.Time, bool) {
+			fields := strings.SplitN(strings.TrimLeft(line, " \t"), " ", numFields+1)
+			if len(fields) < numFields {
+				return time.Time{}, false
+			}
+			return parse(strings.Join(fields[:numFields], " "))
+		}, nil
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp regex: %w", err)
+	}
+	group := 1
+	if i := re.SubexpIndex("ts"); i > 0 {
+		group = i
+	}
+	if re.NumSubexp() < group {
+		return nil, errors.New("timestamp regex must have a capture group")
+	}
+
+	return func(line string) (time.Time, bool) {
+		m := re.FindStringSubmatch(line)
+		if m == nil {
+			return time.Time{}, false
+		}
+		return parse(m[group])
+	}, nil
+}
+
+func parseTimeFunc(format string) func(string) (time.Time, bool) {
+	parseUnix := func(toTime func(int64) time.Time) func(string) (time.Time, bool) {
+		return func(s string) (time.Time, bool) {
+			if format == FormatUnix && strings.Contains(s, ".") {
+				f, err := strconv.ParseFloat(s, 64)
+				if err != nil {
+					return time.Time{}, false
+				}
+				return time.Unix(0, int64(f*float64(time.Second))), true
+			}
+			i, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return time.Time{}, false
+			}
+			return toTime(i), true
+		}
+	}
+
+	switch format {
+	case FormatUnix:
+		return parseUnix(func(i int64) time.Time { return time.Unix(i, 0) })
+	case FormatUnixMs:
+		return parseUnix(time.UnixMilli)
+	case FormatUnixUs:
+		return parseUnix(time.UnixMicro)
+	case FormatUnixNs:
+		return parseUnix(func(i int64) time.Time { return time.Unix(0, i) })
+	case FormatRFC3339:
+		format = time.RFC3339
+	case FormatRFC3339Nano:
+		format = time.RFC3339Nano
+	}
+
+	return func(s string) (time.Time, bool) {
+		t, err := time.Parse(format, s)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return t, true
+	}
+}
@@ -0,0 +1,102 @@
+package push
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logcli/client"
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+type pushClient struct {
+	client.Client
+	requests []*logproto.PushRequest
+}
+
+func (c *pushClient) Push(req *logproto.PushRequest, _ bool) error {
+	c.requests = append(c.requests, req)
+	return nil
+}
+
+func Test_timestampParser(t *testing.T) {
+	for _, tc := range []struct {
+		name, format, regex, line string
+		want                      time.Time
+		wantOK                    bool
+	}{
+		{"rfc3339", FormatRFC3339, "", "2024-01-02T03:04:05Z level=info msg=hello", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), true},
+		{"unix", FormatUnix, "", "1704164645 hello", time.Unix(1704164645, 0), true},
+		{"unix float", FormatUnix, "", "1704164645.5 hello", time.Unix(1704164645, 500000000), true},
+		{"unix_ms", FormatUnixMs, "", "1704164645123 hello", time.UnixMilli(1704164645123), true},
+		{"layout with spaces", "2006-01-02 15:04:05", "", "2024-01-02 03:04:05 hello", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), true},
+		{"regex", FormatRFC3339, `ts=(\S+)`, "level=info ts=2024-01-02T03:04:05Z msg=hello", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), true},
+		{"named group", FormatUnix, `(\w+)=(?P<ts>\d+)`, "time=1704164645 hello", time.Unix(1704164645, 0), true},
+		{"no match", FormatRFC3339, `ts=(\S+)`, "hello", time.Time{}, false},
+		{"invalid timestamp", FormatRFC3339, "", "hello world", time.Time{}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			parse, err := timestampParser(tc.format, tc.regex)
+			require.NoError(t, err)
+
+			ts, ok := parse(tc.line)
+			require.Equal(t, tc.wantOK, ok)
+			require.True(t, tc.want.Equal(ts), "expected %s got %s", tc.want, ts)
+		})
+	}
+}
+
+func Test_timestampParserErrors(t *testing.T) {
+	_, err := timestampParser("", `(\d+)`)
+	require.Error(t, err)
+
+	_, err = timestampParser(FormatUnix, `\d+`)
+	require.Error(t, err)
+
+	_, err = timestampParser(FormatUnix, `(`)
+	require.Error(t, err)
+}
+
+func TestPush_batches(t *testing.T) {
+	now := time.Unix(100, 0)
+	p := &Push{
+		Labels:             `{job="foo", env="dev"}`,
+		StructuredMetadata: map[string]string{"trace_id": "abc"},
+		TimestampFormat:    FormatUnix,
+		BatchSize:          20,
+		now:                func() time.Time { return now },
+	}
+	c := &pushClient{}
+
+	pusher, err := p.newPusher(c)
+	require.NoError(t, err)
+	require.NoError(t, pusher.pushReader(strings.NewReader("1 aaaa\nno timestamp\n\n3 cc\r\n"), "test"))
+
+	require.Len(t, c.requests, 2)
+	require.Equal(t, 3, pusher.entries)
+	require.Equal(t, 1, pusher.unparsed)
+
+	var entries []logproto.Entry
+	for _, req := range c.requests {
+		require.Len(t, req.Streams, 1)
+		require.Equal(t, `{env="dev", job="foo"}`, req.Streams[0].Labels)
+		entries = append(entries, req.Streams[0].Entries...)
+	}
+
+	sm := []logproto.LabelAdapter{{Name: "trace_id", Value: "abc"}}
+	require.Equal(t, []logproto.Entry{
+		{Timestamp: time.Unix(1, 0), Line: "1 aaaa", StructuredMetadata: sm},
+		{Timestamp: time.Unix(1, 0), Line: "no timestamp", StructuredMetadata: sm},
+		{Timestamp: time.Unix(3, 0), Line: "3 cc", StructuredMetadata: sm},
+	}, entries)
+}
+
+func TestPush_invalidLabels(t *testing.T) {
+	_, err := (&Push{Labels: `{job=`}).newPusher(&pushClient{})
+	require.Error(t, err)
+
+	_, err = (&Push{}).newPusher(&pushClient{})
+	require.Error(t, err)
+}
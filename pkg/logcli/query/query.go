@@ -92,6 +92,7 @@ func (q *Query) DoQuery(c client.Client, out output.LogOutput, statistics bool)
 		if err := q.DoLocalQuery(out, statistics, orgID, q.FetchSchemaFromStorage); err != nil {
 			log.Fatalf("Query failed: %+v", err)
 		}
+		q.flushOutput(out)
 		return
 	}
 
@@ -201,6 +202,11 @@ func (q *Query) DoQuery(c client.Client, out output.LogOutput, statistics bool)
 		}
 	}
 
+	// Parallel jobs writing to stdout share the same output, it's flushed once all of them are done.
+	if partFile != nil || q.ParallelMaxWorkers <= 1 {
+		q.flushOutput(out)
+	}
+
 	if partFile != nil {
 		if err := partFile.Finalize(); err != nil {
 			log.Fatalln(err)
@@ -208,6 +214,13 @@ func (q *Query) DoQuery(c client.Client, out output.LogOutput, statistics bool)
 	}
 }
 
+// flushOutput writes the results buffered by outputs such as csv and parquet.
+func (q *Query) flushOutput(out output.LogOutput) {
+	if err := output.Flush(out); err != nil {
+		log.Fatalf("Writing output failed: %+v", err)
+	}
+}
+
 func (q *Query) outputFilename() string {
 	return fmt.Sprintf(
 		"%s_%s_%s.part",
@@ -370,6 +383,11 @@ func (q *Query) DoQueryParallel(c client.Client, out output.LogOutput, statistic
 		log.Fatalf("Parallel duration has to be a positive value\n")
 	}
 
+	// Buffered outputs write a complete file, with its own header or footer, per part.
+	if _, ok := out.(output.Flusher); ok && q.MergeParts {
+		log.Fatalf("--merge-parts is not supported with this output mode, every part file is a complete file on its own\n")
+	}
+
 	jobs := q.parallelJobs()
 
 	wg := q.startWorkers(jobs, c, out, statistics)
@@ -379,6 +397,10 @@ func (q *Query) DoQueryParallel(c client.Client, out output.LogOutput, statistic
 	}
 
 	wg.Wait()
+
+	if q.PartPathPrefix == "" {
+		q.flushOutput(out)
+	}
 }
 
 func minTime(t1, t2 time.Time) time.Time {
@@ -17,6 +17,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	logcli_client "github.com/grafana/loki/v3/pkg/logcli/client"
 	"github.com/grafana/loki/v3/pkg/logcli/output"
 	"github.com/grafana/loki/v3/pkg/logcli/volume"
 	"github.com/grafana/loki/v3/pkg/loghttp"
@@ -485,6 +486,26 @@ func (t *testQueryClient) GetVolumeRange(_ *volume.Query) (*loghttp.QueryRespons
 	panic("not implemented")
 }
 
+func (t *testQueryClient) CreateDeleteRequest(_ logcli_client.DeleteRequestParams, _ bool) error {
+	panic("not implemented")
+}
+
+func (t *testQueryClient) ListDeleteRequests(_ bool) ([]logcli_client.DeleteRequest, error) {
+	panic("not implemented")
+}
+
+func (t *testQueryClient) CancelDeleteRequest(_ string, _ bool, _ bool) error {
+	panic("not implemented")
+}
+
+func (t *testQueryClient) Push(_ *logproto.PushRequest, _ bool) error {
+	panic("not implemented")
+}
+
+func (t *testQueryClient) GetDetectedFields(_ string, _, _ int, _, _ time.Time, _ bool) (*logproto.DetectedFieldsResponse, error) {
+	panic("not implemented")
+}
+
 var legacySchemaConfigContents = `schema_config:
   configs:
   - from: 2020-05-15
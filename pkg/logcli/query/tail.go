@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -22,19 +23,14 @@ import (
 
 // TailQuery connects to the Loki websocket endpoint and tails logs
 func (q *Query) TailQuery(delayFor time.Duration, c client.Client, out output.LogOutput) {
-	conn, err := c.LiveTailQueryConn(q.QueryString, delayFor, q.Limit, q.Start, q.Quiet)
-	if err != nil {
-		log.Fatalf("Tailing logs failed: %+v", err)
-	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
 	go func() {
 		stopChan := make(chan os.Signal, 1)
 		signal.Notify(stopChan, os.Interrupt, syscall.SIGTERM)
 		<-stopChan
-		if err := conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")); err != nil {
-			log.Println("Error closing websocket:", err)
-		}
-		os.Exit(0)
+		cancel()
 	}()
 
 	if len(q.IgnoreLabelsKey) > 0 && !q.Quiet {
@@ -45,12 +41,43 @@ func (q *Query) TailQuery(delayFor time.Duration, c client.Client, out output.Lo
 		log.Println("Print only labels key:", color.RedString(strings.Join(q.ShowLabelsKey, ",")))
 	}
 
+	err := q.Tail(ctx, delayFor, c, func(labels loghttp.LabelSet, entry loghttp.Entry) {
+		out.FormatAndPrintln(entry.Timestamp, labels, 0, entry.Line)
+	})
+	if err != nil {
+		log.Fatalf("Tailing logs failed: %+v", err)
+	}
+}
+
+// Tail connects to the Loki websocket endpoint and calls fn for every received entry until the context is done
+// or the connection can't be re-established. Only an error to establish the initial connection is returned.
+func (q *Query) Tail(ctx context.Context, delayFor time.Duration, c client.Client, fn func(labels loghttp.LabelSet, entry loghttp.Entry)) error {
+	conn, err := c.LiveTailQueryConn(q.QueryString, delayFor, q.Limit, q.Start, q.Quiet)
+	if err != nil {
+		return err
+	}
+
+	var connMtx sync.Mutex
+	go func() {
+		<-ctx.Done()
+		connMtx.Lock()
+		defer connMtx.Unlock()
+		if err := conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")); err != nil {
+			log.Println("Error closing websocket:", err)
+		}
+		conn.Close()
+	}()
+
 	tailResponse := new(loghttp.TailResponse)
 	lastReceivedTimestamp := q.Start
 
 	for {
 		err := unmarshal.ReadTailResponseJSON(tailResponse, conn)
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
 			// Check if the websocket connection closed unexpectedly. If so, retry.
 			// The connection might close unexpectedly if the querier handling the tail request
 			// in Loki stops running. The following error would be printed:
@@ -64,15 +91,18 @@ func (q *Query) TailQuery(delayFor time.Duration, c client.Client, out output.Lo
 				}
 
 				// Try to re-establish the connection up to 5 times.
-				backoff := backoff.New(context.Background(), backoff.Config{
+				backoff := backoff.New(ctx, backoff.Config{
 					MinBackoff: 1 * time.Second,
 					MaxBackoff: 10 * time.Second,
 					MaxRetries: 5,
 				})
 
 				for backoff.Ongoing() {
-					conn, err = c.LiveTailQueryConn(q.QueryString, delayFor, q.Limit, lastReceivedTimestamp, q.Quiet)
+					newConn, err := c.LiveTailQueryConn(q.QueryString, delayFor, q.Limit, lastReceivedTimestamp, q.Quiet)
 					if err == nil {
+						connMtx.Lock()
+						conn = newConn
+						connMtx.Unlock()
 						break
 					}
 
@@ -82,14 +112,14 @@ func (q *Query) TailQuery(delayFor time.Duration, c client.Client, out output.Lo
 
 				if err = backoff.Err(); err != nil {
 					log.Println("Error recreating tailing connection:", err)
-					return
+					return nil
 				}
 
 				continue
 			}
 
 			log.Println("Error reading stream:", err)
-			return
+			return nil
 		}
 
 		labels := loghttp.LabelSet{}
@@ -115,7 +145,7 @@ func (q *Query) TailQuery(delayFor time.Duration, c client.Client, out output.Lo
 			}
 
 			for _, entry := range stream.Entries {
-				out.FormatAndPrintln(entry.Timestamp, labels, 0, entry.Line)
+				fn(labels, entry)
 				lastReceivedTimestamp = entry.Timestamp
 			}
 
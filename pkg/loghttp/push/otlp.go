@@ -30,6 +30,7 @@ const (
 	attrServiceName     = "service.name"
 
 	OTLPSeverityNumber = "severity_number"
+	OTLPSeverityText   = "severity_text"
 )
 
 func newPushStats() *Stats {
@@ -296,7 +297,7 @@ func otlpLogToPushEntry(log plog.LogRecord, otlpConfig OTLPConfig) push.Entry {
 	}
 	if severityText := log.SeverityText(); severityText != "" {
 		structuredMetadata = append(structuredMetadata, push.LabelAdapter{
-			Name:  "severity_text",
+			Name:  OTLPSeverityText,
 			Value: severityText,
 		})
 	}
@@ -338,6 +338,21 @@ func Test_ParseVolumeInstantQuery(t *testing.T) {
 			require.Equal(t, "labels", actual.AggregateBy)
 		})
 
+		for _, aggregateBy := range []string{"series_count", "label_values", "churn"} {
+			aggregateBy := aggregateBy
+			t.Run(aggregateBy, func(t *testing.T) {
+				req := &http.Request{URL: mustParseURL(url + `&aggregateBy=` + aggregateBy)}
+
+				err := req.ParseForm()
+				require.NoError(t, err)
+
+				actual, err := ParseVolumeInstantQuery(req)
+				require.NoError(t, err)
+
+				require.Equal(t, aggregateBy, actual.AggregateBy)
+			})
+		}
+
 		t.Run("invalid", func(t *testing.T) {
 			req := &http.Request{URL: mustParseURL(url + `&aggregateBy=invalid`)}
 
@@ -239,64 +239,114 @@ func (m *FPBounds) GetMax() github_com_prometheus_common_model.Fingerprint {
 	return 0
 }
 
+type StructuredMetadataKeysRequest struct {
+	From     github_com_prometheus_common_model.Time `protobuf:"varint,1,opt,name=from,proto3,customtype=github.com/prometheus/common/model.Time" json:"from"`
+	Through  github_com_prometheus_common_model.Time `protobuf:"varint,2,opt,name=through,proto3,customtype=github.com/prometheus/common/model.Time" json:"through"`
+	Matchers string                                  `protobuf:"bytes,3,opt,name=matchers,proto3" json:"matchers"`
+}
+
+func (m *StructuredMetadataKeysRequest) Reset()      { *m = StructuredMetadataKeysRequest{} }
+func (*StructuredMetadataKeysRequest) ProtoMessage() {}
+func (*StructuredMetadataKeysRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d27585148d0a52c8, []int{4}
+}
+func (m *StructuredMetadataKeysRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StructuredMetadataKeysRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StructuredMetadataKeysRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *StructuredMetadataKeysRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StructuredMetadataKeysRequest.Merge(m, src)
+}
+func (m *StructuredMetadataKeysRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *StructuredMetadataKeysRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StructuredMetadataKeysRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StructuredMetadataKeysRequest proto.InternalMessageInfo
+
+func (m *StructuredMetadataKeysRequest) GetMatchers() string {
+	if m != nil {
+		return m.Matchers
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*ShardsRequest)(nil), "indexgatewaypb.ShardsRequest")
 	proto.RegisterType((*ShardsResponse)(nil), "indexgatewaypb.ShardsResponse")
 	proto.RegisterType((*Shard)(nil), "indexgatewaypb.Shard")
 	proto.RegisterType((*FPBounds)(nil), "indexgatewaypb.FPBounds")
+	proto.RegisterType((*StructuredMetadataKeysRequest)(nil), "indexgatewaypb.StructuredMetadataKeysRequest")
 }
 
 func init() { proto.RegisterFile("pkg/logproto/indexgateway.proto", fileDescriptor_d27585148d0a52c8) }
 
 var fileDescriptor_d27585148d0a52c8 = []byte{
-	// 737 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0xbf, 0x4f, 0xdb, 0x4c,
-	0x18, 0xf6, 0x91, 0x84, 0x0f, 0x8e, 0x0f, 0x86, 0xfb, 0x7e, 0x60, 0x05, 0xb0, 0xa3, 0x2c, 0x5f,
-	0xbe, 0xc5, 0xae, 0x60, 0xa8, 0x5a, 0x09, 0x89, 0xba, 0x52, 0x22, 0x44, 0x5a, 0x51, 0x83, 0x18,
-	0x2a, 0xb5, 0xd4, 0x09, 0x87, 0x63, 0x61, 0xfb, 0xc2, 0xdd, 0xb9, 0x0d, 0x5b, 0xc7, 0xaa, 0x53,
-	0xd5, 0xff, 0xa0, 0x52, 0x87, 0xfe, 0x29, 0x8c, 0x8c, 0xa8, 0x83, 0x55, 0xc2, 0x52, 0x65, 0x62,
-	0xee, 0x54, 0xf9, 0xce, 0x4e, 0x4c, 0x08, 0x12, 0xed, 0xe2, 0x3b, 0x3f, 0xef, 0xf3, 0x3e, 0xef,
-	0x4f, 0x27, 0x50, 0xef, 0x1e, 0xb9, 0xa6, 0x4f, 0xdc, 0x2e, 0x25, 0x9c, 0x98, 0x5e, 0x78, 0x80,
-	0x7b, 0xae, 0xc3, 0xf1, 0x1b, 0xe7, 0xc4, 0x10, 0x10, 0x5a, 0xc8, 0x63, 0xdd, 0x56, 0xf9, 0x6f,
-	0x97, 0xb8, 0x44, 0xb2, 0x93, 0x9b, 0x64, 0x95, 0x97, 0xae, 0xc9, 0x64, 0x97, 0xd4, 0x58, 0x49,
-	0x8d, 0xc7, 0x7e, 0x40, 0x0e, 0xb0, 0x6f, 0x32, 0xee, 0x70, 0x26, 0x9f, 0x92, 0x51, 0xfd, 0x34,
-	0x05, 0xe7, 0x77, 0x3a, 0x0e, 0x3d, 0x60, 0x36, 0x3e, 0x8e, 0x30, 0xe3, 0x68, 0x0b, 0x16, 0x0f,
-	0x29, 0x09, 0x54, 0x50, 0x01, 0xb5, 0x82, 0x75, 0xff, 0x34, 0xd6, 0x95, 0xaf, 0xb1, 0xfe, 0x9f,
-	0xeb, 0xf1, 0x4e, 0xd4, 0x32, 0xda, 0x24, 0x30, 0xbb, 0x94, 0x04, 0x98, 0x77, 0x70, 0xc4, 0xcc,
-	0x36, 0x09, 0x02, 0x12, 0x9a, 0x42, 0xdd, 0xd8, 0xf5, 0x02, 0x3c, 0x88, 0x75, 0xe1, 0x6e, 0x8b,
-	0x27, 0xda, 0x85, 0x7f, 0xf0, 0x0e, 0x25, 0x91, 0xdb, 0x51, 0xa7, 0x84, 0xde, 0xc3, 0x5f, 0xd7,
-	0xcb, 0x14, 0xec, 0xec, 0x82, 0x74, 0x58, 0x3a, 0x8e, 0x30, 0x3d, 0x51, 0x0b, 0x15, 0x50, 0x9b,
-	0xb5, 0x66, 0x07, 0xb1, 0x2e, 0x01, 0x5b, 0x1e, 0xa8, 0x09, 0xff, 0xe5, 0x0e, 0x75, 0x31, 0xdf,
-	0x6f, 0x9d, 0x70, 0xcc, 0xf6, 0xbb, 0x98, 0xee, 0xb3, 0xa4, 0x4a, 0xb5, 0x58, 0x01, 0xb5, 0xa2,
-	0xb5, 0x38, 0x88, 0xf5, 0xbf, 0x24, 0xc3, 0x4a, 0x08, 0xdb, 0x98, 0x8a, 0x26, 0xd8, 0x93, 0xc0,
-	0xea, 0x47, 0x00, 0x17, 0xb2, 0x1e, 0xb1, 0x2e, 0x09, 0x19, 0x46, 0xeb, 0x70, 0x5a, 0xe8, 0x31,
-	0x15, 0x54, 0x0a, 0xb5, 0xb9, 0xd5, 0x7f, 0x8c, 0xeb, 0xc3, 0x32, 0x04, 0xdf, 0x5a, 0x48, 0xaa,
-	0x1d, 0xc4, 0x7a, 0x4a, 0xb6, 0xd3, 0x13, 0x3d, 0x82, 0x30, 0x19, 0x82, 0xc7, 0xb8, 0xd7, 0x66,
-	0xa2, 0x33, 0x73, 0xab, 0xf3, 0x86, 0x9c, 0x8b, 0x8d, 0x59, 0xe4, 0x73, 0x0b, 0xa5, 0xae, 0x39,
-	0xa2, 0x9d, 0xbb, 0x57, 0xdf, 0x01, 0x58, 0x12, 0x41, 0xd0, 0x06, 0x9c, 0x6e, 0x91, 0x28, 0x14,
-	0xb9, 0x24, 0x42, 0xea, 0x78, 0x2e, 0xf5, 0x6d, 0x4b, 0xd8, 0x47, 0xe9, 0x48, 0xbe, 0x9d, 0x9e,
-	0x68, 0x1d, 0x96, 0x44, 0xec, 0x34, 0x93, 0x65, 0x63, 0xb8, 0x46, 0x9b, 0x89, 0xd2, 0x4e, 0x62,
-	0xcb, 0x4a, 0x97, 0xdd, 0x16, 0x74, 0x5b, 0x1e, 0xd5, 0xcf, 0x00, 0xce, 0x64, 0x31, 0xd0, 0x16,
-	0x2c, 0x04, 0x5e, 0x28, 0x52, 0x29, 0x5a, 0x0f, 0x06, 0xb1, 0x9e, 0xbc, 0xfe, 0x88, 0x75, 0xe3,
-	0x0e, 0x03, 0xaf, 0x7b, 0xa1, 0x8b, 0x69, 0x97, 0x7a, 0x21, 0xb7, 0x13, 0x37, 0x21, 0xe6, 0xf4,
-	0x44, 0x5a, 0x99, 0x98, 0xd3, 0xfb, 0x2d, 0x31, 0xa7, 0xb7, 0xfa, 0xbe, 0x04, 0xff, 0x14, 0xf5,
-	0x34, 0x64, 0x67, 0xd0, 0x26, 0x84, 0xcf, 0x92, 0x75, 0x11, 0x20, 0x5a, 0x1a, 0x55, 0x3d, 0x42,
-	0xd3, 0x8f, 0xa2, 0xbc, 0x3c, 0xd9, 0x28, 0x5b, 0x72, 0x0f, 0xa0, 0x26, 0x9c, 0x6b, 0x60, 0xfe,
-	0xb8, 0x13, 0x85, 0x47, 0x36, 0x3e, 0x44, 0x39, 0x7a, 0x0e, 0xce, 0xc4, 0x56, 0x6e, 0xb1, 0x4a,
-	0xb5, 0xaa, 0x82, 0xea, 0x70, 0xb6, 0x81, 0xf9, 0x0e, 0xa6, 0x1e, 0x66, 0xa8, 0x7c, 0x8d, 0x2d,
-	0xc1, 0x4c, 0x69, 0x69, 0xa2, 0x6d, 0xa8, 0xf3, 0x12, 0x2e, 0x36, 0x9d, 0x16, 0xf6, 0x9f, 0x3a,
-	0x01, 0x66, 0x75, 0x42, 0x9f, 0x60, 0x4e, 0xbd, 0x76, 0xf2, 0x86, 0x6a, 0x23, 0xcf, 0x5b, 0x28,
-	0x59, 0x8c, 0xc5, 0x31, 0x66, 0x4e, 0xff, 0x15, 0x54, 0x05, 0xb4, 0xe7, 0xf8, 0xd1, 0x78, 0x80,
-	0xff, 0xc7, 0xdc, 0x26, 0x70, 0xee, 0x10, 0xa1, 0x01, 0x67, 0x92, 0xc2, 0x92, 0x35, 0xcb, 0x0f,
-	0x28, 0xbf, 0x96, 0x37, 0x06, 0x74, 0x73, 0x67, 0xab, 0x0a, 0xda, 0x10, 0x2d, 0xdd, 0x23, 0x7e,
-	0x14, 0x60, 0x94, 0x0b, 0x28, 0x91, 0x4c, 0x45, 0xbd, 0x69, 0x18, 0x2a, 0x34, 0xe5, 0x50, 0xe4,
-	0x07, 0xbc, 0x32, 0xf1, 0x7b, 0x1f, 0x66, 0xa3, 0xdd, 0x66, 0xce, 0x16, 0xc6, 0x7a, 0x71, 0x76,
-	0xa1, 0x29, 0xe7, 0x17, 0x9a, 0x72, 0x75, 0xa1, 0x81, 0xb7, 0x7d, 0x0d, 0x7c, 0xe9, 0x6b, 0xe0,
-	0xb4, 0xaf, 0x81, 0xb3, 0xbe, 0x06, 0xbe, 0xf5, 0x35, 0xf0, 0xbd, 0xaf, 0x29, 0x57, 0x7d, 0x0d,
-	0x7c, 0xb8, 0xd4, 0x94, 0xb3, 0x4b, 0x4d, 0x39, 0xbf, 0xd4, 0x94, 0xe7, 0xf9, 0x5f, 0x4d, 0x97,
-	0x3a, 0x87, 0x4e, 0xe8, 0x98, 0x3e, 0x39, 0xf2, 0xcc, 0xd7, 0x6b, 0x66, 0xfe, 0x7f, 0xa0, 0x35,
-	0x2d, 0x8e, 0xb5, 0x9f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x1e, 0x17, 0x36, 0xcf, 0x65, 0x06, 0x00,
-	0x00,
+	// 802 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x55, 0xcf, 0x6f, 0xe3, 0x44,
+	0x14, 0xf6, 0x6c, 0x92, 0xd2, 0x4e, 0x77, 0x7b, 0x18, 0x7e, 0xd4, 0xa4, 0x5b, 0x3b, 0xca, 0x85,
+	0x70, 0xc0, 0x46, 0xdd, 0x03, 0x02, 0x69, 0xa5, 0xc5, 0x48, 0x89, 0x56, 0x4d, 0x51, 0x71, 0xaa,
+	0x1e, 0x90, 0xa0, 0x4c, 0x92, 0xa9, 0x63, 0xd5, 0xf6, 0xa4, 0x33, 0x63, 0x48, 0x6e, 0x1c, 0x39,
+	0x22, 0xfe, 0x03, 0x24, 0x0e, 0xfc, 0x29, 0x3d, 0xf6, 0x58, 0x71, 0xb0, 0x68, 0x7a, 0x41, 0x11,
+	0x87, 0x72, 0xe5, 0x84, 0x3c, 0x63, 0x27, 0x6e, 0x92, 0x56, 0x85, 0x1b, 0x17, 0xcf, 0xf8, 0x7b,
+	0xdf, 0x7c, 0xef, 0xcd, 0xfb, 0x61, 0x43, 0x73, 0x78, 0xe6, 0xd9, 0x01, 0xf5, 0x86, 0x8c, 0x0a,
+	0x6a, 0xfb, 0x51, 0x9f, 0x8c, 0x3c, 0x2c, 0xc8, 0x77, 0x78, 0x6c, 0x49, 0x08, 0x6d, 0x15, 0xb1,
+	0x61, 0xb7, 0xfa, 0x96, 0x47, 0x3d, 0xaa, 0xd8, 0xe9, 0x4e, 0xb1, 0xaa, 0x3b, 0x77, 0x64, 0xf2,
+	0x4d, 0x66, 0xac, 0x65, 0xc6, 0xf3, 0x20, 0xa4, 0x7d, 0x12, 0xd8, 0x5c, 0x60, 0xc1, 0xd5, 0x53,
+	0x31, 0xea, 0x3f, 0x3f, 0x81, 0xcf, 0x3a, 0x03, 0xcc, 0xfa, 0xdc, 0x25, 0xe7, 0x31, 0xe1, 0x02,
+	0xed, 0xc3, 0xf2, 0x29, 0xa3, 0xa1, 0x0e, 0x6a, 0xa0, 0x51, 0x72, 0x3e, 0xba, 0x48, 0x4c, 0xed,
+	0xb7, 0xc4, 0x7c, 0xcf, 0xf3, 0xc5, 0x20, 0xee, 0x5a, 0x3d, 0x1a, 0xda, 0x43, 0x46, 0x43, 0x22,
+	0x06, 0x24, 0xe6, 0x76, 0x8f, 0x86, 0x21, 0x8d, 0x6c, 0xa9, 0x6e, 0x1d, 0xf9, 0x21, 0x99, 0x26,
+	0xa6, 0x3c, 0xee, 0xca, 0x27, 0x3a, 0x82, 0x6f, 0x88, 0x01, 0xa3, 0xb1, 0x37, 0xd0, 0x9f, 0x48,
+	0xbd, 0x4f, 0xfe, 0xbd, 0x5e, 0xae, 0xe0, 0xe6, 0x1b, 0x64, 0xc2, 0xca, 0x79, 0x4c, 0xd8, 0x58,
+	0x2f, 0xd5, 0x40, 0x63, 0xc3, 0xd9, 0x98, 0x26, 0xa6, 0x02, 0x5c, 0xb5, 0xa0, 0x36, 0x7c, 0x47,
+	0x60, 0xe6, 0x11, 0x71, 0xd2, 0x1d, 0x0b, 0xc2, 0x4f, 0x86, 0x84, 0x9d, 0xf0, 0xf4, 0x96, 0x7a,
+	0xb9, 0x06, 0x1a, 0x65, 0x67, 0x7b, 0x9a, 0x98, 0x6f, 0x2a, 0x86, 0x93, 0x12, 0x0e, 0x09, 0x93,
+	0x49, 0x70, 0x57, 0x81, 0xf5, 0x9f, 0x00, 0xdc, 0xca, 0x73, 0xc4, 0x87, 0x34, 0xe2, 0x04, 0xbd,
+	0x84, 0x6b, 0x52, 0x8f, 0xeb, 0xa0, 0x56, 0x6a, 0x6c, 0xee, 0xbd, 0x6d, 0xdd, 0x2d, 0x96, 0x25,
+	0xf9, 0xce, 0x56, 0x7a, 0xdb, 0x69, 0x62, 0x66, 0x64, 0x37, 0x5b, 0xd1, 0xa7, 0x10, 0xa6, 0x45,
+	0xf0, 0xb9, 0xf0, 0x7b, 0x5c, 0x66, 0x66, 0x73, 0xef, 0x99, 0xa5, 0xea, 0xe2, 0x12, 0x1e, 0x07,
+	0xc2, 0x41, 0xd9, 0xd1, 0x02, 0xd1, 0x2d, 0xec, 0xeb, 0x3f, 0x00, 0x58, 0x91, 0x4e, 0xd0, 0x2b,
+	0xb8, 0xd6, 0xa5, 0x71, 0x24, 0x63, 0x49, 0x85, 0xf4, 0xc5, 0x58, 0x9a, 0x87, 0x8e, 0xb4, 0xcf,
+	0xc3, 0x51, 0x7c, 0x37, 0x5b, 0xd1, 0x4b, 0x58, 0x91, 0xbe, 0xb3, 0x48, 0x9e, 0x5b, 0xb3, 0x36,
+	0x7a, 0x9d, 0x2a, 0x75, 0x52, 0x5b, 0x7e, 0x75, 0x95, 0x6d, 0x49, 0x77, 0xd5, 0x52, 0xff, 0x05,
+	0xc0, 0xf5, 0xdc, 0x07, 0xda, 0x87, 0xa5, 0xd0, 0x8f, 0x64, 0x28, 0x65, 0xe7, 0xe3, 0x69, 0x62,
+	0xa6, 0xaf, 0x7f, 0x27, 0xa6, 0xf5, 0x88, 0x82, 0x37, 0xfd, 0xc8, 0x23, 0x6c, 0xc8, 0xfc, 0x48,
+	0xb8, 0xe9, 0x31, 0x29, 0x86, 0x47, 0x32, 0xac, 0x5c, 0x0c, 0x8f, 0xfe, 0x93, 0x18, 0x1e, 0xd5,
+	0xff, 0x04, 0x70, 0xb7, 0x23, 0x58, 0xdc, 0x13, 0x31, 0x23, 0xfd, 0x03, 0x22, 0x70, 0x1f, 0x0b,
+	0xbc, 0x4f, 0xc6, 0xff, 0xa7, 0xd6, 0x6f, 0xc0, 0xf5, 0x10, 0x8b, 0xde, 0x80, 0x30, 0x9e, 0x75,
+	0xff, 0xd3, 0x69, 0x62, 0xce, 0x30, 0x77, 0xb6, 0xdb, 0xfb, 0xab, 0x02, 0x9f, 0xca, 0xf2, 0xb5,
+	0x54, 0x23, 0xa0, 0xd7, 0x10, 0x7e, 0x91, 0x4e, 0x87, 0x04, 0xd1, 0xce, 0xbc, 0xc8, 0x73, 0x34,
+	0x4b, 0x44, 0xf5, 0xf9, 0x6a, 0xa3, 0xea, 0x80, 0x0f, 0x01, 0x6a, 0xc3, 0xcd, 0x16, 0x11, 0x9f,
+	0x0d, 0xe2, 0xe8, 0xcc, 0x25, 0xa7, 0xa8, 0x40, 0x2f, 0xc0, 0xb9, 0xd8, 0xee, 0x3d, 0x56, 0xa5,
+	0x56, 0xd7, 0x50, 0x13, 0x6e, 0xb4, 0x88, 0xe8, 0x10, 0xe6, 0x13, 0x8e, 0xaa, 0x77, 0xd8, 0x0a,
+	0xcc, 0x95, 0x76, 0x56, 0xda, 0x66, 0x3a, 0x5f, 0xc3, 0xed, 0x36, 0xee, 0x92, 0xe0, 0x73, 0x1c,
+	0x12, 0xde, 0xa4, 0xec, 0x80, 0x08, 0xe6, 0xf7, 0xd2, 0x37, 0xd4, 0x98, 0x9f, 0xbc, 0x87, 0x92,
+	0xfb, 0xd8, 0x5e, 0x60, 0x16, 0xf4, 0xbf, 0x81, 0xba, 0x84, 0x8e, 0x71, 0x10, 0x2f, 0x3a, 0x78,
+	0x7f, 0xe1, 0xd8, 0x0a, 0xce, 0x23, 0x3c, 0xb4, 0xe0, 0x7a, 0x7a, 0xb1, 0x74, 0xaa, 0x8a, 0x05,
+	0x2a, 0x4e, 0xe1, 0x52, 0x81, 0x96, 0x47, 0xb4, 0xae, 0xa1, 0x57, 0x32, 0xa5, 0xc7, 0x34, 0x88,
+	0x43, 0x82, 0x0a, 0x0e, 0x15, 0x92, 0xab, 0xe8, 0xcb, 0x86, 0x99, 0x42, 0x5b, 0x15, 0x45, 0x7d,
+	0xaf, 0x76, 0x57, 0x7e, 0xde, 0x66, 0xd1, 0x18, 0xf7, 0x99, 0x67, 0x0d, 0x43, 0xe0, 0xbb, 0xf2,
+	0x62, 0xab, 0xa6, 0x0f, 0x7d, 0xb0, 0x74, 0xfc, 0xa1, 0x29, 0x7d, 0x20, 0x7f, 0xce, 0x57, 0x97,
+	0xd7, 0x86, 0x76, 0x75, 0x6d, 0x68, 0xb7, 0xd7, 0x06, 0xf8, 0x7e, 0x62, 0x80, 0x5f, 0x27, 0x06,
+	0xb8, 0x98, 0x18, 0xe0, 0x72, 0x62, 0x80, 0xdf, 0x27, 0x06, 0xf8, 0x63, 0x62, 0x68, 0xb7, 0x13,
+	0x03, 0xfc, 0x78, 0x63, 0x68, 0x97, 0x37, 0x86, 0x76, 0x75, 0x63, 0x68, 0x5f, 0x16, 0x07, 0xd2,
+	0x63, 0xf8, 0x14, 0x47, 0xd8, 0x0e, 0xe8, 0x99, 0x6f, 0x7f, 0xfb, 0xc2, 0x2e, 0xfe, 0x5d, 0xbb,
+	0x6b, 0x72, 0x79, 0xf1, 0x4f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x23, 0x70, 0xfd, 0x59, 0xbb, 0x07,
+	0x00, 0x00,
 }
 
 func (this *ShardsRequest) Equal(that interface{}) bool {
@@ -418,6 +468,36 @@ func (this *FPBounds) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *StructuredMetadataKeysRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*StructuredMetadataKeysRequest)
+	if !ok {
+		that2, ok := that.(StructuredMetadataKeysRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.From.Equal(that1.From) {
+		return false
+	}
+	if !this.Through.Equal(that1.Through) {
+		return false
+	}
+	if this.Matchers != that1.Matchers {
+		return false
+	}
+	return true
+}
 func (this *ShardsRequest) GoString() string {
 	if this == nil {
 		return "nil"
@@ -472,6 +552,18 @@ func (this *FPBounds) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *StructuredMetadataKeysRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&logproto.StructuredMetadataKeysRequest{")
+	s = append(s, "From: "+fmt.Sprintf("%#v", this.From)+",\n")
+	s = append(s, "Through: "+fmt.Sprintf("%#v", this.Through)+",\n")
+	s = append(s, "Matchers: "+fmt.Sprintf("%#v", this.Matchers)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func valueToGoStringIndexgateway(v interface{}, typ string) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -510,6 +602,9 @@ type IndexGatewayClient interface {
 	// GetShards is an optimized implemented shard-planning implementation
 	// on the index gateway and not on the ingester.
 	GetShards(ctx context.Context, in *ShardsRequest, opts ...grpc.CallOption) (IndexGateway_GetShardsClient, error)
+	// GetStructuredMetadataKeys returns the structured metadata keys recorded in the index
+	// for the series matching the matchers.
+	GetStructuredMetadataKeys(ctx context.Context, in *StructuredMetadataKeysRequest, opts ...grpc.CallOption) (*LabelResponse, error)
 }
 
 type indexGatewayClient struct {
@@ -638,6 +733,15 @@ func (x *indexGatewayGetShardsClient) Recv() (*ShardsResponse, error) {
 	return m, nil
 }
 
+func (c *indexGatewayClient) GetStructuredMetadataKeys(ctx context.Context, in *StructuredMetadataKeysRequest, opts ...grpc.CallOption) (*LabelResponse, error) {
+	out := new(LabelResponse)
+	err := c.cc.Invoke(ctx, "/indexgatewaypb.IndexGateway/GetStructuredMetadataKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // IndexGatewayServer is the server API for IndexGateway service.
 type IndexGatewayServer interface {
 	/// QueryIndex reads the indexes required for given query & sends back the batch of rows
@@ -657,6 +761,9 @@ type IndexGatewayServer interface {
 	// GetShards is an optimized implemented shard-planning implementation
 	// on the index gateway and not on the ingester.
 	GetShards(*ShardsRequest, IndexGateway_GetShardsServer) error
+	// GetStructuredMetadataKeys returns the structured metadata keys recorded in the index
+	// for the series matching the matchers.
+	GetStructuredMetadataKeys(context.Context, *StructuredMetadataKeysRequest) (*LabelResponse, error)
 }
 
 // UnimplementedIndexGatewayServer can be embedded to have forward compatible implementations.
@@ -687,6 +794,9 @@ func (*UnimplementedIndexGatewayServer) GetVolume(ctx context.Context, req *Volu
 func (*UnimplementedIndexGatewayServer) GetShards(req *ShardsRequest, srv IndexGateway_GetShardsServer) error {
 	return status.Errorf(codes.Unimplemented, "method GetShards not implemented")
 }
+func (*UnimplementedIndexGatewayServer) GetStructuredMetadataKeys(ctx context.Context, req *StructuredMetadataKeysRequest) (*LabelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStructuredMetadataKeys not implemented")
+}
 
 func RegisterIndexGatewayServer(s *grpc.Server, srv IndexGatewayServer) {
 	s.RegisterService(&_IndexGateway_serviceDesc, srv)
@@ -842,6 +952,24 @@ func (x *indexGatewayGetShardsServer) Send(m *ShardsResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _IndexGateway_GetStructuredMetadataKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StructuredMetadataKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IndexGatewayServer).GetStructuredMetadataKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/indexgatewaypb.IndexGateway/GetStructuredMetadataKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IndexGatewayServer).GetStructuredMetadataKeys(ctx, req.(*StructuredMetadataKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _IndexGateway_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "indexgatewaypb.IndexGateway",
 	HandlerType: (*IndexGatewayServer)(nil),
@@ -870,6 +998,10 @@ var _IndexGateway_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetVolume",
 			Handler:    _IndexGateway_GetVolume_Handler,
 		},
+		{
+			MethodName: "GetStructuredMetadataKeys",
+			Handler:    _IndexGateway_GetStructuredMetadataKeys_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1056,6 +1188,46 @@ func (m *FPBounds) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *StructuredMetadataKeysRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StructuredMetadataKeysRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StructuredMetadataKeysRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Matchers) > 0 {
+		i -= len(m.Matchers)
+		copy(dAtA[i:], m.Matchers)
+		i = encodeVarintIndexgateway(dAtA, i, uint64(len(m.Matchers)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Through != 0 {
+		i = encodeVarintIndexgateway(dAtA, i, uint64(m.Through))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.From != 0 {
+		i = encodeVarintIndexgateway(dAtA, i, uint64(m.From))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintIndexgateway(dAtA []byte, offset int, v uint64) int {
 	offset -= sovIndexgateway(v)
 	base := offset
@@ -1136,6 +1308,25 @@ func (m *FPBounds) Size() (n int) {
 	return n
 }
 
+func (m *StructuredMetadataKeysRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.From != 0 {
+		n += 1 + sovIndexgateway(uint64(m.From))
+	}
+	if m.Through != 0 {
+		n += 1 + sovIndexgateway(uint64(m.Through))
+	}
+	l = len(m.Matchers)
+	if l > 0 {
+		n += 1 + l + sovIndexgateway(uint64(l))
+	}
+	return n
+}
+
 func sovIndexgateway(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -1193,6 +1384,18 @@ func (this *FPBounds) String() string {
 	}, "")
 	return s
 }
+func (this *StructuredMetadataKeysRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&StructuredMetadataKeysRequest{`,
+		`From:` + fmt.Sprintf("%v", this.From) + `,`,
+		`Through:` + fmt.Sprintf("%v", this.Through) + `,`,
+		`Matchers:` + fmt.Sprintf("%v", this.Matchers) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func valueToStringIndexgateway(v interface{}) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -1652,6 +1855,126 @@ func (m *FPBounds) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipIndexgateway(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthIndexgateway
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StructuredMetadataKeysRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowIndexgateway
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StructuredMetadataKeysRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StructuredMetadataKeysRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
+			}
+			m.From = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIndexgateway
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.From |= github_com_prometheus_common_model.Time(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Through", wireType)
+			}
+			m.Through = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIndexgateway
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Through |= github_com_prometheus_common_model.Time(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Matchers", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIndexgateway
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthIndexgateway
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthIndexgateway
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Matchers = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipIndexgateway(dAtA[iNdEx:])
@@ -234,6 +234,7 @@ type StreamRate struct {
 	Rate              int64  `protobuf:"varint,3,opt,name=rate,proto3" json:"rate,omitempty"`
 	Tenant            string `protobuf:"bytes,4,opt,name=tenant,proto3" json:"tenant,omitempty"`
 	Pushes            uint32 `protobuf:"varint,5,opt,name=pushes,proto3" json:"pushes,omitempty"`
+	RateLimited       int64  `protobuf:"varint,6,opt,name=rateLimited,proto3" json:"rateLimited,omitempty"`
 }
 
 func (m *StreamRate) Reset()      { *m = StreamRate{} }
@@ -303,6 +304,13 @@ func (m *StreamRate) GetPushes() uint32 {
 	return 0
 }
 
+func (m *StreamRate) GetRateLimited() int64 {
+	if m != nil {
+		return m.RateLimited
+	}
+	return 0
+}
+
 type QueryRequest struct {
 	Selector  string                                                 `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"` // Deprecated: Do not use.
 	Limit     uint32                                                 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
@@ -1629,7 +1637,8 @@ func (m *GetChunkIDsRequest) GetEnd() time.Time {
 }
 
 type GetChunkIDsResponse struct {
-	ChunkIDs []string `protobuf:"bytes,1,rep,name=chunkIDs,proto3" json:"chunkIDs,omitempty"`
+	ChunkIDs    []string    `protobuf:"bytes,1,rep,name=chunkIDs,proto3" json:"chunkIDs,omitempty"`
+	SegmentRefs []*ChunkRef `protobuf:"bytes,2,rep,name=segmentRefs,proto3" json:"segmentRefs,omitempty"`
 }
 
 func (m *GetChunkIDsResponse) Reset()      { *m = GetChunkIDsResponse{} }
@@ -1671,6 +1680,13 @@ func (m *GetChunkIDsResponse) GetChunkIDs() []string {
 	return nil
 }
 
+func (m *GetChunkIDsResponse) GetSegmentRefs() []*ChunkRef {
+	if m != nil {
+		return m.SegmentRefs
+	}
+	return nil
+}
+
 // ChunkRef contains the metadata to reference a Chunk.
 // It is embedded by the Chunk type itself and used to generate the Chunk
 // checksum. So it is imported to take care of the JSON representation of the
@@ -1683,6 +1699,11 @@ type ChunkRef struct {
 	// The checksum is not written to the external storage. We use crc32,
 	// Castagnoli table. See http://www.evanjones.ca/crc32c.html.
 	Checksum uint32 `protobuf:"varint,5,opt,name=checksum,proto3" json:"-"`
+	// The segment the chunk is stored in, if it was flushed in a segment object
+	// with other chunks, and the range of the chunk within the segment.
+	SegmentID     uint64 `protobuf:"varint,6,opt,name=segment_id,json=segmentId,proto3" json:"-"`
+	SegmentOffset uint64 `protobuf:"varint,7,opt,name=segment_offset,json=segmentOffset,proto3" json:"-"`
+	SegmentLength uint32 `protobuf:"varint,8,opt,name=segment_length,json=segmentLength,proto3" json:"-"`
 }
 
 func (m *ChunkRef) Reset()      { *m = ChunkRef{} }
@@ -1738,6 +1759,27 @@ func (m *ChunkRef) GetChecksum() uint32 {
 	return 0
 }
 
+func (m *ChunkRef) GetSegmentID() uint64 {
+	if m != nil {
+		return m.SegmentID
+	}
+	return 0
+}
+
+func (m *ChunkRef) GetSegmentOffset() uint64 {
+	if m != nil {
+		return m.SegmentOffset
+	}
+	return 0
+}
+
+func (m *ChunkRef) GetSegmentLength() uint32 {
+	if m != nil {
+		return m.SegmentLength
+	}
+	return 0
+}
+
 type LabelValuesForMetricNameRequest struct {
 	MetricName string                                  `protobuf:"bytes,1,opt,name=metric_name,json=metricName,proto3" json:"metric_name,omitempty"`
 	LabelName  string                                  `protobuf:"bytes,2,opt,name=label_name,json=labelName,proto3" json:"label_name,omitempty"`
@@ -3015,168 +3057,173 @@ func init() {
 func init() { proto.RegisterFile("pkg/logproto/logproto.proto", fileDescriptor_c28a5f14f1f4c79a) }
 
 var fileDescriptor_c28a5f14f1f4c79a = []byte{
-	// 2569 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x39, 0xcb, 0x6f, 0x1b, 0xc7,
-	0xf9, 0x5c, 0x72, 0xf9, 0xfa, 0x48, 0xc9, 0xd2, 0x88, 0x96, 0x09, 0xda, 0x21, 0x95, 0xc1, 0xef,
-	0x97, 0xa8, 0xb1, 0x23, 0xc6, 0x72, 0xed, 0x3a, 0x76, 0xdd, 0xd4, 0x94, 0x62, 0x45, 0xb6, 0xfc,
-	0xc8, 0x48, 0x71, 0xd2, 0xa2, 0x86, 0xb1, 0x22, 0x47, 0xd4, 0xc2, 0xe4, 0x2e, 0xbd, 0x3b, 0xb4,
-	0xcd, 0x5b, 0xff, 0x81, 0xa2, 0x01, 0x7a, 0x68, 0x7b, 0x29, 0x50, 0xa0, 0x40, 0x8b, 0x14, 0xbd,
-	0x14, 0x3d, 0x16, 0xed, 0xa5, 0x07, 0xf7, 0xe6, 0xde, 0x82, 0x1c, 0xd8, 0x5a, 0xbe, 0x14, 0x3a,
-	0x05, 0xe8, 0x2d, 0xa7, 0x62, 0x1e, 0xfb, 0x14, 0x59, 0x87, 0x8a, 0x83, 0xc0, 0x17, 0x72, 0xe6,
-	0x9b, 0x6f, 0xbe, 0x99, 0xef, 0x31, 0xdf, 0x6b, 0xe1, 0x78, 0xef, 0x5e, 0xbb, 0xde, 0xb1, 0xdb,
-	0x3d, 0xc7, 0x66, 0xb6, 0x3f, 0x58, 0x12, 0xbf, 0x28, 0xe7, 0xcd, 0x2b, 0xa5, 0xb6, 0xdd, 0xb6,
-	0x25, 0x0e, 0x1f, 0xc9, 0xf5, 0x4a, 0xad, 0x6d, 0xdb, 0xed, 0x0e, 0xad, 0x8b, 0xd9, 0x76, 0x7f,
-	0xa7, 0xce, 0xcc, 0x2e, 0x75, 0x99, 0xd1, 0xed, 0x29, 0x84, 0x05, 0x45, 0xfd, 0x7e, 0xa7, 0x6b,
-	0xb7, 0x68, 0xa7, 0xee, 0x32, 0x83, 0xb9, 0xf2, 0x57, 0x61, 0xcc, 0x71, 0x8c, 0x5e, 0xdf, 0xdd,
-	0x15, 0x3f, 0x12, 0x88, 0xff, 0xa4, 0xc1, 0xd1, 0x0d, 0x63, 0x9b, 0x76, 0xb6, 0xec, 0xdb, 0x46,
-	0xa7, 0x4f, 0x5d, 0x42, 0xdd, 0x9e, 0x6d, 0xb9, 0x14, 0xad, 0x40, 0xa6, 0xc3, 0x17, 0xdc, 0xb2,
-	0xb6, 0x90, 0x5a, 0x2c, 0x2c, 0x9f, 0x5c, 0xf2, 0xaf, 0x3c, 0x72, 0x83, 0x84, 0xba, 0xef, 0x5a,
-	0xcc, 0x19, 0x10, 0xb5, 0xb5, 0x72, 0x1b, 0x0a, 0x21, 0x30, 0x9a, 0x81, 0xd4, 0x3d, 0x3a, 0x28,
-	0x6b, 0x0b, 0xda, 0x62, 0x9e, 0xf0, 0x21, 0x3a, 0x0d, 0xe9, 0x07, 0x9c, 0x4c, 0x39, 0xb9, 0xa0,
-	0x2d, 0x16, 0x96, 0x8f, 0x07, 0x87, 0x7c, 0x60, 0x99, 0xf7, 0xfb, 0x54, 0xec, 0x56, 0x07, 0x49,
-	0xcc, 0x0b, 0xc9, 0xf3, 0x1a, 0x3e, 0x09, 0xb3, 0x07, 0xd6, 0xd1, 0x3c, 0x64, 0x04, 0x86, 0xbc,
-	0x71, 0x9e, 0xa8, 0x19, 0x2e, 0x01, 0xda, 0x64, 0x0e, 0x35, 0xba, 0xc4, 0x60, 0xfc, 0xbe, 0xf7,
-	0xfb, 0xd4, 0x65, 0xf8, 0x3a, 0xcc, 0x45, 0xa0, 0x8a, 0xed, 0x73, 0x50, 0x70, 0x03, 0xb0, 0xe2,
-	0xbd, 0x14, 0x5c, 0x2b, 0xd8, 0x43, 0xc2, 0x88, 0xf8, 0x57, 0x1a, 0x40, 0xb0, 0x86, 0xaa, 0x00,
-	0x72, 0xf5, 0x3d, 0xc3, 0xdd, 0x15, 0x0c, 0xeb, 0x24, 0x04, 0x41, 0xa7, 0x60, 0x36, 0x98, 0xdd,
-	0xb0, 0x37, 0x77, 0x0d, 0xa7, 0x25, 0x64, 0xa0, 0x93, 0x83, 0x0b, 0x08, 0x81, 0xee, 0x18, 0x8c,
-	0x96, 0x53, 0x0b, 0xda, 0x62, 0x8a, 0x88, 0x31, 0xe7, 0x96, 0x51, 0xcb, 0xb0, 0x58, 0x59, 0x17,
-	0xe2, 0x54, 0x33, 0x0e, 0xe7, 0xfa, 0xa5, 0x6e, 0x39, 0xbd, 0xa0, 0x2d, 0x4e, 0x11, 0x35, 0xc3,
-	0x9f, 0xa4, 0xa0, 0xf8, 0x7e, 0x9f, 0x3a, 0x03, 0x25, 0x00, 0x54, 0x85, 0x9c, 0x4b, 0x3b, 0xb4,
-	0xc9, 0x6c, 0x47, 0x6a, 0xa4, 0x91, 0x2c, 0x6b, 0xc4, 0x87, 0xa1, 0x12, 0xa4, 0x3b, 0x66, 0xd7,
-	0x64, 0xe2, 0x5a, 0x53, 0x44, 0x4e, 0xd0, 0x05, 0x48, 0xbb, 0xcc, 0x70, 0x98, 0xb8, 0x4b, 0x61,
-	0xb9, 0xb2, 0x24, 0x0d, 0x73, 0xc9, 0x33, 0xcc, 0xa5, 0x2d, 0xcf, 0x30, 0x1b, 0xb9, 0xc7, 0xc3,
-	0x5a, 0xe2, 0xe3, 0x7f, 0xd6, 0x34, 0x22, 0xb7, 0xa0, 0x73, 0x90, 0xa2, 0x56, 0x4b, 0xdc, 0xf7,
-	0xcb, 0xee, 0xe4, 0x1b, 0xd0, 0x69, 0xc8, 0xb7, 0x4c, 0x87, 0x36, 0x99, 0x69, 0x5b, 0x82, 0xab,
-	0xe9, 0xe5, 0xb9, 0x40, 0x23, 0xab, 0xde, 0x12, 0x09, 0xb0, 0xd0, 0x29, 0xc8, 0xb8, 0x5c, 0x74,
-	0x6e, 0x39, 0xcb, 0x6d, 0xa1, 0x51, 0xda, 0x1f, 0xd6, 0x66, 0x24, 0xe4, 0x94, 0xdd, 0x35, 0x19,
-	0xed, 0xf6, 0xd8, 0x80, 0x28, 0x1c, 0xf4, 0x06, 0x64, 0x5b, 0xb4, 0x43, 0xb9, 0xc2, 0x73, 0x42,
-	0xe1, 0x33, 0x21, 0xf2, 0x62, 0x81, 0x78, 0x08, 0xe8, 0x0e, 0xe8, 0xbd, 0x8e, 0x61, 0x95, 0xf3,
-	0x82, 0x8b, 0xe9, 0x00, 0xf1, 0x56, 0xc7, 0xb0, 0x1a, 0x6f, 0x7f, 0x36, 0xac, 0x9d, 0x6d, 0x9b,
-	0x6c, 0xb7, 0xbf, 0xbd, 0xd4, 0xb4, 0xbb, 0xf5, 0xb6, 0x63, 0xec, 0x18, 0x96, 0x51, 0xef, 0xd8,
-	0xf7, 0xcc, 0xfa, 0x83, 0x33, 0x75, 0xfe, 0x06, 0xef, 0xf7, 0xa9, 0x63, 0x52, 0xa7, 0xce, 0xc9,
-	0x2c, 0x09, 0x95, 0xf0, 0xad, 0x44, 0x90, 0xbd, 0xaa, 0xe7, 0x32, 0x33, 0x59, 0xfc, 0x34, 0x09,
-	0x68, 0xd3, 0xe8, 0xf6, 0x3a, 0x74, 0x22, 0x95, 0xf9, 0xca, 0x49, 0x1e, 0x5a, 0x39, 0xa9, 0x49,
-	0x95, 0x13, 0x48, 0x5a, 0x9f, 0x4c, 0xd2, 0xe9, 0x2f, 0x2b, 0xe9, 0xcc, 0xd7, 0x22, 0x69, 0x5c,
-	0x06, 0x9d, 0xcf, 0xb8, 0x53, 0x72, 0x8c, 0x87, 0x42, 0x9e, 0x45, 0xc2, 0x87, 0x78, 0x03, 0x32,
-	0xf2, 0x2e, 0xa8, 0x12, 0x17, 0x78, 0xf4, 0x7d, 0x04, 0xc2, 0x4e, 0x79, 0x62, 0x9c, 0x09, 0xc4,
-	0x98, 0x12, 0x02, 0xc2, 0x7f, 0xd6, 0x60, 0x4a, 0x69, 0x51, 0xf9, 0x98, 0x6d, 0xc8, 0xca, 0x37,
-	0xee, 0xf9, 0x97, 0x63, 0x71, 0xff, 0x72, 0xb9, 0x65, 0xf4, 0x18, 0x75, 0x1a, 0xf5, 0xc7, 0xc3,
-	0x9a, 0xf6, 0xd9, 0xb0, 0xf6, 0xfa, 0x38, 0x46, 0x3d, 0x9f, 0xee, 0xf9, 0x25, 0x8f, 0x30, 0x3a,
-	0x29, 0x6e, 0xc7, 0x5c, 0x65, 0x0a, 0x47, 0x96, 0x64, 0x28, 0x58, 0xb7, 0xda, 0xd4, 0xe5, 0x94,
-	0x75, 0xae, 0x45, 0x22, 0x71, 0x38, 0x9b, 0x0f, 0x0d, 0xc7, 0x32, 0xad, 0xb6, 0x5b, 0x4e, 0x09,
-	0xdf, 0xe9, 0xcf, 0xf1, 0x2f, 0x34, 0x98, 0x8b, 0x98, 0xa2, 0x62, 0xe2, 0x3c, 0x64, 0x5c, 0x2e,
-	0x5d, 0x8f, 0x87, 0x90, 0x22, 0x37, 0x05, 0xbc, 0x31, 0xad, 0x2e, 0x9f, 0x91, 0x73, 0xa2, 0xf0,
-	0x5f, 0xdc, 0xd5, 0xfe, 0xa6, 0x41, 0x51, 0x04, 0x00, 0xef, 0x7d, 0x20, 0xd0, 0x2d, 0xa3, 0x4b,
-	0x95, 0xaa, 0xc4, 0x38, 0x14, 0x15, 0xf8, 0x71, 0x39, 0x2f, 0x2a, 0x4c, 0xea, 0xc8, 0xb4, 0x43,
-	0x3b, 0x32, 0x2d, 0x78, 0x2b, 0x25, 0x48, 0x73, 0x93, 0x1c, 0x08, 0x27, 0x96, 0x27, 0x72, 0x82,
-	0x5f, 0x87, 0x29, 0xc5, 0x85, 0x12, 0xed, 0xb8, 0x40, 0xd6, 0x85, 0x8c, 0xd4, 0x04, 0xfa, 0x3f,
-	0xc8, 0xfb, 0x09, 0x80, 0xe0, 0x36, 0xd5, 0xc8, 0xec, 0x0f, 0x6b, 0x49, 0xe6, 0x92, 0x60, 0x01,
-	0xd5, 0xc2, 0xc1, 0x55, 0x6b, 0xe4, 0xf7, 0x87, 0x35, 0x09, 0x50, 0xa1, 0x14, 0x9d, 0x00, 0x7d,
-	0x97, 0xc7, 0x27, 0x2e, 0x02, 0xbd, 0x91, 0xdb, 0x1f, 0xd6, 0xc4, 0x9c, 0x88, 0x5f, 0xbc, 0x06,
-	0xc5, 0x0d, 0xda, 0x36, 0x9a, 0x03, 0x75, 0x68, 0xc9, 0x23, 0xc7, 0x0f, 0xd4, 0x3c, 0x1a, 0xaf,
-	0x42, 0xd1, 0x3f, 0xf1, 0x6e, 0xd7, 0x55, 0xaf, 0xa1, 0xe0, 0xc3, 0xae, 0xbb, 0xf8, 0x97, 0x1a,
-	0x28, 0x1b, 0x40, 0x38, 0x94, 0x55, 0x70, 0xff, 0x05, 0xfb, 0xc3, 0x9a, 0x82, 0x78, 0x49, 0x03,
-	0xba, 0x08, 0x59, 0x57, 0x9c, 0xc8, 0x89, 0xc5, 0x4d, 0x4b, 0x2c, 0x34, 0x8e, 0x70, 0x13, 0xd9,
-	0x1f, 0xd6, 0x3c, 0x44, 0xe2, 0x0d, 0xd0, 0x52, 0x24, 0xf0, 0x4a, 0xc6, 0xa6, 0xf7, 0x87, 0xb5,
-	0x10, 0x34, 0x1c, 0x88, 0xf1, 0x17, 0x1a, 0x14, 0xb6, 0x0c, 0xd3, 0x37, 0xa1, 0xb2, 0xa7, 0xa2,
-	0xc0, 0xbf, 0x4a, 0x00, 0xb7, 0xc4, 0x16, 0xed, 0x18, 0x83, 0x2b, 0xb6, 0x23, 0xe8, 0x4e, 0x11,
-	0x7f, 0x1e, 0xc4, 0x4a, 0x7d, 0x64, 0xac, 0x4c, 0x4f, 0xee, 0x8e, 0xbf, 0x5e, 0xe7, 0x77, 0x55,
-	0xcf, 0x25, 0x67, 0x52, 0xf8, 0x0f, 0x1a, 0x14, 0x25, 0xf3, 0xca, 0xf2, 0x7e, 0x04, 0x19, 0x29,
-	0x1b, 0xc1, 0xfe, 0xff, 0x70, 0x4c, 0x27, 0x27, 0x71, 0x4a, 0x8a, 0x26, 0x7a, 0x07, 0xa6, 0x5b,
-	0x8e, 0xdd, 0xeb, 0xd1, 0xd6, 0xa6, 0x72, 0x7f, 0xc9, 0xb8, 0xfb, 0x5b, 0x0d, 0xaf, 0x93, 0x18,
-	0x3a, 0xfe, 0xbb, 0x06, 0x53, 0xca, 0x99, 0x28, 0x75, 0xf9, 0x22, 0xd6, 0x0e, 0x1d, 0xf1, 0x92,
-	0x93, 0x46, 0xbc, 0x79, 0xc8, 0xb4, 0x1d, 0xbb, 0xdf, 0xf3, 0x1c, 0x92, 0x9a, 0x4d, 0x16, 0x09,
-	0xf1, 0x55, 0x98, 0xf6, 0x58, 0x19, 0xe3, 0x51, 0x2b, 0x71, 0x8f, 0xba, 0xde, 0xa2, 0x16, 0x33,
-	0x77, 0x4c, 0xdf, 0x47, 0x2a, 0x7c, 0xfc, 0x53, 0x0d, 0x66, 0xe2, 0x28, 0x68, 0x35, 0x96, 0xc0,
-	0xbf, 0x36, 0x9e, 0x5c, 0x38, 0x77, 0xf7, 0x48, 0xab, 0x0c, 0xfe, 0xec, 0xf3, 0x32, 0xf8, 0x52,
-	0xd8, 0xc9, 0xe4, 0x95, 0x57, 0xc0, 0x3f, 0xd7, 0x60, 0x2a, 0xa2, 0x4b, 0x74, 0x1e, 0xf4, 0x1d,
-	0xc7, 0xee, 0x4e, 0xa4, 0x28, 0xb1, 0x03, 0x7d, 0x1b, 0x92, 0xcc, 0x9e, 0x48, 0x4d, 0x49, 0x66,
-	0x73, 0x2d, 0x29, 0xf6, 0x53, 0x32, 0x3f, 0x96, 0x33, 0x7c, 0x16, 0xf2, 0x82, 0xa1, 0x5b, 0x86,
-	0xe9, 0x8c, 0x0c, 0x18, 0xa3, 0x19, 0xba, 0x08, 0x47, 0xa4, 0x33, 0x1c, 0xbd, 0xb9, 0x38, 0x6a,
-	0x73, 0xd1, 0xdb, 0x7c, 0x1c, 0xd2, 0x2b, 0xbb, 0x7d, 0xeb, 0x1e, 0xdf, 0xd2, 0x32, 0x98, 0xe1,
-	0x6d, 0xe1, 0x63, 0x7c, 0x14, 0xe6, 0xf8, 0x1b, 0xa4, 0x8e, 0xbb, 0x62, 0xf7, 0x2d, 0xe6, 0xd5,
-	0x27, 0xa7, 0xa0, 0x14, 0x05, 0x2b, 0x2b, 0x29, 0x41, 0xba, 0xc9, 0x01, 0x82, 0xc6, 0x14, 0x91,
-	0x13, 0xfc, 0x1b, 0x0d, 0xd0, 0x1a, 0x65, 0xe2, 0x94, 0xf5, 0x55, 0xff, 0x79, 0x54, 0x20, 0xd7,
-	0x35, 0x58, 0x73, 0x97, 0x3a, 0xae, 0x97, 0xbf, 0x78, 0xf3, 0x6f, 0x22, 0x59, 0xc4, 0xa7, 0x61,
-	0x2e, 0x72, 0x4b, 0xc5, 0x53, 0x05, 0x72, 0x4d, 0x05, 0x53, 0x21, 0xcf, 0x9f, 0xe3, 0x3f, 0x26,
-	0x21, 0x27, 0x36, 0x10, 0xba, 0x83, 0x4e, 0x43, 0x61, 0xc7, 0xb4, 0xda, 0xd4, 0xe9, 0x39, 0xa6,
-	0x12, 0x81, 0xde, 0x38, 0xb2, 0x3f, 0xac, 0x85, 0xc1, 0x24, 0x3c, 0x41, 0x6f, 0x42, 0xb6, 0xef,
-	0x52, 0xe7, 0xae, 0x29, 0x5f, 0x7a, 0xbe, 0x51, 0xda, 0x1b, 0xd6, 0x32, 0x1f, 0xb8, 0xd4, 0x59,
-	0x5f, 0xe5, 0xc1, 0xa7, 0x2f, 0x46, 0x44, 0xfe, 0xb7, 0xd0, 0x35, 0x65, 0xa6, 0x22, 0x81, 0x6b,
-	0x7c, 0x87, 0x5f, 0x3f, 0xe6, 0xea, 0x7a, 0x8e, 0xdd, 0xa5, 0x6c, 0x97, 0xf6, 0xdd, 0x7a, 0xd3,
-	0xee, 0x76, 0x6d, 0xab, 0x2e, 0x2a, 0x6e, 0xc1, 0x34, 0x8f, 0xa0, 0x7c, 0xbb, 0xb2, 0xdc, 0x2d,
-	0xc8, 0xb2, 0x5d, 0xc7, 0xee, 0xb7, 0x77, 0x45, 0x60, 0x48, 0x35, 0x2e, 0x4c, 0x4e, 0xcf, 0xa3,
-	0x40, 0xbc, 0x01, 0x7a, 0x95, 0x4b, 0x8b, 0x36, 0xef, 0xb9, 0xfd, 0xae, 0xac, 0xf1, 0x1a, 0xe9,
-	0xfd, 0x61, 0x4d, 0x7b, 0x93, 0xf8, 0x60, 0xfc, 0x93, 0x24, 0xd4, 0x42, 0xa5, 0xf1, 0x15, 0xdb,
-	0xb9, 0x4e, 0x99, 0x63, 0x36, 0x6f, 0x18, 0x5d, 0xea, 0xd9, 0x46, 0x0d, 0x0a, 0x5d, 0x01, 0xbc,
-	0x1b, 0x7a, 0x02, 0xd0, 0xf5, 0xf1, 0xd0, 0x2b, 0x00, 0xe2, 0xcd, 0xc8, 0x75, 0xf9, 0x1a, 0xf2,
-	0x02, 0x22, 0x96, 0x57, 0x22, 0x92, 0xaa, 0x4f, 0xc8, 0x99, 0x92, 0xd0, 0x7a, 0x5c, 0x42, 0x13,
-	0xd3, 0xf1, 0xc5, 0x12, 0xb6, 0xf5, 0x74, 0xd4, 0xd6, 0xf1, 0x3f, 0x34, 0xa8, 0x6e, 0x78, 0x37,
-	0x3f, 0xa4, 0x38, 0x3c, 0x7e, 0x93, 0x2f, 0x88, 0xdf, 0xd4, 0x57, 0xe3, 0x17, 0x57, 0x01, 0x36,
-	0x4c, 0x8b, 0x5e, 0x31, 0x3b, 0x8c, 0x3a, 0x23, 0xaa, 0x98, 0x9f, 0xa5, 0x02, 0x97, 0x40, 0xe8,
-	0x8e, 0xc7, 0xe7, 0x4a, 0xc8, 0x0f, 0xbf, 0x08, 0x36, 0x92, 0x2f, 0x50, 0x6d, 0xa9, 0x98, 0x8b,
-	0xb2, 0x20, 0xbb, 0x23, 0xd8, 0x93, 0x21, 0x35, 0xd2, 0x88, 0x09, 0x78, 0x6f, 0x7c, 0x4f, 0x1d,
-	0x7e, 0xee, 0x39, 0x19, 0x91, 0x68, 0x8f, 0xd5, 0xdd, 0x81, 0xc5, 0x8c, 0x47, 0xa1, 0xfd, 0xc4,
-	0x3b, 0x04, 0x19, 0x2a, 0xe9, 0x4a, 0x8f, 0x4c, 0xba, 0x2e, 0xa9, 0x63, 0xbe, 0x52, 0xd5, 0x79,
-	0x29, 0xf0, 0x80, 0x42, 0x29, 0xca, 0x03, 0xbe, 0x06, 0xba, 0x43, 0x77, 0xbc, 0x50, 0x8d, 0x82,
-	0x93, 0x7d, 0x4c, 0xb1, 0x8e, 0xff, 0xa2, 0xc1, 0xcc, 0x1a, 0x65, 0xd1, 0x24, 0xe8, 0x25, 0x52,
-	0x29, 0x7e, 0x0f, 0x66, 0x43, 0xf7, 0x57, 0xdc, 0x9f, 0x89, 0x65, 0x3e, 0x47, 0x03, 0xfe, 0xd7,
-	0xad, 0x16, 0x7d, 0xa4, 0x0a, 0xca, 0x68, 0xd2, 0x73, 0x0b, 0x0a, 0xa1, 0x45, 0x74, 0x39, 0x96,
-	0xee, 0xcc, 0xc5, 0xfa, 0x95, 0x3c, 0x64, 0x37, 0x4a, 0x8a, 0x27, 0x59, 0x36, 0xaa, 0x64, 0xd6,
-	0x4f, 0x0d, 0x36, 0x01, 0x09, 0x75, 0x09, 0xb2, 0xe1, 0xe0, 0x24, 0xa0, 0xd7, 0xfc, 0xbc, 0xc7,
-	0x9f, 0xa3, 0x57, 0x41, 0x77, 0xec, 0x87, 0x5e, 0x1e, 0x3b, 0x15, 0x1c, 0x49, 0xec, 0x87, 0x44,
-	0x2c, 0xe1, 0x8b, 0x90, 0x22, 0xf6, 0x43, 0x54, 0x05, 0x70, 0x0c, 0xab, 0x4d, 0x6f, 0xfb, 0x15,
-	0x54, 0x91, 0x84, 0x20, 0x63, 0x12, 0x87, 0x15, 0x98, 0x0d, 0xdf, 0x48, 0xaa, 0x7b, 0x09, 0xb2,
-	0xef, 0xf7, 0xc3, 0xe2, 0x2a, 0xc5, 0xc4, 0x25, 0x0b, 0x75, 0x0f, 0x89, 0xdb, 0x0c, 0x04, 0x70,
-	0x74, 0x02, 0xf2, 0xcc, 0xd8, 0xee, 0xd0, 0x1b, 0x81, 0x9b, 0x0b, 0x00, 0x7c, 0x95, 0x17, 0x7f,
-	0xb7, 0x43, 0x19, 0x50, 0x00, 0x40, 0x6f, 0xc0, 0x4c, 0x70, 0xe7, 0x5b, 0x0e, 0xdd, 0x31, 0x1f,
-	0x09, 0x0d, 0x17, 0xc9, 0x01, 0x38, 0x5a, 0x84, 0x23, 0x01, 0x6c, 0x53, 0x64, 0x1a, 0xba, 0x40,
-	0x8d, 0x83, 0xb9, 0x6c, 0x04, 0xbb, 0xef, 0xde, 0xef, 0x1b, 0x1d, 0xf1, 0xf8, 0x8a, 0x24, 0x04,
-	0xc1, 0x7f, 0xd5, 0x60, 0x56, 0xaa, 0x9a, 0x19, 0xec, 0xa5, 0xb4, 0xfa, 0xdf, 0x6a, 0x80, 0xc2,
-	0x1c, 0x28, 0xd3, 0xfa, 0xff, 0x70, 0x23, 0x88, 0xa7, 0x32, 0x05, 0x51, 0xd3, 0x4a, 0x50, 0xd0,
-	0xcb, 0xc1, 0x90, 0x11, 0xe9, 0x90, 0x2c, 0xae, 0x75, 0x59, 0x34, 0x4b, 0x08, 0x51, 0xff, 0xbc,
-	0xd6, 0xdf, 0x1e, 0x30, 0xea, 0xaa, 0x92, 0x57, 0xd4, 0xfa, 0x02, 0x40, 0xe4, 0x1f, 0x3f, 0x8b,
-	0x5a, 0x4c, 0x58, 0x8d, 0x1e, 0x9c, 0xa5, 0x40, 0xc4, 0x1b, 0xe0, 0xdf, 0x27, 0x61, 0xea, 0xb6,
-	0xdd, 0xe9, 0x07, 0x81, 0xf1, 0x65, 0x0a, 0x18, 0x91, 0x3a, 0x3c, 0xed, 0xd5, 0xe1, 0x08, 0x74,
-	0x97, 0xd1, 0x9e, 0xb0, 0xac, 0x14, 0x11, 0x63, 0x84, 0xa1, 0xc8, 0x0c, 0xa7, 0x4d, 0x99, 0xac,
-	0x6e, 0xca, 0x19, 0x91, 0x76, 0x46, 0x60, 0x68, 0x01, 0x0a, 0x46, 0xbb, 0xed, 0xd0, 0xb6, 0xc1,
-	0x68, 0x63, 0x50, 0xce, 0x8a, 0xc3, 0xc2, 0x20, 0xfc, 0x11, 0x4c, 0x7b, 0xc2, 0x52, 0x2a, 0x7d,
-	0x0b, 0xb2, 0x0f, 0x04, 0x64, 0x44, 0x5f, 0x4c, 0xa2, 0x2a, 0x37, 0xe6, 0xa1, 0x45, 0xfb, 0xec,
-	0xde, 0x9d, 0xf1, 0x55, 0xc8, 0x48, 0x74, 0x74, 0x22, 0x5c, 0xa3, 0xc8, 0x26, 0x0d, 0x9f, 0xab,
-	0x82, 0x03, 0x43, 0x46, 0x12, 0x52, 0x8a, 0x17, 0xb6, 0x21, 0x21, 0x44, 0xfd, 0xe3, 0xff, 0x68,
-	0x70, 0x74, 0x95, 0x32, 0xda, 0x64, 0xb4, 0x75, 0xc5, 0xa4, 0x9d, 0xd6, 0x37, 0x5a, 0x3e, 0xfb,
-	0x4d, 0xb0, 0x54, 0xa8, 0x09, 0xc6, 0xfd, 0x4e, 0xc7, 0xb4, 0xe8, 0x46, 0xa8, 0x8b, 0x12, 0x00,
-	0xb8, 0x87, 0xd8, 0xe1, 0x17, 0x97, 0xcb, 0xf2, 0xc3, 0x46, 0x08, 0xe2, 0x6b, 0x38, 0x13, 0x68,
-	0x18, 0xaf, 0xc3, 0x7c, 0x9c, 0x69, 0xa5, 0xa3, 0x3a, 0x64, 0xc4, 0xde, 0x11, 0xed, 0xd7, 0xc8,
-	0x0e, 0xa2, 0xd0, 0xb0, 0x03, 0x53, 0x91, 0x05, 0xa1, 0x33, 0x6e, 0x23, 0xca, 0x7f, 0xca, 0x09,
-	0xfa, 0x16, 0xe8, 0x6c, 0xd0, 0x53, 0x6e, 0xb3, 0x71, 0xf4, 0x8b, 0x61, 0x6d, 0x36, 0xb2, 0x6d,
-	0x6b, 0xd0, 0xa3, 0x44, 0xa0, 0x70, 0xd3, 0x6a, 0x1a, 0x4e, 0xcb, 0xb4, 0x8c, 0x8e, 0xc9, 0xa4,
-	0x28, 0x74, 0x12, 0x06, 0xe1, 0x5f, 0x87, 0x94, 0x26, 0xed, 0xf1, 0x90, 0x4a, 0xd3, 0x0e, 0xad,
-	0x34, 0xed, 0x39, 0x4a, 0xc3, 0x3f, 0x08, 0x44, 0xec, 0x5d, 0x51, 0x89, 0xf8, 0x1d, 0x98, 0x6e,
-	0x45, 0x56, 0xc6, 0x8b, 0x5a, 0xf6, 0x3e, 0x63, 0xe8, 0x78, 0x2d, 0x10, 0xb9, 0x80, 0x8c, 0x11,
-	0x79, 0x4c, 0x8e, 0xc9, 0x03, 0x72, 0x7c, 0xe3, 0x35, 0xc8, 0xfb, 0x5f, 0x88, 0x50, 0x01, 0xb2,
-	0x57, 0x6e, 0x92, 0x0f, 0x2f, 0x93, 0xd5, 0x99, 0x04, 0x2a, 0x42, 0xae, 0x71, 0x79, 0xe5, 0x9a,
-	0x98, 0x69, 0xcb, 0x9f, 0x64, 0xbc, 0xb0, 0xea, 0xa0, 0xef, 0x42, 0x5a, 0xc6, 0xca, 0xf9, 0xe0,
-	0xba, 0xe1, 0x0f, 0x31, 0x95, 0x63, 0x07, 0xe0, 0x92, 0x6f, 0x9c, 0x78, 0x4b, 0x43, 0x37, 0xa0,
-	0x20, 0x80, 0xaa, 0x6d, 0x7a, 0x22, 0xde, 0xbd, 0x8c, 0x50, 0x7a, 0x65, 0xcc, 0x6a, 0x88, 0xde,
-	0x05, 0x48, 0x4b, 0x11, 0xcc, 0xc7, 0x52, 0x9a, 0x11, 0xb7, 0x89, 0x34, 0x92, 0x71, 0x02, 0xbd,
-	0x0d, 0xfa, 0x96, 0x61, 0x76, 0x50, 0x28, 0xa3, 0x0a, 0x75, 0x3b, 0x2b, 0xf3, 0x71, 0x70, 0xe8,
-	0xd8, 0x4b, 0x7e, 0xd3, 0xf6, 0x58, 0xbc, 0x73, 0xe4, 0x6d, 0x2f, 0x1f, 0x5c, 0xf0, 0x4f, 0xbe,
-	0x29, 0x5b, 0x8b, 0x5e, 0xff, 0x02, 0xbd, 0x12, 0x3d, 0x2a, 0xd6, 0xee, 0xa8, 0x54, 0xc7, 0x2d,
-	0xfb, 0x04, 0x37, 0xa0, 0x10, 0xea, 0x1d, 0x84, 0xc5, 0x7a, 0xb0, 0xf1, 0x11, 0x16, 0xeb, 0x88,
-	0x86, 0x03, 0x4e, 0xa0, 0x35, 0xc8, 0xf1, 0x3c, 0x54, 0x7c, 0x63, 0x38, 0x1e, 0x4f, 0x37, 0x43,
-	0x69, 0x46, 0xe5, 0xc4, 0xe8, 0x45, 0x9f, 0xd0, 0xf7, 0x21, 0xbf, 0x46, 0x99, 0xf2, 0xd5, 0xc7,
-	0xe2, 0xce, 0x7e, 0x84, 0xa4, 0xa2, 0x01, 0x03, 0x27, 0xd0, 0x47, 0x22, 0x25, 0x8e, 0xfa, 0x2a,
-	0x54, 0x1b, 0xe3, 0x93, 0xfc, 0x7b, 0x2d, 0x8c, 0x47, 0xf0, 0x29, 0x7f, 0x18, 0xa1, 0xac, 0xa2,
-	0x5a, 0x6d, 0xcc, 0x13, 0xf4, 0x29, 0xd7, 0x9e, 0xf3, 0xa5, 0x1f, 0x27, 0x96, 0xef, 0x78, 0x1f,
-	0xbb, 0x57, 0x0d, 0x66, 0xa0, 0x9b, 0x30, 0x2d, 0x64, 0xe9, 0x7f, 0x0d, 0x8f, 0xd8, 0xfc, 0x81,
-	0x4f, 0xef, 0x11, 0x9b, 0x3f, 0xf8, 0x09, 0x1e, 0x27, 0x1a, 0x77, 0x9e, 0x3c, 0xad, 0x26, 0x3e,
-	0x7d, 0x5a, 0x4d, 0x7c, 0xfe, 0xb4, 0xaa, 0xfd, 0x78, 0xaf, 0xaa, 0xfd, 0x6e, 0xaf, 0xaa, 0x3d,
-	0xde, 0xab, 0x6a, 0x4f, 0xf6, 0xaa, 0xda, 0xbf, 0xf6, 0xaa, 0xda, 0xbf, 0xf7, 0xaa, 0x89, 0xcf,
-	0xf7, 0xaa, 0xda, 0xc7, 0xcf, 0xaa, 0x89, 0x27, 0xcf, 0xaa, 0x89, 0x4f, 0x9f, 0x55, 0x13, 0x3f,
-	0x7c, 0xfd, 0xf9, 0xe5, 0x9f, 0x74, 0x74, 0x19, 0xf1, 0x77, 0xe6, 0xbf, 0x01, 0x00, 0x00, 0xff,
-	0xff, 0x78, 0xeb, 0x77, 0xf7, 0x92, 0x21, 0x00, 0x00,
+	// 2650 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x1a, 0x4d, 0x6f, 0x1b, 0xc7,
+	0x55, 0x4b, 0x2e, 0xbf, 0x1e, 0x49, 0x59, 0x1e, 0xd1, 0x32, 0x41, 0xdb, 0x5c, 0x65, 0xd0, 0x26,
+	0x6e, 0xec, 0x88, 0xb1, 0xf3, 0xd1, 0xc4, 0x69, 0xda, 0x9a, 0x52, 0xec, 0xc8, 0x51, 0x6c, 0x67,
+	0xe4, 0x38, 0x69, 0xd1, 0x20, 0x58, 0x93, 0x43, 0x6a, 0x61, 0x72, 0x97, 0xde, 0x1d, 0xc6, 0xe1,
+	0xad, 0x7f, 0xa0, 0x68, 0x80, 0x1e, 0xda, 0x1e, 0x0b, 0x14, 0x68, 0x91, 0xa2, 0xb7, 0x1e, 0x8b,
+	0x7e, 0x00, 0x3d, 0xa4, 0xb7, 0xf4, 0x16, 0xe4, 0xc0, 0xd6, 0xca, 0xa5, 0xd0, 0x29, 0x40, 0x6f,
+	0x39, 0x15, 0xf3, 0xb1, 0xbb, 0xb3, 0x2b, 0xb2, 0x2e, 0x1d, 0x07, 0x81, 0x2f, 0xe2, 0xcc, 0x7b,
+	0x6f, 0xde, 0xcc, 0xfb, 0x98, 0xf7, 0xde, 0xbc, 0x15, 0x9c, 0x18, 0xdd, 0xee, 0xb7, 0x06, 0x5e,
+	0x7f, 0xe4, 0x7b, 0xcc, 0x8b, 0x06, 0x1b, 0xe2, 0x2f, 0x2a, 0x86, 0xf3, 0x46, 0xad, 0xef, 0xf5,
+	0x3d, 0x49, 0xc3, 0x47, 0x12, 0xdf, 0xb0, 0xfa, 0x9e, 0xd7, 0x1f, 0xd0, 0x96, 0x98, 0xdd, 0x1a,
+	0xf7, 0x5a, 0xcc, 0x19, 0xd2, 0x80, 0xd9, 0xc3, 0x91, 0x22, 0x58, 0x57, 0xdc, 0xef, 0x0c, 0x86,
+	0x5e, 0x97, 0x0e, 0x5a, 0x01, 0xb3, 0x59, 0x20, 0xff, 0x2a, 0x8a, 0x55, 0x4e, 0x31, 0x1a, 0x07,
+	0x7b, 0xe2, 0x8f, 0x04, 0xe2, 0x3f, 0x18, 0x70, 0x6c, 0xc7, 0xbe, 0x45, 0x07, 0x37, 0xbc, 0x9b,
+	0xf6, 0x60, 0x4c, 0x03, 0x42, 0x83, 0x91, 0xe7, 0x06, 0x14, 0x6d, 0x42, 0x7e, 0xc0, 0x11, 0x41,
+	0xdd, 0x58, 0xcf, 0x9e, 0x2e, 0x9f, 0x3f, 0xb3, 0x11, 0x1d, 0x79, 0xe6, 0x02, 0x09, 0x0d, 0x5e,
+	0x71, 0x99, 0x3f, 0x21, 0x6a, 0x69, 0xe3, 0x26, 0x94, 0x35, 0x30, 0x5a, 0x81, 0xec, 0x6d, 0x3a,
+	0xa9, 0x1b, 0xeb, 0xc6, 0xe9, 0x12, 0xe1, 0x43, 0x74, 0x0e, 0x72, 0xef, 0x71, 0x36, 0xf5, 0xcc,
+	0xba, 0x71, 0xba, 0x7c, 0xfe, 0x44, 0xbc, 0xc9, 0x9b, 0xae, 0x73, 0x67, 0x4c, 0xc5, 0x6a, 0xb5,
+	0x91, 0xa4, 0xbc, 0x90, 0x79, 0xc1, 0xc0, 0x67, 0xe0, 0xe8, 0x21, 0x3c, 0x5a, 0x83, 0xbc, 0xa0,
+	0x90, 0x27, 0x2e, 0x11, 0x35, 0xc3, 0x35, 0x40, 0xbb, 0xcc, 0xa7, 0xf6, 0x90, 0xd8, 0x8c, 0x9f,
+	0xf7, 0xce, 0x98, 0x06, 0x0c, 0xbf, 0x0e, 0xab, 0x09, 0xa8, 0x12, 0xfb, 0x79, 0x28, 0x07, 0x31,
+	0x58, 0xc9, 0x5e, 0x8b, 0x8f, 0x15, 0xaf, 0x21, 0x3a, 0x21, 0xfe, 0x8b, 0x01, 0x10, 0xe3, 0x50,
+	0x13, 0x40, 0x62, 0x5f, 0xb5, 0x83, 0x3d, 0x21, 0xb0, 0x49, 0x34, 0x08, 0x3a, 0x0b, 0x47, 0xe3,
+	0xd9, 0x55, 0x6f, 0x77, 0xcf, 0xf6, 0xbb, 0x42, 0x07, 0x26, 0x39, 0x8c, 0x40, 0x08, 0x4c, 0xdf,
+	0x66, 0xb4, 0x9e, 0x5d, 0x37, 0x4e, 0x67, 0x89, 0x18, 0x73, 0x69, 0x19, 0x75, 0x6d, 0x97, 0xd5,
+	0x4d, 0xa1, 0x4e, 0x35, 0xe3, 0x70, 0x6e, 0x5f, 0x1a, 0xd4, 0x73, 0xeb, 0xc6, 0xe9, 0x2a, 0x51,
+	0x33, 0xb4, 0x0e, 0x65, 0xbe, 0x6e, 0xc7, 0x19, 0x3a, 0x8c, 0x76, 0xeb, 0x79, 0xc1, 0x4a, 0x07,
+	0xe1, 0x0f, 0xb3, 0x50, 0x79, 0x63, 0x4c, 0xfd, 0x89, 0x52, 0x11, 0x6a, 0x42, 0x31, 0xa0, 0x03,
+	0xda, 0x61, 0x9e, 0x2f, 0x6d, 0xd6, 0xce, 0xd4, 0x0d, 0x12, 0xc1, 0x50, 0x0d, 0x72, 0x03, 0xbe,
+	0x56, 0x1c, 0xbc, 0x4a, 0xe4, 0x04, 0x5d, 0x80, 0x5c, 0xc0, 0x6c, 0x9f, 0x89, 0xd3, 0x96, 0xcf,
+	0x37, 0x36, 0xa4, 0xeb, 0x6e, 0x84, 0xae, 0xbb, 0x71, 0x23, 0x74, 0xdd, 0x76, 0xf1, 0xa3, 0xa9,
+	0xb5, 0xf4, 0xc1, 0x3f, 0x2d, 0x83, 0xc8, 0x25, 0xe8, 0x79, 0xc8, 0x52, 0xb7, 0x5b, 0x37, 0x17,
+	0x58, 0xc9, 0x17, 0xa0, 0x73, 0x50, 0xea, 0x3a, 0x3e, 0xed, 0x30, 0xc7, 0x73, 0x85, 0xdc, 0xcb,
+	0xe7, 0x57, 0x63, 0x9b, 0x6d, 0x85, 0x28, 0x12, 0x53, 0xa1, 0xb3, 0x90, 0x0f, 0xb8, 0x72, 0x83,
+	0x7a, 0x81, 0x7b, 0x4b, 0xbb, 0x76, 0x30, 0xb5, 0x56, 0x24, 0xe4, 0xac, 0xc7, 0x15, 0x32, 0x1c,
+	0xb1, 0x09, 0x51, 0x34, 0xe8, 0x49, 0x28, 0x74, 0xe9, 0x80, 0x72, 0x97, 0x28, 0x0a, 0x97, 0x58,
+	0xd1, 0xd8, 0x0b, 0x04, 0x09, 0x09, 0xd0, 0x3b, 0x60, 0x8e, 0x06, 0xb6, 0x5b, 0x2f, 0x09, 0x29,
+	0x96, 0x63, 0xc2, 0xeb, 0x03, 0xdb, 0x6d, 0xbf, 0xf8, 0xe9, 0xd4, 0x7a, 0xae, 0xef, 0xb0, 0xbd,
+	0xf1, 0xad, 0x8d, 0x8e, 0x37, 0x6c, 0xf5, 0x7d, 0xbb, 0x67, 0xbb, 0x76, 0x6b, 0xe0, 0xdd, 0x76,
+	0x5a, 0xef, 0x3d, 0xd3, 0xe2, 0xb7, 0xf4, 0xce, 0x98, 0xfa, 0x0e, 0xf5, 0x5b, 0x9c, 0xcd, 0x86,
+	0x30, 0x09, 0x5f, 0x4a, 0x04, 0xdb, 0x2b, 0x66, 0x31, 0xbf, 0x52, 0xc0, 0xf7, 0x32, 0x80, 0x76,
+	0xed, 0xe1, 0x68, 0x40, 0x17, 0x32, 0x59, 0x64, 0x9c, 0xcc, 0x03, 0x1b, 0x27, 0xbb, 0xa8, 0x71,
+	0x62, 0x4d, 0x9b, 0x8b, 0x69, 0x3a, 0xf7, 0xff, 0x6a, 0x3a, 0xff, 0x95, 0x68, 0x1a, 0xd7, 0xc1,
+	0xe4, 0x33, 0x1e, 0xb6, 0x7c, 0xfb, 0xae, 0xd0, 0x67, 0x85, 0xf0, 0x21, 0xde, 0x81, 0xbc, 0x3c,
+	0x0b, 0x6a, 0xa4, 0x15, 0x9e, 0xbc, 0x1f, 0xb1, 0xb2, 0xb3, 0xa1, 0x1a, 0x57, 0x62, 0x35, 0x66,
+	0x85, 0x82, 0xf0, 0x1f, 0x0d, 0xa8, 0x2a, 0x2b, 0xaa, 0x28, 0x74, 0x0b, 0x0a, 0x32, 0x0a, 0x84,
+	0x11, 0xe8, 0x78, 0x3a, 0x02, 0x5d, 0xec, 0xda, 0x23, 0x46, 0xfd, 0x76, 0xeb, 0xa3, 0xa9, 0x65,
+	0x7c, 0x3a, 0xb5, 0x9e, 0x98, 0x27, 0x68, 0x18, 0xf5, 0xd5, 0x3a, 0x12, 0x32, 0x46, 0x67, 0xc4,
+	0xe9, 0x58, 0xa0, 0x5c, 0xe1, 0xc8, 0x86, 0x98, 0x6d, 0x6c, 0xbb, 0x7d, 0x1a, 0x70, 0xce, 0x26,
+	0xb7, 0x22, 0x91, 0x34, 0x5c, 0xcc, 0xbb, 0xb6, 0xef, 0x3a, 0x6e, 0x3f, 0xa8, 0x67, 0x45, 0x74,
+	0x8d, 0xe6, 0xf8, 0x17, 0x06, 0xac, 0x26, 0x5c, 0x51, 0x09, 0xf1, 0x02, 0xe4, 0x03, 0xae, 0xdd,
+	0x50, 0x06, 0xcd, 0x90, 0xbb, 0x02, 0xde, 0x5e, 0x56, 0x87, 0xcf, 0xcb, 0x39, 0x51, 0xf4, 0x0f,
+	0xef, 0x68, 0x7f, 0x33, 0xa0, 0x22, 0x52, 0x44, 0x78, 0x3f, 0x10, 0x98, 0xae, 0x3d, 0xa4, 0xca,
+	0x54, 0x62, 0xac, 0xe5, 0x0d, 0xbe, 0x5d, 0x31, 0xcc, 0x1b, 0x8b, 0x06, 0x32, 0xe3, 0x81, 0x03,
+	0x99, 0x11, 0xdf, 0x95, 0x1a, 0xe4, 0xb8, 0x4b, 0x4e, 0x44, 0x10, 0x2b, 0x11, 0x39, 0xc1, 0x4f,
+	0x40, 0x55, 0x49, 0xa1, 0x54, 0x3b, 0x2f, 0xd5, 0x0d, 0x21, 0x2f, 0x2d, 0x81, 0xbe, 0x01, 0xa5,
+	0xa8, 0x44, 0x10, 0xd2, 0x66, 0xdb, 0xf9, 0x83, 0xa9, 0x95, 0x61, 0x01, 0x89, 0x11, 0xc8, 0xd2,
+	0xd3, 0xaf, 0xd1, 0x2e, 0x1d, 0x4c, 0x2d, 0x09, 0x50, 0xc9, 0x16, 0x9d, 0x04, 0x73, 0x8f, 0x67,
+	0x30, 0xae, 0x02, 0xb3, 0x5d, 0x3c, 0x98, 0x5a, 0x62, 0x4e, 0xc4, 0x5f, 0x7c, 0x19, 0x2a, 0x3b,
+	0xb4, 0x6f, 0x77, 0x26, 0x6a, 0xd3, 0x5a, 0xc8, 0x8e, 0x6f, 0x68, 0x84, 0x3c, 0x1e, 0x83, 0x4a,
+	0xb4, 0xe3, 0xbb, 0xc3, 0x40, 0xdd, 0x86, 0x72, 0x04, 0x7b, 0x3d, 0xc0, 0xbf, 0x34, 0x40, 0xf9,
+	0x00, 0xc2, 0x5a, 0xdd, 0xc1, 0xe3, 0x17, 0x1c, 0x4c, 0x2d, 0x05, 0x09, 0xcb, 0x0a, 0xf4, 0x12,
+	0x14, 0x02, 0xb1, 0x23, 0x67, 0x96, 0x76, 0x2d, 0x81, 0x68, 0x1f, 0xe1, 0x2e, 0x72, 0x30, 0xb5,
+	0x42, 0x42, 0x12, 0x0e, 0xd0, 0x46, 0x22, 0x35, 0x4b, 0xc1, 0x96, 0x0f, 0xa6, 0x96, 0x06, 0xd5,
+	0x53, 0x35, 0xfe, 0xc2, 0x80, 0xf2, 0x0d, 0xdb, 0x89, 0x5c, 0xa8, 0x1e, 0x9a, 0x28, 0x8e, 0xaf,
+	0x12, 0xc0, 0x3d, 0xb1, 0x4b, 0x07, 0xf6, 0xe4, 0x92, 0xe7, 0x0b, 0xbe, 0x55, 0x12, 0xcd, 0xe3,
+	0x5c, 0x69, 0xce, 0xcc, 0x95, 0xb9, 0xc5, 0xc3, 0xf1, 0x57, 0x1b, 0xfc, 0xae, 0x98, 0xc5, 0xcc,
+	0x4a, 0x16, 0xff, 0xde, 0x80, 0x8a, 0x14, 0x5e, 0x79, 0xde, 0x8f, 0x20, 0x2f, 0x75, 0x23, 0xc4,
+	0xff, 0x1f, 0x81, 0xe9, 0xcc, 0x22, 0x41, 0x49, 0xf1, 0x44, 0xdf, 0x83, 0xe5, 0xae, 0xef, 0x8d,
+	0x46, 0xb4, 0xbb, 0xab, 0xc2, 0x5f, 0x26, 0x1d, 0xfe, 0xb6, 0x74, 0x3c, 0x49, 0x91, 0xe3, 0xbf,
+	0x1b, 0x50, 0x55, 0xc1, 0x44, 0x99, 0x2b, 0x52, 0xb1, 0xf1, 0xc0, 0x19, 0x2f, 0xb3, 0x68, 0xc6,
+	0x5b, 0x83, 0x7c, 0xdf, 0xf7, 0xc6, 0xa3, 0x30, 0x20, 0xa9, 0xd9, 0x62, 0x99, 0x10, 0x5f, 0x81,
+	0xe5, 0x50, 0x94, 0x39, 0x11, 0xb5, 0x91, 0x8e, 0xa8, 0xdb, 0x5d, 0xea, 0x32, 0xa7, 0xe7, 0x44,
+	0x31, 0x52, 0xd1, 0xe3, 0x9f, 0x1a, 0xb0, 0x92, 0x26, 0x41, 0x5b, 0xa9, 0x12, 0xff, 0xf1, 0xf9,
+	0xec, 0xf4, 0xea, 0x3e, 0x64, 0xad, 0x6a, 0xfc, 0xe7, 0xee, 0x57, 0xe3, 0xd7, 0xf4, 0x20, 0x53,
+	0x52, 0x51, 0x01, 0xff, 0xdc, 0x80, 0x6a, 0xc2, 0x96, 0xe8, 0x05, 0x30, 0x7b, 0xbe, 0x37, 0x5c,
+	0xc8, 0x50, 0x62, 0x05, 0x7a, 0x16, 0x32, 0xcc, 0x5b, 0xc8, 0x4c, 0x19, 0xe6, 0x71, 0x2b, 0x29,
+	0xf1, 0xb3, 0xb2, 0x82, 0x96, 0x33, 0xfc, 0x1c, 0x94, 0x84, 0x40, 0xd7, 0x6d, 0xc7, 0x9f, 0x99,
+	0x30, 0x66, 0x0b, 0xf4, 0x12, 0x1c, 0x91, 0xc1, 0x70, 0xf6, 0xe2, 0xca, 0xac, 0xc5, 0x95, 0x70,
+	0xf1, 0x09, 0xc8, 0x6d, 0xee, 0x8d, 0xdd, 0xdb, 0x7c, 0x49, 0xd7, 0x66, 0x76, 0xb8, 0x84, 0x8f,
+	0xf1, 0x31, 0x58, 0xe5, 0x77, 0x90, 0xfa, 0xc1, 0xa6, 0x37, 0x76, 0x59, 0xf8, 0x82, 0x39, 0x0b,
+	0xb5, 0x24, 0x58, 0x79, 0x49, 0x0d, 0x72, 0x1d, 0x0e, 0x10, 0x3c, 0xaa, 0x44, 0x4e, 0xf0, 0xaf,
+	0x0d, 0x40, 0x97, 0x29, 0x13, 0xbb, 0x6c, 0x6f, 0x45, 0xd7, 0xa3, 0x01, 0xc5, 0xa1, 0xcd, 0x3a,
+	0x7b, 0xd4, 0x0f, 0xc2, 0xfa, 0x25, 0x9c, 0x7f, 0x1d, 0xc5, 0x22, 0xee, 0xc3, 0x6a, 0xe2, 0x94,
+	0x4a, 0xa6, 0x06, 0x14, 0x3b, 0x0a, 0xa6, 0x52, 0x5e, 0x34, 0x47, 0xcf, 0x42, 0x39, 0xa0, 0xfd,
+	0x21, 0xe5, 0x2a, 0xe8, 0x85, 0x11, 0x03, 0xc5, 0xbe, 0x2c, 0x98, 0x11, 0xda, 0x23, 0x3a, 0x19,
+	0xfe, 0x6b, 0x16, 0x8a, 0x21, 0x06, 0x9d, 0x83, 0x72, 0xcf, 0x71, 0xfb, 0xd4, 0x1f, 0xf9, 0x8e,
+	0x52, 0x9c, 0xd9, 0x3e, 0x72, 0x30, 0xb5, 0x74, 0x30, 0xd1, 0x27, 0xe8, 0x29, 0x28, 0x8c, 0x03,
+	0xea, 0xbf, 0xeb, 0xc8, 0xf8, 0x50, 0x6a, 0xd7, 0xf6, 0xa7, 0x56, 0xfe, 0xcd, 0x80, 0xfa, 0xdb,
+	0x5b, 0x3c, 0x65, 0x8d, 0xc5, 0x88, 0xc8, 0xdf, 0x2e, 0x7a, 0x4d, 0x39, 0xb7, 0x28, 0xfb, 0xda,
+	0xdf, 0xe6, 0x42, 0xa7, 0x02, 0xe4, 0xc8, 0xf7, 0x86, 0x94, 0xed, 0xd1, 0x71, 0xd0, 0xea, 0x78,
+	0xc3, 0xa1, 0xe7, 0xb6, 0xc4, 0x4b, 0x5e, 0xa8, 0x8a, 0xe7, 0x5d, 0xbe, 0x5c, 0xf9, 0xfb, 0x0d,
+	0x28, 0xb0, 0x3d, 0xdf, 0x1b, 0xf7, 0xf7, 0x44, 0x3a, 0xc9, 0xb6, 0x2f, 0x2c, 0xce, 0x2f, 0xe4,
+	0x40, 0xc2, 0x01, 0x7a, 0x8c, 0xeb, 0x98, 0x76, 0x6e, 0x07, 0xe3, 0xa1, 0x7c, 0x3b, 0xb6, 0x73,
+	0x07, 0x53, 0xcb, 0x78, 0x8a, 0x44, 0x60, 0x74, 0x0e, 0x40, 0xe9, 0x90, 0xcb, 0x9d, 0x17, 0x6a,
+	0x42, 0xfb, 0x53, 0xab, 0xb4, 0x2b, 0xa1, 0x42, 0x74, 0xe3, 0x29, 0x52, 0x52, 0x54, 0xdb, 0xbc,
+	0xfa, 0x5f, 0x0e, 0x97, 0x78, 0xbd, 0x5e, 0x40, 0x59, 0xbd, 0x20, 0x96, 0x29, 0xde, 0x55, 0x85,
+	0xbc, 0x26, 0x70, 0x3a, 0xf5, 0x80, 0xba, 0x7d, 0xb6, 0x57, 0x2f, 0xea, 0x27, 0x09, 0xa9, 0x77,
+	0x04, 0x0e, 0xff, 0x24, 0x03, 0x96, 0xd6, 0x01, 0xb8, 0xe4, 0xf9, 0xaf, 0x53, 0xe6, 0x3b, 0x9d,
+	0xab, 0xf6, 0x90, 0x86, 0x0e, 0x6e, 0x41, 0x79, 0x28, 0x80, 0xef, 0x6a, 0xf7, 0x18, 0x86, 0x11,
+	0x1d, 0x3a, 0x05, 0x20, 0x2e, 0xbe, 0xc4, 0xcb, 0x2b, 0x5d, 0x12, 0x10, 0x81, 0xde, 0x4c, 0x18,
+	0xae, 0xb5, 0xa0, 0xa2, 0x95, 0xc1, 0xb6, 0xd3, 0x06, 0x5b, 0x98, 0x4f, 0x64, 0x25, 0xfd, 0xc2,
+	0xe6, 0x92, 0x17, 0x16, 0xff, 0xc3, 0x80, 0xe6, 0x4e, 0x78, 0xf2, 0x07, 0x54, 0x47, 0x28, 0x6f,
+	0xe6, 0x21, 0xc9, 0x9b, 0xfd, 0x72, 0xf2, 0xe2, 0x26, 0xc0, 0x8e, 0xe3, 0xd2, 0x4b, 0xce, 0x80,
+	0x51, 0x7f, 0xc6, 0x53, 0xec, 0x67, 0xd9, 0x38, 0xae, 0xf1, 0x4b, 0xae, 0xe4, 0xdc, 0xd4, 0x92,
+	0xc9, 0xc3, 0x10, 0x23, 0xf3, 0x10, 0xcd, 0x96, 0x4d, 0xc5, 0x59, 0x17, 0x0a, 0x3d, 0x21, 0x9e,
+	0xac, 0x0b, 0x12, 0xfd, 0xa6, 0x58, 0xf6, 0xf6, 0x77, 0xd5, 0xe6, 0xcf, 0xdf, 0xa7, 0xac, 0x13,
+	0x5d, 0xc0, 0x56, 0x30, 0x71, 0x99, 0xfd, 0xbe, 0xb6, 0x9e, 0x84, 0x9b, 0x20, 0x5b, 0x55, 0x8e,
+	0xb9, 0x99, 0x95, 0xe3, 0xcb, 0x6a, 0x9b, 0x2f, 0xf5, 0x74, 0x7e, 0x19, 0x56, 0x13, 0x46, 0x51,
+	0x61, 0xfc, 0x71, 0x30, 0x7d, 0xda, 0x93, 0x21, 0x7c, 0x76, 0x8c, 0x16, 0x78, 0xfc, 0x27, 0x03,
+	0x56, 0x2e, 0x53, 0x96, 0xac, 0xe4, 0x1e, 0x21, 0x93, 0xe2, 0x57, 0xe1, 0xa8, 0x76, 0x7e, 0x25,
+	0xfd, 0x33, 0xa9, 0xf2, 0xed, 0x58, 0x2c, 0xff, 0xb6, 0xdb, 0xa5, 0xef, 0xab, 0x57, 0x71, 0xb2,
+	0x72, 0xbb, 0x0e, 0x65, 0x0d, 0x89, 0x2e, 0xa6, 0x6a, 0xb6, 0xd5, 0x54, 0x5b, 0x96, 0xd7, 0x1d,
+	0xed, 0x9a, 0x92, 0x49, 0xbe, 0x7d, 0x55, 0x45, 0x1e, 0xd5, 0x37, 0xbb, 0x80, 0x84, 0xb9, 0x04,
+	0x5b, 0x3d, 0xc3, 0x0a, 0xe8, 0x6b, 0x51, 0xf1, 0x16, 0xcd, 0xd1, 0x63, 0x60, 0xfa, 0xde, 0xdd,
+	0x30, 0xb5, 0x56, 0xe3, 0x2d, 0x89, 0x77, 0x97, 0x08, 0x14, 0x7e, 0x09, 0xb2, 0xc4, 0xbb, 0xcb,
+	0xfb, 0x9e, 0xbe, 0xed, 0xf6, 0xe9, 0xcd, 0xe8, 0x19, 0x58, 0x21, 0x1a, 0x64, 0x4e, 0xf5, 0xb3,
+	0x09, 0x47, 0xf5, 0x13, 0x49, 0x73, 0x6f, 0x40, 0xe1, 0x8d, 0xb1, 0xae, 0xae, 0x5a, 0x4a, 0x5d,
+	0x62, 0x09, 0x09, 0x89, 0xb8, 0xcf, 0x40, 0x0c, 0x47, 0x27, 0xa1, 0xc4, 0xec, 0x5b, 0x03, 0x7a,
+	0x35, 0x0e, 0x73, 0x31, 0x80, 0x63, 0xf9, 0x0b, 0xf6, 0xa6, 0x56, 0xc6, 0xc5, 0x00, 0xf4, 0x24,
+	0xac, 0xc4, 0x67, 0xbe, 0xee, 0xd3, 0x9e, 0xf3, 0xbe, 0xb0, 0x70, 0x85, 0x1c, 0x82, 0xa3, 0xd3,
+	0x70, 0x24, 0x86, 0xed, 0x8a, 0x72, 0xc9, 0x14, 0xa4, 0x69, 0x30, 0xd7, 0x8d, 0x10, 0xf7, 0x95,
+	0x3b, 0x63, 0x7b, 0x20, 0x2e, 0x5f, 0x85, 0x68, 0x10, 0xfc, 0x67, 0x03, 0x8e, 0x4a, 0x53, 0x33,
+	0x9b, 0x3d, 0x92, 0x5e, 0xff, 0x1b, 0x03, 0x90, 0x2e, 0x81, 0x72, 0xad, 0x6f, 0xea, 0xdd, 0x2c,
+	0x9e, 0xfb, 0xcb, 0xe2, 0x61, 0x2e, 0x41, 0x71, 0x43, 0x0a, 0x43, 0x5e, 0xd4, 0x74, 0xb2, 0x43,
+	0x60, 0xca, 0x97, 0xbf, 0x84, 0x10, 0xf5, 0xcb, 0x1b, 0x16, 0xb7, 0x26, 0x8c, 0x06, 0xea, 0xdd,
+	0x2e, 0x1a, 0x16, 0x02, 0x40, 0xe4, 0x0f, 0xdf, 0x8b, 0xba, 0x4c, 0x78, 0x8d, 0x19, 0xef, 0xa5,
+	0x40, 0x24, 0x1c, 0xe0, 0xdf, 0x65, 0xa0, 0x7a, 0xd3, 0x1b, 0x8c, 0x87, 0xf4, 0x11, 0xd4, 0x73,
+	0xb2, 0x99, 0x90, 0x0b, 0x9b, 0x09, 0x08, 0xcc, 0x80, 0xd1, 0x91, 0xf0, 0xac, 0x2c, 0x11, 0x63,
+	0x84, 0xa1, 0xc2, 0x6c, 0xbf, 0x4f, 0x99, 0x7c, 0xa2, 0xd5, 0xf3, 0xa2, 0x76, 0x4e, 0xc0, 0xf8,
+	0x97, 0x01, 0xbb, 0xdf, 0xf7, 0x69, 0xdf, 0x66, 0xb4, 0x3d, 0x11, 0xe5, 0x59, 0x89, 0xe8, 0x20,
+	0xfc, 0x36, 0x2c, 0x87, 0xca, 0x52, 0x26, 0x7d, 0x1a, 0x0a, 0xef, 0x09, 0xc8, 0x8c, 0xe6, 0x9e,
+	0x24, 0x55, 0x61, 0x2c, 0x24, 0x4b, 0x7e, 0x2c, 0x08, 0xcf, 0x8c, 0xaf, 0x40, 0x5e, 0x92, 0xf3,
+	0x4e, 0x53, 0x5c, 0x91, 0xc8, 0x4e, 0x13, 0x9f, 0xab, 0x57, 0x13, 0x86, 0xbc, 0x64, 0x54, 0xcf,
+	0xc6, 0xbe, 0x21, 0x21, 0x44, 0xfd, 0xe2, 0xff, 0x18, 0x70, 0x6c, 0x8b, 0x32, 0xda, 0x61, 0xb4,
+	0x7b, 0xc9, 0xa1, 0x83, 0xee, 0xd7, 0xda, 0x03, 0x88, 0x3a, 0x79, 0x59, 0xad, 0x93, 0xc7, 0xe3,
+	0xce, 0xc0, 0x71, 0xe9, 0x4e, 0x64, 0xbd, 0x2a, 0x89, 0x01, 0x3c, 0x42, 0xf4, 0xf8, 0xc1, 0x25,
+	0x5a, 0x7e, 0xbf, 0xd1, 0x20, 0x91, 0x85, 0xf3, 0xb1, 0x85, 0xf1, 0x36, 0xac, 0xa5, 0x85, 0x56,
+	0x36, 0x6a, 0x41, 0x5e, 0xac, 0x9d, 0xd1, 0x43, 0x4e, 0xac, 0x20, 0x8a, 0x0c, 0xfb, 0x50, 0x4d,
+	0x20, 0x84, 0xcd, 0xb8, 0x8f, 0xa8, 0xf8, 0x29, 0x27, 0xe8, 0x5b, 0x60, 0xb2, 0xc9, 0x48, 0x85,
+	0xcd, 0xf6, 0xb1, 0x2f, 0xa6, 0xd6, 0xd1, 0xc4, 0xb2, 0x1b, 0x93, 0x11, 0x25, 0x82, 0x84, 0xbb,
+	0x56, 0xc7, 0xf6, 0xbb, 0x8e, 0x6b, 0x0f, 0x1c, 0x26, 0x55, 0x61, 0x12, 0x1d, 0x84, 0x7f, 0xa5,
+	0x19, 0x4d, 0xfa, 0xe3, 0x03, 0x1a, 0xcd, 0x78, 0x60, 0xa3, 0x19, 0xf7, 0x31, 0x1a, 0xfe, 0x01,
+	0xac, 0xa5, 0x8f, 0xa8, 0x54, 0xcc, 0xfb, 0x55, 0x09, 0xcc, 0x7c, 0x55, 0x0b, 0x3c, 0x49, 0x91,
+	0xe3, 0xcb, 0xb1, 0xca, 0x05, 0x64, 0x8e, 0xca, 0x53, 0x7a, 0xcc, 0x1c, 0xd2, 0xe3, 0x93, 0x8f,
+	0x43, 0x29, 0xfa, 0xcc, 0x85, 0xca, 0x50, 0xb8, 0x74, 0x8d, 0xbc, 0x75, 0x91, 0x6c, 0xad, 0x2c,
+	0xa1, 0x0a, 0x14, 0xdb, 0x17, 0x37, 0x5f, 0x13, 0x33, 0xe3, 0xfc, 0x87, 0xf9, 0x30, 0xad, 0xfa,
+	0xe8, 0x3b, 0x90, 0x93, 0xb9, 0x72, 0x2d, 0x3e, 0xae, 0xfe, 0x35, 0xa9, 0x71, 0xfc, 0x10, 0x5c,
+	0xca, 0x8d, 0x97, 0x9e, 0x36, 0xd0, 0x55, 0x28, 0x0b, 0xa0, 0xea, 0xfd, 0x9e, 0x4c, 0xb7, 0x60,
+	0x13, 0x9c, 0x4e, 0xcd, 0xc1, 0x6a, 0xfc, 0x2e, 0x40, 0x4e, 0xaa, 0x60, 0x2d, 0x55, 0xd2, 0xcc,
+	0x38, 0x4d, 0xa2, 0x1b, 0x8e, 0x97, 0xd0, 0x8b, 0x60, 0xf2, 0x56, 0x08, 0xd2, 0x2a, 0x2a, 0xad,
+	0x65, 0xdb, 0x58, 0x4b, 0x83, 0xb5, 0x6d, 0x5f, 0x8e, 0x3a, 0xcf, 0xc7, 0xd3, 0xed, 0xaf, 0x70,
+	0x79, 0xfd, 0x30, 0x22, 0xda, 0xf9, 0x1a, 0x54, 0xf4, 0x26, 0x0c, 0x3a, 0x95, 0xdc, 0x2a, 0xd5,
+	0xb3, 0x69, 0x34, 0xe7, 0xa1, 0x23, 0x86, 0x3b, 0x50, 0xd6, 0x1a, 0x20, 0xba, 0x5a, 0x0f, 0x77,
+	0x6f, 0x1a, 0xa7, 0xe6, 0x60, 0x23, 0x6e, 0x97, 0xa1, 0xc8, 0xeb, 0x50, 0xf1, 0xa1, 0xe4, 0x44,
+	0xba, 0xdc, 0xd4, 0xca, 0x8c, 0xc6, 0xc9, 0xd9, 0xc8, 0x88, 0xd1, 0xf7, 0xa1, 0x74, 0x99, 0x32,
+	0x15, 0xab, 0x8f, 0xa7, 0x83, 0xfd, 0x0c, 0x4d, 0x25, 0x13, 0x06, 0x5e, 0x42, 0x6f, 0x8b, 0x92,
+	0x38, 0x19, 0xab, 0x90, 0x35, 0x27, 0x26, 0x45, 0xe7, 0x5a, 0x9f, 0x4f, 0x10, 0x71, 0x7e, 0x2b,
+	0xc1, 0x59, 0x65, 0x35, 0x6b, 0xce, 0x15, 0x8c, 0x38, 0x5b, 0xf7, 0xf9, 0x87, 0x06, 0xbc, 0x74,
+	0xfe, 0x9d, 0xf0, 0x9b, 0xfe, 0x96, 0xcd, 0x6c, 0x74, 0x0d, 0x96, 0x85, 0x2e, 0xa3, 0x8f, 0xfe,
+	0x09, 0x9f, 0x3f, 0xf4, 0x1f, 0x06, 0x8d, 0x53, 0x73, 0xb0, 0x21, 0xfb, 0xf6, 0x3b, 0x1f, 0xdf,
+	0x6b, 0x2e, 0x7d, 0x72, 0xaf, 0xb9, 0xf4, 0xf9, 0xbd, 0xa6, 0xf1, 0xe3, 0xfd, 0xa6, 0xf1, 0xdb,
+	0xfd, 0xa6, 0xf1, 0xd1, 0x7e, 0xd3, 0xf8, 0x78, 0xbf, 0x69, 0xfc, 0x6b, 0xbf, 0x69, 0xfc, 0x7b,
+	0xbf, 0xb9, 0xf4, 0xf9, 0x7e, 0xd3, 0xf8, 0xe0, 0xb3, 0xe6, 0xd2, 0xc7, 0x9f, 0x35, 0x97, 0x3e,
+	0xf9, 0xac, 0xb9, 0xf4, 0xc3, 0x27, 0xee, 0xff, 0xfc, 0x93, 0x81, 0x2e, 0x2f, 0x7e, 0x9e, 0xf9,
+	0xef, 0x00, 0x42, 0x11, 0xb3, 0x78, 0x79, 0x22, 0x00, 0x00,
 }
 
 func (x Direction) String() string {
@@ -3328,6 +3375,9 @@ func (this *StreamRate) Equal(that interface{}) bool {
 	if this.Pushes != that1.Pushes {
 		return false
 	}
+	if this.RateLimited != that1.RateLimited {
+		return false
+	}
 	return true
 }
 func (this *QueryRequest) Equal(that interface{}) bool {
@@ -4155,6 +4205,14 @@ func (this *GetChunkIDsResponse) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if len(this.SegmentRefs) != len(that1.SegmentRefs) {
+		return false
+	}
+	for i := range this.SegmentRefs {
+		if !this.SegmentRefs[i].Equal(that1.SegmentRefs[i]) {
+			return false
+		}
+	}
 	return true
 }
 func (this *ChunkRef) Equal(that interface{}) bool {
@@ -4191,6 +4249,15 @@ func (this *ChunkRef) Equal(that interface{}) bool {
 	if this.Checksum != that1.Checksum {
 		return false
 	}
+	if this.SegmentID != that1.SegmentID {
+		return false
+	}
+	if this.SegmentOffset != that1.SegmentOffset {
+		return false
+	}
+	if this.SegmentLength != that1.SegmentLength {
+		return false
+	}
 	return true
 }
 func (this *LabelValuesForMetricNameRequest) Equal(that interface{}) bool {
@@ -4983,13 +5050,14 @@ func (this *StreamRate) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 9)
+	s := make([]string, 0, 10)
 	s = append(s, "&logproto.StreamRate{")
 	s = append(s, "StreamHash: "+fmt.Sprintf("%#v", this.StreamHash)+",\n")
 	s = append(s, "StreamHashNoShard: "+fmt.Sprintf("%#v", this.StreamHashNoShard)+",\n")
 	s = append(s, "Rate: "+fmt.Sprintf("%#v", this.Rate)+",\n")
 	s = append(s, "Tenant: "+fmt.Sprintf("%#v", this.Tenant)+",\n")
 	s = append(s, "Pushes: "+fmt.Sprintf("%#v", this.Pushes)+",\n")
+	s = append(s, "RateLimited: "+fmt.Sprintf("%#v", this.RateLimited)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -5302,9 +5370,12 @@ func (this *GetChunkIDsResponse) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 5)
+	s := make([]string, 0, 6)
 	s = append(s, "&logproto.GetChunkIDsResponse{")
 	s = append(s, "ChunkIDs: "+fmt.Sprintf("%#v", this.ChunkIDs)+",\n")
+	if this.SegmentRefs != nil {
+		s = append(s, "SegmentRefs: "+fmt.Sprintf("%#v", this.SegmentRefs)+",\n")
+	}
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -5312,13 +5383,16 @@ func (this *ChunkRef) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 9)
+	s := make([]string, 0, 12)
 	s = append(s, "&logproto.ChunkRef{")
 	s = append(s, "Fingerprint: "+fmt.Sprintf("%#v", this.Fingerprint)+",\n")
 	s = append(s, "UserID: "+fmt.Sprintf("%#v", this.UserID)+",\n")
 	s = append(s, "From: "+fmt.Sprintf("%#v", this.From)+",\n")
 	s = append(s, "Through: "+fmt.Sprintf("%#v", this.Through)+",\n")
 	s = append(s, "Checksum: "+fmt.Sprintf("%#v", this.Checksum)+",\n")
+	s = append(s, "SegmentID: "+fmt.Sprintf("%#v", this.SegmentID)+",\n")
+	s = append(s, "SegmentOffset: "+fmt.Sprintf("%#v", this.SegmentOffset)+",\n")
+	s = append(s, "SegmentLength: "+fmt.Sprintf("%#v", this.SegmentLength)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -6387,6 +6461,11 @@ func (m *StreamRate) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.RateLimited != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.RateLimited))
+		i--
+		dAtA[i] = 0x30
+	}
 	if m.Pushes != 0 {
 		i = encodeVarintLogproto(dAtA, i, uint64(m.Pushes))
 		i--
@@ -7536,6 +7615,20 @@ func (m *GetChunkIDsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.SegmentRefs) > 0 {
+		for iNdEx := len(m.SegmentRefs) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.SegmentRefs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
 	if len(m.ChunkIDs) > 0 {
 		for iNdEx := len(m.ChunkIDs) - 1; iNdEx >= 0; iNdEx-- {
 			i -= len(m.ChunkIDs[iNdEx])
@@ -7568,6 +7661,21 @@ func (m *ChunkRef) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.SegmentLength != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.SegmentLength))
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.SegmentOffset != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.SegmentOffset))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.SegmentID != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.SegmentID))
+		i--
+		dAtA[i] = 0x30
+	}
 	if m.Checksum != 0 {
 		i = encodeVarintLogproto(dAtA, i, uint64(m.Checksum))
 		i--
@@ -8695,6 +8803,9 @@ func (m *StreamRate) Size() (n int) {
 	if m.Pushes != 0 {
 		n += 1 + sovLogproto(uint64(m.Pushes))
 	}
+	if m.RateLimited != 0 {
+		n += 1 + sovLogproto(uint64(m.RateLimited))
+	}
 	return n
 }
 
@@ -9176,6 +9287,12 @@ func (m *GetChunkIDsResponse) Size() (n int) {
 			n += 1 + l + sovLogproto(uint64(l))
 		}
 	}
+	if len(m.SegmentRefs) > 0 {
+		for _, e := range m.SegmentRefs {
+			l = e.Size()
+			n += 1 + l + sovLogproto(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -9201,6 +9318,15 @@ func (m *ChunkRef) Size() (n int) {
 	if m.Checksum != 0 {
 		n += 1 + sovLogproto(uint64(m.Checksum))
 	}
+	if m.SegmentID != 0 {
+		n += 1 + sovLogproto(uint64(m.SegmentID))
+	}
+	if m.SegmentOffset != 0 {
+		n += 1 + sovLogproto(uint64(m.SegmentOffset))
+	}
+	if m.SegmentLength != 0 {
+		n += 1 + sovLogproto(uint64(m.SegmentLength))
+	}
 	return n
 }
 
@@ -9726,6 +9852,7 @@ func (this *StreamRate) String() string {
 		`Rate:` + fmt.Sprintf("%v", this.Rate) + `,`,
 		`Tenant:` + fmt.Sprintf("%v", this.Tenant) + `,`,
 		`Pushes:` + fmt.Sprintf("%v", this.Pushes) + `,`,
+		`RateLimited:` + fmt.Sprintf("%v", this.RateLimited) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -10045,8 +10172,14 @@ func (this *GetChunkIDsResponse) String() string {
 	if this == nil {
 		return "nil"
 	}
+	repeatedStringForSegmentRefs := "[]*ChunkRef{"
+	for _, f := range this.SegmentRefs {
+		repeatedStringForSegmentRefs += strings.Replace(f.String(), "ChunkRef", "ChunkRef", 1) + ","
+	}
+	repeatedStringForSegmentRefs += "}"
 	s := strings.Join([]string{`&GetChunkIDsResponse{`,
 		`ChunkIDs:` + fmt.Sprintf("%v", this.ChunkIDs) + `,`,
+		`SegmentRefs:` + repeatedStringForSegmentRefs + `,`,
 		`}`,
 	}, "")
 	return s
@@ -10061,6 +10194,9 @@ func (this *ChunkRef) String() string {
 		`From:` + fmt.Sprintf("%v", this.From) + `,`,
 		`Through:` + fmt.Sprintf("%v", this.Through) + `,`,
 		`Checksum:` + fmt.Sprintf("%v", this.Checksum) + `,`,
+		`SegmentID:` + fmt.Sprintf("%v", this.SegmentID) + `,`,
+		`SegmentOffset:` + fmt.Sprintf("%v", this.SegmentOffset) + `,`,
+		`SegmentLength:` + fmt.Sprintf("%v", this.SegmentLength) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -10923,6 +11059,25 @@ func (m *StreamRate) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RateLimited", wireType)
+			}
+			m.RateLimited = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RateLimited |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipLogproto(dAtA[iNdEx:])
@@ -14224,6 +14379,40 @@ func (m *GetChunkIDsResponse) Unmarshal(dAtA []byte) error {
 			}
 			m.ChunkIDs = append(m.ChunkIDs, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SegmentRefs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SegmentRefs = append(m.SegmentRefs, &ChunkRef{})
+			if err := m.SegmentRefs[len(m.SegmentRefs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipLogproto(dAtA[iNdEx:])
@@ -14385,6 +14574,63 @@ func (m *ChunkRef) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SegmentID", wireType)
+			}
+			m.SegmentID = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SegmentID |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SegmentOffset", wireType)
+			}
+			m.SegmentOffset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SegmentOffset |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SegmentLength", wireType)
+			}
+			m.SegmentLength = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SegmentLength |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipLogproto(dAtA[iNdEx:])
@@ -203,6 +203,8 @@ func RecordRangeAndInstantQueryMetrics(
 		"index_total_chunks", stats.Index.TotalChunks,
 		"index_post_bloom_filter_chunks", stats.Index.PostFilterChunks,
 		"index_bloom_filter_ratio", fmt.Sprintf("%.2f", bloomRatio),
+		"index_total_filters", stats.Index.TotalFilters,
+		"index_bloom_filters", stats.Index.BloomFilters,
 	}...)
 
 	logValues = append(logValues, tagsToKeyValues(queryTags)...)
@@ -427,6 +429,8 @@ func RecordShardsQueryMetrics(
 		"index_total_chunks", stats.Index.TotalChunks,
 		"index_post_bloom_filter_chunks", stats.Index.PostFilterChunks,
 		"index_bloom_filter_ratio", fmt.Sprintf("%.2f", bloomRatio),
+		"index_total_filters", stats.Index.TotalFilters,
+		"index_bloom_filters", stats.Index.BloomFilters,
 	)
 
 	level.Info(logger).Log(logValues...)
@@ -247,6 +247,9 @@ func (i *Ingester) Merge(m Ingester) {
 func (i *Index) Merge(m Index) {
 	i.TotalChunks += m.TotalChunks
 	i.PostFilterChunks += m.PostFilterChunks
+	// All the index requests of a query share its filters.
+	i.TotalFilters = max(i.TotalFilters, m.TotalFilters)
+	i.BloomFilters = max(i.BloomFilters, m.BloomFilters)
 }
 
 func (c *Caches) Merge(m Caches) {
@@ -332,6 +332,26 @@ func TestIngester(t *testing.T) {
 	}, statsCtx.Ingester())
 }
 
+func TestIndex(t *testing.T) {
+	statsCtx, ctx := NewContext(context.Background())
+	JoinIndex(ctx, Index{TotalChunks: 10, PostFilterChunks: 4, TotalFilters: 2, BloomFilters: 1})
+	JoinIndex(ctx, Index{TotalChunks: 5, PostFilterChunks: 5, TotalFilters: 2, BloomFilters: 1})
+	require.Equal(t, Index{
+		TotalChunks:      15,
+		PostFilterChunks: 9,
+		TotalFilters:     2,
+		BloomFilters:     1,
+	}, statsCtx.Index())
+
+	// the filters survive the encoding
+	res := statsCtx.Result(0, 0, 0)
+	b, err := res.Marshal()
+	require.NoError(t, err)
+	var decoded Result
+	require.NoError(t, decoded.Unmarshal(b))
+	require.Equal(t, res.Index, decoded.Index)
+}
+
 func TestCaches(t *testing.T) {
 	statsCtx, _ := NewContext(context.Background())
 
@@ -358,6 +358,11 @@ type Index struct {
 	TotalChunks int64 `protobuf:"varint,1,opt,name=totalChunks,proto3" json:"totalChunks"`
 	// Post-filtered chunks
 	PostFilterChunks int64 `protobuf:"varint,2,opt,name=postFilterChunks,proto3" json:"postFilterChunks"`
+	// Line and label filters of the query, only reported for queries sharded by
+	// index gateways using the bloom gateway
+	TotalFilters int64 `protobuf:"varint,3,opt,name=totalFilters,proto3" json:"totalFilters"`
+	// Filters which can be tested against bloom filters
+	BloomFilters int64 `protobuf:"varint,4,opt,name=bloomFilters,proto3" json:"bloomFilters"`
 }
 
 func (m *Index) Reset()      { *m = Index{} }
@@ -406,6 +411,20 @@ func (m *Index) GetPostFilterChunks() int64 {
 	return 0
 }
 
+func (m *Index) GetTotalFilters() int64 {
+	if m != nil {
+		return m.TotalFilters
+	}
+	return 0
+}
+
+func (m *Index) GetBloomFilters() int64 {
+	if m != nil {
+		return m.BloomFilters
+	}
+	return 0
+}
+
 type Querier struct {
 	Store Store `protobuf:"bytes,1,opt,name=store,proto3" json:"store"`
 }
@@ -864,92 +883,94 @@ func init() {
 func init() { proto.RegisterFile("pkg/logqlmodel/stats/stats.proto", fileDescriptor_6cdfe5d2aea33ebb) }
 
 var fileDescriptor_6cdfe5d2aea33ebb = []byte{
-	// 1358 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x57, 0xcd, 0x6f, 0xdc, 0x44,
-	0x14, 0xdf, 0xcd, 0xd6, 0x9b, 0x74, 0xf2, 0xd5, 0x4e, 0x52, 0xba, 0xa5, 0x95, 0x1d, 0x16, 0x2a,
-	0x8a, 0x90, 0xb2, 0x2a, 0x45, 0x42, 0x20, 0x2a, 0x21, 0xa7, 0x44, 0xaa, 0x94, 0x8a, 0xf2, 0x16,
-	0x04, 0x82, 0x93, 0x63, 0xbf, 0xec, 0x5a, 0xf5, 0xda, 0x8e, 0x3d, 0x0e, 0x8d, 0x84, 0x04, 0x7f,
-	0x02, 0x77, 0xee, 0x88, 0x0b, 0x27, 0x4e, 0x9c, 0xb9, 0xf4, 0xd8, 0x63, 0x4f, 0x16, 0xdd, 0x5c,
-	0x90, 0x4f, 0xfd, 0x03, 0x38, 0xa0, 0xf9, 0x58, 0x7f, 0xad, 0x37, 0xcd, 0x65, 0x3d, 0xef, 0xf7,
-	0xde, 0xef, 0xcd, 0xcc, 0x9b, 0x79, 0xef, 0xcd, 0x92, 0x9d, 0xf0, 0xc9, 0x68, 0xe0, 0x05, 0xa3,
-	0x63, 0x6f, 0x12, 0x38, 0xe8, 0x0d, 0x62, 0x66, 0xb1, 0x58, 0xfe, 0xee, 0x86, 0x51, 0xc0, 0x02,
-	0xaa, 0x09, 0xe1, 0xcd, 0xed, 0x51, 0x30, 0x0a, 0x04, 0x32, 0xe0, 0x23, 0xa9, 0xec, 0xff, 0xb6,
-	0x44, 0xba, 0x80, 0x71, 0xe2, 0x31, 0xfa, 0x31, 0x59, 0x8e, 0x93, 0xc9, 0xc4, 0x8a, 0x4e, 0x7b,
-	0xed, 0x9d, 0xf6, 0x9d, 0xd5, 0x0f, 0x36, 0x76, 0xa5, 0x9b, 0xa1, 0x44, 0xcd, 0xcd, 0x67, 0xa9,
-	0xd1, 0xca, 0x52, 0x63, 0x66, 0x06, 0xb3, 0x01, 0xa7, 0x1e, 0x27, 0x18, 0xb9, 0x18, 0xf5, 0x96,
-	0x2a, 0xd4, 0x2f, 0x25, 0x5a, 0x50, 0x95, 0x19, 0xcc, 0x06, 0xf4, 0x3e, 0x59, 0x71, 0xfd, 0x11,
-	0xc6, 0x0c, 0xa3, 0x5e, 0x47, 0x70, 0x37, 0x15, 0xf7, 0xa1, 0x82, 0xcd, 0x2b, 0x8a, 0x9c, 0x1b,
-	0x42, 0x3e, 0xa2, 0x1f, 0x92, 0xae, 0x6d, 0xd9, 0x63, 0x8c, 0x7b, 0x97, 0x04, 0x79, 0x5d, 0x91,
-	0xf7, 0x04, 0x68, 0xae, 0x2b, 0xaa, 0x26, 0x8c, 0x40, 0xd9, 0xd2, 0xbb, 0x44, 0x73, 0x7d, 0x07,
-	0x9f, 0xf6, 0x34, 0x41, 0x5a, 0xcb, 0x67, 0x74, 0xf0, 0x69, 0xc1, 0x11, 0x26, 0x20, 0x3f, 0xfd,
-	0x5f, 0x2f, 0x91, 0xee, 0x5e, 0xce, 0xb6, 0xc7, 0x89, 0xff, 0x44, 0x85, 0x69, 0xad, 0x3c, 0x65,
-	0x69, 0x46, 0x6e, 0x02, 0xf2, 0x53, 0x4c, 0xb8, 0x74, 0x1e, 0xa5, 0x3c, 0x21, 0xdf, 0x59, 0x24,
-	0x0e, 0x46, 0x85, 0xa5, 0xca, 0xd9, 0x50, 0x1c, 0x65, 0x03, 0xea, 0x4b, 0xf7, 0xc8, 0xaa, 0x30,
-	0x93, 0x67, 0xaa, 0x82, 0x52, 0xa5, 0x6e, 0x29, 0x6a, 0xd9, 0x10, 0xca, 0x02, 0xdd, 0x27, 0x6b,
-	0x27, 0x81, 0x97, 0x4c, 0x50, 0x79, 0xd1, 0x1a, 0xbc, 0x6c, 0x2b, 0x2f, 0x15, 0x4b, 0xa8, 0x48,
-	0xdc, 0x4f, 0xcc, 0x4f, 0x79, 0xb6, 0x9a, 0xee, 0x79, 0x7e, 0xca, 0x96, 0x50, 0x91, 0xf8, 0xa6,
-	0x3c, 0xeb, 0x10, 0x3d, 0xe5, 0x66, 0xf9, 0xbc, 0x4d, 0x95, 0x0c, 0xa1, 0x2c, 0xd0, 0xef, 0xc9,
-	0x96, 0xeb, 0xc7, 0xcc, 0xf2, 0xd9, 0x23, 0x64, 0x91, 0x6b, 0x2b, 0x67, 0x2b, 0x0d, 0xce, 0x6e,
-	0x2a, 0x67, 0x4d, 0x04, 0x68, 0x02, 0xfb, 0x7f, 0x75, 0xc9, 0xb2, 0x4a, 0x13, 0xfa, 0x35, 0xb9,
-	0x7e, 0x78, 0xca, 0x30, 0x7e, 0x1c, 0x05, 0x36, 0xc6, 0x31, 0x3a, 0x8f, 0x31, 0x1a, 0xa2, 0x1d,
-	0xf8, 0x8e, 0xb8, 0x30, 0x1d, 0xf3, 0x66, 0x96, 0x1a, 0x8b, 0x4c, 0x60, 0x91, 0x82, 0xbb, 0xf5,
-	0x5c, 0xbf, 0xd1, 0xed, 0x52, 0xe1, 0x76, 0x81, 0x09, 0x2c, 0x52, 0xd0, 0x87, 0x64, 0x8b, 0x05,
-	0xcc, 0xf2, 0xcc, 0xca, 0xb4, 0xe2, 0xce, 0x75, 0xcc, 0xeb, 0x3c, 0x08, 0x0d, 0x6a, 0x68, 0x02,
-	0x73, 0x57, 0x07, 0x95, 0xa9, 0xc4, 0x1d, 0x2c, 0xbb, 0xaa, 0xaa, 0xa1, 0x09, 0xa4, 0x77, 0xc8,
-	0x0a, 0x3e, 0x45, 0xfb, 0x2b, 0x77, 0x82, 0xe2, 0xf6, 0xb5, 0xcd, 0x35, 0x5e, 0x00, 0x66, 0x18,
-	0xe4, 0x23, 0xfa, 0x3e, 0xb9, 0x7c, 0x9c, 0x60, 0x82, 0xc2, 0xb4, 0x2b, 0x4c, 0xd7, 0xb3, 0xd4,
-	0x28, 0x40, 0x28, 0x86, 0x74, 0x97, 0x90, 0x38, 0x39, 0x94, 0xa5, 0x27, 0x16, 0xf7, 0xa8, 0x63,
-	0x6e, 0x64, 0xa9, 0x51, 0x42, 0xa1, 0x34, 0xa6, 0x07, 0x64, 0x5b, 0xac, 0xee, 0x73, 0x9f, 0xc9,
-	0xeb, 0xc8, 0x92, 0xc8, 0x47, 0x47, 0x5c, 0x9a, 0x8e, 0xd9, 0xcb, 0x52, 0xa3, 0x51, 0x0f, 0x8d,
-	0x28, 0xed, 0x93, 0x6e, 0x1c, 0x7a, 0x2e, 0x8b, 0x7b, 0x97, 0x05, 0x9f, 0xf0, 0xfc, 0x95, 0x08,
-	0xa8, 0xaf, 0xb0, 0x19, 0x5b, 0x91, 0x13, 0xf7, 0x48, 0xc9, 0x46, 0x20, 0xa0, 0xbe, 0xf9, 0xaa,
-	0x1e, 0x07, 0x31, 0xdb, 0x77, 0x3d, 0x86, 0x91, 0x88, 0x5e, 0x6f, 0xb5, 0xb6, 0xaa, 0x9a, 0x1e,
-	0x1a, 0x51, 0xfa, 0x13, 0xb9, 0x2d, 0xf0, 0x21, 0x8b, 0x12, 0x9b, 0x25, 0x11, 0x3a, 0x8f, 0x90,
-	0x59, 0x8e, 0xc5, 0xac, 0xda, 0x95, 0x58, 0x13, 0xee, 0xdf, 0xcb, 0x52, 0xe3, 0x62, 0x04, 0xb8,
-	0x98, 0x59, 0xff, 0x47, 0xa2, 0x89, 0xc2, 0x4b, 0xef, 0x92, 0x55, 0xc1, 0xd8, 0xe3, 0x25, 0x33,
-	0x56, 0xc9, 0xb2, 0xc9, 0x93, 0xba, 0x04, 0x43, 0x59, 0xa0, 0x9f, 0x91, 0x2b, 0x61, 0xbe, 0x1f,
-	0xc5, 0x93, 0xd9, 0xb0, 0x9d, 0xa5, 0xc6, 0x9c, 0x0e, 0xe6, 0x90, 0xfe, 0xa7, 0x64, 0x59, 0x35,
-	0x29, 0x5e, 0xa4, 0x63, 0x16, 0x44, 0x58, 0xab, 0xeb, 0x43, 0x8e, 0x15, 0x45, 0x5a, 0x98, 0x80,
-	0xfc, 0xf4, 0xff, 0x58, 0x22, 0x2b, 0x0f, 0x8b, 0x5e, 0xb4, 0x26, 0xd6, 0x06, 0xc8, 0xab, 0x88,
-	0xcc, 0x76, 0xcd, 0xbc, 0xc2, 0x8b, 0x5b, 0x19, 0x87, 0x8a, 0x44, 0xf7, 0x09, 0x2d, 0xed, 0xe8,
-	0x91, 0xc5, 0x04, 0x57, 0x6e, 0xe2, 0x8d, 0x2c, 0x35, 0x1a, 0xb4, 0xd0, 0x80, 0xe5, 0xb3, 0x9b,
-	0x42, 0x8e, 0x55, 0x06, 0x17, 0xb3, 0x2b, 0x1c, 0x2a, 0x12, 0xfd, 0x84, 0x6c, 0x14, 0xf9, 0x37,
-	0x44, 0x9f, 0xa9, 0x74, 0xa5, 0x59, 0x6a, 0xd4, 0x34, 0x50, 0x93, 0x8b, 0x78, 0x69, 0x17, 0x8e,
-	0xd7, 0x7f, 0x97, 0x88, 0x26, 0xf4, 0xf9, 0xc4, 0xea, 0x60, 0xf0, 0x48, 0x9d, 0x77, 0x31, 0x71,
-	0xae, 0x81, 0x9a, 0x4c, 0xbf, 0x20, 0xd7, 0x4a, 0xc8, 0x83, 0xe0, 0x07, 0xdf, 0x0b, 0x2c, 0x27,
-	0x8f, 0xda, 0x8d, 0x2c, 0x35, 0x9a, 0x0d, 0xa0, 0x19, 0xe6, 0x67, 0x60, 0x57, 0x30, 0x51, 0x4d,
-	0x3a, 0xc5, 0x19, 0xcc, 0x6b, 0xa1, 0x01, 0xa3, 0x36, 0xb9, 0xc1, 0x4b, 0xc7, 0x29, 0xe0, 0x11,
-	0x46, 0xe8, 0xdb, 0xe8, 0x14, 0xb7, 0xbf, 0xb7, 0xbe, 0xd3, 0xbe, 0xb3, 0x62, 0xde, 0xce, 0x52,
-	0xe3, 0xad, 0x85, 0x46, 0xb3, 0x14, 0x81, 0xc5, 0x7e, 0x8a, 0xe7, 0x47, 0xad, 0xb9, 0x73, 0x6c,
-	0xc1, 0xf3, 0x63, 0xb6, 0x3f, 0xc0, 0xa3, 0x78, 0x1f, 0x99, 0x3d, 0xce, 0x0b, 0x6b, 0x79, 0x7f,
-	0x15, 0x2d, 0x34, 0x60, 0xf4, 0x5b, 0xd2, 0xb3, 0x03, 0x71, 0xdd, 0xdd, 0xc0, 0xdf, 0x0b, 0x7c,
-	0x16, 0x05, 0xde, 0x81, 0xc5, 0xd0, 0xb7, 0x4f, 0x45, 0xed, 0xed, 0x98, 0xb7, 0xb2, 0xd4, 0x58,
-	0x68, 0x03, 0x0b, 0x35, 0xd4, 0x21, 0xb7, 0x42, 0x37, 0x44, 0xde, 0xa5, 0xbe, 0x89, 0xac, 0x30,
-	0xc4, 0x48, 0x66, 0x29, 0x3a, 0xb2, 0xb6, 0xc9, 0x5a, 0xbd, 0x93, 0xa5, 0xc6, 0xb9, 0x76, 0x70,
-	0xae, 0xb6, 0xff, 0xa7, 0x46, 0x34, 0x11, 0x27, 0x7e, 0xfd, 0xc6, 0x68, 0x39, 0x32, 0x68, 0xbc,
-	0x1e, 0x95, 0xef, 0x7d, 0x55, 0x03, 0x35, 0xb9, 0xc2, 0x95, 0xab, 0xd3, 0x1a, 0xb8, 0x72, 0x3d,
-	0x35, 0x99, 0xee, 0x91, 0xab, 0x0e, 0xda, 0xc1, 0x24, 0x8c, 0x44, 0xf1, 0x93, 0x53, 0xcb, 0xd0,
-	0x5d, 0xcb, 0x52, 0x63, 0x5e, 0x09, 0xf3, 0x50, 0xdd, 0x49, 0x39, 0x42, 0x73, 0x4e, 0xe4, 0x32,
-	0xe6, 0x21, 0x7a, 0x9f, 0x6c, 0xd6, 0xd7, 0x21, 0xdb, 0xda, 0x56, 0x96, 0x1a, 0x75, 0x15, 0xd4,
-	0x01, 0x4e, 0x17, 0xb9, 0xf4, 0x20, 0x09, 0x3d, 0xd7, 0xb6, 0x38, 0xfd, 0x72, 0x41, 0xaf, 0xa9,
-	0xa0, 0x0e, 0x70, 0x7a, 0x58, 0x6b, 0x5f, 0xa4, 0xa0, 0xd7, 0x54, 0x50, 0x07, 0x68, 0x48, 0x76,
-	0xf2, 0xc0, 0x2e, 0x68, 0x30, 0xaa, 0x1d, 0xbe, 0x93, 0xa5, 0xc6, 0x6b, 0x6d, 0xe1, 0xb5, 0x16,
-	0xf4, 0x94, 0xbc, 0x5d, 0x8e, 0xe1, 0xa2, 0x49, 0x65, 0x93, 0x7c, 0x37, 0x4b, 0x8d, 0x8b, 0x98,
-	0xc3, 0x45, 0x8c, 0xfa, 0x7f, 0x77, 0x88, 0x26, 0x1e, 0xa6, 0xbc, 0xc6, 0xa3, 0x7c, 0x54, 0xec,
-	0x07, 0x89, 0x5f, 0xe9, 0x30, 0x65, 0x1c, 0x2a, 0x12, 0x6f, 0x92, 0x38, 0x7b, 0x8a, 0x1c, 0x27,
-	0xbc, 0x57, 0xc9, 0x4a, 0xa9, 0xc9, 0x26, 0x59, 0xd7, 0xc1, 0x1c, 0x42, 0x3f, 0x22, 0xeb, 0x0a,
-	0x13, 0xc5, 0x5b, 0x3e, 0x0f, 0x35, 0xf3, 0x6a, 0x96, 0x1a, 0x55, 0x05, 0x54, 0x45, 0x4e, 0x14,
-	0xef, 0x59, 0x40, 0x1b, 0xdd, 0x93, 0xfc, 0x31, 0x28, 0x88, 0x15, 0x05, 0x54, 0x45, 0xfe, 0xac,
-	0x13, 0x80, 0x68, 0x49, 0x32, 0xbd, 0xc4, 0xb3, 0x2e, 0x07, 0xa1, 0x18, 0xf2, 0xd7, 0x62, 0x24,
-	0xd7, 0x2a, 0x73, 0x49, 0x93, 0xaf, 0xc5, 0x19, 0x06, 0xf9, 0x88, 0x07, 0xd0, 0x29, 0x97, 0xf8,
-	0xe5, 0xa2, 0x49, 0x96, 0x71, 0xa8, 0x48, 0x3c, 0xdf, 0x44, 0x39, 0x3e, 0x40, 0x7f, 0xc4, 0xc6,
-	0x43, 0x8c, 0x4e, 0xf2, 0x37, 0xa0, 0xc8, 0xb7, 0x39, 0x25, 0xcc, 0x43, 0x26, 0x3e, 0x7f, 0xa9,
-	0xb7, 0x5e, 0xbc, 0xd4, 0x5b, 0xaf, 0x5e, 0xea, 0xed, 0x9f, 0xa7, 0x7a, 0xfb, 0xf7, 0xa9, 0xde,
-	0x7e, 0x36, 0xd5, 0xdb, 0xcf, 0xa7, 0x7a, 0xfb, 0x9f, 0xa9, 0xde, 0xfe, 0x77, 0xaa, 0xb7, 0x5e,
-	0x4d, 0xf5, 0xf6, 0x2f, 0x67, 0x7a, 0xeb, 0xf9, 0x99, 0xde, 0x7a, 0x71, 0xa6, 0xb7, 0xbe, 0x1b,
-	0x8c, 0x5c, 0x36, 0x4e, 0x0e, 0x77, 0xed, 0x60, 0x32, 0x18, 0x45, 0xd6, 0x91, 0xe5, 0x5b, 0x03,
-	0x2f, 0x78, 0xe2, 0x0e, 0x4e, 0xee, 0x0d, 0x9a, 0xfe, 0xf9, 0x1f, 0x76, 0xc5, 0xff, 0xfa, 0x7b,
-	0xff, 0x07, 0x00, 0x00, 0xff, 0xff, 0x19, 0x80, 0x75, 0xde, 0x18, 0x10, 0x00, 0x00,
+	// 1380 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x58, 0xcf, 0x6f, 0xdc, 0xc4,
+	0x17, 0x8f, 0xb3, 0xf1, 0x26, 0x9d, 0xfc, 0x6a, 0x27, 0xe9, 0xb7, 0xee, 0xb7, 0x95, 0x1d, 0x16,
+	0x2a, 0x8a, 0x90, 0xb2, 0x2a, 0xad, 0x84, 0x40, 0x54, 0x42, 0x4e, 0x89, 0x54, 0x29, 0x15, 0xe5,
+	0x05, 0x04, 0x82, 0x93, 0x63, 0xbf, 0xec, 0x5a, 0xf5, 0xda, 0x1b, 0x7b, 0x1c, 0x9a, 0x13, 0xfc,
+	0x09, 0xdc, 0xb9, 0x23, 0x2e, 0x9c, 0x38, 0x71, 0xe6, 0xd2, 0x63, 0x8f, 0x3d, 0x59, 0x74, 0x7b,
+	0xa9, 0x7c, 0xea, 0x1f, 0xc0, 0x01, 0xcd, 0x8f, 0xf5, 0xaf, 0xf5, 0xa6, 0xb9, 0xac, 0xe7, 0x7d,
+	0xde, 0xe7, 0xf3, 0x66, 0xfc, 0x3c, 0xf3, 0xde, 0x68, 0xc9, 0xce, 0xf8, 0xc9, 0xa0, 0x1f, 0x44,
+	0x83, 0x93, 0x60, 0x14, 0x79, 0x18, 0xf4, 0x13, 0xe6, 0xb0, 0x44, 0xfe, 0xee, 0x8e, 0xe3, 0x88,
+	0x45, 0x54, 0x17, 0xc6, 0xff, 0xb7, 0x07, 0xd1, 0x20, 0x12, 0x48, 0x9f, 0x8f, 0xa4, 0xb3, 0xf7,
+	0xdb, 0x22, 0xe9, 0x02, 0x26, 0x69, 0xc0, 0xe8, 0x27, 0x64, 0x39, 0x49, 0x47, 0x23, 0x27, 0x3e,
+	0x33, 0xb4, 0x1d, 0xed, 0xf6, 0xea, 0x47, 0x1b, 0xbb, 0x32, 0xcc, 0xa1, 0x44, 0xed, 0xcd, 0x67,
+	0x99, 0xb5, 0x90, 0x67, 0xd6, 0x94, 0x06, 0xd3, 0x01, 0x97, 0x9e, 0xa4, 0x18, 0xfb, 0x18, 0x1b,
+	0x8b, 0x35, 0xe9, 0x57, 0x12, 0x2d, 0xa5, 0x8a, 0x06, 0xd3, 0x01, 0xbd, 0x4f, 0x56, 0xfc, 0x70,
+	0x80, 0x09, 0xc3, 0xd8, 0xe8, 0x08, 0xed, 0xa6, 0xd2, 0x3e, 0x54, 0xb0, 0x7d, 0x59, 0x89, 0x0b,
+	0x22, 0x14, 0x23, 0x7a, 0x8f, 0x74, 0x5d, 0xc7, 0x1d, 0x62, 0x62, 0x2c, 0x09, 0xf1, 0xba, 0x12,
+	0xef, 0x09, 0xd0, 0x5e, 0x57, 0x52, 0x5d, 0x90, 0x40, 0x71, 0xe9, 0x1d, 0xa2, 0xfb, 0xa1, 0x87,
+	0x4f, 0x0d, 0x5d, 0x88, 0xd6, 0x8a, 0x19, 0x3d, 0x7c, 0x5a, 0x6a, 0x04, 0x05, 0xe4, 0xa3, 0xf7,
+	0xeb, 0x12, 0xe9, 0xee, 0x15, 0x6a, 0x77, 0x98, 0x86, 0x4f, 0x0c, 0xad, 0xa6, 0x16, 0xde, 0xca,
+	0x8c, 0x9c, 0x02, 0xf2, 0x51, 0x4e, 0xb8, 0x78, 0x9e, 0xa4, 0x3a, 0x21, 0x7f, 0xb3, 0x58, 0x7c,
+	0x18, 0xa3, 0xd3, 0xa2, 0xd9, 0x50, 0x1a, 0xc5, 0x01, 0xf5, 0xa4, 0x7b, 0x64, 0x55, 0xd0, 0xe4,
+	0x37, 0x35, 0x96, 0x5a, 0xa4, 0x5b, 0x4a, 0x5a, 0x25, 0x42, 0xd5, 0xa0, 0xfb, 0x64, 0xed, 0x34,
+	0x0a, 0xd2, 0x11, 0xaa, 0x28, 0x7a, 0x4b, 0x94, 0x6d, 0x15, 0xa5, 0xc6, 0x84, 0x9a, 0xc5, 0xe3,
+	0x24, 0xfc, 0x2b, 0x4f, 0x57, 0xd3, 0x3d, 0x2f, 0x4e, 0x95, 0x09, 0x35, 0x8b, 0xbf, 0x54, 0xe0,
+	0x1c, 0x61, 0xa0, 0xc2, 0x2c, 0x9f, 0xf7, 0x52, 0x15, 0x22, 0x54, 0x0d, 0xfa, 0x03, 0xd9, 0xf2,
+	0xc3, 0x84, 0x39, 0x21, 0x7b, 0x84, 0x2c, 0xf6, 0x5d, 0x15, 0x6c, 0xa5, 0x25, 0xd8, 0x0d, 0x15,
+	0xac, 0x4d, 0x00, 0x6d, 0x60, 0xef, 0xaf, 0x2e, 0x59, 0x56, 0xc7, 0x84, 0x7e, 0x43, 0xae, 0x1d,
+	0x9d, 0x31, 0x4c, 0x1e, 0xc7, 0x91, 0x8b, 0x49, 0x82, 0xde, 0x63, 0x8c, 0x0f, 0xd1, 0x8d, 0x42,
+	0x4f, 0x6c, 0x98, 0x8e, 0x7d, 0x23, 0xcf, 0xac, 0x79, 0x14, 0x98, 0xe7, 0xe0, 0x61, 0x03, 0x3f,
+	0x6c, 0x0d, 0xbb, 0x58, 0x86, 0x9d, 0x43, 0x81, 0x79, 0x0e, 0xfa, 0x90, 0x6c, 0xb1, 0x88, 0x39,
+	0x81, 0x5d, 0x9b, 0x56, 0xec, 0xb9, 0x8e, 0x7d, 0x8d, 0x27, 0xa1, 0xc5, 0x0d, 0x6d, 0x60, 0x11,
+	0xea, 0xa0, 0x36, 0x95, 0xb1, 0xd4, 0x08, 0x55, 0x77, 0x43, 0x1b, 0x48, 0x6f, 0x93, 0x15, 0x7c,
+	0x8a, 0xee, 0xd7, 0xfe, 0x08, 0xc5, 0xee, 0xd3, 0xec, 0x35, 0x5e, 0x00, 0xa6, 0x18, 0x14, 0x23,
+	0xfa, 0x21, 0xb9, 0x74, 0x92, 0x62, 0x8a, 0x82, 0xda, 0x15, 0xd4, 0xf5, 0x3c, 0xb3, 0x4a, 0x10,
+	0xca, 0x21, 0xdd, 0x25, 0x24, 0x49, 0x8f, 0x64, 0xe9, 0x49, 0xc4, 0x3e, 0xea, 0xd8, 0x1b, 0x79,
+	0x66, 0x55, 0x50, 0xa8, 0x8c, 0xe9, 0x01, 0xd9, 0x16, 0xab, 0xfb, 0x22, 0x64, 0xc2, 0x87, 0x2c,
+	0x8d, 0x43, 0xf4, 0xc4, 0xa6, 0xe9, 0xd8, 0x46, 0x9e, 0x59, 0xad, 0x7e, 0x68, 0x45, 0x69, 0x8f,
+	0x74, 0x93, 0x71, 0xe0, 0xb3, 0xc4, 0xb8, 0x24, 0xf4, 0x84, 0x9f, 0x5f, 0x89, 0x80, 0x7a, 0x0a,
+	0xce, 0xd0, 0x89, 0xbd, 0xc4, 0x20, 0x15, 0x8e, 0x40, 0x40, 0x3d, 0x8b, 0x55, 0x3d, 0x8e, 0x12,
+	0xb6, 0xef, 0x07, 0x0c, 0x63, 0x91, 0x3d, 0x63, 0xb5, 0xb1, 0xaa, 0x86, 0x1f, 0x5a, 0x51, 0xfa,
+	0x13, 0xb9, 0x25, 0xf0, 0x43, 0x16, 0xa7, 0x2e, 0x4b, 0x63, 0xf4, 0x1e, 0x21, 0x73, 0x3c, 0x87,
+	0x39, 0x8d, 0x2d, 0xb1, 0x26, 0xc2, 0x7f, 0x90, 0x67, 0xd6, 0xc5, 0x04, 0x70, 0x31, 0x5a, 0xef,
+	0xb5, 0x46, 0x74, 0x51, 0x79, 0xe9, 0x1d, 0xb2, 0x2a, 0x24, 0x7b, 0xbc, 0x66, 0x26, 0xea, 0xb4,
+	0x6c, 0xf2, 0x53, 0x5d, 0x81, 0xa1, 0x6a, 0xd0, 0xcf, 0xc9, 0xe5, 0x71, 0xf1, 0x42, 0x4a, 0x27,
+	0x8f, 0xc3, 0x76, 0x9e, 0x59, 0x33, 0x3e, 0x98, 0x41, 0xe8, 0x3d, 0xb2, 0x26, 0x02, 0x4a, 0x30,
+	0x51, 0x3b, 0xff, 0x32, 0x2f, 0x49, 0x55, 0x1c, 0x6a, 0x16, 0x57, 0x1d, 0x05, 0x51, 0x34, 0x9a,
+	0xaa, 0x96, 0x4a, 0x55, 0x15, 0x87, 0x9a, 0xd5, 0xfb, 0x8c, 0x2c, 0xab, 0x8e, 0xc8, 0x3b, 0x42,
+	0xc2, 0xa2, 0x18, 0x1b, 0x4d, 0xe4, 0x90, 0x63, 0x65, 0x47, 0x10, 0x14, 0x90, 0x8f, 0xde, 0x1f,
+	0x8b, 0x64, 0xe5, 0x61, 0xd9, 0xf8, 0xe4, 0x82, 0x00, 0x79, 0xc9, 0x92, 0xa5, 0x45, 0xaf, 0x2c,
+	0x5b, 0xe1, 0x50, 0xb3, 0xe8, 0x3e, 0xa1, 0x95, 0xec, 0x3d, 0x72, 0x98, 0xd0, 0xca, 0x84, 0xfd,
+	0x2f, 0xcf, 0xac, 0x16, 0x2f, 0xb4, 0x60, 0xc5, 0xec, 0xb6, 0xb0, 0x67, 0x93, 0xa6, 0x70, 0xa8,
+	0x59, 0xf4, 0x53, 0xb2, 0x51, 0x1e, 0xf6, 0x43, 0x0c, 0x99, 0x4a, 0x1b, 0xcd, 0x33, 0xab, 0xe1,
+	0x81, 0x86, 0x5d, 0xe6, 0x4b, 0xbf, 0x70, 0xbe, 0xfe, 0x5d, 0x22, 0xba, 0xf0, 0x17, 0x13, 0xab,
+	0x4d, 0x80, 0xc7, 0x86, 0xd6, 0x98, 0xb8, 0xf0, 0x40, 0xc3, 0xa6, 0x5f, 0x92, 0xab, 0x15, 0xe4,
+	0x41, 0xf4, 0x63, 0x18, 0x44, 0x8e, 0x57, 0x64, 0xed, 0x7a, 0x9e, 0x59, 0xed, 0x04, 0x68, 0x87,
+	0xf9, 0x37, 0x70, 0x6b, 0x98, 0x28, 0x5d, 0x9d, 0xf2, 0x1b, 0xcc, 0x7a, 0xa1, 0x05, 0xa3, 0x2e,
+	0xb9, 0xce, 0xeb, 0xd4, 0x19, 0xe0, 0x31, 0xc6, 0x18, 0xba, 0xe8, 0x95, 0x47, 0xcd, 0x58, 0xdf,
+	0xd1, 0x6e, 0xaf, 0xd8, 0xb7, 0xf2, 0xcc, 0x7a, 0x67, 0x2e, 0x69, 0x7a, 0x1e, 0x61, 0x7e, 0x9c,
+	0xf2, 0xae, 0xd3, 0xb8, 0x49, 0x70, 0x6c, 0xce, 0x5d, 0x67, 0xfa, 0x7e, 0x80, 0xc7, 0xc9, 0x3e,
+	0x32, 0x77, 0x58, 0x54, 0xf1, 0xea, 0xfb, 0xd5, 0xbc, 0xd0, 0x82, 0xd1, 0xef, 0x88, 0xe1, 0x46,
+	0x62, 0xbb, 0xfb, 0x51, 0xb8, 0x17, 0x85, 0x2c, 0x8e, 0x82, 0x03, 0x87, 0x61, 0xe8, 0x9e, 0x89,
+	0x42, 0xdf, 0xb1, 0x6f, 0xe6, 0x99, 0x35, 0x97, 0x03, 0x73, 0x3d, 0xd4, 0x23, 0x37, 0xc7, 0xfe,
+	0x18, 0x79, 0x4b, 0xfc, 0x36, 0x76, 0xc6, 0x63, 0x8c, 0xe5, 0x01, 0x45, 0x4f, 0x16, 0x52, 0xd9,
+	0x18, 0x76, 0xf2, 0xcc, 0x3a, 0x97, 0x07, 0xe7, 0x7a, 0x7b, 0x7f, 0xea, 0x44, 0x17, 0x79, 0xe2,
+	0xdb, 0x6f, 0x88, 0x8e, 0x27, 0x93, 0xc6, 0x8b, 0x5f, 0x75, 0xdf, 0xd7, 0x3d, 0xd0, 0xb0, 0x6b,
+	0x5a, 0xb9, 0x3a, 0xbd, 0x45, 0x2b, 0xd7, 0xd3, 0xb0, 0xe9, 0x1e, 0xb9, 0xe2, 0xa1, 0x1b, 0x8d,
+	0xc6, 0xb1, 0xa8, 0xb4, 0x72, 0x6a, 0x99, 0xba, 0xab, 0x79, 0x66, 0xcd, 0x3a, 0x61, 0x16, 0x6a,
+	0x06, 0xa9, 0x66, 0x68, 0x26, 0x88, 0x5c, 0xc6, 0x2c, 0x44, 0xef, 0x93, 0xcd, 0xe6, 0x3a, 0x64,
+	0x0f, 0xdd, 0xca, 0x33, 0xab, 0xe9, 0x82, 0x26, 0xc0, 0xe5, 0xe2, 0x2c, 0x3d, 0x48, 0xc7, 0x81,
+	0xef, 0x3a, 0x0c, 0xa7, 0x2d, 0x54, 0xc8, 0x1b, 0x2e, 0x68, 0x02, 0x5c, 0x3e, 0x6e, 0xf4, 0x4a,
+	0x52, 0xca, 0x1b, 0x2e, 0x68, 0x02, 0x74, 0x4c, 0x76, 0x8a, 0xc4, 0xce, 0xe9, 0x66, 0xaa, 0xf7,
+	0xbe, 0x97, 0x67, 0xd6, 0x5b, 0xb9, 0xf0, 0x56, 0x06, 0x3d, 0x23, 0xef, 0x56, 0x73, 0x38, 0x6f,
+	0x52, 0xd9, 0x91, 0xdf, 0xcf, 0x33, 0xeb, 0x22, 0x74, 0xb8, 0x08, 0xa9, 0xf7, 0x77, 0x87, 0xe8,
+	0xe2, 0x16, 0xcc, 0x6b, 0x3c, 0xca, 0x1b, 0xcc, 0x7e, 0x94, 0x86, 0xb5, 0x0e, 0x53, 0xc5, 0xa1,
+	0x66, 0xf1, 0x86, 0x8c, 0xd3, 0x7b, 0xcf, 0x49, 0x8a, 0x09, 0x53, 0x95, 0x52, 0x97, 0x0d, 0xb9,
+	0xe9, 0x83, 0x19, 0x84, 0x7e, 0x4c, 0xd6, 0x15, 0x26, 0x8a, 0xb7, 0xbc, 0x8b, 0xea, 0xf6, 0x95,
+	0x3c, 0xb3, 0xea, 0x0e, 0xa8, 0x9b, 0x5c, 0x28, 0x2e, 0xcf, 0x80, 0x2e, 0xfa, 0xa7, 0xc5, 0xcd,
+	0x53, 0x08, 0x6b, 0x0e, 0xa8, 0x9b, 0xfc, 0x0e, 0x29, 0x00, 0xd1, 0x92, 0xe4, 0xf1, 0x12, 0x77,
+	0xc8, 0x02, 0x84, 0x72, 0xc8, 0xaf, 0xa6, 0xb1, 0x5c, 0xab, 0x3c, 0x4b, 0xba, 0xbc, 0x9a, 0x4e,
+	0x31, 0x28, 0x46, 0x3c, 0x81, 0x5e, 0xb5, 0xc4, 0x2f, 0x97, 0x4d, 0xb2, 0x8a, 0x43, 0xcd, 0xe2,
+	0xe7, 0x4d, 0x94, 0xe3, 0x03, 0x0c, 0x07, 0x6c, 0x78, 0x88, 0xf1, 0x69, 0x71, 0xe1, 0x14, 0xe7,
+	0x6d, 0xc6, 0x09, 0xb3, 0x90, 0x8d, 0xcf, 0x5f, 0x9a, 0x0b, 0x2f, 0x5e, 0x9a, 0x0b, 0x6f, 0x5e,
+	0x9a, 0xda, 0xcf, 0x13, 0x53, 0xfb, 0x7d, 0x62, 0x6a, 0xcf, 0x26, 0xa6, 0xf6, 0x7c, 0x62, 0x6a,
+	0xff, 0x4c, 0x4c, 0xed, 0xf5, 0xc4, 0x5c, 0x78, 0x33, 0x31, 0xb5, 0x5f, 0x5e, 0x99, 0x0b, 0xcf,
+	0x5f, 0x99, 0x0b, 0x2f, 0x5e, 0x99, 0x0b, 0xdf, 0xf7, 0x07, 0x3e, 0x1b, 0xa6, 0x47, 0xbb, 0x6e,
+	0x34, 0xea, 0x0f, 0x62, 0xe7, 0xd8, 0x09, 0x9d, 0x7e, 0x10, 0x3d, 0xf1, 0xfb, 0xa7, 0x77, 0xfb,
+	0x6d, 0x7f, 0x33, 0x1c, 0x75, 0xc5, 0x9f, 0x08, 0x77, 0xff, 0x1b, 0x00, 0xfe, 0xda, 0xba, 0x1d,
+	0x85, 0x10, 0x00, 0x00,
 }
 
 func (this *Result) Equal(that interface{}) bool {
@@ -1115,6 +1136,12 @@ func (this *Index) Equal(that interface{}) bool {
 	if this.PostFilterChunks != that1.PostFilterChunks {
 		return false
 	}
+	if this.TotalFilters != that1.TotalFilters {
+		return false
+	}
+	if this.BloomFilters != that1.BloomFilters {
+		return false
+	}
 	return true
 }
 func (this *Querier) Equal(that interface{}) bool {
@@ -1371,10 +1398,12 @@ func (this *Index) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 6)
+	s := make([]string, 0, 8)
 	s = append(s, "&stats.Index{")
 	s = append(s, "TotalChunks: "+fmt.Sprintf("%#v", this.TotalChunks)+",\n")
 	s = append(s, "PostFilterChunks: "+fmt.Sprintf("%#v", this.PostFilterChunks)+",\n")
+	s = append(s, "TotalFilters: "+fmt.Sprintf("%#v", this.TotalFilters)+",\n")
+	s = append(s, "BloomFilters: "+fmt.Sprintf("%#v", this.BloomFilters)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -1743,6 +1772,16 @@ func (m *Index) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.BloomFilters != 0 {
+		i = encodeVarintStats(dAtA, i, uint64(m.BloomFilters))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.TotalFilters != 0 {
+		i = encodeVarintStats(dAtA, i, uint64(m.TotalFilters))
+		i--
+		dAtA[i] = 0x18
+	}
 	if m.PostFilterChunks != 0 {
 		i = encodeVarintStats(dAtA, i, uint64(m.PostFilterChunks))
 		i--
@@ -2158,6 +2197,12 @@ func (m *Index) Size() (n int) {
 	if m.PostFilterChunks != 0 {
 		n += 1 + sovStats(uint64(m.PostFilterChunks))
 	}
+	if m.TotalFilters != 0 {
+		n += 1 + sovStats(uint64(m.TotalFilters))
+	}
+	if m.BloomFilters != 0 {
+		n += 1 + sovStats(uint64(m.BloomFilters))
+	}
 	return n
 }
 
@@ -2361,6 +2406,8 @@ func (this *Index) String() string {
 	s := strings.Join([]string{`&Index{`,
 		`TotalChunks:` + fmt.Sprintf("%v", this.TotalChunks) + `,`,
 		`PostFilterChunks:` + fmt.Sprintf("%v", this.PostFilterChunks) + `,`,
+		`TotalFilters:` + fmt.Sprintf("%v", this.TotalFilters) + `,`,
+		`BloomFilters:` + fmt.Sprintf("%v", this.BloomFilters) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -3316,6 +3363,44 @@ func (m *Index) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalFilters", wireType)
+			}
+			m.TotalFilters = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TotalFilters |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BloomFilters", wireType)
+			}
+			m.BloomFilters = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BloomFilters |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStats(dAtA[iNdEx:])
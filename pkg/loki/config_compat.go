@@ -14,6 +14,7 @@ func ValidateConfigCompatibility(c Config) []error {
 	for _, fn := range []func(Config) error{
 		ensureInvertedIndexShardingCompatibility,
 		ensureProtobufEncodingForAggregationSharding,
+		ensureSegmentFlushWithoutRetention,
 	} {
 		if err := fn(c); err != nil {
 			errs = append(errs, err)
@@ -51,3 +52,12 @@ func ensureProtobufEncodingForAggregationSharding(c Config) error {
 	}
 	return nil
 }
+
+// ensureSegmentFlushWithoutRetention refuses segment flushing along with retention, which also processes delete
+// requests: the compactor can't delete segments, as they may hold chunks which aren't deleted.
+func ensureSegmentFlushWithoutRetention(c Config) error {
+	if c.Ingester.SegmentFlush.Enabled && c.CompactorConfig.RetentionEnabled {
+		return errors.New("ingester.segment_flush can't be enabled along with compactor.retention_enabled, segments are never deleted by retention or delete requests")
+	}
+	return nil
+}
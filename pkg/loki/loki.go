@@ -346,6 +346,7 @@ type Loki struct {
 	MemberlistKV              *memberlist.KVInitService
 	compactor                 *compactor.Compactor
 	QueryFrontEndMiddleware   queryrangebase.Middleware
+	runningQueries            *queryrange.RunningQueries
 	queryScheduler            *scheduler.Scheduler
 	querySchedulerRingManager *lokiring.RingManager
 	usageReport               *analytics.Reporter
@@ -684,7 +685,7 @@ func (t *Loki) setupModuleManager() error {
 		TableManager:             {Server, Analytics},
 		Compactor:                {Server, Overrides, MemberlistKV, Analytics},
 		IndexGateway:             {Server, Store, IndexGatewayRing, IndexGatewayInterceptors, Analytics},
-		BloomGateway:             {Server, BloomStore, Analytics},
+		BloomGateway:             {Server, BloomStore, Overrides, Analytics},
 		BloomCompactor:           {Server, BloomStore, BloomCompactorRing, Analytics, Store},
 		PatternIngester:          {Server, MemberlistKV, Analytics},
 		PatternRingClient:        {Server, MemberlistKV, Analytics},
@@ -883,7 +883,7 @@ func (t *Loki) setupAsyncStore() error {
 }
 
 func (t *Loki) initIngesterQuerier() (_ services.Service, err error) {
-	t.ingesterQuerier, err = querier.NewIngesterQuerier(t.Cfg.IngesterClient, t.ring, t.Cfg.Querier.ExtraQueryDelay, t.Cfg.MetricsNamespace)
+	t.ingesterQuerier, err = querier.NewIngesterQuerier(t.Cfg.Querier, t.Cfg.IngesterClient, t.ring, t.Cfg.MetricsNamespace)
 	if err != nil {
 		return nil, err
 	}
@@ -898,6 +898,10 @@ func (disabledShuffleShardingLimits) MaxQueriersPerUser(_ string) uint { return
 
 func (disabledShuffleShardingLimits) MaxQueryCapacity(_ string) float64 { return 0 }
 
+func (disabledShuffleShardingLimits) QuerySchedulerTenantWeight(_ string) float64 { return 1 }
+
+func (disabledShuffleShardingLimits) QuerySchedulerMaxLowPriorityCapacity(_ string) float64 { return 0 }
+
 // ingesterQueryOptions exists simply to avoid dependency cycles when using querier.Config directly in queryrange.NewMiddleware
 type ingesterQueryOptions struct {
 	querier.Config
@@ -1022,7 +1026,11 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 		level.Debug(util_log.Logger).Log("msg", "no query frontend configured")
 	}
 
-	roundTripper := queryrange.NewSerializeRoundTripper(t.QueryFrontEndMiddleware.Wrap(frontendTripper), queryrange.DefaultCodec)
+	t.runningQueries = queryrange.NewRunningQueries()
+	roundTripper := queryrange.NewSerializeRoundTripper(
+		t.runningQueries.Wrap(t.QueryFrontEndMiddleware.Wrap(t.runningQueries.Track(frontendTripper))),
+		queryrange.DefaultCodec,
+	)
 
 	frontendHandler := transport.NewHandler(t.Cfg.Frontend.Handler, roundTripper, util_log.Logger, prometheus.DefaultRegisterer, t.Cfg.MetricsNamespace)
 	if t.Cfg.Frontend.CompressResponses {
@@ -1031,7 +1039,7 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 
 	toMerge := []middleware.Interface{
 		httpreq.ExtractQueryTagsMiddleware(),
-		httpreq.PropagateHeadersMiddleware(httpreq.LokiActorPathHeader, httpreq.LokiEncodingFlagsHeader, httpreq.LokiDisablePipelineWrappersHeader),
+		httpreq.PropagateHeadersMiddleware(httpreq.LokiActorPathHeader, httpreq.LokiEncodingFlagsHeader, httpreq.LokiDisablePipelineWrappersHeader, httpreq.LokiQueryPriorityHeader),
 		serverutil.RecoveryHTTPMiddleware,
 		t.HTTPAuthMiddleware,
 		queryrange.StatsHTTPMiddleware,
@@ -1097,6 +1105,9 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 	t.Server.HTTP.Path("/api/prom/label/{name}/values").Methods("GET", "POST").Handler(frontendHandler)
 	t.Server.HTTP.Path("/api/prom/series").Methods("GET", "POST").Handler(frontendHandler)
 
+	t.Server.HTTP.Path("/loki/api/v1/running_queries").Methods("GET").Handler(t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.runningQueries.ListHandler)))
+	t.Server.HTTP.Path("/loki/api/v1/running_queries/cancel").Methods("POST").Handler(t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.runningQueries.CancelHandler)))
+
 	// Only register tailing requests if this process does not act as a Querier
 	// If this process is also a Querier the Querier will register the tail endpoints.
 	if !t.isModuleActive(Querier) {
@@ -1376,11 +1387,12 @@ func (t *Loki) initBloomGateway() (services.Service, error) {
 	}
 	logger := log.With(util_log.Logger, "component", "bloom-gateway")
 
-	gateway, err := bloomgateway.New(t.Cfg.BloomGateway, t.BloomStore, logger, prometheus.DefaultRegisterer)
+	gateway, err := bloomgateway.New(t.Cfg.BloomGateway, t.BloomStore, t.Overrides, logger, prometheus.DefaultRegisterer)
 	if err != nil {
 		return nil, err
 	}
 	logproto.RegisterBloomGatewayServer(t.Server.GRPC, gateway)
+	t.Server.HTTP.Path("/bloomgateway/explain").Methods("GET", "POST").Handler(t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(gateway.ExplainHandler)))
 	return gateway, nil
 }
 
@@ -1429,7 +1441,7 @@ func (t *Loki) initIndexGateway() (services.Service, error) {
 		if err != nil {
 			return nil, err
 		}
-		bloomQuerier = bloomgateway.NewQuerier(bloomGatewayClient, prometheus.DefaultRegisterer, logger)
+		bloomQuerier = bloomgateway.NewQuerier(bloomGatewayClient, t.Overrides, prometheus.DefaultRegisterer, logger)
 	}
 
 	gateway, err := indexgateway.NewIndexGateway(t.Cfg.IndexGateway, logger, prometheus.DefaultRegisterer, t.Store, indexClients, bloomQuerier)
@@ -1543,6 +1555,9 @@ func (t *Loki) initQueryScheduler() (services.Service, error) {
 	schedulerpb.RegisterSchedulerForFrontendServer(t.Server.GRPC, s)
 	schedulerpb.RegisterSchedulerForQuerierServer(t.Server.GRPC, s)
 
+	t.Server.HTTP.Path("/scheduler/running_queries").Methods("GET").Handler(t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(s.RunningQueriesHandler)))
+	t.Server.HTTP.Path("/scheduler/running_queries/cancel").Methods("POST").Handler(t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(s.CancelRunningQueryHandler)))
+
 	t.queryScheduler = s
 	return s, nil
 }
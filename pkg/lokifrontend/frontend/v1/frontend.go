@@ -46,6 +46,12 @@ type Limits interface {
 
 	// MaxQueryCapacity returns how much of the available query capacity can be used by this user.
 	MaxQueryCapacity(user string) float64
+
+	// QuerySchedulerTenantWeight returns the weight of the user's share of the query cost.
+	QuerySchedulerTenantWeight(user string) float64
+
+	// QuerySchedulerMaxLowPriorityCapacity returns how much of the user's query capacity can be used by low priority requests.
+	QuerySchedulerMaxLowPriorityCapacity(user string) float64
 }
 
 // Frontend queues HTTP requests, dispatches them to backends, and handles retries
@@ -38,26 +38,28 @@ type responseFromIngesters struct {
 
 // IngesterQuerier helps with querying the ingesters.
 type IngesterQuerier struct {
-	ring            ring.ReadRing
-	pool            *ring_client.Pool
-	extraQueryDelay time.Duration
+	ring             ring.ReadRing
+	pool             *ring_client.Pool
+	extraQueryDelay  time.Duration
+	minimizeRequests bool
 }
 
-func NewIngesterQuerier(clientCfg client.Config, ring ring.ReadRing, extraQueryDelay time.Duration, metricsNamespace string) (*IngesterQuerier, error) {
+func NewIngesterQuerier(querierConfig Config, clientCfg client.Config, ring ring.ReadRing, metricsNamespace string) (*IngesterQuerier, error) {
 	factory := func(addr string) (ring_client.PoolClient, error) {
 		return client.New(clientCfg, addr)
 	}
 
-	return newIngesterQuerier(clientCfg, ring, extraQueryDelay, ring_client.PoolAddrFunc(factory), metricsNamespace)
+	return newIngesterQuerier(querierConfig, clientCfg, ring, ring_client.PoolAddrFunc(factory), metricsNamespace)
 }
 
 // newIngesterQuerier creates a new IngesterQuerier and allows to pass a custom ingester client factory
 // used for testing purposes
-func newIngesterQuerier(clientCfg client.Config, ring ring.ReadRing, extraQueryDelay time.Duration, clientFactory ring_client.PoolFactory, metricsNamespace string) (*IngesterQuerier, error) {
+func newIngesterQuerier(querierConfig Config, clientCfg client.Config, ring ring.ReadRing, clientFactory ring_client.PoolFactory, metricsNamespace string) (*IngesterQuerier, error) {
 	iq := IngesterQuerier{
-		ring:            ring,
-		pool:            clientpool.NewPool("ingester", clientCfg.PoolConfig, ring, clientFactory, util_log.Logger, metricsNamespace),
-		extraQueryDelay: extraQueryDelay,
+		ring:             ring,
+		pool:             clientpool.NewPool("ingester", clientCfg.PoolConfig, ring, clientFactory, util_log.Logger, metricsNamespace),
+		extraQueryDelay:  querierConfig.ExtraQueryDelay,
+		minimizeRequests: querierConfig.MinimizeIngesterRequests,
 	}
 
 	err := services.StartAndAwaitRunning(context.Background(), iq.pool)
@@ -81,8 +83,17 @@ func (q *IngesterQuerier) forAllIngesters(ctx context.Context, f func(context.Co
 
 // forGivenIngesters runs f, in parallel, for given ingesters
 func (q *IngesterQuerier) forGivenIngesters(ctx context.Context, replicationSet ring.ReplicationSet, f func(context.Context, logproto.QuerierClient) (interface{}, error)) ([]responseFromIngesters, error) {
+	return q.doUntilQuorum(ctx, replicationSet, q.minimizeRequests, f)
+}
+
+// doUntilQuorum runs f, in parallel, for given ingesters until quorum is reached.
+func (q *IngesterQuerier) doUntilQuorum(ctx context.Context, replicationSet ring.ReplicationSet, minimizeRequests bool, f func(context.Context, logproto.QuerierClient) (interface{}, error)) ([]responseFromIngesters, error) {
 	cfg := ring.DoUntilQuorumConfig{
-		// Nothing here
+		// When minimizing requests, only the ingesters needed to reach quorum are queried at first, e.g. the ingesters
+		// of 2 zones out of 3 with zone-awareness. The other ingesters are queried when a request fails, or when the
+		// requests take longer than the extra query delay.
+		MinimizeRequests: minimizeRequests,
+		HedgingDelay:     q.extraQueryDelay,
 	}
 	results, err := ring.DoUntilQuorum(ctx, replicationSet, cfg, func(ctx context.Context, ingester *ring.InstanceDesc) (responseFromIngesters, error) {
 		client, err := q.pool.GetClientFor(ingester.Addr)
@@ -159,7 +170,13 @@ func (q *IngesterQuerier) Label(ctx context.Context, req *logproto.LabelRequest)
 }
 
 func (q *IngesterQuerier) Tail(ctx context.Context, req *logproto.TailRequest) (map[string]logproto.Querier_TailClient, error) {
-	resps, err := q.forAllIngesters(ctx, func(_ context.Context, client logproto.QuerierClient) (interface{}, error) {
+	replicationSet, err := q.ring.GetReplicationSetForOperation(ring.Read)
+	if err != nil {
+		return nil, err
+	}
+
+	// requests are never minimized for tailing, since the tailer reconnects to all the ingesters it isn't connected to.
+	resps, err := q.doUntilQuorum(ctx, replicationSet, false, func(_ context.Context, client logproto.QuerierClient) (interface{}, error) {
 		return client.Tail(ctx, req)
 	})
 	if err != nil {
@@ -278,7 +295,7 @@ func (q *IngesterQuerier) TailersCount(ctx context.Context) ([]uint32, error) {
 	return counts, nil
 }
 
-func (q *IngesterQuerier) GetChunkIDs(ctx context.Context, from, through model.Time, matchers ...*labels.Matcher) ([]string, error) {
+func (q *IngesterQuerier) GetChunkIDs(ctx context.Context, from, through model.Time, matchers ...*labels.Matcher) ([]string, []*logproto.ChunkRef, error) {
 	resps, err := q.forAllIngesters(ctx, func(ctx context.Context, querierClient logproto.QuerierClient) (interface{}, error) {
 		return querierClient.GetChunkIDs(ctx, &logproto.GetChunkIDsRequest{
 			Matchers: convertMatchersToString(matchers),
@@ -287,15 +304,20 @@ func (q *IngesterQuerier) GetChunkIDs(ctx context.Context, from, through model.T
 		})
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	var chunkIDs []string
+	var (
+		chunkIDs    []string
+		segmentRefs []*logproto.ChunkRef
+	)
 	for i := range resps {
-		chunkIDs = append(chunkIDs, resps[i].response.(*logproto.GetChunkIDsResponse).ChunkIDs...)
+		resp := resps[i].response.(*logproto.GetChunkIDsResponse)
+		chunkIDs = append(chunkIDs, resp.ChunkIDs...)
+		segmentRefs = append(segmentRefs, resp.SegmentRefs...)
 	}
 
-	return chunkIDs, nil
+	return chunkIDs, segmentRefs, nil
 }
 
 func (q *IngesterQuerier) Stats(ctx context.Context, _ string, from, through model.Time, matchers ...*labels.Matcher) (*index_stats.Stats, error) {
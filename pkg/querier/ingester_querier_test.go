@@ -3,6 +3,7 @@ package querier
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/grafana/dskit/ring"
+	ring_client "github.com/grafana/dskit/ring/client"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/assert"
@@ -60,7 +62,7 @@ func TestIngesterQuerier_earlyExitOnQuorum(t *testing.T) {
 		"get_chunk_ids": {
 			method: "GetChunkIDs",
 			testFn: func(ingesterQuerier *IngesterQuerier) error {
-				_, err := ingesterQuerier.GetChunkIDs(context.Background(), model.Time(0), model.Time(0))
+				_, _, err := ingesterQuerier.GetChunkIDs(context.Background(), model.Time(0), model.Time(0))
 				return err
 			},
 			retVal: new(logproto.GetChunkIDsResponse),
@@ -105,9 +107,9 @@ func TestIngesterQuerier_earlyExitOnQuorum(t *testing.T) {
 					ingesterClient.On(testData.method, mock.Anything, mock.Anything, mock.Anything).Return(testData.retVal, nil).Run(runFn)
 				}
 				ingesterQuerier, err := newIngesterQuerier(
+					mockQuerierConfig(),
 					mockIngesterClientConfig(),
 					newReadRingMock(ringIngesters, 1),
-					mockQuerierConfig().ExtraQueryDelay,
 					newIngesterClientMockFactory(ingesterClient),
 					constants.Loki,
 				)
@@ -205,9 +207,9 @@ func TestIngesterQuerier_earlyExitOnQuorum(t *testing.T) {
 					ingesterClient.On(testData.method, mock.Anything, mock.Anything, mock.Anything).Return(testData.retVal, nil).Run(runFn)
 				}
 				ingesterQuerier, err := newIngesterQuerier(
+					mockQuerierConfig(),
 					mockIngesterClientConfig(),
 					newReadRingMock(ringIngesters, 1),
-					mockQuerierConfig().ExtraQueryDelay,
 					newIngesterClientMockFactory(ingesterClient),
 					constants.Loki,
 				)
@@ -303,9 +305,9 @@ func TestQuerier_tailDisconnectedIngesters(t *testing.T) {
 			ingesterClient.On("Tail", mock.Anything, &req, mock.Anything).Return(newTailClientMock(), nil)
 
 			ingesterQuerier, err := newIngesterQuerier(
+				mockQuerierConfig(),
 				mockIngesterClientConfig(),
 				newReadRingMock(testData.ringIngesters, 0),
-				mockQuerierConfig().ExtraQueryDelay,
 				newIngesterClientMockFactory(ingesterClient),
 				constants.Loki,
 			)
@@ -366,9 +368,9 @@ func TestIngesterQuerier_Volume(t *testing.T) {
 		ingesterClient.On("GetVolume", mock.Anything, mock.Anything, mock.Anything).Return(ret, nil)
 
 		ingesterQuerier, err := newIngesterQuerier(
+			mockQuerierConfig(),
 			mockIngesterClientConfig(),
 			newReadRingMock([]ring.InstanceDesc{mockInstanceDesc("1.1.1.1", ring.ACTIVE), mockInstanceDesc("3.3.3.3", ring.ACTIVE)}, 0),
-			mockQuerierConfig().ExtraQueryDelay,
 			newIngesterClientMockFactory(ingesterClient),
 			constants.Loki,
 		)
@@ -387,9 +389,9 @@ func TestIngesterQuerier_Volume(t *testing.T) {
 		ingesterClient.On("GetVolume", mock.Anything, mock.Anything, mock.Anything).Return(nil, status.Error(codes.Unimplemented, "something bad"))
 
 		ingesterQuerier, err := newIngesterQuerier(
+			mockQuerierConfig(),
 			mockIngesterClientConfig(),
 			newReadRingMock([]ring.InstanceDesc{mockInstanceDesc("1.1.1.1", ring.ACTIVE), mockInstanceDesc("3.3.3.3", ring.ACTIVE)}, 0),
-			mockQuerierConfig().ExtraQueryDelay,
 			newIngesterClientMockFactory(ingesterClient),
 			constants.Loki,
 		)
@@ -401,3 +403,112 @@ func TestIngesterQuerier_Volume(t *testing.T) {
 		require.Equal(t, []logproto.Volume(nil), volumes.Volumes)
 	})
 }
+
+func TestIngesterQuerier_MinimizeRequests(t *testing.T) {
+	const failingZone = "zone-a"
+
+	for name, tc := range map[string]struct {
+		minimizeRequests bool
+		extraQueryDelay  time.Duration
+		// failZone makes the ingesters of the failing zone return errors.
+		failZone bool
+		// slowZone makes the ingesters of the failing zone block until their request is cancelled.
+		slowZone bool
+
+		expectedCalls []int
+	}{
+		"all ingesters are queried by default": {
+			expectedCalls: []int{6},
+		},
+		"only the ingesters of 2 zones are queried when minimizing requests": {
+			minimizeRequests: true,
+			expectedCalls:    []int{4},
+		},
+		"the ingesters of the last zone are queried when a zone fails": {
+			minimizeRequests: true,
+			failZone:         true,
+			expectedCalls:    []int{4, 6},
+		},
+		"the ingesters of the last zone are queried after the extra query delay when a zone is slow": {
+			minimizeRequests: true,
+			extraQueryDelay:  10 * time.Millisecond,
+			slowZone:         true,
+			expectedCalls:    []int{4, 6},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			var (
+				instances []ring.InstanceDesc
+				clients   = map[string]*querierClientMock{}
+				calls     atomic.Int32
+			)
+			for _, zone := range []string{"zone-a", "zone-b", "zone-c"} {
+				for i := 0; i < 2; i++ {
+					instance := mockInstanceDesc(fmt.Sprintf("%s-%d", zone, i), ring.ACTIVE)
+					instance.Zone = zone
+					instances = append(instances, instance)
+
+					addr := instance.Addr
+					client := newQuerierClientMock()
+					call := client.On("Label", mock.Anything, mock.Anything, mock.Anything)
+					switch {
+					case zone == failingZone && tc.failZone:
+						call.Return(nil, errors.New("failed"))
+					case zone == failingZone && tc.slowZone:
+						call.Return(nil, context.Canceled)
+					default:
+						call.Return(&logproto.LabelResponse{Values: []string{addr}}, nil)
+					}
+					call.Run(func(args mock.Arguments) {
+						calls.Inc()
+						if zone == failingZone && tc.slowZone {
+							<-args.Get(0).(context.Context).Done()
+							return
+						}
+						// give all the requests initiated concurrently the time to be sent before quorum is reached.
+						time.Sleep(10 * time.Millisecond)
+					})
+					clients[addr] = client
+				}
+			}
+
+			querierConfig := mockQuerierConfig()
+			querierConfig.MinimizeIngesterRequests = tc.minimizeRequests
+			querierConfig.ExtraQueryDelay = tc.extraQueryDelay
+
+			ingesterQuerier, err := newIngesterQuerier(
+				querierConfig,
+				mockIngesterClientConfig(),
+				&readRingMock{replicationSet: ring.ReplicationSet{
+					Instances:            instances,
+					ZoneAwarenessEnabled: true,
+					MaxUnavailableZones:  1,
+				}},
+				ring_client.PoolAddrFunc(func(addr string) (ring_client.PoolClient, error) {
+					return clients[addr], nil
+				}),
+				constants.Loki,
+			)
+			require.NoError(t, err)
+
+			values, err := ingesterQuerier.Label(context.Background(), &logproto.LabelRequest{})
+			require.NoError(t, err)
+
+			// results are always returned from the ingesters of 2 zones, excluding the failing zone.
+			require.Len(t, values, 4)
+			if tc.failZone || tc.slowZone {
+				for _, v := range values {
+					require.NotContains(t, v[0], failingZone)
+				}
+			}
+
+			if len(tc.expectedCalls) == 1 {
+				require.Equal(t, int32(tc.expectedCalls[0]), calls.Load())
+			} else {
+				require.GreaterOrEqual(t, calls.Load(), int32(tc.expectedCalls[0]))
+				require.LessOrEqual(t, calls.Load(), int32(tc.expectedCalls[1]))
+			}
+		})
+	}
+}
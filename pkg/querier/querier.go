@@ -72,13 +72,15 @@ type Config struct {
 	QueryIngesterOnly             bool             `yaml:"query_ingester_only"`
 	MultiTenantQueriesEnabled     bool             `yaml:"multi_tenant_queries_enabled"`
 	PerRequestLimitsEnabled       bool             `yaml:"per_request_limits_enabled"`
+	MinimizeIngesterRequests      bool             `yaml:"minimize_ingester_requests"`
 }
 
 // RegisterFlags register flags.
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	cfg.Engine.RegisterFlagsWithPrefix("querier", f)
 	f.DurationVar(&cfg.TailMaxDuration, "querier.tail-max-duration", 1*time.Hour, "Maximum duration for which the live tailing requests are served.")
-	f.DurationVar(&cfg.ExtraQueryDelay, "querier.extra-query-delay", 0, "Time to wait before sending more than the minimum successful query requests.")
+	f.DurationVar(&cfg.ExtraQueryDelay, "querier.extra-query-delay", 0, "Time to wait before sending more than the minimum successful query requests. Only used when ingester requests are minimized.")
+	f.BoolVar(&cfg.MinimizeIngesterRequests, "querier.minimize-ingester-requests", false, "When true, query only the ingesters needed to reach quorum, e.g. the ingesters of 2 zones out of 3 when zone-awareness is enabled with a replication factor of 3, instead of all ingesters. Other ingesters are queried when a request fails, or after the extra query delay if it is set.")
 	f.DurationVar(&cfg.QueryIngestersWithin, "querier.query-ingesters-within", 3*time.Hour, "Maximum lookback beyond which queries are not sent to ingester. 0 means all queries are sent to ingester.")
 	f.IntVar(&cfg.MaxConcurrent, "querier.max-concurrent", 4, "The maximum number of queries that can be simultaneously processed by the querier.")
 	f.BoolVar(&cfg.QueryStoreOnly, "querier.query-store-only", false, "Only query the store, and not attempt any ingesters. This is useful for running a standalone querier pool operating only against stored data.")
@@ -856,6 +858,12 @@ func (q *SingleTenantQuerier) Volume(ctx context.Context, req *logproto.VolumeRe
 	)
 
 	ingesterQueryInterval, storeQueryInterval := q.buildQueryIntervals(req.From.Time(), req.Through.Time())
+	if seriesvolume.IsCardinality(req.AggregateBy) {
+		// Cardinality aggregations are computed from the TSDB index only, since the streams of the ingesters
+		// can't be deduplicated against the series of the index once counted.
+		ingesterQueryInterval = nil
+		storeQueryInterval = &interval{start: req.From.Time(), end: req.Through.Time()}
+	}
 
 	queryIngesters := !q.cfg.QueryStoreOnly && ingesterQueryInterval != nil
 	queryStore := !q.cfg.QueryIngesterOnly && storeQueryInterval != nil
@@ -1039,11 +1047,45 @@ func (q *SingleTenantQuerier) DetectedFields(ctx context.Context, req *logproto.
 		fieldCount++
 	}
 
+	// Add the structured metadata keys recorded in the index, which aren't limited to the sampled lines.
+	for _, key := range q.indexedStructuredMetadataKeys(ctx, req, expr.Matchers()) {
+		if uint32(len(fields)) >= req.FieldLimit {
+			break
+		}
+		if _, ok := detectedFields[key]; ok {
+			continue
+		}
+		fields = append(fields, &logproto.DetectedField{
+			Label: key,
+			Type:  logproto.DetectedFieldString,
+		})
+	}
+
 	return &logproto.DetectedFieldsResponse{
 		Fields: fields,
 	}, nil
 }
 
+// indexedStructuredMetadataKeys returns the structured metadata keys recorded in the index for the stored part
+// of the request. Errors are only logged, e.g. index gateways which don't serve them yet.
+func (q *SingleTenantQuerier) indexedStructuredMetadataKeys(ctx context.Context, req *logproto.DetectedFieldsRequest, matchers []*labels.Matcher) []string {
+	_, storeQueryInterval := q.buildQueryIntervals(req.Start, req.End)
+	if q.cfg.QueryIngesterOnly || storeQueryInterval == nil {
+		return nil
+	}
+
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil
+	}
+	keys, err := q.store.StructuredMetadataKeys(ctx, userID, model.TimeFromUnixNano(storeQueryInterval.start.UnixNano()), model.TimeFromUnixNano(storeQueryInterval.end.UnixNano()), matchers...)
+	if err != nil {
+		level.Warn(spanlogger.FromContext(ctx)).Log("msg", "failed to get structured metadata keys from the index", "err", err)
+		return nil
+	}
+	return keys
+}
+
 type parsedFields struct {
 	sketch         *hyperloglog.Sketch
 	isTypeDetected bool
@@ -347,6 +347,11 @@ func (s *storeMock) LabelNamesForMetricName(ctx context.Context, userID string,
 	return args.Get(0).([]string), args.Error(1)
 }
 
+func (s *storeMock) StructuredMetadataKeys(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) ([]string, error) {
+	args := s.Called(ctx, userID, from, through, matchers)
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func (s *storeMock) GetChunkFetcher(_ model.Time) *fetcher.Fetcher {
 	panic("don't call me please")
 }
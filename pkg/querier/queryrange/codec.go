@@ -779,6 +779,17 @@ func (c Codec) EncodeRequest(ctx context.Context, r queryrangebase.Request) (*ht
 		header.Set(httpreq.LokiDisablePipelineWrappersHeader, disableWrappers)
 	}
 
+	// Add estimated cost and priority class for the scheduler
+	if cost := httpreq.ExtractHeader(ctx, httpreq.LokiQueryCostHeader); cost != "" {
+		header.Set(httpreq.LokiQueryCostHeader, cost)
+	}
+	if priority := httpreq.ExtractHeader(ctx, httpreq.LokiQueryPriorityHeader); priority != "" {
+		header.Set(httpreq.LokiQueryPriorityHeader, priority)
+	}
+	if id := httpreq.ExtractHeader(ctx, httpreq.LokiQueryIDHeader); id != "" {
+		header.Set(httpreq.LokiQueryIDHeader, id)
+	}
+
 	// Add limits
 	if limits := querylimits.ExtractQueryLimitsContext(ctx); limits != nil {
 		err := querylimits.InjectQueryLimitsHeader(&header, limits)
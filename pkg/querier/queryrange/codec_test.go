@@ -1691,7 +1691,9 @@ var (
 		},
 		"index": {
 			"postFilterChunks": 0,
-			"totalChunks": 0
+			"totalChunks": 0,
+			"totalFilters": 0,
+			"bloomFilters": 0
 		},
 		"cache": {
 			"chunk": {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strconv"
 	"time"
 
 	"github.com/go-kit/log/level"
@@ -19,6 +20,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/logqlmodel"
 	"github.com/grafana/loki/v3/pkg/querier/plan"
 	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/v3/pkg/util/httpreq"
 	"github.com/grafana/loki/v3/pkg/util/spanlogger"
 )
 
@@ -138,6 +140,10 @@ func (in instance) Downstream(ctx context.Context, queries []logql.DownstreamQue
 		} else {
 			req = ParamsToLokiRequest(qry.Params).WithQuery(qry.Params.GetExpression().String())
 		}
+		ctx := ctx
+		if cost := queryCost(qry.Params.Shards()); cost > 0 {
+			ctx = httpreq.InjectHeader(ctx, httpreq.LokiQueryCostHeader, strconv.FormatUint(cost, 10))
+		}
 		sp, ctx := opentracing.StartSpanFromContext(ctx, "DownstreamHandler.instance")
 		defer sp.Finish()
 		logger := spanlogger.FromContext(ctx)
@@ -152,6 +158,22 @@ func (in instance) Downstream(ctx context.Context, queries []logql.DownstreamQue
 	})
 }
 
+// queryCost estimates the cost of a sub-query from the bytes of its bounded shards.
+// It returns 0 if the shards carry no index stats, the cost estimated by the sharding middleware is then kept.
+func queryCost(shards []string) uint64 {
+	parsed, _, err := logql.ParseShards(shards)
+	if err != nil {
+		return 0
+	}
+	var bytes uint64
+	for _, shard := range parsed {
+		if shard.Bounded != nil {
+			bytes += shard.Bounded.Stats.GetBytes()
+		}
+	}
+	return bytes
+}
+
 // For runs a function against a list of queries, collecting the results or returning an error. The indices are preserved such that input[i] maps to output[i].
 func (in instance) For(
 	ctx context.Context,
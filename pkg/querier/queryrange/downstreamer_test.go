@@ -520,3 +520,23 @@ func TestDownstreamerUsesCorrectParallelism(t *testing.T) {
 	}
 	require.Equal(t, l.maxQueryParallelism, ct)
 }
+
+func TestQueryCost(t *testing.T) {
+	bounded := logql.Shards{
+		logql.NewBoundedShard(logproto.Shard{
+			Bounds: logproto.FPBounds{Min: 0, Max: 10},
+			Stats:  &logproto.IndexStatsResponse{Bytes: 1 << 20},
+		}),
+		logql.NewBoundedShard(logproto.Shard{
+			Bounds: logproto.FPBounds{Min: 11, Max: 20},
+			Stats:  &logproto.IndexStatsResponse{Bytes: 1 << 10},
+		}),
+	}.Encode()
+	require.Equal(t, uint64(1<<20+1<<10), queryCost(bounded))
+
+	powerOfTwo := logql.Shards{
+		logql.NewPowerOfTwoShard(index.ShardAnnotation{Shard: 0, Of: 2}),
+	}.Encode()
+	require.Equal(t, uint64(0), queryCost(powerOfTwo))
+	require.Equal(t, uint64(0), queryCost(nil))
+}
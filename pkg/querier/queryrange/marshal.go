@@ -425,6 +425,17 @@ func (Codec) QueryRequestWrap(ctx context.Context, r queryrangebase.Request) (*Q
 		result.Metadata[httpreq.LokiDisablePipelineWrappersHeader] = disableWrappers
 	}
 
+	// Add estimated cost, priority class and running query ID for the scheduler
+	if cost := httpreq.ExtractHeader(ctx, httpreq.LokiQueryCostHeader); cost != "" {
+		result.Metadata[httpreq.LokiQueryCostHeader] = cost
+	}
+	if priority := httpreq.ExtractHeader(ctx, httpreq.LokiQueryPriorityHeader); priority != "" {
+		result.Metadata[httpreq.LokiQueryPriorityHeader] = priority
+	}
+	if id := httpreq.ExtractHeader(ctx, httpreq.LokiQueryIDHeader); id != "" {
+		result.Metadata[httpreq.LokiQueryIDHeader] = id
+	}
+
 	// Add limits
 	limits := querylimits.ExtractQueryLimitsContext(ctx)
 	if limits != nil {
@@ -15,7 +15,9 @@ import (
 var emptyStats = `"stats": {
 	"index": {
 		"postFilterChunks": 0,
-		"totalChunks": 0
+		"totalChunks": 0,
+		"totalFilters": 0,
+		"bloomFilters": 0
 	},
 	"ingester" : {
 		"store": {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/dustin/go-humanize"
@@ -24,6 +25,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/storage/config"
 	"github.com/grafana/loki/v3/pkg/storage/types"
 	"github.com/grafana/loki/v3/pkg/util"
+	"github.com/grafana/loki/v3/pkg/util/httpreq"
 	util_log "github.com/grafana/loki/v3/pkg/util/log"
 	"github.com/grafana/loki/v3/pkg/util/marshal"
 	"github.com/grafana/loki/v3/pkg/util/spanlogger"
@@ -224,6 +226,12 @@ func (ast *astMapperware) Do(ctx context.Context, r queryrangebase.Request) (que
 		return nil, err
 	}
 
+	// The sub-queries are weighted by the bytes they read in the scheduler. Bounded shards carry their own bytes
+	// which the downstreamer uses instead, the index stats divided by the shard factor are used for the others.
+	if bytesPerShard > 0 {
+		ctx = httpreq.InjectHeader(ctx, httpreq.LokiQueryCostHeader, strconv.FormatUint(bytesPerShard, 10))
+	}
+
 	// If the ast can't be mapped to a sharded equivalent,
 	// we can bypass the sharding engine and forward the request downstream.
 	if noop {
@@ -28,6 +28,8 @@ import (
 	"github.com/grafana/loki/v3/pkg/storage/types"
 	"github.com/grafana/loki/v3/pkg/util"
 	"github.com/grafana/loki/v3/pkg/util/constants"
+	"github.com/grafana/loki/v3/pkg/util/httpreq"
+	valid "github.com/grafana/loki/v3/pkg/validation"
 )
 
 var (
@@ -337,6 +339,93 @@ func Test_astMapper_QuerySizeLimits(t *testing.T) {
 	}
 }
 
+func Test_astMapper_QueryCost(t *testing.T) {
+	const bytes = 4 * valid.DefaultTSDBMaxBytesPerShard
+	for _, tc := range []struct {
+		desc  string
+		query string
+
+		expectedRequests int
+		expectedCost     string
+	}{
+		{
+			desc:  "Non shardable query",
+			query: `quantile_over_time(0.99, {app="foo"} | unwrap foo [1h])`,
+
+			expectedRequests: 1,
+			expectedCost:     fmt.Sprint(bytes),
+		},
+		{
+			desc:  "Shardable query",
+			query: `count_over_time({app="foo"} |= "foo" [1h])`,
+
+			expectedRequests: 4,
+			expectedCost:     fmt.Sprint(bytes / 4),
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			var (
+				mtx   sync.Mutex
+				costs []string
+			)
+			handler := queryrangebase.HandlerFunc(func(ctx context.Context, req queryrangebase.Request) (queryrangebase.Response, error) {
+				if _, ok := req.(*logproto.IndexStatsRequest); ok {
+					return &IndexStatsResponse{
+						Response: &logproto.IndexStatsResponse{
+							Bytes: bytes,
+						},
+					}, nil
+				}
+
+				mtx.Lock()
+				costs = append(costs, httpreq.ExtractHeader(ctx, httpreq.LokiQueryCostHeader))
+				mtx.Unlock()
+				return &LokiPromResponse{Response: &queryrangebase.PrometheusResponse{
+					Data: queryrangebase.PrometheusData{
+						ResultType: loghttp.ResultTypeMatrix,
+					},
+				}}, nil
+			})
+
+			// the fake limits use the default power of two sharding strategy
+			mware := newASTMapperware(
+				ShardingConfigs{
+					config.PeriodConfig{
+						RowShards: 2,
+						IndexType: types.TSDBType,
+					},
+				},
+				testEngineOpts,
+				handler,
+				nil,
+				log.NewNopLogger(),
+				nilShardingMetrics,
+				fakeLimits{
+					maxSeries:               math.MaxInt32,
+					maxQueryParallelism:     1,
+					tsdbMaxQueryParallelism: 1,
+					queryTimeout:            time.Minute,
+				},
+				0,
+				[]string{},
+			)
+
+			req := defaultReq()
+			req.Query = tc.query
+			req.Plan = &plan.QueryPlan{
+				AST: syntax.MustParseExpr(tc.query),
+			}
+			_, err := mware.Do(user.InjectOrgID(context.Background(), "1"), req)
+			require.NoError(t, err)
+
+			require.Len(t, costs, tc.expectedRequests)
+			for _, cost := range costs {
+				require.Equal(t, tc.expectedCost, cost)
+			}
+		})
+	}
+}
+
 func Test_ShardingByPass(t *testing.T) {
 	called := 0
 	handler := queryrangebase.HandlerFunc(func(ctx context.Context, req queryrangebase.Request) (queryrangebase.Response, error) {
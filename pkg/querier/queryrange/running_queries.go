@@ -0,0 +1,191 @@
+package queryrange
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafana/dskit/tenant"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
+	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/v3/pkg/util/httpreq"
+)
+
+// RunningQueries is a registry of the queries in flight in the query-frontend.
+// Each query is assigned an ID which is propagated to the query-scheduler with all of its sub-queries,
+// and which can be used to cancel the query together with all of its sub-queries on the queriers.
+type RunningQueries struct {
+	mtx     sync.Mutex
+	queries map[string]*runningQuery
+}
+
+type runningQuery struct {
+	id        string
+	tenant    string
+	query     string
+	start     time.Time
+	end       time.Time
+	startedAt time.Time
+	cancel    context.CancelFunc
+
+	subQueries     atomic.Int64
+	subQueriesDone atomic.Int64
+	bytesProcessed atomic.Int64
+}
+
+// RunningQuery describes a query in flight.
+type RunningQuery struct {
+	ID             string    `json:"id"`
+	Tenant         string    `json:"tenant"`
+	Query          string    `json:"query"`
+	Start          time.Time `json:"start"`
+	End            time.Time `json:"end"`
+	StartedAt      time.Time `json:"startedAt"`
+	Shards         int64     `json:"shards"`
+	ShardsDone     int64     `json:"shardsDone"`
+	BytesProcessed int64     `json:"bytesProcessed"`
+}
+
+func NewRunningQueries() *RunningQueries {
+	return &RunningQueries{
+		queries: map[string]*runningQuery{},
+	}
+}
+
+// Wrap implements queryrangebase.Middleware.
+// It registers every request as a running query for as long as it is handled by the next handler.
+func (r *RunningQueries) Wrap(next queryrangebase.Handler) queryrangebase.Handler {
+	return queryrangebase.HandlerFunc(func(ctx context.Context, req queryrangebase.Request) (queryrangebase.Response, error) {
+		tenantIDs, err := tenant.TenantIDs(ctx)
+		if err != nil {
+			return next.Do(ctx, req)
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		q := &runningQuery{
+			id:        uuid.NewString(),
+			tenant:    tenant.JoinTenantIDs(tenantIDs),
+			query:     req.GetQuery(),
+			start:     req.GetStart(),
+			end:       req.GetEnd(),
+			startedAt: time.Now(),
+			cancel:    cancel,
+		}
+		r.mtx.Lock()
+		r.queries[q.id] = q
+		r.mtx.Unlock()
+		defer func() {
+			r.mtx.Lock()
+			delete(r.queries, q.id)
+			r.mtx.Unlock()
+		}()
+
+		ctx = httpreq.InjectHeader(ctx, httpreq.LokiQueryIDHeader, q.id)
+		return next.Do(ctx, req)
+	})
+}
+
+// Track returns a handler which accounts the requests sent to the next handler, i.e. the sub-queries
+// sent to the queriers, to the progress of the running query they belong to.
+func (r *RunningQueries) Track(next queryrangebase.Handler) queryrangebase.Handler {
+	return queryrangebase.HandlerFunc(func(ctx context.Context, req queryrangebase.Request) (queryrangebase.Response, error) {
+		r.mtx.Lock()
+		q := r.queries[httpreq.ExtractHeader(ctx, httpreq.LokiQueryIDHeader)]
+		r.mtx.Unlock()
+		if q == nil {
+			return next.Do(ctx, req)
+		}
+
+		q.subQueries.Inc()
+		resp, err := next.Do(ctx, req)
+		q.subQueriesDone.Inc()
+		if s, ok := resp.(interface{ GetStatistics() stats.Result }); ok && err == nil {
+			q.bytesProcessed.Add(s.GetStatistics().Summary.TotalBytesProcessed)
+		}
+		return resp, err
+	})
+}
+
+// List returns the running queries of the tenant, ordered by the time they started.
+func (r *RunningQueries) List(tenantID string) []RunningQuery {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	res := make([]RunningQuery, 0, len(r.queries))
+	for _, q := range r.queries {
+		if q.tenant != tenantID {
+			continue
+		}
+		res = append(res, RunningQuery{
+			ID:             q.id,
+			Tenant:         q.tenant,
+			Query:          q.query,
+			Start:          q.start,
+			End:            q.end,
+			StartedAt:      q.startedAt,
+			Shards:         q.subQueries.Load(),
+			ShardsDone:     q.subQueriesDone.Load(),
+			BytesProcessed: q.bytesProcessed.Load(),
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].StartedAt.Before(res[j].StartedAt)
+	})
+	return res
+}
+
+// Cancel cancels the running query of the tenant with the given ID.
+// It returns false if there is no such query.
+func (r *RunningQueries) Cancel(tenantID, id string) bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	q, ok := r.queries[id]
+	if !ok || q.tenant != tenantID {
+		return false
+	}
+	q.cancel()
+	return true
+}
+
+// ListHandler lists the running queries of the tenant of the request.
+func (r *RunningQueries) ListHandler(w http.ResponseWriter, req *http.Request) {
+	tenantIDs, err := tenant.TenantIDs(req.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(r.List(tenant.JoinTenantIDs(tenantIDs))); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// CancelHandler cancels the running query of the tenant of the request with the ID of the `id` parameter.
+func (r *RunningQueries) CancelHandler(w http.ResponseWriter, req *http.Request) {
+	tenantIDs, err := tenant.TenantIDs(req.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id := req.FormValue("id")
+	if id == "" {
+		http.Error(w, "missing query id", http.StatusBadRequest)
+		return
+	}
+	if !r.Cancel(tenant.JoinTenantIDs(tenantIDs), id) {
+		http.Error(w, "query not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,75 @@
+package queryrange
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
+	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/v3/pkg/util/httpreq"
+)
+
+func TestRunningQueries(t *testing.T) {
+	r := NewRunningQueries()
+
+	started := make(chan struct{})
+	subQuery := queryrangebase.HandlerFunc(func(ctx context.Context, _ queryrangebase.Request) (queryrangebase.Response, error) {
+		require.NotEmpty(t, httpreq.ExtractHeader(ctx, httpreq.LokiQueryIDHeader))
+		return &LokiResponse{
+			Statistics: stats.Result{Summary: stats.Summary{TotalBytesProcessed: 100}},
+		}, nil
+	})
+	track := r.Track(subQuery)
+	handler := r.Wrap(queryrangebase.HandlerFunc(func(ctx context.Context, req queryrangebase.Request) (queryrangebase.Response, error) {
+		for i := 0; i < 2; i++ {
+			if _, err := track.Do(ctx, req); err != nil {
+				return nil, err
+			}
+		}
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}))
+
+	req := &LokiRequest{
+		Query:   `{app="foo"}`,
+		StartTs: time.Unix(0, 0),
+		EndTs:   time.Unix(3600, 0),
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := handler.Do(user.InjectOrgID(context.Background(), "tenant"), req)
+		errCh <- err
+	}()
+	<-started
+
+	require.Empty(t, r.List("other"))
+	queries := r.List("tenant")
+	require.Len(t, queries, 1)
+	q := queries[0]
+	require.Equal(t, "tenant", q.Tenant)
+	require.Equal(t, `{app="foo"}`, q.Query)
+	require.Equal(t, req.StartTs, q.Start)
+	require.Equal(t, req.EndTs, q.End)
+	require.Equal(t, int64(2), q.Shards)
+	require.Equal(t, int64(2), q.ShardsDone)
+	require.Equal(t, int64(200), q.BytesProcessed)
+
+	// the query of a tenant can't be cancelled by another tenant
+	cancelReq := httptest.NewRequest(http.MethodPost, "/loki/api/v1/running_queries/cancel?id="+q.ID, nil)
+	rec := httptest.NewRecorder()
+	r.CancelHandler(rec, cancelReq.WithContext(user.InjectOrgID(cancelReq.Context(), "other")))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	r.CancelHandler(rec, cancelReq.WithContext(user.InjectOrgID(cancelReq.Context(), "tenant")))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.ErrorIs(t, <-errCh, context.Canceled)
+	require.Empty(t, r.List("tenant"))
+}
@@ -90,7 +90,7 @@ func NewVolumeMiddleware() queryrangebase.Middleware {
 				return nil, err
 			}
 
-			promResp := ToPrometheusResponse(collector, seriesvolume.AggregateBySeries(volReq.AggregateBy))
+			promResp := ToPrometheusResponse(collector, seriesvolume.GroupsBySeries(volReq.AggregateBy))
 			return promResp, nil
 		})
 	})
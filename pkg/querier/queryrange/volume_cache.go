@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/cache"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/cache/resultscache"
+	"github.com/grafana/loki/v3/pkg/storage/stores/index/seriesvolume"
 	"github.com/grafana/loki/v3/pkg/util"
 	"github.com/grafana/loki/v3/pkg/util/validation"
 )
@@ -38,7 +39,8 @@ func (i VolumeSplitter) GenerateCacheKey(ctx context.Context, userID string, r r
 type VolumeExtractor struct{}
 
 // Extract favors the ability to cache over exactness of results. It assumes a constant distribution
-// of log volumes over a range and will extract subsets proportionally.
+// of log volumes over a range and will extract subsets proportionally. Cardinality aggregations aren't
+// proportional to time, only entire extents are extracted for them, see NewVolumeCacheMiddleware.
 func (p VolumeExtractor) Extract(start, end int64, res resultscache.Response, resStart, resEnd int64) resultscache.Response {
 	factor := util.GetFactorOfTime(start, end, resStart, resEnd)
 
@@ -108,30 +110,52 @@ func NewVolumeCacheMiddleware(
 	transformer UserIDTransformer,
 	metrics *queryrangebase.ResultsCacheMetrics,
 ) (queryrangebase.Middleware, error) {
-	return queryrangebase.NewResultsCacheMiddleware(
-		log,
-		c,
-		VolumeSplitter{cacheKeyLimits{limits, transformer, iqo}},
-		limits,
-		merger,
-		VolumeExtractor{},
-		cacheGenNumberLoader,
-		func(ctx context.Context, r queryrangebase.Request) bool {
-			if shouldCache != nil && !shouldCache(ctx, r) {
-				return false
-			}
+	newMiddleware := func(onlyUseEntireExtent bool) (queryrangebase.Middleware, error) {
+		return queryrangebase.NewResultsCacheMiddleware(
+			log,
+			c,
+			VolumeSplitter{cacheKeyLimits{limits, transformer, iqo}},
+			limits,
+			merger,
+			VolumeExtractor{},
+			cacheGenNumberLoader,
+			func(ctx context.Context, r queryrangebase.Request) bool {
+				if shouldCache != nil && !shouldCache(ctx, r) {
+					return false
+				}
+
+				cacheStats, err := shouldCacheVolume(ctx, r, limits)
+				if err != nil {
+					level.Error(log).Log("msg", "failed to determine if volume should be cached. Won't cache", "err", err)
+					return false
+				}
+
+				return cacheStats
+			},
+			parallelismForReq,
+			retentionEnabled,
+			onlyUseEntireExtent,
+			metrics,
+		)
+	}
 
-			cacheStats, err := shouldCacheVolume(ctx, r, limits)
-			if err != nil {
-				level.Error(log).Log("msg", "failed to determine if volume should be cached. Won't cache", "err", err)
-				return false
-			}
+	volume, err := newMiddleware(false)
+	if err != nil {
+		return nil, err
+	}
+	// The number of series of a part of an extent can't be extracted proportionally to its time range.
+	cardinality, err := newMiddleware(true)
+	if err != nil {
+		return nil, err
+	}
 
-			return cacheStats
-		},
-		parallelismForReq,
-		retentionEnabled,
-		false,
-		metrics,
-	)
+	return queryrangebase.MiddlewareFunc(func(next queryrangebase.Handler) queryrangebase.Handler {
+		volumeHandler, cardinalityHandler := volume.Wrap(next), cardinality.Wrap(next)
+		return queryrangebase.HandlerFunc(func(ctx context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+			if volReq, ok := r.(*logproto.VolumeRequest); ok && seriesvolume.IsCardinality(volReq.GetAggregateBy()) {
+				return cardinalityHandler.Do(ctx, r)
+			}
+			return volumeHandler.Do(ctx, r)
+		})
+	}), nil
 }
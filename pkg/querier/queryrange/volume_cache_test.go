@@ -142,6 +142,45 @@ func TestVolumeCache(t *testing.T) {
 		require.Equal(t, expectedVol, resp)
 	})
 
+	t.Run("a new request with overlapping time range doesn't extract part of a previous cardinality request", func(t *testing.T) {
+		volResp := &VolumeResponse{
+			Response: &logproto.VolumeResponse{
+				Volumes: []logproto.Volume{
+					{
+						Name:   `{foo="bar"}`,
+						Volume: 42,
+					},
+				},
+				Limit: 10,
+			},
+		}
+		calls, handler := setup(volResp)
+
+		from, through := util.RoundToMilliseconds(testTime, testTime.Add(1*time.Hour))
+		volReq := &logproto.VolumeRequest{
+			From:        from,
+			Through:     through,
+			Matchers:    `{foo="bar"}`,
+			Limit:       10,
+			AggregateBy: seriesvolume.SeriesCount,
+		}
+
+		ctx := user.InjectOrgID(context.Background(), "fake")
+		resp, err := handler.Do(ctx, volReq)
+		require.NoError(t, err)
+		require.Equal(t, 1, *calls)
+		require.Equal(t, volResp, resp)
+
+		// The cached extent only partially overlaps the new request, so the whole range is requested again
+		// instead of counting 75% of the series of the extent.
+		*calls = 0
+		req := volReq.WithStartEnd(volReq.GetStart().Add(15*time.Minute), volReq.GetEnd().Add(15*time.Minute))
+		resp, err = handler.Do(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, 1, *calls)
+		require.Equal(t, volResp, resp)
+	})
+
 	t.Run("caches are only valid for the same request parameters", func(t *testing.T) {
 		volResp := &VolumeResponse{
 			Response: &logproto.VolumeResponse{
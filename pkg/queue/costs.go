@@ -0,0 +1,79 @@
+package queue
+
+import (
+	"math"
+	"time"
+)
+
+// CostedRequest is implemented by requests which carry an estimation of their cost,
+// e.g. the amount of bytes they are going to process.
+type CostedRequest interface {
+	Cost() int64
+}
+
+// requestCost returns the cost of the request.
+// Requests without a cost estimation are accounted with a cost of 1.
+func requestCost(req Request) float64 {
+	if r, ok := req.(CostedRequest); ok {
+		if cost := r.Cost(); cost > 0 {
+			return float64(cost)
+		}
+	}
+	return 1
+}
+
+type decayedCost struct {
+	value   float64
+	updated time.Time
+}
+
+// tenantCosts tracks the cost consumed by each tenant. The consumed cost decays exponentially with the
+// configured window as time constant, so that the costs of requests dequeued before the window only weigh little.
+type tenantCosts struct {
+	window time.Duration
+	costs  map[string]*decayedCost
+}
+
+func newTenantCosts(window time.Duration) *tenantCosts {
+	return &tenantCosts{
+		window: window,
+		costs:  map[string]*decayedCost{},
+	}
+}
+
+func (c *tenantCosts) decay(value float64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return value
+	}
+	return value * math.Exp(-float64(elapsed)/float64(c.window))
+}
+
+// get returns the consumed cost of the tenant at the given time.
+func (c *tenantCosts) get(tenant string, now time.Time) float64 {
+	cost, ok := c.costs[tenant]
+	if !ok {
+		return 0
+	}
+	return c.decay(cost.value, now.Sub(cost.updated))
+}
+
+// add accounts the cost of a dequeued request to the tenant.
+func (c *tenantCosts) add(tenant string, value float64, now time.Time) {
+	cost, ok := c.costs[tenant]
+	if !ok {
+		c.costs[tenant] = &decayedCost{value: value, updated: now}
+		return
+	}
+	cost.value = c.decay(cost.value, now.Sub(cost.updated)) + value
+	cost.updated = now
+}
+
+// expire removes the tenants which haven't consumed anything for the last 10 windows.
+func (c *tenantCosts) expire(now time.Time) {
+	threshold := now.Add(-10 * c.window)
+	for tenant, cost := range c.costs {
+		if cost.updated.Before(threshold) {
+			delete(c.costs, tenant)
+		}
+	}
+}
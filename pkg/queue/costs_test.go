@@ -0,0 +1,125 @@
+package queue
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/util/constants"
+)
+
+type costedRequest struct {
+	tenant string
+	cost   int64
+}
+
+func (r costedRequest) Cost() int64 {
+	return r.cost
+}
+
+type weightedQueueLimits map[string]float64
+
+func (l weightedQueueLimits) MaxConsumers(_ string, _ int) int {
+	return 0
+}
+
+func (l weightedQueueLimits) TenantWeight(user string) float64 {
+	if w, ok := l[user]; ok {
+		return w
+	}
+	return 1
+}
+
+func (l weightedQueueLimits) MaxLowPriorityCapacity(_ string) float64 {
+	return 0
+}
+
+func TestTenantCosts(t *testing.T) {
+	now := time.Unix(0, 0)
+	costs := newTenantCosts(time.Minute)
+
+	require.Equal(t, 0.0, costs.get("a", now))
+
+	costs.add("a", 100, now)
+	require.Equal(t, 100.0, costs.get("a", now))
+	require.InDelta(t, 100/math.E, costs.get("a", now.Add(time.Minute)), 1e-9)
+
+	costs.add("a", 100, now.Add(time.Minute))
+	require.InDelta(t, 100+100/math.E, costs.get("a", now.Add(time.Minute)), 1e-9)
+
+	costs.add("b", 1, now.Add(10*time.Minute))
+	costs.expire(now.Add(11*time.Minute + time.Second))
+	require.Equal(t, 0.0, costs.get("a", now.Add(11*time.Minute)))
+	require.Equal(t, 1.0, costs.costs["b"].value)
+}
+
+func TestRequestCost(t *testing.T) {
+	require.Equal(t, 1.0, requestCost("not costed"))
+	require.Equal(t, 1.0, requestCost(costedRequest{cost: 0}))
+	require.Equal(t, 42.0, requestCost(costedRequest{cost: 42}))
+}
+
+func dequeueTenants(t *testing.T, q *RequestQueue, n int) []string {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var tenants []string
+	last := StartIndexWithLocalQueue
+	for i := 0; i < n; i++ {
+		req, idx, err := q.Dequeue(ctx, last, "consumer")
+		require.NoError(t, err)
+		last = idx
+		tenants = append(tenants, req.(costedRequest).tenant)
+	}
+	return tenants
+}
+
+func TestQueue_CostFairness(t *testing.T) {
+	t.Run("cheap tenant is dequeued until it consumed as much as expensive tenant", func(t *testing.T) {
+		q := NewRequestQueue(100, 0, noQueueLimits, NewMetrics(nil, constants.Loki, "query_scheduler"))
+		q.EnableCostFairness(time.Hour)
+		q.RegisterConsumerConnection("consumer")
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, q.Enqueue("a", nil, costedRequest{tenant: "a", cost: 100}, nil))
+		}
+		for i := 0; i < 5; i++ {
+			require.NoError(t, q.Enqueue("b", nil, costedRequest{tenant: "b", cost: 10}, nil))
+		}
+
+		require.Equal(t, []string{"a", "b", "b", "b", "b", "b", "a", "a"}, dequeueTenants(t, q, 8))
+	})
+
+	t.Run("tenants with higher weight consume more", func(t *testing.T) {
+		q := NewRequestQueue(100, 0, weightedQueueLimits{"a": 2}, NewMetrics(nil, constants.Loki, "query_scheduler"))
+		q.EnableCostFairness(time.Hour)
+		q.RegisterConsumerConnection("consumer")
+
+		for i := 0; i < 6; i++ {
+			require.NoError(t, q.Enqueue("a", nil, costedRequest{tenant: "a", cost: 10}, nil))
+			require.NoError(t, q.Enqueue("b", nil, costedRequest{tenant: "b", cost: 10}, nil))
+		}
+
+		counts := map[string]int{}
+		for _, tenant := range dequeueTenants(t, q, 6) {
+			counts[tenant]++
+		}
+		require.Equal(t, map[string]int{"a": 4, "b": 2}, counts)
+	})
+
+	t.Run("round-robin without cost fairness", func(t *testing.T) {
+		q := NewRequestQueue(100, 0, noQueueLimits, NewMetrics(nil, constants.Loki, "query_scheduler"))
+		q.RegisterConsumerConnection("consumer")
+
+		for i := 0; i < 2; i++ {
+			require.NoError(t, q.Enqueue("a", nil, costedRequest{tenant: "a", cost: 100}, nil))
+			require.NoError(t, q.Enqueue("b", nil, costedRequest{tenant: "b", cost: 10}, nil))
+		}
+
+		require.Equal(t, []string{"a", "b", "a", "b"}, dequeueTenants(t, q, 4))
+	})
+}
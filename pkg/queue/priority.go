@@ -0,0 +1,147 @@
+package queue
+
+import (
+	"fmt"
+)
+
+// Priority is the priority class of a request. Requests of a tenant are dequeued from its
+// higher priority classes first, according to the PriorityPolicy of the queue.
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+
+	numPriorities = 3
+)
+
+var priorityNames = [numPriorities]string{"low", "normal", "high"}
+
+// Priorities are all priority classes, from the highest to the lowest.
+var Priorities = []Priority{PriorityHigh, PriorityNormal, PriorityLow}
+
+func (p Priority) String() string {
+	if p < 0 || p >= numPriorities {
+		return fmt.Sprintf("Priority(%d)", int(p))
+	}
+	return priorityNames[p]
+}
+
+// ParsePriority parses the name of a priority class.
+func ParsePriority(s string) (Priority, error) {
+	for i, name := range priorityNames {
+		if s == name {
+			return Priority(i), nil
+		}
+	}
+	return PriorityNormal, fmt.Errorf("invalid priority class %q, must be one of %v", s, priorityNames)
+}
+
+// PrioritizedRequest is implemented by requests which carry a priority class.
+type PrioritizedRequest interface {
+	Priority() Priority
+}
+
+// requestPriority returns the priority class of the request.
+// Requests without a valid priority class are of normal priority.
+func requestPriority(req Request) Priority {
+	if r, ok := req.(PrioritizedRequest); ok {
+		if p := r.Priority(); p >= 0 && p < numPriorities {
+			return p
+		}
+	}
+	return PriorityNormal
+}
+
+// PriorityPolicy defines how the requests of the priority classes of a tenant preempt each other.
+type PriorityPolicy string
+
+const (
+	// PriorityPolicyStrict always dequeues from the highest priority class with pending requests.
+	PriorityPolicyStrict PriorityPolicy = "strict"
+	// PriorityPolicyWeighted dequeues from the priority classes with pending requests in a weighted
+	// round-robin fashion, so that lower priority classes can't be starved.
+	PriorityPolicyWeighted PriorityPolicy = "weighted"
+)
+
+// priorityWeights are the weights of the priority classes with PriorityPolicyWeighted.
+var priorityWeights = [numPriorities]int{1, 2, 4}
+
+func (p PriorityPolicy) Validate() error {
+	switch p {
+	case PriorityPolicyStrict, PriorityPolicyWeighted:
+		return nil
+	default:
+		return fmt.Errorf("invalid priority policy %q, must be one of %q or %q", p, PriorityPolicyStrict, PriorityPolicyWeighted)
+	}
+}
+
+// nextPriority returns the priority class to dequeue from next, or false if no class can be dequeued from.
+func (q *tenantQueue) nextPriority() (Priority, bool) {
+	if q.policy != PriorityPolicyWeighted {
+		for _, p := range Priorities {
+			if q.canDequeue(p) {
+				return p, true
+			}
+		}
+		return PriorityNormal, false
+	}
+
+	// Smooth weighted round-robin between the classes with pending requests.
+	var total int
+	next, found := PriorityNormal, false
+	for _, p := range Priorities {
+		if !q.canDequeue(p) {
+			continue
+		}
+		q.currentWeights[p] += priorityWeights[p]
+		total += priorityWeights[p]
+		if !found || q.currentWeights[p] > q.currentWeights[next] {
+			next, found = p, true
+		}
+	}
+	if found {
+		q.currentWeights[next] -= total
+	}
+	return next, found
+}
+
+func (q *tenantQueue) canDequeue(p Priority) bool {
+	if p == PriorityLow && q.lowPriorityExhausted {
+		return false
+	}
+	return q.queues[p] != nil && q.queues[p].Len() > 0
+}
+
+// hasDequeueable returns whether a request can be dequeued from any priority class.
+func (q *tenantQueue) hasDequeueable() bool {
+	for _, p := range Priorities {
+		if q.canDequeue(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// Dequeue implements Queue.
+// It takes the next request of the priority class chosen by the priority policy.
+func (q *tenantQueue) Dequeue() Request {
+	p, ok := q.nextPriority()
+	if !ok {
+		return nil
+	}
+	return q.queues[p].Dequeue()
+}
+
+// Len implements Queue.
+// It returns the length of the queues of all priority classes.
+func (q *tenantQueue) Len() int {
+	var count int
+	for _, queue := range q.queues {
+		if queue != nil {
+			count += queue.Len()
+		}
+	}
+	return count
+}
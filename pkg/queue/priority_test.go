@@ -0,0 +1,119 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/util/constants"
+)
+
+type prioritizedRequest struct {
+	priority Priority
+}
+
+func (r prioritizedRequest) Priority() Priority {
+	return r.priority
+}
+
+type lowPriorityCapacityLimits float64
+
+func (l lowPriorityCapacityLimits) MaxConsumers(_ string, _ int) int {
+	return 0
+}
+
+func (l lowPriorityCapacityLimits) TenantWeight(_ string) float64 {
+	return 1
+}
+
+func (l lowPriorityCapacityLimits) MaxLowPriorityCapacity(_ string) float64 {
+	return float64(l)
+}
+
+func TestParsePriority(t *testing.T) {
+	for _, p := range Priorities {
+		parsed, err := ParsePriority(p.String())
+		require.NoError(t, err)
+		require.Equal(t, p, parsed)
+	}
+	_, err := ParsePriority("urgent")
+	require.Error(t, err)
+
+	require.Equal(t, PriorityNormal, requestPriority("not prioritized"))
+	require.Equal(t, PriorityNormal, requestPriority(prioritizedRequest{priority: 42}))
+}
+
+func dequeuePriorities(t *testing.T, q *RequestQueue, n int) []Priority {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var priorities []Priority
+	last := StartIndexWithLocalQueue
+	for i := 0; i < n; i++ {
+		req, idx, err := q.Dequeue(ctx, last, "consumer")
+		require.NoError(t, err)
+		last = idx
+		priorities = append(priorities, req.(prioritizedRequest).priority)
+	}
+	return priorities
+}
+
+func TestQueue_Priorities(t *testing.T) {
+	t.Run("strict", func(t *testing.T) {
+		q := NewRequestQueue(100, 0, noQueueLimits, NewMetrics(nil, constants.Loki, "query_scheduler"))
+		q.RegisterConsumerConnection("consumer")
+
+		for _, p := range []Priority{PriorityLow, PriorityNormal, PriorityHigh, PriorityLow, PriorityNormal, PriorityHigh} {
+			require.NoError(t, q.Enqueue("tenant", nil, prioritizedRequest{priority: p}, nil))
+		}
+
+		require.Equal(t, []Priority{
+			PriorityHigh, PriorityHigh, PriorityNormal, PriorityNormal, PriorityLow, PriorityLow,
+		}, dequeuePriorities(t, q, 6))
+	})
+
+	t.Run("weighted", func(t *testing.T) {
+		q := NewRequestQueue(100, 0, noQueueLimits, NewMetrics(nil, constants.Loki, "query_scheduler"))
+		q.SetPriorityPolicy(PriorityPolicyWeighted)
+		q.RegisterConsumerConnection("consumer")
+
+		for i := 0; i < 7; i++ {
+			for _, p := range Priorities {
+				require.NoError(t, q.Enqueue("tenant", []string{"actor"}, prioritizedRequest{priority: p}, nil))
+			}
+		}
+
+		counts := map[Priority]int{}
+		for _, p := range dequeuePriorities(t, q, 7) {
+			counts[p]++
+		}
+		require.Equal(t, map[Priority]int{PriorityHigh: 4, PriorityNormal: 2, PriorityLow: 1}, counts)
+	})
+
+	t.Run("low priority capacity", func(t *testing.T) {
+		q := NewRequestQueue(100, 0, lowPriorityCapacityLimits(0.5), NewMetrics(nil, constants.Loki, "query_scheduler"))
+		// two connections of the consumer allow a single low priority request in flight
+		q.RegisterConsumerConnection("consumer")
+		q.RegisterConsumerConnection("consumer")
+
+		low := prioritizedRequest{priority: PriorityLow}
+		require.NoError(t, q.Enqueue("tenant", nil, low, nil))
+		require.NoError(t, q.Enqueue("tenant", nil, low, nil))
+		require.Equal(t, []Priority{PriorityLow}, dequeuePriorities(t, q, 1))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_, _, err := q.Dequeue(ctx, StartIndex, "consumer")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// higher priority requests are not limited
+		require.NoError(t, q.Enqueue("tenant", nil, prioritizedRequest{priority: PriorityNormal}, nil))
+		require.Equal(t, []Priority{PriorityNormal}, dequeuePriorities(t, q, 1))
+
+		q.FinishRequest("tenant", low)
+		require.Equal(t, []Priority{PriorityLow}, dequeuePriorities(t, q, 1))
+	})
+}
@@ -44,6 +44,13 @@ func (ui QueueIndex) ReuseLastIndex() QueueIndex {
 type Limits interface {
 	// MaxConsumers returns the max consumers to use per tenant or 0 to allow all consumers to consume from the queue.
 	MaxConsumers(user string, allConsumers int) int
+
+	// TenantWeight returns the weight of the tenant's share of the consumed cost when dequeuing weighted fairly.
+	TenantWeight(user string) float64
+
+	// MaxLowPriorityCapacity returns the fraction of the consumers' connections of a tenant which can be used by
+	// low priority requests, or 0 if they can use all of them.
+	MaxLowPriorityCapacity(user string) float64
 }
 
 // Request stored into the queue.
@@ -83,6 +90,23 @@ func NewRequestQueue(maxOutstandingPerTenant int, forgetDelay time.Duration, lim
 	return q
 }
 
+// EnableCostFairness makes the queue dequeue tenants weighted fairly by the cost of their requests instead of
+// round-robin: the next request is taken from the tenant with the lowest consumed cost relative to its weight.
+// The consumed cost decays exponentially over the given window. It must be called before the queue is used.
+func (q *RequestQueue) EnableCostFairness(window time.Duration) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	q.queues.costs = newTenantCosts(window)
+}
+
+// SetPriorityPolicy sets how the priority classes of the requests of a tenant preempt each other.
+// It must be called before the queue is used.
+func (q *RequestQueue) SetPriorityPolicy(policy PriorityPolicy) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	q.queues.priorityPolicy = policy
+}
+
 // Enqueue puts the request into the queue.
 // If request is successfully enqueued, successFn is called with the lock held, before any querier can receive the request.
 func (q *RequestQueue) Enqueue(tenant string, path []string, req Request, successFn func()) error {
@@ -93,7 +117,7 @@ func (q *RequestQueue) Enqueue(tenant string, path []string, req Request, succes
 		return ErrStopped
 	}
 
-	queue, err := q.queues.getOrAddQueue(tenant, path)
+	queue, err := q.queues.getOrAddQueue(tenant, requestPriority(req), path)
 	if err != nil {
 		return fmt.Errorf("no queue found: %w", err)
 	}
@@ -201,7 +225,18 @@ FindQueue:
 		return nil, last, wantedQueueName, false, err
 	}
 
-	queue, tenant, idx := q.queues.getNextQueueForConsumer(last, consumerID)
+	var (
+		queue  Queue
+		tenant string
+		idx    QueueIndex
+		now    = time.Now()
+	)
+	// A consumer which wants to read from a specific queue keeps reading from the same tenant.
+	if q.queues.costs != nil && wantedQueueName == anyQueue {
+		queue, tenant, idx = q.queues.getCheapestQueueForConsumer(last, consumerID, now)
+	} else {
+		queue, tenant, idx = q.queues.getNextQueueForConsumer(last, consumerID)
+	}
 	last = idx
 	if queue == nil {
 		// it can be a case the consumer has other tenants queues available for him,
@@ -236,6 +271,12 @@ FindQueue:
 
 	q.queues.perUserQueueLen.Dec(tenant)
 	q.metrics.queueLength.WithLabelValues(tenant).Dec()
+	if requestPriority(request) == PriorityLow {
+		q.queues.inflightLowPriority.Inc(tenant)
+	}
+	if q.queues.costs != nil {
+		q.queues.costs.add(tenant, requestCost(request), now)
+	}
 
 	// Tell close() we've processed a request.
 	q.cond.Broadcast()
@@ -243,11 +284,31 @@ FindQueue:
 	return request, last, queue.Name(), isTenantQueueEmpty, nil
 }
 
+// FinishRequest must be called when a consumer finished processing a dequeued request,
+// so that the capacity used by the low priority requests of the tenant is released.
+func (q *RequestQueue) FinishRequest(tenant string, req Request) {
+	if requestPriority(req) != PriorityLow {
+		return
+	}
+
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	q.queues.inflightLowPriority.Dec(tenant)
+
+	// Consumers may be waiting for the low priority capacity of the tenant.
+	q.cond.Broadcast()
+}
+
 func (q *RequestQueue) forgetDisconnectedConsumers(_ context.Context) error {
 	q.mtx.Lock()
 	defer q.mtx.Unlock()
 
-	if q.queues.forgetDisconnectedConsumers(time.Now()) > 0 {
+	now := time.Now()
+	if q.queues.costs != nil {
+		q.queues.costs.expire(now)
+	}
+
+	if q.queues.forgetDisconnectedConsumers(now) > 0 {
 		// We need to notify goroutines cause having removed some queriers
 		// may have caused a resharding.
 		q.cond.Broadcast()
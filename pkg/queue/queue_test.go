@@ -344,6 +344,14 @@ func (l *mockLimits) MaxConsumers(_ string, _ int) int {
 	return l.maxConsumer
 }
 
+func (l *mockLimits) TenantWeight(_ string) float64 {
+	return 1
+}
+
+func (l *mockLimits) MaxLowPriorityCapacity(_ string) float64 {
+	return 0
+}
+
 func Test_Queue_DequeueMany(t *testing.T) {
 	tenantsQueueMaxSize := 100
 	tests := map[string]struct {
@@ -7,6 +7,7 @@ package queue
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 	"sort"
 	"time"
@@ -44,6 +45,13 @@ func (tqs intPointerMap) Dec(key string) int {
 	return *ptr
 }
 
+func (tqs intPointerMap) Get(key string) int {
+	if ptr, ok := tqs[key]; ok {
+		return *ptr
+	}
+	return 0
+}
+
 // consumer holds information about a consumer registered in the queue.
 type consumer struct {
 	// Number of active connections.
@@ -75,6 +83,14 @@ type tenantQueues struct {
 	sortedConsumers []string
 
 	limits Limits
+
+	// Consumed cost of the tenants, if tenants are dequeued weighted fairly by cost.
+	costs *tenantCosts
+
+	// How the priority classes of a tenant preempt each other.
+	priorityPolicy PriorityPolicy
+	// Number of dequeued low priority requests per tenant which haven't been finished yet.
+	inflightLowPriority intPointerMap
 }
 
 type Queue interface {
@@ -101,6 +117,20 @@ type tenantQueue struct {
 	// Seed for shuffle sharding of consumers. This seed is based on userID only and is therefore consistent
 	// between different frontends.
 	seed int64
+
+	// Weight of the tenant's share of the consumed cost.
+	weight float64
+
+	// Queues of the priority classes. The queue of the normal priority class is the embedded TreeQueue,
+	// the others are created on demand.
+	queues         [numPriorities]*TreeQueue
+	policy         PriorityPolicy
+	currentWeights [numPriorities]int
+
+	// Fraction of the consumers' connections which can be used by low priority requests, 0 if unlimited.
+	lowPriorityCapacity float64
+	// True if the tenant's low priority requests currently use all their capacity.
+	lowPriorityExhausted bool
 }
 
 func newTenantQueues(maxUserQueueSize int, forgetDelay time.Duration, limits Limits) *tenantQueues {
@@ -114,6 +144,9 @@ func newTenantQueues(maxUserQueueSize int, forgetDelay time.Duration, limits Lim
 		consumers:        map[string]*consumer{},
 		sortedConsumers:  nil,
 		limits:           limits,
+
+		priorityPolicy:      PriorityPolicyStrict,
+		inflightLowPriority: make(intPointerMap),
 	}
 }
 
@@ -125,8 +158,8 @@ func (q *tenantQueues) deleteQueue(tenant string) {
 	q.mapping.Remove(tenant)
 }
 
-// Returns existing or new queue for a tenant.
-func (q *tenantQueues) getOrAddQueue(tenantID string, path []string) (Queue, error) {
+// Returns existing or new queue for a tenant and priority class.
+func (q *tenantQueues) getOrAddQueue(tenantID string, priority Priority, path []string) (Queue, error) {
 	// Empty tenant is not allowed, as that would break our tenants list ("" is used for free spot).
 	if tenantID == "" {
 		return nil, fmt.Errorf("empty tenant is not allowed")
@@ -144,8 +177,10 @@ func (q *tenantQueues) getOrAddQueue(tenantID string, path []string) (Queue, err
 			seed: util.ShuffleShardSeed(tenantID, ""),
 		}
 		uq.TreeQueue = newTreeQueue(q.maxUserQueueSize, tenantID)
+		uq.queues[PriorityNormal] = uq.TreeQueue
 		q.mapping.Put(tenantID, uq)
 	}
+	uq.policy = q.priorityPolicy
 
 	consumersToSelect := validation.SmallestPositiveNonZeroIntPerTenant(
 		tenantIDs,
@@ -158,10 +193,53 @@ func (q *tenantQueues) getOrAddQueue(tenantID string, path []string) (Queue, err
 		uq.consumers = shuffleConsumersForTenants(uq.seed, consumersToSelect, q.sortedConsumers, nil)
 	}
 
-	if len(path) == 0 {
+	// Multi-tenant queries get the smallest weight of their tenants.
+	uq.weight = 0
+	for _, id := range tenantIDs {
+		if w := q.limits.TenantWeight(id); w > 0 && (uq.weight == 0 || w < uq.weight) {
+			uq.weight = w
+		}
+	}
+	if uq.weight == 0 {
+		uq.weight = 1
+	}
+
+	uq.lowPriorityCapacity = 0
+	for _, id := range tenantIDs {
+		if c := q.limits.MaxLowPriorityCapacity(id); c > 0 && (uq.lowPriorityCapacity == 0 || c < uq.lowPriorityCapacity) {
+			uq.lowPriorityCapacity = c
+		}
+	}
+
+	if priority == PriorityNormal && len(path) == 0 {
 		return uq, nil
 	}
-	return uq.add(path), nil
+	if uq.queues[priority] == nil {
+		uq.queues[priority] = newTreeQueue(q.maxUserQueueSize, priority.String())
+	}
+	return uq.queues[priority].add(path), nil
+}
+
+// canDequeue returns false if the tenant only has low priority requests pending while their capacity is exhausted.
+// It updates whether the capacity of the low priority requests of the tenant is exhausted.
+func (q *tenantQueues) canDequeue(tq *tenantQueue) bool {
+	tq.lowPriorityExhausted = false
+	if tq.lowPriorityCapacity <= 0 || tq.queues[PriorityLow] == nil || tq.queues[PriorityLow].Len() == 0 {
+		return true
+	}
+
+	var connections int
+	for id, c := range q.consumers {
+		if tq.consumers != nil {
+			if _, ok := tq.consumers[id]; !ok {
+				continue
+			}
+		}
+		connections += c.connections
+	}
+	maxInflight := max(int(math.Ceil(float64(connections)*tq.lowPriorityCapacity)), 1)
+	tq.lowPriorityExhausted = q.inflightLowPriority.Get(tq.name) >= maxInflight
+	return tq.hasDequeueable()
 }
 
 // Finds next queue for the consumer. To support fair scheduling between users, client is expected
@@ -199,12 +277,62 @@ func (q *tenantQueues) getNextQueueForConsumer(lastUserIndex QueueIndex, consume
 				continue
 			}
 		}
+		if !q.canDequeue(tq) {
+			continue
+		}
 		return tq, tq.name, uid
 	}
 
 	return nil, "", uid
 }
 
+// getCheapestQueueForConsumer finds the queue of the tenant with the lowest consumed cost relative to its weight
+// among the tenants handled by the consumer. Tenants with the same relative cost are picked in round-robin order,
+// starting after the last user index.
+func (q *tenantQueues) getCheapestQueueForConsumer(lastUserIndex QueueIndex, consumerID string, now time.Time) (Queue, string, QueueIndex) {
+	uid := lastUserIndex
+
+	// at the RequestQueue level we don't have local queues, so start index is -1
+	if uid == StartIndexWithLocalQueue {
+		uid = StartIndex
+	}
+
+	// Ensure the consumer is not shutting down. If the consumer is shutting down, we shouldn't forward
+	// any more queries to it.
+	if info := q.consumers[consumerID]; info == nil || info.shuttingDown {
+		return nil, "", uid
+	}
+
+	var (
+		cheapest *tenantQueue
+		lowest   float64
+	)
+	n := len(q.mapping.keys)
+	for i := 1; i <= n; i++ {
+		tq := q.mapping.Get(QueueIndex((int(uid) + i) % n))
+		if tq == nil {
+			continue
+		}
+		if tq.consumers != nil {
+			if _, ok := tq.consumers[consumerID]; !ok {
+				// This consumer is not handling the user.
+				continue
+			}
+		}
+		if !q.canDequeue(tq) {
+			continue
+		}
+		if cost := q.costs.get(tq.name, now) / tq.weight; cheapest == nil || cost < lowest {
+			cheapest, lowest = tq, cost
+		}
+	}
+
+	if cheapest == nil {
+		return nil, "", uid
+	}
+	return cheapest, cheapest.name, cheapest.pos
+}
+
 func (q *tenantQueues) addConsumerToConnection(consumerID string) {
 	info := q.consumers[consumerID]
 	if info != nil {
@@ -200,7 +200,7 @@ func TestQueuesConsistency(t *testing.T) {
 			for i := 0; i < 10000; i++ {
 				switch r.Int() % 6 {
 				case 0:
-					q, err := uq.getOrAddQueue(generateTenant(r), generateActor(r))
+					q, err := uq.getOrAddQueue(generateTenant(r), PriorityNormal, generateActor(r))
 					assert.NoError(t, err)
 					assert.NotNil(t, q)
 				case 1:
@@ -409,11 +409,11 @@ func generateConsumer(r *rand.Rand) string {
 
 func getOrAdd(t *testing.T, uq *tenantQueues, tenant string) Queue {
 	actor := []string{}
-	q, err := uq.getOrAddQueue(tenant, actor)
+	q, err := uq.getOrAddQueue(tenant, PriorityNormal, actor)
 	assert.NoError(t, err)
 	assert.NotNil(t, q)
 	assert.NoError(t, isConsistent(uq))
-	q2, err := uq.getOrAddQueue(tenant, actor)
+	q2, err := uq.getOrAddQueue(tenant, PriorityNormal, actor)
 	assert.NoError(t, err)
 	assert.Equal(t, q, q2)
 	return q
@@ -543,3 +543,11 @@ type mockQueueLimits struct {
 func (l *mockQueueLimits) MaxConsumers(_ string, _ int) int {
 	return l.maxConsumers
 }
+
+func (l *mockQueueLimits) TenantWeight(_ string) float64 {
+	return 1
+}
+
+func (l *mockQueueLimits) MaxLowPriorityCapacity(_ string) float64 {
+	return 0
+}
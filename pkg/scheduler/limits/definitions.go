@@ -11,6 +11,14 @@ type Limits interface {
 
 	// MaxQueryCapacity returns how much of the available query capacity can be used by this user.
 	MaxQueryCapacity(user string) float64
+
+	// QuerySchedulerTenantWeight returns the weight of the user's share of the query cost when the
+	// query-scheduler dequeues requests weighted fairly by cost.
+	QuerySchedulerTenantWeight(user string) float64
+
+	// QuerySchedulerMaxLowPriorityCapacity returns how much of the user's query capacity can be used by
+	// low priority requests, or 0 if they can use all of it.
+	QuerySchedulerMaxLowPriorityCapacity(user string) float64
 }
 
 func NewQueueLimits(limits Limits) *QueueLimits {
@@ -44,3 +52,20 @@ func (c *QueueLimits) MaxConsumers(tenantID string, allConsumers int) int {
 
 	return res
 }
+
+// TenantWeight returns the weight of the tenant's share of the query cost. It defaults to 1.
+func (c *QueueLimits) TenantWeight(tenantID string) float64 {
+	if c == nil || c.limits == nil {
+		return 1
+	}
+	return c.limits.QuerySchedulerTenantWeight(tenantID)
+}
+
+// MaxLowPriorityCapacity returns the fraction of the tenant's query capacity which can be used by low priority requests.
+// 0 is returned when the limit is not applied.
+func (c *QueueLimits) MaxLowPriorityCapacity(tenantID string) float64 {
+	if c == nil || c.limits == nil {
+		return 0
+	}
+	return c.limits.QuerySchedulerMaxLowPriorityCapacity(tenantID)
+}
@@ -76,3 +76,11 @@ func (l mockLimits) MaxQueriersPerUser(_ string) uint {
 func (l mockLimits) MaxQueryCapacity(_ string) float64 {
 	return l.maxQueryCapacity
 }
+
+func (l mockLimits) QuerySchedulerTenantWeight(_ string) float64 {
+	return 1
+}
+
+func (l mockLimits) QuerySchedulerMaxLowPriorityCapacity(_ string) float64 {
+	return 0
+}
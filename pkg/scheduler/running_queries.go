@@ -0,0 +1,135 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/grafana/dskit/tenant"
+)
+
+var errRunningQueryCancelled = errors.New("query cancelled")
+
+// RunningQuery describes the sub-queries of a running query of a query-frontend in flight in the scheduler.
+type RunningQuery struct {
+	ID       string `json:"id"`
+	Tenant   string `json:"tenant"`
+	Frontend string `json:"frontend"`
+	Queued   int    `json:"queued"`
+	Running  int    `json:"running"`
+	// Queriers are the queriers the running sub-queries have been dispatched to.
+	Queriers          []string  `json:"queriers"`
+	OldestEnqueueTime time.Time `json:"oldestEnqueueTime"`
+}
+
+// RunningQueries returns the running queries of the tenant with sub-queries in flight, ordered by their oldest sub-query.
+func (s *Scheduler) RunningQueries(tenantID string) []RunningQuery {
+	s.pendingRequestsMu.Lock()
+	defer s.pendingRequestsMu.Unlock()
+
+	byID := map[string]*RunningQuery{}
+	queriers := map[string]map[string]struct{}{}
+	for _, req := range s.pendingRequests {
+		if req.runningQueryID == "" || req.tenantID != tenantID {
+			continue
+		}
+		q, ok := byID[req.runningQueryID]
+		if !ok {
+			q = &RunningQuery{
+				ID:                req.runningQueryID,
+				Tenant:            req.tenantID,
+				Frontend:          req.frontendAddress,
+				OldestEnqueueTime: req.queueTime,
+			}
+			byID[q.ID] = q
+			queriers[q.ID] = map[string]struct{}{}
+		}
+		if req.queueTime.Before(q.OldestEnqueueTime) {
+			q.OldestEnqueueTime = req.queueTime
+		}
+		if req.querierID == "" {
+			q.Queued++
+			continue
+		}
+		q.Running++
+		if _, ok := queriers[q.ID][req.querierID]; !ok {
+			queriers[q.ID][req.querierID] = struct{}{}
+			q.Queriers = append(q.Queriers, req.querierID)
+		}
+	}
+
+	res := make([]RunningQuery, 0, len(byID))
+	for _, q := range byID {
+		sort.Strings(q.Queriers)
+		res = append(res, *q)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].OldestEnqueueTime.Before(res[j].OldestEnqueueTime)
+	})
+	return res
+}
+
+// CancelRunningQuery cancels all sub-queries of the running query of the tenant with the given ID.
+// Sub-queries dispatched to queriers are cancelled on the queriers, and the query-frontend is notified
+// of the cancellation of each sub-query so that it fails the query.
+// It returns the number of cancelled sub-queries.
+func (s *Scheduler) CancelRunningQuery(tenantID, id string) int {
+	s.pendingRequestsMu.Lock()
+	var cancelled []*schedulerRequest
+	for _, req := range s.pendingRequests {
+		if req.runningQueryID == id && req.tenantID == tenantID {
+			req.ctxCancel()
+			cancelled = append(cancelled, req)
+		}
+	}
+	s.pendingRequestsMu.Unlock()
+
+	for _, req := range cancelled {
+		go func(req *schedulerRequest) {
+			// The context of the request is already cancelled.
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			s.forwardErrorToFrontend(ctx, req, errRunningQueryCancelled)
+		}(req)
+	}
+	return len(cancelled)
+}
+
+// RunningQueriesHandler lists the running queries of the tenant of the request with sub-queries in the scheduler.
+func (s *Scheduler) RunningQueriesHandler(w http.ResponseWriter, r *http.Request) {
+	tenantIDs, err := tenant.TenantIDs(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	tenantID := tenant.JoinTenantIDs(tenantIDs)
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(s.RunningQueries(tenantID)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// CancelRunningQueryHandler cancels the sub-queries of the running query with the ID of the `id` parameter.
+func (s *Scheduler) CancelRunningQueryHandler(w http.ResponseWriter, r *http.Request) {
+	tenantIDs, err := tenant.TenantIDs(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	tenantID := tenant.JoinTenantIDs(tenantIDs)
+
+	id := r.FormValue("id")
+	if id == "" {
+		http.Error(w, "missing query id", http.StatusBadRequest)
+		return
+	}
+	if s.CancelRunningQuery(tenantID, id) == 0 {
+		http.Error(w, "query not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
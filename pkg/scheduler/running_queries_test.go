@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	util_log "github.com/grafana/loki/v3/pkg/util/log"
+)
+
+func TestScheduler_RunningQueries(t *testing.T) {
+	now := time.Now()
+	s := &Scheduler{
+		log:             util_log.Logger,
+		pendingRequests: map[requestKey]*schedulerRequest{},
+	}
+	add := func(queryID uint64, tenantID, runningQueryID, querierID string, queueTime time.Time) *schedulerRequest {
+		ctx, cancel := context.WithCancel(context.Background())
+		req := &schedulerRequest{
+			frontendAddress: "frontend",
+			tenantID:        tenantID,
+			queryID:         queryID,
+			runningQueryID:  runningQueryID,
+			querierID:       querierID,
+			queueTime:       queueTime,
+			ctx:             ctx,
+			ctxCancel:       cancel,
+		}
+		s.pendingRequests[requestKey{frontendAddr: "frontend", queryID: queryID}] = req
+		return req
+	}
+
+	a1 := add(1, "tenant", "a", "", now)
+	a2 := add(2, "tenant", "a", "querier-2", now.Add(-time.Minute))
+	a3 := add(3, "tenant", "a", "querier-1", now)
+	add(4, "tenant", "b", "querier-1", now.Add(-2*time.Minute))
+	other := add(5, "other", "a", "querier-1", now)
+	add(6, "tenant", "", "querier-1", now)
+
+	require.Equal(t, []RunningQuery{
+		{
+			ID:                "b",
+			Tenant:            "tenant",
+			Frontend:          "frontend",
+			Running:           1,
+			Queriers:          []string{"querier-1"},
+			OldestEnqueueTime: now.Add(-2 * time.Minute),
+		},
+		{
+			ID:                "a",
+			Tenant:            "tenant",
+			Frontend:          "frontend",
+			Queued:            1,
+			Running:           2,
+			Queriers:          []string{"querier-1", "querier-2"},
+			OldestEnqueueTime: now.Add(-time.Minute),
+		},
+	}, s.RunningQueries("tenant"))
+
+	require.Equal(t, 0, s.CancelRunningQuery("tenant", "unknown"))
+	require.Equal(t, 3, s.CancelRunningQuery("tenant", "a"))
+	for _, req := range []*schedulerRequest{a1, a2, a3} {
+		require.Error(t, req.ctx.Err())
+	}
+	require.NoError(t, other.ctx.Err())
+}
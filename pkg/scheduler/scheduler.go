@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"net/textproto"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -76,7 +77,7 @@ type Scheduler struct {
 	// scheduler metrics.
 	connectedQuerierClients  prometheus.GaugeFunc
 	connectedFrontendClients prometheus.GaugeFunc
-	queueDuration            prometheus.Histogram
+	queueDuration            *prometheus.HistogramVec
 	schedulerRunning         prometheus.Gauge
 	inflightRequests         prometheus.Summary
 
@@ -106,6 +107,8 @@ type Config struct {
 	MaxOutstandingPerTenant int               `yaml:"max_outstanding_requests_per_tenant"`
 	MaxQueueHierarchyLevels int               `yaml:"max_queue_hierarchy_levels"`
 	QuerierForgetDelay      time.Duration     `yaml:"querier_forget_delay"`
+	CostWindow              time.Duration     `yaml:"cost_window"`
+	PriorityPolicy          string            `yaml:"priority_policy"`
 	GRPCClientConfig        grpcclient.Config `yaml:"grpc_client_config" doc:"description=This configures the gRPC client used to report errors back to the query-frontend."`
 	// Schedulers ring
 	UseSchedulerRing bool                `yaml:"use_scheduler_ring"`
@@ -116,6 +119,8 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.IntVar(&cfg.MaxOutstandingPerTenant, "query-scheduler.max-outstanding-requests-per-tenant", 32000, "Maximum number of outstanding requests per tenant per query-scheduler. In-flight requests above this limit will fail with HTTP response status code 429.")
 	f.IntVar(&cfg.MaxQueueHierarchyLevels, "query-scheduler.max-queue-hierarchy-levels", 3, "Maximum number of levels of nesting of hierarchical queues. 0 means that hierarchical queues are disabled.")
 	f.DurationVar(&cfg.QuerierForgetDelay, "query-scheduler.querier-forget-delay", 0, "If a querier disconnects without sending notification about graceful shutdown, the query-scheduler will keep the querier in the tenant's shard until the forget delay has passed. This feature is useful to reduce the blast radius when shuffle-sharding is enabled.")
+	f.DurationVar(&cfg.CostWindow, "query-scheduler.cost-window", 0, "If set, requests are dequeued weighted fairly by the cost of the queries of each tenant instead of round-robin between tenants. The cost of a request is estimated from the index stats of its shards, and the cost consumed by each tenant decays over this window. Tenant weights are configured with the query_scheduler_tenant_weight limit. 0 means round-robin between tenants.")
+	f.StringVar(&cfg.PriorityPolicy, "query-scheduler.priority-policy", string(queue.PriorityPolicyStrict), "How the priority classes (high, normal, low) of the requests of a tenant preempt each other. With 'strict', requests are always dequeued from the highest priority class with pending requests. With 'weighted', the classes are dequeued in a weighted round-robin fashion (4:2:1), so that lower priority requests can't be starved. The priority class of a request is set with the X-Loki-Query-Priority header; queries of the ruler are of high priority by default.")
 	cfg.GRPCClientConfig.RegisterFlagsWithPrefix("query-scheduler.grpc-client-config", f)
 	f.BoolVar(&cfg.UseSchedulerRing, "query-scheduler.use-scheduler-ring", false, "Set to true to have the query schedulers create and place themselves in a ring. If no frontend_address or scheduler_address are present anywhere else in the configuration, Loki will toggle this value to true.")
 
@@ -130,6 +135,9 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 }
 
 func (cfg *Config) Validate() error {
+	if err := queue.PriorityPolicy(cfg.PriorityPolicy).Validate(); err != nil {
+		return err
+	}
 	if cfg.SchedulerRing.NumTokens != NumTokens {
 		return errors.New("Num tokens must not be changed as it will not take effect")
 	}
@@ -162,12 +170,17 @@ func NewScheduler(cfg Config, schedulerLimits Limits, log log.Logger, ringManage
 		requestQueue:       queue.NewRequestQueue(cfg.MaxOutstandingPerTenant, cfg.QuerierForgetDelay, limits.NewQueueLimits(schedulerLimits), queueMetrics),
 	}
 
-	s.queueDuration = promauto.With(registerer).NewHistogram(prometheus.HistogramOpts{
+	if cfg.CostWindow > 0 {
+		s.requestQueue.EnableCostFairness(cfg.CostWindow)
+	}
+	s.requestQueue.SetPriorityPolicy(queue.PriorityPolicy(cfg.PriorityPolicy))
+
+	s.queueDuration = promauto.With(registerer).NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: metricsNamespace,
 		Name:      "query_scheduler_queue_duration_seconds",
 		Help:      "Time spend by requests in queue before getting picked up by a querier.",
 		Buckets:   prometheus.DefBuckets,
-	})
+	}, []string{"priority"})
 	s.connectedQuerierClients = promauto.With(registerer).NewGaugeFunc(prometheus.GaugeOpts{
 		Namespace: metricsNamespace,
 		Name:      "query_scheduler_connected_querier_clients",
@@ -224,6 +237,12 @@ type schedulerRequest struct {
 	request         *httpgrpc.HTTPRequest
 	queryRequest    *queryrange.QueryRequest
 	statsEnabled    bool
+	cost            int64
+	priority        queue.Priority
+	// runningQueryID is the ID of the query in the query-frontend the request is a sub-query of.
+	runningQueryID string
+	// querierID is the querier the request has been dispatched to, if any.
+	querierID string
 
 	queueTime time.Time
 
@@ -235,6 +254,56 @@ type schedulerRequest struct {
 	parentSpanContext opentracing.SpanContext
 }
 
+// Cost returns the estimated cost in bytes of the request, or 0 if unknown.
+func (r *schedulerRequest) Cost() int64 {
+	return r.cost
+}
+
+// Priority returns the priority class of the request.
+func (r *schedulerRequest) Priority() queue.Priority {
+	return r.priority
+}
+
+// requestHeader returns the value of the header of the request sent by the query-frontend.
+func requestHeader(msg *schedulerpb.FrontendToScheduler, name string) string {
+	if req := msg.GetQueryRequest(); req != nil {
+		return req.Metadata[name]
+	}
+	if req := msg.GetHttpRequest(); req != nil {
+		for _, h := range req.Headers {
+			if textproto.CanonicalMIMEHeaderKey(h.Key) == textproto.CanonicalMIMEHeaderKey(name) && len(h.Values) > 0 {
+				return h.Values[0]
+			}
+		}
+	}
+	return ""
+}
+
+// requestCost returns the cost estimated by the query-frontend for the request.
+func requestCost(msg *schedulerpb.FrontendToScheduler) int64 {
+	cost, err := strconv.ParseInt(requestHeader(msg, lokihttpreq.LokiQueryCostHeader), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return cost
+}
+
+// requestPriority returns the priority class of the request. The priority class is either set explicitly
+// with the X-Loki-Query-Priority header, or derived from the query tags: queries of the ruler are of high priority.
+func requestPriority(msg *schedulerpb.FrontendToScheduler) queue.Priority {
+	if value := requestHeader(msg, lokihttpreq.LokiQueryPriorityHeader); value != "" {
+		if p, err := queue.ParsePriority(strings.ToLower(value)); err == nil {
+			return p
+		}
+	}
+	for _, tag := range strings.Split(requestHeader(msg, string(lokihttpreq.QueryTagsHTTPHeader)), ",") {
+		if k, v, ok := strings.Cut(tag, "="); ok && strings.EqualFold(strings.TrimSpace(k), "source") && strings.EqualFold(strings.TrimSpace(v), "ruler") {
+			return queue.PriorityHigh
+		}
+	}
+	return queue.PriorityNormal
+}
+
 // FrontendLoop handles connection from frontend.
 func (s *Scheduler) FrontendLoop(frontend schedulerpb.SchedulerForFrontend_FrontendLoopServer) error {
 	frontendAddress, frontendCtx, err := s.frontendConnected(frontend)
@@ -367,6 +436,9 @@ func (s *Scheduler) enqueueRequest(frontendContext context.Context, frontendAddr
 		request:         msg.GetHttpRequest(),
 		queryRequest:    msg.GetQueryRequest(),
 		statsEnabled:    msg.StatsEnabled,
+		cost:            requestCost(msg),
+		priority:        requestPriority(msg),
+		runningQueryID:  requestHeader(msg, lokihttpreq.LokiQueryIDHeader),
 	}
 
 	now := time.Now()
@@ -446,7 +518,7 @@ func (s *Scheduler) QuerierLoop(querier schedulerpb.SchedulerForQuerier_QuerierL
 		r := req.(*schedulerRequest)
 
 		reqQueueTime := time.Since(r.queueTime)
-		s.queueDuration.Observe(reqQueueTime.Seconds())
+		s.queueDuration.WithLabelValues(r.priority.String()).Observe(reqQueueTime.Seconds())
 		r.queueSpan.Finish()
 
 		// Add HTTP header to the request containing the query queue time
@@ -475,12 +547,19 @@ func (s *Scheduler) QuerierLoop(querier schedulerpb.SchedulerForQuerier_QuerierL
 		if r.ctx.Err() != nil {
 			// Remove from pending requests.
 			s.cancelRequestAndRemoveFromPending(r.frontendAddress, r.queryID)
+			s.requestQueue.FinishRequest(r.tenantID, r)
 
 			lastIndex = lastIndex.ReuseLastIndex()
 			continue
 		}
 
-		if err := s.forwardRequestToQuerier(querier, r); err != nil {
+		s.pendingRequestsMu.Lock()
+		r.querierID = querierID
+		s.pendingRequestsMu.Unlock()
+
+		err = s.forwardRequestToQuerier(querier, r)
+		s.requestQueue.FinishRequest(r.tenantID, r)
+		if err != nil {
 			return err
 		}
 	}
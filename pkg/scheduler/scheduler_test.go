@@ -12,7 +12,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc/metadata"
 
+	"github.com/grafana/loki/v3/pkg/querier/queryrange"
+	"github.com/grafana/loki/v3/pkg/queue"
 	"github.com/grafana/loki/v3/pkg/scheduler/schedulerpb"
+	lokihttpreq "github.com/grafana/loki/v3/pkg/util/httpreq"
 	util_log "github.com/grafana/loki/v3/pkg/util/log"
 )
 
@@ -149,3 +152,92 @@ func (m mockSchedulerForFrontendFrontendLoopServer) SendMsg(_ interface{}) error
 func (m mockSchedulerForFrontendFrontendLoopServer) RecvMsg(_ interface{}) error {
 	panic("implement me")
 }
+
+func TestRequestCost(t *testing.T) {
+	for name, tc := range map[string]struct {
+		msg      *schedulerpb.FrontendToScheduler
+		expected int64
+	}{
+		"query request": {
+			msg: &schedulerpb.FrontendToScheduler{
+				Request: &schedulerpb.FrontendToScheduler_QueryRequest{QueryRequest: &queryrange.QueryRequest{
+					Metadata: map[string]string{lokihttpreq.LokiQueryCostHeader: "1024"},
+				}},
+			},
+			expected: 1024,
+		},
+		"http request": {
+			msg: &schedulerpb.FrontendToScheduler{
+				Request: &schedulerpb.FrontendToScheduler_HttpRequest{HttpRequest: &httpgrpc.HTTPRequest{
+					Headers: []*httpgrpc.Header{{Key: lokihttpreq.LokiQueryCostHeader, Values: []string{"2048"}}},
+				}},
+			},
+			expected: 2048,
+		},
+		"invalid cost": {
+			msg: &schedulerpb.FrontendToScheduler{
+				Request: &schedulerpb.FrontendToScheduler_QueryRequest{QueryRequest: &queryrange.QueryRequest{
+					Metadata: map[string]string{lokihttpreq.LokiQueryCostHeader: "foo"},
+				}},
+			},
+		},
+		"no cost": {
+			msg: &schedulerpb.FrontendToScheduler{},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, requestCost(tc.msg))
+		})
+	}
+}
+
+func TestRequestPriority(t *testing.T) {
+	queryRequest := func(metadata map[string]string) *schedulerpb.FrontendToScheduler {
+		return &schedulerpb.FrontendToScheduler{
+			Request: &schedulerpb.FrontendToScheduler_QueryRequest{QueryRequest: &queryrange.QueryRequest{Metadata: metadata}},
+		}
+	}
+
+	for name, tc := range map[string]struct {
+		msg      *schedulerpb.FrontendToScheduler
+		expected queue.Priority
+	}{
+		"explicit priority": {
+			msg:      queryRequest(map[string]string{lokihttpreq.LokiQueryPriorityHeader: "low"}),
+			expected: queue.PriorityLow,
+		},
+		"explicit priority in http request": {
+			msg: &schedulerpb.FrontendToScheduler{
+				Request: &schedulerpb.FrontendToScheduler_HttpRequest{HttpRequest: &httpgrpc.HTTPRequest{
+					Headers: []*httpgrpc.Header{{Key: lokihttpreq.LokiQueryPriorityHeader, Values: []string{"High"}}},
+				}},
+			},
+			expected: queue.PriorityHigh,
+		},
+		"ruler query": {
+			msg:      queryRequest(map[string]string{string(lokihttpreq.QueryTagsHTTPHeader): "source=ruler"}),
+			expected: queue.PriorityHigh,
+		},
+		"explicit priority overrides query tags": {
+			msg: queryRequest(map[string]string{
+				string(lokihttpreq.QueryTagsHTTPHeader): "Source=ruler",
+				lokihttpreq.LokiQueryPriorityHeader:     "normal",
+			}),
+			expected: queue.PriorityNormal,
+		},
+		"invalid priority": {
+			msg:      queryRequest(map[string]string{lokihttpreq.LokiQueryPriorityHeader: "urgent"}),
+			expected: queue.PriorityNormal,
+		},
+		"no priority": {
+			msg:      queryRequest(map[string]string{string(lokihttpreq.QueryTagsHTTPHeader): "source=grafana"}),
+			expected: queue.PriorityNormal,
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, requestPriority(tc.msg))
+		})
+	}
+}
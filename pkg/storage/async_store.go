@@ -26,7 +26,8 @@ import (
 )
 
 type IngesterQuerier interface {
-	GetChunkIDs(ctx context.Context, from, through model.Time, matchers ...*labels.Matcher) ([]string, error)
+	// GetChunkIDs returns the IDs of the chunks flushed by the ingesters, and the refs of the ones stored in segment objects.
+	GetChunkIDs(ctx context.Context, from, through model.Time, matchers ...*labels.Matcher) ([]string, []*logproto.ChunkRef, error)
 	Stats(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) (*stats.Stats, error)
 	Volume(ctx context.Context, userID string, from, through model.Time, limit int32, targetLabels []string, aggregateBy string, matchers ...*labels.Matcher) (*logproto.VolumeResponse, error)
 }
@@ -76,7 +77,10 @@ func (a *AsyncStore) GetChunks(ctx context.Context, userID string, from, through
 		errs <- err
 	}()
 
-	var ingesterChunks []string
+	var (
+		ingesterChunks      []string
+		ingesterSegmentRefs []*logproto.ChunkRef
+	)
 
 	go func() {
 		if !a.shouldQueryIngesters(through, model.Now()) {
@@ -86,7 +90,7 @@ func (a *AsyncStore) GetChunks(ctx context.Context, userID string, from, through
 		}
 
 		var err error
-		ingesterChunks, err = a.ingesterQuerier.GetChunkIDs(ctx, from, through, predicate.Matchers...)
+		ingesterChunks, ingesterSegmentRefs, err = a.ingesterQuerier.GetChunkIDs(ctx, from, through, predicate.Matchers...)
 
 		if err == nil {
 			level.Debug(spanLogger).Log("ingester-chunks-count", len(ingesterChunks))
@@ -106,7 +110,7 @@ func (a *AsyncStore) GetChunks(ctx context.Context, userID string, from, through
 		return storeChunks, fetchers, nil
 	}
 
-	return a.mergeIngesterAndStoreChunks(userID, storeChunks, fetchers, ingesterChunks)
+	return a.mergeIngesterAndStoreChunks(userID, storeChunks, fetchers, ingesterChunks, ingesterSegmentRefs)
 }
 
 func (a *AsyncStore) Stats(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) (*stats.Stats, error) {
@@ -221,10 +225,16 @@ func (a *AsyncStore) Volume(ctx context.Context, userID string, from, through mo
 	return merged, nil
 }
 
-func (a *AsyncStore) mergeIngesterAndStoreChunks(userID string, storeChunks [][]chunk.Chunk, fetchers []*fetcher.Fetcher, ingesterChunkIDs []string) ([][]chunk.Chunk, []*fetcher.Fetcher, error) {
+func (a *AsyncStore) mergeIngesterAndStoreChunks(userID string, storeChunks [][]chunk.Chunk, fetchers []*fetcher.Fetcher, ingesterChunkIDs []string, ingesterSegmentRefs []*logproto.ChunkRef) ([][]chunk.Chunk, []*fetcher.Fetcher, error) {
 	ingesterChunkIDs = filterDuplicateChunks(a.scfg, storeChunks, ingesterChunkIDs)
 	level.Debug(util_log.Logger).Log("msg", "post-filtering ingester chunks", "count", len(ingesterChunkIDs))
 
+	// chunks stored in segment objects can only be read with the segment they are stored in.
+	segmentRefs := make(map[string]*logproto.ChunkRef, len(ingesterSegmentRefs))
+	for _, ref := range ingesterSegmentRefs {
+		segmentRefs[a.scfg.ExternalKey(*ref)] = ref
+	}
+
 	fetcherToChunksGroupIdx := make(map[*fetcher.Fetcher]int, len(fetchers))
 
 	for i, fetcher := range fetchers {
@@ -236,6 +246,11 @@ func (a *AsyncStore) mergeIngesterAndStoreChunks(userID string, storeChunks [][]
 		if err != nil {
 			return nil, nil, err
 		}
+		if ref, ok := segmentRefs[chunkID]; ok {
+			chk.SegmentID = ref.SegmentID
+			chk.SegmentOffset = ref.SegmentOffset
+			chk.SegmentLength = ref.SegmentLength
+		}
 
 		// ToDo(Sandeep) possible optimization: Keep the chunk fetcher reference handy after first call since it is expected to stay the same.
 		fetcher := a.Store.GetChunkFetcher(chk.Through)
@@ -58,9 +58,9 @@ func newIngesterQuerierMock() *ingesterQuerierMock {
 	return &ingesterQuerierMock{}
 }
 
-func (i *ingesterQuerierMock) GetChunkIDs(ctx context.Context, from, through model.Time, matchers ...*labels.Matcher) ([]string, error) {
+func (i *ingesterQuerierMock) GetChunkIDs(ctx context.Context, from, through model.Time, matchers ...*labels.Matcher) ([]string, []*logproto.ChunkRef, error) {
 	args := i.Called(ctx, from, through, matchers)
-	return args.Get(0).([]string), args.Error(1)
+	return args.Get(0).([]string), args.Get(1).([]*logproto.ChunkRef), args.Error(2)
 }
 
 func (i *ingesterQuerierMock) Volume(_ context.Context, userID string, from, through model.Time, _ int32, targetLabels []string, _ string, matchers ...*labels.Matcher) (*logproto.VolumeResponse, error) {
@@ -121,6 +121,17 @@ func TestAsyncStore_mergeIngesterAndStoreChunks(t *testing.T) {
 	testChunks := buildMockChunkRef(t, 10)
 	fetchers := buildMockFetchers(3)
 
+	segmentChunks := make([]chunk.Chunk, 0, 5)
+	segmentRefs := make([]*logproto.ChunkRef, 0, 5)
+	for i, chk := range testChunks[5:] {
+		chk.SegmentID = 1
+		chk.SegmentOffset = uint64(i * 100)
+		chk.SegmentLength = 100
+		segmentChunks = append(segmentChunks, chk)
+		ref := chk.ChunkRef
+		segmentRefs = append(segmentRefs, &ref)
+	}
+
 	s := config.SchemaConfig{
 		Configs: []config.PeriodConfig{
 			{
@@ -136,6 +147,7 @@ func TestAsyncStore_mergeIngesterAndStoreChunks(t *testing.T) {
 		storeChunks      [][]chunk.Chunk
 		storeFetcher     []*fetcher.Fetcher
 		ingesterChunkIDs []string
+		ingesterSegments []*logproto.ChunkRef
 		ingesterFetcher  *fetcher.Fetcher
 		expectedChunks   [][]chunk.Chunk
 		expectedFetchers []*fetcher.Fetcher
@@ -230,6 +242,21 @@ func TestAsyncStore_mergeIngesterAndStoreChunks(t *testing.T) {
 			},
 			expectedFetchers: fetchers[0:1],
 		},
+		{
+			name: "chunks stored in segments from ingesters",
+			storeChunks: [][]chunk.Chunk{
+				testChunks[0:5],
+			},
+			storeFetcher:     fetchers[0:1],
+			ingesterChunkIDs: convertChunksToChunkIDs(s, testChunks[5:]),
+			ingesterSegments: segmentRefs,
+			ingesterFetcher:  fetchers[1],
+			expectedChunks: [][]chunk.Chunk{
+				testChunks[0:5],
+				segmentChunks,
+			},
+			expectedFetchers: fetchers[0:2],
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			store := newStoreMock()
@@ -237,7 +264,7 @@ func TestAsyncStore_mergeIngesterAndStoreChunks(t *testing.T) {
 			store.On("GetChunkFetcher", mock.Anything).Return(tc.ingesterFetcher)
 
 			ingesterQuerier := newIngesterQuerierMock()
-			ingesterQuerier.On("GetChunkIDs", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tc.ingesterChunkIDs, nil)
+			ingesterQuerier.On("GetChunkIDs", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tc.ingesterChunkIDs, tc.ingesterSegments, nil)
 
 			asyncStoreCfg := AsyncStoreCfg{IngesterQuerier: ingesterQuerier}
 			asyncStore := NewAsyncStore(asyncStoreCfg, store, config.SchemaConfig{})
@@ -296,7 +323,7 @@ func TestAsyncStore_QueryIngestersWithin(t *testing.T) {
 			store.On("GetChunks", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([][]chunk.Chunk{}, []*fetcher.Fetcher{}, nil)
 
 			ingesterQuerier := newIngesterQuerierMock()
-			ingesterQuerier.On("GetChunkIDs", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]string{}, nil)
+			ingesterQuerier.On("GetChunkIDs", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]string{}, []*logproto.ChunkRef(nil), nil)
 
 			asyncStoreCfg := AsyncStoreCfg{
 				IngesterQuerier:      ingesterQuerier,
@@ -22,10 +22,7 @@ func TestArchive(t *testing.T) {
 
 	builder, err := NewBlockBuilder(
 		BlockOptions{
-			Schema: Schema{
-				version:  DefaultSchemaVersion,
-				encoding: chunkenc.EncSnappy,
-			},
+			Schema:         newSchema(DefaultSchemaVersion, chunkenc.EncSnappy, 0, 0, DefaultFalsePositiveRate),
 			SeriesPageSize: 100,
 			BloomPageSize:  10 << 10,
 		},
@@ -156,8 +156,9 @@ func (bq *BlockQuerier) Next() bool {
 		}
 		bloom := bq.blooms.At()
 		bq.cur = &SeriesWithBloom{
-			Series: &series.Series,
-			Bloom:  bloom,
+			Series:  &series.Series,
+			Bloom:   bloom,
+			Summary: series.Summary,
 		}
 		return true
 	}
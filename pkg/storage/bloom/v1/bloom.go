@@ -204,6 +204,8 @@ func (d *BloomPageDecoder) Err() error {
 
 type BloomPageHeader struct {
 	N, Offset, Len, DecompressedLen int
+	// location of the summary of the page, since V4
+	SummaryOffset, SummaryLen int
 }
 
 func (h *BloomPageHeader) Encode(enc *encoding.Encbuf) {
@@ -221,6 +223,17 @@ func (h *BloomPageHeader) Decode(dec *encoding.Decbuf) error {
 	return dec.Err()
 }
 
+func (h *BloomPageHeader) encodeSummaryRef(enc *encoding.Encbuf) {
+	enc.PutUvarint(h.SummaryOffset)
+	enc.PutUvarint(h.SummaryLen)
+}
+
+func (h *BloomPageHeader) decodeSummaryRef(dec *encoding.Decbuf) error {
+	h.SummaryOffset = dec.Uvarint()
+	h.SummaryLen = dec.Uvarint()
+	return dec.Err()
+}
+
 type BloomBlock struct {
 	schema      Schema
 	pageHeaders []BloomPageHeader
@@ -228,7 +241,7 @@ type BloomBlock struct {
 
 func NewBloomBlock(encoding chunkenc.Encoding) BloomBlock {
 	return BloomBlock{
-		schema: Schema{version: DefaultSchemaVersion, encoding: encoding},
+		schema: newSchema(DefaultSchemaVersion, encoding, 0, 0, DefaultFalsePositiveRate),
 	}
 }
 
@@ -271,6 +284,11 @@ func (b *BloomBlock) DecodeHeaders(r io.ReadSeeker) (uint32, error) {
 		if err := header.Decode(&dec); err != nil {
 			return 0, errors.Wrapf(err, "decoding %dth series header", i)
 		}
+		if b.schema.Summaries() {
+			if err := header.decodeSummaryRef(&dec); err != nil {
+				return 0, errors.Wrapf(err, "decoding %dth series header summary", i)
+			}
+		}
 	}
 	return checksum, nil
 }
@@ -313,3 +331,35 @@ func (b *BloomBlock) BloomPageDecoder(r io.ReadSeeker, pageIdx int, maxPageSize
 	metrics.bytesRead.WithLabelValues(pageTypeBloom).Add(float64(page.DecompressedLen))
 	return res, nil
 }
+
+// PageSummary reads the summary of the raw tokens of all the series of a bloom page.
+// It returns nil if the block doesn't store summaries.
+func (b *BloomBlock) PageSummary(r io.ReadSeeker, pageIdx int, metrics *Metrics) (*SummaryBloom, error) {
+	if !b.schema.Summaries() {
+		return nil, nil
+	}
+	if pageIdx < 0 || pageIdx >= len(b.pageHeaders) {
+		return nil, fmt.Errorf("invalid page (%d) for bloom page summary", pageIdx)
+	}
+
+	page := b.pageHeaders[pageIdx]
+	if _, err := r.Seek(int64(page.SummaryOffset), io.SeekStart); err != nil {
+		return nil, errors.Wrap(err, "seeking to bloom page summary")
+	}
+	data := make([]byte, page.SummaryLen)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, errors.Wrap(err, "reading bloom page summary")
+	}
+
+	dec := encoding.DecWith(data)
+	if err := dec.CheckCrc(castagnoliTable); err != nil {
+		return nil, errors.Wrap(err, "checksumming bloom page summary")
+	}
+	summary := b.schema.NewSummary()
+	if err := summary.Decode(&dec); err != nil {
+		return nil, err
+	}
+
+	metrics.bytesRead.WithLabelValues(pageTypeSummary).Add(float64(page.SummaryLen))
+	return summary, nil
+}
@@ -17,6 +17,11 @@ type LazyBloomIter struct {
 	err          error
 	curPageIndex int
 	curPage      *BloomPageDecoder
+
+	// summary of the last requested page and the last page skipped thanks to its summary, offset by one
+	summaryPage int
+	summary     *SummaryBloom
+	skippedPage int
 }
 
 // NewLazyBloomIter returns a new lazy bloom iterator.
@@ -137,6 +142,38 @@ func (it *LazyBloomIter) At() *Bloom {
 	return it.curPage.At()
 }
 
+// PageSummary returns the summary of the given bloom page, nil if the block doesn't store summaries.
+func (it *LazyBloomIter) PageSummary(page int) (*SummaryBloom, error) {
+	it.ensureInit()
+	if it.err != nil {
+		return nil, it.err
+	}
+	if it.summary != nil && it.summaryPage == page {
+		return it.summary, nil
+	}
+
+	r, err := it.b.reader.Blooms()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting blooms reader")
+	}
+	summary, err := it.b.blooms.PageSummary(r, page, it.b.metrics)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading bloom page summary")
+	}
+	it.summaryPage, it.summary = page, summary
+	return summary, nil
+}
+
+// skipPage records that the given page wasn't loaded because its summary can't match.
+func (it *LazyBloomIter) skipPage(page int) {
+	if it.skippedPage == page+1 || (it.curPage != nil && it.curPageIndex == page) {
+		return
+	}
+	it.skippedPage = page + 1
+	it.b.metrics.pagesSkipped.WithLabelValues(pageTypeBloom, skipReasonSummary).Inc()
+	it.b.metrics.bytesSkipped.WithLabelValues(pageTypeBloom, skipReasonSummary).Add(float64(it.b.blooms.pageHeaders[page].DecompressedLen))
+}
+
 func (it *LazyBloomIter) Err() error {
 	{
 		if it.err != nil {
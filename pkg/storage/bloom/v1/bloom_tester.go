@@ -4,10 +4,12 @@ import (
 	"unicode/utf8"
 
 	"github.com/grafana/regexp"
+	"github.com/prometheus/prometheus/model/labels"
 
 	"github.com/grafana/loki/v3/pkg/logql/log"
 	"github.com/grafana/loki/v3/pkg/logql/log/pattern"
 	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
 	"github.com/grafana/loki/v3/pkg/storage/bloom/v1/filter"
 )
 
@@ -66,6 +68,38 @@ func ExtractTestableLineFilters(expr syntax.Expr) []syntax.LineFilterExpr {
 	return filters
 }
 
+// ExtractTestableLabelFilters extracts the label filters of an expression which can be tested against
+// the structured metadata and stream labels indexed in a bloom filter. This skips any label filter after
+// a stage which can add or modify labels, like a parser or label format expression, since these labels
+// aren't indexed.
+// E.g. For {app="fake"} | trace_id="abc" | json | level="error"
+// this function will return only the label filter for trace_id.
+func ExtractTestableLabelFilters(expr syntax.Expr) []syntax.LabelFilterExpr {
+	if expr == nil {
+		return nil
+	}
+
+	var filters []syntax.LabelFilterExpr
+	var labelsModified bool
+	modifiesLabels := func() { labelsModified = true }
+	visitor := &syntax.DepthFirstTraversal{
+		VisitLabelFilterFn: func(v syntax.RootVisitor, e *syntax.LabelFilterExpr) {
+			if e != nil && !labelsModified {
+				filters = append(filters, *e)
+			}
+		},
+		VisitLabelParserFn:            func(_ syntax.RootVisitor, _ *syntax.LabelParserExpr) { modifiesLabels() },
+		VisitJSONExpressionParserFn:   func(_ syntax.RootVisitor, _ *syntax.JSONExpressionParser) { modifiesLabels() },
+		VisitLogfmtExpressionParserFn: func(_ syntax.RootVisitor, _ *syntax.LogfmtExpressionParser) { modifiesLabels() },
+		VisitLogfmtParserFn:           func(_ syntax.RootVisitor, _ *syntax.LogfmtParserExpr) { modifiesLabels() },
+		VisitLabelFmtFn:               func(_ syntax.RootVisitor, _ *syntax.LabelFmtExpr) { modifiesLabels() },
+		VisitDropLabelsFn:             func(_ syntax.RootVisitor, _ *syntax.DropLabelsExpr) { modifiesLabels() },
+		VisitKeepLabelFn:              func(_ syntax.RootVisitor, _ *syntax.KeepLabelsExpr) { modifiesLabels() },
+	}
+	expr.Accept(visitor)
+	return filters
+}
+
 // FiltersToBloomTest converts a list of line filters to a BloomTest.
 // Note that all the line filters should be testable against a bloom filter.
 // Use ExtractTestableLineFilters to extract testable line filters from an expression.
@@ -102,7 +136,7 @@ func simpleFilterToBloomTest(b NGramBuilder, filter syntax.LineFilter) BloomTest
 	case log.LineMatchEqual:
 		return newStringTest(b, filter.Match)
 	case log.LineMatchRegexp:
-		return MatchAll
+		return newRegexTest(b, filter.Match)
 	case log.LineMatchPattern:
 		return newPatternTest(b, filter.Match)
 	default:
@@ -110,6 +144,59 @@ func simpleFilterToBloomTest(b NGramBuilder, filter syntax.LineFilter) BloomTest
 	}
 }
 
+// LabelFiltersToBloomTest converts a list of label filters to a BloomTest.
+// Only equality matchers on non-empty values can be tested, the structured metadata and
+// stream labels of a chunk being indexed as name=value tokens. Use ExtractTestableLabelFilters
+// to extract testable label filters from an expression.
+// Note that blocks built with a schema older than V2 don't index labels and must not be tested.
+func LabelFiltersToBloomTest(filters ...syntax.LabelFilterExpr) BloomTest {
+	tests := make(BloomTests, 0, len(filters))
+	for _, f := range filters {
+		tests = append(tests, labelFilterToBloomTest(f.LabelFilterer))
+	}
+	return tests
+}
+
+func labelFilterToBloomTest(filter log.LabelFilterer) BloomTest {
+	var matcher *labels.Matcher
+	switch f := filter.(type) {
+	case *log.BinaryLabelFilter:
+		left, right := labelFilterToBloomTest(f.Left), labelFilterToBloomTest(f.Right)
+		if f.And {
+			return BloomTests{left, right}
+		}
+		return newOrTest(left, right)
+	case *log.StringLabelFilter:
+		matcher = f.Matcher
+	case *log.LineFilterLabelFilter:
+		matcher = f.Matcher
+	default:
+		// Numeric, duration and bytes filters convert the label value and can't be tested.
+		return MatchAll
+	}
+
+	// An empty value also matches lines without the label, and the error label is never indexed.
+	if matcher.Type != labels.MatchEqual || matcher.Value == "" || matcher.Name == logqlmodel.ErrorLabel {
+		return MatchAll
+	}
+	return labelTest{token: LabelToken(nil, matcher.Name, matcher.Value)}
+}
+
+// labelTest tests the presence of a name=value pair of structured metadata or stream labels.
+type labelTest struct {
+	token []byte
+}
+
+// Matches implements the BloomTest interface
+func (l labelTest) Matches(bloom filter.Checker) bool {
+	return bloom.Test(l.token)
+}
+
+// MatchesWithPrefixBuf implements the BloomTest interface
+func (l labelTest) MatchesWithPrefixBuf(bloom filter.Checker, buf []byte, prefixLen int) bool {
+	return bloom.Test(append(buf[:prefixLen], l.token...))
+}
+
 type bloomCheckerWrapper struct {
 	bloom filter.Checker
 }
@@ -120,8 +207,9 @@ func (b bloomCheckerWrapper) Test(line []byte, _ bool, _ bool) bool {
 }
 
 // TestRegex implements the log.Checker interface
+// The bloom holds n-grams, so the literals of the regexp can't be tested without tokenizing them, and every
+// regexp matches. Regexp line filters are tested with their n-grams by newRegexTest.
 func (b bloomCheckerWrapper) TestRegex(_ *regexp.Regexp) bool {
-	// We won't support regexes in bloom filters so we just return true
 	return true
 }
 
@@ -291,6 +379,12 @@ func (o orTest) MatchesWithPrefixBuf(bloom filter.Checker, buf []byte, prefixLen
 	return o.left.MatchesWithPrefixBuf(bloom, buf, prefixLen) || o.right.MatchesWithPrefixBuf(bloom, buf, prefixLen)
 }
 
+// newRegexTest tests the literals a line must contain to match the regexp, e.g. `error.*timeout`
+// requires both "error" and "timeout". Regexps without required literals match everything.
+func newRegexTest(b NGramBuilder, expr string) BloomTest {
+	return extractRequiredLiterals(expr).bloomTest(b)
+}
+
 func newPatternTest(b NGramBuilder, match string) BloomTest {
 	lit, err := pattern.ParseLiterals(match)
 	if err != nil {
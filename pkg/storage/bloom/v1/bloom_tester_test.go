@@ -92,9 +92,45 @@ func TestBloomQueryingLogic(t *testing.T) {
 			match: false,
 		},
 		{
-			desc:  "regexp disabled",
+			desc:  "regexp required literals",
+			line:  "request failed with timeout",
+			query: `{app="fake"} |~ "failed.*timeout"`,
+			match: true,
+		},
+		{
+			desc:  "regexp required literal missing",
 			line:  "foobarbaz",
 			query: `{app="fake"} |~ "(aaaaa|bbbbb)bazz"`,
+			match: false,
+		},
+		{
+			desc:  "regexp alternate",
+			line:  "foobarbaz",
+			query: `{app="fake"} |~ "(aaaaa|rbaz)"`,
+			match: true,
+		},
+		{
+			desc:  "regexp expanded character class",
+			line:  "an Error happened",
+			query: `{app="fake"} |~ "[Ee]rror"`,
+			match: true,
+		},
+		{
+			desc:  "regexp case insensitive always match",
+			line:  "foobarbaz",
+			query: `{app="fake"} |~ "(?i)zzzzz"`,
+			match: true,
+		},
+		{
+			desc:  "regexp optional literal always match",
+			line:  "foobarbaz",
+			query: `{app="fake"} |~ "(zzzzz)?bar"`,
+			match: true,
+		},
+		{
+			desc:  "regexp alternate with unsafe branch always match",
+			line:  "foobarbaz",
+			query: `{app="fake"} |~ "zzzzz|.*"`,
 			match: true,
 		},
 	} {
@@ -117,3 +153,104 @@ func TestBloomQueryingLogic(t *testing.T) {
 		})
 	}
 }
+
+func TestLabelFiltersToBloomTest(t *testing.T) {
+	bloom := fakeBloom{
+		string(LabelToken(nil, "trace_id", "abc")),
+		string(LabelToken(nil, "app", "api")),
+	}
+
+	for _, tc := range []struct {
+		desc    string
+		query   string
+		filters int
+		match   bool
+	}{
+		{
+			desc:  "no label filter",
+			query: `{app="fake"} |= "foo"`,
+			match: true,
+		},
+		{
+			desc:    "structured metadata present",
+			query:   `{app="fake"} | trace_id="abc"`,
+			filters: 1,
+			match:   true,
+		},
+		{
+			desc:    "structured metadata missing",
+			query:   `{app="fake"} | trace_id="abd"`,
+			filters: 1,
+			match:   false,
+		},
+		{
+			desc:    "stream label present",
+			query:   `{app="fake"} | app="api"`,
+			filters: 1,
+			match:   true,
+		},
+		{
+			desc:    "and",
+			query:   `{app="fake"} | trace_id="abc" and app="web"`,
+			filters: 1,
+			match:   false,
+		},
+		{
+			desc:    "or",
+			query:   `{app="fake"} | trace_id="abd" or app="api"`,
+			filters: 1,
+			match:   true,
+		},
+		{
+			desc:    "multiple stages",
+			query:   `{app="fake"} | trace_id="abc" | app="web"`,
+			filters: 2,
+			match:   false,
+		},
+		{
+			desc:    "not equal always match",
+			query:   `{app="fake"} | trace_id!="abc"`,
+			filters: 1,
+			match:   true,
+		},
+		{
+			desc:    "regexp always match",
+			query:   `{app="fake"} | trace_id=~"zz.*"`,
+			filters: 1,
+			match:   true,
+		},
+		{
+			desc:    "empty value always match",
+			query:   `{app="fake"} | trace_id=""`,
+			filters: 1,
+			match:   true,
+		},
+		{
+			desc:    "numeric filter always match",
+			query:   `{app="fake"} | status > 400`,
+			filters: 1,
+			match:   true,
+		},
+		{
+			desc:    "filters after parser are not testable",
+			query:   `{app="fake"} | trace_id="abc" | json | trace_id="abd"`,
+			filters: 1,
+			match:   true,
+		},
+		{
+			desc:    "filters after label format are not testable",
+			query:   `{app="fake"} | label_format trace_id="abd" | trace_id="abd"`,
+			filters: 0,
+			match:   true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			expr, err := syntax.ParseExpr(tc.query)
+			require.NoError(t, err)
+			filters := ExtractTestableLabelFilters(expr)
+			require.Len(t, filters, tc.filters)
+			require.Equal(t, tc.match, LabelFiltersToBloomTest(filters...).Matches(bloom))
+		})
+	}
+}
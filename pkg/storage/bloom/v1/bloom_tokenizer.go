@@ -7,6 +7,7 @@ import (
 
 	"github.com/go-kit/log/level"
 	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
 
 	"github.com/grafana/dskit/multierror"
 
@@ -82,10 +83,26 @@ func prefixedToken(ngram int, chk ChunkRef, buf []byte) ([]byte, int) {
 	return enc.Get(), prefixLn
 }
 
+// labelTokenMarker starts label tokens, it can't be part of the UTF-8 encoded n-grams of a line.
+const labelTokenMarker = 0xff
+
+// LabelToken appends the token of a label name and value pair to the buffer.
+// Structured metadata and stream labels are indexed with these tokens so that
+// label filters like `| trace_id="abc"` can be tested against blooms.
+func LabelToken(buf []byte, name, value string) []byte {
+	buf = append(buf, labelTokenMarker)
+	buf = append(buf, name...)
+	buf = append(buf, '=')
+	return append(buf, value...)
+}
+
 // ChunkRefWithIter is a wrapper around a ChunkRef and an EntryIterator.
 type ChunkRefWithIter struct {
 	Ref ChunkRef
 	Itr iter.EntryIterator
+	// StreamLabels are indexed along the structured metadata of the entries so that label
+	// filters on stream labels don't remove the chunk.
+	StreamLabels labels.Labels
 }
 
 // Populate adds the tokens from the given chunks to the given seriesWithBloom.
@@ -96,6 +113,7 @@ func (bt *BloomTokenizer) Populate(swb *SeriesWithBloom, chks Iterator[ChunkRefW
 
 	var (
 		tokenBuf []byte
+		labelBuf []byte
 		prefixLn int
 		// TODO(owen-d): slightly more efficient to expose the
 		// UncompressedSize() method on the chunk interface and use that
@@ -117,13 +135,51 @@ func (bt *BloomTokenizer) Populate(swb *SeriesWithBloom, chks Iterator[ChunkRefW
 		)
 		tokenBuf, prefixLn = prefixedToken(bt.lineTokenizer.N(), chk.Ref, tokenBuf)
 
+		// insertLabel indexes a label pair, once raw and once prefixed by the chunk ref like the line tokens.
+		insertLabel := func(name, value string) {
+			labelBuf = LabelToken(labelBuf[:0], name, value)
+			tokens++
+			switch cached, collision := bt.insert(swb, labelBuf); {
+			case cached:
+				cachedInserts++
+			case collision:
+				collisionInserts++
+				bt.summarize(swb, labelBuf)
+			default:
+				successfulInserts++
+				bt.summarize(swb, labelBuf)
+			}
+
+			tokenBuf = append(tokenBuf[:prefixLn], labelBuf...)
+			tokens++
+			switch cached, collision := bt.insert(swb, tokenBuf); {
+			case cached:
+				chunkCachedInserts++
+			case collision:
+				chunkCollisionInserts++
+			default:
+				chunkSuccessfulInserts++
+			}
+		}
+
+		for _, l := range chk.StreamLabels {
+			if l.Name != labels.MetricName {
+				insertLabel(l.Name, l.Value)
+			}
+		}
+
 		// Iterate over lines in the chunk
 		for itr.Next() && itr.Error() == nil {
 			// TODO(owen-d): rather than iterate over the line twice, once for prefixed tokenizer & once for
 			// raw tokenizer, we could iterate once and just return (prefix, token) pairs from the tokenizer.
 			// Double points for them being different-ln references to the same data.
-			line := itr.Entry().Line
+			entry := itr.Entry()
+			line := entry.Line
 			sourceBytes += len(line)
+			for _, l := range entry.StructuredMetadata {
+				insertLabel(l.Name, l.Value)
+			}
+
 			chunkTokenizer := NewPrefixedTokenIter(tokenBuf, prefixLn, bt.lineTokenizer.Tokens(line))
 			for chunkTokenizer.Next() {
 				tok := chunkTokenizer.At()
@@ -167,6 +223,7 @@ func (bt *BloomTokenizer) Populate(swb *SeriesWithBloom, chks Iterator[ChunkRefW
 				} else {
 					chunkSuccessfulInserts++
 				}
+				bt.summarize(swb, tok)
 
 				if len(bt.cache) >= cacheSize { // While crude, this has proven efficient in performance testing.  This speaks to the similarity in log lines near each other
 					clearCache(bt.cache)
@@ -213,6 +270,28 @@ func (bt *BloomTokenizer) Populate(swb *SeriesWithBloom, chks Iterator[ChunkRefW
 	return sourceBytes, nil
 }
 
+// summarize adds a raw token to the summary of the series, if any.
+func (bt *BloomTokenizer) summarize(swb *SeriesWithBloom, tok []byte) {
+	if swb.Summary != nil {
+		swb.Summary.Add(tok)
+	}
+}
+
+// insert adds the token to the bloom unless it was recently added.
+func (bt *BloomTokenizer) insert(swb *SeriesWithBloom, tok []byte) (cached, collision bool) {
+	str := string(tok)
+	if _, found := bt.cache[str]; found {
+		return true, false
+	}
+	bt.cache[str] = nil
+
+	collision = swb.Bloom.ScalableBloomFilter.TestAndAdd(tok)
+	if len(bt.cache) >= cacheSize {
+		clearCache(bt.cache)
+	}
+	return false, collision
+}
+
 // n ≈ −m ln(1 − p).
 func estimatedCount(m uint, p float64) uint {
 	return uint(-float64(m) * math.Log(1-p))
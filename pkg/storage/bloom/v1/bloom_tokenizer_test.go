@@ -121,8 +121,9 @@ func TestTokenizerPopulate(t *testing.T) {
 		Fingerprint: model.Fingerprint(lbsList[0].Hash()),
 	}
 	swb := SeriesWithBloom{
-		Bloom:  &bloom,
-		Series: &series,
+		Bloom:   &bloom,
+		Series:  &series,
+		Summary: NewSummaryBloom(DefaultSummaryBits, DefaultSummaryHashes),
 	}
 
 	_, err = bt.Populate(&swb, NewSliceIter([]ChunkRefWithIter{{Ref: ChunkRef{}, Itr: itr}}))
@@ -132,6 +133,55 @@ func TestTokenizerPopulate(t *testing.T) {
 	for toks.Next() {
 		token := toks.At()
 		require.True(t, swb.Bloom.Test(token))
+		require.True(t, swb.Summary.Test(token))
+	}
+	require.False(t, swb.Summary.Test([]byte("missing")))
+}
+
+func TestTokenizerPopulateLabels(t *testing.T) {
+	t.Parallel()
+	bt := NewBloomTokenizer(DefaultNGramLength, DefaultNGramSkip, metrics)
+
+	memChunk := chunkenc.NewMemChunk(chunkenc.ChunkFormatV4, chunkenc.EncSnappy, chunkenc.ChunkHeadFormatFor(chunkenc.ChunkFormatV4), 256000, 1500000)
+	_ = memChunk.Append(&push.Entry{
+		Timestamp:          time.Unix(0, 1),
+		Line:               "this is a log line",
+		StructuredMetadata: push.LabelsAdapter{{Name: "trace_id", Value: "abc"}},
+	})
+	itr, err := memChunk.Iterator(
+		context.Background(),
+		time.Unix(0, 0),
+		time.Unix(0, math.MaxInt64),
+		logproto.FORWARD,
+		log.NewNoopPipeline().ForStream(nil),
+	)
+	require.Nil(t, err)
+
+	swb := SeriesWithBloom{
+		Bloom:  &Bloom{ScalableBloomFilter: *filter.NewScalableBloomFilter(1024, 0.01, 0.8)},
+		Series: &Series{},
+	}
+	ref := ChunkRef{From: 1, Through: 2, Checksum: 3}
+	_, err = bt.Populate(&swb, NewSliceIter([]ChunkRefWithIter{{
+		Ref:          ref,
+		Itr:          itr,
+		StreamLabels: labels.FromStrings(labels.MetricName, "logs", "app", "api"),
+	}}))
+	require.NoError(t, err)
+
+	prefix, prefixLn := prefixedToken(DefaultNGramLength, ref, nil)
+	for _, tc := range []struct {
+		name, value string
+		indexed     bool
+	}{
+		{name: "trace_id", value: "abc", indexed: true},
+		{name: "app", value: "api", indexed: true},
+		{name: labels.MetricName, value: "logs"},
+		{name: "trace_id", value: "abd"},
+	} {
+		tok := LabelToken(nil, tc.name, tc.value)
+		require.Equal(t, tc.indexed, swb.Bloom.Test(tok), tok)
+		require.Equal(t, tc.indexed, swb.Bloom.Test(append(prefix[:prefixLn], tok...)), tok)
 	}
 }
 
@@ -10,12 +10,11 @@ import (
 	"github.com/prometheus/common/model"
 
 	"github.com/grafana/loki/v3/pkg/chunkenc"
-	"github.com/grafana/loki/v3/pkg/storage/bloom/v1/filter"
 	"github.com/grafana/loki/v3/pkg/util/encoding"
 )
 
 var (
-	DefaultBlockOptions = NewBlockOptions(0, 4, 1, 50<<20) // EncNone, 50MB
+	DefaultBlockOptions = NewBlockOptions(0, 4, 1, 50<<20, DefaultFalsePositiveRate) // EncNone, 50MB
 )
 
 type BlockOptions struct {
@@ -38,17 +37,17 @@ func (b BlockOptions) Len() int {
 }
 
 func (b *BlockOptions) DecodeFrom(r io.ReadSeeker) error {
-	buf := make([]byte, b.Len())
+	if err := b.Schema.DecodeFrom(r); err != nil {
+		return errors.Wrap(err, "decoding schema")
+	}
+
+	buf := make([]byte, 3*8)
 	_, err := io.ReadFull(r, buf)
 	if err != nil {
 		return errors.Wrap(err, "reading block options")
 	}
 
 	dec := encoding.DecWith(buf)
-
-	if err := b.Schema.Decode(&dec); err != nil {
-		return errors.Wrap(err, "decoding schema")
-	}
 	b.SeriesPageSize = dec.Be64()
 	b.BloomPageSize = dec.Be64()
 	b.BlockSize = dec.Be64()
@@ -70,13 +69,8 @@ type BlockBuilder struct {
 	blooms *BloomBlockBuilder
 }
 
-func NewBlockOptions(enc chunkenc.Encoding, NGramLength, NGramSkip, MaxBlockSizeBytes uint64) BlockOptions {
-	opts := NewBlockOptionsFromSchema(Schema{
-		version:     byte(1),
-		encoding:    enc,
-		nGramLength: NGramLength,
-		nGramSkip:   NGramSkip,
-	})
+func NewBlockOptions(enc chunkenc.Encoding, NGramLength, NGramSkip, MaxBlockSizeBytes uint64, FalsePositiveRate float64) BlockOptions {
+	opts := NewBlockOptionsFromSchema(newSchema(DefaultSchemaVersion, enc, NGramLength, NGramSkip, FalsePositiveRate))
 	opts.BlockSize = MaxBlockSizeBytes
 	return opts
 }
@@ -111,6 +105,9 @@ func NewBlockBuilder(opts BlockOptions, writer BlockWriter) (*BlockBuilder, erro
 type SeriesWithBloom struct {
 	Series *Series
 	Bloom  *Bloom
+	// Summary holds the raw tokens of the series, it is stored in the index and merged into the summary of its
+	// bloom page. It is nil for schemas prior to V4 and for series whose tokens are unknown.
+	Summary *SummaryBloom
 }
 
 func (b *BlockBuilder) BuildFrom(itr Iterator[SeriesWithBloom]) (uint32, error) {
@@ -151,8 +148,9 @@ func (b *BlockBuilder) AddSeries(series SeriesWithBloom) (bool, error) {
 	}
 
 	if err := b.index.Append(SeriesWithOffset{
-		Offset: offset,
-		Series: *series.Series,
+		Offset:  offset,
+		Series:  *series.Series,
+		Summary: series.Summary,
 	}); err != nil {
 		return false, errors.Wrapf(err, "writing index for series %v", series.Series.Fingerprint)
 	}
@@ -187,6 +185,7 @@ type BloomBlockBuilder struct {
 	writtenSchema bool
 	pages         []BloomPageHeader
 	page          PageWriter
+	pageSummary   *SummaryBloom // union of the summaries of the series of the current page
 	scratch       *encoding.Encbuf
 }
 
@@ -228,6 +227,15 @@ func (b *BloomBlockBuilder) Append(series SeriesWithBloom) (BloomOffset, error)
 		}
 	}
 
+	if b.opts.Schema.Summaries() {
+		if b.pageSummary == nil {
+			b.pageSummary = b.opts.Schema.NewSummary()
+		}
+		if err := b.pageSummary.Merge(series.Summary); err != nil {
+			return BloomOffset{}, errors.Wrapf(err, "summarizing bloom for %v", series.Series.Fingerprint)
+		}
+	}
+
 	return BloomOffset{
 		Page:       len(b.pages),
 		ByteOffset: b.page.Add(b.scratch.Get()),
@@ -245,6 +253,9 @@ func (b *BloomBlockBuilder) Close() (uint32, error) {
 	b.scratch.PutUvarint(len(b.pages))
 	for _, h := range b.pages {
 		h.Encode(b.scratch)
+		if b.opts.Schema.Summaries() {
+			h.encodeSummaryRef(b.scratch)
+		}
 	}
 	// put offset to beginning of header section
 	// cannot be varint encoded because it's offset will be calculated as
@@ -280,9 +291,27 @@ func (b *BloomBlockBuilder) flushPage() error {
 		Len:             compressedLen,
 		DecompressedLen: decompressedLen,
 	}
-	b.pages = append(b.pages, header)
 	b.offset += compressedLen
 	b.page.Reset()
+
+	if b.opts.Schema.Summaries() {
+		// the page summary follows the page, so pages can be skipped without reading them.
+		// The scratch buffer may hold the series being appended.
+		var enc encoding.Encbuf
+		b.pageSummary.shrink(b.pageSummary.m)
+		b.pageSummary.Encode(&enc)
+		crc32Hash.Reset()
+		enc.PutHash(crc32Hash)
+		if _, err := b.writer.Write(enc.Get()); err != nil {
+			return errors.Wrap(err, "writing bloom page summary")
+		}
+		header.SummaryOffset = b.offset
+		header.SummaryLen = enc.Len()
+		b.offset += enc.Len()
+		b.pageSummary = nil
+	}
+
+	b.pages = append(b.pages, header)
 	return nil
 }
 
@@ -393,11 +422,30 @@ func (b *IndexBuilder) Append(series SeriesWithOffset) error {
 		}
 	}
 
+	// the summary of the series follows it, shrunk to its tokens. A missing summary matches everything.
+	var summary *SummaryBloom
+	if b.opts.Schema.Summaries() {
+		summary = &SummaryBloom{all: true}
+		if series.Summary != nil {
+			// shrinking replaces the content of the copy, the summary of the page may still use the original
+			cpy := *series.Summary
+			summary = &cpy
+			summary.shrink(seriesSummaryMaxBits)
+		}
+	}
+	encode := func() (model.Fingerprint, BloomOffset) {
+		fp, offset := series.Encode(b.scratch, b.previousFp, b.previousOffset)
+		if summary != nil {
+			summary.Encode(b.scratch)
+		}
+		return fp, offset
+	}
+
 	b.scratch.Reset()
 	// we don't want to update the previous pointers yet in case
 	// we need to flush the page first which would
 	// be passed the incorrect final fp/offset
-	previousFp, previousOffset := series.Encode(b.scratch, b.previousFp, b.previousOffset)
+	previousFp, previousOffset := encode()
 
 	if !b.page.SpaceFor(b.scratch.Len()) {
 		if err := b.flushPage(); err != nil {
@@ -406,7 +454,7 @@ func (b *IndexBuilder) Append(series SeriesWithOffset) error {
 
 		// re-encode now that a new page has been cut and we use delta-encoding
 		b.scratch.Reset()
-		previousFp, previousOffset = series.Encode(b.scratch, b.previousFp, b.previousOffset)
+		previousFp, previousOffset = encode()
 	}
 	b.previousFp = previousFp
 	b.previousOffset = previousOffset
@@ -526,7 +574,7 @@ type MergeBuilder struct {
 	// store
 	store Iterator[*Series]
 	// Add chunks to a bloom
-	populate func(*Series, *Bloom) (int, error)
+	populate func(*Series, *Bloom, *SummaryBloom) (int, error)
 	metrics  *Metrics
 }
 
@@ -537,7 +585,7 @@ type MergeBuilder struct {
 func NewMergeBuilder(
 	blocks Iterator[*SeriesWithBloom],
 	store Iterator[*Series],
-	populate func(*Series, *Bloom) (int, error),
+	populate func(*Series, *Bloom, *SummaryBloom) (int, error),
 	metrics *Metrics,
 ) *MergeBuilder {
 	return &MergeBuilder{
@@ -599,14 +647,23 @@ func (mb *MergeBuilder) processNextSeries(
 	// in its entirety
 	if nextInBlocks == nil || nextInBlocks.Series.Fingerprint > nextInStore.Fingerprint {
 		cur = &SeriesWithBloom{
-			Series: nextInStore,
-			Bloom: &Bloom{
-				// TODO parameterise SBF options. fp_rate
-				ScalableBloomFilter: *filter.NewScalableBloomFilter(1024, 0.01, 0.8),
-			},
+			Series:  nextInStore,
+			Bloom:   builder.opts.Schema.NewBloom(),
+			Summary: builder.opts.Schema.NewSummary(),
 		}
 	} else {
-		// if the series already exists in the block, we only need to add the new chunks
+		// if the series already exists in the block, we only need to add the new chunks.
+		// Its tokens are only known through the summary of its page in the block, which covers them.
+		cur = &SeriesWithBloom{
+			Series:  nextInBlocks.Series,
+			Bloom:   nextInBlocks.Bloom,
+			Summary: builder.opts.Schema.NewSummary(),
+		}
+		if cur.Summary != nil {
+			if err := cur.Summary.Merge(nextInBlocks.Summary); err != nil {
+				return nil, 0, false, false, errors.Wrapf(err, "summarizing series with fingerprint: %v", nextInStore.Fingerprint)
+			}
+		}
 		chunksToAdd = nextInStore.Chunks.Unless(nextInBlocks.Series.Chunks)
 		chunksCopied += len(nextInStore.Chunks) - len(chunksToAdd)
 	}
@@ -620,6 +677,7 @@ func (mb *MergeBuilder) processNextSeries(
 				Chunks:      chunksToAdd,
 			},
 			cur.Bloom,
+			cur.Summary,
 		)
 		bytesAdded += sourceBytes
 
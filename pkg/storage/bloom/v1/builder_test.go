@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/loki/v3/pkg/chunkenc"
+	"github.com/grafana/loki/v3/pkg/storage/bloom/v1/filter"
 	"github.com/grafana/loki/v3/pkg/util/encoding"
 )
 
@@ -24,26 +25,43 @@ var blockEncodings = []chunkenc.Encoding{
 
 func TestBlockOptionsRoundTrip(t *testing.T) {
 	t.Parallel()
-	opts := BlockOptions{
-		Schema: Schema{
-			version:     V1,
-			encoding:    chunkenc.EncSnappy,
-			nGramLength: 10,
-			nGramSkip:   2,
-		},
-		SeriesPageSize: 100,
-		BloomPageSize:  10 << 10,
-		BlockSize:      10 << 20,
-	}
+	for _, schema := range []Schema{
+		// blocks prior to V3 are decoded with the default filter parameters
+		newSchema(V1, chunkenc.EncSnappy, 10, 2, DefaultFalsePositiveRate),
+		newSchema(V2, chunkenc.EncSnappy, 10, 2, DefaultFalsePositiveRate),
+		newSchema(V3, chunkenc.EncSnappy, 10, 2, 0.05),
+	} {
+		t.Run(schema.String(), func(t *testing.T) {
+			opts := BlockOptions{
+				Schema:         schema,
+				SeriesPageSize: 100,
+				BloomPageSize:  10 << 10,
+				BlockSize:      10 << 20,
+			}
 
-	var enc encoding.Encbuf
-	opts.Encode(&enc)
+			var enc encoding.Encbuf
+			opts.Encode(&enc)
+			require.Equal(t, opts.Len(), enc.Len())
 
-	var got BlockOptions
-	err := got.DecodeFrom(bytes.NewReader(enc.Get()))
-	require.Nil(t, err)
+			var got BlockOptions
+			err := got.DecodeFrom(bytes.NewReader(enc.Get()))
+			require.Nil(t, err)
+
+			require.Equal(t, opts, got)
+		})
+	}
+}
+
+func TestSchemaNewBloom(t *testing.T) {
+	t.Parallel()
+	for _, fpRate := range []float64{0.01, 0.05} {
+		schema := newSchema(DefaultSchemaVersion, chunkenc.EncNone, 4, 1, fpRate)
+		require.Equal(t, fpRate, schema.FalsePositiveRate())
+		require.Equal(t, filter.NewScalableBloomFilter(DefaultFilterHint, fpRate, DefaultFilterTighteningRatio), &schema.NewBloom().ScalableBloomFilter)
+	}
 
-	require.Equal(t, opts, got)
+	// Blocks with different filter parameters can't be merged.
+	require.False(t, newSchema(V3, chunkenc.EncNone, 4, 1, 0.01).Compatible(newSchema(V3, chunkenc.EncNone, 4, 1, 0.05)))
 }
 
 func TestBlockBuilder_RoundTrip(t *testing.T) {
@@ -87,12 +105,7 @@ func TestBlockBuilder_RoundTrip(t *testing.T) {
 			desc := fmt.Sprintf("%s/%s", tc.desc, enc)
 			t.Run(desc, func(t *testing.T) {
 				blockOpts := BlockOptions{
-					Schema: Schema{
-						version:     DefaultSchemaVersion,
-						encoding:    enc,
-						nGramLength: 10,
-						nGramSkip:   2,
-					},
+					Schema:         newSchema(DefaultSchemaVersion, enc, 10, 2, DefaultFalsePositiveRate),
 					SeriesPageSize: 100,
 					BloomPageSize:  10 << 10,
 					BlockSize:      tc.maxBlockSize,
@@ -186,10 +199,7 @@ func TestMergeBuilder(t *testing.T) {
 	blocks := make([]PeekingIterator[*SeriesWithBloom], 0, nBlocks)
 	data, _ := MkBasicSeriesWithBlooms(numSeries, numKeysPerSeries, 0, 0xffff, 0, 10000)
 	blockOpts := BlockOptions{
-		Schema: Schema{
-			version:  DefaultSchemaVersion,
-			encoding: chunkenc.EncSnappy,
-		},
+		Schema:         newSchema(DefaultSchemaVersion, chunkenc.EncSnappy, 0, 0, DefaultFalsePositiveRate),
 		SeriesPageSize: 100,
 		BloomPageSize:  10 << 10,
 	}
@@ -222,7 +232,7 @@ func TestMergeBuilder(t *testing.T) {
 	}
 
 	// We're not testing the ability to extend a bloom in this test
-	pop := func(_ *Series, _ *Bloom) (int, error) {
+	pop := func(_ *Series, _ *Bloom, _ *SummaryBloom) (int, error) {
 		return 0, errors.New("not implemented")
 	}
 
@@ -275,12 +285,7 @@ func TestBlockReset(t *testing.T) {
 	writer := NewMemoryBlockWriter(indexBuf, bloomsBuf)
 	reader := NewByteReader(indexBuf, bloomsBuf)
 
-	schema := Schema{
-		version:     DefaultSchemaVersion,
-		encoding:    chunkenc.EncSnappy,
-		nGramLength: 10,
-		nGramSkip:   2,
-	}
+	schema := newSchema(DefaultSchemaVersion, chunkenc.EncSnappy, 10, 2, DefaultFalsePositiveRate)
 
 	builder, err := NewBlockBuilder(
 		BlockOptions{
@@ -334,12 +339,7 @@ func TestMergeBuilder_Roundtrip(t *testing.T) {
 	}
 
 	blockOpts := BlockOptions{
-		Schema: Schema{
-			version:     DefaultSchemaVersion,
-			encoding:    chunkenc.EncSnappy, // test with different encodings?
-			nGramLength: 4,                  // needs to match values from MkBasicSeriesWithBlooms
-			nGramSkip:   0,                  // needs to match values from MkBasicSeriesWithBlooms
-		},
+		Schema:         newSchema(DefaultSchemaVersion, chunkenc.EncSnappy, 4, 0, DefaultFalsePositiveRate),
 		SeriesPageSize: 100,
 		BloomPageSize:  10 << 10,
 	}
@@ -408,7 +408,7 @@ func TestMergeBuilder_Roundtrip(t *testing.T) {
 	mb := NewMergeBuilder(
 		dedupedBlocks(blocks),
 		dedupedStore,
-		func(s *Series, b *Bloom) (int, error) {
+		func(_ *Series, _ *Bloom, _ *SummaryBloom) (int, error) {
 			// We're not actually indexing new data in this test
 			return 0, nil
 		},
@@ -419,7 +419,7 @@ func TestMergeBuilder_Roundtrip(t *testing.T) {
 
 	checksum, _, err := mb.Build(builder)
 	require.Nil(t, err)
-	require.Equal(t, uint32(0xc7b4210b), checksum)
+	require.Equal(t, uint32(0xf83b0bf), checksum)
 
 	// ensure the new block contains one copy of all the data
 	// by comparing it against an iterator over the source data
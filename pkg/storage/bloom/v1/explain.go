@@ -0,0 +1,121 @@
+package v1
+
+import (
+	"fmt"
+
+	"github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+// FilterEligibility describes whether a line or label filter of a query can be tested against bloom filters.
+type FilterEligibility struct {
+	Filter   string `json:"filter"`
+	Testable bool   `json:"testable"`
+	// Reason explains why the filter can't be tested.
+	Reason string `json:"reason,omitempty"`
+}
+
+// ExplainFilters returns the bloom eligibility of every line and label filter of an expression, in order.
+// It follows the rules of ExtractTestableLineFilters, ExtractTestableLabelFilters and of the conversion to
+// bloom tests, so that users can rewrite their queries to be bloom-friendly.
+func ExplainFilters(b NGramBuilder, expr syntax.Expr) []FilterEligibility {
+	if expr == nil {
+		return nil
+	}
+
+	var res []FilterEligibility
+	var lineFmtFound, labelsModified bool
+	modifiesLabels := func() { labelsModified = true }
+	visitor := &syntax.DepthFirstTraversal{
+		VisitLineFilterFn: func(_ syntax.RootVisitor, e *syntax.LineFilterExpr) {
+			// Chained line filters are nested from right to left.
+			var chain []syntax.LineFilterExpr
+			for curr := e; curr != nil; curr = curr.Left {
+				chain = append(chain, syntax.LineFilterExpr{LineFilter: curr.LineFilter, Or: curr.Or})
+			}
+			for i := len(chain) - 1; i >= 0; i-- {
+				res = append(res, explainLineFilter(b, chain[i], lineFmtFound))
+			}
+		},
+		VisitLineFmtFn: func(_ syntax.RootVisitor, _ *syntax.LineFmtExpr) { lineFmtFound = true },
+		VisitLabelFilterFn: func(_ syntax.RootVisitor, e *syntax.LabelFilterExpr) {
+			res = append(res, explainLabelFilter(*e, labelsModified))
+		},
+		VisitLabelParserFn:            func(_ syntax.RootVisitor, _ *syntax.LabelParserExpr) { modifiesLabels() },
+		VisitJSONExpressionParserFn:   func(_ syntax.RootVisitor, _ *syntax.JSONExpressionParser) { modifiesLabels() },
+		VisitLogfmtExpressionParserFn: func(_ syntax.RootVisitor, _ *syntax.LogfmtExpressionParser) { modifiesLabels() },
+		VisitLogfmtParserFn:           func(_ syntax.RootVisitor, _ *syntax.LogfmtParserExpr) { modifiesLabels() },
+		VisitLabelFmtFn:               func(_ syntax.RootVisitor, _ *syntax.LabelFmtExpr) { modifiesLabels() },
+		VisitDropLabelsFn:             func(_ syntax.RootVisitor, _ *syntax.DropLabelsExpr) { modifiesLabels() },
+		VisitKeepLabelFn:              func(_ syntax.RootVisitor, _ *syntax.KeepLabelsExpr) { modifiesLabels() },
+	}
+	expr.Accept(visitor)
+	return res
+}
+
+// CountTestableFilters returns the number of filters of the explanation which can be tested against blooms.
+func CountTestableFilters(filters []FilterEligibility) (n int) {
+	for _, f := range filters {
+		if f.Testable {
+			n++
+		}
+	}
+	return n
+}
+
+func explainLineFilter(b NGramBuilder, f syntax.LineFilterExpr, afterLineFmt bool) FilterEligibility {
+	res := FilterEligibility{Filter: f.String()}
+	switch {
+	case afterLineFmt:
+		res.Reason = "follows a line_format stage"
+	case f.Ty == log.LineMatchNotEqual || f.Ty == log.LineMatchNotRegexp || f.Ty == log.LineMatchNotPattern:
+		res.Reason = "negated filters can't be tested"
+	case matchesAll(FiltersToBloomTest(b, f)):
+		minLen := b.N() + b.SkipFactor()
+		switch {
+		case f.Or != nil:
+			res.Reason = fmt.Sprintf("not every alternative contains a literal of at least %d characters", minLen)
+		case f.Ty == log.LineMatchEqual:
+			res.Reason = fmt.Sprintf("shorter than %d characters", minLen)
+		default:
+			res.Reason = fmt.Sprintf("no required literal of at least %d characters", minLen)
+		}
+	default:
+		res.Testable = true
+	}
+	return res
+}
+
+func explainLabelFilter(f syntax.LabelFilterExpr, afterLabelsModified bool) FilterEligibility {
+	res := FilterEligibility{Filter: f.String()}
+	switch {
+	case afterLabelsModified:
+		res.Reason = "follows a parser or a stage which modifies labels"
+	case matchesAll(labelFilterToBloomTest(f.LabelFilterer)):
+		res.Reason = "only equality matchers on non-empty values can be tested"
+	default:
+		res.Testable = true
+	}
+	return res
+}
+
+// matchesAll returns whether the test can't rule out any bloom.
+func matchesAll(test BloomTest) bool {
+	switch t := test.(type) {
+	case matchAllTest:
+		return true
+	case BloomTests:
+		for _, sub := range t {
+			if !matchesAll(sub) {
+				return false
+			}
+		}
+		return true
+	case orTest:
+		return matchesAll(t.left) || matchesAll(t.right)
+	case stringTest:
+		return len(t.ngrams) == 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+func TestExplainFilters(t *testing.T) {
+	tokenizer := NewNGramTokenizer(4, 1)
+
+	for _, tc := range []struct {
+		desc     string
+		query    string
+		expected []FilterEligibility
+	}{
+		{
+			desc:  "no filters",
+			query: `{app="fake"}`,
+		},
+		{
+			desc:  "chained line filters",
+			query: `{app="fake"} |= "foobar" != "bazqux" |= "ab"`,
+			expected: []FilterEligibility{
+				{Filter: `|= "foobar"`, Testable: true},
+				{Filter: `!= "bazqux"`, Reason: "negated filters can't be tested"},
+				{Filter: `|= "ab"`, Reason: "shorter than 5 characters"},
+			},
+		},
+		{
+			desc:  "regexp and or filters",
+			query: `{app="fake"} |~ "error.*timeout" |~ ".*" |= "foobar" or "ab"`,
+			expected: []FilterEligibility{
+				{Filter: `|~ "error.*timeout"`, Testable: true},
+				{Filter: `|~ ".*"`, Reason: "no required literal of at least 5 characters"},
+				{Filter: `|= "foobar" or "ab"`, Reason: "not every alternative contains a literal of at least 5 characters"},
+			},
+		},
+		{
+			desc:  "line filter after line_format",
+			query: `{app="fake"} |= "foobar" | line_format "{{.msg}}" |= "bazqux"`,
+			expected: []FilterEligibility{
+				{Filter: `|= "foobar"`, Testable: true},
+				{Filter: `|= "bazqux"`, Reason: "follows a line_format stage"},
+			},
+		},
+		{
+			desc:  "label filters",
+			query: `{app="fake"} | trace_id="abc" | level=~"err.*" | json | user="bob"`,
+			expected: []FilterEligibility{
+				{Filter: `| trace_id="abc"`, Testable: true},
+				{Filter: `| level=~"err.*"`, Reason: "only equality matchers on non-empty values can be tested"},
+				{Filter: `| user="bob"`, Reason: "follows a parser or a stage which modifies labels"},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			expr, err := syntax.ParseExpr(tc.query)
+			require.NoError(t, err)
+			filters := ExplainFilters(tokenizer, expr)
+			require.Equal(t, tc.expected, filters)
+
+			var testable int
+			for _, f := range tc.expected {
+				if f.Testable {
+					testable++
+				}
+			}
+			require.Equal(t, testable, CountTestableFilters(filters))
+		})
+	}
+}
@@ -93,6 +93,17 @@ func (fq *FusedQuerier) Run() error {
 			}
 		}
 
+		// Skip loading the bloom of the series if the requests can't match the summary of the series, which is
+		// stored in the index, or the summary of its bloom page
+		if schema.Summaries() {
+			if nextBatch = filterBySummary(series, series.Summary, nextBatch); len(nextBatch) == 0 {
+				continue
+			}
+			if nextBatch = fq.filterByPageSummary(series, nextBatch); len(nextBatch) == 0 {
+				continue
+			}
+		}
+
 		// Now that we've found the series, we need to find the unpack the bloom
 		fq.bq.blooms.Seek(series.Offset)
 		if !fq.bq.blooms.Next() {
@@ -151,3 +162,41 @@ func (fq *FusedQuerier) Run() error {
 
 	return nil
 }
+
+// filterByPageSummary responds to the requests which can't match the summary of the bloom page of the series
+// and returns the remaining ones. All requests remain if the summary can't be read.
+func (fq *FusedQuerier) filterByPageSummary(series *SeriesWithOffset, batch []Request) []Request {
+	summary, err := fq.bq.blooms.PageSummary(series.Offset.Page)
+	if err != nil || summary == nil {
+		level.Debug(fq.logger).Log("msg", "failed to read bloom page summary", "page", series.Offset.Page, "err", err)
+		return batch
+	}
+
+	remaining := filterBySummary(series, summary, batch)
+	if len(remaining) == 0 {
+		fq.bq.blooms.skipPage(series.Offset.Page)
+	}
+	return remaining
+}
+
+// filterBySummary responds to the requests which can't match the summary and returns the remaining ones.
+// A nil summary is unknown and all requests remain.
+func filterBySummary(series *SeriesWithOffset, summary *SummaryBloom, batch []Request) []Request {
+	if summary == nil {
+		return batch
+	}
+
+	remaining := make([]Request, 0, len(batch))
+	for _, input := range batch {
+		if input.Search.Matches(summary) {
+			remaining = append(remaining, input)
+			continue
+		}
+		_, inBlooms := input.Chks.Compare(series.Chunks, true)
+		input.Response <- Output{
+			Fp:       series.Fingerprint,
+			Removals: inBlooms,
+		}
+	}
+	return remaining
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-kit/log"
 	"github.com/grafana/dskit/concurrency"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/loki/v3/pkg/chunkenc"
@@ -46,10 +47,7 @@ func TestFusedQuerier(t *testing.T) {
 
 	builder, err := NewBlockBuilder(
 		BlockOptions{
-			Schema: Schema{
-				version:  DefaultSchemaVersion,
-				encoding: chunkenc.EncSnappy,
-			},
+			Schema:         newSchema(DefaultSchemaVersion, chunkenc.EncSnappy, 0, 0, DefaultFalsePositiveRate),
 			SeriesPageSize: 100,
 			BloomPageSize:  10 << 10,
 		},
@@ -130,6 +128,81 @@ func TestFusedQuerier(t *testing.T) {
 	}
 }
 
+func TestFusedQuerier_SkipsBloomsBySummary(t *testing.T) {
+	indexBuf := bytes.NewBuffer(nil)
+	bloomsBuf := bytes.NewBuffer(nil)
+	writer := NewMemoryBlockWriter(indexBuf, bloomsBuf)
+	reader := NewByteReader(indexBuf, bloomsBuf)
+	numSeries := 100
+	data, keys := MkBasicSeriesWithBlooms(numSeries, 0, 0x0000, 0xffff, 0, 10000)
+
+	builder, err := NewBlockBuilder(
+		BlockOptions{
+			Schema:         newSchema(DefaultSchemaVersion, chunkenc.EncSnappy, 0, 0, DefaultFalsePositiveRate),
+			SeriesPageSize: 100,
+			BloomPageSize:  10 << 10,
+		},
+		writer,
+	)
+	require.Nil(t, err)
+	_, err = builder.BuildFrom(NewSliceIter[SeriesWithBloom](data))
+	require.NoError(t, err)
+	metrics := NewMetrics(nil)
+	block := NewBlock(reader, metrics)
+	querier := NewBlockQuerier(block, true, DefaultMaxPageSize)
+
+	// every other series is searched for a key it contains, the others for a missing key
+	ch := make(chan Output, numSeries)
+	reqs := make([]Request, 0, numSeries)
+	for i, d := range data {
+		search := keysToBloomTest([][]byte{[]byte("missing")})
+		if i%20 == 0 {
+			search = keysToBloomTest(keys[i][:1])
+		}
+		reqs = append(reqs, Request{
+			Fp:       d.Series.Fingerprint,
+			Chks:     d.Series.Chunks,
+			Response: ch,
+			Search:   search,
+		})
+	}
+
+	fused := querier.Fuse([]PeekingIterator[Request]{NewPeekingIter[Request](NewSliceIter[Request](reqs))}, log.NewNopLogger())
+	require.NoError(t, fused.Run())
+	close(ch)
+
+	var i int
+	for resp := range ch {
+		expected := Output{Fp: data[i].Series.Fingerprint, Removals: data[i].Series.Chunks}
+		if i%20 == 0 {
+			expected.Removals = nil
+		}
+		require.Equal(t, expected, resp)
+		i++
+	}
+	require.Equal(t, numSeries, i)
+
+	// the series searched for a missing key are answered by their summaries in the index, so only the bloom
+	// pages of the other series are loaded
+	skipped := testutil.ToFloat64(metrics.pagesSkipped.WithLabelValues(pageTypeBloom, skipReasonSummary))
+	read := testutil.ToFloat64(metrics.pagesRead.WithLabelValues(pageTypeBloom))
+	require.Greater(t, read, float64(0))
+	require.Less(t, skipped+read, float64(len(block.blooms.pageHeaders)))
+
+	// the summaries of the series are read back from the index
+	querier = NewBlockQuerier(NewBlock(reader, NewMetrics(nil)), true, DefaultMaxPageSize)
+	for i := range data {
+		require.True(t, querier.Next())
+		summary := querier.At().Summary
+		require.NotNil(t, summary)
+		for _, key := range keys[i] {
+			require.True(t, summary.Test(key))
+		}
+	}
+	require.False(t, querier.Next())
+	require.NoError(t, querier.Err())
+}
+
 func setupBlockForBenchmark(b *testing.B) (*BlockQuerier, [][]Request, []chan Output) {
 	indexBuf := bytes.NewBuffer(nil)
 	bloomsBuf := bytes.NewBuffer(nil)
@@ -141,10 +214,7 @@ func setupBlockForBenchmark(b *testing.B) (*BlockQuerier, [][]Request, []chan Ou
 
 	builder, err := NewBlockBuilder(
 		BlockOptions{
-			Schema: Schema{
-				version:  DefaultSchemaVersion,
-				encoding: chunkenc.EncSnappy,
-			},
+			Schema:         newSchema(DefaultSchemaVersion, chunkenc.EncSnappy, 0, 0, DefaultFalsePositiveRate),
 			SeriesPageSize: 256 << 10, // 256k
 			BloomPageSize:  1 << 20,   // 1MB
 		},
@@ -10,7 +10,7 @@ import (
 	"github.com/prometheus/common/model"
 
 	"github.com/grafana/loki/v3/pkg/chunkenc"
-	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/storage/bloom/v1/filter"
 	"github.com/grafana/loki/v3/pkg/util/encoding"
 )
 
@@ -18,16 +18,77 @@ type Schema struct {
 	version                byte
 	encoding               chunkenc.Encoding
 	nGramLength, nGramSkip uint64
+
+	// parameters of the scalable bloom filters, encoded since V3
+	hashing         hashing
+	filterHint      uint64
+	fpRate          float64
+	tighteningRatio float64
+
+	// size and number of hashes of the summary blooms, encoded since V4
+	summaryBits, summaryHashes uint32
+}
+
+// hashing identifies the hash function the bloom filters of a block are built with.
+type hashing byte
+
+const (
+	hashingFNV64 hashing = iota + 1
+)
+
+// Defaults of the scalable bloom filters, used by blocks prior to V3 which don't encode them.
+const (
+	DefaultFilterHint            = 1024
+	DefaultFalsePositiveRate     = 0.01
+	DefaultFilterTighteningRatio = 0.8
+)
+
+// Defaults of the summary blooms. The bits are the maximum size of a page summary, which is shrunk to about 10 bits
+// per token: up to about 52k tokens per page keep a false positive rate of at most 1.2%, 100k tokens give 8% and pages with
+// more than about 145k tokens, above 20%, store no summary and are always read. Series summaries are limited to 8KiB,
+// which covers series with up to about 18k tokens.
+const (
+	DefaultSummaryBits   = 1 << 19 // 64KiB
+	DefaultSummaryHashes = 4
+)
+
+func newSchema(version byte, enc chunkenc.Encoding, nGramLength, nGramSkip uint64, fpRate float64) Schema {
+	return Schema{
+		version:         version,
+		encoding:        enc,
+		nGramLength:     nGramLength,
+		nGramSkip:       nGramSkip,
+		hashing:         hashingFNV64,
+		filterHint:      DefaultFilterHint,
+		fpRate:          fpRate,
+		tighteningRatio: DefaultFilterTighteningRatio,
+		summaryBits:     DefaultSummaryBits,
+		summaryHashes:   DefaultSummaryHashes,
+	}
 }
 
 func (s Schema) String() string {
-	return fmt.Sprintf("v%d,encoding=%s,ngram=%d,skip=%d", s.version, s.encoding, s.nGramLength, s.nGramSkip)
+	return fmt.Sprintf(
+		"v%d,encoding=%s,ngram=%d,skip=%d,hashing=%d,hint=%d,fp=%g,ratio=%g,summary=%d/%d",
+		s.version, s.encoding, s.nGramLength, s.nGramSkip, s.hashing, s.filterHint, s.fpRate, s.tighteningRatio,
+		s.summaryBits, s.summaryHashes,
+	)
 }
 
 func (s Schema) Compatible(other Schema) bool {
 	return s == other
 }
 
+func (s Schema) Version() byte {
+	return s.version
+}
+
+// IndexesLabels returns whether the structured metadata and stream labels of chunks are indexed,
+// in which case label filters can be tested against the blooms.
+func (s Schema) IndexesLabels() bool {
+	return s.version >= V2
+}
+
 func (s Schema) NGramLen() int {
 	return int(s.nGramLength)
 }
@@ -36,10 +97,50 @@ func (s Schema) NGramSkip() int {
 	return int(s.nGramSkip)
 }
 
+func (s Schema) FalsePositiveRate() float64 {
+	return s.fpRate
+}
+
+// NewBloom returns an empty bloom built with the filter parameters of the schema.
+func (s Schema) NewBloom() *Bloom {
+	return &Bloom{
+		ScalableBloomFilter: *filter.NewScalableBloomFilter(uint(s.filterHint), s.fpRate, s.tighteningRatio),
+	}
+}
+
+// Summaries returns whether the block stores summary blooms of its series and bloom pages.
+func (s Schema) Summaries() bool {
+	return s.version >= V4
+}
+
+// NewSummary returns an empty summary bloom, or nil if the schema doesn't store summaries.
+func (s Schema) NewSummary() *SummaryBloom {
+	if !s.Summaries() {
+		return nil
+	}
+	return NewSummaryBloom(s.summaryBits, s.summaryHashes)
+}
+
 // byte length
 func (s Schema) Len() int {
-	// magic number + version + encoding + ngram length + ngram skip
-	return 4 + 1 + 1 + 8 + 8
+	return schemaHeaderLen + s.bodyLen()
+}
+
+// magic number + version
+const schemaHeaderLen = 4 + 1
+
+func (s Schema) bodyLen() int {
+	// encoding + ngram length + ngram skip
+	n := 1 + 8 + 8
+	if s.version >= V3 {
+		// hashing + filter hint + false positive rate + tightening ratio
+		n += 1 + 8 + 8 + 8
+	}
+	if s.version >= V4 {
+		// summary bits + summary hashes
+		n += 4 + 4
+	}
+	return n
 }
 
 func (s *Schema) DecompressorPool() chunkenc.ReaderPool {
@@ -57,18 +158,31 @@ func (s *Schema) Encode(enc *encoding.Encbuf) {
 	enc.PutByte(byte(s.encoding))
 	enc.PutBE64(s.nGramLength)
 	enc.PutBE64(s.nGramSkip)
-
+	if s.version >= V3 {
+		enc.PutByte(byte(s.hashing))
+		enc.PutBE64(s.filterHint)
+		enc.PutBEFloat64(s.fpRate)
+		enc.PutBEFloat64(s.tighteningRatio)
+	}
+	if s.version >= V4 {
+		enc.PutBE32(s.summaryBits)
+		enc.PutBE32(s.summaryHashes)
+	}
 }
 
 func (s *Schema) DecodeFrom(r io.ReadSeeker) error {
+	// The length of the schema depends on its version, which follows the magic number.
 	// TODO(owen-d): improve allocations
-	schemaBytes := make([]byte, s.Len())
-	_, err := io.ReadFull(r, schemaBytes)
-	if err != nil {
+	schemaBytes := make([]byte, schemaHeaderLen)
+	if _, err := io.ReadFull(r, schemaBytes); err != nil {
+		return errors.Wrap(err, "reading schema")
+	}
+	body := make([]byte, Schema{version: schemaBytes[schemaHeaderLen-1]}.bodyLen())
+	if _, err := io.ReadFull(r, body); err != nil {
 		return errors.Wrap(err, "reading schema")
 	}
 
-	dec := encoding.DecWith(schemaBytes)
+	dec := encoding.DecWith(append(schemaBytes, body...))
 	return s.Decode(&dec)
 }
 
@@ -78,8 +192,8 @@ func (s *Schema) Decode(dec *encoding.Decbuf) error {
 		return errors.Errorf("invalid magic number. expected %x, got  %x", magicNumber, number)
 	}
 	s.version = dec.Byte()
-	if s.version != 1 {
-		return errors.Errorf("invalid version. expected %d, got %d", 1, s.version)
+	if s.version < V1 || s.version > V4 {
+		return errors.Errorf("invalid version. expected %d to %d, got %d", V1, V4, s.version)
 	}
 
 	s.encoding = chunkenc.Encoding(dec.Byte())
@@ -90,6 +204,36 @@ func (s *Schema) Decode(dec *encoding.Decbuf) error {
 	s.nGramLength = dec.Be64()
 	s.nGramSkip = dec.Be64()
 
+	if s.version < V3 {
+		s.hashing = hashingFNV64
+		s.filterHint = DefaultFilterHint
+		s.fpRate = DefaultFalsePositiveRate
+		s.tighteningRatio = DefaultFilterTighteningRatio
+		s.summaryBits = DefaultSummaryBits
+		s.summaryHashes = DefaultSummaryHashes
+		return dec.Err()
+	}
+
+	s.hashing = hashing(dec.Byte())
+	if s.hashing != hashingFNV64 {
+		return errors.Errorf("invalid hashing strategy %d", s.hashing)
+	}
+	s.filterHint = dec.Be64()
+	s.fpRate = dec.Be64Float64()
+	s.tighteningRatio = dec.Be64Float64()
+
+	if s.version < V4 {
+		s.summaryBits = DefaultSummaryBits
+		s.summaryHashes = DefaultSummaryHashes
+		return dec.Err()
+	}
+
+	s.summaryBits = dec.Be32()
+	s.summaryHashes = dec.Be32()
+	if s.summaryBits == 0 || s.summaryHashes == 0 {
+		return errors.Errorf("invalid summary size %d with %d hashes", s.summaryBits, s.summaryHashes)
+	}
+
 	return dec.Err()
 }
 
@@ -193,6 +337,9 @@ func (b *BlockIndex) NewSeriesPageDecoder(r io.ReadSeeker, header SeriesPageHead
 		data:   decompressed,
 		header: header.SeriesHeader,
 	}
+	if b.opts.Schema.Summaries() {
+		res.summaryBits, res.summaryHashes = b.opts.Schema.summaryBits, b.opts.Schema.summaryHashes
+	}
 
 	res.Reset()
 	return res, nil
@@ -271,6 +418,9 @@ type SeriesPageDecoder struct {
 	dec    encoding.Decbuf
 	header SeriesHeader
 
+	// size and number of hashes of the summaries following each series, zero if the block doesn't store them
+	summaryBits, summaryHashes uint32
+
 	// state
 	i              int // current index
 	cur            *SeriesWithOffset
@@ -303,6 +453,12 @@ func (d *SeriesPageDecoder) Next() bool {
 	if d.err != nil {
 		return false
 	}
+	if d.summaryBits > 0 {
+		res.Summary = NewSummaryBloom(d.summaryBits, d.summaryHashes)
+		if d.err = res.Summary.Decode(&d.dec); d.err != nil {
+			return false
+		}
+	}
 
 	d.cur = &res
 	return true
@@ -367,6 +523,9 @@ type Series struct {
 type SeriesWithOffset struct {
 	Offset BloomOffset
 	Series
+	// Summary is the summary of the raw tokens of the series, which follows it in the series page since V4.
+	// It is nil for schemas prior to V4.
+	Summary *SummaryBloom
 }
 
 func (s *SeriesWithOffset) Encode(
@@ -411,7 +570,23 @@ func (s *SeriesWithOffset) Decode(dec *encoding.Decbuf, previousFp model.Fingerp
 	return s.Fingerprint, s.Offset, dec.Err()
 }
 
-type ChunkRef logproto.ShortRef
+// ChunkRef identifies a chunk of a series by its time range and checksum.
+type ChunkRef struct {
+	From     model.Time
+	Through  model.Time
+	Checksum uint32
+
+	// The segment the chunk is stored in, if any. See logproto.ChunkRef.
+	// It's only known for refs read from the TSDB index, and isn't encoded in blocks.
+	SegmentID     uint64
+	SegmentOffset uint64
+	SegmentLength uint32
+}
+
+// Equal returns whether the refs identify the same chunk, regardless of where it's stored.
+func (r *ChunkRef) Equal(other ChunkRef) bool {
+	return r.From == other.From && r.Through == other.Through && r.Checksum == other.Checksum
+}
 
 func (r *ChunkRef) Less(other ChunkRef) bool {
 	if r.From != other.From {
@@ -489,7 +664,7 @@ func (refs ChunkRefs) Compare(others ChunkRefs, populateInclusive bool) (exclusi
 	for i < len(refs) && j < len(others) {
 		switch {
 
-		case refs[i] == others[j]:
+		case refs[i].Equal(others[j]):
 			if populateInclusive {
 				inclusive = append(inclusive, refs[i])
 			}
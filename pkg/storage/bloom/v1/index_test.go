@@ -120,6 +120,13 @@ func TestChunkRefCompare(t *testing.T) {
 				{From: 4, Through: 6},
 			},
 		},
+		{
+			desc:      "segments are ignored",
+			left:      ChunkRefs{{From: 1, Through: 2, SegmentID: 3, SegmentOffset: 4, SegmentLength: 5}},
+			right:     ChunkRefs{{From: 1, Through: 2}},
+			exclusive: nil,
+			inclusive: ChunkRefs{{From: 1, Through: 2, SegmentID: 3, SegmentOffset: 4, SegmentLength: 5}},
+		},
 	} {
 		t.Run(tc.desc, func(t *testing.T) {
 			exc, inc := tc.left.Compare(tc.right, true)
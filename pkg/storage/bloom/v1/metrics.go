@@ -34,12 +34,14 @@ const (
 	collisionTypeTrue      = "true"
 	collisionTypeCache     = "cache"
 
-	pageTypeBloom  = "bloom"
-	pageTypeSeries = "series"
+	pageTypeBloom   = "bloom"
+	pageTypeSeries  = "series"
+	pageTypeSummary = "summary"
 
 	skipReasonTooLarge = "too_large"
 	skipReasonErr      = "err"
 	skipReasonOOB      = "out_of_bounds"
+	skipReasonSummary  = "summary"
 )
 
 func NewMetrics(r prometheus.Registerer) *Metrics {
@@ -0,0 +1,344 @@
+package v1
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/grafana/regexp/syntax"
+)
+
+const (
+	// maxClassSize is the maximum number of runes of a character class expanded into alternative literals.
+	maxClassSize = 4
+	// maxLiteralAlternatives bounds the number of alternatives a sequence of literals is expanded to,
+	// e.g. `[Ee]rror (foo|bar)` has 4 alternatives.
+	maxLiteralAlternatives = 16
+	// maxRegexDepth bounds the recursion when analyzing a regexp.
+	maxRegexDepth = 32
+)
+
+type literalsOp int
+
+const (
+	// literalsAny means nothing is known to be required, e.g. `.*` or a case-insensitive literal.
+	literalsAny literalsOp = iota
+	literalsLiteral
+	literalsAnd
+	literalsOr
+)
+
+// requiredLiterals is a tree of the literals a line must contain to match a regexp.
+// For instance `(error|fail).*timeout` requires ("error" OR "fail") AND "timeout".
+type requiredLiterals struct {
+	op      literalsOp
+	literal string
+	subs    []requiredLiterals
+}
+
+var anyLiterals = requiredLiterals{op: literalsAny}
+
+// extractRequiredLiterals analyzes a regexp into the literals required for a line to match it.
+// Constructs which can't be safely reasoned about, like case-insensitive matches, don't require anything.
+func extractRequiredLiterals(expr string) requiredLiterals {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return anyLiterals
+	}
+	return literalsOf(re.Simplify(), 0)
+}
+
+func literalsOf(re *syntax.Regexp, depth int) requiredLiterals {
+	if depth > maxRegexDepth {
+		return anyLiterals
+	}
+
+	switch re.Op {
+	case syntax.OpLiteral:
+		if re.Flags&syntax.FoldCase == 0 {
+			return literalNode(string(re.Rune))
+		}
+		if alternatives, ok := exactStrings(re, depth+1); ok {
+			return alternativesNode(alternatives)
+		}
+	case syntax.OpCapture:
+		return literalsOf(re.Sub[0], depth+1)
+	case syntax.OpPlus:
+		// At least one occurrence of the sub expression is required.
+		return literalsOf(re.Sub[0], depth+1)
+	case syntax.OpRepeat:
+		if re.Min == 0 {
+			return anyLiterals
+		}
+		return literalsOf(re.Sub[0], depth+1)
+	case syntax.OpAlternate:
+		subs := make([]requiredLiterals, 0, len(re.Sub))
+		for _, sub := range re.Sub {
+			subs = append(subs, literalsOf(sub, depth+1))
+		}
+		return orNode(subs)
+	case syntax.OpConcat:
+		return concatLiterals(re.Sub, depth+1)
+	case syntax.OpCharClass:
+		if alternatives, ok := exactStrings(re, depth+1); ok {
+			return alternativesNode(alternatives)
+		}
+	}
+	return anyLiterals
+}
+
+// concatLiterals merges the adjacent literals of a concatenation, expanding small character classes and
+// alternations of literals, so that the longest possible literals are required.
+func concatLiterals(subs []*syntax.Regexp, depth int) requiredLiterals {
+	var (
+		required []requiredLiterals
+		current  []string
+	)
+	flush := func() {
+		if len(current) > 0 {
+			required = append(required, alternativesNode(current))
+			current = nil
+		}
+	}
+
+	for _, sub := range flattenConcat(subs, nil) {
+		// A repeated expression ends the literal before it and starts the one after it,
+		// e.g. `a(foo)+b` requires "afoo" and "foob".
+		if sub.Op == syntax.OpPlus {
+			if alternatives, ok := exactStrings(sub.Sub[0], depth); ok {
+				if len(current) > 0 {
+					if prefixed, ok := crossProduct(current, alternatives); ok {
+						current = prefixed
+					}
+					flush()
+				}
+				current = alternatives
+				continue
+			}
+		}
+
+		if alternatives, ok := exactStrings(sub, depth); ok {
+			if next, ok := crossProduct(current, alternatives); ok {
+				current = next
+				continue
+			}
+			flush()
+			current = alternatives
+			continue
+		}
+
+		flush()
+		required = append(required, literalsOf(sub, depth))
+	}
+	flush()
+
+	return andNode(required)
+}
+
+func flattenConcat(subs []*syntax.Regexp, res []*syntax.Regexp) []*syntax.Regexp {
+	for _, sub := range subs {
+		if sub.Op == syntax.OpConcat {
+			res = flattenConcat(sub.Sub, res)
+			continue
+		}
+		res = append(res, sub)
+	}
+	return res
+}
+
+// exactStrings returns the strings matched by the regexp when it only matches a small number of them.
+func exactStrings(re *syntax.Regexp, depth int) ([]string, bool) {
+	if depth > maxRegexDepth {
+		return nil, false
+	}
+
+	switch re.Op {
+	case syntax.OpLiteral:
+		if re.Flags&syntax.FoldCase == 0 {
+			return []string{string(re.Rune)}, true
+		}
+		// Case-insensitive literals are expanded into all their cases, the parser also
+		// uses them for classes like [Ee].
+		var res []string
+		for _, r := range re.Rune {
+			var ok bool
+			if res, ok = crossProduct(res, caseFolds(r)); !ok {
+				return nil, false
+			}
+		}
+		return res, len(res) > 0
+	case syntax.OpCharClass:
+		// Rune holds the inclusive ranges of the class.
+		var size int
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			size += int(re.Rune[i+1]-re.Rune[i]) + 1
+			if size > maxClassSize {
+				return nil, false
+			}
+		}
+		if size == 0 {
+			return nil, false
+		}
+		res := make([]string, 0, size)
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			for r := re.Rune[i]; r <= re.Rune[i+1]; r++ {
+				res = append(res, string(r))
+			}
+		}
+		return res, true
+	case syntax.OpCapture:
+		return exactStrings(re.Sub[0], depth+1)
+	case syntax.OpAlternate:
+		var res []string
+		for _, sub := range re.Sub {
+			alternatives, ok := exactStrings(sub, depth+1)
+			if !ok || len(res)+len(alternatives) > maxLiteralAlternatives {
+				return nil, false
+			}
+			res = append(res, alternatives...)
+		}
+		return res, true
+	case syntax.OpConcat:
+		var res []string
+		for _, sub := range re.Sub {
+			alternatives, ok := exactStrings(sub, depth+1)
+			if !ok {
+				return nil, false
+			}
+			if res, ok = crossProduct(res, alternatives); !ok {
+				return nil, false
+			}
+		}
+		return res, len(res) > 0
+	}
+	return nil, false
+}
+
+func caseFolds(r rune) []string {
+	res := []string{string(r)}
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		res = append(res, string(f))
+	}
+	return res
+}
+
+func crossProduct(prefixes, suffixes []string) ([]string, bool) {
+	if len(prefixes) == 0 {
+		return suffixes, len(suffixes) <= maxLiteralAlternatives
+	}
+	if len(prefixes)*len(suffixes) > maxLiteralAlternatives {
+		return nil, false
+	}
+	res := make([]string, 0, len(prefixes)*len(suffixes))
+	for _, p := range prefixes {
+		for _, s := range suffixes {
+			res = append(res, p+s)
+		}
+	}
+	return res, true
+}
+
+func literalNode(literal string) requiredLiterals {
+	if literal == "" {
+		return anyLiterals
+	}
+	return requiredLiterals{op: literalsLiteral, literal: literal}
+}
+
+func alternativesNode(alternatives []string) requiredLiterals {
+	subs := make([]requiredLiterals, 0, len(alternatives))
+	for _, a := range alternatives {
+		subs = append(subs, literalNode(a))
+	}
+	return orNode(subs)
+}
+
+// andNode requires all the sub trees, the ones not requiring anything are dropped.
+func andNode(subs []requiredLiterals) requiredLiterals {
+	res := make([]requiredLiterals, 0, len(subs))
+	for _, sub := range subs {
+		switch sub.op {
+		case literalsAny:
+		case literalsAnd:
+			res = append(res, sub.subs...)
+		default:
+			res = append(res, sub)
+		}
+	}
+	switch len(res) {
+	case 0:
+		return anyLiterals
+	case 1:
+		return res[0]
+	}
+	return requiredLiterals{op: literalsAnd, subs: res}
+}
+
+// orNode requires any of the sub trees, it doesn't require anything if one of them doesn't.
+func orNode(subs []requiredLiterals) requiredLiterals {
+	res := make([]requiredLiterals, 0, len(subs))
+	for _, sub := range subs {
+		switch sub.op {
+		case literalsAny:
+			return anyLiterals
+		case literalsOr:
+			res = append(res, sub.subs...)
+		default:
+			res = append(res, sub)
+		}
+	}
+	switch len(res) {
+	case 0:
+		return anyLiterals
+	case 1:
+		return res[0]
+	}
+	return requiredLiterals{op: literalsOr, subs: res}
+}
+
+// bloomTest converts the tree into n-gram bloom tests.
+func (l requiredLiterals) bloomTest(b NGramBuilder) BloomTest {
+	switch l.op {
+	case literalsLiteral:
+		return newStringTest(b, l.literal)
+	case literalsAnd:
+		tests := make(BloomTests, 0, len(l.subs))
+		for _, sub := range l.subs {
+			tests = append(tests, sub.bloomTest(b))
+		}
+		return tests
+	case literalsOr:
+		var res BloomTest
+		for _, sub := range l.subs {
+			test := sub.bloomTest(b)
+			// A literal too short to be tested matches everything, so does the alternation.
+			if _, ok := test.(matchAllTest); ok {
+				return MatchAll
+			}
+			if res == nil {
+				res = test
+				continue
+			}
+			res = newOrTest(res, test)
+		}
+		return res
+	}
+	return MatchAll
+}
+
+func (l requiredLiterals) String() string {
+	switch l.op {
+	case literalsLiteral:
+		return `"` + l.literal + `"`
+	case literalsAnd, literalsOr:
+		sep := " AND "
+		if l.op == literalsOr {
+			sep = " OR "
+		}
+		parts := make([]string, 0, len(l.subs))
+		for _, sub := range l.subs {
+			parts = append(parts, sub.String())
+		}
+		return "(" + strings.Join(parts, sep) + ")"
+	}
+	return "*"
+}
@@ -0,0 +1,47 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/grafana/regexp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractRequiredLiterals(t *testing.T) {
+	for _, tc := range []struct {
+		expr string
+		want string
+	}{
+		{expr: `error`, want: `"error"`},
+		{expr: `error.*timeout`, want: `("error" AND "timeout")`},
+		{expr: `^level=(error|warn) `, want: `("level=error " OR "level=warn ")`},
+		{expr: `(error|fail).+timeout`, want: `(("error" OR "fail") AND "timeout")`},
+		{expr: `[Ee]rror`, want: `("Error" OR "error")`},
+		{expr: `(?i)err`, want: `("ERR" OR "ERr" OR "ErR" OR "Err" OR "eRR" OR "eRr" OR "erR" OR "err")`},
+		{expr: `status=5[0-9][0-9]`, want: `"status=5"`},
+		{expr: `(foo)+bar`, want: `"foobar"`},
+		{expr: `a(foo)+b`, want: `("afoo" AND "foob")`},
+		{expr: `x{2,}y`, want: `("xx" AND "xy")`},
+		{expr: `(foo)?bar`, want: `"bar"`},
+		{expr: `foo|.*`, want: `*`},
+		{expr: `(?i)error`, want: `*`},
+		{expr: `error(?i)timeout`, want: `"error"`},
+		{expr: `.*`, want: `*`},
+		{expr: `[a-z]+`, want: `*`},
+		{expr: `(`, want: `*`},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			require.Equal(t, tc.want, extractRequiredLiterals(tc.expr).String())
+		})
+	}
+}
+
+func TestBloomCheckerWrapper_TestRegex(t *testing.T) {
+	bloom := fakeBloom{"error", "timeout"}
+	checker := bloomCheckerWrapper{bloom: bloom}
+
+	// literals longer than the n-grams aren't in the bloom, so regexps can't rule out a chunk.
+	require.True(t, checker.TestRegex(regexp.MustCompile(`error.*timeout`)))
+	require.True(t, checker.TestRegex(regexp.MustCompile(`fail.*timeout`)))
+	require.True(t, checker.TestRegex(regexp.MustCompile(`(?i)failure`)))
+}
@@ -0,0 +1,270 @@
+package v1
+
+import (
+	"math"
+	"math/bits"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/grafana/loki/v3/pkg/util/encoding"
+)
+
+const (
+	// summaryAll is an unknown summary, it matches everything.
+	summaryAll byte = iota
+	// summarySparse encodes the positions of the set bits, used while few bits are set.
+	summarySparse
+	// summaryDense encodes the bitset.
+	summaryDense
+)
+
+// Sizing of the summaries. A summary of m bits with k hashes holding n tokens has a false positive rate of
+// (1-e^(-kn/m))^k: summaries are shrunk to about summaryBitsPerToken bits per token, that is about 1.2% false
+// positives with 4 hashes, and summaries which would exceed summaryMaxFalsePositiveRate are stored as unknown.
+// Series summaries are stored in the series pages of the index and are limited to seriesSummaryMaxBits, so that
+// series with many tokens don't bloat the index, they are then unknown and only their bloom is tested.
+const (
+	summaryBitsPerToken         = 10
+	summaryMinBits              = 1 << 10
+	summaryMaxFalsePositiveRate = 0.2
+	seriesSummaryMaxBits        = 1 << 16 // 8KiB
+)
+
+// SummaryBloom is a coarse bloom filter summarizing the raw tokens of a series or of a whole bloom page. The tokens
+// of each series are collected in a summary of the maximum size of the schema, which is shrunk to fit its number of
+// tokens and stored along with the series in the index, and merged into the summary of its page, which is shrunk
+// when the page is flushed. Testing the summaries first allows skipping series and whole pages which can't match
+// without reading their blooms.
+//
+// The positions of a token are taken modulo the size of the summary and the sizes are powers of two, so that
+// summaries of different sizes can be merged: a summary of m bits folds into a summary of m/2 bits by merging its
+// halves, and unfolds into a larger summary by repeating it.
+type SummaryBloom struct {
+	m, k uint32
+	// all is set when the content of the summary is unknown, e.g. when merging a series without summary.
+	all bool
+	// Either the bitset or the sorted positions of the set bits of a decoded sparse summary.
+	bits      []uint64
+	positions []uint32
+}
+
+func NewSummaryBloom(m, k uint32) *SummaryBloom {
+	return &SummaryBloom{m: m, k: k}
+}
+
+// summaryHash is the 64 bit FNV-1a hash of the data, split into the two halves the k positions are derived from.
+func summaryHash(data []byte) (lower, upper uint32) {
+	h := uint64(14695981039346656037)
+	for _, c := range data {
+		h ^= uint64(c)
+		h *= 1099511628211
+	}
+	return uint32(h), uint32(h >> 32)
+}
+
+func (s *SummaryBloom) position(lower, upper, i uint32) uint32 {
+	return uint32((uint64(lower) + uint64(i)*uint64(upper)) % uint64(s.m))
+}
+
+// dense converts the summary to a bitset so that it can be modified.
+func (s *SummaryBloom) dense() {
+	if s.bits != nil {
+		return
+	}
+	s.bits = make([]uint64, (s.m+63)/64)
+	for _, p := range s.positions {
+		s.bits[p/64] |= 1 << (p % 64)
+	}
+	s.positions = nil
+}
+
+// Add adds the token to the summary.
+func (s *SummaryBloom) Add(data []byte) {
+	if s.all {
+		return
+	}
+	s.dense()
+	lower, upper := summaryHash(data)
+	for i := uint32(0); i < s.k; i++ {
+		p := s.position(lower, upper, i)
+		s.bits[p/64] |= 1 << (p % 64)
+	}
+}
+
+// Test implements the filter.Checker interface.
+func (s *SummaryBloom) Test(data []byte) bool {
+	if s.all {
+		return true
+	}
+	lower, upper := summaryHash(data)
+	for i := uint32(0); i < s.k; i++ {
+		if !s.isSet(s.position(lower, upper, i)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *SummaryBloom) isSet(p uint32) bool {
+	if s.bits != nil {
+		return s.bits[p/64]&(1<<(p%64)) != 0
+	}
+	i := sort.Search(len(s.positions), func(i int) bool { return s.positions[i] >= p })
+	return i < len(s.positions) && s.positions[i] == p
+}
+
+// Merge adds all the tokens of the other summary. A nil summary is unknown and matches everything.
+// The size of either summary must be a multiple of the size of the other one.
+func (s *SummaryBloom) Merge(other *SummaryBloom) error {
+	if other == nil || other.all {
+		s.all, s.bits, s.positions = true, nil, nil
+		return nil
+	}
+	if s.all {
+		return nil
+	}
+	if s.k != other.k || (s.m%other.m != 0 && other.m%s.m != 0) {
+		return errors.Errorf("incompatible summaries: m=%d,k=%d and m=%d,k=%d", s.m, s.k, other.m, other.k)
+	}
+	s.dense()
+	if other.bits != nil && other.m == s.m {
+		for i, w := range other.bits {
+			s.bits[i] |= w
+		}
+		return nil
+	}
+	other.forEach(func(p uint32) {
+		// a larger summary is folded, a smaller one repeated
+		for p %= s.m; p < s.m; p += other.m {
+			s.bits[p/64] |= 1 << (p % 64)
+		}
+	})
+	return nil
+}
+
+// forEach calls fn with the positions of the set bits in increasing order.
+func (s *SummaryBloom) forEach(fn func(p uint32)) {
+	if s.bits == nil {
+		for _, p := range s.positions {
+			fn(p)
+		}
+		return
+	}
+	for i, w := range s.bits {
+		for w != 0 {
+			fn(uint32(i*64 + bits.TrailingZeros64(w)))
+			w &= w - 1
+		}
+	}
+}
+
+// shrink folds the summary to the smallest size of at most maxBits which keeps about summaryBitsPerToken bits per
+// token, and makes it match everything if its false positive rate exceeds summaryMaxFalsePositiveRate.
+func (s *SummaryBloom) shrink(maxBits uint32) {
+	if s.all {
+		return
+	}
+
+	// estimate the number of tokens from the ratio of set bits
+	m := s.m
+	set := float64(s.setBits())
+	tokens := -float64(m) / float64(s.k) * math.Log1p(-set/float64(m))
+	for m%2 == 0 && m/2 >= summaryMinBits && (m > maxBits || float64(m/2) >= tokens*summaryBitsPerToken) {
+		m /= 2
+	}
+	if m != s.m {
+		folded := NewSummaryBloom(m, s.k)
+		_ = folded.Merge(s)
+		*s = *folded
+	}
+
+	if math.Pow(float64(s.setBits())/float64(s.m), float64(s.k)) > summaryMaxFalsePositiveRate {
+		s.all, s.bits, s.positions = true, nil, nil
+	}
+}
+
+func (s *SummaryBloom) setBits() int {
+	if s.bits == nil {
+		return len(s.positions)
+	}
+	var n int
+	for _, w := range s.bits {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+func (s *SummaryBloom) Encode(enc *encoding.Encbuf) {
+	if s.all {
+		enc.PutByte(summaryAll)
+		return
+	}
+
+	// delta encoded positions take about two bytes each, the bitset m/8 bytes
+	n := s.setBits()
+	if n*2 >= int(s.m/8) {
+		s.dense()
+		enc.PutByte(summaryDense)
+		enc.PutUvarint32(s.m)
+		for _, w := range s.bits {
+			enc.PutBE64(w)
+		}
+		return
+	}
+
+	enc.PutByte(summarySparse)
+	enc.PutUvarint32(s.m)
+	enc.PutUvarint(n)
+	var prev uint32
+	s.forEach(func(p uint32) {
+		enc.PutUvarint32(p - prev)
+		prev = p
+	})
+}
+
+// Decode decodes a summary whose size divides the size it was created with, sparse summaries are kept sparse.
+func (s *SummaryBloom) Decode(dec *encoding.Decbuf) error {
+	s.all, s.bits, s.positions = false, nil, nil
+
+	kind := dec.Byte()
+	if kind == summarySparse || kind == summaryDense {
+		m := dec.Uvarint32()
+		if m == 0 || s.m%m != 0 {
+			if err := dec.Err(); err != nil {
+				return errors.Wrap(err, "decoding summary")
+			}
+			return errors.Errorf("invalid summary size %d for a maximum size of %d", m, s.m)
+		}
+		s.m = m
+	}
+
+	switch kind {
+	case summaryAll:
+		s.all = true
+	case summarySparse:
+		n := dec.Uvarint()
+		if n > int(s.m) {
+			return errors.Errorf("summary with %d positions exceeds its size %d", n, s.m)
+		}
+		s.positions = make([]uint32, n)
+		var prev uint32
+		for i := range s.positions {
+			prev += dec.Uvarint32()
+			if prev >= s.m {
+				return errors.Errorf("summary position %d out of range %d", prev, s.m)
+			}
+			s.positions[i] = prev
+		}
+	case summaryDense:
+		s.bits = make([]uint64, (s.m+63)/64)
+		for i := range s.bits {
+			s.bits[i] = dec.Be64()
+		}
+	default:
+		if dec.Err() == nil {
+			return errors.Errorf("invalid summary encoding %d", kind)
+		}
+	}
+	return errors.Wrap(dec.Err(), "decoding summary")
+}
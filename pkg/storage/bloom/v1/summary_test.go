@@ -0,0 +1,147 @@
+package v1
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/util/encoding"
+)
+
+func TestSummaryBloom_EncodingRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		desc   string
+		tokens int
+		kind   byte
+	}{
+		{desc: "empty", tokens: 0, kind: summarySparse},
+		{desc: "sparse", tokens: 100, kind: summarySparse},
+		{desc: "dense", tokens: 10000, kind: summaryDense},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			summary := NewSummaryBloom(1<<16, 4)
+			for i := 0; i < tc.tokens; i++ {
+				summary.Add([]byte(fmt.Sprintf("token-%d", i)))
+			}
+
+			var enc encoding.Encbuf
+			summary.Encode(&enc)
+			require.Equal(t, tc.kind, enc.Get()[0])
+
+			decoded := NewSummaryBloom(1<<16, 4)
+			dec := encoding.DecWith(enc.Get())
+			require.NoError(t, decoded.Decode(&dec))
+			require.Equal(t, 0, dec.Len())
+			require.Equal(t, summary.setBits(), decoded.setBits())
+
+			for i := 0; i < tc.tokens; i++ {
+				require.True(t, decoded.Test([]byte(fmt.Sprintf("token-%d", i))))
+			}
+			require.False(t, decoded.Test([]byte("missing")))
+
+			// re-encoding a decoded summary is stable
+			var reenc encoding.Encbuf
+			decoded.Encode(&reenc)
+			require.Equal(t, enc.Get(), reenc.Get())
+		})
+	}
+}
+
+func TestSummaryBloom_Merge(t *testing.T) {
+	a, b := NewSummaryBloom(1<<16, 4), NewSummaryBloom(1<<16, 4)
+	a.Add([]byte("foo"))
+	b.Add([]byte("bar"))
+
+	// merging a decoded sparse summary
+	var enc encoding.Encbuf
+	b.Encode(&enc)
+	dec := encoding.DecWith(enc.Get())
+	decoded := NewSummaryBloom(1<<16, 4)
+	require.NoError(t, decoded.Decode(&dec))
+
+	page := NewSummaryBloom(1<<16, 4)
+	require.NoError(t, page.Merge(a))
+	require.NoError(t, page.Merge(decoded))
+	require.True(t, page.Test([]byte("foo")))
+	require.True(t, page.Test([]byte("bar")))
+	require.False(t, page.Test([]byte("baz")))
+
+	require.Error(t, page.Merge(NewSummaryBloom(3000, 4)))
+	require.Error(t, page.Merge(NewSummaryBloom(1<<16, 3)))
+
+	// a series without summary makes the page match everything
+	require.NoError(t, page.Merge(nil))
+	require.True(t, page.Test([]byte("baz")))
+
+	enc.Reset()
+	page.Encode(&enc)
+	require.Equal(t, []byte{summaryAll}, enc.Get())
+}
+
+func TestSummaryBloom_MergeSizes(t *testing.T) {
+	large, small := NewSummaryBloom(1<<16, 4), NewSummaryBloom(1<<10, 4)
+	large.Add([]byte("foo"))
+	small.Add([]byte("bar"))
+
+	// folding the larger summary
+	folded := NewSummaryBloom(1<<12, 4)
+	require.NoError(t, folded.Merge(large))
+	require.True(t, folded.Test([]byte("foo")))
+	require.False(t, folded.Test([]byte("bar")))
+
+	// repeating the smaller one
+	require.NoError(t, large.Merge(small))
+	require.True(t, large.Test([]byte("foo")))
+	require.True(t, large.Test([]byte("bar")))
+	require.False(t, large.Test([]byte("baz")))
+}
+
+func TestSummaryBloom_Shrink(t *testing.T) {
+	for _, tc := range []struct {
+		desc   string
+		tokens int
+		bits   uint32
+		all    bool
+	}{
+		{desc: "empty", tokens: 0, bits: summaryMinBits},
+		{desc: "few tokens", tokens: 1000, bits: 1 << 14},
+		{desc: "full size", tokens: 50000, bits: 1 << 19},
+		{desc: "saturated", tokens: 200000, all: true},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			summary := NewSummaryBloom(DefaultSummaryBits, DefaultSummaryHashes)
+			for i := 0; i < tc.tokens; i++ {
+				summary.Add([]byte(fmt.Sprintf("token-%d", i)))
+			}
+			summary.shrink(DefaultSummaryBits)
+			require.Equal(t, tc.all, summary.all)
+			if tc.all {
+				return
+			}
+			require.Equal(t, tc.bits, summary.m)
+
+			for i := 0; i < tc.tokens; i++ {
+				require.True(t, summary.Test([]byte(fmt.Sprintf("token-%d", i))))
+			}
+			var falsePositives int
+			for i := 0; i < 10000; i++ {
+				if summary.Test([]byte(fmt.Sprintf("missing-%d", i))) {
+					falsePositives++
+				}
+			}
+			require.Less(t, falsePositives, 150)
+
+			// the shrunk summary decodes with the maximum size of the schema
+			var enc encoding.Encbuf
+			summary.Encode(&enc)
+			decoded := NewSummaryBloom(DefaultSummaryBits, DefaultSummaryHashes)
+			dec := encoding.DecWith(enc.Get())
+			require.NoError(t, decoded.Decode(&dec))
+			require.Equal(t, summary.m, decoded.m)
+			require.Equal(t, summary.setBits(), decoded.setBits())
+		})
+	}
+}
@@ -26,12 +26,8 @@ func MakeBlock(t testing.TB, nth int, fromFp, throughFp model.Fingerprint, fromT
 
 	builder, err := NewBlockBuilder(
 		BlockOptions{
-			Schema: Schema{
-				version:     DefaultSchemaVersion,
-				encoding:    chunkenc.EncSnappy,
-				nGramLength: 4, // see DefaultNGramLength in bloom_tokenizer_test.go
-				nGramSkip:   0, // see DefaultNGramSkip in bloom_tokenizer_test.go
-			},
+			// see DefaultNGramLength and DefaultNGramSkip in bloom_tokenizer_test.go
+			Schema:         newSchema(DefaultSchemaVersion, chunkenc.EncSnappy, 4, 0, DefaultFalsePositiveRate),
 			SeriesPageSize: 100,
 			BloomPageSize:  10 << 10,
 		},
@@ -67,7 +63,8 @@ func MkBasicSeriesWithBlooms(nSeries, _ int, fromFp, throughFp model.Fingerprint
 		}
 
 		var bloom Bloom
-		bloom.ScalableBloomFilter = *filter.NewScalableBloomFilter(1024, 0.01, 0.8)
+		bloom.ScalableBloomFilter = *filter.NewScalableBloomFilter(DefaultFilterHint, DefaultFalsePositiveRate, DefaultFilterTighteningRatio)
+		summary := NewSummaryBloom(DefaultSummaryBits, DefaultSummaryHashes)
 
 		keys := make([][]byte, 0, int(step))
 		for _, chk := range series.Chunks {
@@ -80,6 +77,7 @@ func MkBasicSeriesWithBlooms(nSeries, _ int, fromFp, throughFp model.Fingerprint
 					key := it.At()
 					// series-level key
 					bloom.Add(key)
+					summary.Add(key)
 
 					// chunk-level key
 					tokenBuf = append(tokenBuf[:prefixLen], key...)
@@ -92,8 +90,9 @@ func MkBasicSeriesWithBlooms(nSeries, _ int, fromFp, throughFp model.Fingerprint
 		}
 
 		seriesList = append(seriesList, SeriesWithBloom{
-			Series: &series,
-			Bloom:  &bloom,
+			Series:  &series,
+			Bloom:   &bloom,
+			Summary: summary,
 		})
 		keysList = append(keysList, keys)
 	}
@@ -14,10 +14,16 @@ const (
 	magicNumber = uint32(0xCA7CAFE5)
 	// Add new versions below
 	V1 byte = iota
+	// V2 indexes the structured metadata and stream labels of chunks along their lines
+	V2
+	// V3 encodes the hashing strategy and scalable bloom filter parameters in the schema
+	V3
+	// V4 stores summary blooms of the raw tokens of each series and bloom page
+	V4
 )
 
 const (
-	DefaultSchemaVersion = V1
+	DefaultSchemaVersion = V4
 )
 
 var (
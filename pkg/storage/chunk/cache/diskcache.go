@@ -0,0 +1,643 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
+	"github.com/grafana/loki/v3/pkg/util/constants"
+)
+
+const (
+	EvictionPolicyLRU = "lru"
+	EvictionPolicyLFU = "lfu"
+
+	diskCacheFormatV1 = byte(1)
+	diskCacheTmpExt   = ".tmp"
+
+	corruptedReason = "corrupted"
+)
+
+var (
+	diskCacheMagic       = []byte("LKDC")
+	diskCacheCRC32Table  = crc32.MakeTable(crc32.Castagnoli)
+	errDiskCacheCorrupt  = errors.New("corrupted disk cache entry")
+	diskCacheHeaderBytes = len(diskCacheMagic) + 1 + 8 + 4
+)
+
+// DiskCacheConfig represents the config of a cache persisted on the local disk.
+type DiskCacheConfig struct {
+	Enabled        bool          `yaml:"enabled,omitempty"`
+	Directory      string        `yaml:"directory"`
+	MaxSizeMB      int64         `yaml:"max_size_mb"`
+	TTL            time.Duration `yaml:"ttl"`
+	EvictionPolicy string        `yaml:"eviction_policy"`
+	SyncWrites     bool          `yaml:"sync_writes"`
+
+	// PurgeInterval tell how often should we remove keys that are expired.
+	// by default it takes `defaultPurgeInterval`
+	PurgeInterval time.Duration `yaml:"-"`
+}
+
+func (cfg *DiskCacheConfig) RegisterFlagsWithPrefix(prefix, description string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, prefix+"enabled", false, description+"Whether the disk cache is enabled. When the embedded cache is enabled as well, the disk cache is used as a tier below it.")
+	f.StringVar(&cfg.Directory, prefix+"directory", "", description+"Directory where the entries of the disk cache are stored. Each cache must use its own directory. Entries stored in the directory are loaded on startup.")
+	f.Int64Var(&cfg.MaxSizeMB, prefix+"max-size-mb", 10240, description+"Maximum size of the entries of the disk cache in MB.")
+	f.DurationVar(&cfg.TTL, prefix+"ttl", 24*time.Hour, description+"The time to live for items in the disk cache before they get purged.")
+	f.StringVar(&cfg.EvictionPolicy, prefix+"eviction-policy", EvictionPolicyLRU, description+"Policy used to evict entries when the disk cache is full. Supported values are: lru, lfu.")
+	f.BoolVar(&cfg.SyncWrites, prefix+"sync-writes", false, description+"Whether entries are synced to disk before they are added to the disk cache. Entries lost or corrupted by a crash are detected by their checksum and dropped, so syncing is only needed to keep them across crashes of the host.")
+}
+
+func (cfg *DiskCacheConfig) IsEnabled() bool {
+	return cfg.Enabled
+}
+
+func (cfg *DiskCacheConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Directory == "" {
+		return errors.New("disk cache directory must be set")
+	}
+	if cfg.MaxSizeMB <= 0 {
+		return errors.New("disk cache max size must be greater than 0")
+	}
+	if cfg.EvictionPolicy != EvictionPolicyLRU && cfg.EvictionPolicy != EvictionPolicyLFU {
+		return fmt.Errorf("unsupported disk cache eviction policy: %s", cfg.EvictionPolicy)
+	}
+	return nil
+}
+
+// DiskCache is a cache storing each entry in its own file on the local disk, bounded by the total size of the entries.
+//
+// Each file holds the key of the entry, the time it was stored and a checksum of its content, so that the index of
+// the cache can be rebuilt from the directory on startup and corrupted entries are detected when they are fetched.
+// Files are written to a temporary file first and renamed, so that a crash of the process never leaves partially
+// written entries. They are only synced to disk when configured to.
+type DiskCache struct {
+	cacheType stats.CacheType
+	logger    log.Logger
+
+	dir          string
+	ttl          time.Duration
+	maxSizeBytes uint64
+	syncWrites   bool
+
+	lock          sync.Mutex
+	entries       map[string]*diskCacheEntry
+	policy        diskCacheEvictionPolicy
+	currSizeBytes uint64
+
+	done     chan struct{}
+	stopOnce sync.Once
+
+	entriesAddedNew prometheus.Counter
+	entriesEvicted  *prometheus.CounterVec
+	entriesCurrent  prometheus.Gauge
+	diskBytes       prometheus.Gauge
+}
+
+type diskCacheEntry struct {
+	key     string
+	path    string
+	size    uint64
+	created time.Time
+
+	// used by the eviction policies.
+	element    *list.Element
+	heapIndex  int
+	hits       uint64
+	lastAccess time.Time
+}
+
+// NewDiskCache returns a new DiskCache storing its entries in the directory of the config.
+// Entries already stored in the directory are loaded.
+func NewDiskCache(name string, cfg DiskCacheConfig, reg prometheus.Registerer, logger log.Logger, cacheType stats.CacheType) (*DiskCache, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(cfg.Directory, 0o750); err != nil {
+		return nil, err
+	}
+
+	if cfg.PurgeInterval == 0 {
+		cfg.PurgeInterval = defaultPurgeInterval
+	}
+
+	c := &DiskCache{
+		cacheType: cacheType,
+		logger:    log.With(logger, "cache", name),
+
+		dir:          cfg.Directory,
+		ttl:          cfg.TTL,
+		maxSizeBytes: uint64(cfg.MaxSizeMB * 1e6),
+		syncWrites:   cfg.SyncWrites,
+
+		entries: make(map[string]*diskCacheEntry),
+		policy:  newDiskCacheEvictionPolicy(cfg.EvictionPolicy),
+
+		done: make(chan struct{}),
+
+		entriesAddedNew: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace:   constants.Loki,
+			Subsystem:   "diskcache",
+			Name:        "added_new_total",
+			Help:        "The total number of new entries added to the cache",
+			ConstLabels: prometheus.Labels{"cache": name},
+		}),
+
+		entriesEvicted: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace:   constants.Loki,
+			Subsystem:   "diskcache",
+			Name:        "evicted_total",
+			Help:        "The total number of evicted entries",
+			ConstLabels: prometheus.Labels{"cache": name},
+		}, []string{"reason"}),
+
+		entriesCurrent: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace:   constants.Loki,
+			Subsystem:   "diskcache",
+			Name:        "entries",
+			Help:        "Current number of entries in the cache",
+			ConstLabels: prometheus.Labels{"cache": name},
+		}),
+
+		diskBytes: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace:   constants.Loki,
+			Subsystem:   "diskcache",
+			Name:        "disk_bytes",
+			Help:        "The current size of the entries of the cache on disk in bytes",
+			ConstLabels: prometheus.Labels{"cache": name},
+		}),
+	}
+
+	if err := c.load(); err != nil {
+		return nil, fmt.Errorf("failed to load disk cache from %s: %w", cfg.Directory, err)
+	}
+
+	if cfg.TTL > 0 {
+		go c.runPruneJob(cfg.PurgeInterval)
+	}
+
+	return c, nil
+}
+
+// load rebuilds the index of the cache from the files of its directory.
+// Leftover temporary files as well as expired entries and entries with invalid headers are removed.
+func (c *DiskCache) load() error {
+	var loaded []*diskCacheEntry
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		if strings.HasSuffix(path, diskCacheTmpExt) {
+			return os.Remove(path)
+		}
+
+		entry, err := readDiskCacheEntryHeader(path)
+		if err != nil || entry.path != c.entryPath(entry.key) || c.expired(entry) {
+			level.Debug(c.logger).Log("msg", "removing invalid or expired disk cache entry", "path", path, "err", err)
+			return os.Remove(path)
+		}
+		loaded = append(loaded, entry)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// add entries from the least to the most recently written one, so that the oldest entries are evicted first.
+	sort.Slice(loaded, func(i, j int) bool {
+		return loaded[i].lastAccess.Before(loaded[j].lastAccess)
+	})
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, entry := range loaded {
+		c.add(entry)
+	}
+	c.evict(0)
+	c.diskBytes.Set(float64(c.currSizeBytes))
+
+	level.Info(c.logger).Log("msg", "loaded disk cache", "entries", len(c.entries), "bytes", c.currSizeBytes)
+	return nil
+}
+
+func (c *DiskCache) entryPath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:16])
+	return filepath.Join(c.dir, name[:2], name)
+}
+
+func (c *DiskCache) expired(entry *diskCacheEntry) bool {
+	return c.ttl > 0 && time.Since(entry.created) > c.ttl
+}
+
+func (c *DiskCache) runPruneJob(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			c.pruneExpiredItems()
+		}
+	}
+}
+
+// pruneExpiredItems prunes items in the cache that exceeded their ttl
+func (c *DiskCache) pruneExpiredItems() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, entry := range c.entries {
+		if c.expired(entry) {
+			c.remove(entry, expiredReason)
+		}
+	}
+	c.diskBytes.Set(float64(c.currSizeBytes))
+}
+
+// Store implements Cache.
+func (c *DiskCache) Store(_ context.Context, keys []string, bufs [][]byte) error {
+	var err error
+	for i := range keys {
+		if storeErr := c.put(keys[i], bufs[i]); storeErr != nil {
+			err = storeErr
+		}
+	}
+	return err
+}
+
+func (c *DiskCache) put(key string, value []byte) error {
+	created := time.Now()
+	content := encodeDiskCacheEntry(key, value, created)
+	size := uint64(len(content))
+
+	if size > c.maxSizeBytes {
+		c.entriesEvicted.WithLabelValues(tooBigReason).Inc()
+		return nil
+	}
+
+	path := c.entryPath(key)
+	tmpPath, err := writeTempFile(path, content, c.syncWrites)
+	if err != nil {
+		return fmt.Errorf("failed to write disk cache entry: %w", err)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// rename the file while holding the lock so that evicting the previous entry never removes the new file.
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write disk cache entry: %w", err)
+	}
+
+	_, replaced := c.entries[key]
+	if replaced {
+		// the file of the previous entry was already replaced.
+		c.removeFromIndex(c.entries[key], replacedReason)
+	}
+	c.evict(size)
+	c.add(&diskCacheEntry{
+		key:        key,
+		path:       path,
+		size:       size,
+		created:    created,
+		lastAccess: created,
+	})
+	if !replaced {
+		c.entriesAddedNew.Inc()
+	}
+	c.diskBytes.Set(float64(c.currSizeBytes))
+	return nil
+}
+
+// Fetch implements Cache.
+func (c *DiskCache) Fetch(_ context.Context, keys []string) (found []string, bufs [][]byte, missing []string, err error) {
+	found, bufs, missing = make([]string, 0, len(keys)), make([][]byte, 0, len(keys)), make([]string, 0, len(keys))
+	for _, key := range keys {
+		value, ok := c.get(key)
+		if !ok {
+			missing = append(missing, key)
+			continue
+		}
+
+		found = append(found, key)
+		bufs = append(bufs, value)
+	}
+	return
+}
+
+func (c *DiskCache) get(key string) ([]byte, bool) {
+	c.lock.Lock()
+	entry, ok := c.entries[key]
+	if ok && c.expired(entry) {
+		c.remove(entry, expiredReason)
+		c.diskBytes.Set(float64(c.currSizeBytes))
+		ok = false
+	}
+	if ok {
+		entry.hits++
+		entry.lastAccess = time.Now()
+		c.policy.touch(entry)
+	}
+	c.lock.Unlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	value, err := readDiskCacheEntry(entry.path, key)
+	if err != nil {
+		level.Warn(c.logger).Log("msg", "failed to read disk cache entry", "path", entry.path, "err", err)
+
+		c.lock.Lock()
+		// the entry might have been replaced in the meantime.
+		if c.entries[key] == entry {
+			c.remove(entry, corruptedReason)
+			c.diskBytes.Set(float64(c.currSizeBytes))
+		}
+		c.lock.Unlock()
+		return nil, false
+	}
+	return value, true
+}
+
+// Stop implements Cache. Entries are kept on disk to be loaded again on the next start.
+func (c *DiskCache) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.done)
+	})
+}
+
+func (c *DiskCache) GetCacheType() stats.CacheType {
+	return c.cacheType
+}
+
+func (c *DiskCache) add(entry *diskCacheEntry) {
+	c.entries[entry.key] = entry
+	c.policy.add(entry)
+	c.currSizeBytes += entry.size
+	c.entriesCurrent.Inc()
+}
+
+// evict removes entries until an entry of the given size fits in the cache.
+func (c *DiskCache) evict(size uint64) {
+	for c.currSizeBytes+size > c.maxSizeBytes {
+		entry := c.policy.victim()
+		if entry == nil {
+			return
+		}
+		c.remove(entry, fullReason)
+	}
+}
+
+func (c *DiskCache) remove(entry *diskCacheEntry, reason string) {
+	c.removeFromIndex(entry, reason)
+	if err := os.Remove(entry.path); err != nil && !os.IsNotExist(err) {
+		level.Warn(c.logger).Log("msg", "failed to remove disk cache entry", "path", entry.path, "err", err)
+	}
+}
+
+func (c *DiskCache) removeFromIndex(entry *diskCacheEntry, reason string) {
+	c.policy.remove(entry)
+	delete(c.entries, entry.key)
+	c.currSizeBytes -= entry.size
+	c.entriesCurrent.Dec()
+	c.entriesEvicted.WithLabelValues(reason).Inc()
+}
+
+// writeTempFile writes the content to a temporary file next to the path, to be renamed to the path once complete.
+// The file is synced to disk if sync is set.
+func writeTempFile(path string, content []byte, sync bool) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return "", err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+"-*"+diskCacheTmpExt)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = f.Write(content)
+	if err == nil && sync {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// encodeDiskCacheEntry encodes an entry as:
+//
+//	magic | version | created (unix nanoseconds) | crc32 of the rest | uvarint len(key) | key | value
+func encodeDiskCacheEntry(key string, value []byte, created time.Time) []byte {
+	buf := make([]byte, diskCacheHeaderBytes, diskCacheHeaderBytes+binary.MaxVarintLen64+len(key)+len(value))
+	copy(buf, diskCacheMagic)
+	buf[len(diskCacheMagic)] = diskCacheFormatV1
+	binary.BigEndian.PutUint64(buf[len(diskCacheMagic)+1:], uint64(created.UnixNano()))
+
+	buf = binary.AppendUvarint(buf, uint64(len(key)))
+	buf = append(buf, key...)
+	buf = append(buf, value...)
+
+	binary.BigEndian.PutUint32(buf[diskCacheHeaderBytes-4:], crc32.Checksum(buf[diskCacheHeaderBytes:], diskCacheCRC32Table))
+	return buf
+}
+
+// readDiskCacheEntryHeader reads the header of the entry stored in the file without validating its checksum.
+func readDiskCacheEntryHeader(path string) (*diskCacheEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	r := bufio.NewReader(f)
+	header := make([]byte, diskCacheHeaderBytes)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(header[:len(diskCacheMagic)], diskCacheMagic) || header[len(diskCacheMagic)] != diskCacheFormatV1 {
+		return nil, errDiskCacheCorrupt
+	}
+
+	keyLen, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if keyLen > uint64(info.Size()) {
+		return nil, errDiskCacheCorrupt
+	}
+	key := make([]byte, keyLen)
+	if _, err := io.ReadFull(r, key); err != nil {
+		return nil, err
+	}
+
+	return &diskCacheEntry{
+		key:        string(key),
+		path:       path,
+		size:       uint64(info.Size()),
+		created:    time.Unix(0, int64(binary.BigEndian.Uint64(header[len(diskCacheMagic)+1:]))),
+		lastAccess: info.ModTime(),
+	}, nil
+}
+
+// readDiskCacheEntry reads the value of the entry stored in the file, validating its checksum and key.
+func readDiskCacheEntry(path, key string) ([]byte, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(buf) < diskCacheHeaderBytes || !bytes.Equal(buf[:len(diskCacheMagic)], diskCacheMagic) || buf[len(diskCacheMagic)] != diskCacheFormatV1 {
+		return nil, errDiskCacheCorrupt
+	}
+	if crc32.Checksum(buf[diskCacheHeaderBytes:], diskCacheCRC32Table) != binary.BigEndian.Uint32(buf[diskCacheHeaderBytes-4:]) {
+		return nil, errDiskCacheCorrupt
+	}
+
+	rest := buf[diskCacheHeaderBytes:]
+	keyLen, n := binary.Uvarint(rest)
+	if n <= 0 || keyLen > uint64(len(rest)-n) {
+		return nil, errDiskCacheCorrupt
+	}
+	rest = rest[n:]
+	if string(rest[:keyLen]) != key {
+		return nil, errDiskCacheCorrupt
+	}
+	return rest[keyLen:], nil
+}
+
+// diskCacheEvictionPolicy selects the entries to evict when the disk cache is full.
+type diskCacheEvictionPolicy interface {
+	add(entry *diskCacheEntry)
+	touch(entry *diskCacheEntry)
+	remove(entry *diskCacheEntry)
+	// victim returns the next entry to evict, or nil if there is none.
+	victim() *diskCacheEntry
+}
+
+func newDiskCacheEvictionPolicy(policy string) diskCacheEvictionPolicy {
+	if policy == EvictionPolicyLFU {
+		return &lfuPolicy{}
+	}
+	return &lruPolicy{list: list.New()}
+}
+
+// lruPolicy evicts the least recently used entries first.
+type lruPolicy struct {
+	list *list.List
+}
+
+func (p *lruPolicy) add(entry *diskCacheEntry) {
+	entry.element = p.list.PushFront(entry)
+}
+
+func (p *lruPolicy) touch(entry *diskCacheEntry) {
+	p.list.MoveToFront(entry.element)
+}
+
+func (p *lruPolicy) remove(entry *diskCacheEntry) {
+	p.list.Remove(entry.element)
+}
+
+func (p *lruPolicy) victim() *diskCacheEntry {
+	if e := p.list.Back(); e != nil {
+		return e.Value.(*diskCacheEntry)
+	}
+	return nil
+}
+
+// lfuPolicy evicts the least frequently used entries first, and the least recently used ones among them.
+type lfuPolicy struct {
+	entries lfuHeap
+}
+
+func (p *lfuPolicy) add(entry *diskCacheEntry) {
+	heap.Push(&p.entries, entry)
+}
+
+func (p *lfuPolicy) touch(entry *diskCacheEntry) {
+	heap.Fix(&p.entries, entry.heapIndex)
+}
+
+func (p *lfuPolicy) remove(entry *diskCacheEntry) {
+	heap.Remove(&p.entries, entry.heapIndex)
+}
+
+func (p *lfuPolicy) victim() *diskCacheEntry {
+	if len(p.entries) == 0 {
+		return nil
+	}
+	return p.entries[0]
+}
+
+type lfuHeap []*diskCacheEntry
+
+func (h lfuHeap) Len() int { return len(h) }
+
+func (h lfuHeap) Less(i, j int) bool {
+	if h[i].hits != h[j].hits {
+		return h[i].hits < h[j].hits
+	}
+	return h[i].lastAccess.Before(h[j].lastAccess)
+}
+
+func (h lfuHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIndex = i
+	h[j].heapIndex = j
+}
+
+func (h *lfuHeap) Push(x any) {
+	entry := x.(*diskCacheEntry)
+	entry.heapIndex = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *lfuHeap) Pop() any {
+	old := *h
+	entry := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return entry
+}
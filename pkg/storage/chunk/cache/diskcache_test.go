@@ -0,0 +1,191 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDiskCache(t *testing.T, cfg DiskCacheConfig) *DiskCache {
+	cfg.Enabled = true
+	if cfg.MaxSizeMB == 0 {
+		cfg.MaxSizeMB = 1
+	}
+	if cfg.EvictionPolicy == "" {
+		cfg.EvictionPolicy = EvictionPolicyLRU
+	}
+
+	c, err := NewDiskCache("test", cfg, nil, log.NewNopLogger(), "test")
+	require.NoError(t, err)
+	t.Cleanup(c.Stop)
+	return c
+}
+
+func TestDiskCache(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	c := newTestDiskCache(t, DiskCacheConfig{Directory: dir})
+
+	require.NoError(t, c.Store(ctx, []string{"a", "b", "c"}, [][]byte{[]byte("1"), []byte("2"), {}}))
+	require.NoError(t, c.Store(ctx, []string{"b"}, [][]byte{[]byte("3")}))
+
+	found, bufs, missing, err := c.Fetch(ctx, []string{"a", "b", "c", "d"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, found)
+	require.Equal(t, [][]byte{[]byte("1"), []byte("3"), {}}, bufs)
+	require.Equal(t, []string{"d"}, missing)
+	require.Equal(t, float64(3), testutil.ToFloat64(c.entriesCurrent))
+	require.Equal(t, float64(1), testutil.ToFloat64(c.entriesEvicted.WithLabelValues(replacedReason)))
+
+	// entries are loaded again when the cache is restarted.
+	c.Stop()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "leftover"+diskCacheTmpExt), []byte("partial"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "invalid"), []byte("invalid"), 0o600))
+
+	c = newTestDiskCache(t, DiskCacheConfig{Directory: dir})
+	found, bufs, missing, err = c.Fetch(ctx, []string{"a", "b", "c", "d"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, found)
+	require.Equal(t, [][]byte{[]byte("1"), []byte("3"), {}}, bufs)
+	require.Equal(t, []string{"d"}, missing)
+	require.NoFileExists(t, filepath.Join(dir, "leftover"+diskCacheTmpExt))
+	require.NoFileExists(t, filepath.Join(dir, "invalid"))
+}
+
+func TestDiskCache_Corruption(t *testing.T) {
+	ctx := context.Background()
+	c := newTestDiskCache(t, DiskCacheConfig{Directory: t.TempDir()})
+
+	require.NoError(t, c.Store(ctx, []string{"a", "b"}, [][]byte{[]byte("value a"), []byte("value b")}))
+
+	path := c.entryPath("a")
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	content[len(content)-1] ^= 0xff
+	require.NoError(t, os.WriteFile(path, content, 0o600))
+
+	found, _, missing, err := c.Fetch(ctx, []string{"a", "b"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"b"}, found)
+	require.Equal(t, []string{"a"}, missing)
+	require.NoFileExists(t, path)
+	require.Equal(t, float64(1), testutil.ToFloat64(c.entriesEvicted.WithLabelValues(corruptedReason)))
+	require.Equal(t, float64(1), testutil.ToFloat64(c.entriesCurrent))
+}
+
+func TestDiskCache_Eviction(t *testing.T) {
+	ctx := context.Background()
+	// 3 values fit in the cache of 1MB.
+	value := make([]byte, 300_000)
+
+	for _, tc := range []struct {
+		policy  string
+		evicted string
+	}{
+		// b is the least recently used entry.
+		{policy: EvictionPolicyLRU, evicted: "b"},
+		// c is the least frequently used entry.
+		{policy: EvictionPolicyLFU, evicted: "c"},
+	} {
+		tc := tc
+		t.Run(tc.policy, func(t *testing.T) {
+			c := newTestDiskCache(t, DiskCacheConfig{Directory: t.TempDir(), EvictionPolicy: tc.policy})
+
+			require.NoError(t, c.Store(ctx, []string{"a", "b", "c"}, [][]byte{value, value, value}))
+			for _, keys := range [][]string{{"a"}, {"b"}, {"a"}, {"c"}, {"b"}, {"a"}} {
+				found, _, _, err := c.Fetch(ctx, keys)
+				require.NoError(t, err)
+				require.Equal(t, keys, found)
+			}
+			// make b the least recently used entry.
+			_, _, _, err := c.Fetch(ctx, []string{"b", "c", "a"})
+			require.NoError(t, err)
+
+			require.NoError(t, c.Store(ctx, []string{"d"}, [][]byte{value}))
+
+			_, _, missing, err := c.Fetch(ctx, []string{"a", "b", "c", "d"})
+			require.NoError(t, err)
+			require.Equal(t, []string{tc.evicted}, missing)
+			require.NoFileExists(t, c.entryPath(tc.evicted))
+			require.Equal(t, float64(1), testutil.ToFloat64(c.entriesEvicted.WithLabelValues(fullReason)))
+		})
+	}
+
+	t.Run("too big", func(t *testing.T) {
+		c := newTestDiskCache(t, DiskCacheConfig{Directory: t.TempDir()})
+		require.NoError(t, c.Store(ctx, []string{"a"}, [][]byte{make([]byte, 1_000_000)}))
+
+		_, _, missing, err := c.Fetch(ctx, []string{"a"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"a"}, missing)
+	})
+
+	t.Run("loaded entries exceeding the size", func(t *testing.T) {
+		dir := t.TempDir()
+		c := newTestDiskCache(t, DiskCacheConfig{Directory: dir, MaxSizeMB: 2})
+		for i := 0; i < 6; i++ {
+			require.NoError(t, c.Store(ctx, []string{fmt.Sprint(i)}, [][]byte{value}))
+			require.NoError(t, os.Chtimes(c.entryPath(fmt.Sprint(i)), time.Now(), time.Now().Add(time.Duration(i)*time.Second)))
+		}
+		c.Stop()
+
+		// the least recently written entries are evicted.
+		c = newTestDiskCache(t, DiskCacheConfig{Directory: dir})
+		found, _, _, err := c.Fetch(ctx, []string{"0", "1", "2", "3", "4", "5"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"3", "4", "5"}, found)
+	})
+}
+
+func TestDiskCache_TTL(t *testing.T) {
+	ctx := context.Background()
+	c := newTestDiskCache(t, DiskCacheConfig{Directory: t.TempDir(), TTL: time.Hour})
+
+	require.NoError(t, c.Store(ctx, []string{"a", "b"}, [][]byte{[]byte("a"), []byte("b")}))
+	c.entries["a"].created = time.Now().Add(-2 * time.Hour)
+
+	_, _, missing, err := c.Fetch(ctx, []string{"a", "b"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a"}, missing)
+
+	c.entries["b"].created = time.Now().Add(-2 * time.Hour)
+	c.pruneExpiredItems()
+	require.Empty(t, c.entries)
+	require.Equal(t, float64(2), testutil.ToFloat64(c.entriesEvicted.WithLabelValues(expiredReason)))
+}
+
+func TestDiskCache_TierBelowEmbeddedCache(t *testing.T) {
+	ctx := context.Background()
+	cfg := Config{
+		EmbeddedCache: EmbeddedCacheConfig{Enabled: true, MaxSizeMB: 1},
+		DiskCache:     DiskCacheConfig{Enabled: true, Directory: t.TempDir(), MaxSizeMB: 1, EvictionPolicy: EvictionPolicyLRU},
+	}
+
+	c, err := New(cfg, nil, log.NewNopLogger(), "test", "loki")
+	require.NoError(t, err)
+
+	require.NoError(t, c.Store(ctx, []string{"a"}, [][]byte{[]byte("value")}))
+
+	// a new cache only finds the entry on disk, and stores it in the embedded cache.
+	c.Stop()
+	c, err = New(cfg, nil, log.NewNopLogger(), "test", "loki")
+	require.NoError(t, err)
+	defer c.Stop()
+
+	found, bufs, _, err := c.Fetch(ctx, []string{"a"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a"}, found)
+	require.Equal(t, [][]byte{[]byte("value")}, bufs)
+
+	embedded := c.(*instrumentedCache).Cache.(tiered)[0]
+	found, _, _, err = embedded.Fetch(ctx, []string{"a"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a"}, found)
+}
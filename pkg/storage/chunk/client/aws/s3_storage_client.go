@@ -345,6 +345,16 @@ func (a *S3ObjectClient) bucketFromKey(key string) string {
 
 // GetObject returns a reader and the size for the specified object key from the configured S3 bucket.
 func (a *S3ObjectClient) GetObject(ctx context.Context, objectKey string) (io.ReadCloser, int64, error) {
+	return a.getObject(ctx, objectKey, nil)
+}
+
+// GetObjectRange returns a reader for the given range of the object.
+func (a *S3ObjectClient) GetObjectRange(ctx context.Context, objectKey string, offset, length int64) (io.ReadCloser, error) {
+	readCloser, _, err := a.getObject(ctx, objectKey, aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)))
+	return readCloser, err
+}
+
+func (a *S3ObjectClient) getObject(ctx context.Context, objectKey string, byteRange *string) (io.ReadCloser, int64, error) {
 	var resp *s3.GetObjectOutput
 
 	// Map the key into a bucket
@@ -363,6 +373,7 @@ func (a *S3ObjectClient) GetObject(ctx context.Context, objectKey string) (io.Re
 			resp, requestErr = a.hedgedS3.GetObjectWithContext(ctx, &s3.GetObjectInput{
 				Bucket: aws.String(bucket),
 				Key:    aws.String(objectKey),
+				Range:  byteRange,
 			})
 			return requestErr
 		})
@@ -88,9 +88,24 @@ func (a *AIMDController) PutObject(ctx context.Context, objectKey string, object
 }
 
 func (a *AIMDController) GetObject(ctx context.Context, objectKey string) (io.ReadCloser, int64, error) {
-	// Only GetObject implements congestion avoidance; the other methods are either non-idempotent which means they
-	// cannot be retried, or are too low volume to care about
+	// Only GetObject and GetObjectRange implement congestion avoidance; the other methods are either non-idempotent
+	// which means they cannot be retried, or are too low volume to care about
+	return a.get(ctx, func() (io.ReadCloser, int64, error) {
+		return a.inner.GetObject(ctx, objectKey)
+	})
+}
+
+// GetObjectRange reads a range of the object with the same congestion avoidance as GetObject.
+// Inner clients which can't read ranges read the object from the start.
+func (a *AIMDController) GetObjectRange(ctx context.Context, objectKey string, offset, length int64) (io.ReadCloser, error) {
+	rc, _, err := a.get(ctx, func() (io.ReadCloser, int64, error) {
+		rc, err := client.GetObjectRange(ctx, a.inner, objectKey, offset, length)
+		return rc, length, err
+	})
+	return rc, err
+}
 
+func (a *AIMDController) get(ctx context.Context, fn func() (io.ReadCloser, int64, error)) (io.ReadCloser, int64, error) {
 	// TODO(dannyk): use hedging client to handle requests, do NOT hedge retries
 
 	start := time.Now()
@@ -119,7 +134,7 @@ func (a *AIMDController) GetObject(ctx context.Context, objectKey string) (io.Re
 
 			// It is vitally important that retries are DISABLED in the inner implementation.
 			// Some object storage clients implement retries internally, and this will interfere here.
-			return a.inner.GetObject(ctx, objectKey)
+			return fn()
 		},
 		a.IsRetryableErr,
 		a.additiveIncrease,
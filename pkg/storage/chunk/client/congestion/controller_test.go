@@ -111,6 +111,44 @@ func TestRequestLimitedRetry(t *testing.T) {
 	require.EqualValues(t, 4, testutil.ToFloat64(metrics.requests))
 }
 
+func TestRequestRangeLimitedRetry(t *testing.T) {
+	cfg := Config{
+		Controller: ControllerConfig{
+			Strategy: "aimd",
+		},
+		Retry: RetrierConfig{
+			Strategy: "limited",
+			Limit:    2,
+		},
+	}
+
+	metrics := NewMetrics(t.Name(), cfg)
+	ctrl := NewController(cfg, log.NewNopLogger(), metrics)
+
+	// allow 1 request through, fail the rest
+	cli := newMockObjectClient(maxFailer{max: 1})
+	ctrl.Wrap(cli)
+
+	ctx := context.Background()
+	rangeCtrl, ok := ctrl.(client.RangeObjectClient)
+	require.True(t, ok)
+
+	// first request succeeds, no retries
+	rc, err := rangeCtrl.GetObjectRange(ctx, "foo", 1, 1)
+	require.NoError(t, err)
+	read, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.Equal(t, "a", string(read))
+	require.EqualValues(t, 1, testutil.ToFloat64(metrics.requests))
+
+	// all requests will now fail, which should incur 1 request & 2 retries
+	_, err = rangeCtrl.GetObjectRange(ctx, "foo", 1, 1)
+	require.ErrorIs(t, err, RetriesExceeded)
+	require.EqualValues(t, 1, testutil.ToFloat64(metrics.retriesExceeded))
+	require.EqualValues(t, 2, testutil.ToFloat64(metrics.retries))
+	require.EqualValues(t, 4, testutil.ToFloat64(metrics.requests))
+}
+
 func TestRequestLimitedRetryNonRetryableErr(t *testing.T) {
 	cfg := Config{
 		Controller: ControllerConfig{
@@ -0,0 +1,62 @@
+package encryption
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+)
+
+const (
+	// KMSKeyring is the KMS backed by a local keyring file.
+	KMSKeyring = "keyring"
+)
+
+// Config configures the encryption at rest of the objects written to object storage.
+type Config struct {
+	Enabled bool          `yaml:"enabled"`
+	KMS     string        `yaml:"kms"`
+	Keyring KeyringConfig `yaml:"keyring"`
+}
+
+// RegisterFlagsWithPrefix registers flags with prefix.
+func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	prefix = prefix + "encryption."
+	f.BoolVar(&cfg.Enabled, prefix+"enabled", false, "Experimental: Encrypt the chunks, index files and bloom blocks written to object storage with the keys of their tenants. Objects written before encryption was enabled remain readable.")
+	f.StringVar(&cfg.KMS, prefix+"kms", KMSKeyring, "The key management service wrapping the data keys of the objects with the keys of their tenants. Supported values are: keyring.")
+	cfg.Keyring.RegisterFlagsWithPrefix(prefix+"keyring.", f)
+}
+
+// Validate the config.
+func (cfg *Config) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	switch cfg.KMS {
+	case KMSKeyring:
+		return cfg.Keyring.Validate()
+	default:
+		return fmt.Errorf("unsupported kms: %s", cfg.KMS)
+	}
+}
+
+// KeyringConfig configures the KMS backed by a local keyring file.
+type KeyringConfig struct {
+	Path         string        `yaml:"path"`
+	ReloadPeriod time.Duration `yaml:"reload_period"`
+}
+
+// RegisterFlagsWithPrefix registers flags with prefix.
+func (cfg *KeyringConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.StringVar(&cfg.Path, prefix+"path", "", "Path to the keyring file holding the keys of the tenants. The file must be the same for all components.")
+	f.DurationVar(&cfg.ReloadPeriod, prefix+"reload-period", time.Minute, "How often the keyring file is checked for changes. Keys removed from the file can't be used anymore once it's reloaded.")
+}
+
+// Validate the config.
+func (cfg *KeyringConfig) Validate() error {
+	if cfg.Path == "" {
+		return errors.New("keyring path is required")
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"gopkg.in/yaml.v2"
+
+	util_log "github.com/grafana/loki/v3/pkg/util/log"
+)
+
+const (
+	keySize = 32
+
+	defaultKeyPrefix = "default/"
+	tenantKeyPrefix  = "tenant/"
+)
+
+// keyringFile is the format of the keyring file. The last key of a list is the current one:
+// rotating a key is done by appending a new key, destroying it by removing it from the list.
+//
+//	default:
+//	  - id: "1"
+//	    key: <base64 encoded 32 bytes key>
+//	tenants:
+//	  tenant-a:
+//	    - id: "1"
+//	      key: <base64 encoded 32 bytes key>
+//
+// The default keys are used for objects which don't belong to a single tenant, and for tenants which never had keys.
+// Tenants listed without keys are revoked: no object can be written for them anymore. Tenants removed from the file
+// are revoked as well until the keyring is loaded again by a new process, so revoking a tenant should keep it in the
+// file with an empty list.
+type keyringFile struct {
+	Default []keyringKey            `yaml:"default"`
+	Tenants map[string][]keyringKey `yaml:"tenants"`
+}
+
+type keyringKey struct {
+	ID  string `yaml:"id"`
+	Key string `yaml:"key"`
+}
+
+// Keyring is a KMS backed by a local keyring file. The file is reloaded when it changes.
+type Keyring struct {
+	path         string
+	reloadPeriod time.Duration
+	now          func() time.Time
+
+	mtx     sync.RWMutex
+	keys    map[string]cipher.AEAD
+	current map[string]string
+	// tenants which are or were listed in the file.
+	tenants   map[string]struct{}
+	modTime   time.Time
+	lastCheck time.Time
+}
+
+// NewKeyring loads the keyring file of the config.
+func NewKeyring(cfg KeyringConfig) (*Keyring, error) {
+	k := &Keyring{
+		path:         cfg.Path,
+		reloadPeriod: cfg.ReloadPeriod,
+		now:          time.Now,
+	}
+	if err := k.load(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (k *Keyring) WrapKey(_ context.Context, tenant string, dataKey []byte) (string, []byte, error) {
+	k.maybeReload()
+
+	k.mtx.RLock()
+	defer k.mtx.RUnlock()
+
+	keyID, err := k.currentKeyID(tenant)
+	if err != nil {
+		return "", nil, err
+	}
+
+	aead := k.keys[keyID]
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(dataKey)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, err
+	}
+	return keyID, aead.Seal(nonce, nonce, dataKey, []byte(keyID)), nil
+}
+
+func (k *Keyring) UnwrapKey(_ context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	k.maybeReload()
+
+	k.mtx.RLock()
+	aead, ok := k.keys[keyID]
+	k.mtx.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, keyID)
+	}
+
+	if len(wrapped) < aead.NonceSize() {
+		return nil, fmt.Errorf("invalid wrapped key")
+	}
+	nonce, ciphertext := wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, []byte(keyID))
+}
+
+func (k *Keyring) CurrentKeyID(_ context.Context, tenant string) (string, error) {
+	k.maybeReload()
+
+	k.mtx.RLock()
+	defer k.mtx.RUnlock()
+	return k.currentKeyID(tenant)
+}
+
+func (k *Keyring) currentKeyID(tenant string) (string, error) {
+	if keyID, ok := k.current[tenant]; ok {
+		return keyID, nil
+	}
+	if _, ok := k.tenants[tenant]; ok && tenant != "" {
+		return "", fmt.Errorf("%w: tenant %q has no key left", ErrKeyRevoked, tenant)
+	}
+	if keyID, ok := k.current[""]; ok {
+		return keyID, nil
+	}
+	return "", fmt.Errorf("%w: no key for tenant %q and no default key", ErrKeyNotFound, tenant)
+}
+
+// maybeReload reloads the keyring file if it changed since it was loaded, at most once per reload period.
+func (k *Keyring) maybeReload() {
+	now := k.now()
+
+	k.mtx.Lock()
+	if now.Sub(k.lastCheck) < k.reloadPeriod {
+		k.mtx.Unlock()
+		return
+	}
+	k.lastCheck = now
+	modTime := k.modTime
+	k.mtx.Unlock()
+
+	info, err := os.Stat(k.path)
+	if err != nil {
+		level.Warn(util_log.Logger).Log("msg", "failed to check keyring file for changes", "path", k.path, "err", err)
+		return
+	}
+	if info.ModTime().Equal(modTime) {
+		return
+	}
+	if err := k.load(); err != nil {
+		level.Warn(util_log.Logger).Log("msg", "failed to reload keyring file, keeping the previous keys", "path", k.path, "err", err)
+	}
+}
+
+func (k *Keyring) load() error {
+	info, err := os.Stat(k.path)
+	if err != nil {
+		return err
+	}
+	buf, err := os.ReadFile(k.path)
+	if err != nil {
+		return err
+	}
+
+	var f keyringFile
+	if err := yaml.UnmarshalStrict(buf, &f); err != nil {
+		return fmt.Errorf("failed to parse keyring file %s: %w", k.path, err)
+	}
+
+	keys := map[string]cipher.AEAD{}
+	current := map[string]string{}
+	tenants := map[string]struct{}{}
+	add := func(tenant, prefix string, list []keyringKey) error {
+		for _, key := range list {
+			if key.ID == "" || strings.Contains(key.ID, "/") {
+				return fmt.Errorf("invalid key id %q", key.ID)
+			}
+			raw, err := base64.StdEncoding.DecodeString(key.Key)
+			if err != nil {
+				return fmt.Errorf("failed to decode key %s%s: %w", prefix, key.ID, err)
+			}
+			if len(raw) != keySize {
+				return fmt.Errorf("key %s%s must be %d bytes long", prefix, key.ID, keySize)
+			}
+			block, err := aes.NewCipher(raw)
+			if err != nil {
+				return err
+			}
+			aead, err := cipher.NewGCM(block)
+			if err != nil {
+				return err
+			}
+
+			keyID := prefix + key.ID
+			keys[keyID] = aead
+			current[tenant] = keyID
+		}
+		return nil
+	}
+
+	if err := add("", defaultKeyPrefix, f.Default); err != nil {
+		return err
+	}
+	for tenant, list := range f.Tenants {
+		if err := add(tenant, tenantKeyPrefix+tenant+"/", list); err != nil {
+			return err
+		}
+		tenants[tenant] = struct{}{}
+	}
+
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+	for tenant := range k.tenants {
+		tenants[tenant] = struct{}{}
+	}
+	k.keys = keys
+	k.current = current
+	k.tenants = tenants
+	k.modTime = info.ModTime()
+	k.lastCheck = k.now()
+	return nil
+}
@@ -0,0 +1,145 @@
+package encryption
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKey(t *testing.T) string {
+	key := make([]byte, keySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+// writeKeyring writes a keyring file with the given default keys and tenant keys, where each list of keys is
+// formatted as a comma separated list of key ids.
+func writeKeyring(t *testing.T, path string, keys map[string]string, defaultKeys string, tenantKeys map[string]string) {
+	var sb strings.Builder
+	writeKeys := func(indent, ids string) {
+		if ids == "" {
+			return
+		}
+		for _, id := range strings.Split(ids, ",") {
+			if _, ok := keys[id]; !ok {
+				keys[id] = newTestKey(t)
+			}
+			fmt.Fprintf(&sb, "%s- id: %q\n%s  key: %s\n", indent, id, indent, keys[id])
+		}
+	}
+
+	if defaultKeys != "" {
+		sb.WriteString("default:\n")
+		writeKeys("  ", defaultKeys)
+	}
+	if len(tenantKeys) > 0 {
+		sb.WriteString("tenants:\n")
+		for tenant, ids := range tenantKeys {
+			fmt.Fprintf(&sb, "  %s:\n", tenant)
+			writeKeys("    ", ids)
+		}
+	}
+	require.NoError(t, os.WriteFile(path, []byte(sb.String()), 0o600))
+}
+
+func TestKeyring(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "keyring.yaml")
+	keys := map[string]string{}
+	writeKeyring(t, path, keys, "d1", map[string]string{"tenant-a": "a1,a2"})
+
+	k, err := NewKeyring(KeyringConfig{Path: path, ReloadPeriod: time.Minute})
+	require.NoError(t, err)
+	now := time.Now()
+	k.now = func() time.Time { return now }
+
+	for _, tc := range []struct {
+		tenant, keyID string
+	}{
+		{tenant: "tenant-a", keyID: "tenant/tenant-a/a2"},
+		{tenant: "tenant-b", keyID: "default/d1"},
+		{tenant: "", keyID: "default/d1"},
+	} {
+		keyID, err := k.CurrentKeyID(ctx, tc.tenant)
+		require.NoError(t, err)
+		require.Equal(t, tc.keyID, keyID)
+
+		dataKey := []byte("data key of " + tc.tenant)
+		keyID, wrapped, err := k.WrapKey(ctx, tc.tenant, dataKey)
+		require.NoError(t, err)
+		require.Equal(t, tc.keyID, keyID)
+
+		unwrapped, err := k.UnwrapKey(ctx, keyID, wrapped)
+		require.NoError(t, err)
+		require.Equal(t, dataKey, unwrapped)
+
+		// the key id is authenticated along with the wrapped key.
+		_, err = k.UnwrapKey(ctx, "tenant/tenant-a/a1", wrapped)
+		require.Error(t, err)
+	}
+
+	_, wrapped, err := k.WrapKey(ctx, "tenant-a", []byte("data key"))
+	require.NoError(t, err)
+
+	// destroy the keys of tenant-a.
+	writeKeyring(t, path, keys, "d1", nil)
+	require.NoError(t, os.Chtimes(path, now, now.Add(time.Hour)))
+
+	// changes are picked up once the reload period elapsed.
+	_, err = k.UnwrapKey(ctx, "tenant/tenant-a/a2", wrapped)
+	require.NoError(t, err)
+
+	now = now.Add(time.Minute)
+	_, err = k.UnwrapKey(ctx, "tenant/tenant-a/a2", wrapped)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	// tenants which had keys don't fall back to the default key.
+	_, err = k.CurrentKeyID(ctx, "tenant-a")
+	require.ErrorIs(t, err, ErrKeyRevoked)
+	_, _, err = k.WrapKey(ctx, "tenant-a", []byte("data key"))
+	require.ErrorIs(t, err, ErrKeyRevoked)
+
+	keyID, err := k.CurrentKeyID(ctx, "tenant-b")
+	require.NoError(t, err)
+	require.Equal(t, "default/d1", keyID)
+
+	// invalid keyring files are ignored on reload.
+	require.NoError(t, os.WriteFile(path, []byte("default: [{id: x, key: invalid}]"), 0o600))
+	require.NoError(t, os.Chtimes(path, now, now.Add(2*time.Hour)))
+	now = now.Add(time.Minute)
+	keyID, err = k.CurrentKeyID(ctx, "tenant-b")
+	require.NoError(t, err)
+	require.Equal(t, "default/d1", keyID)
+}
+
+func TestKeyring_RevokedTenant(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keyring.yaml")
+	writeKeyring(t, path, map[string]string{}, "d1", map[string]string{"tenant-a": ""})
+
+	// tenants listed without keys are revoked when the keyring is loaded by a new process.
+	k, err := NewKeyring(KeyringConfig{Path: path})
+	require.NoError(t, err)
+
+	_, err = k.CurrentKeyID(context.Background(), "tenant-a")
+	require.ErrorIs(t, err, ErrKeyRevoked)
+}
+
+func TestKeyring_NoDefaultKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keyring.yaml")
+	writeKeyring(t, path, map[string]string{}, "", map[string]string{"tenant-a": "a1"})
+
+	k, err := NewKeyring(KeyringConfig{Path: path})
+	require.NoError(t, err)
+
+	_, _, err = k.WrapKey(context.Background(), "tenant-b", []byte("data key"))
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
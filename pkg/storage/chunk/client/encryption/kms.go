@@ -0,0 +1,54 @@
+package encryption
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrKeyNotFound is returned when unwrapping a data key wrapped with a key which doesn't exist (anymore).
+var ErrKeyNotFound = errors.New("encryption key not found")
+
+// ErrKeyRevoked is returned when wrapping a data key for a tenant whose keys were all destroyed.
+var ErrKeyRevoked = errors.New("encryption keys revoked")
+
+// KMS wraps the data keys of objects with the keys of their tenants.
+// Destroying the keys of a tenant in the KMS makes all data keys wrapped with them, and so the objects of the tenant,
+// unreadable.
+type KMS interface {
+	// WrapKey wraps the data key with the current key of the tenant, and returns the ID of the key it was wrapped with.
+	// An empty tenant is used for objects which don't belong to a single tenant.
+	WrapKey(ctx context.Context, tenant string, dataKey []byte) (keyID string, wrapped []byte, err error)
+	// UnwrapKey unwraps a data key wrapped with the key with the given ID.
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+	// CurrentKeyID returns the ID of the key new data keys of the tenant are wrapped with.
+	CurrentKeyID(ctx context.Context, tenant string) (string, error)
+}
+
+var (
+	keyringsMtx sync.Mutex
+	keyrings    = map[string]*Keyring{}
+)
+
+// NewKMS returns the KMS of the config.
+// Object clients of the same process share the KMS of a keyring file.
+func NewKMS(cfg Config) (KMS, error) {
+	switch cfg.KMS {
+	case KMSKeyring:
+		keyringsMtx.Lock()
+		defer keyringsMtx.Unlock()
+
+		if k, ok := keyrings[cfg.Keyring.Path]; ok {
+			return k, nil
+		}
+		k, err := NewKeyring(cfg.Keyring)
+		if err != nil {
+			return nil, err
+		}
+		keyrings[cfg.Keyring.Path] = k
+		return k, nil
+	default:
+		return nil, fmt.Errorf("unsupported kms: %s", cfg.KMS)
+	}
+}
@@ -0,0 +1,511 @@
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/grafana/loki/v3/pkg/storage/chunk/client"
+)
+
+// Encrypted objects are made of a header followed by the payload encrypted with the data key of the object:
+//
+//	magic | version | uvarint len(key id) | key id | uvarint len(wrapped data key) | wrapped data key | nonce prefix | segments
+//
+// The payload is split in segments of segmentSize bytes, each encrypted with AES-256-GCM, so that objects can be
+// decrypted while they are read. The nonce of a segment is the nonce prefix of the object, followed by the index of
+// the segment and a flag set on the last segment, which protects against reordered and truncated segments.
+const (
+	formatV1 = byte(1)
+
+	dataKeySize     = 32
+	noncePrefixSize = 7
+	segmentSize     = 64 << 10
+	tagSize         = 16
+
+	maxHeaderFieldSize = 1 << 10
+	// maxHeaderSize is the size of the largest header readHeader accepts.
+	maxHeaderSize = len(magic) + 1 + 2*(2+maxHeaderFieldSize) + noncePrefixSize
+)
+
+const magic = "LKE"
+
+// ObjectClient is an ObjectClient encrypting the objects written to the downstream client with per object data
+// keys, wrapped with the key of the tenant of the object by the KMS. Objects which aren't encrypted are read as is.
+type ObjectClient struct {
+	downstreamClient client.ObjectClient
+	kms              KMS
+}
+
+// NewObjectClient wraps the downstream client with a client encrypting objects at rest.
+func NewObjectClient(downstreamClient client.ObjectClient, kms KMS) *ObjectClient {
+	return &ObjectClient{downstreamClient: downstreamClient, kms: kms}
+}
+
+// PutObject encrypts the object while the downstream client reads it, so that only one segment of the object is held
+// in memory. The object must not change until PutObject returns.
+func (c *ObjectClient) PutObject(ctx context.Context, objectKey string, object io.ReadSeeker) error {
+	size, err := object.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return err
+	}
+	keyID, wrapped, err := c.kms.WrapKey(ctx, client.ObjectTenant(ctx), dataKey)
+	if err != nil {
+		return fmt.Errorf("failed to wrap data key of object %s: %w", objectKey, err)
+	}
+
+	noncePrefix := make([]byte, noncePrefixSize)
+	if _, err := rand.Read(noncePrefix); err != nil {
+		return err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return err
+	}
+
+	header := bytes.NewBuffer(make([]byte, 0, headerSize(keyID, wrapped)))
+	writeHeader(header, keyID, wrapped, noncePrefix)
+
+	return c.downstreamClient.PutObject(ctx, objectKey, newEncryptingReader(object, size, header.Bytes(), aead, noncePrefix))
+}
+
+func (c *ObjectClient) GetObject(ctx context.Context, objectKey string) (io.ReadCloser, int64, error) {
+	rc, size, err := c.downstreamClient.GetObject(ctx, objectKey)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	br := bufio.NewReaderSize(rc, segmentSize+tagSize)
+	if !isEncrypted(br) {
+		return readCloser{Reader: br, Closer: rc}, size, nil
+	}
+
+	h, err := readHeader(br)
+	if err != nil {
+		rc.Close()
+		return nil, 0, fmt.Errorf("failed to read header of encrypted object %s: %w", objectKey, err)
+	}
+	dataKey, err := c.kms.UnwrapKey(ctx, h.keyID, h.wrapped)
+	if err != nil {
+		rc.Close()
+		return nil, 0, fmt.Errorf("failed to unwrap data key of object %s: %w", objectKey, err)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		rc.Close()
+		return nil, 0, err
+	}
+
+	if size > 0 {
+		size = plaintextSize(size - int64(headerSize(h.keyID, h.wrapped)))
+	}
+	return readCloser{Reader: &decryptingReader{src: br, aead: aead, noncePrefix: h.noncePrefix}, Closer: rc}, size, nil
+}
+
+// GetObjectRange returns a reader for length bytes of the plaintext of the object, starting at offset.
+// The header of encrypted objects is read first, then only the segments holding the range are read and decrypted.
+func (c *ObjectClient) GetObjectRange(ctx context.Context, objectKey string, offset, length int64) (io.ReadCloser, error) {
+	rc, err := client.GetObjectRange(ctx, c.downstreamClient, objectKey, 0, int64(maxHeaderSize))
+	if err != nil {
+		return nil, err
+	}
+	br := bufio.NewReaderSize(rc, maxHeaderSize)
+	if !isEncrypted(br) {
+		rc.Close()
+		return client.GetObjectRange(ctx, c.downstreamClient, objectKey, offset, length)
+	}
+
+	h, err := readHeader(br)
+	rc.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header of encrypted object %s: %w", objectKey, err)
+	}
+	dataKey, err := c.kms.UnwrapKey(ctx, h.keyID, h.wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key of object %s: %w", objectKey, err)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	first, last := offset/segmentSize, (offset+max(length, 1)-1)/segmentSize
+	rc, err = client.GetObjectRange(ctx, c.downstreamClient, objectKey, int64(headerSize(h.keyID, h.wrapped))+first*(segmentSize+tagSize), (last-first+1)*(segmentSize+tagSize))
+	if err != nil {
+		return nil, err
+	}
+	r := &decryptingReader{
+		src:         bufio.NewReaderSize(rc, segmentSize+tagSize),
+		aead:        aead,
+		noncePrefix: h.noncePrefix,
+		segment:     uint32(first),
+		partial:     true,
+	}
+	if _, err := io.CopyN(io.Discard, r, offset-first*segmentSize); err != nil {
+		rc.Close()
+		return nil, err
+	}
+	return readCloser{Reader: io.LimitReader(r, length), Closer: rc}, nil
+}
+
+func (c *ObjectClient) ObjectExists(ctx context.Context, objectKey string) (bool, error) {
+	return c.downstreamClient.ObjectExists(ctx, objectKey)
+}
+
+func (c *ObjectClient) List(ctx context.Context, prefix, delimiter string) ([]client.StorageObject, []client.StorageCommonPrefix, error) {
+	return c.downstreamClient.List(ctx, prefix, delimiter)
+}
+
+func (c *ObjectClient) DeleteObject(ctx context.Context, objectKey string) error {
+	return c.downstreamClient.DeleteObject(ctx, objectKey)
+}
+
+func (c *ObjectClient) IsObjectNotFoundErr(err error) bool {
+	return c.downstreamClient.IsObjectNotFoundErr(err)
+}
+
+func (c *ObjectClient) IsRetryableErr(err error) bool {
+	return c.downstreamClient.IsRetryableErr(err)
+}
+
+func (c *ObjectClient) Stop() {
+	c.downstreamClient.Stop()
+}
+
+func (c *ObjectClient) GetDownstream() client.ObjectClient {
+	return c.downstreamClient
+}
+
+// CurrentKeyID returns the ID of the key the data keys of new objects of the tenant are wrapped with.
+func (c *ObjectClient) CurrentKeyID(ctx context.Context, tenant string) (string, error) {
+	return c.kms.CurrentKeyID(ctx, tenant)
+}
+
+// Rewrap makes sure the object is encrypted with the current key of the tenant: objects which aren't encrypted yet
+// are encrypted, and the data keys of objects encrypted with another key are re-wrapped with the current key.
+// The payload of encrypted objects is left untouched. It returns whether the object was rewritten.
+func (c *ObjectClient) Rewrap(ctx context.Context, tenant, objectKey string) (bool, error) {
+	currentKeyID, err := c.kms.CurrentKeyID(ctx, tenant)
+	if err != nil {
+		return false, err
+	}
+
+	rc, _, err := c.downstreamClient.GetObject(ctx, objectKey)
+	if err != nil {
+		return false, err
+	}
+	object, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return false, err
+	}
+
+	br := bufio.NewReader(bytes.NewReader(object))
+	if !isEncrypted(br) {
+		return true, c.PutObject(client.InjectObjectTenant(ctx, tenant), objectKey, bytes.NewReader(object))
+	}
+
+	h, err := readHeader(br)
+	if err != nil {
+		return false, fmt.Errorf("failed to read header of encrypted object %s: %w", objectKey, err)
+	}
+	if h.keyID == currentKeyID {
+		return false, nil
+	}
+
+	dataKey, err := c.kms.UnwrapKey(ctx, h.keyID, h.wrapped)
+	if err != nil {
+		return false, fmt.Errorf("failed to unwrap data key of object %s: %w", objectKey, err)
+	}
+	keyID, wrapped, err := c.kms.WrapKey(ctx, tenant, dataKey)
+	if err != nil {
+		return false, fmt.Errorf("failed to wrap data key of object %s: %w", objectKey, err)
+	}
+
+	payload := object[headerSize(h.keyID, h.wrapped):]
+	buf := bytes.NewBuffer(make([]byte, 0, headerSize(keyID, wrapped)+len(payload)))
+	writeHeader(buf, keyID, wrapped, h.noncePrefix)
+	buf.Write(payload)
+
+	return true, c.downstreamClient.PutObject(ctx, objectKey, bytes.NewReader(buf.Bytes()))
+}
+
+type header struct {
+	keyID       string
+	wrapped     []byte
+	noncePrefix []byte
+}
+
+func isEncrypted(br *bufio.Reader) bool {
+	b, err := br.Peek(len(magic) + 1)
+	return err == nil && string(b[:len(magic)]) == magic && b[len(magic)] == formatV1
+}
+
+func headerSize(keyID string, wrapped []byte) int {
+	return len(magic) + 1 +
+		uvarintSize(uint64(len(keyID))) + len(keyID) +
+		uvarintSize(uint64(len(wrapped))) + len(wrapped) +
+		noncePrefixSize
+}
+
+func uvarintSize(v uint64) int {
+	var buf [binary.MaxVarintLen64]byte
+	return binary.PutUvarint(buf[:], v)
+}
+
+func writeHeader(buf *bytes.Buffer, keyID string, wrapped, noncePrefix []byte) {
+	var lenBuf [binary.MaxVarintLen64]byte
+
+	buf.WriteString(magic)
+	buf.WriteByte(formatV1)
+	buf.Write(lenBuf[:binary.PutUvarint(lenBuf[:], uint64(len(keyID)))])
+	buf.WriteString(keyID)
+	buf.Write(lenBuf[:binary.PutUvarint(lenBuf[:], uint64(len(wrapped)))])
+	buf.Write(wrapped)
+	buf.Write(noncePrefix)
+}
+
+func readHeader(br *bufio.Reader) (header, error) {
+	var h header
+	if _, err := br.Discard(len(magic) + 1); err != nil {
+		return h, err
+	}
+
+	readBytes := func() ([]byte, error) {
+		l, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, err
+		}
+		if l > maxHeaderFieldSize {
+			return nil, fmt.Errorf("invalid header field length %d", l)
+		}
+		b := make([]byte, l)
+		_, err = io.ReadFull(br, b)
+		return b, err
+	}
+
+	keyID, err := readBytes()
+	if err != nil {
+		return h, err
+	}
+	h.keyID = string(keyID)
+	if h.wrapped, err = readBytes(); err != nil {
+		return h, err
+	}
+	h.noncePrefix = make([]byte, noncePrefixSize)
+	if _, err := io.ReadFull(br, h.noncePrefix); err != nil {
+		return h, err
+	}
+	return h, nil
+}
+
+func newAEAD(dataKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func segmentNonce(noncePrefix []byte, segment uint32, last bool) []byte {
+	nonce := make([]byte, noncePrefixSize+5)
+	copy(nonce, noncePrefix)
+	binary.BigEndian.PutUint32(nonce[noncePrefixSize:], segment)
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce
+}
+
+// encryptingReader is the encrypted object of a plaintext object, made of its header followed by the segments of the
+// plaintext encrypted as they are read. Seeking only encrypts the segment the new position is in again, which
+// produces the same ciphertext as long as the plaintext doesn't change.
+type encryptingReader struct {
+	src         io.ReadSeeker
+	srcSize     int64
+	header      []byte
+	aead        cipher.AEAD
+	noncePrefix []byte
+	segments    int64
+
+	pos     int64
+	segment int64
+	plain   []byte
+	sealed  []byte
+}
+
+func newEncryptingReader(src io.ReadSeeker, srcSize int64, header []byte, aead cipher.AEAD, noncePrefix []byte) *encryptingReader {
+	return &encryptingReader{
+		src:         src,
+		srcSize:     srcSize,
+		header:      header,
+		aead:        aead,
+		noncePrefix: noncePrefix,
+		segments:    max((srcSize+segmentSize-1)/segmentSize, 1),
+		segment:     -1,
+	}
+}
+
+func (r *encryptingReader) size() int64 {
+	return int64(len(r.header)) + encryptedSize(r.srcSize)
+}
+
+func (r *encryptingReader) Read(p []byte) (int, error) {
+	if r.pos >= r.size() {
+		return 0, io.EOF
+	}
+	if r.pos < int64(len(r.header)) {
+		n := copy(p, r.header[r.pos:])
+		r.pos += int64(n)
+		return n, nil
+	}
+
+	offset := r.pos - int64(len(r.header))
+	segment := offset / (segmentSize + tagSize)
+	if segment != r.segment {
+		if err := r.seal(segment); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.sealed[offset-segment*(segmentSize+tagSize):])
+	r.pos += int64(n)
+	return n, nil
+}
+
+// seal encrypts the given segment of the plaintext.
+func (r *encryptingReader) seal(segment int64) error {
+	if _, err := r.src.Seek(segment*segmentSize, io.SeekStart); err != nil {
+		return err
+	}
+	if r.plain == nil {
+		r.plain = make([]byte, segmentSize)
+		r.sealed = make([]byte, 0, segmentSize+tagSize)
+	}
+	n, err := io.ReadFull(r.src, r.plain[:min(segmentSize, r.srcSize-segment*segmentSize)])
+	if err != nil {
+		return fmt.Errorf("failed to read segment %d of object: %w", segment, err)
+	}
+
+	r.sealed = r.aead.Seal(r.sealed[:0], segmentNonce(r.noncePrefix, uint32(segment), segment == r.segments-1), r.plain[:n], nil)
+	r.segment = segment
+	return nil
+}
+
+func (r *encryptingReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.pos
+	case io.SeekEnd:
+		offset += r.size()
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	r.pos = offset
+	return offset, nil
+}
+
+// encryptedSize returns the size of the encrypted payload of a plaintext of the given size.
+func encryptedSize(size int64) int64 {
+	segments := (size + segmentSize - 1) / segmentSize
+	if segments == 0 {
+		segments = 1
+	}
+	return size + segments*tagSize
+}
+
+// plaintextSize returns the size of the plaintext of an encrypted payload of the given size.
+func plaintextSize(size int64) int64 {
+	full, rem := size/(segmentSize+tagSize), size%(segmentSize+tagSize)
+	if rem == 0 {
+		return full * segmentSize
+	}
+	return full*segmentSize + rem - tagSize
+}
+
+// decryptingReader decrypts the segments of an encrypted payload as they are read.
+type decryptingReader struct {
+	src         *bufio.Reader
+	aead        cipher.AEAD
+	noncePrefix []byte
+	// partial is set when src may end before the last segment of the payload, e.g. for range reads, so the last
+	// segment read may or may not be the last segment of the payload.
+	partial bool
+
+	segment uint32
+	buf     []byte
+	plain   []byte
+	done    bool
+	err     error
+}
+
+func (r *decryptingReader) Read(p []byte) (int, error) {
+	for len(r.plain) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.done {
+			return 0, io.EOF
+		}
+		r.err = r.next()
+	}
+
+	n := copy(p, r.plain)
+	r.plain = r.plain[n:]
+	return n, nil
+}
+
+func (r *decryptingReader) next() error {
+	if r.buf == nil {
+		r.buf = make([]byte, segmentSize+tagSize)
+	}
+
+	n, err := io.ReadFull(r.src, r.buf)
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.EOF):
+		r.done = true
+	case err != nil:
+		return err
+	default:
+		if _, err := r.src.Peek(1); errors.Is(err, io.EOF) {
+			r.done = true
+		}
+	}
+
+	var plain []byte
+	if r.done && r.partial {
+		// decrypt to another buffer, as a failed attempt may overwrite it.
+		plain, err = r.aead.Open(nil, segmentNonce(r.noncePrefix, r.segment, false), r.buf[:n], nil)
+		if err != nil {
+			plain, err = r.aead.Open(nil, segmentNonce(r.noncePrefix, r.segment, true), r.buf[:n], nil)
+		}
+	} else {
+		plain, err = r.aead.Open(r.buf[:0], segmentNonce(r.noncePrefix, r.segment, r.done), r.buf[:n], nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to decrypt segment %d: %w", r.segment, err)
+	}
+	r.segment++
+	r.plain = plain
+	return nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
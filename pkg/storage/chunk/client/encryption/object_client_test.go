@@ -0,0 +1,218 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/storage/chunk/client"
+	"github.com/grafana/loki/v3/pkg/storage/chunk/client/testutils"
+)
+
+func newTestObjectClient(t *testing.T, tenantKeys map[string]string) (*ObjectClient, *testutils.InMemoryObjectClient, func(map[string]string)) {
+	path := filepath.Join(t.TempDir(), "keyring.yaml")
+	keys := map[string]string{}
+	writeKeyring(t, path, keys, "d1", tenantKeys)
+
+	k, err := NewKeyring(KeyringConfig{Path: path})
+	require.NoError(t, err)
+
+	downstream := testutils.NewInMemoryObjectClient()
+	update := func(tenantKeys map[string]string) {
+		writeKeyring(t, path, keys, "d1", tenantKeys)
+		require.NoError(t, k.load())
+	}
+	return NewObjectClient(downstream, k), downstream, update
+}
+
+func readObject(t *testing.T, c client.ObjectClient, key string) ([]byte, int64, error) {
+	rc, size, err := c.GetObject(context.Background(), key)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	return data, size, err
+}
+
+func TestObjectClient_RoundTrip(t *testing.T) {
+	c, downstream, _ := newTestObjectClient(t, map[string]string{"tenant-a": "a1"})
+	ctx := client.InjectObjectTenant(context.Background(), "tenant-a")
+
+	for _, size := range []int{0, 1, segmentSize - 1, segmentSize, segmentSize + 1, 3*segmentSize + 42} {
+		data := make([]byte, size)
+		_, err := rand.Read(data)
+		require.NoError(t, err)
+
+		require.NoError(t, c.PutObject(ctx, "object", bytes.NewReader(data)))
+
+		stored, _, err := readObject(t, downstream, "object")
+		require.NoError(t, err)
+		wrappedSize := 12 + dataKeySize + tagSize
+		require.Equal(t, int64(headerSize("tenant/tenant-a/a1", make([]byte, wrappedSize)))+encryptedSize(int64(size)), int64(len(stored)))
+		if size > segmentSize {
+			require.False(t, bytes.Contains(stored, data[:segmentSize]))
+		}
+
+		read, readSize, err := readObject(t, c, "object")
+		require.NoError(t, err)
+		require.Equal(t, data, read)
+		require.Equal(t, int64(size), readSize)
+	}
+}
+
+func TestObjectClient_GetObjectRange(t *testing.T) {
+	c, downstream, _ := newTestObjectClient(t, nil)
+	ctx := context.Background()
+
+	data := make([]byte, 3*segmentSize+42)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+	require.NoError(t, c.PutObject(ctx, "encrypted", bytes.NewReader(data)))
+	require.NoError(t, downstream.PutObject(ctx, "plaintext", bytes.NewReader(data)))
+
+	for _, tc := range []struct {
+		desc           string
+		offset, length int64
+	}{
+		{desc: "within a segment", offset: 10, length: 100},
+		{desc: "across segments", offset: segmentSize - 10, length: segmentSize + 20},
+		{desc: "last segment", offset: 3*segmentSize + 2, length: 40},
+		{desc: "up to the end", offset: segmentSize, length: 2*segmentSize + 42},
+		{desc: "empty", offset: 5, length: 0},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			for _, key := range []string{"encrypted", "plaintext"} {
+				rc, err := c.GetObjectRange(ctx, key, tc.offset, tc.length)
+				require.NoError(t, err)
+				read, err := io.ReadAll(rc)
+				require.NoError(t, err)
+				require.NoError(t, rc.Close())
+				require.Equal(t, data[tc.offset:tc.offset+tc.length], read, key)
+			}
+		})
+	}
+}
+
+func TestEncryptingReader_Seek(t *testing.T) {
+	data := make([]byte, 2*segmentSize+42)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+	aead, err := newAEAD(make([]byte, dataKeySize))
+	require.NoError(t, err)
+	r := newEncryptingReader(bytes.NewReader(data), int64(len(data)), []byte("header"), aead, make([]byte, noncePrefixSize))
+
+	encrypted, err := io.ReadAll(r)
+	require.NoError(t, err)
+	size, err := r.Seek(0, io.SeekEnd)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(encrypted)), size)
+
+	// segments encrypted again after seeking are identical.
+	for _, pos := range []int64{segmentSize + tagSize + 10, 3, 0, size - 1} {
+		_, err := r.Seek(pos, io.SeekStart)
+		require.NoError(t, err)
+		read, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, encrypted[pos:], read)
+	}
+}
+
+func TestObjectClient_PlaintextObjects(t *testing.T) {
+	c, downstream, _ := newTestObjectClient(t, nil)
+
+	require.NoError(t, downstream.PutObject(context.Background(), "object", bytes.NewReader([]byte("plaintext"))))
+
+	read, size, err := readObject(t, c, "object")
+	require.NoError(t, err)
+	require.Equal(t, []byte("plaintext"), read)
+	require.Equal(t, int64(len("plaintext")), size)
+}
+
+func TestObjectClient_TamperedObject(t *testing.T) {
+	c, downstream, _ := newTestObjectClient(t, nil)
+	require.NoError(t, c.PutObject(context.Background(), "object", bytes.NewReader(make([]byte, 2*segmentSize))))
+
+	stored, _, err := readObject(t, downstream, "object")
+	require.NoError(t, err)
+
+	// drop the last segment.
+	require.NoError(t, downstream.PutObject(context.Background(), "object", bytes.NewReader(stored[:len(stored)-segmentSize-tagSize])))
+	_, _, err = readObject(t, c, "object")
+	require.Error(t, err)
+}
+
+func TestObjectClient_DestroyedKey(t *testing.T) {
+	c, _, updateKeys := newTestObjectClient(t, map[string]string{"tenant-a": "a1", "tenant-b": "b1"})
+
+	for _, tenant := range []string{"tenant-a", "tenant-b"} {
+		require.NoError(t, c.PutObject(client.InjectObjectTenant(context.Background(), tenant), tenant, bytes.NewReader([]byte("data of "+tenant))))
+	}
+
+	updateKeys(map[string]string{"tenant-b": "b1"})
+
+	_, _, err := readObject(t, c, "tenant-a")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	read, _, err := readObject(t, c, "tenant-b")
+	require.NoError(t, err)
+	require.Equal(t, []byte("data of tenant-b"), read)
+}
+
+func TestObjectClient_Rewrap(t *testing.T) {
+	c, downstream, updateKeys := newTestObjectClient(t, map[string]string{"tenant-a": "a1"})
+	ctx := context.Background()
+
+	require.NoError(t, c.PutObject(client.InjectObjectTenant(ctx, "tenant-a"), "encrypted", bytes.NewReader([]byte("encrypted"))))
+	require.NoError(t, downstream.PutObject(ctx, "plaintext", bytes.NewReader([]byte("plaintext"))))
+
+	// objects encrypted with the current key are left untouched, plaintext objects get encrypted.
+	rewritten, err := c.Rewrap(ctx, "tenant-a", "encrypted")
+	require.NoError(t, err)
+	require.False(t, rewritten)
+
+	rewritten, err = c.Rewrap(ctx, "tenant-a", "plaintext")
+	require.NoError(t, err)
+	require.True(t, rewritten)
+	stored, _, err := readObject(t, downstream, "plaintext")
+	require.NoError(t, err)
+	require.NotEqual(t, []byte("plaintext"), stored)
+
+	// rotate the key of the tenant and destroy the previous one once the objects are rewrapped.
+	updateKeys(map[string]string{"tenant-a": "a1,a2"})
+	for _, key := range []string{"encrypted", "plaintext"} {
+		rewritten, err := c.Rewrap(ctx, "tenant-a", key)
+		require.NoError(t, err)
+		require.True(t, rewritten)
+	}
+	updateKeys(map[string]string{"tenant-a": "a2"})
+
+	for _, key := range []string{"encrypted", "plaintext"} {
+		read, _, err := readObject(t, c, key)
+		require.NoError(t, err)
+		require.Equal(t, []byte(key), read)
+	}
+}
+
+func TestNewKMS(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keyring.yaml")
+	writeKeyring(t, path, map[string]string{}, "d1", nil)
+
+	cfg := Config{Enabled: true, KMS: KMSKeyring, Keyring: KeyringConfig{Path: path, ReloadPeriod: time.Minute}}
+	k1, err := NewKMS(cfg)
+	require.NoError(t, err)
+	k2, err := NewKMS(cfg)
+	require.NoError(t, err)
+	require.Same(t, k1, k2)
+
+	cfg.KMS = "unknown"
+	_, err = NewKMS(cfg)
+	require.Error(t, err)
+}
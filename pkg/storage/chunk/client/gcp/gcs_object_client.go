@@ -151,6 +151,21 @@ func (s *GCSObjectClient) GetObject(ctx context.Context, objectKey string) (io.R
 	return util.NewReadCloserWithContextCancelFunc(rc, cancel), size, nil
 }
 
+// GetObjectRange returns a reader for the given range of the object.
+func (s *GCSObjectClient) GetObjectRange(ctx context.Context, objectKey string, offset, length int64) (io.ReadCloser, error) {
+	var cancel context.CancelFunc = func() {}
+	if s.cfg.RequestTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.RequestTimeout)
+	}
+
+	reader, err := s.getsBuckets.Object(objectKey).NewRangeReader(ctx, offset, length)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return util.NewReadCloserWithContextCancelFunc(reader, cancel), nil
+}
+
 func (s *GCSObjectClient) getObject(ctx context.Context, objectKey string) (rc io.ReadCloser, size int64, err error) {
 	reader, err := s.getsBuckets.Object(objectKey).NewReader(ctx)
 	if err != nil {
@@ -88,6 +88,19 @@ func (f *FSObjectClient) GetObject(_ context.Context, objectKey string) (io.Read
 	return fl, stats.Size(), nil
 }
 
+// GetObjectRange returns a reader for the given range of the object.
+func (f *FSObjectClient) GetObjectRange(_ context.Context, objectKey string, offset, length int64) (io.ReadCloser, error) {
+	fl, err := os.Open(filepath.Join(f.cfg.Directory, filepath.FromSlash(objectKey)))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fl.Seek(offset, io.SeekStart); err != nil {
+		fl.Close()
+		return nil, err
+	}
+	return util.NewLimitedReadCloser(fl, length), nil
+}
+
 // PutObject into the store
 func (f *FSObjectClient) PutObject(_ context.Context, objectKey string, object io.ReadSeeker) error {
 	fullPath := filepath.Join(f.cfg.Directory, filepath.FromSlash(objectKey))
@@ -82,6 +82,26 @@ func (c MetricsChunkClient) PutChunks(ctx context.Context, chunks []chunk.Chunk)
 	return nil
 }
 
+// PutSegment stores the chunks in a segment object if the underlying client supports it.
+func (c MetricsChunkClient) PutSegment(ctx context.Context, userID string, chunks []chunk.Chunk) error {
+	segmentClient, ok := c.Client.(SegmentClient)
+	if !ok {
+		return ErrMethodNotImplemented
+	}
+	if err := segmentClient.PutSegment(ctx, userID, chunks); err != nil {
+		return err
+	}
+
+	size := 0
+	for _, c := range chunks {
+		size += c.Data.Size()
+	}
+	c.metrics.chunksSizePutPerUser.WithLabelValues(userID).Add(float64(size))
+	c.metrics.chunksPutPerUser.WithLabelValues(userID).Add(float64(len(chunks)))
+
+	return nil
+}
+
 func (c MetricsChunkClient) GetChunks(ctx context.Context, chunks []chunk.Chunk) ([]chunk.Chunk, error) {
 	chks, err := c.Client.GetChunks(ctx, chunks)
 	if err != nil {
@@ -40,6 +40,45 @@ type ObjectClient interface {
 	Stop()
 }
 
+// RangeObjectClient is implemented by object clients which can read a range of an object.
+type RangeObjectClient interface {
+	// GetObjectRange returns a reader for length bytes of the object, starting at offset.
+	// NOTE: The consumer of GetObjectRange should always call the Close method when it is done reading.
+	GetObjectRange(ctx context.Context, objectKey string, offset, length int64) (io.ReadCloser, error)
+}
+
+// GetObjectRange returns a reader for length bytes of the object, starting at offset.
+// Object clients which can't read a range of an object read it from the start, skipping the bytes before the range.
+func GetObjectRange(ctx context.Context, objectClient ObjectClient, objectKey string, offset, length int64) (io.ReadCloser, error) {
+	if rangeClient, ok := objectClient.(RangeObjectClient); ok {
+		return rangeClient.GetObjectRange(ctx, objectKey, offset, length)
+	}
+
+	readCloser, _, err := objectClient.GetObject(ctx, objectKey)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.CopyN(io.Discard, readCloser, offset); err != nil {
+		readCloser.Close()
+		return nil, err
+	}
+	return util.NewLimitedReadCloser(readCloser, length), nil
+}
+
+type objectTenantContextKey struct{}
+
+// InjectObjectTenant returns a context for writing objects holding data of the given tenant.
+// Object clients encrypting objects at rest use it to select the key of the tenant.
+func InjectObjectTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, objectTenantContextKey{}, tenant)
+}
+
+// ObjectTenant returns the tenant of the objects written with the context, if any.
+func ObjectTenant(ctx context.Context) string {
+	tenant, _ := ctx.Value(objectTenantContextKey{}).(string)
+	return tenant
+}
+
 // StorageObject represents an object being stored in an Object Store
 type StorageObject struct {
 	Key        string
@@ -133,6 +172,7 @@ func (o *client) PutChunks(ctx context.Context, chunks []chunk.Chunk) error {
 	incomingErrors := make(chan error)
 	for i := range chunkBufs {
 		go func(i int) {
+			ctx := InjectObjectTenant(ctx, chunks[i].UserID)
 			incomingErrors <- o.store.PutObject(ctx, chunkKeys[i], bytes.NewReader(chunkBufs[i]))
 		}(i)
 	}
@@ -147,6 +187,23 @@ func (o *client) PutChunks(ctx context.Context, chunks []chunk.Chunk) error {
 	return lastErr
 }
 
+// PutSegment stores the chunks of the tenant in a single segment object instead of one object per chunk, and records
+// the range of each chunk within the segment in its ChunkRef.
+func (o *client) PutSegment(ctx context.Context, userID string, chunks []chunk.Chunk) error {
+	segmentID, err := newSegmentID()
+	if err != nil {
+		return err
+	}
+
+	buf, err := encodeSegment(o.schema, segmentID, chunks)
+	if err != nil {
+		return err
+	}
+
+	ctx = InjectObjectTenant(ctx, userID)
+	return o.store.PutObject(ctx, SegmentKey(userID, segmentID), bytes.NewReader(buf))
+}
+
 // GetChunks retrieves the specified chunks from the configured backend
 func (o *client) GetChunks(ctx context.Context, chunks []chunk.Chunk) ([]chunk.Chunk, error) {
 	getChunkMaxParallel := o.getChunkMaxParallel
@@ -161,12 +218,24 @@ func (o *client) getChunk(ctx context.Context, decodeContext *chunk.DecodeContex
 		return chunk.Chunk{}, ctx.Err()
 	}
 
-	key := o.schema.ExternalKey(c.ChunkRef)
-	if o.keyEncoder != nil {
-		key = o.keyEncoder(o.schema, c)
+	var (
+		key        string
+		readCloser io.ReadCloser
+		size       int64
+		err        error
+	)
+	if c.SegmentLength > 0 {
+		// the chunk is stored in a segment object with other chunks.
+		key = SegmentKey(c.UserID, c.SegmentID)
+		size = int64(c.SegmentLength)
+		readCloser, err = GetObjectRange(ctx, o.store, key, int64(c.SegmentOffset), size)
+	} else {
+		key = o.schema.ExternalKey(c.ChunkRef)
+		if o.keyEncoder != nil {
+			key = o.keyEncoder(o.schema, c)
+		}
+		readCloser, size, err = o.store.GetObject(ctx, key)
 	}
-
-	readCloser, size, err := o.store.GetObject(ctx, key)
 	if err != nil {
 		return chunk.Chunk{}, errors.WithStack(errors.Wrapf(err, "failed to load chunk '%s'", key))
 	}
@@ -0,0 +1,147 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+
+	"github.com/grafana/loki/v3/pkg/storage/chunk"
+	"github.com/grafana/loki/v3/pkg/storage/config"
+	"github.com/grafana/loki/v3/pkg/util/encoding"
+)
+
+// Segment objects hold many chunks of a tenant flushed together, followed by a table of the offsets of the chunks
+// within the segment:
+//
+//	┌─────────┬─────┬─────────┬──────────────────────────┬───────────────────────┐
+//	│ chunk 1 │ ... │ chunk n │ offset table │ crc32 (4) │ table offset (8) │ magic (4) │ version (1) │
+//	└─────────┴─────┴─────────┴──────────────────────────┴───────────────────────┘
+//
+// The offset table holds the number of chunks, then the external key, offset and length of each chunk.
+// Chunks are read with ranged reads using the offset and length recorded in their refs, so the offset table is only
+// needed to find the chunks of a segment without the index.
+const (
+	segmentMagic     = 0x4C4B5347 // "LKSG"
+	segmentFormatV1  = 1
+	segmentFooterLen = 8 + 4 + 1
+
+	segmentsPrefix = "segments/"
+)
+
+var (
+	castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+	ErrInvalidSegment = errors.New("invalid segment object")
+)
+
+// SegmentClient is implemented by chunk clients which can store many chunks of a tenant in a single segment object.
+type SegmentClient interface {
+	// PutSegment stores the chunks of the tenant in a new segment object, and records the range of each chunk within
+	// the segment in its ChunkRef.
+	PutSegment(ctx context.Context, userID string, chunks []chunk.Chunk) error
+}
+
+// SegmentKey returns the key of the segment object with the given ID holding chunks of the tenant.
+// Segments of all tenants share a common prefix so that they can be managed with a single object lifecycle rule.
+func SegmentKey(userID string, segmentID uint64) string {
+	return fmt.Sprintf("%s%s/%016x", segmentsPrefix, userID, segmentID)
+}
+
+// SegmentEntry is the location of a chunk within a segment object.
+type SegmentEntry struct {
+	ChunkKey string
+	Offset   uint64
+	Length   uint32
+}
+
+func newSegmentID() (uint64, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(b[:]), nil
+}
+
+// encodeSegment encodes the chunks in a segment and sets their offset and length within the segment.
+func encodeSegment(schema config.SchemaConfig, segmentID uint64, chunks []chunk.Chunk) ([]byte, error) {
+	var (
+		buf     bytes.Buffer
+		entries = make([]SegmentEntry, 0, len(chunks))
+	)
+	for i := range chunks {
+		encoded, err := chunks[i].Encoded()
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, SegmentEntry{
+			ChunkKey: schema.ExternalKey(chunks[i].ChunkRef),
+			Offset:   uint64(buf.Len()),
+			Length:   uint32(len(encoded)),
+		})
+		buf.Write(encoded)
+	}
+
+	tableOffset := uint64(buf.Len())
+	table := encoding.EncWith(nil)
+	table.PutUvarint(len(entries))
+	for _, e := range entries {
+		table.PutUvarintStr(e.ChunkKey)
+		table.PutUvarint64(e.Offset)
+		table.PutUvarint32(e.Length)
+	}
+	table.PutHash(crc32.New(castagnoliTable))
+	table.PutBE64(tableOffset)
+	table.PutBE32(segmentMagic)
+	table.PutByte(segmentFormatV1)
+	buf.Write(table.Get())
+
+	for i := range chunks {
+		chunks[i].SegmentID = segmentID
+		chunks[i].SegmentOffset = entries[i].Offset
+		chunks[i].SegmentLength = entries[i].Length
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodeSegmentEntries decodes the offset table of a segment object.
+func DecodeSegmentEntries(segment []byte) ([]SegmentEntry, error) {
+	if len(segment) < segmentFooterLen {
+		return nil, ErrInvalidSegment
+	}
+
+	footer := encoding.DecWith(segment[len(segment)-segmentFooterLen:])
+	tableOffset := footer.Be64()
+	if footer.Be32() != segmentMagic {
+		return nil, ErrInvalidSegment
+	}
+	if version := footer.Byte(); version != segmentFormatV1 {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidSegment, version)
+	}
+	if tableOffset > uint64(len(segment)-segmentFooterLen) {
+		return nil, ErrInvalidSegment
+	}
+
+	table := encoding.DecWith(segment[tableOffset : len(segment)-segmentFooterLen])
+	if err := table.CheckCrc(castagnoliTable); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSegment, err)
+	}
+
+	n := table.Uvarint()
+	entries := make([]SegmentEntry, 0, n)
+	for i := 0; i < n && table.Err() == nil; i++ {
+		entries = append(entries, SegmentEntry{
+			ChunkKey: table.UvarintStr(),
+			Offset:   table.Uvarint64(),
+			Length:   uint32(table.Uvarint()),
+		})
+	}
+	if err := table.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSegment, err)
+	}
+	return entries, nil
+}
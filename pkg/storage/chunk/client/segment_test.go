@@ -0,0 +1,61 @@
+package client
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/chunkenc"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/storage/chunk"
+	"github.com/grafana/loki/v3/pkg/storage/config"
+)
+
+func TestSegmentEncoding(t *testing.T) {
+	schema := config.SchemaConfig{
+		Configs: []config.PeriodConfig{
+			{
+				From:   MustParseDayTime("2020-01-01"),
+				Schema: "v13",
+			},
+		},
+	}
+
+	from, through := MustParseDayTime("2022-01-02").Time, MustParseDayTime("2022-01-03").Time
+	chunks := make([]chunk.Chunk, 3)
+	for i := range chunks {
+		memChunk := chunkenc.NewMemChunk(chunkenc.ChunkFormatV4, chunkenc.EncSnappy, chunkenc.UnorderedWithStructuredMetadataHeadBlockFmt, 256*1024, 0)
+		require.NoError(t, memChunk.Append(&logproto.Entry{Timestamp: from.Time(), Line: strconv.Itoa(i)}))
+
+		lbs := labels.FromStrings("index", strconv.Itoa(i))
+		chunks[i] = chunk.NewChunk("fake", model.Fingerprint(lbs.Hash()), lbs, chunkenc.NewFacade(memChunk, 0, 0), from, through)
+		require.NoError(t, chunks[i].Encode())
+	}
+
+	segment, err := encodeSegment(schema, 42, chunks)
+	require.NoError(t, err)
+
+	entries, err := DecodeSegmentEntries(segment)
+	require.NoError(t, err)
+	require.Len(t, entries, len(chunks))
+	for i, e := range entries {
+		require.Equal(t, schema.ExternalKey(chunks[i].ChunkRef), e.ChunkKey)
+		require.Equal(t, uint64(42), chunks[i].SegmentID)
+		require.Equal(t, e.Offset, chunks[i].SegmentOffset)
+		require.Equal(t, e.Length, chunks[i].SegmentLength)
+		encoded, err := chunks[i].Encoded()
+		require.NoError(t, err)
+		require.Equal(t, encoded, segment[e.Offset:e.Offset+uint64(e.Length)])
+	}
+
+	// corrupting the offset table is detected.
+	segment[len(segment)-segmentFooterLen-1] ^= 0xff
+	_, err = DecodeSegmentEntries(segment)
+	require.ErrorIs(t, err, ErrInvalidSegment)
+
+	_, err = DecodeSegmentEntries([]byte("not a segment"))
+	require.ErrorIs(t, err, ErrInvalidSegment)
+}
@@ -96,3 +96,16 @@ func (r ReadCloserWithContextCancelFunc) Close() error {
 	defer r.cancel()
 	return r.ReadCloser.Close()
 }
+
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// NewLimitedReadCloser returns a ReadCloser reading at most n bytes from readCloser, and closing it when closed.
+func NewLimitedReadCloser(readCloser io.ReadCloser, n int64) io.ReadCloser {
+	return limitedReadCloser{
+		Reader: io.LimitReader(readCloser, n),
+		Closer: readCloser,
+	}
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/grafana/loki/v3/pkg/storage/chunk"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/client"
+	"github.com/grafana/loki/v3/pkg/storage/chunk/client/local"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/client/testutils"
 	"github.com/grafana/loki/v3/pkg/storage/config"
 	"github.com/grafana/loki/v3/pkg/storage/stores/series/index"
@@ -71,3 +72,67 @@ func TestChunksBasic(t *testing.T) {
 		}
 	})
 }
+
+func TestChunksSegment(t *testing.T) {
+	s := config.SchemaConfig{
+		Configs: []config.PeriodConfig{
+			{
+				From:      config.DayTime{Time: 0},
+				Schema:    "v13",
+				RowShards: 16,
+			},
+		},
+	}
+
+	fsObjectClient, err := local.NewFSObjectClient(local.FSConfig{Directory: t.TempDir()})
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name         string
+		objectClient client.ObjectClient
+	}{
+		{
+			// the filesystem reads ranges of segments.
+			name:         "ranged reads",
+			objectClient: fsObjectClient,
+		},
+		{
+			name:         "whole object reads",
+			objectClient: testutils.NewInMemoryObjectClient(),
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			chunkClient := client.NewClient(tc.objectClient, nil, s)
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+			defer cancel()
+
+			keys, chunks, err := testutils.CreateChunks(s, 0, 5, model.Now().Add(-time.Hour), model.Now())
+			require.NoError(t, err)
+			require.NoError(t, chunkClient.(client.SegmentClient).PutSegment(ctx, userID, chunks))
+
+			chunksToGet := make([]chunk.Chunk, 0, len(chunks))
+			for i, key := range keys {
+				require.NotZero(t, chunks[i].SegmentLength)
+
+				chk, err := chunk.ParseExternalKey(userID, key)
+				require.NoError(t, err)
+				chk.SegmentID = chunks[i].SegmentID
+				chk.SegmentOffset = chunks[i].SegmentOffset
+				chk.SegmentLength = chunks[i].SegmentLength
+				chunksToGet = append(chunksToGet, chk)
+			}
+
+			chunksWeGot, err := chunkClient.GetChunks(ctx, chunksToGet)
+			require.NoError(t, err)
+			require.Equal(t, len(chunksToGet), len(chunksWeGot))
+
+			sort.Sort(ByKey{chunks, s})
+			sort.Sort(ByKey{chunksWeGot, s})
+			for i := range chunksWeGot {
+				require.Equal(t, s.ExternalKey(chunks[i].ChunkRef), s.ExternalKey(chunksWeGot[i].ChunkRef), strconv.Itoa(i))
+				require.Equal(t, chunks[i].Metric, chunksWeGot[i].Metric, strconv.Itoa(i))
+			}
+		})
+	}
+}
@@ -439,9 +439,22 @@ func (cfg *PeriodConfig) TSDBFormat() (int, error) {
 	switch {
 	case sver <= 12:
 		return index.FormatV2, nil
-	default: // for v13 and above
+	case sver == 13:
 		return index.FormatV3, nil
+	case sver == 14:
+		return index.FormatV4, nil
+	default: // for v15 and above
+		return index.FormatV5, nil
+	}
+}
+
+// RecordsChunkSegments returns whether the index of the period records the segment objects chunks are stored in.
+func (cfg *PeriodConfig) RecordsChunkSegments() bool {
+	if cfg.IndexType != types.TSDBType {
+		return false
 	}
+	format, err := cfg.TSDBFormat()
+	return err == nil && format >= index.FormatV5
 }
 
 // Validate the period config.
@@ -469,7 +482,7 @@ func (cfg PeriodConfig) validate() error {
 	}
 
 	switch v {
-	case 10, 11, 12, 13:
+	case 10, 11, 12, 13, 14, 15:
 		if cfg.RowShards == 0 {
 			return fmt.Errorf("must have row_shards > 0 (current: %d) for schema (%s)", cfg.RowShards, cfg.Schema)
 		}
@@ -22,6 +22,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/storage/chunk/client/baidubce"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/client/cassandra"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/client/congestion"
+	"github.com/grafana/loki/v3/pkg/storage/chunk/client/encryption"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/client/gcp"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/client/grpc"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/client/hedging"
@@ -289,6 +290,7 @@ type Config struct {
 	IndexCacheValidity     time.Duration             `yaml:"index_cache_validity"`
 	CongestionControl      congestion.Config         `yaml:"congestion_control,omitempty"`
 	ObjectPrefix           string                    `yaml:"object_prefix" doc:"description=Experimental. Sets a constant prefix for all keys inserted into object storage. Example: loki/"`
+	Encryption             encryption.Config         `yaml:"encryption" category:"experimental"`
 
 	IndexQueriesCacheConfig  cache.Config `yaml:"index_queries_cache_config"`
 	DisableBroadIndexQueries bool         `yaml:"disable_broad_index_queries"`
@@ -324,6 +326,7 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	cfg.IndexQueriesCacheConfig.RegisterFlagsWithPrefix("store.index-cache-read.", "", f)
 	f.DurationVar(&cfg.IndexCacheValidity, "store.index-cache-validity", 5*time.Minute, "Cache validity for active index entries. Should be no higher than -ingester.max-chunk-idle.")
 	f.StringVar(&cfg.ObjectPrefix, "store.object-prefix", "", "The prefix to all keys inserted in object storage. Example: loki-instances/west/")
+	cfg.Encryption.RegisterFlagsWithPrefix("store.", f)
 	f.BoolVar(&cfg.DisableBroadIndexQueries, "store.disable-broad-index-queries", false, "Disable broad index queries which results in reduced cache usage and faster query performance at the expense of somewhat higher QPS on the index store.")
 	f.IntVar(&cfg.MaxParallelGetChunk, "store.max-parallel-get-chunk", 150, "Maximum number of parallel chunk reads.")
 	cfg.BoltDBShipperConfig.RegisterFlags(f)
@@ -358,6 +361,9 @@ func (cfg *Config) Validate() error {
 	if err := cfg.BloomShipperConfig.Validate(); err != nil {
 		return errors.Wrap(err, "invalid bloom shipper config")
 	}
+	if err := cfg.Encryption.Validate(); err != nil {
+		return errors.Wrap(err, "invalid encryption config")
+	}
 
 	return cfg.NamedStores.Validate()
 }
@@ -596,19 +602,27 @@ func (c *ClientMetrics) Unregister() {
 	c.AzureMetrics.Unregister()
 }
 
-// NewObjectClient makes a new StorageClient with the prefix in the front.
+// NewObjectClient makes a new StorageClient with the prefix in the front, encrypting objects at rest if enabled.
 func NewObjectClient(name string, cfg Config, clientMetrics ClientMetrics) (client.ObjectClient, error) {
 	actual, err := internalNewObjectClient(name, cfg, clientMetrics)
 	if err != nil {
 		return nil, err
 	}
 
-	if cfg.ObjectPrefix == "" {
-		return actual, nil
-	} else {
+	if cfg.ObjectPrefix != "" {
 		prefix := strings.Trim(cfg.ObjectPrefix, "/") + "/"
-		return client.NewPrefixedObjectClient(actual, prefix), nil
+		actual = client.NewPrefixedObjectClient(actual, prefix)
+	}
+
+	if cfg.Encryption.Enabled {
+		kms, err := encryption.NewKMS(cfg.Encryption)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create kms for encryption at rest")
+		}
+		actual = encryption.NewObjectClient(actual, kms)
 	}
+
+	return actual, nil
 }
 
 // internalNewObjectClient makes the underlying StorageClient of the desired types.
@@ -389,6 +389,14 @@ func injectShardLabel(shards []string, matchers []*labels.Matcher) ([]*labels.Ma
 	return matchers, nil
 }
 
+// PutSegment stores the chunks of the tenant in segment objects if the store of their period supports it.
+func (s *LokiStore) PutSegment(ctx context.Context, userID string, chunks []chunk.Chunk) error {
+	if segmentWriter, ok := s.Store.(stores.SegmentWriter); ok {
+		return segmentWriter.PutSegment(ctx, userID, chunks)
+	}
+	return s.Store.Put(ctx, chunks)
+}
+
 func (s *LokiStore) SetChunkFilterer(chunkFilterer chunk.RequestChunkFilterer) {
 	s.chunkFilterer = chunkFilterer
 	s.Store.SetChunkFilterer(chunkFilterer)
@@ -23,6 +23,11 @@ type ChunkWriter interface {
 	PutOne(ctx context.Context, from, through model.Time, chunk chunk.Chunk) error
 }
 
+// SegmentWriter is implemented by chunk writers which can store many chunks of a tenant in a single segment object.
+type SegmentWriter interface {
+	PutSegment(ctx context.Context, userID string, chunks []chunk.Chunk) error
+}
+
 type ChunkFetcherProvider interface {
 	GetChunkFetcher(tm model.Time) *fetcher.Fetcher
 }
@@ -99,6 +104,40 @@ func (c CompositeStore) PutOne(ctx context.Context, from, through model.Time, ch
 	})
 }
 
+// PutSegment stores the chunks of each period in a segment object. Chunks spanning several periods are stored
+// in their own objects since they are written to the stores of all the periods.
+func (c CompositeStore) PutSegment(ctx context.Context, userID string, chunks []chunk.Chunk) error {
+	var (
+		byStore  = map[int][]chunk.Chunk{}
+		overlaps []chunk.Chunk
+	)
+	for _, chk := range chunks {
+		i := sort.Search(len(c.stores), func(i int) bool {
+			return c.stores[i].start > chk.From
+		}) - 1
+		if i < 0 || (i+1 < len(c.stores) && chk.Through >= c.stores[i+1].start) {
+			overlaps = append(overlaps, chk)
+			continue
+		}
+		byStore[i] = append(byStore[i], chk)
+	}
+
+	for i, chks := range byStore {
+		segmentWriter, ok := c.stores[i].Store.(SegmentWriter)
+		if !ok {
+			if err := c.Put(ctx, chks); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := segmentWriter.PutSegment(ctx, userID, chks); err != nil {
+			return err
+		}
+	}
+
+	return c.Put(ctx, overlaps)
+}
+
 func (c CompositeStore) SetChunkFilterer(chunkFilter chunk.RequestChunkFilterer) {
 	for _, store := range c.stores {
 		store.Store.SetChunkFilterer(chunkFilter)
@@ -155,6 +194,20 @@ func (c CompositeStore) LabelNamesForMetricName(ctx context.Context, userID stri
 	return result.Strings(), err
 }
 
+// StructuredMetadataKeys retrieves the structured metadata keys recorded in the index for the matching series.
+func (c CompositeStore) StructuredMetadataKeys(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) ([]string, error) {
+	var result util.UniqueStrings
+	err := c.forStores(ctx, from, through, func(innerCtx context.Context, from, through model.Time, store Store) error {
+		keys, err := store.StructuredMetadataKeys(innerCtx, userID, from, through, matchers...)
+		if err != nil {
+			return err
+		}
+		result.Add(keys...)
+		return nil
+	})
+	return result.Strings(), err
+}
+
 func (c CompositeStore) GetChunks(ctx context.Context, userID string, from, through model.Time, predicate chunk.Predicate) ([][]chunk.Chunk, []*fetcher.Fetcher, error) {
 	chunkIDs := [][]chunk.Chunk{}
 	fetchers := []*fetcher.Fetcher{}
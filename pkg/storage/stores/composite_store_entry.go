@@ -43,6 +43,13 @@ type storeEntry struct {
 	ChunkWriter
 }
 
+func (c *storeEntry) PutSegment(ctx context.Context, userID string, chunks []chunk.Chunk) error {
+	if segmentWriter, ok := c.ChunkWriter.(SegmentWriter); ok {
+		return segmentWriter.PutSegment(ctx, userID, chunks)
+	}
+	return c.ChunkWriter.Put(ctx, chunks)
+}
+
 func (c *storeEntry) GetChunks(ctx context.Context, userID string, from, through model.Time, predicate chunk.Predicate) ([][]chunk.Chunk, []*fetcher.Fetcher, error) {
 	if ctx.Err() != nil {
 		return nil, nil, ctx.Err()
@@ -109,6 +116,20 @@ func (c *storeEntry) LabelValuesForMetricName(ctx context.Context, userID string
 	return c.indexReader.LabelValuesForMetricName(ctx, userID, from, through, metricName, labelName, matchers...)
 }
 
+func (c *storeEntry) StructuredMetadataKeys(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) ([]string, error) {
+	sp, ctx := opentracing.StartSpanFromContext(ctx, "SeriesStore.StructuredMetadataKeys")
+	defer sp.Finish()
+
+	shortcut, err := c.validateQueryTimeRange(ctx, userID, &from, &through)
+	if err != nil {
+		return nil, err
+	} else if shortcut {
+		return nil, nil
+	}
+
+	return c.indexReader.StructuredMetadataKeys(ctx, userID, from, through, matchers...)
+}
+
 func (c *storeEntry) Stats(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) (*stats.Stats, error) {
 	shortcut, err := c.validateQueryTimeRange(ctx, userID, &from, &through)
 	if err != nil {
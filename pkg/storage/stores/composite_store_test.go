@@ -49,6 +49,10 @@ func (m mockStore) LabelNamesForMetricName(_ context.Context, _ string, _, _ mod
 	return nil, nil
 }
 
+func (m mockStore) StructuredMetadataKeys(_ context.Context, _ string, _, _ model.Time, _ ...*labels.Matcher) ([]string, error) {
+	return nil, nil
+}
+
 func (m mockStore) GetChunkFetcher(_ model.Time) *fetcher.Fetcher {
 	return nil
 }
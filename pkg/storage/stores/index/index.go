@@ -25,6 +25,9 @@ type BaseReader interface {
 	GetSeries(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) ([]labels.Labels, error)
 	LabelValuesForMetricName(ctx context.Context, userID string, from, through model.Time, metricName string, labelName string, matchers ...*labels.Matcher) ([]string, error)
 	LabelNamesForMetricName(ctx context.Context, userID string, from, through model.Time, metricName string) ([]string, error)
+	// StructuredMetadataKeys returns the sorted structured metadata keys recorded in the index for the matching
+	// series. Only TSDB indexes of FormatV4 and above record them.
+	StructuredMetadataKeys(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) ([]string, error)
 }
 
 type StatsReader interface {
@@ -124,6 +127,19 @@ func (m MonitoredReaderWriter) LabelNamesForMetricName(ctx context.Context, user
 	return values, nil
 }
 
+func (m MonitoredReaderWriter) StructuredMetadataKeys(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) ([]string, error) {
+	var keys []string
+	if err := loki_instrument.TimeRequest(ctx, "structured_metadata_keys", instrument.NewHistogramCollector(m.metrics.indexQueryLatency), instrument.ErrorCode, func(ctx context.Context) error {
+		var err error
+		keys, err = m.rw.StructuredMetadataKeys(ctx, userID, from, through, matchers...)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
 func (m MonitoredReaderWriter) Stats(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) (*stats.Stats, error) {
 	var sts *stats.Stats
 	if err := loki_instrument.TimeRequest(ctx, "stats", instrument.NewHistogramCollector(m.metrics.indexQueryLatency), instrument.ErrorCode, func(ctx context.Context) error {
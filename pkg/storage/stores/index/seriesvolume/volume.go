@@ -14,6 +14,11 @@ const (
 	Series       = "series"
 	Labels       = "labels"
 
+	// Cardinality aggregations count series instead of bytes. They are only computed from the TSDB index.
+	SeriesCount = "series_count"
+	LabelValues = "label_values"
+	Churn       = "churn"
+
 	DefaultAggregateBy = Series
 
 	ErrVolumeMaxSeriesHit = "the query hit the max number of series limit (limit: %d series)"
@@ -103,6 +108,8 @@ func ValidateAggregateBy(aggregateBy string) bool {
 		return true
 	case Series:
 		return true
+	case SeriesCount, LabelValues, Churn:
+		return true
 	default:
 		return false
 	}
@@ -111,3 +118,23 @@ func ValidateAggregateBy(aggregateBy string) bool {
 func AggregateBySeries(aggregateBy string) bool {
 	return aggregateBy == Series
 }
+
+// IsCardinality returns whether the aggregation counts series instead of bytes.
+func IsCardinality(aggregateBy string) bool {
+	switch aggregateBy {
+	case SeriesCount, LabelValues, Churn:
+		return true
+	default:
+		return false
+	}
+}
+
+// GroupsBySeries returns whether the names of the volumes of the aggregation are series labels.
+func GroupsBySeries(aggregateBy string) bool {
+	switch aggregateBy {
+	case Labels, LabelValues:
+		return false
+	default:
+		return true
+	}
+}
@@ -27,6 +27,7 @@ type GatewayClient interface {
 	GetVolume(ctx context.Context, in *logproto.VolumeRequest) (*logproto.VolumeResponse, error)
 
 	GetShards(ctx context.Context, in *logproto.ShardsRequest) (*logproto.ShardsResponse, error)
+	GetStructuredMetadataKeys(ctx context.Context, in *logproto.StructuredMetadataKeysRequest) (*logproto.LabelResponse, error)
 }
 
 // IndexGatewayClientStore implements pkg/storage/stores/index.ReaderWriter
@@ -106,6 +107,18 @@ func (c *IndexGatewayClientStore) LabelValuesForMetricName(ctx context.Context,
 	return resp.Values, nil
 }
 
+func (c *IndexGatewayClientStore) StructuredMetadataKeys(ctx context.Context, _ string, from, through model.Time, matchers ...*labels.Matcher) ([]string, error) {
+	resp, err := c.client.GetStructuredMetadataKeys(ctx, &logproto.StructuredMetadataKeysRequest{
+		From:     from,
+		Through:  through,
+		Matchers: (&syntax.MatchersExpr{Mts: matchers}).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Values, nil
+}
+
 func (c *IndexGatewayClientStore) Stats(ctx context.Context, _ string, from, through model.Time, matchers ...*labels.Matcher) (*stats.Stats, error) {
 	return c.client.GetStats(ctx, &logproto.IndexStatsRequest{
 		From:     from,
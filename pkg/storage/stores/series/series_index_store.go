@@ -315,6 +315,11 @@ func (c *IndexReaderWriter) chunksToSeries(ctx context.Context, in []logproto.Ch
 	return results, nil
 }
 
+// StructuredMetadataKeys is not supported by the series index, which doesn't record structured metadata keys.
+func (c *IndexReaderWriter) StructuredMetadataKeys(_ context.Context, _ string, _, _ model.Time, _ ...*labels.Matcher) ([]string, error) {
+	return nil, nil
+}
+
 // LabelNamesForMetricName retrieves all label names for a metric name.
 func (c *IndexReaderWriter) LabelNamesForMetricName(ctx context.Context, userID string, from, through model.Time, metricName string) ([]string, error) {
 	sp, ctx := opentracing.StartSpanFromContext(ctx, "SeriesStore.LabelNamesForMetricName")
@@ -2,6 +2,7 @@ package stores
 
 import (
 	"context"
+	"errors"
 
 	"github.com/go-kit/log/level"
 	"github.com/opentracing/opentracing-go"
@@ -10,6 +11,7 @@ import (
 	"github.com/prometheus/common/model"
 
 	"github.com/grafana/loki/v3/pkg/storage/chunk"
+	"github.com/grafana/loki/v3/pkg/storage/chunk/client"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/fetcher"
 	"github.com/grafana/loki/v3/pkg/storage/config"
 	"github.com/grafana/loki/v3/pkg/storage/stores/index"
@@ -127,3 +129,79 @@ func (c *Writer) PutOne(ctx context.Context, from, through model.Time, chk chunk
 
 	return nil
 }
+
+// PutSegment implements SegmentWriter. The chunks are stored in a single segment object if the chunk client supports
+// it and the index of their period records the segments chunks are stored in, otherwise each chunk is stored in its
+// own object.
+func (c *Writer) PutSegment(ctx context.Context, userID string, chunks []chunk.Chunk) error {
+	segmentClient, ok := c.fetcher.Client().(client.SegmentClient)
+	if !ok || !c.recordsChunkSegments(chunks) {
+		return c.Put(ctx, chunks)
+	}
+
+	sp, ctx := opentracing.StartSpanFromContext(ctx, "SeriesStore.PutSegment")
+	defer sp.Finish()
+	log := spanlogger.FromContext(ctx)
+	defer log.Finish()
+
+	keys := make([]string, 0, len(chunks))
+	for _, chk := range chunks {
+		keys = append(keys, c.schemaCfg.ExternalKey(chk.ChunkRef))
+	}
+
+	// Chunks in cache were already stored by another replica. Unlike chunks stored in their own object, they are not
+	// indexed again even if index deduplication is disabled since the index entry has to reference the segment of
+	// the replica.
+	found, _, _, _ := c.fetcher.Cache().Fetch(ctx, keys)
+	if len(found) > 0 {
+		foundKeys := make(map[string]struct{}, len(found))
+		for _, key := range found {
+			foundKeys[key] = struct{}{}
+		}
+
+		toWrite := make([]chunk.Chunk, 0, len(chunks)-len(found))
+		for i, chk := range chunks {
+			if _, ok := foundKeys[keys[i]]; !ok {
+				toWrite = append(toWrite, chk)
+				continue
+			}
+			DedupedChunksTotal.Inc()
+			if encoded, err := chk.Encoded(); err == nil {
+				DedupedBytesTotal.Add(float64(len(encoded)))
+			}
+		}
+		chunks = toWrite
+	}
+	if len(chunks) == 0 {
+		return nil
+	}
+
+	if err := segmentClient.PutSegment(ctx, userID, chunks); err != nil {
+		if errors.Is(err, client.ErrMethodNotImplemented) {
+			return c.Put(ctx, chunks)
+		}
+		return err
+	}
+
+	for _, chk := range chunks {
+		if err := c.indexWriter.IndexChunk(ctx, chk.From, chk.Through, chk); err != nil {
+			return err
+		}
+	}
+
+	if cacheErr := c.fetcher.WriteBackCache(ctx, chunks); cacheErr != nil {
+		level.Warn(log).Log("msg", "could not store chunks in chunk cache", "err", cacheErr)
+	}
+
+	return nil
+}
+
+func (c *Writer) recordsChunkSegments(chunks []chunk.Chunk) bool {
+	for _, chk := range chunks {
+		periodCfg, err := c.schemaCfg.SchemaForTime(chk.From)
+		if err != nil || !periodCfg.RecordsChunkSegments() {
+			return false
+		}
+	}
+	return true
+}
@@ -287,7 +287,7 @@ func (b *BloomClient) PutMeta(ctx context.Context, meta Meta) error {
 		return fmt.Errorf("failed to encode meta file %s: %w", meta.String(), err)
 	}
 	key := b.Meta(meta.MetaRef).Addr()
-	return b.client.PutObject(ctx, key, bytes.NewReader(data))
+	return b.client.PutObject(client.InjectObjectTenant(ctx, meta.TenantID), key, bytes.NewReader(data))
 }
 
 func (b *BloomClient) DeleteMetas(ctx context.Context, refs []MetaRef) error {
@@ -355,7 +355,7 @@ func (b *BloomClient) PutBlock(ctx context.Context, block Block) error {
 		return fmt.Errorf("failed to seek block file %s: %w", key, err)
 	}
 
-	err = b.client.PutObject(ctx, key, block.Data)
+	err = b.client.PutObject(client.InjectObjectTenant(ctx, block.TenantID), key, block.Data)
 	if err != nil {
 		return fmt.Errorf("failed to put block file %s: %w", key, err)
 	}
@@ -289,6 +289,18 @@ func (s *GatewayClient) LabelValuesForMetricName(ctx context.Context, in *logpro
 	return resp, err
 }
 
+func (s *GatewayClient) GetStructuredMetadataKeys(ctx context.Context, in *logproto.StructuredMetadataKeysRequest) (*logproto.LabelResponse, error) {
+	var (
+		resp *logproto.LabelResponse
+		err  error
+	)
+	err = s.poolDo(ctx, func(client logproto.IndexGatewayClient) error {
+		resp, err = client.GetStructuredMetadataKeys(ctx, in)
+		return err
+	})
+	return resp, err
+}
+
 func (s *GatewayClient) GetStats(ctx context.Context, in *logproto.IndexStatsRequest) (*logproto.IndexStatsResponse, error) {
 	var (
 		resp *logproto.IndexStatsResponse
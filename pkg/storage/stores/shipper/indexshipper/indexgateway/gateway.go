@@ -57,6 +57,7 @@ type IndexClientWithRange struct {
 
 type BloomQuerier interface {
 	FilterChunkRefs(ctx context.Context, tenant string, from, through model.Time, chunks []*logproto.ChunkRef, plan plan.QueryPlan) ([]*logproto.ChunkRef, error)
+	ExplainFilters(tenant string, expr syntax.Expr) []v1.FilterEligibility
 }
 
 type Gateway struct {
@@ -242,9 +243,9 @@ func (g *Gateway) GetChunkRef(ctx context.Context, req *logproto.GetChunkRefRequ
 		return result, nil
 	}
 
-	// Extract LineFiltersExpr from the plan. If there is none, we can short-circuit and return before making a req
-	// to the bloom-gateway (through the g.bloomQuerier)
-	if len(v1.ExtractTestableLineFilters(req.Plan.AST)) == 0 {
+	// Extract LineFiltersExpr and LabelFilterExpr from the plan. If there is none, we can short-circuit and return
+	// before making a req to the bloom-gateway (through the g.bloomQuerier)
+	if len(v1.ExtractTestableLineFilters(req.Plan.AST)) == 0 && len(v1.ExtractTestableLabelFilters(req.Plan.AST)) == 0 {
 		return result, nil
 	}
 
@@ -298,6 +299,24 @@ func (g *Gateway) LabelNamesForMetricName(ctx context.Context, req *logproto.Lab
 	}, nil
 }
 
+func (g *Gateway) GetStructuredMetadataKeys(ctx context.Context, req *logproto.StructuredMetadataKeysRequest) (*logproto.LabelResponse, error) {
+	instanceID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	matchers, err := syntax.ParseMatchers(req.Matchers, true)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := g.indexQuerier.StructuredMetadataKeys(ctx, instanceID, req.From, req.Through, matchers...)
+	if err != nil {
+		return nil, err
+	}
+	return &logproto.LabelResponse{
+		Values: keys,
+	}, nil
+}
+
 func (g *Gateway) LabelValuesForMetricName(ctx context.Context, req *logproto.LabelValuesForMetricNameRequest) (*logproto.LabelResponse, error) {
 	instanceID, err := tenant.TenantID(ctx)
 	if err != nil {
@@ -386,6 +405,11 @@ func (g *Gateway) GetShards(request *logproto.ShardsRequest, server logproto.Ind
 			return err
 		}
 
+		if g.bloomQuerier != nil {
+			index := g.filterStatistics(instanceID, p.Plan().AST)
+			shards.Statistics.Index.TotalFilters = index.TotalFilters
+			shards.Statistics.Index.BloomFilters = index.BloomFilters
+		}
 		return server.Send(shards)
 	}
 
@@ -445,11 +469,10 @@ func (g *Gateway) getShardsWithBlooms(
 	g.metrics.postFilterChunks.WithLabelValues(routeShards).Observe(float64(len(filtered)))
 
 	statistics := stats.Result{
-		Index: stats.Index{
-			TotalChunks:      int64(ct),
-			PostFilterChunks: int64(len(filtered)),
-		},
+		Index: g.filterStatistics(instanceID, p.Plan().AST),
 	}
+	statistics.Index.TotalChunks = int64(ct)
+	statistics.Index.PostFilterChunks = int64(len(filtered))
 
 	resp := &logproto.ShardsResponse{
 		Statistics: statistics,
@@ -477,6 +500,8 @@ func (g *Gateway) getShardsWithBlooms(
 		"msg", "send shards response",
 		"total_chunks", statistics.Index.TotalChunks,
 		"post_filter_chunks", statistics.Index.PostFilterChunks,
+		"total_filters", statistics.Index.TotalFilters,
+		"bloom_filters", statistics.Index.BloomFilters,
 		"shards", len(resp.Shards),
 		"query", req.Query,
 		"target_bytes_per_shard", datasize.ByteSize(req.TargetBytesPerShard).HumanReadable(),
@@ -486,6 +511,16 @@ func (g *Gateway) getShardsWithBlooms(
 	return server.Send(resp)
 }
 
+// filterStatistics returns the index statistics of the line and label filters of the query.
+// They are only reported by the shards requests of index gateways using the bloom gateway.
+func (g *Gateway) filterStatistics(instanceID string, expr syntax.Expr) stats.Index {
+	filters := g.bloomQuerier.ExplainFilters(instanceID, expr)
+	return stats.Index{
+		TotalFilters: int64(len(filters)),
+		BloomFilters: int64(v1.CountTestableFilters(filters)),
+	}
+}
+
 // ExtractShardRequestMatchersAndAST extracts the matchers and AST from a query string.
 // It errors if there is more than one matcher group in the AST as this is supposed to be
 // split out during query planning before reaching this point.
@@ -139,12 +139,14 @@ func (s *indexStorageClient) GetUserFile(ctx context.Context, tableName, userID,
 	return readCloser, err
 }
 
+// PutFile uploads a file of the common index set. The file can hold the index of several tenants, e.g. the multi-tenant
+// TSDB files uploaded by ingesters, and is therefore encrypted with the default key when encryption at rest is enabled.
 func (s *indexStorageClient) PutFile(ctx context.Context, tableName, fileName string, file io.ReadSeeker) error {
 	return s.objectClient.PutObject(ctx, path.Join(tableName, fileName), file)
 }
 
 func (s *indexStorageClient) PutUserFile(ctx context.Context, tableName, userID, fileName string, file io.ReadSeeker) error {
-	return s.objectClient.PutObject(ctx, path.Join(tableName, userID, fileName), file)
+	return s.objectClient.PutObject(client.InjectObjectTenant(ctx, userID), path.Join(tableName, userID, fileName), file)
 }
 
 func (s *indexStorageClient) DeleteFile(ctx context.Context, tableName, fileName string) error {
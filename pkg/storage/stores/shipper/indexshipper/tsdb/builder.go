@@ -31,6 +31,7 @@ type stream struct {
 	labels labels.Labels
 	fp     model.Fingerprint
 	chunks index.ChunkMetas
+	keys   index.StructuredMetadataKeys
 }
 
 func NewBuilder(version int) *Builder {
@@ -54,6 +55,18 @@ func (b *Builder) AddSeries(ls labels.Labels, fp model.Fingerprint, chks []index
 	s.chunks = append(s.chunks, chks...)
 }
 
+// AddStructuredMetadataKeys adds structured metadata keys seen in chunks to the catalog of a stream
+// which has been added before. The catalog is only written to indices of FormatV4 and above.
+func (b *Builder) AddStructuredMetadataKeys(ls labels.Labels, keys index.StructuredMetadataKeys) {
+	if len(keys) == 0 {
+		return
+	}
+
+	if s, ok := b.streams[ls.String()]; ok {
+		s.keys = s.keys.Merge(keys)
+	}
+}
+
 func (b *Builder) FinalizeChunks() {
 	for id := range b.streams {
 		b.streams[id].chunks = b.streams[id].chunks.Finalize()
@@ -143,6 +156,11 @@ func (b *Builder) Build(
 			symbolsMap[l.Name] = struct{}{}
 			symbolsMap[l.Value] = struct{}{}
 		}
+		if b.version > index.FormatV3 {
+			for _, key := range s.keys {
+				symbolsMap[key.Name] = struct{}{}
+			}
+		}
 	}
 
 	// Sort symbols
@@ -164,7 +182,7 @@ func (b *Builder) Build(
 		if !b.chunksFinalized {
 			s.chunks = s.chunks.Finalize()
 		}
-		if err := writer.AddSeries(storage.SeriesRef(i), s.labels, s.fp, s.chunks...); err != nil {
+		if err := writer.AddSeriesWithStructuredMetadataKeys(storage.SeriesRef(i), s.labels, s.fp, s.keys, s.chunks...); err != nil {
 			return id, err
 		}
 	}
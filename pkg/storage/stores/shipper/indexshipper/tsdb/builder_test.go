@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/loki/v3/pkg/storage/stores/shipper/indexshipper/tsdb/index"
@@ -104,6 +105,48 @@ func Test_Build(t *testing.T) {
 		reader := getReader(tmpDir)
 		require.Equal(t, index.FormatV2, reader.Version())
 	})
+
+	t.Run("writes structured metadata keys of series", func(t *testing.T) {
+		for _, tc := range []struct {
+			version  int
+			expected []string
+		}{
+			{version: index.FormatV3, expected: []string{}},
+			{version: index.FormatV4, expected: []string{"pod", "trace_id"}},
+		} {
+			tc := tc
+			t.Run(fmt.Sprintf("v%d", tc.version), func(t *testing.T) {
+				ctx, builder, tmpDir := setup(tc.version)
+				var keys index.StructuredMetadataKeys
+				keys = keys.Add("trace_id", 1, 3)
+				keys = keys.Add("pod", 4, 5)
+				builder.AddStructuredMetadataKeys(mustParseLabels(`{foo="bar", a="b"}`), keys)
+				// keys of series without chunks are not written
+				builder.AddStructuredMetadataKeys(mustParseLabels(`{foo="baz"}`), keys)
+
+				_, err := builder.Build(ctx, tmpDir, func(from, through model.Time, checksum uint32) Identifier {
+					return &fakeIdentifier{
+						parentPath: tmpDir,
+						from:       from,
+						through:    through,
+						checksum:   checksum,
+					}
+				})
+				require.NoError(t, err)
+
+				idx := NewTSDBIndex(getReader(tmpDir))
+				names, err := idx.StructuredMetadataKeys(ctx, "", 0, 10, labels.MustNewMatcher(labels.MatchEqual, "foo", "bar"))
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, names)
+
+				names, err = idx.StructuredMetadataKeys(ctx, "", 4, 10, labels.MustNewMatcher(labels.MatchEqual, "foo", "bar"))
+				require.NoError(t, err)
+				if len(tc.expected) > 0 {
+					require.Equal(t, []string{"pod"}, names)
+				}
+			})
+		}
+	})
 }
 
 type fakeIdentifier struct {
@@ -0,0 +1,186 @@
+package tsdb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/axiomhq/hyperloglog"
+	"github.com/cespare/xxhash/v2"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/storage/stores/index/seriesvolume"
+	"github.com/grafana/loki/v3/pkg/storage/stores/shipper/indexshipper/tsdb/index"
+	"github.com/grafana/loki/v3/pkg/util"
+)
+
+// cardinalityGroup is a series group or a label of a cardinality aggregation.
+// Its series or values are counted with sketches so that the memory doesn't grow with the number of series.
+type cardinalityGroup struct {
+	name string
+	// all counts the fingerprints of the series of the group or the values of the label.
+	all *hyperloglog.Sketch
+	// before counts the fingerprints of the series of the group with a chunk starting before the range.
+	// It's only used by churn aggregations.
+	before *hyperloglog.Sketch
+}
+
+// cardinalityAccumulator aggregates the series matching a cardinality request according to aggregateBy:
+//   - series_count counts the series of each combination of the target labels.
+//   - churn counts the series of each combination of the target labels whose first chunk in the range starts
+//     within the range, i.e. series created during the range.
+//   - label_values counts the distinct values of each label.
+//
+// Like for volumes, the target labels default to the labels of the matchers.
+// Series can be part of several indices and index buckets, counting their fingerprints deduplicates them.
+// The number of groups is limited to maxGroups.
+type cardinalityAccumulator struct {
+	from          int64
+	labelsToMatch map[string]struct{}
+	includeAll    bool
+	targetLabels  []string
+	aggregateBy   string
+	maxGroups     int
+
+	mtx          sync.Mutex
+	groups       map[uint64]*cardinalityGroup
+	seriesLabels labels.Labels
+	err          error
+}
+
+func newCardinalityAccumulator(from model.Time, labelsToMatch map[string]struct{}, includeAll bool, targetLabels []string, aggregateBy string, maxGroups int) *cardinalityAccumulator {
+	return &cardinalityAccumulator{
+		from:          int64(from),
+		labelsToMatch: labelsToMatch,
+		includeAll:    includeAll,
+		targetLabels:  targetLabels,
+		aggregateBy:   aggregateBy,
+		maxGroups:     maxGroups,
+		groups:        make(map[uint64]*cardinalityGroup),
+		seriesLabels:  make(labels.Labels, 0, len(labelsToMatch)),
+	}
+}
+
+// forSeries is the ForSeries callback of the accumulator. It may be called concurrently.
+func (a *cardinalityAccumulator) forSeries(ls labels.Labels, fp model.Fingerprint, chks []index.ChunkMeta) (stop bool) {
+	if len(chks) == 0 {
+		return false
+	}
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if a.err != nil {
+		return true
+	}
+
+	if a.aggregateBy == seriesvolume.LabelValues {
+		for _, l := range ls {
+			if l.Name == TenantLabel {
+				continue
+			}
+			if _, ok := a.labelsToMatch[l.Name]; len(a.targetLabels) > 0 && !a.includeAll && !ok {
+				continue
+			}
+			g := a.group(xxhash.Sum64String(l.Name), l.Name)
+			if g == nil {
+				return true
+			}
+			g.all.InsertHash(xxhash.Sum64String(l.Value))
+		}
+		return false
+	}
+
+	a.seriesLabels = a.seriesLabels[:0]
+	for _, l := range ls {
+		if _, ok := a.labelsToMatch[l.Name]; l.Name != TenantLabel && a.includeAll || ok {
+			a.seriesLabels = append(a.seriesLabels, l)
+		}
+	}
+	g := a.group(a.seriesLabels.Hash(), "")
+	if g == nil {
+		return true
+	}
+	g.all.InsertHash(uint64(fp))
+
+	if a.aggregateBy == seriesvolume.Churn {
+		for _, chk := range chks {
+			if chk.MinTime < a.from {
+				g.before.InsertHash(uint64(fp))
+				break
+			}
+		}
+	}
+	return false
+}
+
+// group returns the group with the given hash, creating it if needed.
+// It returns nil and records an error when there are too many groups. Series group names are computed on
+// creation from the current series labels.
+func (a *cardinalityAccumulator) group(hash uint64, name string) *cardinalityGroup {
+	if g, ok := a.groups[hash]; ok {
+		return g
+	}
+	if len(a.groups) >= a.maxGroups {
+		a.err = fmt.Errorf(seriesvolume.ErrVolumeMaxSeriesHit, a.maxGroups)
+		return nil
+	}
+	if name == "" {
+		name = a.seriesLabels.String()
+	}
+	g := &cardinalityGroup{name: name, all: hyperloglog.New()}
+	if a.aggregateBy == seriesvolume.Churn {
+		g.before = hyperloglog.New()
+	}
+	a.groups[hash] = g
+	return g
+}
+
+// volumes adds the counts of the groups to acc.
+func (a *cardinalityAccumulator) volumes(acc *seriesvolume.Accumulator) error {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if a.err != nil {
+		return a.err
+	}
+
+	for _, g := range a.groups {
+		count := g.all.Estimate()
+		if g.before != nil {
+			if before := g.before.Estimate(); before < count {
+				count -= before
+			} else {
+				count = 0
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		if err := acc.AddVolume(g.name, count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cardinality computes a cardinality aggregation of a volume request by walking the series of the index.
+func (c *IndexClient) cardinality(ctx context.Context, userID string, from model.Time, intervals []model.Interval, limit int32, targetLabels []string, aggregateBy string, shard index.FingerprintFilter, matchers ...*labels.Matcher) (*logproto.VolumeResponse, error) {
+	labelsToMatch, matchers, includeAll := util.PrepareLabelsAndMatchers(targetLabels, matchers, TenantLabel)
+
+	maxSeries := c.limits.VolumeMaxSeries(userID)
+	series := newCardinalityAccumulator(from, labelsToMatch, includeAll, targetLabels, aggregateBy, maxSeries)
+	for _, interval := range intervals {
+		if err := c.idx.ForSeries(ctx, userID, shard, interval.Start, interval.End, series.forSeries, matchers...); err != nil {
+			return nil, err
+		}
+	}
+
+	acc := seriesvolume.NewAccumulator(limit, maxSeries)
+	if err := series.volumes(acc); err != nil {
+		return nil, err
+	}
+	return acc.Volumes(), nil
+}
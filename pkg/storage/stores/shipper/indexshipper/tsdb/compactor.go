@@ -60,6 +60,9 @@ func (i indexProcessor) OpenCompactedIndexFile(ctx context.Context, path, tableN
 	if err != nil {
 		return nil, err
 	}
+	if err := addStructuredMetadataKeys(ctx, builder, indexFile.(*TSDBFile).Index.(*TSDBIndex), false, labels.MustNewMatcher(labels.MatchEqual, "", "")); err != nil {
+		return nil, err
+	}
 
 	builder.chunksFinalized = true
 
@@ -220,6 +223,9 @@ func setupBuilder(ctx context.Context, indexType int, userID string, sourceIndex
 		if err != nil {
 			return nil, err
 		}
+		if err := addStructuredMetadataKeys(ctx, builder, idx.(*TSDBFile).Index.(*TSDBIndex), true, withTenantLabelMatcher(userID, []*labels.Matcher{})...); err != nil {
+			return nil, err
+		}
 	}
 
 	// download all the existing compacted indexes and add them to the builder
@@ -253,6 +259,9 @@ func setupBuilder(ctx context.Context, indexType int, userID string, sourceIndex
 		if err != nil {
 			return nil, err
 		}
+		if err := addStructuredMetadataKeys(ctx, builder, indexFile.(*TSDBFile).Index.(*TSDBIndex), false, labels.MustNewMatcher(labels.MatchEqual, "", "")); err != nil {
+			return nil, err
+		}
 	}
 
 	// finalize the chunks to remove the duplicates and sort them
@@ -261,6 +270,26 @@ func setupBuilder(ctx context.Context, indexType int, userID string, sourceIndex
 	return builder, nil
 }
 
+// addStructuredMetadataKeys merges the structured metadata key catalogs of the matching series of the index
+// into the builder, optionally stripping the tenant label of multi-tenant indices.
+// Indices older than FormatV4 don't have the catalogs and are skipped.
+func addStructuredMetadataKeys(ctx context.Context, builder *Builder, idx *TSDBIndex, stripTenantLabel bool, matchers ...*labels.Matcher) error {
+	if reader, ok := idx.reader.(*tsdbindex.Reader); ok && reader.Version() < tsdbindex.FormatV4 {
+		return nil
+	}
+	return idx.ForSeriesStructuredMetadataKeys(ctx, nil, 0, math.MaxInt64, func(lbls labels.Labels, _ model.Fingerprint, keys tsdbindex.StructuredMetadataKeys) (stop bool) {
+		if len(keys) == 0 {
+			return false
+		}
+		lbls = lbls.Copy()
+		if stripTenantLabel {
+			lbls = withoutTenantLabel(lbls)
+		}
+		builder.AddStructuredMetadataKeys(lbls, keys.Copy())
+		return false
+	}, matchers...)
+}
+
 type compactedIndex struct {
 	ctx           context.Context
 	userID        string
@@ -315,6 +344,9 @@ func (c *compactedIndex) ForEachChunk(ctx context.Context, callback retention.Ch
 			chunkEntry.ChunkID = getUnsafeBytes(schemaCfg.ExternalKey(logprotoChunkRef))
 			chunkEntry.From = logprotoChunkRef.From
 			chunkEntry.Through = logprotoChunkRef.Through
+			chunkEntry.SegmentID = chk.SegmentID
+			chunkEntry.SegmentOffset = chk.SegmentOffset
+			chunkEntry.SegmentLength = chk.SegmentLength
 
 			deleteChunk, err := callback(chunkEntry)
 			if err != nil {
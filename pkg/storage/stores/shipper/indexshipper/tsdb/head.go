@@ -159,10 +159,10 @@ func updateMintMaxt(mint, maxt int64, mintSrc, maxtSrc *atomic.Int64) {
 }
 
 // Note: chks must not be nil or zero-length
-func (h *Head) Append(ls labels.Labels, fprint uint64, chks index.ChunkMetas) (created bool, refID uint64) {
+func (h *Head) Append(ls labels.Labels, fprint uint64, chks index.ChunkMetas, keys index.StructuredMetadataKeys) (created bool, refID uint64) {
 	from, through := chks.Bounds()
 	var id uint64
-	created, refID = h.series.Append(ls, chks, func() *memSeries {
+	created, refID = h.series.Append(ls, chks, keys, func() *memSeries {
 		id = h.lastSeriesID.Inc()
 		return newMemSeries(id, ls, fprint)
 	})
@@ -176,6 +176,13 @@ func (h *Head) Append(ls labels.Labels, fprint uint64, chks index.ChunkMetas) (c
 	return
 }
 
+// AppendStructuredMetadataKeys adds the structured metadata keys of chunks to the catalog of an existing series.
+// It returns false if the series doesn't exist in the head. It is only used when replaying the WAL, the keys are
+// otherwise appended along with their chunks.
+func (h *Head) AppendStructuredMetadataKeys(ls labels.Labels, keys index.StructuredMetadataKeys) (ok bool, refID uint64) {
+	return h.series.AppendStructuredMetadataKeys(ls, keys)
+}
+
 // seriesHashmap is a simple hashmap for memSeries by their label set. It is built
 // on top of a regular hashmap and holds a slice of series to resolve hash collisions.
 // Its methods require the hash to be submitted with it to avoid re-computations throughout
@@ -252,10 +259,12 @@ func (s *stripeSeries) getByID(id uint64) *memSeries {
 	return x.m[id]
 }
 
-// Append adds chunks to the correct series and returns whether a new series was added
+// Append adds chunks and the structured metadata keys of their entries to the correct series and returns
+// whether a new series was added
 func (s *stripeSeries) Append(
 	ls labels.Labels,
 	chks index.ChunkMetas,
+	keys index.StructuredMetadataKeys,
 	createFn func() *memSeries,
 ) (created bool, refID uint64) {
 	fp := ls.Hash()
@@ -278,18 +287,41 @@ func (s *stripeSeries) Append(
 
 	series.Lock()
 	series.chks = append(series.chks, chks...)
+	if len(keys) > 0 {
+		series.keys = series.keys.Merge(keys)
+	}
 	refID = series.ref
 	series.Unlock()
 
 	return
 }
 
+// AppendStructuredMetadataKeys adds keys to the catalog of the series and returns whether the series exists
+func (s *stripeSeries) AppendStructuredMetadataKeys(ls labels.Labels, keys index.StructuredMetadataKeys) (ok bool, refID uint64) {
+	fp := ls.Hash()
+	hashes := s.hashes[fp&uint64(s.shards-1)]
+	hashes.RLock()
+	series := hashes.get(fp, ls)
+	hashes.RUnlock()
+	if series == nil {
+		return false, 0
+	}
+
+	series.Lock()
+	series.keys = series.keys.Merge(keys)
+	refID = series.ref
+	series.Unlock()
+
+	return true, refID
+}
+
 type memSeries struct {
 	sync.RWMutex
 	ref  uint64 // The unique reference within a *Head
 	ls   labels.Labels
 	fp   uint64
 	chks index.ChunkMetas
+	keys index.StructuredMetadataKeys
 }
 
 func newMemSeries(ref uint64, ls labels.Labels, fp uint64) *memSeries {
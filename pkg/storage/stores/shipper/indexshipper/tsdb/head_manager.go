@@ -243,7 +243,9 @@ func (m *HeadManager) Stop() error {
 	return m.buildTSDBFromHead(m.activeHeads)
 }
 
-func (m *HeadManager) Append(userID string, ls labels.Labels, fprint uint64, chks index.ChunkMetas) error {
+// Append adds the chunks of a series to the active head along with the structured metadata keys of their entries,
+// so that both are always logged to the same head and WAL.
+func (m *HeadManager) Append(userID string, ls labels.Labels, fprint uint64, chks index.ChunkMetas, keys index.StructuredMetadataKeys) error {
 	// TSDB doesnt need the __name__="log" convention the old chunk store index used.
 	// We must create a copy of the labels here to avoid mutating the existing
 	// labels when writing across index buckets.
@@ -254,7 +256,7 @@ func (m *HeadManager) Append(userID string, ls labels.Labels, fprint uint64, chk
 	m.mtx.RLock()
 	defer m.mtx.RUnlock()
 
-	rec := m.activeHeads.Append(userID, ls, fprint, chks)
+	rec := m.activeHeads.Append(userID, ls, fprint, chks, keys)
 	return m.active.Log(rec)
 }
 
@@ -594,7 +596,16 @@ func recoverHead(name, dir string, heads *tenantHeads, wals []WALIdentifier, leg
 					if !ok {
 						return fmt.Errorf("found tsdb chunk metas without series in WAL replay (period=%s): %+v", id.String(), *rec)
 					}
-					_ = heads.Append(rec.UserID, x.ls, x.fp, rec.Chks.Chks)
+					_ = heads.Append(rec.UserID, x.ls, x.fp, rec.Chks.Chks, nil)
+				}
+
+				// structured metadata keys are always written to the WAL after the chunks they were appended with
+				if len(rec.StructuredMetadataKeys.Keys) > 0 {
+					x, ok := seriesMap[rec.UserID][rec.StructuredMetadataKeys.Ref]
+					if !ok {
+						return fmt.Errorf("found tsdb structured metadata keys without series in WAL replay (period=%s): %+v", id.String(), *rec)
+					}
+					_ = heads.AppendStructuredMetadataKeys(rec.UserID, x.ls, rec.StructuredMetadataKeys.Keys)
 				}
 			}
 			return reader.Err()
@@ -655,7 +666,7 @@ func newTenantHeads(start time.Time, shards int, metrics *Metrics, logger log.Lo
 	return res
 }
 
-func (t *tenantHeads) Append(userID string, ls labels.Labels, fprint uint64, chks index.ChunkMetas) *WALRecord {
+func (t *tenantHeads) Append(userID string, ls labels.Labels, fprint uint64, chks index.ChunkMetas, keys index.StructuredMetadataKeys) *WALRecord {
 	var mint, maxt int64
 	for _, chk := range chks {
 		if chk.MinTime < mint || mint == 0 {
@@ -669,7 +680,7 @@ func (t *tenantHeads) Append(userID string, ls labels.Labels, fprint uint64, chk
 	updateMintMaxt(mint, maxt, &t.mint, &t.maxt)
 
 	head := t.getOrCreateTenantHead(userID)
-	newStream, refID := head.Append(ls, fprint, chks)
+	newStream, refID := head.Append(ls, fprint, chks, keys)
 
 	rec := &WALRecord{
 		UserID: userID,
@@ -677,6 +688,10 @@ func (t *tenantHeads) Append(userID string, ls labels.Labels, fprint uint64, chk
 			Ref:  refID,
 			Chks: chks,
 		},
+		StructuredMetadataKeys: StructuredMetadataKeysRecord{
+			Ref:  refID,
+			Keys: keys,
+		},
 	}
 
 	if newStream {
@@ -690,6 +705,24 @@ func (t *tenantHeads) Append(userID string, ls labels.Labels, fprint uint64, chk
 	return rec
 }
 
+// AppendStructuredMetadataKeys adds keys to the catalog of an existing series of the tenant when replaying the WAL.
+// It returns a nil record if the series doesn't exist.
+func (t *tenantHeads) AppendStructuredMetadataKeys(userID string, ls labels.Labels, keys index.StructuredMetadataKeys) *WALRecord {
+	head := t.getOrCreateTenantHead(userID)
+	ok, refID := head.AppendStructuredMetadataKeys(ls, keys)
+	if !ok {
+		return nil
+	}
+
+	return &WALRecord{
+		UserID: userID,
+		StructuredMetadataKeys: StructuredMetadataKeysRecord{
+			Ref:  refID,
+			Keys: keys,
+		},
+	}
+}
+
 func (t *tenantHeads) getOrCreateTenantHead(userID string) *Head {
 	idx := t.shardForTenant(userID)
 	mtx := &t.locks[idx]
@@ -766,6 +799,14 @@ func (t *tenantHeads) Series(ctx context.Context, userID string, from, through m
 
 }
 
+func (t *tenantHeads) StructuredMetadataKeys(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) ([]string, error) {
+	idx, ok := t.tenantIndex(userID, from, through)
+	if !ok {
+		return nil, nil
+	}
+	return idx.StructuredMetadataKeys(ctx, userID, from, through, matchers...)
+}
+
 func (t *tenantHeads) LabelNames(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) ([]string, error) {
 	idx, ok := t.tenantIndex(userID, from, through)
 	if !ok {
@@ -809,7 +850,7 @@ func (t *tenantHeads) ForSeries(ctx context.Context, userID string, fpFilter ind
 }
 
 // helper only used in building TSDBs
-func (t *tenantHeads) forAll(fn func(user string, ls labels.Labels, fp uint64, chks index.ChunkMetas) error) error {
+func (t *tenantHeads) forAll(fn func(user string, ls labels.Labels, fp uint64, chks index.ChunkMetas, keys index.StructuredMetadataKeys) error) error {
 	for i, shard := range t.tenants {
 		t.locks[i].RLock()
 		defer t.locks[i].RUnlock()
@@ -825,6 +866,7 @@ func (t *tenantHeads) forAll(fn func(user string, ls labels.Labels, fp uint64, c
 				var (
 					ls   labels.Labels
 					chks []index.ChunkMeta
+					keys index.StructuredMetadataKeys
 				)
 
 				fp, err := idx.Series(ps.At(), 0, math.MaxInt64, &ls, &chks)
@@ -833,7 +875,11 @@ func (t *tenantHeads) forAll(fn func(user string, ls labels.Labels, fp uint64, c
 					return errors.Wrapf(err, "iterating postings for tenant: %s", user)
 				}
 
-				if err := fn(user, ls, fp, chks); err != nil {
+				if _, err := idx.StructuredMetadataKeys(ps.At(), 0, math.MaxInt64, &ls, &keys); err != nil {
+					return errors.Wrapf(err, "iterating postings for tenant: %s", user)
+				}
+
+				if err := fn(user, ls, fp, chks, keys); err != nil {
 					return err
 				}
 			}
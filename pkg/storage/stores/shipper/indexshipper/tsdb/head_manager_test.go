@@ -108,7 +108,7 @@ func Test_TenantHeads_Append(t *testing.T) {
 			Entries:  30,
 		},
 	}
-	_ = h.Append("fake", ls, ls.Hash(), chks)
+	_ = h.Append("fake", ls, ls.Hash(), chks, nil)
 
 	found, err := h.GetChunkRefs(
 		context.Background(),
@@ -123,6 +123,32 @@ func Test_TenantHeads_Append(t *testing.T) {
 
 }
 
+func Test_TenantHeads_AppendStructuredMetadataKeys(t *testing.T) {
+	h := newTenantHeads(time.Now(), defaultHeadManagerStripeSize, NewMetrics(nil), log.NewNopLogger())
+	ls := mustParseLabels(`{foo="bar"}`)
+	var keys index.StructuredMetadataKeys
+	keys = keys.Add("trace_id", 1, 10)
+
+	// keys of unknown series are dropped when replaying the WAL
+	rec := h.AppendStructuredMetadataKeys("fake", ls, index.StructuredMetadataKeys{}.Add("pod", 20, 30))
+	require.Nil(t, rec)
+
+	// keys are recorded along with their chunks
+	rec = h.Append("fake", ls, ls.Hash(), []index.ChunkMeta{{MinTime: 1, MaxTime: 10}}, keys)
+	require.Equal(t, rec.Chks.Ref, rec.StructuredMetadataKeys.Ref)
+	require.Equal(t, keys, rec.StructuredMetadataKeys.Keys)
+
+	_ = h.Append("fake", ls, ls.Hash(), []index.ChunkMeta{{MinTime: 20, MaxTime: 30}}, index.StructuredMetadataKeys{}.Add("pod", 20, 30))
+
+	names, err := h.StructuredMetadataKeys(context.Background(), "fake", 0, 100, labels.MustNewMatcher(labels.MatchEqual, "foo", "bar"))
+	require.Nil(t, err)
+	require.Equal(t, []string{"pod", "trace_id"}, names)
+
+	names, err = h.StructuredMetadataKeys(context.Background(), "fake", 15, 100, labels.MustNewMatcher(labels.MatchEqual, "foo", "bar"))
+	require.Nil(t, err)
+	require.Equal(t, []string{"pod"}, names)
+}
+
 // Test multitenant reads
 func Test_TenantHeads_MultiRead(t *testing.T) {
 	h := newTenantHeads(time.Now(), defaultHeadManagerStripeSize, NewMetrics(nil), log.NewNopLogger())
@@ -159,7 +185,7 @@ func Test_TenantHeads_MultiRead(t *testing.T) {
 
 	// add data for both tenants
 	for _, tenant := range tenants {
-		_ = h.Append(tenant.user, tenant.ls, tenant.ls.Hash(), chks)
+		_ = h.Append(tenant.user, tenant.ls, tenant.ls.Hash(), chks, nil)
 
 	}
 
@@ -267,6 +293,34 @@ func Test_HeadManager_RecoverHead(t *testing.T) {
 
 }
 
+// test structured metadata keys are logged to the WAL along with their chunks
+func Test_HeadManager_RecoverStructuredMetadataKeys(t *testing.T) {
+	now := time.Now()
+	dir := t.TempDir()
+	ls := mustParseLabels(`{foo="bar"}`)
+	keys := index.StructuredMetadataKeys{}.Add("trace_id", 1, 10)
+
+	storeName := "store_2010-10-10"
+	mgr := NewHeadManager(storeName, log.NewNopLogger(), dir, NewMetrics(nil), newNoopTSDBManager(storeName, dir))
+	for _, d := range managerRequiredDirs(storeName, dir) {
+		require.Nil(t, util.EnsureDirectory(d))
+	}
+	require.Nil(t, mgr.Rotate(now))
+
+	require.Nil(t, mgr.Append("tenant1", ls, ls.Hash(), []index.ChunkMeta{{MinTime: 1, MaxTime: 10, Checksum: 3}}, keys))
+	require.Nil(t, mgr.active.Stop())
+
+	grp, ok, err := walsForPeriod(managerWalDir(mgr.name, mgr.dir), mgr.period, mgr.period.PeriodFor(now))
+	require.Nil(t, err)
+	require.True(t, ok)
+	heads := newTenantHeads(now, defaultHeadManagerStripeSize, NewMetrics(nil), log.NewNopLogger())
+	require.Nil(t, recoverHead(mgr.name, mgr.dir, heads, grp.wals, false))
+
+	names, err := heads.StructuredMetadataKeys(context.Background(), "tenant1", 0, 100, labels.MustNewMatcher(labels.MatchEqual, "foo", "bar"))
+	require.Nil(t, err)
+	require.Equal(t, []string{"trace_id"}, names)
+}
+
 // test head still serves data for the most recently rotated period.
 func Test_HeadManager_QueryAfterRotate(t *testing.T) {
 	now := time.Now()
@@ -302,7 +356,7 @@ func Test_HeadManager_QueryAfterRotate(t *testing.T) {
 
 	// add data for both tenants
 	for _, tc := range cases {
-		require.Nil(t, mgr.Append(tc.User, tc.Labels, tc.Labels.Hash(), tc.Chunks))
+		require.Nil(t, mgr.Append(tc.User, tc.Labels, tc.Labels.Hash(), tc.Chunks, nil))
 	}
 
 	nextPeriod := time.Now().Add(time.Duration(mgr.period))
@@ -416,7 +470,7 @@ func Test_HeadManager_Lifecycle(t *testing.T) {
 		},
 	}
 
-	require.Nil(t, mgr.Append(newCase.User, newCase.Labels, newCase.Labels.Hash(), newCase.Chunks))
+	require.Nil(t, mgr.Append(newCase.User, newCase.Labels, newCase.Labels.Hash(), newCase.Chunks, nil))
 
 	// Ensure old + new data is queryable
 	for _, c := range append(cases, newCase) {
@@ -644,7 +698,7 @@ func BenchmarkTenantHeads(b *testing.B) {
 				ls := mustParseLabels(fmt.Sprintf(`{foo="bar", i="%d"}`, i))
 				heads.Append(fmt.Sprint(tenant), ls, ls.Hash(), index.ChunkMetas{
 					{},
-				})
+				}, nil)
 			}
 
 			for n := 0; n < b.N; n++ {
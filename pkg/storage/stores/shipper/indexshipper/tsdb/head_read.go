@@ -170,6 +170,27 @@ func (h *headIndexReader) ChunkStats(ref storage.SeriesRef, from, through int64,
 	return s.fp, res, nil
 }
 
+func (h *headIndexReader) StructuredMetadataKeys(ref storage.SeriesRef, from, through int64, lbls *labels.Labels, keys *index.StructuredMetadataKeys) (uint64, error) {
+	s := h.head.series.getByID(uint64(ref))
+
+	if s == nil {
+		h.head.metrics.seriesNotFound.Inc()
+		return 0, storage.ErrNotFound
+	}
+	*lbls = append((*lbls)[:0], s.ls...)
+
+	*keys = (*keys)[:0]
+	s.Lock()
+	for _, key := range s.keys {
+		if key.Overlaps(from, through) {
+			*keys = append(*keys, key)
+		}
+	}
+	s.Unlock()
+
+	return s.fp, nil
+}
+
 // LabelValueFor returns label value for the given label name in the series referred to by ID.
 func (h *headIndexReader) LabelValueFor(id storage.SeriesRef, label string) (string, error) {
 	memSeries := h.head.series.getByID(uint64(id))
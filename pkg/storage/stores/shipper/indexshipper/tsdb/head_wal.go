@@ -39,13 +39,16 @@ const (
 	WalRecordSeries RecordType = iota
 	WalRecordChunks
 	WalRecordSeriesWithFingerprint
+	WalRecordStructuredMetadataKeys
+	WalRecordChunksWithSegments
 )
 
 type WALRecord struct {
-	UserID      string
-	Series      record.RefSeries
-	Fingerprint uint64
-	Chks        ChunkMetasRecord
+	UserID                 string
+	Series                 record.RefSeries
+	Fingerprint            uint64
+	Chks                   ChunkMetasRecord
+	StructuredMetadataKeys StructuredMetadataKeysRecord
 }
 
 type ChunkMetasRecord struct {
@@ -53,6 +56,11 @@ type ChunkMetasRecord struct {
 	Ref  uint64
 }
 
+type StructuredMetadataKeysRecord struct {
+	Keys index.StructuredMetadataKeys
+	Ref  uint64
+}
+
 // NB(owen-d): unused since we started recording the fingerprint
 // with the series, but left here for future understanding
 func (r *WALRecord) encodeSeries(b []byte) []byte {
@@ -84,9 +92,23 @@ func (r *WALRecord) encodeSeriesWithFingerprint(b []byte) []byte {
 	return encoded
 }
 
+// encodeChunks encodes the chunk metas of the record. The segments chunks are stored in are only
+// encoded, with a distinct record type, when some chunks are stored in segments.
 func (r *WALRecord) encodeChunks(b []byte) []byte {
+	withSegments := false
+	for _, chk := range r.Chks.Chks {
+		if chk.SegmentLength > 0 {
+			withSegments = true
+			break
+		}
+	}
+
 	buf := encoding.EncWith(b)
-	buf.PutByte(byte(WalRecordChunks))
+	if withSegments {
+		buf.PutByte(byte(WalRecordChunksWithSegments))
+	} else {
+		buf.PutByte(byte(WalRecordChunks))
+	}
 	buf.PutUvarintStr(r.UserID)
 	buf.PutBE64(r.Chks.Ref)
 	buf.PutUvarint(len(r.Chks.Chks))
@@ -97,12 +119,17 @@ func (r *WALRecord) encodeChunks(b []byte) []byte {
 		buf.PutBE32(chk.Checksum)
 		buf.PutBE32(chk.KB)
 		buf.PutBE32(chk.Entries)
+		if withSegments {
+			buf.PutBE64(chk.SegmentID)
+			buf.PutBE64(chk.SegmentOffset)
+			buf.PutBE32(chk.SegmentLength)
+		}
 	}
 
 	return buf.Get()
 }
 
-func decodeChunks(b []byte, rec *WALRecord) error {
+func decodeChunks(b []byte, rec *WALRecord, withSegments bool) error {
 	if len(b) == 0 {
 		return nil
 	}
@@ -122,13 +149,19 @@ func decodeChunks(b []byte, rec *WALRecord) error {
 	rec.Chks.Chks = make(index.ChunkMetas, 0, ln)
 
 	for len(dec.B) > 0 && dec.Err() == nil {
-		rec.Chks.Chks = append(rec.Chks.Chks, index.ChunkMeta{
+		chk := index.ChunkMeta{
 			MinTime:  dec.Be64int64(),
 			MaxTime:  dec.Be64int64(),
 			Checksum: dec.Be32(),
 			KB:       dec.Be32(),
 			Entries:  dec.Be32(),
-		})
+		}
+		if withSegments {
+			chk.SegmentID = dec.Be64()
+			chk.SegmentOffset = dec.Be64()
+			chk.SegmentLength = dec.Be32()
+		}
+		rec.Chks.Chks = append(rec.Chks.Chks, chk)
 	}
 
 	if err := dec.Err(); err != nil {
@@ -138,6 +171,55 @@ func decodeChunks(b []byte, rec *WALRecord) error {
 	return nil
 }
 
+func (r *WALRecord) encodeStructuredMetadataKeys(b []byte) []byte {
+	buf := encoding.EncWith(b)
+	buf.PutByte(byte(WalRecordStructuredMetadataKeys))
+	buf.PutUvarintStr(r.UserID)
+	buf.PutBE64(r.StructuredMetadataKeys.Ref)
+	buf.PutUvarint(len(r.StructuredMetadataKeys.Keys))
+
+	for _, key := range r.StructuredMetadataKeys.Keys {
+		buf.PutUvarintStr(key.Name)
+		buf.PutBE64(uint64(key.MinTime))
+		buf.PutBE64(uint64(key.MaxTime))
+	}
+
+	return buf.Get()
+}
+
+func decodeStructuredMetadataKeys(b []byte, rec *WALRecord) error {
+	if len(b) == 0 {
+		return nil
+	}
+
+	dec := encoding.DecWith(b)
+
+	rec.StructuredMetadataKeys.Ref = dec.Be64()
+	if err := dec.Err(); err != nil {
+		return errors.Wrap(err, "decoding series ref")
+	}
+
+	ln := dec.Uvarint()
+	if err := dec.Err(); err != nil {
+		return errors.Wrap(err, "decoding number of structured metadata keys")
+	}
+	rec.StructuredMetadataKeys.Keys = make(index.StructuredMetadataKeys, 0, ln)
+
+	for len(dec.B) > 0 && dec.Err() == nil {
+		rec.StructuredMetadataKeys.Keys = append(rec.StructuredMetadataKeys.Keys, index.StructuredMetadataKey{
+			Name:    dec.UvarintStr(),
+			MinTime: dec.Be64int64(),
+			MaxTime: dec.Be64int64(),
+		})
+	}
+
+	if err := dec.Err(); err != nil {
+		return errors.Wrap(err, "decoding structured metadata keys")
+	}
+
+	return nil
+}
+
 func decodeWALRecord(b []byte, walRec *WALRecord) error {
 	var (
 		userID string
@@ -175,9 +257,14 @@ func decodeWALRecord(b []byte, walRec *WALRecord) error {
 		if len(rSeries) == 1 {
 			walRec.Series = rSeries[0]
 		}
-	case WalRecordChunks:
+	case WalRecordChunks, WalRecordChunksWithSegments:
 		userID = decbuf.UvarintStr()
-		if err := decodeChunks(decbuf.B, walRec); err != nil {
+		if err := decodeChunks(decbuf.B, walRec, t == WalRecordChunksWithSegments); err != nil {
+			return err
+		}
+	case WalRecordStructuredMetadataKeys:
+		userID = decbuf.UvarintStr()
+		if err := decodeStructuredMetadataKeys(decbuf.B, walRec); err != nil {
 			return err
 		}
 	default:
@@ -225,22 +312,24 @@ func (w *headWAL) Log(record *WALRecord) error {
 		return nil
 	}
 
-	var buf []byte
+	var bufs [][]byte
 
 	// Always write series before chunks
 	if len(record.Series.Labels) > 0 {
-		buf = record.encodeSeriesWithFingerprint(buf[:0])
-		if err := w.wal.Log(buf); err != nil {
-			return err
-		}
+		bufs = append(bufs, record.encodeSeriesWithFingerprint(nil))
 	}
 
 	if len(record.Chks.Chks) > 0 {
-		buf = record.encodeChunks(buf[:0])
-		if err := w.wal.Log(buf); err != nil {
-			return err
-		}
+		bufs = append(bufs, record.encodeChunks(nil))
 	}
 
-	return nil
+	// the structured metadata keys are logged along with the chunks they were seen in
+	if len(record.StructuredMetadataKeys.Keys) > 0 {
+		bufs = append(bufs, record.encodeStructuredMetadataKeys(nil))
+	}
+
+	if len(bufs) == 0 {
+		return nil
+	}
+	return w.wal.Log(bufs...)
 }
@@ -76,6 +76,60 @@ func Test_Encoding_Chunks(t *testing.T) {
 	require.Equal(t, record, decoded)
 }
 
+func Test_Encoding_ChunksWithSegments(t *testing.T) {
+	record := &WALRecord{
+		UserID: "foo",
+		Chks: ChunkMetasRecord{
+			Ref: 1,
+			Chks: index.ChunkMetas{
+				{
+					Checksum:      1,
+					MinTime:       1,
+					MaxTime:       4,
+					KB:            5,
+					Entries:       6,
+					SegmentID:     42,
+					SegmentOffset: 0,
+					SegmentLength: 5 << 10,
+				},
+				{
+					Checksum: 2,
+					MinTime:  5,
+					MaxTime:  10,
+					KB:       7,
+					Entries:  8,
+				},
+			},
+		},
+	}
+	buf := record.encodeChunks(nil)
+	require.Equal(t, byte(WalRecordChunksWithSegments), buf[0])
+	decoded := &WALRecord{}
+
+	err := decodeWALRecord(buf, decoded)
+	require.Nil(t, err)
+	require.Equal(t, record, decoded)
+}
+
+func Test_Encoding_StructuredMetadataKeys(t *testing.T) {
+	record := &WALRecord{
+		UserID: "foo",
+		StructuredMetadataKeys: StructuredMetadataKeysRecord{
+			Ref: 1,
+			Keys: index.StructuredMetadataKeys{
+				{Name: "pod", MinTime: 1, MaxTime: 4},
+				{Name: "trace_id", MinTime: 5, MaxTime: 10},
+			},
+		},
+	}
+	buf := record.encodeStructuredMetadataKeys(nil)
+	decoded := &WALRecord{}
+
+	err := decodeWALRecord(buf, decoded)
+	require.Nil(t, err)
+	require.Equal(t, record, decoded)
+}
+
 func Test_HeadWALLog(t *testing.T) {
 	dir := t.TempDir()
 	w, err := newHeadWAL(log.NewNopLogger(), dir, time.Now())
@@ -21,6 +21,18 @@ type ChunkRef struct {
 	Fingerprint model.Fingerprint
 	Start, End  model.Time
 	Checksum    uint32
+
+	// The segment the chunk is stored in, if any. See index.ChunkMeta.
+	SegmentID     uint64
+	SegmentOffset uint64
+	SegmentLength uint32
+}
+
+// withoutSegment returns the ref without the segment the chunk is stored in, to compare chunks stored in
+// different objects.
+func (r ChunkRef) withoutSegment() ChunkRef {
+	r.SegmentID, r.SegmentOffset, r.SegmentLength = 0, 0, 0
+	return r
 }
 
 // Compares by (Fp, Start, End, checksum)
@@ -63,6 +75,9 @@ type Index interface {
 	Series(ctx context.Context, userID string, from, through model.Time, res []Series, fpFilter index.FingerprintFilter, matchers ...*labels.Matcher) ([]Series, error)
 	LabelNames(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) ([]string, error)
 	LabelValues(ctx context.Context, userID string, from, through model.Time, name string, matchers ...*labels.Matcher) ([]string, error)
+	// StructuredMetadataKeys returns the sorted names of the structured metadata keys seen in the chunks of the matching
+	// series within the time range. Only indices of FormatV4 and above record structured metadata keys.
+	StructuredMetadataKeys(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) ([]string, error)
 	Stats(ctx context.Context, userID string, from, through model.Time, acc IndexStatsAccumulator, fpFilter index.FingerprintFilter, shouldIncludeChunk shouldIncludeChunk, matchers ...*labels.Matcher) error
 	Volume(ctx context.Context, userID string, from, through model.Time, acc VolumeAccumulator, fpFilter index.FingerprintFilter, shouldIncludeChunk shouldIncludeChunk, targetLabels []string, aggregateBy string, matchers ...*labels.Matcher) error
 }
@@ -86,6 +101,10 @@ func (NoopIndex) LabelValues(_ context.Context, _ string, _, _ model.Time, _ str
 	return nil, nil
 }
 
+func (NoopIndex) StructuredMetadataKeys(_ context.Context, _ string, _, _ model.Time, _ ...*labels.Matcher) ([]string, error) {
+	return nil, nil
+}
+
 func (NoopIndex) Stats(_ context.Context, _ string, _, _ model.Time, _ IndexStatsAccumulator, _ index.FingerprintFilter, _ shouldIncludeChunk, _ ...*labels.Matcher) error {
 	return nil
 }
@@ -19,6 +19,12 @@ type ChunkMeta struct {
 	KB uint32
 
 	Entries uint32
+
+	// The segment object the chunk is stored in with other chunks, and the range of the chunk
+	// within the segment. A zero SegmentLength means the chunk is stored in its own object.
+	SegmentID     uint64
+	SegmentOffset uint64
+	SegmentLength uint32
 }
 
 func (c ChunkMeta) From() model.Time                 { return model.Time(c.MinTime) }
@@ -250,3 +256,22 @@ func (cs *ChunkStats) AddChunk(chk *ChunkMeta, from, through int64) {
 	entries := uint32(float64(chk.Entries) * factor)
 	cs.addRaw(1, kb, entries)
 }
+
+// putChunkSegment encodes the segment the chunk is stored in.
+// Chunks stored in their own object only take a single byte.
+func putChunkSegment(e *encoding.Encbuf, c ChunkMeta) {
+	e.PutUvarint32(c.SegmentLength)
+	if c.SegmentLength > 0 {
+		e.PutBE64(c.SegmentID)
+		e.PutUvarint64(c.SegmentOffset)
+	}
+}
+
+func readChunkSegment(d *encoding.Decbuf, c *ChunkMeta) {
+	c.SegmentLength = uint32(d.Uvarint())
+	c.SegmentID, c.SegmentOffset = 0, 0
+	if c.SegmentLength > 0 {
+		c.SegmentID = d.Be64()
+		c.SegmentOffset = d.Uvarint64()
+	}
+}
@@ -54,6 +54,12 @@ const (
 	// FormatV3 represents 3 version of index. It adds support for
 	// paging through batches of chunks within a series
 	FormatV3 = 3
+	// FormatV4 represents 4 version of index. It adds a catalog of
+	// the structured metadata keys seen in the chunks of each series
+	FormatV4 = 4
+	// FormatV5 represents 5 version of index. It adds the segment
+	// object and range each chunk is stored in, if any
+	FormatV5 = 5
 
 	IndexFilename = "index"
 
@@ -454,6 +460,12 @@ func (w *Writer) writeMeta() error {
 // multitenant TSDBs embed a tenant label, but the actual series has no such
 // label and so the derived fingerprint differs.
 func (w *Writer) AddSeries(ref storage.SeriesRef, lset labels.Labels, fp model.Fingerprint, chunks ...ChunkMeta) error {
+	return w.AddSeriesWithStructuredMetadataKeys(ref, lset, fp, nil, chunks...)
+}
+
+// AddSeriesWithStructuredMetadataKeys adds the series like AddSeries, together with the catalog of the
+// structured metadata keys of its chunks. The catalog is only written with FormatV4 and above.
+func (w *Writer) AddSeriesWithStructuredMetadataKeys(ref storage.SeriesRef, lset labels.Labels, fp model.Fingerprint, keys StructuredMetadataKeys, chunks ...ChunkMeta) error {
 	if err := w.ensureStage(idxStageSeries); err != nil {
 		return err
 	}
@@ -515,6 +527,12 @@ func (w *Writer) AddSeries(ref storage.SeriesRef, lset labels.Labels, fp model.F
 		w.buf2.PutUvarint32(valueIndex)
 	}
 
+	if w.Version > FormatV3 {
+		if err := w.writeStructuredMetadataKeys(keys, &w.buf2); err != nil {
+			return err
+		}
+	}
+
 	w.addChunks(chunks, &w.buf2, &w.buf1, ChunkPageSize)
 
 	w.buf1.Reset()
@@ -614,6 +632,10 @@ func (w *Writer) addChunksV3(chunks []ChunkMeta, primary, scratch *encoding.Encb
 
 			scratch.PutBE32(c.Checksum)
 
+			if w.Version > FormatV4 {
+				putChunkSegment(scratch, c)
+			}
+
 			// test if this is the last chunk in the page
 			if i%chunkPageSize == chunkPageSize-1 {
 				pageMarker.encode(primary, markerOffset, chunkPageSize)
@@ -1329,7 +1351,7 @@ func newReader(b ByteSlice, c io.Closer) (*Reader, error) {
 	}
 	r.version = int(r.b.Range(4, 5)[0])
 
-	if r.version != FormatV1 && r.version != FormatV2 && r.version != FormatV3 {
+	if r.version != FormatV1 && r.version != FormatV2 && r.version != FormatV3 && r.version != FormatV4 && r.version != FormatV5 {
 		return nil, errors.Errorf("unknown index file version %d", r.version)
 	}
 
@@ -1849,6 +1871,23 @@ func (r *Reader) ChunkStats(id storage.SeriesRef, from, through int64, lbls *lab
 	return r.dec.ChunkStats(r.version, d.Get(), id, from, through, lbls)
 }
 
+// StructuredMetadataKeys reads the series with the given ID and writes its labels and the structured metadata keys
+// seen in its chunks overlapping the given time range into lbls and keys.
+func (r *Reader) StructuredMetadataKeys(id storage.SeriesRef, from, through int64, lbls *labels.Labels, keys *StructuredMetadataKeys) (uint64, error) {
+	offset := id
+	// In version 2+ series IDs are no longer exact references but series are 16-byte padded
+	// and the ID is the multiple of 16 of the actual position.
+	if r.version >= FormatV2 {
+		offset = id * 16
+	}
+	d := encoding.DecWrap(tsdb_enc.NewDecbufUvarintAt(r.b, int(offset), castagnoliTable))
+	if d.Err() != nil {
+		return 0, d.Err()
+	}
+
+	return r.dec.StructuredMetadataKeys(r.version, d.Get(), from, through, lbls, keys)
+}
+
 func (r *Reader) Postings(name string, fpFilter FingerprintFilter, values ...string) (Postings, error) {
 	if r.version == FormatV1 {
 		e, ok := r.postingsV1[name]
@@ -2159,7 +2198,7 @@ func buildChunkSamples(d encoding.Decbuf, numChunks int, info *chunkSamples) err
 
 	chunkPos := bufLen - d.Len()
 	chunkMeta := &ChunkMeta{}
-	if err := readChunkMeta(&d, 0, chunkMeta); err != nil {
+	if err := readChunkMeta(FormatV2, &d, 0, chunkMeta); err != nil {
 		return errors.Wrapf(d.Err(), "read meta for chunk %d", 0)
 	}
 
@@ -2175,7 +2214,7 @@ func buildChunkSamples(d encoding.Decbuf, numChunks int, info *chunkSamples) err
 
 	for i := 1; i < numChunks; i++ {
 		chunkPos = bufLen - d.Len()
-		if err := readChunkMeta(&d, t0, chunkMeta); err != nil {
+		if err := readChunkMeta(FormatV2, &d, t0, chunkMeta); err != nil {
 			return errors.Wrapf(d.Err(), "read meta for chunk %d", i)
 		}
 		if chunkMeta.MaxTime > largestMaxt {
@@ -2200,11 +2239,14 @@ func buildChunkSamples(d encoding.Decbuf, numChunks int, info *chunkSamples) err
 	return d.Err()
 }
 
-func (dec *Decoder) prepSeries(b []byte, lbls *labels.Labels, chks *[]ChunkMeta) (*encoding.Decbuf, uint64, error) {
+func (dec *Decoder) prepSeries(version int, b []byte, from, through int64, lbls *labels.Labels, chks *[]ChunkMeta, keys *StructuredMetadataKeys) (*encoding.Decbuf, uint64, error) {
 	*lbls = (*lbls)[:0]
 	if chks != nil {
 		*chks = (*chks)[:0]
 	}
+	if keys != nil {
+		*keys = (*keys)[:0]
+	}
 
 	d := encoding.DecWrap(tsdb_enc.Decbuf{B: b})
 
@@ -2230,11 +2272,17 @@ func (dec *Decoder) prepSeries(b []byte, lbls *labels.Labels, chks *[]ChunkMeta)
 
 		*lbls = append(*lbls, labels.Label{Name: ln, Value: lv})
 	}
+
+	if version > FormatV3 {
+		if err := dec.readStructuredMetadataKeys(&d, from, through, keys); err != nil {
+			return nil, 0, err
+		}
+	}
 	return &d, fprint, nil
 }
 
 func (dec *Decoder) ChunkStats(version int, b []byte, seriesRef storage.SeriesRef, from, through int64, lbls *labels.Labels) (uint64, ChunkStats, error) {
-	d, fp, err := dec.prepSeries(b, lbls, nil)
+	d, fp, err := dec.prepSeries(version, b, from, through, lbls, nil, nil)
 	if err != nil {
 		return 0, ChunkStats{}, err
 	}
@@ -2245,19 +2293,19 @@ func (dec *Decoder) ChunkStats(version int, b []byte, seriesRef storage.SeriesRe
 
 func (dec *Decoder) readChunkStats(version int, d *encoding.Decbuf, seriesRef storage.SeriesRef, from, through int64) (ChunkStats, error) {
 	if version > FormatV2 {
-		return dec.readChunkStatsV3(d, from, through)
+		return dec.readChunkStatsV3(version, d, from, through)
 	}
 	return dec.readChunkStatsPriorV3(d, seriesRef, from, through)
 }
 
-func (dec *Decoder) readChunkStatsV3(d *encoding.Decbuf, from, through int64) (res ChunkStats, err error) {
+func (dec *Decoder) readChunkStatsV3(version int, d *encoding.Decbuf, from, through int64) (res ChunkStats, err error) {
 	nChunks := d.Uvarint()
 	markersLn := int(d.Be32()) // markersLn
 	startMarkers := d.Len()
 
 	if nChunks < dec.maxChunksToBypassMarkerLookup {
 		d.Skip(markersLn)
-		return dec.accumulateChunkStats(d, nChunks, from, through)
+		return dec.accumulateChunkStats(version, d, nChunks, from, through)
 	}
 
 	nMarkers := d.Uvarint()
@@ -2315,9 +2363,9 @@ func (dec *Decoder) readChunkStatsV3(d *encoding.Decbuf, from, through int64) (r
 				// but this doesn't reset at page boundaries
 				// (maybe it should for more ergonomic programming).
 				// instead, we can just force the min-time to the page's min-time
-				err = readChunkMetaWithForcedMintime(d, curMarker.MinTime, chunkMeta, true)
+				err = readChunkMetaWithForcedMintime(version, d, curMarker.MinTime, chunkMeta, true)
 			} else {
-				err = readChunkMeta(d, prevMaxT, chunkMeta)
+				err = readChunkMeta(version, d, prevMaxT, chunkMeta)
 			}
 			if err != nil {
 				return res, errors.Wrap(d.Err(), "read meta for chunk")
@@ -2338,11 +2386,11 @@ func (dec *Decoder) readChunkStatsV3(d *encoding.Decbuf, from, through int64) (r
 
 }
 
-func (dec *Decoder) accumulateChunkStats(d *encoding.Decbuf, nChunks int, from, through int64) (res ChunkStats, err error) {
+func (dec *Decoder) accumulateChunkStats(version int, d *encoding.Decbuf, nChunks int, from, through int64) (res ChunkStats, err error) {
 	var prevMaxT int64
 	chunkMeta := &ChunkMeta{}
 	for i := 0; i < nChunks; i++ {
-		if err := readChunkMeta(d, prevMaxT, chunkMeta); err != nil {
+		if err := readChunkMeta(version, d, prevMaxT, chunkMeta); err != nil {
 			return res, errors.Wrap(d.Err(), "read meta for chunk")
 		}
 		prevMaxT = chunkMeta.MaxTime
@@ -2379,10 +2427,20 @@ func (dec *Decoder) readChunkStatsPriorV3(d *encoding.Decbuf, seriesRef storage.
 
 }
 
+// StructuredMetadataKeys decodes a series entry from the given byte slice into lset and the catalog of its
+// structured metadata keys into keys. Index versions prior to FormatV4 have no catalog.
+func (dec *Decoder) StructuredMetadataKeys(version int, b []byte, from, through int64, lbls *labels.Labels, keys *StructuredMetadataKeys) (uint64, error) {
+	_, fprint, err := dec.prepSeries(version, b, from, through, lbls, nil, keys)
+	if err != nil {
+		return 0, errors.Wrapf(err, "series %s", lbls.String())
+	}
+	return fprint, nil
+}
+
 // Series decodes a series entry from the given byte slice into lset and chks.
 func (dec *Decoder) Series(version int, b []byte, seriesRef storage.SeriesRef, from int64, through int64, lbls *labels.Labels, chks *[]ChunkMeta) (uint64, error) {
 
-	d, fprint, err := dec.prepSeries(b, lbls, chks)
+	d, fprint, err := dec.prepSeries(version, b, from, through, lbls, chks, nil)
 	if err != nil {
 		return 0, err
 	}
@@ -2398,12 +2456,12 @@ func (dec *Decoder) Series(version int, b []byte, seriesRef storage.SeriesRef, f
 func (dec *Decoder) readChunks(version int, d *encoding.Decbuf, seriesRef storage.SeriesRef, from int64, through int64, chks *[]ChunkMeta) error {
 	// read chunks based on fmt
 	if version > FormatV2 {
-		return dec.readChunksV3(d, from, through, chks)
+		return dec.readChunksV3(version, d, from, through, chks)
 	}
 	return dec.readChunksPriorV3(d, seriesRef, from, through, chks)
 }
 
-func (dec *Decoder) readChunksV3(d *encoding.Decbuf, from int64, through int64, chks *[]ChunkMeta) error {
+func (dec *Decoder) readChunksV3(version int, d *encoding.Decbuf, from int64, through int64, chks *[]ChunkMeta) error {
 	nChunks := d.Uvarint()
 	chunksRemaining := nChunks
 
@@ -2455,9 +2513,9 @@ iterate:
 		chunkMeta := &ChunkMeta{}
 		var err error
 		if i == 0 && forceMinTime {
-			err = readChunkMetaWithForcedMintime(d, marker.MinTime, chunkMeta, true)
+			err = readChunkMetaWithForcedMintime(version, d, marker.MinTime, chunkMeta, true)
 		} else {
-			err = readChunkMeta(d, prevMaxT, chunkMeta)
+			err = readChunkMeta(version, d, prevMaxT, chunkMeta)
 		}
 		if err != nil {
 			return errors.Wrapf(d.Err(), "read meta for chunk %d", nChunks-chunksRemaining+i)
@@ -2494,7 +2552,7 @@ func (dec *Decoder) readChunksPriorV3(d *encoding.Decbuf, seriesRef storage.Seri
 	d.Skip(cs.offset)
 
 	chunkMeta := &ChunkMeta{}
-	if err := readChunkMeta(d, cs.prevChunkMaxt, chunkMeta); err != nil {
+	if err := readChunkMeta(FormatV2, d, cs.prevChunkMaxt, chunkMeta); err != nil {
 		return errors.Wrapf(d.Err(), "read meta for chunk %d", cs.idx)
 	}
 
@@ -2504,7 +2562,7 @@ func (dec *Decoder) readChunksPriorV3(d *encoding.Decbuf, seriesRef storage.Seri
 	t0 := chunkMeta.MaxTime
 
 	for i := cs.idx + 1; i < k; i++ {
-		if err := readChunkMeta(d, t0, chunkMeta); err != nil {
+		if err := readChunkMeta(FormatV2, d, t0, chunkMeta); err != nil {
 			return errors.Wrapf(d.Err(), "read meta for chunk %d", cs.idx)
 		}
 		t0 = chunkMeta.MaxTime
@@ -2518,14 +2576,14 @@ func (dec *Decoder) readChunksPriorV3(d *encoding.Decbuf, seriesRef storage.Seri
 	return d.Err()
 }
 
-func readChunkMeta(d *encoding.Decbuf, prevChunkMaxt int64, chunkMeta *ChunkMeta) error {
+func readChunkMeta(version int, d *encoding.Decbuf, prevChunkMaxt int64, chunkMeta *ChunkMeta) error {
 	// Decode the diff against previous chunk as varint
 	// instead of uvarint because chunks may overlap
 	mint := d.Varint64() + prevChunkMaxt
-	return readChunkMetaWithForcedMintime(d, mint, chunkMeta, false)
+	return readChunkMetaWithForcedMintime(version, d, mint, chunkMeta, false)
 }
 
-func readChunkMetaWithForcedMintime(d *encoding.Decbuf, mint int64, chunkMeta *ChunkMeta, decodeMinT bool) error {
+func readChunkMetaWithForcedMintime(version int, d *encoding.Decbuf, mint int64, chunkMeta *ChunkMeta, decodeMinT bool) error {
 	if decodeMinT {
 		// skip the mint delta since we're forcing, but still need to
 		// remove the bytes from our buffer
@@ -2537,6 +2595,10 @@ func readChunkMetaWithForcedMintime(d *encoding.Decbuf, mint int64, chunkMeta *C
 	chunkMeta.Entries = uint32(d.Uvarint64())
 	chunkMeta.Checksum = d.Be32()
 
+	if version > FormatV4 {
+		readChunkSegment(d, chunkMeta)
+	}
+
 	if d.Err() != nil {
 		return d.Err()
 	}
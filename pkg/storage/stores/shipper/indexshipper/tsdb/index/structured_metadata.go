@@ -0,0 +1,115 @@
+package index
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/grafana/loki/v3/pkg/util/encoding"
+)
+
+// StructuredMetadataKey is a structured metadata key seen in the chunks of a series,
+// together with the time bounds of the chunks it was seen in.
+type StructuredMetadataKey struct {
+	Name             string
+	MinTime, MaxTime int64
+}
+
+// Overlaps returns whether the key was seen in chunks overlapping the given time range.
+func (k StructuredMetadataKey) Overlaps(from, through int64) bool {
+	return k.MinTime <= through && from <= k.MaxTime
+}
+
+// StructuredMetadataKeys is the catalog of the structured metadata keys of a series, sorted by name.
+type StructuredMetadataKeys []StructuredMetadataKey
+
+// Add adds the key seen in a chunk with the given time bounds, extending the bounds of the key if it
+// is already known.
+func (k StructuredMetadataKeys) Add(name string, from, through int64) StructuredMetadataKeys {
+	i := sort.Search(len(k), func(i int) bool { return k[i].Name >= name })
+	if i < len(k) && k[i].Name == name {
+		if from < k[i].MinTime {
+			k[i].MinTime = from
+		}
+		if through > k[i].MaxTime {
+			k[i].MaxTime = through
+		}
+		return k
+	}
+
+	k = append(k, StructuredMetadataKey{})
+	copy(k[i+1:], k[i:])
+	k[i] = StructuredMetadataKey{Name: name, MinTime: from, MaxTime: through}
+	return k
+}
+
+// Merge adds all keys of other to the catalog.
+func (k StructuredMetadataKeys) Merge(other StructuredMetadataKeys) StructuredMetadataKeys {
+	for _, key := range other {
+		k = k.Add(key.Name, key.MinTime, key.MaxTime)
+	}
+	return k
+}
+
+// Copy returns a deep copy of the catalog, which doesn't reference the memory of the index it was read from.
+func (k StructuredMetadataKeys) Copy() StructuredMetadataKeys {
+	res := make(StructuredMetadataKeys, len(k))
+	for i, key := range k {
+		res[i] = StructuredMetadataKey{Name: strings.Clone(key.Name), MinTime: key.MinTime, MaxTime: key.MaxTime}
+	}
+	return res
+}
+
+// Names returns the names of the keys seen in chunks overlapping the given time range.
+func (k StructuredMetadataKeys) Names(from, through int64) []string {
+	names := make([]string, 0, len(k))
+	for _, key := range k {
+		if key.Overlaps(from, through) {
+			names = append(names, key.Name)
+		}
+	}
+	return names
+}
+
+// writeStructuredMetadataKeys encodes the catalog of a series as a list of symbol references of the key names,
+// followed by their time bounds.
+func (w *Writer) writeStructuredMetadataKeys(keys StructuredMetadataKeys, buf *encoding.Encbuf) error {
+	buf.PutUvarint(len(keys))
+	for _, key := range keys {
+		nameIndex, err := w.symbols.ReverseLookup(key.Name)
+		if err != nil {
+			return errors.Errorf("symbol entry for %q does not exist, %v", key.Name, err)
+		}
+		buf.PutUvarint32(nameIndex)
+		buf.PutVarint64(key.MinTime)
+		buf.PutUvarint64(uint64(key.MaxTime - key.MinTime))
+	}
+	return nil
+}
+
+// readStructuredMetadataKeys decodes the catalog of a series into keys, keeping only the keys seen in chunks
+// overlapping the given time range. If keys is nil, the catalog is skipped.
+func (dec *Decoder) readStructuredMetadataKeys(d *encoding.Decbuf, from, through int64, keys *StructuredMetadataKeys) error {
+	n := d.Uvarint()
+	for i := 0; i < n && d.Err() == nil; i++ {
+		nameIndex := uint32(d.Uvarint())
+		minTime := d.Varint64()
+		maxTime := minTime + int64(d.Uvarint64())
+		if keys == nil || d.Err() != nil {
+			continue
+		}
+
+		key := StructuredMetadataKey{MinTime: minTime, MaxTime: maxTime}
+		if !key.Overlaps(from, through) {
+			continue
+		}
+		name, err := dec.LookupSymbol(nameIndex)
+		if err != nil {
+			return errors.Wrap(err, "lookup structured metadata key")
+		}
+		key.Name = name
+		*keys = append(*keys, key)
+	}
+	return errors.Wrap(d.Err(), "read structured metadata keys")
+}
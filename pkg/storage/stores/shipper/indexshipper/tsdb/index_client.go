@@ -136,6 +136,10 @@ func (c *IndexClient) GetChunkRefs(ctx context.Context, userID string, from, thr
 			From:        chk.Start,
 			Through:     chk.End,
 			Checksum:    chk.Checksum,
+
+			SegmentID:     chk.SegmentID,
+			SegmentOffset: chk.SegmentOffset,
+			SegmentLength: chk.SegmentLength,
 		})
 	}
 
@@ -174,6 +178,14 @@ func (c *IndexClient) LabelNamesForMetricName(ctx context.Context, userID string
 	return c.idx.LabelNames(ctx, userID, from, through)
 }
 
+func (c *IndexClient) StructuredMetadataKeys(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) ([]string, error) {
+	matchers, _, err := cleanMatchers(matchers...)
+	if err != nil {
+		return nil, err
+	}
+	return c.idx.StructuredMetadataKeys(ctx, userID, from, through, matchers...)
+}
+
 func (c *IndexClient) Stats(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) (*stats.Stats, error) {
 	matchers, shard, err := cleanMatchers(matchers...)
 	if err != nil {
@@ -256,6 +268,10 @@ func (c *IndexClient) Volume(ctx context.Context, userID string, from, through m
 		})
 	})
 
+	if seriesvolume.IsCardinality(aggregateBy) {
+		return c.cardinality(ctx, userID, from, intervals, limit, targetLabels, aggregateBy, shard, matchers...)
+	}
+
 	acc := seriesvolume.NewAccumulator(limit, c.limits.VolumeMaxSeries(userID))
 	for _, interval := range intervals {
 		if err := c.idx.Volume(ctx, userID, interval.Start, interval.End, acc, shard, nil, targetLabels, aggregateBy, matchers...); err != nil {
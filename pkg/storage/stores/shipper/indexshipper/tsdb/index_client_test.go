@@ -320,3 +320,109 @@ type fakeLimits struct {
 func (f *fakeLimits) VolumeMaxSeries(_ string) int {
 	return f.volumeMaxSeries
 }
+
+func TestIndexClient_Cardinality(t *testing.T) {
+	tempDir := t.TempDir()
+	tableRange := config.TableRange{
+		Start: 0,
+		End:   math.MaxInt64,
+		PeriodConfig: &config.PeriodConfig{
+			IndexTables: config.IndexPeriodicTableConfig{
+				PeriodicTableConfig: config.PeriodicTableConfig{
+					Period: config.ObjectStorageIndexRequiredPeriod,
+				}},
+		},
+	}
+
+	indexStartToday := model.TimeFromUnixNano(time.Now().Truncate(config.ObjectStorageIndexRequiredPeriod).UnixNano())
+	indexStartYesterday := indexStartToday.Add(-config.ObjectStorageIndexRequiredPeriod)
+
+	tables := map[string][]*TSDBFile{
+		tableRange.PeriodConfig.IndexTables.TableFor(indexStartToday): {
+			BuildIndex(t, tempDir, []LoadableSeries{
+				{
+					Labels: mustParseLabels(`{foo="bar"}`),
+					Chunks: buildChunkMetas(int64(indexStartToday), int64(indexStartToday+99), 10),
+				},
+				{
+					Labels: mustParseLabels(`{foo="baz"}`),
+					Chunks: buildChunkMetas(int64(indexStartToday), int64(indexStartToday+99), 10),
+				},
+			}),
+		},
+
+		tableRange.PeriodConfig.IndexTables.TableFor(indexStartYesterday): {
+			BuildIndex(t, tempDir, []LoadableSeries{
+				{
+					Labels: mustParseLabels(`{foo="bar"}`),
+					Chunks: buildChunkMetas(int64(indexStartYesterday), int64(indexStartYesterday+99), 10),
+				},
+				{
+					Labels: mustParseLabels(`{foo="bar", fizz="buzz"}`),
+					Chunks: buildChunkMetas(int64(indexStartYesterday), int64(indexStartYesterday+99), 10),
+				},
+				{
+					Labels: mustParseLabels(`{ping="pong"}`),
+					Chunks: buildChunkMetas(int64(indexStartYesterday+100), int64(indexStartYesterday+199), 10),
+				},
+			}),
+		},
+	}
+
+	idx := newIndexShipperQuerier(mockIndexShipperIndexIterator{tables: tables}, config.TableRange{
+		Start:        0,
+		End:          math.MaxInt64,
+		PeriodConfig: &config.PeriodConfig{},
+	})
+
+	limits := &fakeLimits{volumeMaxSeries: 5}
+	indexClient := NewIndexClient(idx, IndexClientOptions{UseBloomFilters: true}, limits)
+	from := indexStartYesterday + 50
+	through := indexStartToday + 1000
+
+	t.Run("it counts the series of label values", func(t *testing.T) {
+		vol, err := indexClient.Volume(context.Background(), "", from, through, 10, []string{"foo"}, seriesvolume.SeriesCount, nil...)
+		require.NoError(t, err)
+
+		require.Equal(t, &logproto.VolumeResponse{
+			Volumes: []logproto.Volume{
+				{Name: `{foo="bar"}`, Volume: 2},
+				{Name: `{foo="baz"}`, Volume: 1},
+			},
+			Limit: 10,
+		}, vol)
+	})
+
+	t.Run("it counts the values of labels", func(t *testing.T) {
+		vol, err := indexClient.Volume(context.Background(), "", from, through, 10, nil, seriesvolume.LabelValues, nil...)
+		require.NoError(t, err)
+
+		require.Equal(t, &logproto.VolumeResponse{
+			Volumes: []logproto.Volume{
+				{Name: `foo`, Volume: 2},
+				{Name: `fizz`, Volume: 1},
+				{Name: `ping`, Volume: 1},
+			},
+			Limit: 10,
+		}, vol)
+	})
+
+	t.Run("it counts the series created during the range", func(t *testing.T) {
+		vol, err := indexClient.Volume(context.Background(), "", from, through, 10, nil, seriesvolume.Churn, nil...)
+		require.NoError(t, err)
+
+		require.Equal(t, &logproto.VolumeResponse{
+			Volumes: []logproto.Volume{
+				{Name: `{foo="baz"}`, Volume: 1},
+				{Name: `{ping="pong"}`, Volume: 1},
+			},
+			Limit: 10,
+		}, vol)
+	})
+
+	t.Run("it returns an error when the number of selected series exceeds the limit", func(t *testing.T) {
+		limits.volumeMaxSeries = 0
+		_, err := indexClient.Volume(context.Background(), "", from, through, 1, nil, seriesvolume.SeriesCount, nil...)
+		require.EqualError(t, err, fmt.Sprintf(seriesvolume.ErrVolumeMaxSeriesHit, 0))
+	})
+}
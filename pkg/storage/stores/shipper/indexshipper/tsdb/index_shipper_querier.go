@@ -108,6 +108,14 @@ func (i *indexShipperQuerier) LabelNames(ctx context.Context, userID string, fro
 	return idx.LabelNames(ctx, userID, from, through, matchers...)
 }
 
+func (i *indexShipperQuerier) StructuredMetadataKeys(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) ([]string, error) {
+	idx, err := i.indices(ctx, from, through, userID)
+	if err != nil {
+		return nil, err
+	}
+	return idx.StructuredMetadataKeys(ctx, userID, from, through, matchers...)
+}
+
 func (i *indexShipperQuerier) LabelValues(ctx context.Context, userID string, from, through model.Time, name string, matchers ...*labels.Matcher) ([]string, error) {
 	idx, err := i.indices(ctx, from, through, userID)
 	if err != nil {
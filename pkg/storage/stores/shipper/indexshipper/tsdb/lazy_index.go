@@ -57,6 +57,13 @@ func (f LazyIndex) LabelNames(ctx context.Context, userID string, from, through
 	}
 	return i.LabelNames(ctx, userID, from, through, matchers...)
 }
+func (f LazyIndex) StructuredMetadataKeys(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) ([]string, error) {
+	i, err := f()
+	if err != nil {
+		return nil, err
+	}
+	return i.StructuredMetadataKeys(ctx, userID, from, through, matchers...)
+}
 func (f LazyIndex) LabelValues(ctx context.Context, userID string, from, through model.Time, name string, matchers ...*labels.Matcher) ([]string, error) {
 	i, err := f()
 	if err != nil {
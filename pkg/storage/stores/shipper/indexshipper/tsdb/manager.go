@@ -154,13 +154,14 @@ func (m *tsdbManager) Start() (err error) {
 
 type chunkInfo struct {
 	chunkMetas index.ChunkMetas
+	keys       index.StructuredMetadataKeys
 	tsdbFormat int
 }
 
 func (m *tsdbManager) buildFromHead(heads *tenantHeads, indexShipper indexshipper.IndexShipper, tableRanges []config.TableRange) (err error) {
 	periods := make(map[string]*Builder)
 
-	if err := heads.forAll(func(user string, ls labels.Labels, fp uint64, chks index.ChunkMetas) error {
+	if err := heads.forAll(func(user string, ls labels.Labels, fp uint64, chks index.ChunkMetas, keys index.StructuredMetadataKeys) error {
 
 		// chunks may overlap index period bounds, in which case they're written to multiple
 		pds := make(map[string]chunkInfo)
@@ -175,6 +176,16 @@ func (m *tsdbManager) buildFromHead(heads *tenantHeads, indexShipper indexshippe
 			}
 		}
 
+		// as are the structured metadata keys seen in these chunks
+		for _, key := range keys {
+			for _, bucket := range indexBuckets(model.Time(key.MinTime), model.Time(key.MaxTime), tableRanges) {
+				if chkinfo, ok := pds[bucket.prefix]; ok {
+					chkinfo.keys = chkinfo.keys.Add(key.Name, key.MinTime, key.MaxTime)
+					pds[bucket.prefix] = chkinfo
+				}
+			}
+		}
+
 		// Embed the tenant label into TSDB
 		lb := labels.NewBuilder(ls)
 		lb.Set(TenantLabel, user)
@@ -196,6 +207,7 @@ func (m *tsdbManager) buildFromHead(heads *tenantHeads, indexShipper indexshippe
 				model.Fingerprint(fp),
 				matchingChks,
 			)
+			b.AddStructuredMetadataKeys(withTenant, chkinfo.keys)
 		}
 
 		return nil
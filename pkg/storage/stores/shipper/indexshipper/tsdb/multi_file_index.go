@@ -139,7 +139,7 @@ func (i *MultiIndex) GetChunkRefs(ctx context.Context, userID string, from, thro
 		}
 		res = res[:0]
 
-		// keep track of duplicates
+		// keep track of duplicates. Replicas can store the same chunk in different segments.
 		seen := make(map[ChunkRef]struct{})
 
 		// TODO(owen-d): Do this more efficiently,
@@ -150,11 +150,12 @@ func (i *MultiIndex) GetChunkRefs(ctx context.Context, userID string, from, thro
 			g := group
 			for _, ref := range g {
 
-				_, ok := seen[ref]
+				key := ref.withoutSegment()
+				_, ok := seen[key]
 				if ok {
 					continue
 				}
-				seen[ref] = struct{}{}
+				seen[key] = struct{}{}
 				res = append(res, ref)
 			}
 			ChunkRefsPool.Put(g)
@@ -301,6 +302,49 @@ func (i *MultiIndex) LabelNames(ctx context.Context, userID string, from, throug
 	return merged, nil
 }
 
+func (i *MultiIndex) StructuredMetadataKeys(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) ([]string, error) {
+	acc := newResultAccumulator(func(xs [][]string) ([]string, error) {
+		seen := make(map[string]struct{})
+		var results []string
+		for _, names := range xs {
+			for _, name := range names {
+				if _, ok := seen[name]; ok {
+					continue
+				}
+				seen[name] = struct{}{}
+				results = append(results, name)
+			}
+		}
+		sort.Strings(results)
+		return results, nil
+	})
+
+	if err := i.forMatchingIndices(
+		ctx,
+		from,
+		through,
+		func(ctx context.Context, idx Index) error {
+			got, err := idx.StructuredMetadataKeys(ctx, userID, from, through, matchers...)
+			if err != nil {
+				return err
+			}
+			acc.Add(got)
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	merged, err := acc.Merge()
+	if err != nil {
+		if err == ErrEmptyAccumulator {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return merged, nil
+}
+
 func (i *MultiIndex) LabelValues(ctx context.Context, userID string, from, through model.Time, name string, matchers ...*labels.Matcher) ([]string, error) {
 	acc := newResultAccumulator(func(xs [][]string) ([]string, error) {
 		var (
@@ -66,6 +66,10 @@ func (m *MultiTenantIndex) Series(ctx context.Context, userID string, from, thro
 	return xs, nil
 }
 
+func (m *MultiTenantIndex) StructuredMetadataKeys(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) ([]string, error) {
+	return m.idx.StructuredMetadataKeys(ctx, userID, from, through, withTenantLabelMatcher(userID, matchers)...)
+}
+
 func (m *MultiTenantIndex) LabelNames(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) ([]string, error) {
 	res, err := m.idx.LabelNames(ctx, userID, from, through, withTenantLabelMatcher(userID, matchers)...)
 	if err != nil {
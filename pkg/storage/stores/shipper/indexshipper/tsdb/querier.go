@@ -71,6 +71,10 @@ type IndexReader interface {
 	// ChunkStats returns the stats for the chunks in the given series.
 	ChunkStats(ref storage.SeriesRef, from, through int64, lset *labels.Labels) (uint64, index.ChunkStats, error)
 
+	// StructuredMetadataKeys populates the given labels and the structured metadata keys seen in the chunks
+	// of the series identified by the reference which overlap the given time range.
+	StructuredMetadataKeys(ref storage.SeriesRef, from, through int64, lset *labels.Labels, keys *index.StructuredMetadataKeys) (uint64, error)
+
 	// LabelNames returns all the unique label names present in the index in sorted order.
 	LabelNames(matchers ...*labels.Matcher) ([]string, error)
 
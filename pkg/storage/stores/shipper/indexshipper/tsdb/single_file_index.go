@@ -7,6 +7,8 @@ import (
 	"io"
 	"math"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/opentracing/opentracing-go"
@@ -198,6 +200,44 @@ func (i *TSDBIndex) ForSeries(ctx context.Context, _ string, fpFilter index.Fing
 
 }
 
+// ForSeriesStructuredMetadataKeys iterates over the series matching the matchers together with the structured
+// metadata keys seen in their chunks overlapping the given time range.
+// Like with ForSeries, fn must NOT capture it's arguments. Iteration will stop if the callback returns true.
+func (i *TSDBIndex) ForSeriesStructuredMetadataKeys(ctx context.Context, fpFilter index.FingerprintFilter, from model.Time, through model.Time, fn func(labels.Labels, model.Fingerprint, index.StructuredMetadataKeys) (stop bool), matchers ...*labels.Matcher) error {
+	var (
+		ls   labels.Labels
+		keys index.StructuredMetadataKeys
+	)
+
+	var filterer chunk.Filterer
+	if i.chunkFilter != nil {
+		filterer = i.chunkFilter.ForRequest(ctx)
+	}
+
+	return i.forPostings(ctx, fpFilter, from, through, matchers, func(p index.Postings) error {
+		for p.Next() {
+			hash, err := i.reader.StructuredMetadataKeys(p.At(), int64(from), int64(through), &ls, &keys)
+			if err != nil {
+				return err
+			}
+
+			// skip series that belong to different shards
+			if fpFilter != nil && !fpFilter.Match(model.Fingerprint(hash)) {
+				continue
+			}
+
+			if filterer != nil && filterer.ShouldFilter(ls) {
+				continue
+			}
+
+			if stop := fn(ls, model.Fingerprint(hash), keys); stop {
+				break
+			}
+		}
+		return p.Err()
+	})
+}
+
 func (i *TSDBIndex) forPostings(
 	_ context.Context,
 	fpFilter index.FingerprintFilter,
@@ -227,6 +267,10 @@ func (i *TSDBIndex) GetChunkRefs(ctx context.Context, userID string, from, throu
 				Start:       chk.From(),
 				End:         chk.Through(),
 				Checksum:    chk.Checksum,
+
+				SegmentID:     chk.SegmentID,
+				SegmentOffset: chk.SegmentOffset,
+				SegmentLength: chk.SegmentLength,
 			})
 		}
 		return false
@@ -274,6 +318,27 @@ func (i *TSDBIndex) LabelValues(_ context.Context, _ string, _, _ model.Time, na
 	return labelValuesWithMatchers(i.reader, name, matchers...)
 }
 
+func (i *TSDBIndex) StructuredMetadataKeys(ctx context.Context, _ string, from, through model.Time, matchers ...*labels.Matcher) ([]string, error) {
+	names := make(map[string]struct{})
+	if err := i.ForSeriesStructuredMetadataKeys(ctx, nil, from, through, func(_ labels.Labels, _ model.Fingerprint, keys index.StructuredMetadataKeys) (stop bool) {
+		for _, key := range keys {
+			if _, ok := names[key.Name]; !ok {
+				names[strings.Clone(key.Name)] = struct{}{}
+			}
+		}
+		return false
+	}, matchers...); err != nil {
+		return nil, err
+	}
+
+	res := make([]string, 0, len(names))
+	for name := range names {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
 func (i *TSDBIndex) Checksum() uint32 {
 	return i.reader.Checksum()
 }
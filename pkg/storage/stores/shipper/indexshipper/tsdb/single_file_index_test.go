@@ -78,7 +78,7 @@ func TestSingleIdx(t *testing.T) {
 			fn: func() Index {
 				head := NewHead("fake", NewMetrics(nil), log.NewNopLogger())
 				for _, x := range cases {
-					_, _ = head.Append(x.Labels, x.Labels.Hash(), x.Chunks)
+					_, _ = head.Append(x.Labels, x.Labels.Hash(), x.Chunks, nil)
 				}
 				reader := head.Index()
 				return NewTSDBIndex(reader)
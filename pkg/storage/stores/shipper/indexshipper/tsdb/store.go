@@ -13,6 +13,7 @@ import (
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
 
+	"github.com/grafana/loki/v3/pkg/chunkenc"
 	"github.com/grafana/loki/v3/pkg/storage/chunk"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/client"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/fetcher"
@@ -24,13 +25,14 @@ import (
 )
 
 type IndexWriter interface {
-	Append(userID string, ls labels.Labels, fprint uint64, chks tsdbindex.ChunkMetas) error
+	Append(userID string, ls labels.Labels, fprint uint64, chks tsdbindex.ChunkMetas, keys tsdbindex.StructuredMetadataKeys) error
 }
 
 type store struct {
 	index.Reader
 	indexShipper indexshipper.IndexShipper
 	indexWriter  IndexWriter
+	schemaCfg    config.SchemaConfig
 	logger       log.Logger
 	stopOnce     sync.Once
 }
@@ -67,6 +69,7 @@ func (s *store) init(name, prefix string, indexShipperCfg indexshipper.Config, s
 	limits downloads.Limits, tableRange config.TableRange, reg prometheus.Registerer) error {
 
 	var err error
+	s.schemaCfg = schemaCfg
 	s.indexShipper, err = indexshipper.NewIndexShipper(
 		prefix,
 		indexShipperCfg,
@@ -159,16 +162,45 @@ func (s *store) IndexChunk(_ context.Context, _ model.Time, _ model.Time, chk ch
 			MaxTime:  int64(chk.ChunkRef.Through),
 			KB:       uint32(approxKB),
 			Entries:  uint32(chk.Data.Entries()),
+
+			SegmentID:     chk.ChunkRef.SegmentID,
+			SegmentOffset: chk.ChunkRef.SegmentOffset,
+			SegmentLength: chk.ChunkRef.SegmentLength,
 		},
 	}
-	if err := s.indexWriter.Append(chk.UserID, chk.Metric, chk.ChunkRef.Fingerprint, metas); err != nil {
+	keys, err := s.structuredMetadataKeys(chk)
+	if err != nil {
+		return err
+	}
+	if err := s.indexWriter.Append(chk.UserID, chk.Metric, chk.ChunkRef.Fingerprint, metas, keys); err != nil {
 		return errors.Wrap(err, "writing index entry")
 	}
 	return nil
 }
 
+// structuredMetadataKeys returns the structured metadata keys of the chunk for index formats keeping a catalog of
+// the structured metadata keys of each series, and nil otherwise.
+func (s *store) structuredMetadataKeys(chk chunk.Chunk) (tsdbindex.StructuredMetadataKeys, error) {
+	periodCfg, err := s.schemaCfg.SchemaForTime(chk.From)
+	if err != nil {
+		return nil, err
+	}
+	if format, err := periodCfg.TSDBFormat(); err != nil || format < tsdbindex.FormatV4 {
+		return nil, err
+	}
+	names, err := chunkenc.StructuredMetadataKeys(chk.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading structured metadata keys")
+	}
+	var keys tsdbindex.StructuredMetadataKeys
+	for _, name := range names {
+		keys = keys.Add(name, int64(chk.From), int64(chk.Through))
+	}
+	return keys, nil
+}
+
 type failingIndexWriter struct{}
 
-func (f failingIndexWriter) Append(_ string, _ labels.Labels, _ uint64, _ tsdbindex.ChunkMetas) error {
+func (f failingIndexWriter) Append(_ string, _ labels.Labels, _ uint64, _ tsdbindex.ChunkMetas, _ tsdbindex.StructuredMetadataKeys) error {
 	return fmt.Errorf("index writer is not initialized due to tsdb store being initialized in read-only mode")
 }
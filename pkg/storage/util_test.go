@@ -224,6 +224,10 @@ func (m *mockChunkStore) LabelNamesForMetricName(_ context.Context, _ string, _,
 	return nil, nil
 }
 
+func (m *mockChunkStore) StructuredMetadataKeys(_ context.Context, _ string, _, _ model.Time, _ ...*labels.Matcher) ([]string, error) {
+	return nil, nil
+}
+
 func (m *mockChunkStore) SetChunkFilterer(f chunk.RequestChunkFilterer) {
 	m.f = f
 }
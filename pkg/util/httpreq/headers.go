@@ -14,6 +14,12 @@ var (
 	// LokiActorPathHeader is the name of the header e.g. used to enqueue requests in hierarchical queues.
 	LokiActorPathHeader               = "X-Loki-Actor-Path"
 	LokiDisablePipelineWrappersHeader = "X-Loki-Disable-Pipeline-Wrappers"
+	// LokiQueryCostHeader is the name of the header carrying the estimated cost in bytes of a sub-query.
+	LokiQueryCostHeader = "X-Loki-Query-Cost"
+	// LokiQueryPriorityHeader is the name of the header carrying the priority class of a query in the query-scheduler.
+	LokiQueryPriorityHeader = "X-Loki-Query-Priority"
+	// LokiQueryIDHeader is the name of the header carrying the ID of the running query a sub-query belongs to.
+	LokiQueryIDHeader = "X-Loki-Query-ID"
 
 	// LokiActorPathDelimiter is the delimiter used to serialise the hierarchy of the actor.
 	LokiActorPathDelimiter = "|"
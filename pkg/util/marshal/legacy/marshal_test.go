@@ -58,7 +58,9 @@ var queryTests = []struct {
 			"stats" : {
 				"index": {
 					"postFilterChunks": 0,
-					"totalChunks": 0
+					"totalChunks": 0,
+					"totalFilters": 0,
+					"bloomFilters": 0
 				},
 				"ingester" : {
 					"store": {
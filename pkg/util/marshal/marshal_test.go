@@ -27,7 +27,9 @@ import (
 const emptyStats = `{
 	"index": {
 		"postFilterChunks": 0,
-		"totalChunks": 0
+		"totalChunks": 0,
+		"totalFilters": 0,
+		"bloomFilters": 0
 	},
 	"ingester" : {
 		"store": {
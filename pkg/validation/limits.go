@@ -21,6 +21,8 @@ import (
 
 	"github.com/grafana/loki/v3/pkg/chunkenc"
 	"github.com/grafana/loki/v3/pkg/compactor/deletionmode"
+	"github.com/grafana/loki/v3/pkg/distributor/labelpolicy"
+	"github.com/grafana/loki/v3/pkg/distributor/severity"
 	"github.com/grafana/loki/v3/pkg/distributor/shardstreams"
 	"github.com/grafana/loki/v3/pkg/loghttp/push"
 	"github.com/grafana/loki/v3/pkg/logql"
@@ -68,20 +70,24 @@ const (
 // to support user-friendly duration format (e.g: "1h30m45s") in JSON value.
 type Limits struct {
 	// Distributor enforced limits.
-	IngestionRateStrategy       string           `yaml:"ingestion_rate_strategy" json:"ingestion_rate_strategy"`
-	IngestionRateMB             float64          `yaml:"ingestion_rate_mb" json:"ingestion_rate_mb"`
-	IngestionBurstSizeMB        float64          `yaml:"ingestion_burst_size_mb" json:"ingestion_burst_size_mb"`
-	MaxLabelNameLength          int              `yaml:"max_label_name_length" json:"max_label_name_length"`
-	MaxLabelValueLength         int              `yaml:"max_label_value_length" json:"max_label_value_length"`
-	MaxLabelNamesPerSeries      int              `yaml:"max_label_names_per_series" json:"max_label_names_per_series"`
-	RejectOldSamples            bool             `yaml:"reject_old_samples" json:"reject_old_samples"`
-	RejectOldSamplesMaxAge      model.Duration   `yaml:"reject_old_samples_max_age" json:"reject_old_samples_max_age"`
-	CreationGracePeriod         model.Duration   `yaml:"creation_grace_period" json:"creation_grace_period"`
-	MaxLineSize                 flagext.ByteSize `yaml:"max_line_size" json:"max_line_size"`
-	MaxLineSizeTruncate         bool             `yaml:"max_line_size_truncate" json:"max_line_size_truncate"`
-	IncrementDuplicateTimestamp bool             `yaml:"increment_duplicate_timestamp" json:"increment_duplicate_timestamp"`
-	DiscoverServiceName         []string         `yaml:"discover_service_name" json:"discover_service_name"`
-	DiscoverLogLevels           bool             `yaml:"discover_log_levels" json:"discover_log_levels"`
+	IngestionRateStrategy       string              `yaml:"ingestion_rate_strategy" json:"ingestion_rate_strategy"`
+	IngestionRateMB             float64             `yaml:"ingestion_rate_mb" json:"ingestion_rate_mb"`
+	IngestionBurstSizeMB        float64             `yaml:"ingestion_burst_size_mb" json:"ingestion_burst_size_mb"`
+	MaxLabelNameLength          int                 `yaml:"max_label_name_length" json:"max_label_name_length"`
+	MaxLabelValueLength         int                 `yaml:"max_label_value_length" json:"max_label_value_length"`
+	MaxLabelNamesPerSeries      int                 `yaml:"max_label_names_per_series" json:"max_label_names_per_series"`
+	RejectOldSamples            bool                `yaml:"reject_old_samples" json:"reject_old_samples"`
+	RejectOldSamplesMaxAge      model.Duration      `yaml:"reject_old_samples_max_age" json:"reject_old_samples_max_age"`
+	CreationGracePeriod         model.Duration      `yaml:"creation_grace_period" json:"creation_grace_period"`
+	MaxLineSize                 flagext.ByteSize    `yaml:"max_line_size" json:"max_line_size"`
+	MaxLineSizeTruncate         bool                `yaml:"max_line_size_truncate" json:"max_line_size_truncate"`
+	IncrementDuplicateTimestamp bool                `yaml:"increment_duplicate_timestamp" json:"increment_duplicate_timestamp"`
+	DiscoverServiceName         []string            `yaml:"discover_service_name" json:"discover_service_name"`
+	DiscoverLogLevels           bool                `yaml:"discover_log_levels" json:"discover_log_levels"`
+	SeverityNormalization       *severity.Config    `yaml:"severity_normalization" json:"severity_normalization"`
+	RejectionsStreamEnabled     bool                `yaml:"rejections_stream_enabled" json:"rejections_stream_enabled"`
+	RejectionsStreamTenant      string              `yaml:"rejections_stream_tenant" json:"rejections_stream_tenant"`
+	LabelPolicy                 []*labelpolicy.Rule `yaml:"label_policy,omitempty" json:"label_policy,omitempty" category:"experimental" doc:"description=Experimental: Relabeling rules applied by the distributors to the labels of the streams pushed by the tenant, before the labels are validated. The rules use the fields of the Prometheus relabel configs, and must have a name. Streams dropped by a rule are rejected.\nExample:\n label_policy:\n - name: request-id-to-metadata\n action: structured_metadata\n regex: request_id\n - name: no-debug-streams\n action: drop\n source_labels: [level]\n regex: debug"`
 
 	// Ingester enforced limits.
 	MaxLocalStreamsPerUser  int              `yaml:"max_streams_per_user" json:"max_streams_per_user"`
@@ -89,6 +95,7 @@ type Limits struct {
 	UnorderedWrites         bool             `yaml:"unordered_writes" json:"unordered_writes"`
 	PerStreamRateLimit      flagext.ByteSize `yaml:"per_stream_rate_limit" json:"per_stream_rate_limit"`
 	PerStreamRateLimitBurst flagext.ByteSize `yaml:"per_stream_rate_limit_burst" json:"per_stream_rate_limit_burst"`
+	StreamBudgets           []StreamBudget   `yaml:"stream_budgets,omitempty" json:"stream_budgets,omitempty" category:"experimental" doc:"description=Experimental: Limits of the active streams of the tenant matching a selector, or of the values of a label of its active streams. The max_streams limits are global, and are converted to limits of each ingester like max_global_streams_per_user. The max_values_per_ingester limits aren't divided, because the streams with a value can be spread across all the ingesters, so each ingester accepts up to that many values. Streams exceeding a budget are rejected.\nExample:\n stream_budgets:\n - selector: '{namespace=\"foo\"}'\n max_streams: 5000\n - label: pod_ip\n max_values_per_ingester: 100"`
 
 	// Querier enforced limits.
 	MaxChunksPerQuery          int              `yaml:"max_chunks_per_query" json:"max_chunks_per_query"`
@@ -112,6 +119,10 @@ type Limits struct {
 	QueryReadyIndexNumDays     int              `yaml:"query_ready_index_num_days" json:"query_ready_index_num_days"`
 	QueryTimeout               model.Duration   `yaml:"query_timeout" json:"query_timeout"`
 
+	// Query scheduler enforced limits.
+	QuerySchedulerTenantWeight           float64 `yaml:"query_scheduler_tenant_weight" json:"query_scheduler_tenant_weight"`
+	QuerySchedulerMaxLowPriorityCapacity float64 `yaml:"query_scheduler_max_low_priority_capacity" json:"query_scheduler_max_low_priority_capacity"`
+
 	// Query frontend enforced limits. The default is actually parameterized by the queryrange config.
 	QuerySplitDuration               model.Duration   `yaml:"split_queries_by_interval" json:"split_queries_by_interval"`
 	MetadataQuerySplitDuration       model.Duration   `yaml:"split_metadata_queries_by_interval" json:"split_metadata_queries_by_interval"`
@@ -221,6 +232,24 @@ type StreamRetention struct {
 	Matchers []*labels.Matcher `yaml:"-" json:"-"` // populated during validation.
 }
 
+// StreamBudget limits the number of active streams matching a selector, or the number of values of a label of the
+// active streams of each ingester.
+type StreamBudget struct {
+	Selector             string            `yaml:"selector,omitempty" json:"selector,omitempty" doc:"description=Stream selector of the streams limited by max_streams."`
+	MaxStreams           int               `yaml:"max_streams,omitempty" json:"max_streams,omitempty" doc:"description=Maximum number of active streams matching the selector."`
+	Label                string            `yaml:"label,omitempty" json:"label,omitempty" doc:"description=Label whose values are limited by max_values_per_ingester."`
+	MaxValuesPerIngester int               `yaml:"max_values_per_ingester,omitempty" json:"max_values_per_ingester,omitempty" doc:"description=Maximum number of values of the label across the active streams of each ingester."`
+	Matchers             []*labels.Matcher `yaml:"-" json:"-"` // populated during validation.
+}
+
+// Name returns the selector or the label of the budget.
+func (b StreamBudget) Name() string {
+	if b.Selector != "" {
+		return b.Selector
+	}
+	return b.Label
+}
+
 // LimitError are errors that do not comply with the limits specified.
 type LimitError string
 
@@ -256,6 +285,10 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	}
 	f.Var((*dskit_flagext.StringSlice)(&l.DiscoverServiceName), "validation.discover-service-name", "If no service_name label exists, Loki maps a single label from the configured list to service_name. If none of the configured labels exist in the stream, label is set to unknown_service. Empty list disables setting the label.")
 	f.BoolVar(&l.DiscoverLogLevels, "validation.discover-log-levels", true, "Discover and add log levels during ingestion, if not present already. Levels would be added to Structured Metadata with name 'level' and one of the values from 'debug', 'info', 'warn', 'error', 'critical', 'fatal'.")
+	l.SeverityNormalization = &severity.Config{}
+	l.SeverityNormalization.RegisterFlagsWithPrefix("validation.severity-normalization", f)
+	f.BoolVar(&l.RejectionsStreamEnabled, "validation.rejections-stream-enabled", false, "Write a sample of the entries rejected by the distributors, and the reasons of the rejections, to a stream with the label __loki_rejections__=\"true\", so that the tenant can query its ingestion errors. The volume of the stream is limited by -distributor.rejections-stream.rate.")
+	f.StringVar(&l.RejectionsStreamTenant, "validation.rejections-stream-tenant", "", "Tenant the rejections stream is written to. The stream written to another tenant has a tenant label with the ID of the rejecting tenant. Empty to write it to the rejecting tenant itself.")
 
 	_ = l.RejectOldSamplesMaxAge.Set("7d")
 	f.Var(&l.RejectOldSamplesMaxAge, "validation.reject-old-samples.max-age", "Maximum accepted sample age before rejecting.")
@@ -322,6 +355,8 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 
 	f.UintVar(&l.MaxQueriersPerTenant, "frontend.max-queriers-per-tenant", 0, "Maximum number of queriers that can handle requests for a single tenant. If set to 0 or value higher than number of available queriers, *all* queriers will handle requests for the tenant. Each frontend (or query-scheduler, if used) will select the same set of queriers for the same tenant (given that all queriers are connected to all frontends / query-schedulers). This option only works with queriers connecting to the query-frontend / query-scheduler, not when using downstream URL.")
 	f.Float64Var(&l.MaxQueryCapacity, "frontend.max-query-capacity", 0, "How much of the available query capacity (\"querier\" components in distributed mode, \"read\" components in SSD mode) can be used by a single tenant. Allowed values are 0.0 to 1.0. For example, setting this to 0.5 would allow a tenant to use half of the available queriers for processing the query workload. If set to 0, query capacity is determined by frontend.max-queriers-per-tenant. When both frontend.max-queriers-per-tenant and frontend.max-query-capacity are configured, smaller value of the resulting querier replica count is considered: min(frontend.max-queriers-per-tenant, ceil(querier_replicas * frontend.max-query-capacity)). *All* queriers will handle requests for the tenant if neither limits are applied. This option only works with queriers connecting to the query-frontend / query-scheduler, not when using downstream URL. Use this feature in a multi-tenant setup where you need to limit query capacity for certain tenants.")
+	f.Float64Var(&l.QuerySchedulerTenantWeight, "query-scheduler.tenant-weight", 1, "Weight of the tenant's share of the query cost when the query-scheduler dequeues requests weighted fairly by cost (see -query-scheduler.cost-window). A tenant with weight 2 gets twice the query cost of a tenant with weight 1 when both have requests queued. Must be greater than 0.")
+	f.Float64Var(&l.QuerySchedulerMaxLowPriorityCapacity, "query-scheduler.max-low-priority-capacity", 0, "How much of the query capacity of a tenant (the connections of the queriers handling its requests) can be used by low priority requests at the same time. Allowed values are 0.0 to 1.0. 0 means that low priority requests can use all of the capacity.")
 	f.IntVar(&l.QueryReadyIndexNumDays, "store.query-ready-index-num-days", 0, "Number of days of index to be kept always downloaded for queries. Applies only to per user index in boltdb-shipper index store. 0 to disable.")
 
 	f.IntVar(&l.RulerMaxRulesPerRuleGroup, "ruler.max-rules-per-rule-group", 0, "Maximum number of rules per rule group per-tenant. 0 to disable.")
@@ -437,6 +472,33 @@ func (l *Limits) Validate() error {
 		}
 	}
 
+	for i, budget := range l.StreamBudgets {
+		switch {
+		case budget.Selector != "" && budget.Label == "":
+			if budget.MaxStreams <= 0 || budget.MaxValuesPerIngester != 0 {
+				return fmt.Errorf("stream budget %s: max_streams must be set to a positive value for a selector", budget.Name())
+			}
+			matchers, err := syntax.ParseMatchers(budget.Selector, true)
+			if err != nil {
+				return fmt.Errorf("stream budget %s: invalid labels matchers: %w", budget.Name(), err)
+			}
+			// populate matchers during validation
+			l.StreamBudgets[i].Matchers = matchers
+		case budget.Label != "" && budget.Selector == "":
+			if budget.MaxValuesPerIngester <= 0 || budget.MaxStreams != 0 {
+				return fmt.Errorf("stream budget %s: max_values_per_ingester must be set to a positive value for a label", budget.Name())
+			}
+		default:
+			return errors.New("stream budget: exactly one of selector and label must be set")
+		}
+	}
+
+	if l.SeverityNormalization != nil {
+		if err := l.SeverityNormalization.Validate(); err != nil {
+			return err
+		}
+	}
+
 	if _, err := deletionmode.ParseMode(l.DeletionMode); err != nil {
 		return err
 	}
@@ -455,6 +517,21 @@ func (l *Limits) Validate() error {
 		l.MaxQueryCapacity = 1
 	}
 
+	if l.QuerySchedulerTenantWeight <= 0 {
+		level.Warn(util_log.Logger).Log("msg", "setting query-scheduler.tenant-weight to 1 as it is configured to a value less than or equal to 0")
+		l.QuerySchedulerTenantWeight = 1
+	}
+
+	if l.QuerySchedulerMaxLowPriorityCapacity < 0 {
+		level.Warn(util_log.Logger).Log("msg", "setting query-scheduler.max-low-priority-capacity to 0 as it is configured to a value less than 0")
+		l.QuerySchedulerMaxLowPriorityCapacity = 0
+	}
+
+	if l.QuerySchedulerMaxLowPriorityCapacity > 1 {
+		level.Warn(util_log.Logger).Log("msg", "setting query-scheduler.max-low-priority-capacity to 1 as it is configured to a value greater than 1")
+		l.QuerySchedulerMaxLowPriorityCapacity = 1
+	}
+
 	if err := l.OTLPConfig.Validate(); err != nil {
 		return err
 	}
@@ -576,6 +653,11 @@ func (o *Overrides) MaxGlobalStreamsPerUser(userID string) int {
 	return o.getOverridesForUser(userID).MaxGlobalStreamsPerUser
 }
 
+// StreamBudgets returns the limits of the streams of a user matching a selector or of the values of a label.
+func (o *Overrides) StreamBudgets(userID string) []StreamBudget {
+	return o.getOverridesForUser(userID).StreamBudgets
+}
+
 // MaxChunksPerQuery returns the maximum number of chunks allowed per query.
 func (o *Overrides) MaxChunksPerQuery(userID string) int {
 	return o.getOverridesForUser(userID).MaxChunksPerQuery
@@ -610,6 +692,16 @@ func (o *Overrides) MaxQueryCapacity(userID string) float64 {
 	return o.getOverridesForUser(userID).MaxQueryCapacity
 }
 
+// QuerySchedulerTenantWeight returns the weight of the user's share of the query cost in the query-scheduler.
+func (o *Overrides) QuerySchedulerTenantWeight(userID string) float64 {
+	return o.getOverridesForUser(userID).QuerySchedulerTenantWeight
+}
+
+// QuerySchedulerMaxLowPriorityCapacity returns how much of the query capacity of the user can be used by low priority requests.
+func (o *Overrides) QuerySchedulerMaxLowPriorityCapacity(userID string) float64 {
+	return o.getOverridesForUser(userID).QuerySchedulerMaxLowPriorityCapacity
+}
+
 // QueryReadyIndexNumDays returns the number of days for which we have to be query ready for a user.
 func (o *Overrides) QueryReadyIndexNumDays(userID string) int {
 	return o.getOverridesForUser(userID).QueryReadyIndexNumDays
@@ -920,6 +1012,22 @@ func (o *Overrides) DiscoverLogLevels(userID string) bool {
 	return o.getOverridesForUser(userID).DiscoverLogLevels
 }
 
+func (o *Overrides) SeverityNormalization(userID string) *severity.Config {
+	return o.getOverridesForUser(userID).SeverityNormalization
+}
+
+func (o *Overrides) RejectionsStreamEnabled(userID string) bool {
+	return o.getOverridesForUser(userID).RejectionsStreamEnabled
+}
+
+func (o *Overrides) RejectionsStreamTenant(userID string) string {
+	return o.getOverridesForUser(userID).RejectionsStreamTenant
+}
+
+func (o *Overrides) LabelPolicy(userID string) []*labelpolicy.Rule {
+	return o.getOverridesForUser(userID).LabelPolicy
+}
+
 // VolumeEnabled returns whether volume endpoints are enabled for a user.
 func (o *Overrides) VolumeEnabled(userID string) bool {
 	return o.getOverridesForUser(userID).VolumeEnabled
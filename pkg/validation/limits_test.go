@@ -2,6 +2,7 @@ package validation
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
@@ -341,6 +342,25 @@ func TestLimitsValidation(t *testing.T) {
 			limits:   Limits{DeletionMode: "disabled", BloomBlockEncoding: "unknown"},
 			expected: fmt.Errorf("invalid encoding: unknown, supported: %s", chunkenc.SupportedEncoding()),
 		},
+		{
+			limits: Limits{DeletionMode: "disabled", BloomBlockEncoding: "none", StreamBudgets: []StreamBudget{
+				{Selector: `{namespace="foo"}`, MaxStreams: 5000},
+				{Label: "pod_ip", MaxValuesPerIngester: 100},
+			}},
+			expected: nil,
+		},
+		{
+			limits: Limits{DeletionMode: "disabled", BloomBlockEncoding: "none", StreamBudgets: []StreamBudget{
+				{Selector: `{namespace="foo"}`, Label: "pod_ip", MaxValuesPerIngester: 100},
+			}},
+			expected: errors.New("stream budget: exactly one of selector and label must be set"),
+		},
+		{
+			limits: Limits{DeletionMode: "disabled", BloomBlockEncoding: "none", StreamBudgets: []StreamBudget{
+				{Label: "pod_ip"},
+			}},
+			expected: errors.New("stream budget pod_ip: max_values_per_ingester must be set to a positive value for a label"),
+		},
 	} {
 		desc := fmt.Sprintf("%s/%s", tc.limits.DeletionMode, tc.limits.BloomBlockEncoding)
 		t.Run(desc, func(t *testing.T) {
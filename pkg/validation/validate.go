@@ -29,6 +29,10 @@ const (
 	// because the limit of active streams has been reached.
 	StreamLimit         = "stream_limit"
 	StreamLimitErrorMsg = "Maximum active stream limit exceeded when trying to create stream %s, reduce the number of active streams (reduce labels or reduce label values), or contact your Loki administrator to see if the limit can be increased, user: '%s'"
+	// StreamBudgetLimit is a reason for discarding lines when we can't create a new stream
+	// because the limit of active streams matching a selector or of values of a label has been reached.
+	StreamBudgetLimit         = "stream_budget_limit"
+	StreamBudgetLimitErrorMsg = "Stream budget %s exceeded when trying to create stream %s (limit: %d), reduce the number of active streams or label values, or contact your Loki administrator to see if the limit can be increased, user: '%s'"
 	// StreamRateLimit is a reason for discarding lines when the streams own rate limit is hit
 	// rather than the overall ingestion rate limit.
 	StreamRateLimit = "per_stream_rate_limit"
@@ -67,6 +71,9 @@ const (
 	StructuredMetadataTooLargeErrorMsg   = "stream '%s' has structured metadata too large: '%d' bytes, limit: '%d' bytes. Please see `limits_config.structured_metadata_max_size` or contact your Loki administrator to increase it."
 	StructuredMetadataTooMany            = "structured_metadata_too_many"
 	StructuredMetadataTooManyErrorMsg    = "stream '%s' has too many structured metadata labels: '%d', limit: '%d'. Please see `limits_config.max_structured_metadata_entries_count` or contact your Loki administrator to increase it."
+	// LabelPolicyRejected is a reason for discarding a log line which has labels rejected by the label policy of the tenant
+	LabelPolicyRejected         = "label_policy_rejected"
+	LabelPolicyRejectedErrorMsg = "stream '%s' was rejected by the label policy rule '%s'"
 )
 
 type ErrStreamRateLimit struct {
@@ -122,6 +129,26 @@ var DiscardedSamples = promauto.NewCounterVec(
 	[]string{ReasonLabel, "tenant"},
 )
 
+// DiscardedStreamBudgetSamples is a metric of the number of samples discarded because of a stream budget, by budget.
+var DiscardedStreamBudgetSamples = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: constants.Loki,
+		Name:      "discarded_stream_budget_samples_total",
+		Help:      "The total number of samples that were discarded because of a stream budget.",
+	},
+	[]string{"budget", "tenant"},
+)
+
+// DiscardedStreamBudgetBytes is a metric of the total bytes discarded because of a stream budget, by budget.
+var DiscardedStreamBudgetBytes = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: constants.Loki,
+		Name:      "discarded_stream_budget_bytes_total",
+		Help:      "The total number of bytes that were discarded because of a stream budget.",
+	},
+	[]string{"budget", "tenant"},
+)
+
 var LineLengthHist = promauto.NewHistogram(prometheus.HistogramOpts{
 	Namespace: constants.Loki,
 	Name:      "bytes_per_line",
@@ -17,6 +17,7 @@ import (
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/flagext"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -36,6 +37,11 @@ type ProxyConfig struct {
 	SkipRecentSamples              time.Duration
 	RequestURLFilter               *regexp.Regexp
 	InstrumentCompares             bool
+	OrderInsensitiveStreams        bool
+	SkipRecentLogEntries           time.Duration
+	IncludeStreamLabels            flagext.StringSliceCSV
+	ExcludeStreamLabels            flagext.StringSliceCSV
+	MaxStreamDiffSamples           int
 }
 
 func (cfg *ProxyConfig) RegisterFlags(f *flag.FlagSet) {
@@ -55,6 +61,11 @@ func (cfg *ProxyConfig) RegisterFlags(f *flag.FlagSet) {
 		return err
 	})
 	f.BoolVar(&cfg.InstrumentCompares, "proxy.compare-instrument", false, "Reports metrics on comparisons of responses between preferred and non-preferred endpoints for supported routes.")
+	f.BoolVar(&cfg.OrderInsensitiveStreams, "proxy.compare-streams-order-insensitive", false, "Ignore the order of log entries sharing the same timestamp when comparing log streams.")
+	f.DurationVar(&cfg.SkipRecentLogEntries, "proxy.compare-streams-skip-recent-entries", 0, "The window from now to skip comparing log entries, eg. to ignore entries still being ingested. 0 to disable.")
+	f.Var(&cfg.IncludeStreamLabels, "proxy.compare-streams-include-labels", "Comma separated list of stream labels to compare log streams on. Streams only differing by other labels are merged. All labels are compared if empty.")
+	f.Var(&cfg.ExcludeStreamLabels, "proxy.compare-streams-exclude-labels", "Comma separated list of stream labels to ignore when comparing log streams, eg. structured metadata which isn't categorized by all backends. Streams only differing by these labels are merged.")
+	f.IntVar(&cfg.MaxStreamDiffSamples, "proxy.compare-streams-diff-samples", 10, "Maximum number of missing or extra log entries logged when log streams differ.")
 }
 
 type Route struct {
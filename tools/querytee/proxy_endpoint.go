@@ -20,6 +20,9 @@ type ResponsesComparator interface {
 
 type ComparisonSummary struct {
 	missingMetrics int
+
+	// streams is set when log streams were compared.
+	streams *streamsDiff
 }
 
 type ProxyEndpoint struct {
@@ -187,14 +190,63 @@ func (p *ProxyEndpoint) executeBackendRequests(r *http.Request, resCh chan *back
 				result = comparisonFailed
 			}
 
+			if summary != nil && summary.streams != nil && !summary.streams.empty() {
+				p.logStreamsDiff(p.backends[i].name, r.URL.RawQuery, summary.streams)
+			}
+
 			if p.instrumentCompares && summary != nil {
-				p.metrics.missingMetrics.WithLabelValues(p.backends[i].name, p.routeName, result, issuer).Observe(float64(summary.missingMetrics))
+				// the missing metrics are only counted by successful comparisons of vector responses.
+				if err == nil && summary.streams == nil {
+					p.metrics.missingMetrics.WithLabelValues(p.backends[i].name, p.routeName, result, issuer).Observe(float64(summary.missingMetrics))
+				}
+				if summary.streams != nil {
+					p.observeStreamsDiff(p.backends[i].name, issuer, summary.streams)
+				}
 			}
 			p.metrics.responsesComparedTotal.WithLabelValues(p.backends[i].name, p.routeName, result, issuer).Inc()
 		}
 	}
 }
 
+// logStreamsDiff logs the number of differences between log streams along with a sample of the differing entries.
+func (p *ProxyEndpoint) logStreamsDiff(backend, query string, diff *streamsDiff) {
+	level.Warn(p.logger).Log("msg", "log streams differ",
+		"backend-name", backend,
+		"route-name", p.routeName,
+		"query", query,
+		"missing-streams", diff.missingStreams,
+		"extra-streams", diff.extraStreams,
+		"missing-entries", diff.missingEntries,
+		"extra-entries", diff.extraEntries,
+		"unordered-streams", diff.unorderedStreams)
+
+	for _, sample := range diff.samples {
+		kind := "extra"
+		if sample.missing {
+			kind = "missing"
+		}
+		level.Warn(p.logger).Log("msg", "log entry differs",
+			"backend-name", backend,
+			"route-name", p.routeName,
+			"diff", kind,
+			"stream", sample.stream,
+			"ts", sample.entry.Timestamp.UnixNano(),
+			"line", sample.entry.Line)
+	}
+}
+
+func (p *ProxyEndpoint) observeStreamsDiff(backend, issuer string, diff *streamsDiff) {
+	for kind, n := range map[string]int{
+		streamsDiffMissingStreams:   diff.missingStreams,
+		streamsDiffExtraStreams:     diff.extraStreams,
+		streamsDiffMissingEntries:   diff.missingEntries,
+		streamsDiffExtraEntries:     diff.extraEntries,
+		streamsDiffUnorderedStreams: diff.unorderedStreams,
+	} {
+		p.metrics.streamsDiff.WithLabelValues(backend, p.routeName, kind, issuer).Observe(float64(n))
+	}
+}
+
 func (p *ProxyEndpoint) waitBackendResponseForDownstream(resCh chan *backendResponse) *backendResponse {
 	var (
 		responses                 = make([]*backendResponse, 0, len(p.backends))
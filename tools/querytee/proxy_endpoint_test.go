@@ -341,6 +341,34 @@ func Test_ProxyEndpoint_SummaryMetrics(t *testing.T) {
 	}
 }
 
+func TestProxyEndpoint_MissingMetricsNotObservedOnFailedComparison(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer backend.Close()
+	backendURL, err := url.Parse(backend.URL)
+	require.NoError(t, err)
+
+	backends := []*ProxyBackend{
+		NewProxyBackend("backend-1", backendURL, time.Second, true),
+		NewProxyBackend("backend-2", backendURL, time.Second, false),
+	}
+
+	proxyMetrics := NewProxyMetrics(prometheus.NewRegistry())
+	endpoint := NewProxyEndpoint(backends, "test", proxyMetrics, log.NewNopLogger(), &mockComparator{err: errors.New("responses differ")}, true)
+
+	r, err := http.NewRequest("GET", "http://test/api/v1/test", nil)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	endpoint.ServeHTTP(w, r)
+	require.Equal(t, 200, w.Code)
+
+	require.Eventually(t, func() bool {
+		return prom_testutil.ToFloat64(proxyMetrics.responsesComparedTotal.WithLabelValues("backend-2", "test", comparisonFailed, "unknown")) == 1
+	}, 2*time.Second, 100*time.Millisecond)
+	require.Equal(t, 0, prom_testutil.CollectAndCount(proxyMetrics.missingMetrics))
+}
+
 func Test_backendResponse_succeeded(t *testing.T) {
 	tests := map[string]struct {
 		resStatus int
@@ -421,8 +449,10 @@ func Test_backendResponse_statusCode(t *testing.T) {
 	}
 }
 
-type mockComparator struct{}
+type mockComparator struct {
+	err error
+}
 
 func (c *mockComparator) Compare(_, _ []byte) (*ComparisonSummary, error) {
-	return &ComparisonSummary{missingMetrics: 12}, nil
+	return &ComparisonSummary{missingMetrics: 12}, c.err
 }
@@ -11,6 +11,12 @@ const (
 
 	unknownIssuer = "unknown"
 	canaryIssuer  = "loki-canary"
+
+	streamsDiffMissingStreams   = "missing_streams"
+	streamsDiffExtraStreams     = "extra_streams"
+	streamsDiffMissingEntries   = "missing_entries"
+	streamsDiffExtraEntries     = "extra_entries"
+	streamsDiffUnorderedStreams = "unordered_streams"
 )
 
 type ProxyMetrics struct {
@@ -18,6 +24,7 @@ type ProxyMetrics struct {
 	responsesTotal         *prometheus.CounterVec
 	responsesComparedTotal *prometheus.CounterVec
 	missingMetrics         *prometheus.HistogramVec
+	streamsDiff            *prometheus.HistogramVec
 }
 
 func NewProxyMetrics(registerer prometheus.Registerer) *ProxyMetrics {
@@ -44,6 +51,12 @@ func NewProxyMetrics(registerer prometheus.Registerer) *ProxyMetrics {
 			Help:      "Number of missing metrics (series) in a vector response.",
 			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 0.75, 1, 1.5, 2, 3, 4, 5, 10, 25, 50, 100},
 		}, []string{"backend", "route", "status_code", "issuer"}),
+		streamsDiff: promauto.With(registerer).NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "cortex_querytee",
+			Name:      "log_streams_differences",
+			Help:      "Number of differences per kind (missing or extra streams and entries, unordered streams) in a log streams response.",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 8),
+		}, []string{"backend", "route", "diff", "issuer"}),
 	}
 
 	return m
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -31,6 +33,13 @@ type SampleComparisonOptions struct {
 	Tolerance         float64
 	UseRelativeError  bool
 	SkipRecentSamples time.Duration
+
+	// Options only applying to log streams.
+	OrderInsensitiveStreams bool
+	SkipRecentLogEntries    time.Duration
+	IncludeStreamLabels     []string
+	ExcludeStreamLabels     []string
+	MaxStreamDiffSamples    int
 }
 
 func NewSamplesComparator(opts SampleComparisonOptions) *SamplesComparator {
@@ -250,7 +259,7 @@ func compareSampleValue(first, second model.SampleValue, opts SampleComparisonOp
 	return math.Abs(f-s) <= opts.Tolerance
 }
 
-func compareStreams(expectedRaw, actualRaw json.RawMessage, _ SampleComparisonOptions) (*ComparisonSummary, error) {
+func compareStreams(expectedRaw, actualRaw json.RawMessage, opts SampleComparisonOptions) (*ComparisonSummary, error) {
 	var expected, actual loghttp.Streams
 
 	err := jsoniter.Unmarshal(expectedRaw, &expected)
@@ -262,48 +271,259 @@ func compareStreams(expectedRaw, actualRaw json.RawMessage, _ SampleComparisonOp
 		return nil, errors.Wrap(err, "unable to unmarshal actual streams")
 	}
 
+	now := time.Now()
+	backward := isBackward(expected, actual)
+	expectedKeys, expectedStreams := normalizeStreams(expected, opts, now, backward)
+	actualKeys, actualStreams := normalizeStreams(actual, opts, now, backward)
+
+	// The comparison goes on after the first difference so that the summary reports all of them,
+	// the returned error is the first difference found.
+	diff := &streamsDiff{maxSamples: opts.MaxStreamDiffSamples}
+	err = nil
+	if len(expectedKeys) != len(actualKeys) {
+		err = fmt.Errorf("expected %d streams but got %d", len(expectedKeys), len(actualKeys))
+	}
+
+	for _, key := range expectedKeys {
+		expectedEntries := expectedStreams[key]
+		actualEntries, ok := actualStreams[key]
+		if !ok {
+			diff.missingStreams++
+			for _, e := range expectedEntries {
+				diff.addMissing(key, e)
+			}
+			if err == nil {
+				err = fmt.Errorf("expected stream %s missing from actual response", key)
+			}
+			continue
+		}
+
+		if entriesErr := compareEntries(key, expectedEntries, actualEntries, diff); err == nil {
+			err = entriesErr
+		}
+	}
+
+	for _, key := range actualKeys {
+		if _, ok := expectedStreams[key]; ok {
+			continue
+		}
+		diff.extraStreams++
+		for _, e := range actualStreams[key] {
+			diff.addExtra(key, e)
+		}
+	}
+
+	return &ComparisonSummary{streams: diff}, err
+}
+
+// compareEntries compares the entries of a stream position by position, the entries missing from
+// or extra in the actual response are counted regardless of their position.
+func compareEntries(stream string, expected, actual []loghttp.Entry, diff *streamsDiff) error {
+	var err error
 	if len(expected) != len(actual) {
-		return nil, fmt.Errorf("expected %d streams but got %d", len(expected), len(actual))
+		err = fmt.Errorf("expected %d values for stream %s but got %d", len(expected), stream, len(actual))
+		if len(expected) > 0 && len(actual) > 0 {
+			level.Error(util_log.Logger).Log("msg", err.Error(), "oldest-expected-ts", expected[0].Timestamp.UnixNano(),
+				"newest-expected-ts", expected[len(expected)-1].Timestamp.UnixNano(),
+				"oldest-actual-ts", actual[0].Timestamp.UnixNano(), "newest-actual-ts", actual[len(actual)-1].Timestamp.UnixNano())
+		}
+	} else {
+		for i, expectedEntry := range expected {
+			actualEntry := actual[i]
+			if !expectedEntry.Timestamp.Equal(actualEntry.Timestamp) {
+				err = fmt.Errorf("expected timestamp %v but got %v for stream %s", expectedEntry.Timestamp.UnixNano(),
+					actualEntry.Timestamp.UnixNano(), stream)
+				break
+			}
+			if expectedEntry.Line != actualEntry.Line {
+				err = fmt.Errorf("expected line %s for timestamp %v but got %s for stream %s", expectedEntry.Line,
+					expectedEntry.Timestamp.UnixNano(), actualEntry.Line, stream)
+				break
+			}
+		}
+	}
+	if err == nil {
+		return nil
 	}
 
-	streamLabelsToIndexMap := make(map[string]int, len(expected))
-	for i, actualStream := range actual {
-		streamLabelsToIndexMap[actualStream.Labels.String()] = i
+	type entryKey struct {
+		ts   int64
+		line string
+	}
+	remaining := make(map[entryKey]int, len(actual))
+	for _, e := range actual {
+		remaining[entryKey{e.Timestamp.UnixNano(), e.Line}]++
 	}
 
-	for _, expectedStream := range expected {
-		actualStreamIndex, ok := streamLabelsToIndexMap[expectedStream.Labels.String()]
-		if !ok {
-			return nil, fmt.Errorf("expected stream %s missing from actual response", expectedStream.Labels)
+	missing := diff.missingEntries
+	for _, e := range expected {
+		k := entryKey{e.Timestamp.UnixNano(), e.Line}
+		if remaining[k] > 0 {
+			remaining[k]--
+			continue
 		}
+		diff.addMissing(stream, e)
+	}
 
-		actualStream := actual[actualStreamIndex]
-		expectedValuesLen := len(expectedStream.Entries)
-		actualValuesLen := len(actualStream.Entries)
-
-		if expectedValuesLen != actualValuesLen {
-			err := fmt.Errorf("expected %d values for stream %s but got %d", expectedValuesLen,
-				expectedStream.Labels, actualValuesLen)
-			if expectedValuesLen > 0 && actualValuesLen > 0 {
-				level.Error(util_log.Logger).Log("msg", err.Error(), "oldest-expected-ts", expectedStream.Entries[0].Timestamp.UnixNano(),
-					"newest-expected-ts", expectedStream.Entries[expectedValuesLen-1].Timestamp.UnixNano(),
-					"oldest-actual-ts", actualStream.Entries[0].Timestamp.UnixNano(), "newest-actual-ts", actualStream.Entries[actualValuesLen-1].Timestamp.UnixNano())
+	extra := diff.extraEntries
+	for _, e := range actual {
+		k := entryKey{e.Timestamp.UnixNano(), e.Line}
+		if remaining[k] > 0 {
+			remaining[k]--
+			diff.addExtra(stream, e)
+		}
+	}
+
+	// Same entries, different order.
+	if missing == diff.missingEntries && extra == diff.extraEntries {
+		diff.unorderedStreams++
+	}
+	return err
+}
+
+// normalizeStreams applies the comparison options to the streams and returns them by labels,
+// along with the labels in the order of the response. The entries of merged streams are sorted
+// backward or forward, like the entries of the streams which are not merged.
+func normalizeStreams(streams loghttp.Streams, opts SampleComparisonOptions, now time.Time, backward bool) ([]string, map[string][]loghttp.Entry) {
+	keys := make([]string, 0, len(streams))
+	byLabels := make(map[string][]loghttp.Entry, len(streams))
+	merged := map[string]struct{}{}
+
+	for _, stream := range streams {
+		entries := stream.Entries
+		if opts.SkipRecentLogEntries > 0 {
+			entries = make([]loghttp.Entry, 0, len(stream.Entries))
+			for _, e := range stream.Entries {
+				if now.Sub(e.Timestamp) >= opts.SkipRecentLogEntries {
+					entries = append(entries, e)
+				}
 			}
-			return nil, err
+			if len(entries) == 0 && len(stream.Entries) > 0 {
+				continue
+			}
+		}
+
+		key := filterStreamLabels(stream.Labels, opts.IncludeStreamLabels, opts.ExcludeStreamLabels).String()
+		if existing, ok := byLabels[key]; ok {
+			// Streams only differing by filtered out labels are merged.
+			byLabels[key] = append(existing[:len(existing):len(existing)], entries...)
+			merged[key] = struct{}{}
+			continue
 		}
+		keys = append(keys, key)
+		byLabels[key] = entries
+	}
 
-		for i, expectedSamplePair := range expectedStream.Entries {
-			actualSamplePair := actualStream.Entries[i]
-			if !expectedSamplePair.Timestamp.Equal(actualSamplePair.Timestamp) {
-				return nil, fmt.Errorf("expected timestamp %v but got %v for stream %s", expectedSamplePair.Timestamp.UnixNano(),
-					actualSamplePair.Timestamp.UnixNano(), expectedStream.Labels)
+	for key := range merged {
+		entries := byLabels[key]
+		sort.SliceStable(entries, func(i, j int) bool {
+			if backward {
+				return entries[i].Timestamp.After(entries[j].Timestamp)
 			}
-			if expectedSamplePair.Line != actualSamplePair.Line {
-				return nil, fmt.Errorf("expected line %s for timestamp %v but got %s for stream %s", expectedSamplePair.Line,
-					expectedSamplePair.Timestamp.UnixNano(), actualSamplePair.Line, expectedStream.Labels)
+			return entries[i].Timestamp.Before(entries[j].Timestamp)
+		})
+	}
+
+	if opts.OrderInsensitiveStreams {
+		for key, entries := range byLabels {
+			byLabels[key] = sortEqualTimestamps(entries)
+		}
+	}
+
+	return keys, byLabels
+}
+
+// isBackward returns whether the entries of the responses are sorted from the newest to the oldest,
+// which is the default direction of log queries. The responses don't include the direction
+// of the query so it is found from the first stream with different timestamps.
+func isBackward(responses ...loghttp.Streams) bool {
+	for _, streams := range responses {
+		for _, stream := range streams {
+			for i := 1; i < len(stream.Entries); i++ {
+				if prev, ts := stream.Entries[i-1].Timestamp, stream.Entries[i].Timestamp; !prev.Equal(ts) {
+					return prev.After(ts)
+				}
 			}
 		}
 	}
+	return true
+}
 
-	return nil, nil
+// sortEqualTimestamps sorts the entries sharing the same timestamp by line, their order is
+// otherwise undefined and can differ between backends.
+func sortEqualTimestamps(entries []loghttp.Entry) []loghttp.Entry {
+	sorted := make([]loghttp.Entry, len(entries))
+	copy(sorted, entries)
+
+	for start := 0; start < len(sorted); {
+		end := start + 1
+		for end < len(sorted) && sorted[end].Timestamp.Equal(sorted[start].Timestamp) {
+			end++
+		}
+		if end-start > 1 {
+			run := sorted[start:end]
+			sort.SliceStable(run, func(i, j int) bool {
+				return run[i].Line < run[j].Line
+			})
+		}
+		start = end
+	}
+	return sorted
+}
+
+func filterStreamLabels(lbls loghttp.LabelSet, include, exclude []string) loghttp.LabelSet {
+	if len(include) == 0 && len(exclude) == 0 {
+		return lbls
+	}
+
+	filtered := make(loghttp.LabelSet, len(lbls))
+	for name, value := range lbls {
+		if len(include) > 0 && !slices.Contains(include, name) {
+			continue
+		}
+		if slices.Contains(exclude, name) {
+			continue
+		}
+		filtered[name] = value
+	}
+	return filtered
+}
+
+// streamsDiff reports the differences found between the expected and actual log streams.
+type streamsDiff struct {
+	missingStreams   int
+	extraStreams     int
+	missingEntries   int
+	extraEntries     int
+	unorderedStreams int
+
+	// samples are some of the missing and extra entries, up to maxSamples.
+	samples    []entryDiff
+	maxSamples int
+}
+
+type entryDiff struct {
+	stream  string
+	missing bool
+	entry   loghttp.Entry
+}
+
+func (d *streamsDiff) addMissing(stream string, e loghttp.Entry) {
+	d.missingEntries++
+	d.addSample(entryDiff{stream: stream, missing: true, entry: e})
+}
+
+func (d *streamsDiff) addExtra(stream string, e loghttp.Entry) {
+	d.extraEntries++
+	d.addSample(entryDiff{stream: stream, entry: e})
+}
+
+func (d *streamsDiff) addSample(sample entryDiff) {
+	if len(d.samples) < d.maxSamples {
+		d.samples = append(d.samples, sample)
+	}
+}
+
+func (d *streamsDiff) empty() bool {
+	return d.missingStreams == 0 && d.extraStreams == 0 && d.missingEntries == 0 && d.extraEntries == 0 && d.unorderedStreams == 0
 }
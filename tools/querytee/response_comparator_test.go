@@ -3,11 +3,14 @@ package querytee
 import (
 	"encoding/json"
 	"errors"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/loghttp"
 )
 
 func TestCompareMatrix(t *testing.T) {
@@ -511,3 +514,153 @@ func TestCompareStreams(t *testing.T) {
 		})
 	}
 }
+
+func TestCompareStreams_Options(t *testing.T) {
+	recent := time.Now().UnixNano()
+
+	for _, tc := range []struct {
+		name     string
+		opts     SampleComparisonOptions
+		expected json.RawMessage
+		actual   json.RawMessage
+		err      error
+		diff     streamsDiff
+	}{
+		{
+			name: "different order of entries with the same timestamp",
+			expected: json.RawMessage(`[
+							{"stream":{"foo":"bar"},"values":[["1","a"],["2","b"],["2","c"]]}
+						]`),
+			actual: json.RawMessage(`[
+							{"stream":{"foo":"bar"},"values":[["1","a"],["2","c"],["2","b"]]}
+						]`),
+			err:  errors.New("expected line b for timestamp 2 but got c for stream {foo=\"bar\"}"),
+			diff: streamsDiff{unorderedStreams: 1},
+		},
+		{
+			name: "different order of entries with the same timestamp, order insensitive",
+			opts: SampleComparisonOptions{OrderInsensitiveStreams: true},
+			expected: json.RawMessage(`[
+							{"stream":{"foo":"bar"},"values":[["1","a"],["2","b"],["2","c"]]}
+						]`),
+			actual: json.RawMessage(`[
+							{"stream":{"foo":"bar"},"values":[["1","a"],["2","c"],["2","b"]]}
+						]`),
+		},
+		{
+			name: "different order of entries with different timestamps, order insensitive",
+			opts: SampleComparisonOptions{OrderInsensitiveStreams: true},
+			expected: json.RawMessage(`[
+							{"stream":{"foo":"bar"},"values":[["1","a"],["2","b"]]}
+						]`),
+			actual: json.RawMessage(`[
+							{"stream":{"foo":"bar"},"values":[["2","b"],["1","a"]]}
+						]`),
+			err:  errors.New("expected timestamp 1 but got 2 for stream {foo=\"bar\"}"),
+			diff: streamsDiff{unorderedStreams: 1},
+		},
+		{
+			name: "recent entries are skipped",
+			opts: SampleComparisonOptions{SkipRecentLogEntries: time.Hour},
+			expected: json.RawMessage(`[
+							{"stream":{"foo":"bar"},"values":[["1","a"],["` + strconv.FormatInt(recent, 10) + `","b"]]},
+							{"stream":{"foo":"baz"},"values":[["` + strconv.FormatInt(recent, 10) + `","c"]]}
+						]`),
+			actual: json.RawMessage(`[
+							{"stream":{"foo":"bar"},"values":[["1","a"]]}
+						]`),
+		},
+		{
+			name: "excluded labels",
+			opts: SampleComparisonOptions{ExcludeStreamLabels: []string{"trace_id"}},
+			expected: json.RawMessage(`[
+							{"stream":{"foo":"bar","trace_id":"1"},"values":[["1","a"]]},
+							{"stream":{"foo":"bar","trace_id":"2"},"values":[["2","b"]]}
+						]`),
+			actual: json.RawMessage(`[
+							{"stream":{"foo":"bar"},"values":[["1","a"],["2","b"]]}
+						]`),
+		},
+		{
+			name: "excluded labels, backward",
+			opts: SampleComparisonOptions{ExcludeStreamLabels: []string{"trace_id"}},
+			expected: json.RawMessage(`[
+							{"stream":{"foo":"bar","trace_id":"1"},"values":[["3","c"],["1","a"]]},
+							{"stream":{"foo":"bar","trace_id":"2"},"values":[["2","b"]]}
+						]`),
+			actual: json.RawMessage(`[
+							{"stream":{"foo":"bar"},"values":[["3","c"],["2","b"],["1","a"]]}
+						]`),
+		},
+		{
+			name: "merged streams are compared in the same direction",
+			opts: SampleComparisonOptions{ExcludeStreamLabels: []string{"trace_id"}},
+			expected: json.RawMessage(`[
+							{"stream":{"foo":"bar","trace_id":"1"},"values":[["3","c"],["1","a"]]},
+							{"stream":{"foo":"bar","trace_id":"2"},"values":[["2","b"]]}
+						]`),
+			actual: json.RawMessage(`[
+							{"stream":{"foo":"bar","trace_id":"2"},"values":[["2","b"]]},
+							{"stream":{"foo":"bar","trace_id":"1"},"values":[["3","c"],["1","a"]]}
+						]`),
+		},
+		{
+			name: "recent samples aren't skipped from log streams",
+			opts: SampleComparisonOptions{SkipRecentSamples: time.Hour},
+			expected: json.RawMessage(`[
+							{"stream":{"foo":"bar"},"values":[["` + strconv.FormatInt(recent, 10) + `","b"],["1","a"]]}
+						]`),
+			actual: json.RawMessage(`[
+							{"stream":{"foo":"bar"},"values":[["1","a"]]}
+						]`),
+			err:  errors.New("expected 2 values for stream {foo=\"bar\"} but got 1"),
+			diff: streamsDiff{missingEntries: 1},
+		},
+		{
+			name: "included labels",
+			opts: SampleComparisonOptions{IncludeStreamLabels: []string{"foo"}},
+			expected: json.RawMessage(`[
+							{"stream":{"foo":"bar","level":"info"},"values":[["1","a"]]}
+						]`),
+			actual: json.RawMessage(`[
+							{"stream":{"foo":"bar","level":"INFO"},"values":[["1","a"]]}
+						]`),
+		},
+		{
+			name: "missing and extra entries and streams",
+			opts: SampleComparisonOptions{MaxStreamDiffSamples: 2},
+			expected: json.RawMessage(`[
+							{"stream":{"foo":"bar"},"values":[["1","a"],["2","b"],["3","c"]]},
+							{"stream":{"foo":"baz"},"values":[["1","d"]]}
+						]`),
+			actual: json.RawMessage(`[
+							{"stream":{"foo":"bar"},"values":[["1","a"],["3","c"],["4","e"]]},
+							{"stream":{"foo":"qux"},"values":[["1","f"],["2","g"]]}
+						]`),
+			err: errors.New("expected timestamp 2 but got 3 for stream {foo=\"bar\"}"),
+			diff: streamsDiff{
+				missingStreams: 1,
+				extraStreams:   1,
+				missingEntries: 2,
+				extraEntries:   3,
+				samples: []entryDiff{
+					{stream: `{foo="bar"}`, missing: true, entry: loghttp.Entry{Timestamp: time.Unix(0, 2), Line: "b"}},
+					{stream: `{foo="bar"}`, entry: loghttp.Entry{Timestamp: time.Unix(0, 4), Line: "e"}},
+				},
+				maxSamples: 2,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			summary, err := compareStreams(tc.expected, tc.actual, tc.opts)
+			if tc.err == nil {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Equal(t, tc.err.Error(), err.Error())
+			}
+			require.NotNil(t, summary)
+			require.Equal(t, tc.diff, *summary.streams)
+		})
+	}
+}